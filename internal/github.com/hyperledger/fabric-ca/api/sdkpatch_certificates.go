@@ -0,0 +1,40 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package api
+
+// GetCertificatesRequest represents the request to get certificates from the fabric-ca-server
+type GetCertificatesRequest struct {
+	ID         string    `json:"id,omitempty"`         // Get certificates for this enrollment ID
+	AKI        string    `json:"aki,omitempty"`        // Get certificates for this AKI
+	Serial     string    `json:"serial,omitempty"`     // Get certificates for this serial number
+	Revoked    TimeRange `json:"revoked,omitempty"`    // Get certificates that were revoked between the UTC timestamp (RFC3339 format) or duration specified
+	Expired    TimeRange `json:"expired,omitempty"`    // Get certificates that have expired between the UTC timestamp (RFC3339 format) or duration specified
+	NotExpired bool      `json:"notexpired,omitempty"` // Don't return expired certificates
+	NotRevoked bool      `json:"notrevoked,omitempty"` // Don't return revoked certificates
+	CAName     string    `json:"caname,omitempty" skip:"true"`
+}
+
+// TimeRange contains a start and end time
+type TimeRange struct {
+	StartTime string `help:"Start time"`
+	EndTime   string `help:"End time"`
+}
+
+// CertificatesResponse contains the response for a get certificates request
+type CertificatesResponse struct {
+	CAName string            `json:"caname"`
+	Certs  []CertificateInfo `json:"certs"`
+}
+
+// CertificateInfo contains a PEM-encoded certificate
+type CertificateInfo struct {
+	PEM string `json:"PEM"`
+}
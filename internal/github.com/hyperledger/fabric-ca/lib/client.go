@@ -231,6 +231,10 @@ func (c *Client) GenCSR(req *api.CSRInfo, id string) ([]byte, core.Key, error) {
 
 	cr := c.newCertificateRequest(req)
 	cr.CN = id
+	// Use the requested common name, if any
+	if req != nil && req.CN != "" {
+		cr.CN = req.CN
+	}
 
 	if cr.KeyRequest == nil {
 		cr.KeyRequest = newCfsslBasicKeyRequest(api.NewBasicKeyRequest())
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	log "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/logbridge"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	"github.com/pkg/errors"
+)
+
+// GetAffiliation returns information about the requested affiliation
+func (i *Identity) GetAffiliation(affiliation, caname string) (*api.AffiliationResponse, error) {
+	log.Debugf("Entering identity.GetAffiliation %s", affiliation)
+	result := &api.AffiliationResponse{}
+	err := i.Get(fmt.Sprintf("affiliations/%s", url.PathEscape(affiliation)), caname, result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully retrieved affiliation: %+v", result)
+	return result, nil
+}
+
+// GetAllAffiliations returns all affiliations that the caller is authorized to see
+func (i *Identity) GetAllAffiliations(caname string) (*api.AffiliationResponse, error) {
+	log.Debugf("Entering identity.GetAllAffiliations")
+	result := &api.AffiliationResponse{}
+	err := i.Get("affiliations", caname, result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully retrieved affiliations: %+v", result)
+	return result, nil
+}
+
+// AddAffiliation adds a new affiliation to the fabric-ca-server
+func (i *Identity) AddAffiliation(req *api.AddAffiliationRequest) (*api.AffiliationResponse, error) {
+	log.Debugf("Entering identity.AddAffiliation with request: %+v", req)
+	if req.Name == "" {
+		return nil, errors.New("Affiliation to add was not specified")
+	}
+
+	reqBody, err := util.Marshal(req, "AddAffiliationRequest")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.AffiliationResponse{}
+	err = i.Post("affiliations", reqBody, result, affiliationQueryParams(req.Force, req.CAName))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully added affiliation: %+v", result)
+	return result, nil
+}
+
+// ModifyAffiliation renames an existing affiliation on the fabric-ca-server
+func (i *Identity) ModifyAffiliation(req *api.ModifyAffiliationRequest) (*api.AffiliationResponse, error) {
+	log.Debugf("Entering identity.ModifyAffiliation with request: %+v", req)
+	if req.Name == "" {
+		return nil, errors.New("Affiliation to modify was not specified")
+	}
+	if req.NewName == "" {
+		return nil, errors.New("New affiliation not specified")
+	}
+
+	reqBody, err := util.Marshal(req, "ModifyAffiliationRequest")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.AffiliationResponse{}
+	err = i.Put(fmt.Sprintf("affiliations/%s", url.PathEscape(req.Name)), reqBody, affiliationQueryParams(req.Force, req.CAName), result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully modified affiliation: %+v", result)
+	return result, nil
+}
+
+// RemoveAffiliation removes an existing affiliation from the fabric-ca-server
+func (i *Identity) RemoveAffiliation(req *api.RemoveAffiliationRequest) (*api.AffiliationResponse, error) {
+	log.Debugf("Entering identity.RemoveAffiliation with request: %+v", req)
+	if req.Name == "" {
+		return nil, errors.New("Affiliation to remove was not specified")
+	}
+
+	endpoint := fmt.Sprintf("affiliations/%s", url.PathEscape(req.Name))
+	if req.Force {
+		endpoint = endpoint + "?force=true"
+	}
+
+	result := &api.AffiliationResponse{}
+	err := i.Delete(endpoint, req.CAName, result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully removed affiliation: %+v", result)
+	return result, nil
+}
+
+func affiliationQueryParams(force bool, caname string) map[string]string {
+	queryParam := map[string]string{"force": strconv.FormatBool(force)}
+	if caname != "" {
+		queryParam["ca"] = caname
+	}
+	return queryParam
+}
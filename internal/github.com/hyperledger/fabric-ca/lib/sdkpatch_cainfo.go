@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package lib
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+)
+
+// GetCAInfoResponse is the response from the GetCAInfo call
+type GetCAInfoResponse struct {
+	GetServerInfoResponse
+	// IssuerPublicKey is the bytes of the Idemix issuer public key, if the CA issues Idemix credentials
+	IssuerPublicKey []byte
+	// IssuerRevocationPublicKey is the PEM-encoded Idemix issuer revocation public key
+	IssuerRevocationPublicKey []byte
+}
+
+// caInfoResponseNet is flat since the response is decoded with mapstructure, which doesn't
+// set the fields of embedded structs
+type caInfoResponseNet struct {
+	// CAName is a unique name associated with fabric-ca-server's CA
+	CAName string
+	// Base64 encoding of PEM-encoded certificate chain
+	CAChain string
+	// Version of the server
+	Version string
+	// Base64 encoding of the Idemix issuer public key
+	IssuerPublicKey string
+	// Base64 encoding of the Idemix issuer revocation public key
+	IssuerRevocationPublicKey string
+}
+
+// GetCAInfo returns generic CA information
+func (c *Client) GetCAInfo(req *api.GetCAInfoRequest) (*GetCAInfoResponse, error) {
+	err := c.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := util.Marshal(req, "GetCAInfo")
+	if err != nil {
+		return nil, err
+	}
+	cainforeq, err := c.newPost("cainfo", body)
+	if err != nil {
+		return nil, err
+	}
+	netSI := &caInfoResponseNet{}
+	err = c.SendReq(cainforeq, netSI)
+	if err != nil {
+		return nil, err
+	}
+	localSI := &GetCAInfoResponse{}
+	err = c.net2LocalServerInfo(&serverInfoResponseNet{CAName: netSI.CAName, CAChain: netSI.CAChain, Version: netSI.Version}, &localSI.GetServerInfoResponse)
+	if err != nil {
+		return nil, err
+	}
+	// The Idemix issuer keys are only returned by CAs that issue Idemix credentials
+	if netSI.IssuerPublicKey != "" {
+		localSI.IssuerPublicKey, err = util.B64Decode(netSI.IssuerPublicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if netSI.IssuerRevocationPublicKey != "" {
+		localSI.IssuerRevocationPublicKey, err = util.B64Decode(netSI.IssuerRevocationPublicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return localSI, nil
+}
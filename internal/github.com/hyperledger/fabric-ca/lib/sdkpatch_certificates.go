@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package lib
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	log "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/logbridge"
+)
+
+// GetCertificates returns the certificates that match the request and that the caller is
+// authorized to see
+func (i *Identity) GetCertificates(req *api.GetCertificatesRequest) (*api.CertificatesResponse, error) {
+	log.Debugf("Entering identity.GetCertificates with request: %+v", req)
+
+	result := &api.CertificatesResponse{}
+	err := i.GetWithQueryParams("certificates", certificatesQueryParams(req), result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully retrieved %d certificates", len(result.Certs))
+	return result, nil
+}
+
+func certificatesQueryParams(req *api.GetCertificatesRequest) map[string]string {
+	queryParam := make(map[string]string)
+	add := func(key, value string) {
+		if value != "" {
+			queryParam[key] = value
+		}
+	}
+	add("id", req.ID)
+	add("aki", req.AKI)
+	add("serial", req.Serial)
+	add("revoked_start", req.Revoked.StartTime)
+	add("revoked_end", req.Revoked.EndTime)
+	add("expired_start", req.Expired.StartTime)
+	add("expired_end", req.Expired.EndTime)
+	add("ca", req.CAName)
+	if req.NotExpired {
+		queryParam["notexpired"] = "true"
+	}
+	if req.NotRevoked {
+		queryParam["notrevoked"] = "true"
+	}
+	return queryParam
+}
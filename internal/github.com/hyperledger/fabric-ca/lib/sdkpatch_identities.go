@@ -0,0 +1,192 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	log "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/logbridge"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	"github.com/pkg/errors"
+)
+
+// GetIdentity returns information about the requested identity
+func (i *Identity) GetIdentity(id, caname string) (*api.GetIDResponse, error) {
+	log.Debugf("Entering identity.GetIdentity %s", id)
+	result := &api.GetIDResponse{}
+	err := i.Get(fmt.Sprintf("identities/%s", url.PathEscape(id)), caname, result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully retrieved identity: %+v", result)
+	return result, nil
+}
+
+// GetAllIdentities returns all identities that the caller is authorized to see
+func (i *Identity) GetAllIdentities(caname string) (*api.GetAllIDsResponse, error) {
+	log.Debugf("Entering identity.GetAllIdentities")
+	result := &api.GetAllIDsResponse{}
+	err := i.Get("identities", caname, result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully retrieved identities: %+v", result)
+	return result, nil
+}
+
+// ModifyIdentity modifies an existing identity on the fabric-ca-server
+func (i *Identity) ModifyIdentity(req *api.ModifyIdentityRequest) (*api.IdentityResponse, error) {
+	log.Debugf("Entering identity.ModifyIdentity with request: %+v", req)
+	if req.ID == "" {
+		return nil, errors.New("Name of the identity to be modified is required")
+	}
+
+	reqBody, err := util.Marshal(req, "ModifyIdentityRequest")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.IdentityResponse{}
+	err = i.Put(fmt.Sprintf("identities/%s", url.PathEscape(req.ID)), reqBody, queryParams(req.CAName), result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully modified identity: %+v", result)
+	return result, nil
+}
+
+// RemoveIdentity removes an identity from the fabric-ca-server
+func (i *Identity) RemoveIdentity(req *api.RemoveIdentityRequest) (*api.IdentityResponse, error) {
+	log.Debugf("Entering identity.RemoveIdentity with request: %+v", req)
+	if req.ID == "" {
+		return nil, errors.New("Name of the identity to be removed is required")
+	}
+
+	endpoint := fmt.Sprintf("identities/%s", url.PathEscape(req.ID))
+	if req.Force {
+		endpoint = endpoint + "?force=true"
+	}
+
+	result := &api.IdentityResponse{}
+	err := i.Delete(endpoint, req.CAName, result)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Successfully removed identity: %s", req.ID)
+	return result, nil
+}
+
+// Get sends a get request to an endpoint
+func (i *Identity) Get(endpoint, caname string, result interface{}) error {
+	return i.GetWithQueryParams(endpoint, queryParams(caname), result)
+}
+
+// GetWithQueryParams sends a get request with the given query parameters to an endpoint
+func (i *Identity) GetWithQueryParams(endpoint string, queryParam map[string]string, result interface{}) error {
+	req, err := i.client.newGet(endpoint)
+	if err != nil {
+		return err
+	}
+	for key, value := range queryParam {
+		addQueryParm(req, key, value)
+	}
+	err = i.addTokenAuthHdr(req, nil)
+	if err != nil {
+		return err
+	}
+	return i.client.SendReq(req, result)
+}
+
+// Put sends a put request to an endpoint
+func (i *Identity) Put(endpoint string, reqBody []byte, queryParam map[string]string, result interface{}) error {
+	req, err := i.client.newPut(endpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	for key, value := range queryParam {
+		addQueryParm(req, key, value)
+	}
+	err = i.addTokenAuthHdr(req, reqBody)
+	if err != nil {
+		return err
+	}
+	return i.client.SendReq(req, result)
+}
+
+// Delete sends a delete request to an endpoint
+func (i *Identity) Delete(endpoint, caname string, result interface{}) error {
+	req, err := i.client.newDelete(endpoint)
+	if err != nil {
+		return err
+	}
+	if caname != "" {
+		addQueryParm(req, "ca", caname)
+	}
+	err = i.addTokenAuthHdr(req, nil)
+	if err != nil {
+		return err
+	}
+	return i.client.SendReq(req, result)
+}
+
+func queryParams(caname string) map[string]string {
+	if caname == "" {
+		return nil
+	}
+	return map[string]string{"ca": caname}
+}
+
+// newGet creates a new get request
+func (c *Client) newGet(endpoint string) (*http.Request, error) {
+	curl, err := c.getURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", curl, bytes.NewReader([]byte{}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed creating GET request for %s", curl)
+	}
+	return req, nil
+}
+
+// newPut creates a new put request
+func (c *Client) newPut(endpoint string, reqBody []byte) (*http.Request, error) {
+	curl, err := c.getURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("PUT", curl, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed creating PUT request for %s", curl)
+	}
+	return req, nil
+}
+
+// newDelete creates a new delete request
+func (c *Client) newDelete(endpoint string) (*http.Request, error) {
+	curl, err := c.getURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("DELETE", curl, bytes.NewReader([]byte{}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed creating DELETE request for %s", curl)
+	}
+	return req, nil
+}
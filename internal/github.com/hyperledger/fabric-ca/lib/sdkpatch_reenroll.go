@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package lib
+
+import (
+	"github.com/cloudflare/cfssl/csr"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	factory "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/cryptosuitebridge"
+	log "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/logbridge"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+// ReenrollUsingKey reenrolls an existing Identity and returns a new Identity
+// whose certificate is issued for the key of this identity instead of a newly
+// generated key
+// @param req The reenrollment request
+func (i *Identity) ReenrollUsingKey(req *api.ReenrollmentRequest) (*EnrollmentResponse, error) {
+	log.Debugf("Reenrolling %s using its key", util.StructToString(req))
+
+	key := i.ecert.Key()
+	csrPEM, err := i.client.GenCSRUsingKey(req.CSR, i.GetName(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	reqNet := &api.ReenrollmentRequestNet{
+		CAName:   req.CAName,
+		AttrReqs: req.AttrReqs,
+	}
+
+	// Get the body of the request
+	if req.CSR != nil {
+		reqNet.SignRequest.Hosts = req.CSR.Hosts
+	}
+	reqNet.SignRequest.Request = string(csrPEM)
+	reqNet.SignRequest.Profile = req.Profile
+	reqNet.SignRequest.Label = req.Label
+
+	body, err := util.Marshal(reqNet, "SignRequest")
+	if err != nil {
+		return nil, err
+	}
+	var result enrollmentResponseNet
+	err = i.Post("reenroll", body, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return i.client.newEnrollmentResponse(&result, i.GetName(), key)
+}
+
+// GenCSRUsingKey generates a CSR (Certificate Signing Request) for the given key
+func (c *Client) GenCSRUsingKey(req *api.CSRInfo, id string, key core.Key) ([]byte, error) {
+	log.Debugf("GenCSRUsingKey %+v", req)
+
+	err := c.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	cr := c.newCertificateRequest(req)
+	cr.CN = id
+	if req != nil && req.CN != "" {
+		cr.CN = req.CN
+	}
+
+	cspSigner, err := factory.NewCspSigner(c.csp, key)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Failed initializing CryptoSigner")
+	}
+
+	csrPEM, err := csr.Generate(cspSigner, cr)
+	if err != nil {
+		log.Debugf("failed generating CSR: %s", err)
+		return nil, err
+	}
+	return csrPEM, nil
+}
@@ -0,0 +1,24 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package lib
+
+import (
+	"net/http"
+)
+
+// WithTransport returns a copy of the client whose HTTP transport is wrapped by the given
+// function, e.g. to add headers to the requests. The copy shares the configuration of this
+// client, which must have been initialized.
+func (c *Client) WithTransport(wrap func(http.RoundTripper) http.RoundTripper) *Client {
+	client := *c
+	client.httpClient = &http.Client{Transport: wrap(c.httpClient.Transport)}
+	return &client
+}
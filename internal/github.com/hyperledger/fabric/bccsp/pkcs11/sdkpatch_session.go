@@ -0,0 +1,33 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package pkcs11
+
+import (
+	"fmt"
+)
+
+// CheckSession verifies that a session with the HSM can be obtained and is usable
+func (csp *impl) CheckSession() (err error) {
+	defer func() {
+		// getSession panics if a session can't be opened
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	session := csp.getSession()
+	if _, err = csp.ctx.GetSessionInfo(session); err != nil {
+		csp.ctx.CloseSession(session)
+		return fmt.Errorf("GetSessionInfo failed [%s]", err)
+	}
+	csp.returnSession(session)
+	return nil
+}
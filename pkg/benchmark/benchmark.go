@@ -0,0 +1,249 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package benchmark generates load against a Fabric network and reports the latency and
+// throughput of the requests, so that changes to the SDK or to the sizing of a network may
+// be benchmarked consistently.
+//
+//  Basic Flow:
+//  1) Create a channel client
+//  2) Define the operations of the workload (e.g. an invoke/query mix)
+//  3) Create a runner with the concurrency and the number of requests or the duration
+//  4) Run the workload and print the report
+//
+//  runner := benchmark.New(
+//      benchmark.WithOperations(
+//          benchmark.Invoke(client, "mycc", "move", benchmark.WithWeight(1), benchmark.WithPayloadSize(1024)),
+//          benchmark.Query(client, "mycc", "query", benchmark.WithWeight(9), benchmark.WithArgs([]byte("a"))),
+//      ),
+//      benchmark.WithConcurrency(50),
+//      benchmark.WithDuration(time.Minute),
+//  )
+//  report, err := runner.Run(context.Background())
+package benchmark
+
+import (
+	reqContext "context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/benchmark")
+
+// Operation is a request that is issued by the workload. Operations are chosen at random in
+// proportion to their weights.
+type Operation struct {
+	// Name identifies the operation in the report
+	Name string
+	// Weight is the relative frequency of the operation in the workload
+	Weight int
+	// Do issues the request
+	Do func(ctx reqContext.Context) error
+}
+
+// Runner runs a workload
+type Runner struct {
+	options
+}
+
+// Opt is a runner option
+type Opt func(o *options)
+
+// WithOperations sets the operations of the workload
+func WithOperations(ops ...Operation) Opt {
+	return func(o *options) {
+		o.operations = append(o.operations, ops...)
+	}
+}
+
+// WithConcurrency sets the number of workers that issue requests concurrently
+func WithConcurrency(value int) Opt {
+	return func(o *options) {
+		o.concurrency = value
+	}
+}
+
+// WithRequests sets the total number of requests issued by the workload
+func WithRequests(value int) Opt {
+	return func(o *options) {
+		o.requests = value
+	}
+}
+
+// WithDuration sets the time during which the workload issues requests. The duration
+// applies if the number of requests isn't set.
+func WithDuration(value time.Duration) Opt {
+	return func(o *options) {
+		o.duration = value
+	}
+}
+
+// WithRate limits the number of requests issued per second (0 for no limit)
+func WithRate(value float64) Opt {
+	return func(o *options) {
+		o.rate = value
+	}
+}
+
+// WithWarmup sets the number of requests that are issued before the measurements start
+func WithWarmup(value int) Opt {
+	return func(o *options) {
+		o.warmup = value
+	}
+}
+
+type options struct {
+	operations  []Operation
+	concurrency int
+	requests    int
+	duration    time.Duration
+	rate        float64
+	warmup      int
+}
+
+// New returns a new workload runner
+func New(opts ...Opt) *Runner {
+	r := &Runner{
+		options: options{
+			concurrency: 1,
+		},
+	}
+	for _, opt := range opts {
+		opt(&r.options)
+	}
+	return r
+}
+
+// Run issues the requests of the workload and returns the report once all of the requests
+// have been issued or the duration has elapsed. The workload stops early if the context is done.
+func (r *Runner) Run(ctx reqContext.Context) (*Report, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	if r.warmup > 0 {
+		logger.Debugf("Issuing %d warmup requests...", r.warmup)
+		r.run(ctx, r.warmup, 0, newRecorder(r.operations))
+	}
+
+	rec := newRecorder(r.operations)
+	start := time.Now()
+	r.run(ctx, r.requests, r.duration, rec)
+	return rec.report(time.Since(start)), nil
+}
+
+func (r *Runner) validate() error {
+	if len(r.operations) == 0 {
+		return errors.New("no operations specified")
+	}
+	for _, op := range r.operations {
+		if op.Weight < 0 || op.Do == nil {
+			return errors.Errorf("invalid operation [%s]", op.Name)
+		}
+	}
+	if r.concurrency <= 0 {
+		return errors.New("concurrency must be greater than zero")
+	}
+	if r.requests <= 0 && r.duration <= 0 {
+		return errors.New("either the number of requests or the duration must be specified")
+	}
+	return nil
+}
+
+func (r *Runner) run(ctx reqContext.Context, requests int, duration time.Duration, rec *recorder) {
+	if requests <= 0 && duration > 0 {
+		var cancel reqContext.CancelFunc
+		ctx, cancel = reqContext.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	next := r.dispatch(ctx, requests)
+
+	var wg sync.WaitGroup
+	wg.Add(r.concurrency)
+	for i := 0; i < r.concurrency; i++ {
+		go func(seed int64) {
+			defer wg.Done()
+			chooser := newChooser(r.operations, rand.New(rand.NewSource(seed)))
+			for range next {
+				op := chooser.next()
+				begin := time.Now()
+				err := r.operations[op].Do(ctx)
+				if err != nil && ctx.Err() != nil {
+					// The request was interrupted at the end of the workload
+					return
+				}
+				rec.record(op, time.Since(begin), err)
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+}
+
+// dispatch returns the channel from which the workers take the requests. The channel
+// is closed once the requests have been issued or the context is done.
+func (r *Runner) dispatch(ctx reqContext.Context, requests int) <-chan struct{} {
+	next := make(chan struct{})
+	go func() {
+		defer close(next)
+
+		var tick <-chan time.Time
+		if r.rate > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / r.rate))
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for issued := 0; requests <= 0 || issued < requests; issued++ {
+			if tick != nil {
+				select {
+				case <-tick:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case next <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return next
+}
+
+// chooser chooses operations at random in proportion to their weights
+type chooser struct {
+	cumulative []int
+	total      int
+	rnd        *rand.Rand
+}
+
+func newChooser(ops []Operation, rnd *rand.Rand) *chooser {
+	c := &chooser{rnd: rnd}
+	for _, op := range ops {
+		c.total += op.Weight
+		c.cumulative = append(c.cumulative, c.total)
+	}
+	return c
+}
+
+func (c *chooser) next() int {
+	if c.total == 0 {
+		return c.rnd.Intn(len(c.cumulative))
+	}
+	n := c.rnd.Intn(c.total)
+	for i, cumulative := range c.cumulative {
+		if n < cumulative {
+			return i
+		}
+	}
+	return len(c.cumulative) - 1
+}
@@ -0,0 +1,146 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package benchmark
+
+import (
+	reqContext "context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRequests(t *testing.T) {
+	var mutex sync.Mutex
+	counts := make(map[string]int)
+	op := func(name string, err error) Operation {
+		return Operation{
+			Name:   name,
+			Weight: 1,
+			Do: func(ctx reqContext.Context) error {
+				mutex.Lock()
+				counts[name]++
+				mutex.Unlock()
+				return err
+			},
+		}
+	}
+
+	runner := New(
+		WithOperations(op("ok", nil), op("failed", errors.New("failed"))),
+		WithConcurrency(5),
+		WithRequests(200),
+		WithWarmup(10),
+	)
+	report, err := runner.Run(reqContext.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 210, counts["ok"]+counts["failed"])
+	assert.Equal(t, 200, report.Total.Count)
+	require.Len(t, report.Operations, 2)
+	assert.Equal(t, report.Operations[1].Count, report.Operations[1].Errors)
+	assert.Error(t, report.Operations[1].FirstError)
+	assert.Equal(t, 0, report.Operations[0].Errors)
+	assert.True(t, report.Total.Throughput > 0)
+	assert.Contains(t, report.String(), "failed")
+}
+
+func TestRunDuration(t *testing.T) {
+	runner := New(
+		WithOperations(Operation{Name: "op", Do: func(ctx reqContext.Context) error { return nil }}),
+		WithDuration(200*time.Millisecond),
+		WithRate(50),
+	)
+
+	start := time.Now()
+	report, err := runner.Run(reqContext.Background())
+	require.NoError(t, err)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+	assert.True(t, report.Total.Count > 0 && report.Total.Count <= 11, "unexpected number of requests: %d", report.Total.Count)
+}
+
+func TestInvalidWorkload(t *testing.T) {
+	_, err := New(WithRequests(1)).Run(reqContext.Background())
+	assert.Error(t, err)
+
+	op := Operation{Name: "op", Do: func(ctx reqContext.Context) error { return nil }}
+	_, err = New(WithOperations(op)).Run(reqContext.Background())
+	assert.Error(t, err)
+
+	_, err = New(WithOperations(op), WithRequests(1), WithConcurrency(0)).Run(reqContext.Background())
+	assert.Error(t, err)
+}
+
+func TestStats(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	s := newStats("op", latencies, 5, nil, 10*time.Second)
+	assert.Equal(t, 105, s.Count)
+	assert.Equal(t, time.Millisecond, s.Min)
+	assert.Equal(t, 100*time.Millisecond, s.Max)
+	assert.Equal(t, 50*time.Millisecond, s.P50)
+	assert.Equal(t, 90*time.Millisecond, s.P90)
+	assert.Equal(t, 99*time.Millisecond, s.P99)
+	assert.Equal(t, 50500*time.Microsecond, s.Mean)
+	assert.Equal(t, 10.0, s.Throughput)
+}
+
+type mockChannelClient struct {
+	mutex    sync.Mutex
+	executed []channel.Request
+	queried  []channel.Request
+}
+
+func (c *mockChannelClient) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.executed = append(c.executed, request)
+	return channel.Response{}, nil
+}
+
+func (c *mockChannelClient) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.queried = append(c.queried, request)
+	return channel.Response{}, nil
+}
+
+func TestChaincodeOperations(t *testing.T) {
+	client := &mockChannelClient{}
+
+	runner := New(
+		WithOperations(
+			Invoke(client, "mycc", "put", WithWeight(1), WithArgs([]byte("key")), WithPayloadSize(16)),
+			Query(client, "mycc", "get", WithWeight(3), WithArgs([]byte("key"))),
+		),
+		WithConcurrency(4),
+		WithRequests(400),
+	)
+	report, err := runner.Run(reqContext.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 400, report.Total.Count)
+	assert.Equal(t, "invoke put", report.Operations[0].Name)
+
+	require.Equal(t, 400, len(client.executed)+len(client.queried))
+	assert.True(t, len(client.queried) > len(client.executed), "expecting more queries than invokes")
+
+	for _, request := range client.executed {
+		require.Len(t, request.Args, 2)
+		assert.Equal(t, []byte("key"), request.Args[0])
+		assert.Len(t, request.Args[1], 16)
+	}
+	for _, request := range client.queried {
+		assert.Equal(t, [][]byte{[]byte("key")}, request.Args)
+	}
+}
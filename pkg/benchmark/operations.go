@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package benchmark
+
+import (
+	reqContext "context"
+	"math/rand"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+)
+
+// ChannelClient is the subset of the channel client that issues the invokes and queries
+type ChannelClient interface {
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+// OperationOpt is an option of a chaincode operation
+type OperationOpt func(o *operationOptions)
+
+// WithWeight sets the relative frequency of the operation in the workload (default 1)
+func WithWeight(value int) OperationOpt {
+	return func(o *operationOptions) {
+		o.weight = value
+	}
+}
+
+// WithArgs sets the arguments of the chaincode function
+func WithArgs(args ...[]byte) OperationOpt {
+	return func(o *operationOptions) {
+		o.args = args
+	}
+}
+
+// WithPayloadSize adds an argument of the given number of random bytes to the arguments
+// of the chaincode function. A new payload is generated for every request.
+func WithPayloadSize(value int) OperationOpt {
+	return func(o *operationOptions) {
+		o.payloadSize = value
+	}
+}
+
+// WithRequestOptions sets the channel client options of the requests
+func WithRequestOptions(opts ...channel.RequestOption) OperationOpt {
+	return func(o *operationOptions) {
+		o.requestOpts = opts
+	}
+}
+
+type operationOptions struct {
+	weight      int
+	args        [][]byte
+	payloadSize int
+	requestOpts []channel.RequestOption
+}
+
+// Invoke returns an operation that executes (endorses and commits) the given chaincode function
+func Invoke(client ChannelClient, ccID, fcn string, opts ...OperationOpt) Operation {
+	return newChaincodeOperation("invoke", ccID, fcn, client.Execute, opts)
+}
+
+// Query returns an operation that queries the given chaincode function
+func Query(client ChannelClient, ccID, fcn string, opts ...OperationOpt) Operation {
+	return newChaincodeOperation("query", ccID, fcn, client.Query, opts)
+}
+
+type requestFunc func(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+
+func newChaincodeOperation(kind, ccID, fcn string, invoke requestFunc, opts []OperationOpt) Operation {
+	o := &operationOptions{weight: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return Operation{
+		Name:   kind + " " + fcn,
+		Weight: o.weight,
+		Do: func(ctx reqContext.Context) error {
+			args := o.args
+			if o.payloadSize > 0 {
+				args = append(append([][]byte(nil), o.args...), randomPayload(o.payloadSize))
+			}
+			requestOpts := append([]channel.RequestOption{channel.WithParentContext(ctx)}, o.requestOpts...)
+			_, err := invoke(channel.Request{ChaincodeID: ccID, Fcn: fcn, Args: args}, requestOpts...)
+			return err
+		},
+	}
+}
+
+func randomPayload(size int) []byte {
+	payload := make([]byte, size)
+	rand.Read(payload) //nolint
+	return payload
+}
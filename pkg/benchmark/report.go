@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package benchmark
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Report contains the results of a workload
+type Report struct {
+	// Duration is the time taken by the workload
+	Duration time.Duration
+	// Total contains the statistics of all of the requests
+	Total Stats
+	// Operations contains the statistics of the requests of each operation
+	Operations []Stats
+}
+
+// Stats contains the latency and throughput statistics of requests
+type Stats struct {
+	Name   string
+	Count  int
+	Errors int
+	// Throughput is the number of successful requests per second
+	Throughput float64
+	Min        time.Duration
+	Mean       time.Duration
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+	// FirstError is the first error returned by a request, if any
+	FirstError error
+}
+
+// String returns the report as a table
+func (r *Report) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Duration: %s\n", r.Duration)
+	fmt.Fprintf(&buf, "%-12s %10s %8s %10s %10s %10s %10s %10s %10s %10s\n",
+		"Operation", "Requests", "Errors", "Req/s", "Min", "Mean", "P50", "P90", "P99", "Max")
+	for _, s := range append(r.Operations, r.Total) {
+		fmt.Fprintf(&buf, "%-12s %10d %8d %10.1f %10s %10s %10s %10s %10s %10s\n",
+			s.Name, s.Count, s.Errors, s.Throughput, round(s.Min), round(s.Mean), round(s.P50), round(s.P90), round(s.P99), round(s.Max))
+	}
+	for _, s := range r.Operations {
+		if s.FirstError != nil {
+			fmt.Fprintf(&buf, "First error of %s: %s\n", s.Name, s.FirstError)
+		}
+	}
+	return buf.String()
+}
+
+func round(d time.Duration) time.Duration {
+	return d - d%(10*time.Microsecond)
+}
+
+// recorder records the latencies of the requests of each operation
+type recorder struct {
+	sync.Mutex
+	names      []string
+	latencies  [][]time.Duration
+	errors     []int
+	firstError []error
+}
+
+func newRecorder(ops []Operation) *recorder {
+	r := &recorder{
+		latencies:  make([][]time.Duration, len(ops)),
+		errors:     make([]int, len(ops)),
+		firstError: make([]error, len(ops)),
+	}
+	for _, op := range ops {
+		r.names = append(r.names, op.Name)
+	}
+	return r
+}
+
+func (r *recorder) record(op int, latency time.Duration, err error) {
+	r.Lock()
+	defer r.Unlock()
+
+	if err != nil {
+		r.errors[op]++
+		if r.firstError[op] == nil {
+			r.firstError[op] = err
+		}
+		return
+	}
+	r.latencies[op] = append(r.latencies[op], latency)
+}
+
+func (r *recorder) report(duration time.Duration) *Report {
+	r.Lock()
+	defer r.Unlock()
+
+	report := &Report{Duration: duration}
+
+	var all []time.Duration
+	var errs int
+	for i, name := range r.names {
+		report.Operations = append(report.Operations, newStats(name, r.latencies[i], r.errors[i], r.firstError[i], duration))
+		all = append(all, r.latencies[i]...)
+		errs += r.errors[i]
+	}
+	report.Total = newStats("Total", all, errs, nil, duration)
+	return report
+}
+
+func newStats(name string, latencies []time.Duration, errs int, firstError error, duration time.Duration) Stats {
+	s := Stats{
+		Name:       name,
+		Count:      len(latencies) + errs,
+		Errors:     errs,
+		FirstError: firstError,
+	}
+	if len(latencies) == 0 {
+		return s
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+
+	s.Min = latencies[0]
+	s.Max = latencies[len(latencies)-1]
+	s.Mean = sum / time.Duration(len(latencies))
+	s.P50 = percentile(latencies, 50)
+	s.P90 = percentile(latencies, 90)
+	s.P99 = percentile(latencies, 99)
+	if duration > 0 {
+		s.Throughput = float64(len(latencies)) / duration.Seconds()
+	}
+	return s
+}
+
+// percentile returns the nearest-rank percentile of the given sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
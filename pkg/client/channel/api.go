@@ -10,9 +10,11 @@ import (
 	reqContext "context"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	commcfg "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
@@ -25,6 +27,9 @@ type requestOptions struct {
 	Retry         retry.Opts
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for channel client operations
 	ParentContext reqContext.Context                //parent grpc context for channel client operations (query, execute, invokehandler)
+	RequestID     string                            //ID of the request which is attached to logs, GRPC metadata and audit events
+	MessageSizes  commcfg.MessageSizes              //overrides the maximum GRPC message sizes of the endpoints
+	operation     string                            //name of the operation for audit events
 }
 
 // RequestOption func for each Opts argument
@@ -46,6 +51,8 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	// Receipt is the receipt of a committed transaction (set by Execute)
+	Receipt *invoke.Receipt
 }
 
 //WithTargets allows overriding of the target peers for the request
@@ -125,3 +132,26 @@ func WithParentContext(parentContext reqContext.Context) RequestOption {
 		return nil
 	}
 }
+
+//WithRequestID sets the ID of the request. The ID is attached to the log messages of the request,
+//is sent to the peers and orderers in the GRPC metadata and is included in audit events. If not
+//provided then the request ID of the parent context is used (see audit.WithRequestID) or a new ID
+//is generated.
+func WithRequestID(requestID string) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.RequestID = requestID
+		return nil
+	}
+}
+
+// WithMaxMessageSize overrides the maximum sizes (in bytes) of the GRPC messages received from and sent
+// to the endpoints for the request. A size of zero keeps the limit of the endpoint.
+func WithMaxMessageSize(maxRecv, maxSend int) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if maxRecv < 0 || maxSend < 0 {
+			return errors.New("message size must not be negative")
+		}
+		o.MessageSizes = commcfg.MessageSizes{MaxRecv: maxRecv, MaxSend: maxSend}
+		return nil
+	}
+}
@@ -13,6 +13,13 @@ SPDX-License-Identifier: Apache-2.0
 //  2) Create channel client
 //  3) Execute chaincode
 //  4) Query chaincode
+//
+// Endorsements are collected and transactions are submitted to the orderer by the SDK itself.
+// They can't be delegated to a peer: the Endorse, Submit, CommitStatus and Evaluate calls of the
+// Gateway gRPC service were added to the peer in Fabric 2.4, and this SDK doesn't include the
+// gateway protos needed to call them. Applications that need the peer to collect the
+// endorsements should use the fabric-gateway client library; pkg/gateway offers the Gateway
+// programming model on top of this client.
 package channel
 
 import (
@@ -22,14 +29,38 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/greylist"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/readonly"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	commcfg "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/pkg/errors"
 )
 
+var logger = logging.NewLogger("fabsdk/client")
+var tracer = tracing.NewTracer("fabsdk/client")
+
+var (
+	transactionCount = metrics.NewCounter(metrics.CounterOpts{
+		Subsystem:  "channel",
+		Name:       "transactions_total",
+		Help:       "The number of chaincode invocations (queries and transactions) made by channel clients.",
+		LabelNames: []string{"channel", "chaincode", "status"},
+	})
+	transactionDuration = metrics.NewHistogram(metrics.HistogramOpts{
+		Subsystem:  "channel",
+		Name:       "transaction_duration_seconds",
+		Help:       "The time taken by chaincode invocations, from endorsement to commit.",
+		LabelNames: []string{"channel", "chaincode"},
+	})
+)
+
 // Client enables access to a channel on a Fabric network.
 //
 // A channel client instance provides a handler to interact with peers on specified channel.
@@ -97,6 +128,7 @@ func (cc *Client) Query(request Request, options ...RequestOption) (Response, er
 
 	options = append(options, addDefaultTimeout(fab.Query))
 	options = append(options, addDefaultTargetFilter(cc.context, filter.ChaincodeQuery))
+	options = append(options, withOperation("channel.Query"))
 
 	return cc.InvokeHandler(invoke.NewQueryHandler(), request, options...)
 }
@@ -107,10 +139,18 @@ func (cc *Client) Query(request Request, options ...RequestOption) (Response, er
 //  options holds optional request options
 //
 //  Returns:
-//  the proposal responses from peer(s)
+//  the proposal responses from peer(s) and the receipt of the committed transaction
+//  (see invoke.Receipt), which can be kept for non-repudiation and verified later
+//
+// Execute fails with readonly.ErrReadOnly if the SDK is in read-only mode.
 func (cc *Client) Execute(request Request, options ...RequestOption) (Response, error) {
+	if err := readonly.Check(cc.context, "channel.Execute"); err != nil {
+		return Response{}, err
+	}
+
 	options = append(options, addDefaultTimeout(fab.Execute))
 	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
+	options = append(options, withOperation("channel.Execute"))
 
 	return cc.InvokeHandler(invoke.NewExecuteHandler(), request, options...)
 }
@@ -125,6 +165,14 @@ func addDefaultTargetFilter(chCtx context.Channel, ft filter.EndpointType) Reque
 	}
 }
 
+// withOperation sets the name of the operation that's reported in audit events
+func withOperation(operation string) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.operation = operation
+		return nil
+	}
+}
+
 // addDefaultTimeout adds default timeout if timeout is not specified
 func addDefaultTimeout(tt fab.TimeoutType) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -143,7 +191,7 @@ func addDefaultTimeout(tt fab.TimeoutType) RequestOption {
 //
 //  Returns:
 //  the proposal responses from peer(s)
-func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options ...RequestOption) (Response, error) {
+func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options ...RequestOption) (response Response, err error) {
 	//Read execute tx options
 	txnOpts, err := cc.prepareOptsFromOptions(cc.context, options...)
 	if err != nil {
@@ -153,6 +201,22 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 	reqCtx, cancel := cc.createReqContext(&txnOpts)
 	defer cancel()
 
+	// The span is the parent of the spans of the handlers and of the peer and orderer calls
+	reqCtx, span := tracer.Start(reqCtx, "channel.Invoke", tracing.String("channel", cc.context.ChannelID()),
+		tracing.String("chaincode", request.ChaincodeID), tracing.String("fcn", request.Fcn))
+	start := time.Now()
+	defer func() {
+		if response.TransactionID != "" {
+			span.SetAttributes(tracing.String("txID", string(response.TransactionID)))
+		}
+		tracing.End(span, err)
+		cc.recordTransaction(request.ChaincodeID, start, err)
+		cc.recordAudit(reqCtx, txnOpts.operation, request, response, err)
+	}()
+
+	logger.With(logging.RequestID(audit.RequestID(reqCtx)), logging.Channel(cc.context.ChannelID())).
+		Debugf("Invoking chaincode [%s] function [%s]", request.ChaincodeID, request.Fcn)
+
 	//Prepare context objects for handler
 	requestContext, clientContext, err := cc.prepareHandlerContexts(reqCtx, request, txnOpts)
 	if err != nil {
@@ -173,6 +237,11 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 		),
 	)
 
+	release, err := cc.trackRequest()
+	if err != nil {
+		return Response{}, err
+	}
+
 	complete := make(chan bool)
 	go func() {
 		_, _ = invoker.Invoke(
@@ -180,6 +249,7 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 				handler.Handle(requestContext, clientContext)
 				return nil, requestContext.Error
 			})
+		release()
 		complete <- true
 	}()
 	select {
@@ -191,6 +261,57 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 	}
 }
 
+// trackRequest registers the request as in flight (if supported by the infra provider) so that
+// it completes before the SDK is closed. The returned function must be called on completion.
+func (cc *Client) trackRequest() (func(), error) {
+	tracker, ok := cc.context.InfraProvider().(fab.RequestTracker)
+	if !ok {
+		return func() {}, nil
+	}
+	if !tracker.Acquire() {
+		return nil, status.New(status.ClientStatus, status.ShuttingDown.ToInt32(), "the SDK is shutting down", nil)
+	}
+	return tracker.Release, nil
+}
+
+// recordTransaction reports the outcome and duration of a chaincode invocation
+func (cc *Client) recordTransaction(chaincodeID string, start time.Time, err error) {
+	txStatus := "success"
+	if err != nil {
+		txStatus = "failure"
+	}
+	transactionCount.With(cc.context.ChannelID(), chaincodeID, txStatus).Add(1)
+	transactionDuration.With(cc.context.ChannelID(), chaincodeID).Observe(time.Since(start).Seconds())
+}
+
+// recordAudit records an audit event for the invocation (if auditing is enabled)
+func (cc *Client) recordAudit(reqCtx reqContext.Context, operation string, request Request, response Response, err error) {
+	if !audit.Enabled() {
+		return
+	}
+
+	if operation == "" {
+		operation = "channel.InvokeHandler"
+	}
+
+	event := &audit.Event{
+		RequestID:  audit.RequestID(reqCtx),
+		MSPID:      cc.context.Identifier().MSPID,
+		Identity:   cc.context.Identifier().ID,
+		Operation:  operation,
+		Channel:    cc.context.ChannelID(),
+		Attributes: map[string]string{"chaincode": request.ChaincodeID, "fcn": request.Fcn},
+		Err:        err,
+	}
+	for _, r := range response.Responses {
+		event.Endpoints = append(event.Endpoints, r.Endorser)
+	}
+	if response.TransactionID != "" {
+		event.Attributes["txID"] = string(response.TransactionID)
+	}
+	audit.Record(event)
+}
+
 //createReqContext creates req context for invoke handler
 func (cc *Client) createReqContext(txnOpts *requestOptions) (reqContext.Context, reqContext.CancelFunc) {
 
@@ -208,6 +329,16 @@ func (cc *Client) createReqContext(txnOpts *requestOptions) (reqContext.Context,
 	//Add timeout overrides here as a value so that it can be used by immediate child contexts (in handlers/transactors)
 	reqCtx = reqContext.WithValue(reqCtx, contextImpl.ReqContextTimeoutOverrides, txnOpts.Timeouts)
 
+	//Use the request ID from the options or from the parent context, otherwise generate one
+	if txnOpts.RequestID != "" {
+		reqCtx = audit.WithRequestID(reqCtx, txnOpts.RequestID)
+	}
+	reqCtx, _ = audit.EnsureRequestID(reqCtx)
+
+	if txnOpts.MessageSizes != (commcfg.MessageSizes{}) {
+		reqCtx = commcfg.WithMessageSizes(reqCtx, txnOpts.MessageSizes)
+	}
+
 	return reqCtx, cancel
 }
 
@@ -245,9 +376,18 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 		EventService: cc.eventService,
 	}
 
+	// The request ID and message sizes are carried by the request context
+	handlerOpts := invoke.Opts{
+		Targets:       o.Targets,
+		TargetFilter:  o.TargetFilter,
+		Retry:         o.Retry,
+		Timeouts:      o.Timeouts,
+		ParentContext: o.ParentContext,
+	}
+
 	requestContext := &invoke.RequestContext{
 		Request:         invoke.Request(request),
-		Opts:            invoke.Opts(o),
+		Opts:            handlerOpts,
 		Response:        invoke.Response{},
 		RetryHandler:    retry.New(o.Retry),
 		Ctx:             reqCtx,
@@ -18,10 +18,12 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
 	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/staticselection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/readonly"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
@@ -115,6 +117,44 @@ func TestQuery(t *testing.T) {
 
 }
 
+func TestQueryAudit(t *testing.T) {
+	var events []*audit.Event
+	audit.Initialize(audit.SinkFunc(func(event *audit.Event) {
+		events = append(events, event)
+	}))
+	defer audit.Initialize(nil)
+
+	chClient := setupChannelClient(nil, t)
+
+	_, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}, WithRequestID("req1"))
+	if err != nil {
+		t.Fatalf("Failed to invoke test cc: %s", err)
+	}
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	if err != nil {
+		t.Fatalf("Failed to invoke test cc: %s", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expecting 2 audit events but got %d", len(events))
+	}
+
+	event := events[0]
+	assert.Equal(t, "req1", event.RequestID)
+	assert.Equal(t, "channel.Query", event.Operation)
+	assert.Equal(t, channelID, event.Channel)
+	assert.Equal(t, "test", event.MSPID)
+	assert.Equal(t, "test", event.Identity)
+	assert.Equal(t, "testCC", event.Attributes["chaincode"])
+	assert.Equal(t, "invoke", event.Attributes["fcn"])
+	assert.NotEmpty(t, event.Endpoints)
+	assert.NoError(t, event.Err)
+
+	assert.NotEmpty(t, events[1].RequestID, "expecting request ID to be generated")
+	assert.NotEqual(t, "req1", events[1].RequestID)
+}
+
 func TestQuerySelectionError(t *testing.T) {
 	chClient := setupChannelClientWithError(nil, errors.New("Test Error"), nil, t)
 
@@ -229,6 +269,21 @@ func TestExecuteTx(t *testing.T) {
 
 }
 
+func TestExecuteReadOnly(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+	chClient.context.(*contextImpl.Channel).Client.(*fcmocks.MockContext).SetReadOnly(true)
+
+	_, err := chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}})
+	if !readonly.IsReadOnly(err) {
+		t.Fatalf("Expecting read-only error but got %v", err)
+	}
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	if err != nil {
+		t.Fatalf("Expecting query to succeed in read-only mode but got %s", err)
+	}
+}
+
 type customHandler struct {
 	expectedPayload []byte
 }
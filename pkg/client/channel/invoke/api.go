@@ -43,6 +43,8 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	// Receipt is the receipt of a committed transaction (set by Execute)
+	Receipt *Receipt
 }
 
 //Handler for chaining transaction executions
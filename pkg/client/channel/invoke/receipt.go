@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// Receipt is the evidence that a transaction was endorsed and committed, which may be kept in an
+// audit trail for non-repudiation. It holds the endorsements of the transaction, i.e. the signatures
+// of the endorsing peers over the proposal response payload (which contains the hash of the proposal
+// and the results of the simulation), so that it can be verified later against the MSP config of the
+// channel with Verify. The block number and the validation code are those that were reported by the
+// event service; they aren't signed by the endorsers and may be checked against the block.
+//
+// A receipt can be marshalled to JSON.
+type Receipt struct {
+	ChannelID               string              `json:"channelID"`
+	TxID                    fab.TransactionID   `json:"txID"`
+	BlockNumber             uint64              `json:"blockNumber"`
+	TxValidationCode        pb.TxValidationCode `json:"txValidationCode"`
+	ProposalResponsePayload []byte              `json:"proposalResponsePayload"`
+	Endorsements            []*pb.Endorsement   `json:"endorsements"`
+}
+
+// newReceipt creates the receipt of a committed transaction from its proposal and proposal responses
+func newReceipt(proposal *fab.TransactionProposal, responses []*fab.TransactionProposalResponse, txStatus *fab.TxStatusEvent) (*Receipt, error) {
+	if len(responses) == 0 {
+		return nil, errors.New("no proposal responses")
+	}
+
+	channelID, err := proposalChannelID(proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &Receipt{
+		ChannelID:        channelID,
+		TxID:             proposal.TxnID,
+		BlockNumber:      txStatus.BlockNumber,
+		TxValidationCode: txStatus.TxValidationCode,
+		// The payloads of the responses were checked to be the same by the endorsement validation
+		ProposalResponsePayload: responses[0].ProposalResponse.Payload,
+	}
+	for _, r := range responses {
+		if r.ProposalResponse.Endorsement == nil {
+			return nil, errors.Errorf("endorsement is missing from the response of [%s]", r.Endorser)
+		}
+		receipt.Endorsements = append(receipt.Endorsements, r.ProposalResponse.Endorsement)
+	}
+	return receipt, nil
+}
+
+func proposalChannelID(proposal *fab.TransactionProposal) (string, error) {
+	if proposal == nil || proposal.Proposal == nil {
+		return "", errors.New("proposal is nil")
+	}
+	header := &common.Header{}
+	if err := proto.Unmarshal(proposal.Header, header); err != nil {
+		return "", errors.Wrap(err, "unmarshal of proposal header failed")
+	}
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(header.ChannelHeader, channelHeader); err != nil {
+		return "", errors.Wrap(err, "unmarshal of channel header failed")
+	}
+	return channelHeader.ChannelId, nil
+}
+
+// Endorsers returns the identities (MSP ID and certificate) of the peers that endorsed the transaction
+func (r *Receipt) Endorsers() ([]*mb.SerializedIdentity, error) {
+	var endorsers []*mb.SerializedIdentity
+	for _, endorsement := range r.Endorsements {
+		identity := &mb.SerializedIdentity{}
+		if err := proto.Unmarshal(endorsement.Endorser, identity); err != nil {
+			return nil, errors.Wrap(err, "unmarshal of endorser identity failed")
+		}
+		endorsers = append(endorsers, identity)
+	}
+	return endorsers, nil
+}
+
+// Verify checks that the transaction was endorsed and that each endorsement is a valid signature of
+// the proposal response payload by an identity that is valid for the given membership of the channel
+func (r *Receipt) Verify(membership fab.ChannelMembership) error {
+	v := &verifier.Block{Membership: membership}
+	return v.VerifyEndorsedAction(string(r.TxID), &pb.ChaincodeEndorsedAction{
+		ProposalResponsePayload: r.ProposalResponsePayload,
+		Endorsements:            r.Endorsements,
+	})
+}
@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceipt(t *testing.T) {
+	endorser, err := proto.Marshal(&mb.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("cert")})
+	require.NoError(t, err)
+
+	proposal := newTestProposal(t, "mychannel")
+	responses := []*fab.TransactionProposalResponse{
+		{
+			Endorser: "peer1",
+			ProposalResponse: &pb.ProposalResponse{
+				Payload:     []byte("payload"),
+				Endorsement: &pb.Endorsement{Endorser: endorser, Signature: []byte("signature")},
+			},
+		},
+	}
+
+	receipt, err := newReceipt(proposal, responses, &fab.TxStatusEvent{TxValidationCode: pb.TxValidationCode_VALID, BlockNumber: 5})
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", receipt.ChannelID)
+	assert.Equal(t, proposal.TxnID, receipt.TxID)
+	assert.Equal(t, uint64(5), receipt.BlockNumber)
+	assert.Equal(t, []byte("payload"), receipt.ProposalResponsePayload)
+
+	endorsers, err := receipt.Endorsers()
+	require.NoError(t, err)
+	require.Len(t, endorsers, 1)
+	assert.Equal(t, "Org1MSP", endorsers[0].Mspid)
+
+	// The receipt can be verified after it was stored
+	data, err := json.Marshal(receipt)
+	require.NoError(t, err)
+	stored := &Receipt{}
+	require.NoError(t, json.Unmarshal(data, stored))
+	assert.Equal(t, receipt, stored)
+
+	membership := fcmocks.NewMockMembership()
+	assert.NoError(t, stored.Verify(membership))
+
+	membership.VerifyErr = errors.New("invalid signature")
+	assert.Error(t, stored.Verify(membership))
+
+	membership = fcmocks.NewMockMembership()
+	membership.ValidateErr = errors.New("invalid identity")
+	assert.Error(t, stored.Verify(membership))
+
+	stored.Endorsements = nil
+	assert.Error(t, stored.Verify(fcmocks.NewMockMembership()), "expecting error for receipt without endorsements")
+}
+
+func TestReceiptErrors(t *testing.T) {
+	txStatus := &fab.TxStatusEvent{TxValidationCode: pb.TxValidationCode_VALID}
+
+	_, err := newReceipt(newTestProposal(t, "mychannel"), nil, txStatus)
+	assert.Error(t, err, "expecting error without responses")
+
+	responses := []*fab.TransactionProposalResponse{{Endorser: "peer1", ProposalResponse: &pb.ProposalResponse{}}}
+	_, err = newReceipt(newTestProposal(t, "mychannel"), responses, txStatus)
+	assert.Error(t, err, "expecting error for response without endorsement")
+}
+
+func newTestProposal(t *testing.T, channelID string) *fab.TransactionProposal {
+	channelHeader, err := proto.Marshal(&common.ChannelHeader{ChannelId: channelID, TxId: "txid"})
+	require.NoError(t, err)
+	header, err := proto.Marshal(&common.Header{ChannelHeader: channelHeader})
+	require.NoError(t, err)
+	return &fab.TransactionProposal{TxnID: "txid", Proposal: &pb.Proposal{Header: header}}
+}
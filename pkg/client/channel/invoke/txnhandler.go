@@ -10,7 +10,9 @@ import (
 	"bytes"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/pkg/errors"
 
 	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
@@ -21,6 +23,9 @@ import (
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
+var logger = logging.NewLogger("fabsdk/client")
+var tracer = tracing.NewTracer("fabsdk/client")
+
 //EndorsementHandler for handling endorse transactions
 type EndorsementHandler struct {
 	next Handler
@@ -35,7 +40,12 @@ func (e *EndorsementHandler) Handle(requestContext *RequestContext, clientContex
 	}
 
 	// Endorse Tx
+	_, span := tracer.Start(requestContext.Ctx, "endorse", tracing.Int64("targets", int64(len(requestContext.Opts.Targets))))
 	transactionProposalResponses, proposal, err := createAndSendTransactionProposal(clientContext.Transactor, &requestContext.Request, peer.PeersToTxnProcessors(requestContext.Opts.Targets))
+	if proposal != nil {
+		span.SetAttributes(tracing.String("txID", string(proposal.TxnID)))
+	}
+	tracing.End(span, err)
 
 	requestContext.Response.Proposal = proposal
 	requestContext.Response.TransactionID = proposal.TxnID // TODO: still needed?
@@ -66,7 +76,7 @@ type ProposalProcessorHandler struct {
 func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
 	//Get proposal processor, if not supplied then use selection service to get available peers as endorser
 	if len(requestContext.Opts.Targets) == 0 {
-		var selectionOpts []options.Opt
+		selectionOpts := []options.Opt{selectopts.WithContext(requestContext.Ctx)}
 		if requestContext.SelectionFilter != nil {
 			selectionOpts = append(selectionOpts, selectopts.WithPeerFilter(requestContext.SelectionFilter))
 		}
@@ -143,24 +153,37 @@ func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *
 	}
 	defer clientContext.EventService.Unregister(reg)
 
+	_, span := tracer.Start(requestContext.Ctx, "order", tracing.String("txID", string(txnID)))
 	_, err = createAndSendTransaction(clientContext.Transactor, requestContext.Response.Proposal, requestContext.Response.Responses)
+	tracing.End(span, err)
 	if err != nil {
 		requestContext.Error = errors.Wrap(err, "CreateAndSendTransaction failed")
 		return
 	}
 
+	_, span = tracer.Start(requestContext.Ctx, "commit", tracing.String("txID", string(txnID)))
 	select {
 	case txStatus := <-statusNotifier:
 		requestContext.Response.TxValidationCode = txStatus.TxValidationCode
+		span.SetAttributes(tracing.String("validationCode", txStatus.TxValidationCode.String()))
+
+		receipt, err := newReceipt(requestContext.Response.Proposal, requestContext.Response.Responses, txStatus)
+		if err != nil {
+			logger.Warnf("Creating receipt of transaction [%s] failed: %s", txnID, err)
+		}
+		requestContext.Response.Receipt = receipt
 
 		if txStatus.TxValidationCode != pb.TxValidationCode_VALID {
 			requestContext.Error = status.New(status.EventServerStatus, int32(txStatus.TxValidationCode), "received invalid transaction", nil)
+			tracing.End(span, requestContext.Error)
 			return
 		}
 	case <-requestContext.Ctx.Done():
 		requestContext.Error = errors.New("Execute didn't receive block event")
+		tracing.End(span, requestContext.Error)
 		return
 	}
+	span.End()
 
 	//Delegate to next step if any
 	if c.next != nil {
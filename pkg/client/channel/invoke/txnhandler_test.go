@@ -73,7 +73,7 @@ func TestExecuteTxHandlerSuccess(t *testing.T) {
 	go func() {
 		select {
 		case txStatusReg := <-mockEventService.TxStatusRegCh:
-			txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID}
+			txStatusReg.Eventch <- &fab.TxStatusEvent{TxID: txStatusReg.TxID, TxValidationCode: pb.TxValidationCode_VALID, BlockNumber: 10}
 		case <-time.After(requestContext.Opts.Timeouts[fab.Execute]):
 			panic("Execute handler : time out not expected")
 		}
@@ -84,6 +84,15 @@ func TestExecuteTxHandlerSuccess(t *testing.T) {
 	//Perform action through handler
 	executeHandler.Handle(requestContext, clientContext)
 	assert.Nil(t, requestContext.Error)
+
+	receipt := requestContext.Response.Receipt
+	if assert.NotNil(t, receipt) {
+		assert.Equal(t, requestContext.Response.TransactionID, receipt.TxID)
+		assert.Equal(t, uint64(10), receipt.BlockNumber)
+		assert.Equal(t, pb.TxValidationCode_VALID, receipt.TxValidationCode)
+		assert.Len(t, receipt.Endorsements, 2)
+		assert.NoError(t, receipt.Verify(fcmocks.NewMockMembership()))
+	}
 }
 
 func TestQueryHandlerErrors(t *testing.T) {
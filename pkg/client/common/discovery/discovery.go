@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// GetPeers returns the peers of the given discovery service. If the service implements
+// fab.ContextDiscoveryService then the deadline and cancellation of the context are passed
+// on to the service, otherwise the context is only checked before the peers are retrieved.
+func GetPeers(ctx reqContext.Context, service fab.DiscoveryService) ([]fab.Peer, error) {
+	if ctx == nil {
+		return service.GetPeers()
+	}
+	if cs, ok := service.(fab.ContextDiscoveryService); ok {
+		return cs.GetPeersContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, "unable to get peers")
+	}
+	return service.GetPeers()
+}
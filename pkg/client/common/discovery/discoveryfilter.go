@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package discovery
 
 import (
+	reqContext "context"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 )
 
@@ -31,6 +33,15 @@ func (fs *filterService) GetPeers() ([]fab.Peer, error) {
 	return targets, nil
 }
 
+// GetPeersContext is used to get peers within the deadline of the given context
+func (fs *filterService) GetPeersContext(ctx reqContext.Context) ([]fab.Peer, error) {
+	peers, err := GetPeers(ctx, fs.discoveryService)
+	if err != nil {
+		return nil, err
+	}
+	return filterTargets(peers, fs.targetFilter), nil
+}
+
 // filterTargets is helper method to filter peers
 func filterTargets(peers []fab.Peer, filter fab.TargetFilter) []fab.Peer {
 
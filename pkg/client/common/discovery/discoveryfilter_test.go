@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package discovery
 
 import (
+	reqContext "context"
 	"testing"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/staticdiscovery"
@@ -70,3 +71,23 @@ func TestDiscoveryFilter(t *testing.T) {
 	}
 
 }
+
+func TestGetPeersContext(t *testing.T) {
+	peer := mocks.NewMockPeer("p1", "localhost:7051")
+	discoveryService := NewDiscoveryFilterService(mocks.NewMockDiscoveryService(nil, []fab.Peer{peer}), &mockFilter{})
+
+	peers, err := GetPeers(reqContext.Background(), discoveryService)
+	if err != nil {
+		t.Fatalf("Failed to get peers from discovery service: %s", err)
+	}
+	if len(peers) != 1 {
+		t.Fatalf("Expecting 1, got %d peers", len(peers))
+	}
+
+	ctx, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+
+	if _, err := GetPeers(ctx, discoveryService); err == nil {
+		t.Fatalf("Expecting error for cancelled context")
+	}
+}
@@ -8,10 +8,11 @@ package dynamicdiscovery
 
 import (
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
-	reqContext "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
 	"github.com/pkg/errors"
 )
 
@@ -20,6 +21,7 @@ import (
 // are currently joined to the given channel.
 type channelService struct {
 	*service
+	orderersRef *lazyref.Reference
 }
 
 // newChannelService creates a Discovery Service to query the list of member peers on a given channel.
@@ -28,6 +30,12 @@ func newChannelService(options options) *channelService {
 
 	s := &channelService{}
 	s.service = newService(s.queryPeers, options)
+	s.orderersRef = lazyref.New(
+		func() (interface{}, error) {
+			return s.queryOrderers()
+		},
+		lazyref.WithRefreshInterval(lazyref.InitOnFirstAccess, options.refreshInterval),
+	)
 	return s
 }
 
@@ -36,12 +44,32 @@ func (s *channelService) Initialize(ctx contextAPI.Channel) error {
 	return s.service.Initialize(ctx)
 }
 
+// Close stops the background refresh of peers and orderers
+func (s *channelService) Close() {
+	s.service.Close()
+	s.orderersRef.Close()
+}
+
+// GetOrderers returns the configuration of the channel's orderers as returned by the Discovery
+// service. The TLS CA certificate of each orderer is resolved from the orderer organization's MSP.
+func (s *channelService) GetOrderers() ([]fab.OrdererConfig, error) {
+	refValue, err := s.orderersRef.Get()
+	if err != nil {
+		return nil, err
+	}
+	orderers, ok := refValue.([]fab.OrdererConfig)
+	if !ok {
+		return nil, errors.New("get orderersRef didn't return OrdererConfig type")
+	}
+	return orderers, nil
+}
+
 func (s *channelService) channelContext() contextAPI.Channel {
 	return s.context().(contextAPI.Channel)
 }
 
 func (s *channelService) queryPeers() ([]fab.Peer, error) {
-	logger.Debugf("Refreshing peers of channel [%s] from discovery service...", s.channelContext().ChannelID())
+	logger.With(logging.Channel(s.channelContext().ChannelID())).Debug("Refreshing peers of channel from discovery service...")
 
 	channelContext := s.channelContext()
 	if channelContext == nil {
@@ -56,11 +84,8 @@ func (s *channelService) queryPeers() ([]fab.Peer, error) {
 		return nil, errors.Errorf("no peers configured for channel [%s]", channelContext.ChannelID())
 	}
 
-	reqCtx, cancel := reqContext.NewRequest(channelContext, reqContext.WithTimeout(s.responseTimeout))
-	defer cancel()
-
 	req := discclient.NewRequest().OfChannel(channelContext.ChannelID()).AddPeersQuery()
-	responses, err := s.discoveryClient().Send(reqCtx, req, targets...)
+	responses, err := s.send(channelContext, req, targets...)
 	if err != nil {
 		if len(responses) == 0 {
 			return nil, errors.Wrapf(err, "error calling discover service send")
@@ -70,6 +95,42 @@ func (s *channelService) queryPeers() ([]fab.Peer, error) {
 	return s.evaluate(channelContext, responses)
 }
 
+func (s *channelService) queryOrderers() ([]fab.OrdererConfig, error) {
+	channelContext := s.channelContext()
+	if channelContext == nil {
+		return nil, errors.Errorf("the service has not been initialized")
+	}
+
+	logger.With(logging.Channel(channelContext.ChannelID())).Debug("Refreshing orderers of channel from discovery service...")
+
+	targets, err := s.getTargets(channelContext)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, errors.Errorf("no peers configured for channel [%s]", channelContext.ChannelID())
+	}
+
+	req := discclient.NewRequest().OfChannel(channelContext.ChannelID()).AddConfigQuery()
+	responses, err := s.send(channelContext, req, targets...)
+	if err != nil {
+		if len(responses) == 0 {
+			return nil, errors.Wrapf(err, "error calling discover service send")
+		}
+		logger.Warnf("Received %d response(s) and one or more errors from discovery client: %s", len(responses), err)
+	}
+
+	for _, response := range responses {
+		result, err := response.ForChannel(channelContext.ChannelID()).Config()
+		if err != nil {
+			logger.Warnf("Error getting config from discovery response of target [%s]: %s", response.Target(), err)
+			continue
+		}
+		return fabdiscovery.NewChannelConfig(result).OrdererConfigs(), nil
+	}
+	return nil, errors.New("no successful config response received from any peer")
+}
+
 func (s *channelService) getTargets(ctx contextAPI.Channel) ([]fab.PeerConfig, error) {
 	// TODO: The number of peers to query should be retrieved from the channel policy.
 	// This will done in a future patch.
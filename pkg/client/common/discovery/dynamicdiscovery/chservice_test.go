@@ -7,15 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package dynamicdiscovery
 
 import (
+	reqcontext "context"
 	"testing"
 	"time"
 
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
 	dyndiscmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/dynamicdiscovery/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	pfab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
 	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -96,6 +105,10 @@ func TestDiscoveryService(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(peers))
 
+	peerState, ok := peers[0].(pfab.PeerState)
+	assert.True(t, ok, "discovered peer should provide peer state")
+	assert.Equal(t, uint64(5), peerState.BlockHeight())
+
 	discClient.SetResponses(
 		&dyndiscmocks.MockDiscoverEndpointResponse{
 			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
@@ -119,3 +132,261 @@ func TestDiscoveryService(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(peers))
 }
+
+func TestDiscoveryServiceOrderers(t *testing.T) {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("test", mspID1))
+	config := &config{
+		EndpointConfig: mocks.NewMockEndpointConfig(),
+		peers: []pfab.ChannelPeer{
+			{
+				NetworkPeer: pfab.NetworkPeer{
+					PeerConfig: pfab.PeerConfig{
+						URL: peer1MSP1,
+					},
+					MSPID: mspID1,
+				},
+			},
+		},
+	}
+	ctx.SetEndpointConfig(config)
+
+	discClient := dyndiscmocks.NewMockDiscoveryClient()
+	discClient.SetResponses(
+		&dyndiscmocks.MockDiscoverEndpointResponse{
+			Config: &discovery.ConfigResult{
+				Msps: map[string]*msp.FabricMSPConfig{
+					"OrdererMSP": {
+						Name:         "OrdererMSP",
+						TlsRootCerts: [][]byte{[]byte("tlsrootcert")},
+					},
+				},
+				Orderers: map[string]*discovery.Endpoints{
+					"OrdererMSP": {
+						Endpoint: []*discovery.Endpoint{{Host: "orderer.example.com", Port: 7050}},
+					},
+				},
+			},
+		},
+	)
+
+	clientProvider = func(ctx contextAPI.Client) (discoveryClient, error) {
+		return discClient, nil
+	}
+
+	service := newChannelService(options{refreshInterval: time.Minute, responseTimeout: time.Second})
+	defer service.Close()
+
+	err := service.Initialize(mocks.NewMockChannelContext(ctx, ch))
+	assert.NoError(t, err)
+
+	orderers, err := service.GetOrderers()
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(orderers)) {
+		assert.Equal(t, "orderer.example.com:7050", orderers[0].URL)
+		assert.Equal(t, "tlsrootcert", orderers[0].TLSCACerts.Pem)
+		assert.Equal(t, "orderer.example.com", orderers[0].GRPCOptions["ssl-target-name-override"])
+	}
+}
+
+func TestDiscoveryServiceRetry(t *testing.T) {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("test", mspID1))
+	config := &config{
+		EndpointConfig: mocks.NewMockEndpointConfig(),
+		peers: []pfab.ChannelPeer{
+			{
+				NetworkPeer: pfab.NetworkPeer{
+					PeerConfig: pfab.PeerConfig{
+						URL: peer1MSP1,
+					},
+					MSPID: mspID1,
+				},
+			},
+		},
+	}
+	ctx.SetEndpointConfig(config)
+
+	discClient := dyndiscmocks.NewMockDiscoveryClient()
+	discClient.SetResponses(
+		&dyndiscmocks.MockDiscoverEndpointResponse{
+			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
+				{
+					MSPID:        mspID1,
+					Endpoint:     peer1MSP1,
+					LedgerHeight: 5,
+				},
+			},
+		},
+	)
+
+	flakyClient := &flakyDiscoveryClient{discoveryClient: discClient}
+	clientProvider = func(ctx contextAPI.Client) (discoveryClient, error) {
+		return flakyClient, nil
+	}
+
+	retryOpts := retry.Opts{
+		Attempts:       2,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		BackoffFactor:  2,
+		RetryableCodes: retry.DiscoveryRetryableCodes,
+	}
+
+	// Transient errors are retried
+	flakyClient.failures = 2
+	flakyClient.err = status.New(status.ClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+
+	service := newChannelService(options{refreshInterval: time.Minute, responseTimeout: time.Second, retryOpts: retryOpts})
+	defer service.Close()
+
+	err := service.Initialize(mocks.NewMockChannelContext(ctx, ch))
+	assert.NoError(t, err)
+
+	peers, err := service.GetPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(peers))
+	assert.Equal(t, 3, flakyClient.attempts)
+
+	// Access denied is not retried
+	flakyClient.attempts = 0
+	flakyClient.failures = 2
+	flakyClient.err = status.New(status.DiscoveryServerStatus, status.AccessDenied.ToInt32(), "access denied", nil)
+
+	service2 := newChannelService(options{refreshInterval: time.Minute, responseTimeout: time.Second, retryOpts: retryOpts})
+	defer service2.Close()
+
+	err = service2.Initialize(mocks.NewMockChannelContext(ctx, ch))
+	assert.NoError(t, err)
+
+	_, err = service2.GetPeers()
+	assert.Error(t, err)
+	assert.True(t, fabdiscovery.IsAccessDenied(err), "expecting access denied error")
+	assert.Equal(t, 1, flakyClient.attempts)
+}
+
+func TestDiscoveryServiceRetryMultipleTargets(t *testing.T) {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("test", mspID1))
+	config := &config{
+		EndpointConfig: mocks.NewMockEndpointConfig(),
+		peers: []pfab.ChannelPeer{
+			{
+				NetworkPeer: pfab.NetworkPeer{
+					PeerConfig: pfab.PeerConfig{
+						URL: peer1MSP1,
+					},
+					MSPID: mspID1,
+				},
+			},
+			{
+				NetworkPeer: pfab.NetworkPeer{
+					PeerConfig: pfab.PeerConfig{
+						URL: peer1MSP2,
+					},
+					MSPID: mspID2,
+				},
+			},
+		},
+	}
+	ctx.SetEndpointConfig(config)
+
+	discClient := dyndiscmocks.NewMockDiscoveryClient()
+	discClient.SetResponses(
+		&dyndiscmocks.MockDiscoverEndpointResponse{
+			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
+				{
+					MSPID:        mspID1,
+					Endpoint:     peer1MSP1,
+					LedgerHeight: 5,
+				},
+			},
+		},
+	)
+
+	flakyClient := &flakyDiscoveryClient{discoveryClient: discClient}
+	clientProvider = func(ctx contextAPI.Client) (discoveryClient, error) {
+		return flakyClient, nil
+	}
+
+	retryOpts := retry.Opts{
+		Attempts:       2,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		BackoffFactor:  2,
+		RetryableCodes: retry.DiscoveryRetryableCodes,
+	}
+
+	connectionFailed := status.New(status.ClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+	accessDenied := status.New(status.DiscoveryServerStatus, status.AccessDenied.ToInt32(), "access denied", nil)
+
+	// Both targets returned transient errors so the request is retried
+	flakyClient.failures = 2
+	flakyClient.err = multi.New(
+		errors.WithMessage(connectionFailed, "From target: "+peer1MSP1),
+		errors.WithMessage(connectionFailed, "From target: "+peer1MSP2),
+	)
+
+	service := newChannelService(options{refreshInterval: time.Minute, responseTimeout: time.Second, retryOpts: retryOpts})
+	defer service.Close()
+
+	err := service.Initialize(mocks.NewMockChannelContext(ctx, ch))
+	assert.NoError(t, err)
+
+	peers, err := service.GetPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(peers))
+	assert.Equal(t, 3, flakyClient.attempts)
+
+	// One of the targets denied access so the request is not retried
+	flakyClient.attempts = 0
+	flakyClient.failures = 2
+	flakyClient.err = multi.New(
+		errors.WithMessage(connectionFailed, "From target: "+peer1MSP1),
+		errors.WithMessage(accessDenied, "From target: "+peer1MSP2),
+	)
+
+	service2 := newChannelService(options{refreshInterval: time.Minute, responseTimeout: time.Second, retryOpts: retryOpts})
+	defer service2.Close()
+
+	err = service2.Initialize(mocks.NewMockChannelContext(ctx, ch))
+	assert.NoError(t, err)
+
+	_, err = service2.GetPeers()
+	assert.Error(t, err)
+	assert.True(t, fabdiscovery.IsAccessDenied(err), "expecting access denied error")
+	assert.Equal(t, 1, flakyClient.attempts)
+}
+
+type flakyDiscoveryClient struct {
+	discoveryClient
+	failures int
+	attempts int
+	err      error
+}
+
+func (c *flakyDiscoveryClient) Send(ctx reqcontext.Context, req *discclient.Request, targets ...pfab.PeerConfig) ([]fabdiscovery.Response, error) {
+	c.attempts++
+	if c.attempts <= c.failures {
+		return nil, c.err
+	}
+	return c.discoveryClient.Send(ctx, req, targets...)
+}
+
+func TestDiscoveryServiceGetPeersContext(t *testing.T) {
+	release := make(chan struct{})
+	s := newService(func() ([]pfab.Peer, error) {
+		<-release
+		return []pfab.Peer{mocks.NewMockPeer("p1", "localhost:7051")}, nil
+	}, options{refreshInterval: time.Minute})
+	defer s.Close()
+
+	ctx, cancel := reqcontext.WithTimeout(reqcontext.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := s.GetPeersContext(ctx)
+	assert.Error(t, err, "expecting error since the peers are not available before the deadline")
+
+	close(release)
+
+	peers, err := s.GetPeersContext(reqcontext.Background())
+	assert.NoError(t, err)
+	assert.Len(t, peers, 1)
+}
@@ -10,7 +10,6 @@ import (
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
 	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
-	reqContext "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/pkg/errors"
 )
 
@@ -51,11 +50,8 @@ func (s *LocalService) queryPeers() ([]fab.Peer, error) {
 		return nil, err
 	}
 
-	reqCtx, cancel := reqContext.NewRequest(ctx, reqContext.WithTimeout(s.responseTimeout))
-	defer cancel()
-
 	req := discclient.NewRequest().AddLocalPeersQuery()
-	responses, err := s.discoveryClient().Send(reqCtx, req, *target)
+	responses, err := s.send(ctx, req, *target)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error calling discover service send")
 	}
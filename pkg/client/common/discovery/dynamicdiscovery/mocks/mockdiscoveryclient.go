@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
 	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
+	"github.com/pkg/errors"
 )
 
 // MockDiscoveryClient implements a mock Discover service
@@ -28,6 +29,7 @@ type MockDiscoveryClient struct {
 type MockDiscoverEndpointResponse struct {
 	Target        string
 	PeerEndpoints []*discmocks.MockDiscoveryPeerEndpoint
+	Config        *discovery.ConfigResult
 	Error         error
 }
 
@@ -59,7 +61,7 @@ func (m *MockDiscoveryClient) SetResponses(responses ...*MockDiscoverEndpointRes
 			peers = append(peers, peer)
 		}
 		m.resp = append(m.resp, &mockDiscoverResponse{
-			Response: &response{peers: peers}, target: resp.Target, err: resp.Error,
+			Response: &response{peers: peers, config: resp.Config}, target: resp.Target, err: resp.Error,
 		})
 	}
 }
@@ -85,12 +87,14 @@ func (r *mockDiscoverResponse) Error() error {
 }
 
 type response struct {
-	peers []*discclient.Peer
+	peers  []*discclient.Peer
+	config *discovery.ConfigResult
 }
 
 func (r *response) ForChannel(string) discclient.ChannelResponse {
 	return &channelResponse{
-		peers: r.peers,
+		peers:  r.peers,
+		config: r.config,
 	}
 }
 
@@ -101,12 +105,16 @@ func (r *response) ForLocal() discclient.LocalResponse {
 }
 
 type channelResponse struct {
-	peers []*discclient.Peer
+	peers  []*discclient.Peer
+	config *discovery.ConfigResult
 }
 
 // Config returns a response for a config query, or error if something went wrong
 func (cr *channelResponse) Config() (*discovery.ConfigResult, error) {
-	panic("not implemented")
+	if cr.config == nil {
+		return nil, errors.New("no config")
+	}
+	return cr.config, nil
 }
 
 // Peers returns a response for a peer membership query, or error if something went wrong
@@ -9,6 +9,7 @@ package dynamicdiscovery
 import (
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
@@ -42,9 +43,18 @@ func WithResponseTimeout(value time.Duration) Opt {
 	}
 }
 
+// WithRetryOpts sets the retry options for Discovery service queries. Only errors
+// that are classified as transient by the retry options are retried.
+func WithRetryOpts(value retry.Opts) Opt {
+	return func(o *options) {
+		o.retryOpts = value
+	}
+}
+
 type options struct {
 	refreshInterval time.Duration
 	responseTimeout time.Duration
+	retryOpts       retry.Opts
 }
 
 // New creates a new dynamic discovery provider
@@ -60,6 +70,9 @@ func New(config fab.EndpointConfig, opts ...Opt) *Provider {
 	if options.responseTimeout == 0 {
 		options.responseTimeout = config.Timeout(fab.DiscoveryResponse)
 	}
+	if options.retryOpts.RetryableCodes == nil {
+		options.retryOpts = retry.DefaultDiscoveryOpts
+	}
 
 	return &Provider{
 		cache: lazycache.New("Dynamic_Discovery_Service_Cache", func(key lazycache.Key) (interface{}, error) {
@@ -8,12 +8,18 @@ package dynamicdiscovery
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
 	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/opevents"
 	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	reqContext "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
 	"github.com/pkg/errors"
@@ -33,6 +39,7 @@ var clientProvider = func(ctx contextAPI.Client) (discoveryClient, error) {
 // are currently joined to the given channel.
 type service struct {
 	responseTimeout time.Duration
+	retryOpts       retry.Opts
 	lock            sync.RWMutex
 	ctx             contextAPI.Client
 	discClient      discoveryClient
@@ -43,15 +50,19 @@ type queryPeers func() ([]fab.Peer, error)
 
 func newService(query queryPeers, options options) *service {
 	logger.Debugf("Creating new dynamic discovery service with cache refresh interval %s", options.refreshInterval)
-	return &service{
+	s := &service{
 		responseTimeout: options.responseTimeout,
-		peersRef: lazyref.New(
-			func() (interface{}, error) {
-				return query()
-			},
-			lazyref.WithRefreshInterval(lazyref.InitOnFirstAccess, options.refreshInterval),
-		),
+		retryOpts:       options.retryOpts,
 	}
+	s.peersRef = lazyref.New(
+		func() (interface{}, error) {
+			peers, err := query()
+			s.publishRefreshed(peers, err)
+			return peers, err
+		},
+		lazyref.WithRefreshInterval(lazyref.InitOnFirstAccess, options.refreshInterval),
+	)
+	return s
 }
 
 // Initialize initializes the service with local context
@@ -95,18 +106,111 @@ func (s *service) GetPeers() ([]fab.Peer, error) {
 	return peers, nil
 }
 
+// GetPeersContext returns the available peers. If the peers haven't been retrieved yet (or are
+// being refreshed) then an error is returned if the context is done before they are available.
+func (s *service) GetPeersContext(ctx context.Context) ([]fab.Peer, error) {
+	type result struct {
+		peers []fab.Peer
+		err   error
+	}
+
+	resultch := make(chan result, 1)
+	go func() {
+		peers, err := s.GetPeers()
+		resultch <- result{peers: peers, err: err}
+	}()
+
+	select {
+	case r := <-resultch:
+		return r.peers, r.err
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "timed out waiting for discovered peers")
+	}
+}
+
 func (s *service) context() contextAPI.Client {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 	return s.ctx
 }
 
+// publishRefreshed publishes the outcome of a refresh of the peers to the operational event subscribers
+func (s *service) publishRefreshed(peers []fab.Peer, err error) {
+	if !opevents.Enabled() {
+		return
+	}
+
+	var channelID string
+	if ctx, ok := s.context().(contextAPI.Channel); ok {
+		channelID = ctx.ChannelID()
+	}
+	opevents.Publish(&opevents.Event{
+		Type:       opevents.DiscoveryRefreshed,
+		Source:     channelID,
+		Attributes: map[string]string{"peers": strconv.Itoa(len(peers))},
+		Err:        err,
+	})
+}
+
 func (s *service) discoveryClient() discoveryClient {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 	return s.discClient
 }
 
+// send sends the given request to the given targets and retries on transient errors. A partial
+// failure (i.e. one or more responses along with an error) is not retried. If all of the targets
+// failed then the request is retried only if every target returned a transient error.
+func (s *service) send(ctx contextAPI.Client, req *discclient.Request, targets ...fab.PeerConfig) ([]fabdiscovery.Response, error) {
+	var responses []fabdiscovery.Response
+	var sendErr error
+
+	_, err := retry.NewInvoker(retry.New(s.retryOpts)).Invoke(
+		func() (interface{}, error) {
+			reqCtx, cancel := reqContext.NewRequest(ctx, reqContext.WithTimeout(s.responseTimeout))
+			defer cancel()
+
+			responses, sendErr = s.discoveryClient().Send(reqCtx, req, targets...)
+			if sendErr == nil || len(responses) > 0 {
+				return nil, nil
+			}
+			if errs, ok := sendErr.(multi.Errors); ok && !s.allTransient(errs) {
+				return nil, nil
+			}
+			return nil, sendErr
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return responses, sendErr
+}
+
+// allTransient returns true if all of the given errors are retryable
+func (s *service) allTransient(errs multi.Errors) bool {
+	codes := s.retryOpts.RetryableCodes
+	if len(codes) == 0 {
+		codes = retry.DefaultRetryableCodes
+	}
+
+	for _, err := range errs {
+		st, ok := status.FromError(err)
+		if !ok || !containsCode(codes[st.Group], status.Code(st.Code)) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsCode(codes []status.Code, code status.Code) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
 func asPeers(ctx contextAPI.Client, endpoints []*discclient.Peer) []fab.Peer {
 	var peers []fab.Peer
 	for _, endpoint := range endpoints {
@@ -125,8 +229,26 @@ func asPeers(ctx contextAPI.Client, endpoints []*discclient.Peer) []fab.Peer {
 			logger.Warnf("Unable to create peer config for [%s]: %s", url, err)
 			continue
 		}
-		peers = append(peers, peer)
+		peers = append(peers, &peerEndpoint{Peer: peer, blockHeight: ledgerHeight(endpoint)})
 	}
 
 	return peers
 }
+
+// peerEndpoint extends the peer with the state returned by discovery
+type peerEndpoint struct {
+	fab.Peer
+	blockHeight uint64
+}
+
+// BlockHeight returns the block height of the peer's ledger at the time it was discovered
+func (p *peerEndpoint) BlockHeight() uint64 {
+	return p.blockHeight
+}
+
+func ledgerHeight(endpoint *discclient.Peer) uint64 {
+	if endpoint.StateInfoMessage == nil || endpoint.StateInfoMessage.GossipMessage == nil {
+		return 0
+	}
+	return endpoint.StateInfoMessage.GetStateInfo().GetProperties().GetLedgerHeight()
+}
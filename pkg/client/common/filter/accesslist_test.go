@@ -0,0 +1,61 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerAccessListBlock(t *testing.T) {
+	peer1 := newMockPeer("p1", "grpcs://localhost:7051", "Org1MSP")
+	peer2 := newMockPeer("p2", "grpcs://localhost:8051", "Org2MSP")
+	peers := []fab.Peer{peer1, peer2}
+
+	l := NewPeerAccessList()
+	assert.Equal(t, peers, Filter(peers, l), "expecting all peers to be accepted by default")
+
+	l.Block("localhost:7051", 0)
+	assert.Equal(t, []fab.Peer{peer2}, Filter(peers, l))
+	assert.Equal(t, []string{"localhost:7051"}, l.Blocked())
+
+	l.Unblock("grpcs://localhost:7051")
+	assert.Equal(t, peers, Filter(peers, l))
+	assert.Empty(t, l.Blocked())
+}
+
+func TestPeerAccessListBlockExpiry(t *testing.T) {
+	peer1 := newMockPeer("p1", "localhost:7051", "Org1MSP")
+
+	l := NewPeerAccessList()
+	l.Block(peer1.URL(), 50*time.Millisecond)
+	assert.False(t, l.Accept(peer1))
+
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, l.Accept(peer1), "expecting peer to be accepted after block expired")
+	assert.Empty(t, l.Blocked())
+}
+
+func TestPeerAccessListAllowed(t *testing.T) {
+	peer1 := newMockPeer("p1", "localhost:7051", "Org1MSP")
+	peer2 := newMockPeer("p2", "localhost:8051", "Org2MSP")
+	peers := []fab.Peer{peer1, peer2}
+
+	l := NewPeerAccessList()
+	l.SetAllowed("grpcs://localhost:8051")
+	assert.Equal(t, []fab.Peer{peer2}, Filter(peers, l))
+
+	l.Block(peer2.URL(), 0)
+	assert.Empty(t, Filter(peers, l), "expecting blocklist to take precedence over allowlist")
+
+	l.Unblock(peer2.URL())
+	l.SetAllowed()
+	assert.Equal(t, peers, Filter(peers, l))
+}
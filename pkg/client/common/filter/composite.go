@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// Func is an adapter that allows an ordinary function to be used as a target filter
+type Func func(peer fab.Peer) bool
+
+// Accept returns the result of invoking the function
+func (f Func) Accept(peer fab.Peer) bool {
+	return f(peer)
+}
+
+// All returns a filter that accepts a peer only if all of the given filters accept it
+func All(filters ...fab.TargetFilter) fab.TargetFilter {
+	return Func(func(peer fab.Peer) bool {
+		for _, f := range filters {
+			if !f.Accept(peer) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Any returns a filter that accepts a peer if any of the given filters accepts it
+func Any(filters ...fab.TargetFilter) fab.TargetFilter {
+	return Func(func(peer fab.Peer) bool {
+		for _, f := range filters {
+			if f.Accept(peer) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not returns a filter that accepts a peer only if the given filter rejects it
+func Not(filter fab.TargetFilter) fab.TargetFilter {
+	return Func(func(peer fab.Peer) bool {
+		return !filter.Accept(peer)
+	})
+}
+
+// Filter returns the peers that are accepted by the given filter
+func Filter(peers []fab.Peer, filter fab.TargetFilter) []fab.Peer {
+	if filter == nil {
+		return peers
+	}
+
+	var filtered []fab.Peer
+	for _, peer := range peers {
+		if filter.Accept(peer) {
+			filtered = append(filtered, peer)
+		}
+	}
+	return filtered
+}
+
+// Prioritized applies the given filters in order of priority and returns the peers accepted
+// by the first filter that accepts at least one peer. Lower priority filters therefore act as
+// fallbacks for higher priority filters. An empty slice is returned if no filter accepts any peer.
+func Prioritized(peers []fab.Peer, filters ...fab.TargetFilter) []fab.Peer {
+	for _, f := range filters {
+		if filtered := Filter(peers, f); len(filtered) > 0 {
+			return filtered
+		}
+	}
+	return []fab.Peer{}
+}
+
+// Sorter sorts peers in order of preference
+type Sorter interface {
+	// Sort returns the given peers sorted in order of preference. The given slice is not modified.
+	Sort(peers []fab.Peer) []fab.Peer
+}
+
+// Less is an adapter that allows a comparison function to be used as a Sorter. The peers for
+// which less reports true are sorted first; the order of equal peers is preserved.
+type Less func(p1, p2 fab.Peer) bool
+
+// Sort returns the given peers sorted with the comparison function
+func (l Less) Sort(peers []fab.Peer) []fab.Peer {
+	sorted := make([]fab.Peer, len(peers))
+	copy(sorted, peers)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return l(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// Chain returns a sorter that sorts peers with the first of the given sorters and breaks ties
+// with the following sorters, in order. The sorters must preserve the order of equal peers
+// (as Less and BlockHeightSorter do).
+func Chain(sorters ...Sorter) Sorter {
+	return chainedSorter(sorters)
+}
+
+type chainedSorter []Sorter
+
+// Sort applies the sorters from the lowest to the highest priority so that each
+// (stable) sort keeps the order established by the lower priority sorters among ties
+func (c chainedSorter) Sort(peers []fab.Peer) []fab.Peer {
+	sorted := make([]fab.Peer, len(peers))
+	copy(sorted, peers)
+
+	for i := len(c) - 1; i >= 0; i-- {
+		sorted = c[i].Sort(sorted)
+	}
+	return sorted
+}
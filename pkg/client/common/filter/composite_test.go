@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompositeFilters(t *testing.T) {
+	peer1 := newMockPeer("p1", "localhost:7051", "Org1MSP")
+	peer2 := newMockPeer("p2", "localhost:8051", "Org2MSP")
+	peer3 := newMockPeer("p3", "localhost:9051", "Org3MSP")
+	peers := []fab.Peer{peer1, peer2, peer3}
+
+	org1 := NewMSPFilter("Org1MSP")
+	org1or2 := NewMSPFilter("Org1MSP", "Org2MSP")
+
+	assert.Equal(t, []fab.Peer{peer1}, Filter(peers, All(org1, org1or2)))
+	assert.Equal(t, []fab.Peer{peer1, peer2}, Filter(peers, Any(org1, org1or2)))
+	assert.Equal(t, []fab.Peer{peer3}, Filter(peers, Not(org1or2)))
+	assert.Equal(t, peers, Filter(peers, All()), "empty All should accept all peers")
+	assert.Empty(t, Filter(peers, Any()), "empty Any should reject all peers")
+	assert.Equal(t, peers, Filter(peers, nil))
+}
+
+func TestPrioritized(t *testing.T) {
+	peer1 := newMockPeer("p1", "localhost:7051", "Org1MSP")
+	peer2 := newMockPeer("p2", "localhost:8051", "Org2MSP")
+	peers := []fab.Peer{peer1, peer2}
+
+	result := Prioritized(peers, NewMSPFilter("Org2MSP"), NewMSPFilter("Org1MSP"))
+	assert.Equal(t, []fab.Peer{peer2}, result, "expecting peers of the highest priority filter")
+
+	result = Prioritized(peers, NewMSPFilter("Org3MSP"), NewMSPFilter("Org1MSP"))
+	assert.Equal(t, []fab.Peer{peer1}, result, "expecting fallback to lower priority filter")
+
+	result = Prioritized(peers, NewMSPFilter("Org3MSP"))
+	assert.Empty(t, result)
+}
+
+func TestChainedSorters(t *testing.T) {
+	peer1 := &mockStatePeer{MockPeer: newMockPeer("p1", "localhost:7051", "Org2MSP"), height: 95}
+	peer2 := &mockStatePeer{MockPeer: newMockPeer("p2", "localhost:8051", "Org2MSP"), height: 100}
+	peer3 := &mockStatePeer{MockPeer: newMockPeer("p3", "localhost:9051", "Org1MSP"), height: 100}
+	peer4 := newMockPeer("p4", "localhost:10051", "Org1MSP")
+	peers := []fab.Peer{peer1, peer2, peer3, peer4}
+
+	byMSPID := Less(func(p1, p2 fab.Peer) bool {
+		return p1.MSPID() < p2.MSPID()
+	})
+	assert.Equal(t, []fab.Peer{peer3, peer4, peer1, peer2}, byMSPID.Sort(peers), "expecting the order of equal peers to be preserved")
+
+	sorted := Chain(NewBlockHeightSorter(), byMSPID).Sort(peers)
+	assert.Equal(t, []fab.Peer{peer3, peer2, peer1, peer4}, sorted, "expecting ties in block height to be broken by MSP ID")
+
+	sorted = Chain(byMSPID, NewBlockHeightSorter()).Sort(peers)
+	assert.Equal(t, []fab.Peer{peer3, peer4, peer2, peer1}, sorted, "expecting ties in MSP ID to be broken by block height")
+
+	assert.Equal(t, peers, Chain().Sort(peers))
+	assert.Equal(t, []fab.Peer{peer1, peer2, peer3, peer4}, peers, "expecting the given peers not to be modified")
+}
+
+type mockStatePeer struct {
+	*mocks.MockPeer
+	height uint64
+}
+
+func (p *mockStatePeer) BlockHeight() uint64 {
+	return p.height
+}
+
+func newMockPeer(name, url, mspID string) *mocks.MockPeer {
+	peer := mocks.NewMockPeer(name, url)
+	peer.SetMSPID(mspID)
+	return peer
+}
@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// HealthFilter excludes peers whose endpoints are known to be down
+type HealthFilter struct {
+	health fab.EndpointHealth
+}
+
+// NewHealthFilter creates a new filter that is based on the given endpoint health.
+// If endpoint health is not available then all peers are accepted.
+func NewHealthFilter(health fab.EndpointHealth) *HealthFilter {
+	return &HealthFilter{health: health}
+}
+
+// Accept returns false if the peer's circuit breaker is open
+func (f *HealthFilter) Accept(peer fab.Peer) bool {
+	if f.health == nil {
+		return true
+	}
+	return f.health.Healthy(peer.URL())
+}
@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+type mockEndpointHealth struct {
+	down map[string]bool
+}
+
+func (h *mockEndpointHealth) Healthy(url string) bool {
+	return !h.down[url]
+}
+
+func TestHealthFilter(t *testing.T) {
+	peer1 := mocks.NewMockPeer("p1", "localhost:7051")
+	peer2 := mocks.NewMockPeer("p2", "localhost:8051")
+
+	f := NewHealthFilter(&mockEndpointHealth{down: map[string]bool{peer2.URL(): true}})
+	if !f.Accept(peer1) {
+		t.Fatalf("Expecting healthy peer to be accepted")
+	}
+	if f.Accept(peer2) {
+		t.Fatalf("Expecting unhealthy peer to be rejected")
+	}
+
+	f = NewHealthFilter(nil)
+	if !f.Accept(peer2) {
+		t.Fatalf("Expecting all peers to be accepted when endpoint health is not available")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+// NewMSPFilter returns a filter that accepts peers belonging to any of the given MSPs
+func NewMSPFilter(mspIDs ...string) fab.TargetFilter {
+	return Func(func(peer fab.Peer) bool {
+		for _, mspID := range mspIDs {
+			if peer.MSPID() == mspID {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// NewExcludeLabelFilter returns a filter that rejects peers whose peer config has the given label
+// set to the given value. Peers without config are accepted.
+func NewExcludeLabelFilter(config fab.EndpointConfig, key, value string) fab.TargetFilter {
+	return Func(func(peer fab.Peer) bool {
+		peerConfig, err := config.PeerConfig(peer.URL())
+		if err != nil || peerConfig == nil {
+			return true
+		}
+		labelValue, ok := peerConfig.Labels[key]
+		return !ok || labelValue != value
+	})
+}
+
+// NewMinBlockHeightFilter returns a filter that accepts peers whose block height is at least the
+// given height. Peers that don't provide state information (see fab.PeerState) are accepted.
+func NewMinBlockHeightFilter(minHeight uint64) fab.TargetFilter {
+	return Func(func(peer fab.Peer) bool {
+		height, ok := blockHeight(peer)
+		return !ok || height >= minHeight
+	})
+}
+
+// NewBlockHeightLagFilter returns a filter that accepts peers whose block height is within
+// maxLag blocks of the highest block height of the given peers. Peers that don't provide
+// state information (see fab.PeerState) are accepted.
+func NewBlockHeightLagFilter(peers []fab.Peer, maxLag uint64) fab.TargetFilter {
+	var maxHeight uint64
+	for _, peer := range peers {
+		if height, ok := blockHeight(peer); ok && height > maxHeight {
+			maxHeight = height
+		}
+	}
+
+	var minHeight uint64
+	if maxHeight > maxLag {
+		minHeight = maxHeight - maxLag
+	}
+	return NewMinBlockHeightFilter(minHeight)
+}
+
+// BlockHeightSorter sorts peers by block height (highest first). Peers that
+// don't provide state information are sorted last.
+type BlockHeightSorter struct {
+}
+
+// NewBlockHeightSorter returns a new block height sorter
+func NewBlockHeightSorter() *BlockHeightSorter {
+	return &BlockHeightSorter{}
+}
+
+// Sort returns the given peers sorted by block height
+func (s *BlockHeightSorter) Sort(peers []fab.Peer) []fab.Peer {
+	sorted := make([]fab.Peer, len(peers))
+	copy(sorted, peers)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		hi, oki := blockHeight(sorted[i])
+		hj, okj := blockHeight(sorted[j])
+		if oki != okj {
+			return oki
+		}
+		return hi > hj
+	})
+	return sorted
+}
+
+func blockHeight(peer fab.Peer) (uint64, bool) {
+	state, ok := peer.(fab.PeerState)
+	if !ok {
+		return 0, false
+	}
+	return state.BlockHeight(), true
+}
@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package filter
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockHeightFilters(t *testing.T) {
+	peer1 := &mockStatePeer{MockPeer: newMockPeer("p1", "localhost:7051", "Org1MSP"), height: 100}
+	peer2 := &mockStatePeer{MockPeer: newMockPeer("p2", "localhost:8051", "Org1MSP"), height: 95}
+	peer3 := newMockPeer("p3", "localhost:9051", "Org1MSP")
+	peers := []fab.Peer{peer1, peer2, peer3}
+
+	assert.Equal(t, []fab.Peer{peer1, peer3}, Filter(peers, NewMinBlockHeightFilter(96)))
+	assert.Equal(t, []fab.Peer{peer1, peer3}, Filter(peers, NewBlockHeightLagFilter(peers, 2)))
+	assert.Equal(t, peers, Filter(peers, NewBlockHeightLagFilter(peers, 5)))
+	assert.Equal(t, peers, Filter(peers, NewBlockHeightLagFilter(peers, 200)))
+
+	sorted := NewBlockHeightSorter().Sort([]fab.Peer{peer3, peer2, peer1})
+	assert.Equal(t, []fab.Peer{peer1, peer2, peer3}, sorted)
+}
+
+func TestExcludeLabelFilter(t *testing.T) {
+	peer1 := newMockPeer("p1", "localhost:7051", "Org1MSP")
+	peer2 := newMockPeer("p2", "localhost:8051", "Org1MSP")
+
+	config := &mockLabelConfig{
+		EndpointConfig: mocks.NewMockEndpointConfig(),
+		labels: map[string]map[string]string{
+			peer1.URL(): {"maintenance": "true"},
+		},
+	}
+
+	f := NewExcludeLabelFilter(config, "maintenance", "true")
+	assert.False(t, f.Accept(peer1), "expecting labelled peer to be rejected")
+	assert.True(t, f.Accept(peer2), "expecting unlabelled peer to be accepted")
+}
+
+type mockLabelConfig struct {
+	fab.EndpointConfig
+	labels map[string]map[string]string
+}
+
+func (c *mockLabelConfig) PeerConfig(nameOrURL string) (*fab.PeerConfig, error) {
+	return &fab.PeerConfig{URL: nameOrURL, Labels: c.labels[nameOrURL]}, nil
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/pkg/errors"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
@@ -41,6 +43,9 @@ type SelectionProvider struct {
 	lbp          pgresolver.LoadBalancePolicy
 	providers    api.Providers
 	cacheTimeout time.Duration
+	region       string
+	zone         string
+	accessList   *filter.PeerAccessList
 	refs         []*selectionService
 	refLock      sync.RWMutex
 }
@@ -62,6 +67,16 @@ func WithCacheTimeout(timeout time.Duration) Opt {
 	}
 }
 
+// WithLocality sets the region and (optionally) the zone of the client. Endorsers whose peer config
+// is labelled with the same region (and zone) are preferred. Endorsers in other regions are only
+// selected if the endorsement policy cannot be satisfied by the peers in the client's region.
+func WithLocality(region, zone string) Opt {
+	return func(p *SelectionProvider) {
+		p.region = region
+		p.zone = zone
+	}
+}
+
 // New returns dynamic selection provider
 func New(config fab.EndpointConfig, users []ChannelUser, opts ...Opt) (*SelectionProvider, error) {
 	p := &SelectionProvider{
@@ -69,21 +84,33 @@ func New(config fab.EndpointConfig, users []ChannelUser, opts ...Opt) (*Selectio
 		users:        users,
 		lbp:          pgresolver.NewRandomLBP(),
 		cacheTimeout: defaultCacheTimeout,
+		accessList:   filter.NewPeerAccessList(),
 	}
 
 	for _, opt := range opts {
 		opt(p)
 	}
 
+	if p.region != "" {
+		p.lbp = pgresolver.NewPreferredLBP(newLocalityScorer(config, p.region, p.zone), p.lbp)
+	}
+
 	return p, nil
 }
 
+// AccessList returns the peer access list which may be used to block peers (or restrict
+// the peers to an allowlist) at runtime for all selection services created by this provider
+func (p *SelectionProvider) AccessList() *filter.PeerAccessList {
+	return p.accessList
+}
+
 type selectionService struct {
 	channelID        string
 	pgResolvers      *lazycache.Cache
 	pgLBP            pgresolver.LoadBalancePolicy
 	ccPolicyProvider CCPolicyProvider
 	discoveryService fab.DiscoveryService
+	accessList       *filter.PeerAccessList
 }
 
 // Initialize allow for initializing providers
@@ -118,6 +145,7 @@ func (p *SelectionProvider) CreateSelectionService(channelID string) (fab.Select
 	if err != nil {
 		return nil, err
 	}
+	svc.accessList = p.accessList
 
 	p.refLock.Lock()
 	p.refs = append(p.refs, svc)
@@ -126,6 +154,27 @@ func (p *SelectionProvider) CreateSelectionService(channelID string) (fab.Select
 	return svc, nil
 }
 
+// ChannelStats contains diagnostic information about a selection service
+type ChannelStats struct {
+	ChannelID        string `json:"channelId"`
+	CachedPeerGroups int    `json:"cachedPeerGroupResolvers"`
+}
+
+// Diagnostics returns the stats of the selection services created by this provider
+func (p *SelectionProvider) Diagnostics() interface{} {
+	p.refLock.RLock()
+	defer p.refLock.RUnlock()
+
+	stats := make([]ChannelStats, len(p.refs))
+	for i, ref := range p.refs {
+		stats[i] = ChannelStats{
+			ChannelID:        ref.channelID,
+			CachedPeerGroups: ref.pgResolvers.Len(),
+		}
+	}
+	return stats
+}
+
 // Close the selection services created by this provider
 func (p *SelectionProvider) Close() {
 	p.refLock.Lock()
@@ -159,7 +208,12 @@ func newSelectionService(channelID string, lbp pgresolver.LoadBalancePolicy, ccP
 }
 
 func (s *selectionService) Initialize(context contextAPI.Channel) error {
-	s.discoveryService = context.DiscoveryService()
+	// Skip peers that are known to be down or that have been blocked
+	targetFilter := fab.TargetFilter(filter.NewHealthFilter(context.InfraProvider().EndpointHealth()))
+	if s.accessList != nil {
+		targetFilter = filter.All(targetFilter, s.accessList)
+	}
+	s.discoveryService = discovery.NewDiscoveryFilterService(context.DiscoveryService(), targetFilter)
 	return nil
 }
 
@@ -175,7 +229,7 @@ func (s *selectionService) GetEndorsersForChaincode(chaincodeIDs []string, opts
 		return nil, errors.WithMessage(err, fmt.Sprintf("Error getting peer group resolver for chaincodes [%v] on channel [%s]", chaincodeIDs, s.channelID))
 	}
 
-	peers, err := s.discoveryService.GetPeers()
+	peers, err := discovery.GetPeers(params.Context, s.discoveryService)
 	if err != nil {
 		return nil, err
 	}
@@ -172,6 +172,108 @@ func TestGetEndorsersForChaincodeTwoCCsTwoChannels(t *testing.T) {
 	verify(t, service, expected, channel2, cc1, cc2)
 }
 
+func TestGetEndorsersForChaincodeWithLocality(t *testing.T) {
+	config := &mockLocalityConfig{
+		EndpointConfig: mocks.NewMockEndpointConfig(),
+		labels: map[string]map[string]string{
+			p1.URL(): {RegionLabel: "west", ZoneLabel: "west-a"},
+			p2.URL(): {RegionLabel: "east", ZoneLabel: "east-b"},
+			p3.URL(): {RegionLabel: "east", ZoneLabel: "east-a"},
+			p4.URL(): {RegionLabel: "west", ZoneLabel: "west-a"},
+		},
+	}
+	lbp := pgresolver.NewPreferredLBP(newLocalityScorer(config, "east", "east-a"), pgresolver.NewRoundRobinLBP())
+
+	// Channel1(Policy(cc1)) = Org1
+	service, err := newMockSelectionService(
+		newMockCCDataProvider(channel1).add(cc1, getPolicy1()),
+		lbp,
+		newMockDiscoveryService(p1, p2, p3, p4),
+	)
+	if err != nil {
+		t.Fatalf("got error creating selection service: %s", err)
+	}
+	// The same-region peer should always be chosen
+	verify(t, service, []pgresolver.PeerGroup{pg(p2)}, channel1, cc1)
+
+	// Policy(cc1) AND Policy(cc2) = Org1 AND (1 of [(2 of [Org1, Org2]),(2 of [Org1, Org3, Org4])])
+	service, err = newMockSelectionService(
+		newMockCCDataProvider(channel1).add(cc1, getPolicy1()).add(cc2, getPolicy2()),
+		lbp,
+		newMockDiscoveryService(p1, p2, p3, p4),
+	)
+	if err != nil {
+		t.Fatalf("got error creating selection service: %s", err)
+	}
+	// The group with the most local peers should always be chosen
+	verify(t, service, []pgresolver.PeerGroup{pg(p2, p3)}, channel1, cc1, cc2)
+
+	// Fall back to the peers in other regions
+	service, err = newMockSelectionService(
+		newMockCCDataProvider(channel1).add(cc1, getPolicy1()),
+		lbp,
+		newMockDiscoveryService(p1, p3, p4),
+	)
+	if err != nil {
+		t.Fatalf("got error creating selection service: %s", err)
+	}
+	verify(t, service, []pgresolver.PeerGroup{pg(p1)}, channel1, cc1)
+}
+
+func TestWithLocality(t *testing.T) {
+	selectionProvider, err := New(mocks.NewMockEndpointConfig(), nil, WithLocality("east", ""))
+	if err != nil {
+		t.Fatalf("Failed to setup selection provider: %s", err)
+	}
+	if got, notWant := reflect.TypeOf(selectionProvider.lbp), reflect.TypeOf(pgresolver.NewRandomLBP()); got == notWant {
+		t.Fatalf("Expecting the load balancing policy to prefer local peers")
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	selectionProvider, err := New(mocks.NewMockEndpointConfig(), nil)
+	if err != nil {
+		t.Fatalf("Failed to setup selection provider: %s", err)
+	}
+
+	if stats := selectionProvider.Diagnostics().([]ChannelStats); len(stats) != 0 {
+		t.Fatalf("Expecting no stats but got %v", stats)
+	}
+
+	service, err := newMockSelectionService(
+		newMockCCDataProvider(channel1).
+			add(cc1, getPolicy1()).
+			add(cc2, getPolicy2()),
+		pgresolver.NewRoundRobinLBP(),
+		newMockDiscoveryService(p1, p2, p3, p4, p5, p6, p7, p8),
+	)
+	if err != nil {
+		t.Fatalf("got error creating selection service: %s", err)
+	}
+	selectionProvider.refs = append(selectionProvider.refs, service.(*selectionService))
+
+	if _, err := service.GetEndorsersForChaincode([]string{cc1}); err != nil {
+		t.Fatalf("error getting endorsers: %s", err)
+	}
+	if _, err := service.GetEndorsersForChaincode([]string{cc2}); err != nil {
+		t.Fatalf("error getting endorsers: %s", err)
+	}
+
+	stats := selectionProvider.Diagnostics().([]ChannelStats)
+	if len(stats) != 1 || stats[0].CachedPeerGroups != 2 {
+		t.Fatalf("Expecting stats for one channel with two cached peer group resolvers but got %v", stats)
+	}
+}
+
+type mockLocalityConfig struct {
+	fab.EndpointConfig
+	labels map[string]map[string]string
+}
+
+func (c *mockLocalityConfig) PeerConfig(nameOrURL string) (*fab.PeerConfig, error) {
+	return &fab.PeerConfig{URL: nameOrURL, Labels: c.labels[nameOrURL]}, nil
+}
+
 func verify(t *testing.T, service fab.SelectionService, expectedPeerGroups []pgresolver.PeerGroup, channelID string, chaincodeIDs ...string) {
 	// Set the log level to WARNING since the following spits out too much info in DEBUG
 	module := "pg-resolver"
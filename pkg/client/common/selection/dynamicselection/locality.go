@@ -0,0 +1,45 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dynamicselection
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/dynamicselection/pgresolver"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+const (
+	// RegionLabel is the peer config label that specifies the region of the peer
+	RegionLabel = "region"
+	// ZoneLabel is the peer config label that specifies the zone (within the region) of the peer
+	ZoneLabel = "zone"
+)
+
+const (
+	otherRegionScore = iota
+	sameRegionScore
+	sameZoneScore
+)
+
+// newLocalityScorer returns a scorer that prefers peers in the same zone, followed
+// by peers in the same region, followed by all other peers
+func newLocalityScorer(config fab.EndpointConfig, region, zone string) pgresolver.PeerScorer {
+	return func(peer fab.Peer) int {
+		peerConfig, err := config.PeerConfig(peer.URL())
+		if err != nil || peerConfig == nil {
+			logger.Debugf("Peer config not found for [%s] - assuming peer is in another region", peer.URL())
+			return otherRegionScore
+		}
+
+		if peerConfig.Labels[RegionLabel] != region {
+			return otherRegionScore
+		}
+		if zone != "" && peerConfig.Labels[ZoneLabel] == zone {
+			return sameZoneScore
+		}
+		return sameRegionScore
+	}
+}
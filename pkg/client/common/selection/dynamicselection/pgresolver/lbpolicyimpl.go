@@ -7,7 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package pgresolver
 
 import (
-	"math/rand"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 )
 
 type randomLBP struct {
@@ -27,7 +28,7 @@ func (lbp *randomLBP) Choose(peerGroups []PeerGroup) PeerGroup {
 		return NewPeerGroup()
 	}
 
-	index := rand.Intn(len(peerGroups))
+	index := random.Intn(len(peerGroups))
 
 	logger.Debugf("randomLBP - Choosing index %d\n", index)
 	return peerGroups[index]
@@ -50,7 +51,7 @@ func (lbp *roundRobinLBP) Choose(peerGroups []PeerGroup) PeerGroup {
 	}
 
 	if lbp.index == -1 {
-		lbp.index = rand.Intn(len(peerGroups))
+		lbp.index = random.Intn(len(peerGroups))
 	} else {
 		lbp.index++
 	}
@@ -63,3 +64,51 @@ func (lbp *roundRobinLBP) Choose(peerGroups []PeerGroup) PeerGroup {
 
 	return peerGroups[lbp.index]
 }
+
+// PeerScorer returns the preference score of the given peer. Peers with higher scores are preferred.
+type PeerScorer func(peer fab.Peer) int
+
+type preferredLBP struct {
+	scorer PeerScorer
+	lbp    LoadBalancePolicy
+}
+
+// NewPreferredLBP returns a load-balance policy that narrows the given peer groups down to the groups
+// with the highest score and then uses the given load-balance policy to choose from those groups. The
+// score of a peer group is the lowest score of any of its peers since the slowest endorser determines
+// the latency of the endorsement.
+func NewPreferredLBP(scorer PeerScorer, lbp LoadBalancePolicy) LoadBalancePolicy {
+	return &preferredLBP{scorer: scorer, lbp: lbp}
+}
+
+func (lbp *preferredLBP) Choose(peerGroups []PeerGroup) PeerGroup {
+	var preferred []PeerGroup
+	bestScore := 0
+	for _, pg := range peerGroups {
+		score := lbp.score(pg)
+		if len(preferred) == 0 || score > bestScore {
+			preferred = []PeerGroup{pg}
+			bestScore = score
+		} else if score == bestScore {
+			preferred = append(preferred, pg)
+		}
+	}
+
+	logger.Debugf("preferredLBP - %d of %d peer groups have the highest score %d\n", len(preferred), len(peerGroups), bestScore)
+
+	return lbp.lbp.Choose(preferred)
+}
+
+func (lbp *preferredLBP) score(pg PeerGroup) int {
+	peers := pg.Peers()
+	if len(peers) == 0 {
+		return 0
+	}
+	score := lbp.scorer(peers[0])
+	for _, peer := range peers[1:] {
+		if s := lbp.scorer(peer); s < score {
+			score = s
+		}
+	}
+	return score
+}
@@ -326,6 +326,35 @@ func TestPeerGroupResolverRandomPeers(t *testing.T) {
 	}
 }
 
+func TestPreferredLBP(t *testing.T) {
+	scores := map[fab.Peer]int{p1: 2, p2: 1, p3: 2, p4: 0}
+	scorer := func(peer fab.Peer) int {
+		return scores[peer]
+	}
+
+	lbp := NewPreferredLBP(scorer, NewRoundRobinLBP())
+
+	peerGroups := []PeerGroup{pg(p1, p4), pg(p1, p3), pg(p2, p3)}
+	for i := 0; i < 5; i++ {
+		chosen := lbp.Choose(peerGroups)
+		if !containsAllPeers(chosen, pg(p1, p3)) {
+			t.Fatalf("expecting peer group with the highest score to be chosen but got %s", chosen)
+		}
+	}
+
+	// Fall back to the groups with lower scores if no better groups are available
+	peerGroups = []PeerGroup{pg(p1, p4), pg(p2, p3)}
+	chosen := lbp.Choose(peerGroups)
+	if !containsAllPeers(chosen, pg(p2, p3)) {
+		t.Fatalf("expecting peer group with the highest score to be chosen but got %s", chosen)
+	}
+
+	chosen = lbp.Choose(nil)
+	if len(chosen.Peers()) != 0 {
+		t.Fatalf("expecting empty peer group but got %s", chosen)
+	}
+}
+
 func testPeerGroupResolver(t *testing.T, sigPolicyEnv *common.SignaturePolicyEnvelope, peers []fab.Peer, expected []PeerGroup, expectedErr error) {
 	pgResolver, err := NewRoundRobinPeerGroupResolver(sigPolicyEnv)
 	if err != nil {
@@ -0,0 +1,217 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hybridselection
+
+import (
+	reqContext "context"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	copts "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	contextAPI "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+type providerInit interface {
+	Initialize(providers contextAPI.Providers) error
+}
+
+type serviceInit interface {
+	Initialize(context contextAPI.Channel) error
+}
+
+type closable interface {
+	Close()
+}
+
+type accessListProvider interface {
+	AccessList() *filter.PeerAccessList
+}
+
+type diagnosable interface {
+	Diagnostics() interface{}
+}
+
+// SelectionProvider implements a selection provider that combines the statically configured
+// channel peers with the peers provided by the channel's discovery service. Endorsers are
+// selected by the delegate selection provider (typically dynamic selection) using the
+// following precedence rules:
+//
+//  1. If the endorsement policy can be satisfied by the statically configured channel peers
+//     alone then only static peers are selected.
+//  2. Otherwise, the endorsers are selected from the union of the static peers and the
+//     discovered peers. Static peers take precedence over discovered peers with the same URL.
+//
+// The per-request peer filter (if any) is applied in both cases.
+type SelectionProvider struct {
+	config   fab.EndpointConfig
+	delegate fab.SelectionProvider
+}
+
+// New returns a hybrid selection provider which delegates endorser selection to the given provider
+func New(config fab.EndpointConfig, delegate fab.SelectionProvider) (*SelectionProvider, error) {
+	if delegate == nil {
+		return nil, errors.New("delegate selection provider is required")
+	}
+	return &SelectionProvider{config: config, delegate: delegate}, nil
+}
+
+// Initialize initializes the delegate selection provider
+func (p *SelectionProvider) Initialize(providers contextAPI.Providers) error {
+	if init, ok := p.delegate.(providerInit); ok {
+		return init.Initialize(providers)
+	}
+	return nil
+}
+
+// CreateSelectionService creates a hybrid selection service
+func (p *SelectionProvider) CreateSelectionService(channelID string) (fab.SelectionService, error) {
+	delegate, err := p.delegate.CreateSelectionService(channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create delegate selection service")
+	}
+	return &selectionService{channelID: channelID, config: p.config, delegate: delegate}, nil
+}
+
+// Close closes the delegate selection provider
+func (p *SelectionProvider) Close() {
+	if c, ok := p.delegate.(closable); ok {
+		c.Close()
+	}
+}
+
+// AccessList returns the peer access list of the delegate selection provider
+// or nil if the delegate doesn't support an access list
+func (p *SelectionProvider) AccessList() *filter.PeerAccessList {
+	if alp, ok := p.delegate.(accessListProvider); ok {
+		return alp.AccessList()
+	}
+	return nil
+}
+
+// Diagnostics returns the diagnostics of the delegate selection provider
+// or nil if the delegate doesn't provide diagnostics
+func (p *SelectionProvider) Diagnostics() interface{} {
+	if d, ok := p.delegate.(diagnosable); ok {
+		return d.Diagnostics()
+	}
+	return nil
+}
+
+// selectionService implements hybrid selection service
+type selectionService struct {
+	channelID   string
+	config      fab.EndpointConfig
+	delegate    fab.SelectionService
+	staticPeers map[string]fab.Peer
+}
+
+func (s *selectionService) Initialize(context contextAPI.Channel) error {
+	chPeers, err := s.config.ChannelPeers(s.channelID)
+	if err != nil {
+		return errors.WithMessage(err, "unable to read configuration for channel peers")
+	}
+
+	var peers []fab.Peer
+	s.staticPeers = make(map[string]fab.Peer)
+	for _, p := range chPeers {
+		peer, err := context.InfraProvider().CreatePeerFromConfig(&p.NetworkPeer)
+		if err != nil {
+			return errors.WithMessage(err, "NewPeer failed")
+		}
+		peers = append(peers, peer)
+		s.staticPeers[peer.URL()] = peer
+	}
+
+	if init, ok := s.delegate.(serviceInit); ok {
+		return init.Initialize(&channelContext{
+			Channel:   context,
+			discovery: &discoveryService{staticPeers: peers, discoveryService: context.DiscoveryService()},
+		})
+	}
+	return nil
+}
+
+func (s *selectionService) GetEndorsersForChaincode(chaincodeIDs []string, opts ...copts.Opt) ([]fab.Peer, error) {
+	params := options.NewParams(opts)
+
+	staticFilter := func(peer fab.Peer) bool {
+		if _, ok := s.staticPeers[peer.URL()]; !ok {
+			return false
+		}
+		return params.PeerFilter == nil || params.PeerFilter(peer)
+	}
+
+	// The caller's options are passed through; the static filter, which includes the caller's
+	// peer filter, is appended so that it replaces the caller's filter
+	staticOpts := append(append([]copts.Opt{}, opts...), options.WithPeerFilter(staticFilter))
+	peers, err := s.delegate.GetEndorsersForChaincode(chaincodeIDs, staticOpts...)
+	if err != nil {
+		logger.Debugf("Error selecting endorsers from static peers of channel [%s]: %s", s.channelID, err)
+	} else if len(peers) > 0 {
+		return peers, nil
+	}
+
+	logger.Debugf("Static peers of channel [%s] are insufficient for chaincodes %v - including discovered peers", s.channelID, chaincodeIDs)
+	return s.delegate.GetEndorsersForChaincode(chaincodeIDs, opts...)
+}
+
+// channelContext overrides the discovery service of the channel context
+// that is passed to the delegate selection service
+type channelContext struct {
+	contextAPI.Channel
+	discovery fab.DiscoveryService
+}
+
+// DiscoveryService returns the union of the static and discovered peers
+func (c *channelContext) DiscoveryService() fab.DiscoveryService {
+	return c.discovery
+}
+
+// discoveryService returns the static peers followed by the
+// discovered peers that aren't statically configured
+type discoveryService struct {
+	staticPeers      []fab.Peer
+	discoveryService fab.DiscoveryService
+}
+
+// GetPeers is used to get peers
+func (ds *discoveryService) GetPeers() ([]fab.Peer, error) {
+	return ds.GetPeersContext(reqContext.Background())
+}
+
+// GetPeersContext is used to get peers. Only the static peers are returned if the
+// discovered peers aren't available before the context is done.
+func (ds *discoveryService) GetPeersContext(ctx reqContext.Context) ([]fab.Peer, error) {
+	peers := append([]fab.Peer{}, ds.staticPeers...)
+	if ds.discoveryService == nil {
+		return peers, nil
+	}
+
+	discoveredPeers, err := discovery.GetPeers(ctx, ds.discoveryService)
+	if err != nil {
+		logger.Warnf("Error retrieving peers from discovery service - using static peers only: %s", err)
+		return peers, nil
+	}
+
+	urls := make(map[string]bool)
+	for _, peer := range peers {
+		urls[peer.URL()] = true
+	}
+	for _, peer := range discoveredPeers {
+		if !urls[peer.URL()] {
+			urls[peer.URL()] = true
+			peers = append(peers, peer)
+		}
+	}
+	return peers, nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package hybridselection
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	copts "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fabmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	channelID = "testchannel"
+	org1      = "Org1MSP"
+	org2      = "Org2MSP"
+)
+
+func TestHybridSelection(t *testing.T) {
+	staticPeer := newPeer("peer1.org1.com:7051", org1)
+	discoveredPeer1 := newPeer("peer2.org1.com:7051", org1)
+	discoveredPeer2 := newPeer("peer1.org2.com:7051", org2)
+
+	config := &mockConfig{
+		EndpointConfig: fabmocks.NewMockEndpointConfig(),
+		channelPeers:   []fab.ChannelPeer{channelPeer(staticPeer)},
+	}
+
+	delegate := &mockSelectionProvider{}
+	selectionProvider, err := New(config, delegate)
+	if err != nil {
+		t.Fatalf("Failed to setup selection provider: %s", err)
+	}
+
+	selectionService, err := selectionProvider.CreateSelectionService(channelID)
+	if err != nil {
+		t.Fatalf("Failed to setup selection service: %s", err)
+	}
+
+	ctx := fabmocks.NewMockContext(mspmocks.NewMockSigningIdentity("User1", ""))
+	chctx := fabmocks.NewMockChannelContext(ctx, channelID)
+	chctx.Discovery = fabmocks.NewMockDiscoveryService(nil, []fab.Peer{staticPeer, discoveredPeer1, discoveredPeer2})
+
+	if err := selectionService.(serviceInit).Initialize(chctx); err != nil {
+		t.Fatalf("Failed to initialize selection service: %s", err)
+	}
+
+	// Static peers satisfy the policy
+	delegate.requiredMSPs = []string{org1}
+	peers, err := selectionService.GetEndorsersForChaincode([]string{"cc1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{staticPeer.URL()}, urls(peers), "expecting only the static peer to be selected")
+
+	// Static peers are insufficient so discovered peers are included
+	delegate.requiredMSPs = []string{org1, org2}
+	peers, err = selectionService.GetEndorsersForChaincode([]string{"cc1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{staticPeer.URL(), discoveredPeer2.URL()}, urls(peers), "expecting the static peer to take precedence over discovered peers")
+
+	// The peer filter applies to static peers
+	filter := func(peer fab.Peer) bool {
+		return peer.URL() != staticPeer.URL()
+	}
+	delegate.requiredMSPs = []string{org1}
+	peers, err = selectionService.GetEndorsersForChaincode([]string{"cc1"}, options.WithPeerFilter(filter))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{discoveredPeer1.URL()}, urls(peers), "expecting the filtered static peer to be replaced by a discovered peer")
+}
+
+func TestHybridSelectionCallerOptions(t *testing.T) {
+	staticPeer1 := newPeer("peer1.org1.com:7051", org1)
+	staticPeer2 := newPeer("peer2.org1.com:7051", org1)
+	discoveredPeer := newPeer("peer3.org1.com:7051", org1)
+
+	config := &mockConfig{
+		EndpointConfig: fabmocks.NewMockEndpointConfig(),
+		channelPeers:   []fab.ChannelPeer{channelPeer(staticPeer1), channelPeer(staticPeer2)},
+	}
+
+	delegate := &mockSelectionProvider{requiredMSPs: []string{org1}}
+	selectionProvider, err := New(config, delegate)
+	if err != nil {
+		t.Fatalf("Failed to setup selection provider: %s", err)
+	}
+	selectionService, err := selectionProvider.CreateSelectionService(channelID)
+	if err != nil {
+		t.Fatalf("Failed to setup selection service: %s", err)
+	}
+
+	ctx := fabmocks.NewMockContext(mspmocks.NewMockSigningIdentity("User1", ""))
+	chctx := fabmocks.NewMockChannelContext(ctx, channelID)
+	chctx.Discovery = fabmocks.NewMockDiscoveryService(nil, []fab.Peer{discoveredPeer})
+	if err := selectionService.(serviceInit).Initialize(chctx); err != nil {
+		t.Fatalf("Failed to initialize selection service: %s", err)
+	}
+
+	filter := func(peer fab.Peer) bool {
+		return peer.URL() != staticPeer1.URL()
+	}
+	applied := 0
+	callerOpt := func(p copts.Params) {
+		applied++
+	}
+
+	peers, err := selectionService.GetEndorsersForChaincode([]string{"cc1"}, options.WithPeerFilter(filter), callerOpt)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{staticPeer2.URL()}, urls(peers), "expecting the caller's filter to apply to the static peers")
+	// The options are applied once by the hybrid selection service to get the caller's filter and once by the delegate
+	assert.Equal(t, 2, applied, "expecting the caller's options to be passed to the delegate")
+}
+
+func TestHybridSelectionDiscoveryUnion(t *testing.T) {
+	staticPeer := newPeer("peer1.org1.com:7051", org1)
+	discoveredPeer := newPeer("peer1.org2.com:7051", org2)
+
+	ds := &discoveryService{
+		staticPeers:      []fab.Peer{staticPeer},
+		discoveryService: fabmocks.NewMockDiscoveryService(nil, []fab.Peer{newPeer(staticPeer.URL(), org1), discoveredPeer}),
+	}
+
+	peers, err := ds.GetPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(peers))
+	assert.True(t, peers[0] == staticPeer, "expecting the static peer to take precedence")
+	assert.True(t, peers[1] == discoveredPeer)
+}
+
+func TestHybridSelectionNoDelegate(t *testing.T) {
+	_, err := New(fabmocks.NewMockEndpointConfig(), nil)
+	assert.Error(t, err, "expecting error when no delegate is provided")
+}
+
+type mockConfig struct {
+	fab.EndpointConfig
+	channelPeers []fab.ChannelPeer
+}
+
+func (c *mockConfig) ChannelPeers(name string) ([]fab.ChannelPeer, error) {
+	return c.channelPeers, nil
+}
+
+// mockSelectionProvider selects one peer from each of the required MSPs
+type mockSelectionProvider struct {
+	requiredMSPs []string
+}
+
+func (p *mockSelectionProvider) CreateSelectionService(channelID string) (fab.SelectionService, error) {
+	return &mockSelectionService{provider: p}, nil
+}
+
+type mockSelectionService struct {
+	provider         *mockSelectionProvider
+	discoveryService fab.DiscoveryService
+}
+
+func (s *mockSelectionService) Initialize(context context.Channel) error {
+	s.discoveryService = context.DiscoveryService()
+	return nil
+}
+
+func (s *mockSelectionService) GetEndorsersForChaincode(chaincodeIDs []string, opts ...copts.Opt) ([]fab.Peer, error) {
+	params := options.NewParams(opts)
+
+	peers, err := s.discoveryService.GetPeers()
+	if err != nil {
+		return nil, err
+	}
+
+	var endorsers []fab.Peer
+	for _, mspID := range s.provider.requiredMSPs {
+		for _, peer := range peers {
+			if peer.MSPID() == mspID && (params.PeerFilter == nil || params.PeerFilter(peer)) {
+				endorsers = append(endorsers, peer)
+				break
+			}
+		}
+	}
+	if len(endorsers) < len(s.provider.requiredMSPs) {
+		return nil, nil
+	}
+	return endorsers, nil
+}
+
+func newPeer(url, mspID string) fab.Peer {
+	peer := fabmocks.NewMockPeer(url, url)
+	peer.SetMSPID(mspID)
+	return peer
+}
+
+func channelPeer(peer fab.Peer) fab.ChannelPeer {
+	return fab.ChannelPeer{
+		NetworkPeer: fab.NetworkPeer{
+			PeerConfig: fab.PeerConfig{URL: peer.URL()},
+			MSPID:      peer.MSPID(),
+		},
+	}
+}
+
+func urls(peers []fab.Peer) []string {
+	var result []string
+	for _, peer := range peers {
+		result = append(result, peer.URL())
+	}
+	return result
+}
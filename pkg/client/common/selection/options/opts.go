@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package options
 
 import (
+	reqContext "context"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	copts "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -20,6 +22,7 @@ type PeerFilter func(peer fab.Peer) bool
 // Params defines the parameters of a selection service request
 type Params struct {
 	PeerFilter PeerFilter
+	Context    reqContext.Context
 }
 
 // NewParams creates new parameters based on the provided options
@@ -38,6 +41,16 @@ func WithPeerFilter(value PeerFilter) copts.Opt {
 	}
 }
 
+// WithContext sets the request context. The selection service stops waiting for
+// the peers of the channel when the context is done.
+func WithContext(value reqContext.Context) copts.Opt {
+	return func(p copts.Params) {
+		if setter, ok := p.(contextSetter); ok {
+			setter.SetContext(value)
+		}
+	}
+}
+
 type peerFilterSetter interface {
 	SetPeerFilter(value PeerFilter)
 }
@@ -47,3 +60,12 @@ func (p *Params) SetPeerFilter(value PeerFilter) {
 	logger.Debugf("PeerFilter: %#v", value)
 	p.PeerFilter = value
 }
+
+type contextSetter interface {
+	SetContext(value reqContext.Context)
+}
+
+// SetContext sets the request context
+func (p *Params) SetContext(value reqContext.Context) {
+	p.Context = value
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package staticselection
 
 import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	copts "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
@@ -20,33 +22,45 @@ var logger = logging.NewLogger(loggerModule)
 
 // SelectionProvider implements selection provider
 type SelectionProvider struct {
-	config fab.EndpointConfig
+	config     fab.EndpointConfig
+	accessList *filter.PeerAccessList
 }
 
 // New returns static selection provider
 func New(config fab.EndpointConfig) (*SelectionProvider, error) {
-	return &SelectionProvider{config: config}, nil
+	return &SelectionProvider{config: config, accessList: filter.NewPeerAccessList()}, nil
+}
+
+// AccessList returns the peer access list which may be used to block peers (or restrict
+// the peers to an allowlist) at runtime for all selection services created by this provider
+func (p *SelectionProvider) AccessList() *filter.PeerAccessList {
+	return p.accessList
 }
 
 // selectionService implements static selection service
 type selectionService struct {
 	discoveryService fab.DiscoveryService
+	accessList       *filter.PeerAccessList
 }
 
 // CreateSelectionService creates a static selection service
 func (p *SelectionProvider) CreateSelectionService(channelID string) (fab.SelectionService, error) {
-	return &selectionService{}, nil
+	return &selectionService{accessList: p.accessList}, nil
 }
 
 func (s *selectionService) Initialize(context contextAPI.Channel) error {
-	s.discoveryService = context.DiscoveryService()
+	// Skip peers that are known to be down or that have been blocked
+	s.discoveryService = discovery.NewDiscoveryFilterService(
+		context.DiscoveryService(),
+		filter.All(filter.NewHealthFilter(context.InfraProvider().EndpointHealth()), s.accessList),
+	)
 	return nil
 }
 
 func (s *selectionService) GetEndorsersForChaincode(chaincodeIDs []string, opts ...copts.Opt) ([]fab.Peer, error) {
 	params := options.NewParams(opts)
 
-	channelPeers, err := s.discoveryService.GetPeers()
+	channelPeers, err := discovery.GetPeers(params.Context, s.discoveryService)
 	if err != nil {
 		logger.Errorf("Error retrieving peers from discovery service: %s", err)
 		return nil, nil
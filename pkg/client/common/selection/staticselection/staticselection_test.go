@@ -83,3 +83,44 @@ func TestStaticSelection(t *testing.T) {
 		t.Fatalf("Expecting peer %s but got %s", peer2.URL(), peers[0].URL())
 	}
 }
+
+func TestStaticSelectionAccessList(t *testing.T) {
+	peer1 := fabmocks.NewMockPeer("p1", "localhost:7051")
+	peer2 := fabmocks.NewMockPeer("p2", "localhost:8051")
+
+	selectionProvider, err := New(fabmocks.NewMockEndpointConfig())
+	if err != nil {
+		t.Fatalf("Failed to setup selection provider: %s", err)
+	}
+
+	selectionService, err := selectionProvider.CreateSelectionService("testchannel")
+	if err != nil {
+		t.Fatalf("Failed to setup selection service: %s", err)
+	}
+
+	ctx := fabmocks.NewMockContext(mspmocks.NewMockSigningIdentity("User1", ""))
+	chctx := fabmocks.NewMockChannelContext(ctx, "testchannel")
+	chctx.Discovery = fabmocks.NewMockDiscoveryService(nil, []fab.Peer{peer1, peer2})
+
+	selectionService.(serviceInit).Initialize(chctx)
+
+	selectionProvider.AccessList().Block(peer1.URL(), 0)
+
+	peers, err := selectionService.GetEndorsersForChaincode(nil)
+	if err != nil {
+		t.Fatalf("Failed to get endorsers: %s", err)
+	}
+	if len(peers) != 1 || peers[0].URL() != peer2.URL() {
+		t.Fatalf("Expecting only peer %s but got %v", peer2.URL(), peers)
+	}
+
+	selectionProvider.AccessList().Unblock(peer1.URL())
+
+	peers, err = selectionService.GetEndorsersForChaincode(nil)
+	if err != nil {
+		t.Fatalf("Failed to get endorsers: %s", err)
+	}
+	if len(peers) != 2 {
+		t.Fatalf("Expecting 2, got %d peers", len(peers))
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// Block verifies the integrity of blocks along with the orderer and endorsement
+// signatures that they contain
+type Block struct {
+	Membership fab.ChannelMembership
+	// OrdererMSPIDs contains the MSP IDs of the orderer organizations in the channel
+	// config. If set, orderer signatures must be created by an identity of one of these MSPs.
+	OrdererMSPIDs []string
+}
+
+// Verify checks the data hash, the orderer signatures and the endorsement signatures of
+// the valid transactions in the given block
+func (v *Block) Verify(block *common.Block) error {
+	if err := VerifyDataHash(block); err != nil {
+		return err
+	}
+	if err := v.VerifyOrdererSignatures(block); err != nil {
+		return err
+	}
+	return v.VerifyEndorsements(block)
+}
+
+// VerifyOrdererSignatures checks that the block header was signed by at least one
+// orderer and that all of the orderer signatures are valid
+func (v *Block) VerifyOrdererSignatures(block *common.Block) error {
+	if block.Header == nil {
+		return errors.New("block header is nil")
+	}
+	if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_SIGNATURES) {
+		return errors.Errorf("signatures are missing from block [%d]", block.Header.Number)
+	}
+
+	metadata := &common.Metadata{}
+	if err := proto.Unmarshal(block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES], metadata); err != nil {
+		return errors.Wrapf(err, "unmarshal of signatures metadata of block [%d] failed", block.Header.Number)
+	}
+	if len(metadata.Signatures) == 0 {
+		return errors.Errorf("no orderer signatures in block [%d]", block.Header.Number)
+	}
+
+	headerBytes, err := BlockHeaderBytes(block.Header)
+	if err != nil {
+		return err
+	}
+
+	for _, metadataSig := range metadata.Signatures {
+		sigHeader, err := utils.GetSignatureHeader(metadataSig.SignatureHeader)
+		if err != nil {
+			return errors.Wrapf(err, "unmarshal of signature header of block [%d] failed", block.Header.Number)
+		}
+
+		if err := v.validateOrderer(sigHeader.Creator); err != nil {
+			return errors.WithMessage(err, "invalid orderer signature in block")
+		}
+
+		msg := bytes.Join([][]byte{metadata.Value, metadataSig.SignatureHeader, headerBytes}, nil)
+		if err := v.Membership.Verify(sigHeader.Creator, msg, metadataSig.Signature); err != nil {
+			return errors.WithMessage(err, "orderer signature verification failed")
+		}
+	}
+
+	return nil
+}
+
+// VerifyEndorsements checks the endorsement signatures of the valid endorser transactions in the
+// given block. Transactions that were marked invalid by the committing peer are not checked.
+func (v *Block) VerifyEndorsements(block *common.Block) error {
+	if block.Data == nil {
+		return nil
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, data := range block.Data.Data {
+		if i < len(txFilter) && !txFilter.IsValid(i) {
+			continue
+		}
+
+		env, err := utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			return errors.Wrap(err, "error extracting envelope from block")
+		}
+		if err := v.VerifyTransactionEndorsements(env); err != nil {
+			return errors.WithMessage(err, "endorsement verification failed")
+		}
+	}
+
+	return nil
+}
+
+// VerifyTransactionEndorsements checks the endorsement signatures of the given transaction envelope.
+// Transactions other than endorser transactions aren't checked.
+func (v *Block) VerifyTransactionEndorsements(env *common.Envelope) error {
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return errors.Wrap(err, "error extracting payload from envelope")
+	}
+	if payload.Header == nil {
+		return errors.New("payload header is nil")
+	}
+
+	channelHeader, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return errors.Wrap(err, "error extracting channel header from payload")
+	}
+	if common.HeaderType(channelHeader.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return nil
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+
+	for _, action := range tx.Actions {
+		ccActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return errors.Wrap(err, "error unmarshalling chaincode action payload")
+		}
+		if err := v.VerifyEndorsedAction(channelHeader.TxId, ccActionPayload.Action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VerifyEndorsedAction checks that the given action of a transaction was endorsed and that
+// each endorsement is a valid signature of the proposal response payload by a valid endorser
+func (v *Block) VerifyEndorsedAction(txID string, action *pb.ChaincodeEndorsedAction) error {
+	if action == nil {
+		return errors.Errorf("endorsed action is missing from transaction [%s]", txID)
+	}
+	if len(action.Endorsements) == 0 {
+		return errors.Errorf("no endorsements in transaction [%s]", txID)
+	}
+
+	for _, endorsement := range action.Endorsements {
+		if err := v.Membership.Validate(endorsement.Endorser); err != nil {
+			return errors.WithMessage(err, "endorser certificate of transaction ["+txID+"] is not valid")
+		}
+		msg := append(append([]byte{}, action.ProposalResponsePayload...), endorsement.Endorser...)
+		if err := v.Membership.Verify(endorsement.Endorser, msg, endorsement.Signature); err != nil {
+			return errors.WithMessage(err, "endorsement signature of transaction ["+txID+"] is not valid")
+		}
+	}
+
+	return nil
+}
+
+func (v *Block) validateOrderer(serializedID []byte) error {
+	if err := v.Membership.Validate(serializedID); err != nil {
+		return errors.WithMessage(err, "orderer certificate is not valid")
+	}
+
+	if len(v.OrdererMSPIDs) == 0 {
+		return nil
+	}
+
+	identity := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedID, identity); err != nil {
+		return errors.Wrap(err, "unmarshal of orderer identity failed")
+	}
+	for _, mspID := range v.OrdererMSPIDs {
+		if identity.Mspid == mspID {
+			return nil
+		}
+	}
+	return errors.Errorf("signer MSP [%s] is not an orderer organization", identity.Mspid)
+}
+
+// VerifyDataHash checks that the data hash in the block header matches the block data
+func VerifyDataHash(block *common.Block) error {
+	if block == nil || block.Header == nil {
+		return errors.New("block header is nil")
+	}
+	if !bytes.Equal(block.Header.DataHash, BlockDataHash(block.Data)) {
+		return errors.Errorf("data hash of block [%d] does not match block data", block.Header.Number)
+	}
+	return nil
+}
+
+// VerifyHashChain checks that the given consecutive blocks are chained correctly, i.e. that
+// the previous hash of each block matches the header hash of the block preceding it and that
+// the data hash of each block matches its data
+func VerifyHashChain(blocks ...*common.Block) error {
+	for i, block := range blocks {
+		if err := VerifyDataHash(block); err != nil {
+			return err
+		}
+		if i == 0 {
+			continue
+		}
+
+		previous := blocks[i-1]
+		if block.Header.Number != previous.Header.Number+1 {
+			return errors.Errorf("block [%d] does not follow block [%d]", block.Header.Number, previous.Header.Number)
+		}
+
+		previousHash, err := BlockHeaderHash(previous.Header)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(block.Header.PreviousHash, previousHash) {
+			return errors.Errorf("previous hash of block [%d] does not match the header hash of block [%d]", block.Header.Number, previous.Header.Number)
+		}
+	}
+	return nil
+}
+
+type asn1Header struct {
+	Number       *big.Int
+	PreviousHash []byte
+	DataHash     []byte
+}
+
+// BlockHeaderBytes returns the ASN.1 encoding of the block header, which is what the orderer signs
+func BlockHeaderBytes(header *common.BlockHeader) ([]byte, error) {
+	headerBytes, err := asn1.Marshal(asn1Header{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "ASN.1 marshal of block header failed")
+	}
+	return headerBytes, nil
+}
+
+// BlockHeaderHash returns the hash of the block header, which is the previous hash of the next block
+func BlockHeaderHash(header *common.BlockHeader) ([]byte, error) {
+	headerBytes, err := BlockHeaderBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(headerBytes)
+	return hash[:], nil
+}
+
+// BlockDataHash returns the hash of the block data
+func BlockDataHash(data *common.BlockData) []byte {
+	var dataBytes []byte
+	if data != nil {
+		dataBytes = bytes.Join(data.Data, nil)
+	}
+	hash := sha256.Sum256(dataBytes)
+	return hash[:]
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package verifier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ordererID  = utils.MarshalOrPanic(&mb.SerializedIdentity{Mspid: "OrdererMSP", IdBytes: []byte("orderercert")})
+	endorserID = utils.MarshalOrPanic(&mb.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("endorsercert")})
+)
+
+func TestVerifyHashChain(t *testing.T) {
+	block0 := newTestBlock(t, 0, nil)
+	block1 := newTestBlock(t, 1, block0)
+	block2 := newTestBlock(t, 2, block1)
+
+	assert.NoError(t, VerifyHashChain(block0, block1, block2))
+	assert.Error(t, VerifyHashChain(block0, block2), "expecting error for missing block")
+
+	block2.Header.PreviousHash = []byte("invalid")
+	assert.Error(t, VerifyHashChain(block1, block2), "expecting error for invalid previous hash")
+
+	block1.Data.Data = append(block1.Data.Data, []byte("extra"))
+	assert.Error(t, VerifyHashChain(block0, block1), "expecting error for invalid data hash")
+}
+
+func TestVerifyBlock(t *testing.T) {
+	block := newTestBlock(t, 1, nil)
+
+	v := &Block{Membership: &hashMembership{}, OrdererMSPIDs: []string{"OrdererMSP"}}
+	assert.NoError(t, v.Verify(block))
+
+	v = &Block{Membership: &hashMembership{}, OrdererMSPIDs: []string{"OtherOrdererMSP"}}
+	assert.Error(t, v.VerifyOrdererSignatures(block), "expecting error for signer that isn't an orderer")
+
+	v = &Block{Membership: &hashMembership{invalidID: endorserID}}
+	assert.NoError(t, v.VerifyOrdererSignatures(block))
+	assert.Error(t, v.VerifyEndorsements(block), "expecting error for invalid endorser")
+
+	block.Header.Number = 2
+	v = &Block{Membership: &hashMembership{}}
+	assert.Error(t, v.VerifyOrdererSignatures(block), "expecting error for modified header")
+}
+
+func TestVerifyEndorsementsSkipsInvalidTransactions(t *testing.T) {
+	block := newTestBlock(t, 1, nil)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER][0] = uint8(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+
+	v := &Block{Membership: &hashMembership{invalidID: endorserID}}
+	assert.NoError(t, v.VerifyEndorsements(block))
+}
+
+// hashMembership accepts signatures that are the SHA256 hash of the message
+type hashMembership struct {
+	invalidID []byte
+}
+
+func (m *hashMembership) Validate(serializedID []byte) error {
+	if m.invalidID != nil && bytes.Equal(serializedID, m.invalidID) {
+		return errors.New("invalid identity")
+	}
+	return nil
+}
+
+func (m *hashMembership) Verify(serializedID []byte, msg []byte, sig []byte) error {
+	if !bytes.Equal(sign(msg), sig) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func sign(msg []byte) []byte {
+	hash := sha256.Sum256(msg)
+	return hash[:]
+}
+
+func newTestBlock(t *testing.T, number uint64, previous *common.Block) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number},
+		Data:     &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(newTestEnvelope(t))}},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+	block.Header.DataHash = BlockDataHash(block.Data)
+
+	if previous != nil {
+		previousHash, err := BlockHeaderHash(previous.Header)
+		require.NoError(t, err)
+		block.Header.PreviousHash = previousHash
+	}
+
+	headerBytes, err := BlockHeaderBytes(block.Header)
+	require.NoError(t, err)
+
+	sigHeader := utils.MarshalOrPanic(&common.SignatureHeader{Creator: ordererID, Nonce: []byte("nonce")})
+	metadata := &common.Metadata{
+		Value: []byte("value"),
+		Signatures: []*common.MetadataSignature{
+			{
+				SignatureHeader: sigHeader,
+				Signature:       sign(bytes.Join([][]byte{[]byte("value"), sigHeader, headerBytes}, nil)),
+			},
+		},
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(metadata)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{uint8(pb.TxValidationCode_VALID)}
+
+	return block
+}
+
+func newTestEnvelope(t *testing.T) *common.Envelope {
+	prp := []byte("proposal response payload")
+
+	capBytes, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{
+			ProposalResponsePayload: prp,
+			Endorsements: []*pb.Endorsement{
+				{
+					Endorser:  endorserID,
+					Signature: sign(append(append([]byte{}, prp...), endorserID...)),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	txBytes, err := utils.GetBytesTransaction(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: capBytes}}})
+	require.NoError(t, err)
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+				Type: int32(common.HeaderType_ENDORSER_TRANSACTION),
+				TxId: "tx1",
+			}),
+		},
+		Data: txBytes,
+	}
+
+	return &common.Envelope{Payload: utils.MarshalOrPanic(payload)}
+}
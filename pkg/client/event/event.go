@@ -67,6 +67,7 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 
 // RegisterBlockEvent registers for block events. If the caller does not have permission
 // to register for block events then an error is returned. Unregister must be called when the registration is no longer needed.
+// The events are shared with the other registrations and must not be modified (see fab.BlockEvent).
 //  Parameters:
 //  filter is an optional filter that filters out unwanted events. (Note: Only one filter may be specified.)
 //
@@ -77,6 +78,7 @@ func (c *Client) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration
 }
 
 // RegisterFilteredBlockEvent registers for filtered block events. Unregister must be called when the registration is no longer needed.
+// The events are shared with the other registrations and must not be modified (see fab.FilteredBlockEvent).
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
@@ -84,6 +86,7 @@ func (c *Client) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.Fil
 }
 
 // RegisterChaincodeEvent registers for chaincode events. Unregister must be called when the registration is no longer needed.
+// The events are shared with the other registrations and must not be modified (see fab.CCEvent).
 //  Parameters:
 //  ccID is the chaincode ID for which events are to be received
 //  eventFilter is the chaincode event filter (regular expression) for which events are to be received
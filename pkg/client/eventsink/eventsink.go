@@ -0,0 +1,257 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package eventsink publishes the block and chaincode events received from the event client to a
+// message broker such as Kafka or NATS, so that applications can consume the events of a channel
+// without connecting to its peers.
+//
+// The sink doesn't depend on a particular broker client. Messages are handed to a Publisher with
+// a topic, a partition key and a value. A Kafka producer is adapted with a PublisherFunc that sends
+// a message with the given topic, key and value, so that the events with the same key are written
+// to the same partition and their order is preserved. A NATS connection is adapted with
+// NewNATSPublisher, which appends the key to the subject.
+//
+// Events are forwarded from the channels returned by the event client's registration functions
+// (see Sink.ForwardBlockEvents and Sink.ForwardChaincodeEvents) or published one at a time.
+package eventsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// Publisher publishes a message to a topic of a message broker
+type Publisher interface {
+	Publish(topic string, key, value []byte) error
+}
+
+// PublisherFunc is an adapter that allows a function, e.g. one that sends a message with a Kafka
+// producer, to be used as a Publisher
+type PublisherFunc func(topic string, key, value []byte) error
+
+// Publish calls f(topic, key, value)
+func (f PublisherFunc) Publish(topic string, key, value []byte) error {
+	return f(topic, key, value)
+}
+
+// NATSConn publishes a message to a NATS subject. It is implemented by the connection of the NATS client.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NewNATSPublisher returns a publisher that publishes the messages to the given NATS connection.
+// Since NATS has no partitions, the key is appended to the topic as the last token of the subject,
+// with the characters that aren't valid in a subject token replaced by underscores. The messages are
+// published to the topic itself if the key is empty.
+func NewNATSPublisher(conn NATSConn) Publisher {
+	return PublisherFunc(func(topic string, key, value []byte) error {
+		subject := topic
+		if len(key) > 0 {
+			subject = topic + "." + natsToken(string(key))
+		}
+		return conn.Publish(subject, value)
+	})
+}
+
+func natsToken(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', ' ', '\t', '\r', '\n':
+			return '_'
+		default:
+			return r
+		}
+	}, s)
+}
+
+// Serialization is the format of the published events
+type Serialization int
+
+const (
+	// SerializeJSON publishes the events as JSON. Blocks are serialized with the protobuf JSON
+	// mapping and chaincode events as a JSON object that includes the block number.
+	SerializeJSON Serialization = iota
+	// SerializeProto publishes blocks as common.Block and chaincode events as peer.ChaincodeEvent
+	// protobuf messages. The block number of a chaincode event isn't included.
+	SerializeProto
+)
+
+// Partitioning selects the key with which the events are published
+type Partitioning int
+
+const (
+	// PartitionByChannel publishes all the events with the channel ID as the key, so that the order
+	// of the events of the channel is preserved
+	PartitionByChannel Partitioning = iota
+	// PartitionByKey publishes chaincode events with the chaincode ID and event name as the key, so
+	// that the order is preserved for each chaincode event name only. Block events are published
+	// with the block number as the key.
+	PartitionByKey
+)
+
+// Sink publishes the events of a channel.
+//
+// This component has been designed to be safe for concurrency.
+type Sink struct {
+	channelID         string
+	publisher         Publisher
+	serialization     Serialization
+	partitioning      Partitioning
+	blockTopic        string
+	chaincodeEvtTopic string
+}
+
+// New returns a sink that publishes the events of the given channel. By default the events are
+// serialized as JSON and partitioned by channel, and block and chaincode events are published to the
+// "<channel>.blocks" and "<channel>.chaincodeevents" topics respectively.
+func New(channelID string, publisher Publisher, opts ...Option) (*Sink, error) {
+	if channelID == "" || publisher == nil {
+		return nil, errors.New("channel ID and publisher are required")
+	}
+
+	s := &Sink{
+		channelID:         channelID,
+		publisher:         publisher,
+		serialization:     SerializeJSON,
+		partitioning:      PartitionByChannel,
+		blockTopic:        channelID + ".blocks",
+		chaincodeEvtTopic: channelID + ".chaincodeevents",
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, errors.WithMessage(err, "option failed")
+		}
+	}
+	return s, nil
+}
+
+// ForwardBlockEvents publishes the block events received on the given channel until the channel is
+// closed (i.e. until the registration is unregistered from the event client). It returns the error
+// if an event can't be published; the remaining events aren't forwarded.
+func (s *Sink) ForwardBlockEvents(eventch <-chan *fab.BlockEvent) error {
+	for event := range eventch {
+		if err := s.PublishBlockEvent(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForwardChaincodeEvents publishes the chaincode events received on the given channel until the
+// channel is closed (i.e. until the registration is unregistered from the event client). It returns
+// the error if an event can't be published; the remaining events aren't forwarded.
+func (s *Sink) ForwardChaincodeEvents(eventch <-chan *fab.CCEvent) error {
+	for event := range eventch {
+		if err := s.PublishChaincodeEvent(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PublishBlockEvent publishes a block event
+func (s *Sink) PublishBlockEvent(event *fab.BlockEvent) error {
+	if event == nil || event.Block == nil || event.Block.Header == nil {
+		return errors.New("block event has no block")
+	}
+	number := event.Block.Header.Number
+
+	var value []byte
+	var err error
+	switch s.serialization {
+	case SerializeProto:
+		value, err = proto.Marshal(event.Block)
+	default:
+		var block string
+		block, err = (&jsonpb.Marshaler{}).MarshalToString(event.Block)
+		if err == nil {
+			value, err = json.Marshal(&blockMessage{
+				ChannelID: s.channelID,
+				Number:    number,
+				SourceURL: event.SourceURL,
+				Block:     json.RawMessage(block),
+			})
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("serializing block %d failed", number))
+	}
+
+	key := s.channelID
+	if s.partitioning == PartitionByKey {
+		key = strconv.FormatUint(number, 10)
+	}
+	if err := s.publisher.Publish(s.blockTopic, []byte(key), value); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("publishing block %d failed", number))
+	}
+	return nil
+}
+
+// PublishChaincodeEvent publishes a chaincode event
+func (s *Sink) PublishChaincodeEvent(event *fab.CCEvent) error {
+	if event == nil {
+		return errors.New("chaincode event is nil")
+	}
+
+	var value []byte
+	var err error
+	switch s.serialization {
+	case SerializeProto:
+		value, err = proto.Marshal(&pb.ChaincodeEvent{
+			ChaincodeId: event.ChaincodeID,
+			TxId:        event.TxID,
+			EventName:   event.EventName,
+			Payload:     event.Payload,
+		})
+	default:
+		value, err = json.Marshal(&chaincodeEventMessage{
+			ChannelID:   s.channelID,
+			ChaincodeID: event.ChaincodeID,
+			EventName:   event.EventName,
+			TxID:        event.TxID,
+			BlockNumber: event.BlockNumber,
+			Payload:     event.Payload,
+			SourceURL:   event.SourceURL,
+		})
+	}
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("serializing chaincode event of transaction [%s] failed", event.TxID))
+	}
+
+	key := s.channelID
+	if s.partitioning == PartitionByKey {
+		key = event.ChaincodeID + ":" + event.EventName
+	}
+	if err := s.publisher.Publish(s.chaincodeEvtTopic, []byte(key), value); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("publishing chaincode event of transaction [%s] failed", event.TxID))
+	}
+	return nil
+}
+
+type blockMessage struct {
+	ChannelID string          `json:"channelId"`
+	Number    uint64          `json:"number"`
+	SourceURL string          `json:"sourceUrl,omitempty"`
+	Block     json.RawMessage `json:"block"`
+}
+
+type chaincodeEventMessage struct {
+	ChannelID   string `json:"channelId"`
+	ChaincodeID string `json:"chaincodeId"`
+	EventName   string `json:"eventName"`
+	TxID        string `json:"txId"`
+	BlockNumber uint64 `json:"blockNumber"`
+	Payload     []byte `json:"payload,omitempty"`
+	SourceURL   string `json:"sourceUrl,omitempty"`
+}
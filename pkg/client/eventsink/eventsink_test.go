@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventsink
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type message struct {
+	topic string
+	key   string
+	value []byte
+}
+
+type mockPublisher struct {
+	messages []message
+	err      error
+}
+
+func (p *mockPublisher) Publish(topic string, key, value []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, message{topic: topic, key: string(key), value: value})
+	return nil
+}
+
+func TestPublishJSON(t *testing.T) {
+	publisher := &mockPublisher{}
+	sink, err := New("mychannel", publisher)
+	require.NoError(t, err)
+
+	ccEvent := &fab.CCEvent{TxID: "tx1", ChaincodeID: "mycc", EventName: "transfer", Payload: []byte("payload"), BlockNumber: 7}
+	require.NoError(t, sink.PublishChaincodeEvent(ccEvent))
+	require.NoError(t, sink.PublishBlockEvent(&fab.BlockEvent{Block: &common.Block{Header: &common.BlockHeader{Number: 7}}}))
+
+	require.Len(t, publisher.messages, 2)
+	assert.Equal(t, "mychannel.chaincodeevents", publisher.messages[0].topic)
+	assert.Equal(t, "mychannel", publisher.messages[0].key)
+	decoded := &chaincodeEventMessage{}
+	require.NoError(t, json.Unmarshal(publisher.messages[0].value, decoded))
+	assert.Equal(t, &chaincodeEventMessage{ChannelID: "mychannel", ChaincodeID: "mycc", EventName: "transfer", TxID: "tx1", BlockNumber: 7, Payload: []byte("payload")}, decoded)
+
+	assert.Equal(t, "mychannel.blocks", publisher.messages[1].topic)
+	block := &blockMessage{}
+	require.NoError(t, json.Unmarshal(publisher.messages[1].value, block))
+	assert.Equal(t, uint64(7), block.Number)
+	assert.NotEmpty(t, block.Block)
+}
+
+func TestPublishProto(t *testing.T) {
+	publisher := &mockPublisher{}
+	sink, err := New("mychannel", publisher, WithSerialization(SerializeProto), WithPartitioning(PartitionByKey),
+		WithBlockTopic("blocks"), WithChaincodeEventTopic("events"))
+	require.NoError(t, err)
+
+	require.NoError(t, sink.PublishChaincodeEvent(&fab.CCEvent{TxID: "tx1", ChaincodeID: "mycc", EventName: "transfer"}))
+	require.NoError(t, sink.PublishBlockEvent(&fab.BlockEvent{Block: &common.Block{Header: &common.BlockHeader{Number: 7}}}))
+
+	require.Len(t, publisher.messages, 2)
+	assert.Equal(t, message{topic: "events", key: "mycc:transfer", value: publisher.messages[0].value}, publisher.messages[0])
+	ccEvent := &pb.ChaincodeEvent{}
+	require.NoError(t, proto.Unmarshal(publisher.messages[0].value, ccEvent))
+	assert.Equal(t, "tx1", ccEvent.TxId)
+
+	assert.Equal(t, "blocks", publisher.messages[1].topic)
+	assert.Equal(t, "7", publisher.messages[1].key)
+	block := &common.Block{}
+	require.NoError(t, proto.Unmarshal(publisher.messages[1].value, block))
+	assert.Equal(t, uint64(7), block.Header.Number)
+}
+
+func TestForwardEvents(t *testing.T) {
+	publisher := &mockPublisher{}
+	sink, err := New("mychannel", publisher)
+	require.NoError(t, err)
+
+	eventch := make(chan *fab.CCEvent, 2)
+	eventch <- &fab.CCEvent{TxID: "tx1"}
+	eventch <- &fab.CCEvent{TxID: "tx2"}
+	close(eventch)
+	require.NoError(t, sink.ForwardChaincodeEvents(eventch))
+	assert.Len(t, publisher.messages, 2)
+
+	publisher.err = errors.New("broker unavailable")
+	blockch := make(chan *fab.BlockEvent, 1)
+	blockch <- &fab.BlockEvent{Block: &common.Block{Header: &common.BlockHeader{Number: 1}}}
+	close(blockch)
+	assert.Error(t, sink.ForwardBlockEvents(blockch))
+
+	assert.Error(t, sink.PublishBlockEvent(&fab.BlockEvent{}), "expecting error for missing block")
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("", &mockPublisher{})
+	assert.Error(t, err)
+	_, err = New("mychannel", nil)
+	assert.Error(t, err)
+	_, err = New("mychannel", &mockPublisher{}, WithSerialization(Serialization(5)))
+	assert.Error(t, err)
+	_, err = New("mychannel", &mockPublisher{}, WithBlockTopic(""))
+	assert.Error(t, err)
+}
+
+type mockNATSConn struct {
+	subjects []string
+}
+
+func (c *mockNATSConn) Publish(subject string, data []byte) error {
+	c.subjects = append(c.subjects, subject)
+	return nil
+}
+
+func TestNATSPublisher(t *testing.T) {
+	conn := &mockNATSConn{}
+	publisher := NewNATSPublisher(conn)
+
+	require.NoError(t, publisher.Publish("mychannel.chaincodeevents", []byte("mycc:a.b*c"), nil))
+	require.NoError(t, publisher.Publish("mychannel.blocks", nil, nil))
+	assert.Equal(t, []string{"mychannel.chaincodeevents.mycc:a_b_c", "mychannel.blocks"}, conn.subjects)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventsink
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Option describes a functional parameter for the New function
+type Option func(*Sink) error
+
+// WithSerialization sets the format of the published events (SerializeJSON by default)
+func WithSerialization(serialization Serialization) Option {
+	return func(s *Sink) error {
+		if serialization != SerializeJSON && serialization != SerializeProto {
+			return errors.Errorf("unsupported serialization: %d", serialization)
+		}
+		s.serialization = serialization
+		return nil
+	}
+}
+
+// WithPartitioning sets the key with which the events are published (PartitionByChannel by default)
+func WithPartitioning(partitioning Partitioning) Option {
+	return func(s *Sink) error {
+		if partitioning != PartitionByChannel && partitioning != PartitionByKey {
+			return errors.Errorf("unsupported partitioning: %d", partitioning)
+		}
+		s.partitioning = partitioning
+		return nil
+	}
+}
+
+// WithBlockTopic sets the topic to which the block events are published
+func WithBlockTopic(topic string) Option {
+	return func(s *Sink) error {
+		if topic == "" {
+			return errors.New("block topic is empty")
+		}
+		s.blockTopic = topic
+		return nil
+	}
+}
+
+// WithChaincodeEventTopic sets the topic to which the chaincode events are published
+func WithChaincodeEventTopic(topic string) Option {
+	return func(s *Sink) error {
+		if topic == "" {
+			return errors.New("chaincode event topic is empty")
+		}
+		s.chaincodeEvtTopic = topic
+		return nil
+	}
+}
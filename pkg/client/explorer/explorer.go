@@ -0,0 +1,350 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package explorer provides the read-only queries of a block explorer on a channel, such as the latest
+// blocks, the search of transactions by a prefix of their ID and the number of transactions of each
+// chaincode, so that lightweight explorer backends can be built directly on the SDK.
+//
+// The explorer combines a ledger client and an event client. It keeps an in-memory index of the most
+// recent blocks of the channel: the index is filled from the ledger when the explorer is created and
+// kept up to date from the block events. Searches and counts cover the indexed blocks. The event client
+// must be created with block events, and the explorer must be closed when it is no longer needed.
+package explorer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// LedgerClient queries the ledger of a channel. It is implemented by ledger.Client.
+type LedgerClient interface {
+	QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error)
+	QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error)
+}
+
+// EventClient receives the block events of a channel. It is implemented by event.Client, which must
+// be created with block events (event.WithBlockEvents).
+type EventClient interface {
+	RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error)
+	Unregister(reg fab.Registration)
+}
+
+// BlockSummary summarizes a block
+type BlockSummary struct {
+	Number       uint64
+	PreviousHash []byte
+	DataHash     []byte
+	Transactions []*TxSummary
+}
+
+// TxSummary summarizes a transaction
+type TxSummary struct {
+	TxID        string
+	BlockNumber uint64
+	// Index is the index of the transaction within the block
+	Index     int
+	Type      common.HeaderType
+	Timestamp time.Time
+	// CreatorMSPID is the MSP ID of the client that submitted the transaction
+	CreatorMSPID string
+	// Chaincodes are the names of the chaincodes invoked by the transaction (empty for config transactions)
+	Chaincodes     []string
+	ValidationCode pb.TxValidationCode
+}
+
+// TxCount is the number of transactions of a chaincode
+type TxCount struct {
+	Valid   uint64
+	Invalid uint64
+}
+
+// Explorer queries a channel like a block explorer.
+//
+// This component has been designed to be safe for concurrency.
+type Explorer struct {
+	ledger       LedgerClient
+	events       EventClient
+	indexSize    int
+	reqOpts      []ledger.RequestOption
+	registration fab.Registration
+	done         chan struct{}
+	wg           sync.WaitGroup
+	closeOnce    sync.Once
+
+	lock   sync.RWMutex
+	blocks []*BlockSummary
+	txs    map[string]*TxSummary
+}
+
+// New returns an explorer of the channel of the given clients. The most recent blocks of the channel
+// are indexed before New returns.
+func New(ledgerClient LedgerClient, eventClient EventClient, opts ...Option) (*Explorer, error) {
+	if ledgerClient == nil || eventClient == nil {
+		return nil, errors.New("ledger client and event client are required")
+	}
+
+	e := &Explorer{
+		ledger:    ledgerClient,
+		events:    eventClient,
+		indexSize: defaultIndexSize,
+		done:      make(chan struct{}),
+		txs:       make(map[string]*TxSummary),
+	}
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, errors.WithMessage(err, "option failed")
+		}
+	}
+
+	// Register for the block events before the index is filled so that no block is missed
+	reg, eventch, err := eventClient.RegisterBlockEvent()
+	if err != nil {
+		return nil, errors.WithMessage(err, "block event registration failed")
+	}
+	e.registration = reg
+
+	e.wg.Add(1)
+	go e.listen(eventch)
+
+	if err := e.fillIndex(); err != nil {
+		e.Close()
+		return nil, errors.WithMessage(err, "indexing the latest blocks failed")
+	}
+	return e, nil
+}
+
+// Close stops indexing the new blocks
+func (e *Explorer) Close() {
+	e.closeOnce.Do(func() {
+		close(e.done)
+		e.events.Unregister(e.registration)
+		e.wg.Wait()
+	})
+}
+
+// Height returns the height of the chain as seen by the explorer, i.e. the number of the latest
+// indexed block plus one
+func (e *Explorer) Height() uint64 {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	if len(e.blocks) == 0 {
+		return 0
+	}
+	return e.blocks[len(e.blocks)-1].Number + 1
+}
+
+// LatestBlocks returns up to n of the latest blocks, the latest first. The blocks that are older than the
+// indexed blocks are queried from the ledger.
+func (e *Explorer) LatestBlocks(n int) ([]*BlockSummary, error) {
+	if n <= 0 {
+		return nil, errors.New("number of blocks must be greater than zero")
+	}
+
+	e.lock.RLock()
+	var blocks []*BlockSummary
+	for i := len(e.blocks) - 1; i >= 0 && len(blocks) < n; i-- {
+		blocks = append(blocks, e.blocks[i])
+	}
+	e.lock.RUnlock()
+
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	for number := blocks[len(blocks)-1].Number; len(blocks) < n && number > 0; {
+		number--
+		summary, err := e.queryBlock(number)
+		if err != nil {
+			return blocks, err
+		}
+		blocks = append(blocks, summary)
+	}
+	return blocks, nil
+}
+
+// FindTransactions returns up to limit indexed transactions whose ID starts with the given prefix, the
+// latest first. All of the matching transactions are returned if limit is zero.
+func (e *Explorer) FindTransactions(prefix string, limit int) []*TxSummary {
+	e.lock.RLock()
+	var txs []*TxSummary
+	for txID, tx := range e.txs {
+		if strings.HasPrefix(txID, prefix) {
+			txs = append(txs, tx)
+		}
+	}
+	e.lock.RUnlock()
+
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].BlockNumber != txs[j].BlockNumber {
+			return txs[i].BlockNumber > txs[j].BlockNumber
+		}
+		return txs[i].Index > txs[j].Index
+	})
+	if limit > 0 && len(txs) > limit {
+		txs = txs[:limit]
+	}
+	return txs
+}
+
+// ChaincodeTxCounts returns the number of indexed transactions of each chaincode, by chaincode name. A
+// transaction that invokes several chaincodes is counted for each of them.
+func (e *Explorer) ChaincodeTxCounts() map[string]TxCount {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	counts := make(map[string]TxCount)
+	for _, block := range e.blocks {
+		for _, tx := range block.Transactions {
+			for _, cc := range tx.Chaincodes {
+				count := counts[cc]
+				if tx.ValidationCode == pb.TxValidationCode_VALID {
+					count.Valid++
+				} else {
+					count.Invalid++
+				}
+				counts[cc] = count
+			}
+		}
+	}
+	return counts
+}
+
+func (e *Explorer) fillIndex() error {
+	info, err := e.ledger.QueryInfo(e.reqOpts...)
+	if err != nil {
+		return err
+	}
+
+	height := info.BCI.Height
+	from := uint64(0)
+	if height > uint64(e.indexSize) {
+		from = height - uint64(e.indexSize)
+	}
+	for number := from; number < height; number++ {
+		summary, err := e.queryBlock(number)
+		if err != nil {
+			return err
+		}
+		e.index(summary)
+	}
+	return nil
+}
+
+func (e *Explorer) listen(eventch <-chan *fab.BlockEvent) {
+	defer e.wg.Done()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case event, ok := <-eventch:
+			if !ok {
+				return
+			}
+			summary, err := summarizeBlock(event.Block)
+			if err != nil {
+				logger.Warnf("Block from [%s] isn't indexed: %s", event.SourceURL, err)
+				continue
+			}
+			e.index(summary)
+		}
+	}
+}
+
+func (e *Explorer) queryBlock(number uint64) (*BlockSummary, error) {
+	block, err := e.ledger.QueryBlock(number, e.reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return summarizeBlock(block)
+}
+
+// index adds a block to the index, evicting the oldest blocks if the index is full
+func (e *Explorer) index(summary *BlockSummary) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	i := sort.Search(len(e.blocks), func(i int) bool { return e.blocks[i].Number >= summary.Number })
+	if i < len(e.blocks) && e.blocks[i].Number == summary.Number {
+		return
+	}
+	if len(e.blocks) >= e.indexSize && i == 0 {
+		// The block is older than the indexed blocks
+		return
+	}
+
+	e.blocks = append(e.blocks, nil)
+	copy(e.blocks[i+1:], e.blocks[i:])
+	e.blocks[i] = summary
+	for _, tx := range summary.Transactions {
+		if tx.TxID != "" {
+			e.txs[tx.TxID] = tx
+		}
+	}
+
+	for len(e.blocks) > e.indexSize {
+		for _, tx := range e.blocks[0].Transactions {
+			if e.txs[tx.TxID] == tx {
+				delete(e.txs, tx.TxID)
+			}
+		}
+		e.blocks[0] = nil
+		e.blocks = e.blocks[1:]
+	}
+}
+
+func summarizeBlock(block *common.Block) (*BlockSummary, error) {
+	decoded, err := ledger.DecodeBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &BlockSummary{
+		Number:       decoded.Number,
+		PreviousHash: decoded.PreviousHash,
+		DataHash:     decoded.DataHash,
+	}
+	for i, tx := range decoded.Transactions {
+		txSummary := &TxSummary{
+			TxID:           tx.TxID,
+			BlockNumber:    decoded.Number,
+			Index:          i,
+			Type:           tx.Type,
+			Timestamp:      tx.Timestamp,
+			Chaincodes:     chaincodeNames(tx),
+			ValidationCode: tx.ValidationCode,
+		}
+		if tx.Creator != nil {
+			txSummary.CreatorMSPID = tx.Creator.MSPID
+		}
+		summary.Transactions = append(summary.Transactions, txSummary)
+	}
+	return summary, nil
+}
+
+func chaincodeNames(tx *ledger.Transaction) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, action := range tx.Actions {
+		if action.ChaincodeID == nil || action.ChaincodeID.Name == "" || seen[action.ChaincodeID.Name] {
+			continue
+		}
+		seen[action.ChaincodeID.Name] = true
+		names = append(names, action.ChaincodeID.Name)
+	}
+	return names
+}
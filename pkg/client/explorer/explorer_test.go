@@ -0,0 +1,197 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package explorer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/event"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ LedgerClient = (*ledger.Client)(nil)
+var _ EventClient = (*event.Client)(nil)
+
+func TestExplorer(t *testing.T) {
+	ledgerClient := newMockLedger()
+	ledgerClient.add(newTestBlock(0, testTx{"aa01", "cc1", pb.TxValidationCode_VALID}))
+	ledgerClient.add(newTestBlock(1, testTx{"ab02", "cc1", pb.TxValidationCode_VALID}))
+	ledgerClient.add(newTestBlock(2, testTx{"ab03", "cc2", pb.TxValidationCode_MVCC_READ_CONFLICT}))
+	eventClient := &mockEventClient{eventch: make(chan *fab.BlockEvent, 10)}
+
+	e, err := New(ledgerClient, eventClient, WithIndexSize(2))
+	require.NoError(t, err)
+	defer e.Close()
+	assert.Equal(t, uint64(3), e.Height())
+
+	// Only the two latest blocks are indexed
+	assert.Empty(t, e.FindTransactions("aa", 0))
+	txs := e.FindTransactions("ab", 0)
+	require.Len(t, txs, 2)
+	assert.Equal(t, "ab03", txs[0].TxID)
+	assert.Equal(t, "ab02", txs[1].TxID)
+	assert.Equal(t, []string{"cc2"}, txs[0].Chaincodes)
+	assert.Equal(t, "Org1MSP", txs[0].CreatorMSPID)
+	assert.Len(t, e.FindTransactions("ab", 1), 1)
+
+	assert.Equal(t, map[string]TxCount{"cc1": {Valid: 1}, "cc2": {Invalid: 1}}, e.ChaincodeTxCounts())
+
+	// Older blocks are queried from the ledger
+	blocks, err := e.LatestBlocks(5)
+	require.NoError(t, err)
+	require.Len(t, blocks, 3)
+	assert.Equal(t, uint64(2), blocks[0].Number)
+	assert.Equal(t, uint64(0), blocks[2].Number)
+	_, err = e.LatestBlocks(0)
+	assert.Error(t, err)
+
+	// New blocks are indexed from the block events
+	eventClient.eventch <- &fab.BlockEvent{Block: newTestBlock(3, testTx{"ac04", "cc1", pb.TxValidationCode_VALID})}
+	require.True(t, waitFor(func() bool { return e.Height() == 4 }), "expecting block 3 to be indexed")
+	assert.Len(t, e.FindTransactions("ac", 0), 1)
+	assert.Len(t, e.FindTransactions("ab", 0), 1, "expecting block 1 to be evicted")
+	assert.Equal(t, map[string]TxCount{"cc1": {Valid: 1}, "cc2": {Invalid: 1}}, e.ChaincodeTxCounts())
+
+	e.Close()
+	assert.True(t, eventClient.unregistered)
+}
+
+func TestExplorerErrors(t *testing.T) {
+	_, err := New(nil, &mockEventClient{})
+	assert.Error(t, err)
+
+	_, err = New(newMockLedger(), &mockEventClient{}, WithIndexSize(0))
+	assert.Error(t, err)
+
+	_, err = New(newMockLedger(), &mockEventClient{err: errors.New("permission denied")})
+	assert.Error(t, err)
+
+	ledgerClient := newMockLedger()
+	ledgerClient.add(newTestBlock(0))
+	ledgerClient.height = 2
+	eventClient := &mockEventClient{eventch: make(chan *fab.BlockEvent)}
+	_, err = New(ledgerClient, eventClient)
+	assert.Error(t, err, "expecting error for missing block")
+	assert.True(t, eventClient.unregistered)
+}
+
+type mockLedger struct {
+	blocks map[uint64]*common.Block
+	height uint64
+}
+
+func newMockLedger() *mockLedger {
+	return &mockLedger{blocks: make(map[uint64]*common.Block)}
+}
+
+func (l *mockLedger) add(block *common.Block) {
+	l.blocks[block.Header.Number] = block
+	l.height = block.Header.Number + 1
+}
+
+func (l *mockLedger) QueryInfo(options ...ledger.RequestOption) (*fab.BlockchainInfoResponse, error) {
+	return &fab.BlockchainInfoResponse{BCI: &common.BlockchainInfo{Height: l.height}}, nil
+}
+
+func (l *mockLedger) QueryBlock(blockNumber uint64, options ...ledger.RequestOption) (*common.Block, error) {
+	block, ok := l.blocks[blockNumber]
+	if !ok {
+		return nil, errors.Errorf("block %d not found", blockNumber)
+	}
+	return block, nil
+}
+
+type mockEventClient struct {
+	lock         sync.Mutex
+	eventch      chan *fab.BlockEvent
+	err          error
+	unregistered bool
+}
+
+func (c *mockEventClient) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	if c.err != nil {
+		return nil, nil, c.err
+	}
+	return "registration", c.eventch, nil
+}
+
+func (c *mockEventClient) Unregister(reg fab.Registration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.unregistered = true
+}
+
+type testTx struct {
+	txID           string
+	chaincode      string
+	validationCode pb.TxValidationCode
+}
+
+func newTestBlock(number uint64, txs ...testTx) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number, DataHash: []byte(fmt.Sprintf("hash%d", number))},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+	flags := ledgerutil.NewTxValidationFlags(len(txs))
+	for i, tx := range txs {
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(newTestEnvelope(tx)))
+		flags[i] = uint8(tx.validationCode)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func newTestEnvelope(tx testTx) *common.Envelope {
+	prpBytes, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &pb.Response{Status: 200}, nil, nil, &pb.ChaincodeID{Name: tx.chaincode})
+	if err != nil {
+		panic(err)
+	}
+	capBytes, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prpBytes},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+				Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+				ChannelId: "mychannel",
+				TxId:      tx.txID,
+			}),
+			SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{
+				Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("creatorcert")}),
+			}),
+		},
+		Data: utils.MarshalOrPanic(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: capBytes}}}),
+	}
+	return &common.Envelope{Payload: utils.MarshalOrPanic(payload)}
+}
+
+func waitFor(condition func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if condition() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package explorer
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/pkg/errors"
+)
+
+const defaultIndexSize = 1000
+
+// Option describes a functional parameter for the New constructor
+type Option func(*Explorer) error
+
+// WithIndexSize sets the number of the most recent blocks that are indexed (1000 by default)
+func WithIndexSize(size int) Option {
+	return func(e *Explorer) error {
+		if size <= 0 {
+			return errors.New("index size must be greater than zero")
+		}
+		e.indexSize = size
+		return nil
+	}
+}
+
+// WithRequestOptions sets the request options (targets, timeouts, etc.) of the ledger queries
+func WithRequestOptions(options ...ledger.RequestOption) Option {
+	return func(e *Explorer) error {
+		e.reqOpts = options
+		return nil
+	}
+}
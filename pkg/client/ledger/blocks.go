@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	reqContext "context"
+	"io"
+	"sync"
+	"time"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	clientdisp "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
+	deliverconn "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// deliverConnection is a connection to a peer's Deliver service
+type deliverConnection interface {
+	Send(seekInfo *ab.SeekInfo) error
+	Receive(eventch chan<- interface{})
+	Close()
+}
+
+// deliverConnectionProvider is overridden by unit tests
+var deliverConnectionProvider = func(ctx context.Client, chConfig fab.ChannelCfg, peer fab.Peer) (deliverConnection, error) {
+	peerConfig, err := ctx.EndpointConfig().PeerConfig(peer.URL())
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to get peer config")
+	}
+	eventEndpoint, err := endpoint.FromPeerConfig(ctx.EndpointConfig(), peer, peerConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to create event endpoint")
+	}
+	return deliverconn.New(ctx, chConfig, deliverconn.Deliver, peer.URL(), eventEndpoint.Opts()...)
+}
+
+// QueryBlocks streams the blocks in the given range (inclusive) from a single target peer
+// using the peer's Deliver service. This is more efficient than querying each block
+// individually when scanning large parts of the chain.
+//
+// The returned iterator must be closed when it is no longer needed.
+func (c *Client) QueryBlocks(fromBlock, toBlock uint64, options ...RequestOption) (*BlockIterator, error) {
+	if fromBlock > toBlock {
+		return nil, errors.Errorf("invalid block range [%d, %d]", fromBlock, toBlock)
+	}
+
+	targets, opts, err := c.prepareRequestParams(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryBlocks failed to prepare request parameters")
+	}
+
+	chConfig, err := c.ctx.ChannelService().ChannelConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryBlocks failed to get channel config")
+	}
+
+	conn, err := deliverConnectionProvider(c.ctx, chConfig, targets[0])
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryBlocks failed to connect to deliver service")
+	}
+
+	respTimeout := opts.Timeouts[fab.PeerResponse]
+	if respTimeout == 0 {
+		respTimeout = c.ctx.EndpointConfig().Timeout(fab.PeerResponse)
+	}
+
+	parentCtx := opts.ParentContext
+	if parentCtx == nil {
+		parentCtx = reqContext.Background()
+	}
+
+	it := newBlockIterator(parentCtx, conn, respTimeout, c.ctx.Clock())
+
+	if err := conn.Send(seek.InfoRange(fromBlock, toBlock)); err != nil {
+		it.Close()
+		return nil, errors.WithMessage(err, "QueryBlocks failed to send seek request")
+	}
+
+	return it, nil
+}
+
+// BlockIterator iterates over a range of blocks streamed from the Deliver service
+type BlockIterator struct {
+	ctx         reqContext.Context
+	conn        deliverConnection
+	eventch     chan interface{}
+	respTimeout time.Duration
+	clock       clock.Clock
+	done        bool
+	closeOnce   sync.Once
+}
+
+func newBlockIterator(ctx reqContext.Context, conn deliverConnection, respTimeout time.Duration, clk clock.Clock) *BlockIterator {
+	it := &BlockIterator{
+		ctx:         ctx,
+		conn:        conn,
+		eventch:     make(chan interface{}),
+		respTimeout: respTimeout,
+		clock:       clk,
+	}
+
+	go func() {
+		conn.Receive(it.eventch)
+		close(it.eventch)
+	}()
+
+	return it
+}
+
+// Next returns the next block in the range. io.EOF is returned once all of the
+// blocks in the range have been returned.
+func (it *BlockIterator) Next() (*common.Block, error) {
+	if it.done {
+		return nil, io.EOF
+	}
+
+	block, err := it.next()
+	if block == nil {
+		it.done = true
+		it.Close()
+	}
+	return block, err
+}
+
+func (it *BlockIterator) next() (*common.Block, error) {
+	select {
+	case e, ok := <-it.eventch:
+		if !ok {
+			return nil, errors.New("deliver stream was closed")
+		}
+		return handleDeliverEvent(e)
+	case <-it.clock.After(it.respTimeout):
+		return nil, errors.New("timed out waiting for block from deliver service")
+	case <-it.ctx.Done():
+		return nil, errors.WithMessage(it.ctx.Err(), "block iteration was cancelled")
+	}
+}
+
+func handleDeliverEvent(e interface{}) (*common.Block, error) {
+	switch evt := e.(type) {
+	case *deliverconn.Event:
+		resp, ok := evt.Event.(*pb.DeliverResponse)
+		if !ok {
+			return nil, errors.Errorf("unexpected deliver event type: %T", evt.Event)
+		}
+		switch r := resp.Type.(type) {
+		case *pb.DeliverResponse_Block:
+			return r.Block, nil
+		case *pb.DeliverResponse_Status:
+			if r.Status == common.Status_SUCCESS {
+				return nil, io.EOF
+			}
+			return nil, errors.Errorf("received status [%s] from deliver service", r.Status)
+		default:
+			return nil, errors.Errorf("unexpected deliver response type: %T", resp.Type)
+		}
+	case *clientdisp.DisconnectedEvent:
+		return nil, errors.WithMessage(evt.Err, "disconnected from deliver service")
+	default:
+		return nil, errors.Errorf("unexpected deliver event type: %T", e)
+	}
+}
+
+// Close closes the connection to the Deliver service. Close may be
+// called before all of the blocks in the range have been returned.
+func (it *BlockIterator) Close() {
+	it.closeOnce.Do(func() {
+		it.conn.Close()
+
+		// Drain any pending events so that the receiver may exit
+		go func() {
+			for range it.eventch {
+			}
+		}()
+	})
+}
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"io"
+	"testing"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	deliverconn "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryBlocks(t *testing.T) {
+	peer1 := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test"}
+	lc := setupLedgerClient([]fab.Peer{&peer1}, t)
+
+	conn := &mockDeliverConnection{lastBlock: 4, status: common.Status_SUCCESS, seekch: make(chan struct{})}
+	restore := setDeliverConnection(conn)
+	defer restore()
+
+	it, err := lc.QueryBlocks(2, 4)
+	assert.NoError(t, err)
+
+	var blockNums []uint64
+	for {
+		block, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			break
+		}
+		blockNums = append(blockNums, block.Header.Number)
+	}
+	assert.Equal(t, []uint64{2, 3, 4}, blockNums)
+	assert.True(t, conn.closed, "expecting connection to be closed after last block")
+
+	_, err = it.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestQueryBlocksNotFound(t *testing.T) {
+	peer1 := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test"}
+	lc := setupLedgerClient([]fab.Peer{&peer1}, t)
+
+	conn := &mockDeliverConnection{lastBlock: 1, status: common.Status_NOT_FOUND, seekch: make(chan struct{})}
+	restore := setDeliverConnection(conn)
+	defer restore()
+
+	it, err := lc.QueryBlocks(0, 10)
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = it.Next()
+		assert.NoError(t, err)
+	}
+
+	_, err = it.Next()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "NOT_FOUND")
+
+	_, err = lc.QueryBlocks(5, 1)
+	assert.Error(t, err, "expecting error for invalid range")
+}
+
+func setDeliverConnection(conn deliverConnection) func() {
+	provider := deliverConnectionProvider
+	deliverConnectionProvider = func(ctx context.Client, chConfig fab.ChannelCfg, peer fab.Peer) (deliverConnection, error) {
+		return conn, nil
+	}
+	return func() {
+		deliverConnectionProvider = provider
+	}
+}
+
+type mockDeliverConnection struct {
+	from      uint64
+	lastBlock uint64
+	status    common.Status
+	closed    bool
+	seekch    chan struct{}
+}
+
+func (c *mockDeliverConnection) Send(seekInfo *ab.SeekInfo) error {
+	c.from = seekInfo.Start.GetSpecified().Number
+	close(c.seekch)
+	return nil
+}
+
+func (c *mockDeliverConnection) Receive(eventch chan<- interface{}) {
+	<-c.seekch
+	for i := c.from; i <= c.lastBlock; i++ {
+		block := &common.Block{Header: &common.BlockHeader{Number: i}}
+		eventch <- deliverconn.NewEvent(&pb.DeliverResponse{Type: &pb.DeliverResponse_Block{Block: block}}, "peer1.com")
+	}
+	eventch <- deliverconn.NewEvent(&pb.DeliverResponse{Type: &pb.DeliverResponse_Status{Status: c.status}}, "peer1.com")
+}
+
+func (c *mockDeliverConnection) Close() {
+	c.closed = true
+}
@@ -0,0 +1,283 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/protopool"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// TxValidationCodeNotValidated is the validation code of a transaction that the committing peer
+// hasn't validated (yet). It matches NOT_VALIDATED, which the Fabric protos used by the SDK don't define.
+const TxValidationCodeNotValidated pb.TxValidationCode = 254
+
+// Block contains the decoded contents of a block
+type Block struct {
+	Number       uint64
+	PreviousHash []byte
+	DataHash     []byte
+	Transactions []*Transaction
+}
+
+// Transaction contains the decoded contents of a transaction envelope
+type Transaction struct {
+	TxID      string
+	ChannelID string
+	Type      common.HeaderType
+	Timestamp time.Time
+	// Creator is the identity of the client that submitted the transaction
+	Creator *Identity
+	// ValidationCode is the validation code assigned to the transaction by the committing peer
+	ValidationCode pb.TxValidationCode
+	// Actions contains the chaincode actions of an endorser transaction. Actions is
+	// empty for other types of transactions (such as config transactions).
+	Actions []*Action
+}
+
+// Action contains the decoded contents of a chaincode action
+type Action struct {
+	ChaincodeID  *pb.ChaincodeID
+	Response     *pb.Response
+	Event        *pb.ChaincodeEvent
+	RWSet        *rwsetutil.TxRwSet
+	Endorsements []*Endorsement
+}
+
+// Endorsement contains an endorser's identity and signature
+type Endorsement struct {
+	Endorser  *Identity
+	Signature []byte
+}
+
+// Identity is a decoded serialized identity
+type Identity struct {
+	MSPID string
+	// Certificate is the PEM encoded certificate of the identity
+	Certificate []byte
+}
+
+// TransactionInBlock contains a decoded transaction along with the block that contains it
+type TransactionInBlock struct {
+	Block *Block
+	// Index is the index of the transaction within the block
+	Index       int
+	Transaction *Transaction
+}
+
+// DecodeBlock decodes the transactions contained in the given block
+func DecodeBlock(block *common.Block) (*Block, error) {
+	if block == nil || block.Header == nil {
+		return nil, errors.New("block header is nil")
+	}
+
+	decoded := &Block{
+		Number:       block.Header.Number,
+		PreviousHash: block.Header.PreviousHash,
+		DataHash:     block.Header.DataHash,
+	}
+
+	if block.Data == nil {
+		return decoded, nil
+	}
+
+	var txFilter ledgerutil.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = ledgerutil.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+
+	for i, data := range block.Data.Data {
+		env, err := protopool.Envelope(data)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error extracting envelope from block")
+		}
+
+		tx, err := DecodeTransaction(env)
+		protopool.Release(env)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error decoding transaction")
+		}
+
+		if i < len(txFilter) {
+			tx.ValidationCode = txFilter.Flag(i)
+		} else {
+			tx.ValidationCode = TxValidationCodeNotValidated
+		}
+
+		decoded.Transactions = append(decoded.Transactions, tx)
+	}
+
+	return decoded, nil
+}
+
+// DecodeTransaction decodes the given transaction envelope. The validation code
+// of the returned transaction is not set since it isn't part of the envelope.
+func DecodeTransaction(env *common.Envelope) (*Transaction, error) {
+	payload, err := protopool.Payload(env)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting payload from envelope")
+	}
+	defer protopool.Release(payload)
+
+	if payload.Header == nil {
+		return nil, errors.New("payload header is nil")
+	}
+
+	channelHeader, err := protopool.ChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting channel header from payload")
+	}
+	defer protopool.Release(channelHeader)
+
+	signatureHeader, err := protopool.SignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting signature header from payload")
+	}
+	defer protopool.Release(signatureHeader)
+
+	creator, err := decodeIdentity(signatureHeader.Creator)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error decoding creator")
+	}
+
+	tx := &Transaction{
+		TxID:           channelHeader.TxId,
+		ChannelID:      channelHeader.ChannelId,
+		Type:           common.HeaderType(channelHeader.Type),
+		Creator:        creator,
+		ValidationCode: TxValidationCodeNotValidated,
+	}
+
+	if channelHeader.Timestamp != nil {
+		tx.Timestamp, err = ptypes.Timestamp(channelHeader.Timestamp)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid timestamp in channel header")
+		}
+	}
+
+	if tx.Type == common.HeaderType_ENDORSER_TRANSACTION {
+		tx.Actions, err = decodeActions(payload.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+// DecodeProcessedTransaction decodes the given processed transaction, which
+// includes the validation code of the transaction
+func DecodeProcessedTransaction(processedTx *pb.ProcessedTransaction) (*Transaction, error) {
+	if processedTx == nil || processedTx.TransactionEnvelope == nil {
+		return nil, errors.New("transaction envelope is nil")
+	}
+
+	tx, err := DecodeTransaction(processedTx.TransactionEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	tx.ValidationCode = pb.TxValidationCode(processedTx.ValidationCode)
+	return tx, nil
+}
+
+func findTransaction(block *Block, txID fab.TransactionID) (*TransactionInBlock, error) {
+	for i, tx := range block.Transactions {
+		if tx.TxID == string(txID) {
+			return &TransactionInBlock{Block: block, Index: i, Transaction: tx}, nil
+		}
+	}
+	return nil, errors.Errorf("transaction [%s] not found in block [%d]", txID, block.Number)
+}
+
+func decodeActions(data []byte) ([]*Action, error) {
+	tx, err := protopool.Transaction(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling transaction payload")
+	}
+	defer protopool.Release(tx)
+
+	var actions []*Action
+	for _, txAction := range tx.Actions {
+		action, err := decodeAction(txAction)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+func decodeAction(txAction *pb.TransactionAction) (*Action, error) {
+	chaincodeActionPayload, err := protopool.ChaincodeActionPayload(txAction.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
+	}
+	defer protopool.Release(chaincodeActionPayload)
+
+	if chaincodeActionPayload.Action == nil {
+		return nil, errors.New("chaincode endorsed action is nil")
+	}
+
+	propRespPayload, err := protopool.ProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling proposal response payload")
+	}
+	defer protopool.Release(propRespPayload)
+
+	// The chaincode action isn't pooled since the decoded action refers to its chaincode ID and response
+	ccAction, err := utils.GetChaincodeAction(propRespPayload.Extension)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling chaincode action")
+	}
+
+	action := &Action{
+		ChaincodeID: ccAction.ChaincodeId,
+		Response:    ccAction.Response,
+	}
+
+	if len(ccAction.Events) > 0 {
+		action.Event, err = utils.GetChaincodeEvents(ccAction.Events)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode event")
+		}
+	}
+
+	if len(ccAction.Results) > 0 {
+		action.RWSet = &rwsetutil.TxRwSet{}
+		if err := action.RWSet.FromProtoBytes(ccAction.Results); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling read-write set")
+		}
+	}
+
+	for _, e := range chaincodeActionPayload.Action.Endorsements {
+		endorser, err := decodeIdentity(e.Endorser)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error decoding endorser")
+		}
+		action.Endorsements = append(action.Endorsements, &Endorsement{Endorser: endorser, Signature: e.Signature})
+	}
+
+	return action, nil
+}
+
+func decodeIdentity(serializedIdentity []byte) (*Identity, error) {
+	identity := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedIdentity, identity); err != nil {
+		return nil, errors.Wrap(err, "error unmarshalling serialized identity")
+	}
+	return &Identity{MSPID: identity.Mspid, Certificate: identity.IdBytes}, nil
+}
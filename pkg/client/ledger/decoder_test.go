@@ -0,0 +1,191 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBlock(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	block := newTestBlock(3,
+		newTestEnvelope(t, "tx1", now),
+		newTestEnvelope(t, "tx2", now),
+	)
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER][1] = uint8(pb.TxValidationCode_MVCC_READ_CONFLICT)
+
+	decoded, err := DecodeBlock(block)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(3), decoded.Number)
+	require.Equal(t, 2, len(decoded.Transactions))
+
+	tx := decoded.Transactions[0]
+	assert.Equal(t, "tx1", tx.TxID)
+	assert.Equal(t, channelID, tx.ChannelID)
+	assert.Equal(t, common.HeaderType_ENDORSER_TRANSACTION, tx.Type)
+	assert.Equal(t, now, tx.Timestamp)
+	assert.Equal(t, "Org1MSP", tx.Creator.MSPID)
+	assert.Equal(t, []byte("creatorcert"), tx.Creator.Certificate)
+	assert.Equal(t, pb.TxValidationCode_VALID, tx.ValidationCode)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, decoded.Transactions[1].ValidationCode)
+
+	require.Equal(t, 1, len(tx.Actions))
+	action := tx.Actions[0]
+	assert.Equal(t, "examplecc", action.ChaincodeID.Name)
+	assert.Equal(t, int32(200), action.Response.Status)
+	assert.Equal(t, "event1", action.Event.EventName)
+
+	require.Equal(t, 1, len(action.Endorsements))
+	assert.Equal(t, "Org2MSP", action.Endorsements[0].Endorser.MSPID)
+	assert.Equal(t, []byte("signature"), action.Endorsements[0].Signature)
+
+	require.NotNil(t, action.RWSet)
+	require.Equal(t, 1, len(action.RWSet.NsRwSets))
+	nsRWSet := action.RWSet.NsRwSets[0]
+	assert.Equal(t, "examplecc", nsRWSet.NameSpace)
+	assert.Equal(t, "key1", nsRWSet.KvRwSet.Reads[0].Key)
+	assert.Equal(t, []byte("value1"), nsRWSet.KvRwSet.Writes[0].Value)
+	require.Equal(t, 1, len(nsRWSet.CollHashedRwSets))
+	assert.Equal(t, "coll1", nsRWSet.CollHashedRwSets[0].CollectionName)
+}
+
+func TestDecodeBlockInvalid(t *testing.T) {
+	_, err := DecodeBlock(nil)
+	assert.Error(t, err)
+
+	_, err = DecodeBlock(&common.Block{Header: &common.BlockHeader{}, Data: &common.BlockData{Data: [][]byte{[]byte("invalid")}}})
+	assert.Error(t, err)
+}
+
+func newTestBlock(number uint64, envelopes ...*common.Envelope) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+	for _, env := range envelopes {
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(env))
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = ledgerutil.NewTxValidationFlags(len(envelopes))
+	for i := range envelopes {
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER][i] = uint8(pb.TxValidationCode_VALID)
+	}
+	return block
+}
+
+func newTestEnvelope(t *testing.T, txID string, timestamp time.Time) *common.Envelope {
+	ts, err := ptypes.TimestampProto(timestamp)
+	require.NoError(t, err)
+
+	txRWSet := &rwsetutil.TxRwSet{
+		NsRwSets: []*rwsetutil.NsRwSet{
+			{
+				NameSpace: "examplecc",
+				KvRwSet: &kvrwset.KVRWSet{
+					Reads:  []*kvrwset.KVRead{{Key: "key1"}},
+					Writes: []*kvrwset.KVWrite{{Key: "key1", Value: []byte("value1")}},
+				},
+				CollHashedRwSets: []*rwsetutil.CollHashedRwSet{
+					{
+						CollectionName: "coll1",
+						HashedRwSet:    &kvrwset.HashedRWSet{},
+					},
+				},
+			},
+		},
+	}
+	results, err := txRWSet.ToProtoBytes()
+	require.NoError(t, err)
+
+	event := &pb.ChaincodeEvent{ChaincodeId: "examplecc", TxId: txID, EventName: "event1"}
+	eventBytes, err := utils.GetBytesChaincodeEvent(event)
+	require.NoError(t, err)
+
+	prpBytes, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &pb.Response{Status: 200}, results, eventBytes, &pb.ChaincodeID{Name: "examplecc"})
+	require.NoError(t, err)
+
+	capBytes, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{
+			ProposalResponsePayload: prpBytes,
+			Endorsements: []*pb.Endorsement{
+				{
+					Endorser:  utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org2MSP", IdBytes: []byte("endorsercert")}),
+					Signature: []byte("signature"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	txBytes, err := utils.GetBytesTransaction(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: capBytes}}})
+	require.NoError(t, err)
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+				Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+				ChannelId: channelID,
+				TxId:      txID,
+				Timestamp: ts,
+			}),
+			SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{
+				Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("creatorcert")}),
+			}),
+		},
+		Data: txBytes,
+	}
+
+	return &common.Envelope{Payload: utils.MarshalOrPanic(payload)}
+}
+
+func TestDecodeProcessedTransaction(t *testing.T) {
+	processedTx := &pb.ProcessedTransaction{
+		TransactionEnvelope: newTestEnvelope(t, "tx1", time.Now()),
+		ValidationCode:      int32(pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE),
+	}
+
+	tx, err := DecodeProcessedTransaction(processedTx)
+	require.NoError(t, err)
+	assert.Equal(t, "tx1", tx.TxID)
+	assert.Equal(t, pb.TxValidationCode_ENDORSEMENT_POLICY_FAILURE, tx.ValidationCode)
+	require.Equal(t, 1, len(tx.Actions))
+	require.Equal(t, 1, len(tx.Actions[0].RWSet.NsRwSets))
+	assert.Equal(t, "coll1", tx.Actions[0].RWSet.NsRwSets[0].CollHashedRwSets[0].CollectionName)
+
+	_, err = DecodeProcessedTransaction(&pb.ProcessedTransaction{})
+	assert.Error(t, err)
+}
+
+func TestFindTransaction(t *testing.T) {
+	now := time.Now()
+	block, err := DecodeBlock(newTestBlock(5, newTestEnvelope(t, "tx1", now), newTestEnvelope(t, "tx2", now)))
+	require.NoError(t, err)
+
+	txInBlock, err := findTransaction(block, "tx2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, txInBlock.Index)
+	assert.Equal(t, "tx2", txInBlock.Transaction.TxID)
+	assert.Equal(t, pb.TxValidationCode_VALID, txInBlock.Transaction.ValidationCode)
+	assert.Equal(t, uint64(5), txInBlock.Block.Number)
+
+	_, err = findTransaction(block, "tx3")
+	assert.Error(t, err)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// ExportFormat is the format in which exported blocks are written
+type ExportFormat int
+
+const (
+	// ExportProtobuf writes each block as a varint length-prefixed protobuf message
+	ExportProtobuf ExportFormat = iota
+	// ExportJSON writes each block as a JSON object on its own line
+	ExportJSON
+)
+
+// ExportOption describes a functional parameter for the ExportBlocks function
+type ExportOption func(*exportOptions)
+
+type exportOptions struct {
+	format  ExportFormat
+	redact  bool
+	reqOpts []RequestOption
+}
+
+// WithExportFormat sets the format of the exported blocks (ExportProtobuf by default)
+func WithExportFormat(format ExportFormat) ExportOption {
+	return func(opts *exportOptions) {
+		opts.format = format
+	}
+}
+
+// WithRedactedPayloads removes the data of each transaction payload from the exported
+// blocks. The payload headers (transaction ID, creator, timestamp, etc.), envelope
+// signatures and block metadata are retained.
+func WithRedactedPayloads() ExportOption {
+	return func(opts *exportOptions) {
+		opts.redact = true
+	}
+}
+
+// WithExportRequestOptions sets the request options (target, timeouts, etc.) used to retrieve the blocks
+func WithExportRequestOptions(options ...RequestOption) ExportOption {
+	return func(opts *exportOptions) {
+		opts.reqOpts = options
+	}
+}
+
+// ExportBlocks writes the blocks in the given range (inclusive) to the given writer.
+// The number of blocks written is returned.
+func (c *Client) ExportBlocks(w io.Writer, fromBlock, toBlock uint64, options ...ExportOption) (int, error) {
+	opts := exportOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	it, err := c.QueryBlocks(fromBlock, toBlock, opts.reqOpts...)
+	if err != nil {
+		return 0, errors.WithMessage(err, "ExportBlocks failed")
+	}
+	defer it.Close()
+
+	return exportBlocks(w, it, opts)
+}
+
+// ExportBlocksToFile writes the blocks in the given range (inclusive) to the given file,
+// which is created (or truncated if it exists). The number of blocks written is returned.
+func (c *Client) ExportBlocksToFile(path string, fromBlock, toBlock uint64, options ...ExportOption) (int, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create export file [%s]", path)
+	}
+
+	n, err := c.ExportBlocks(f, fromBlock, toBlock, options...)
+	if closeErr := f.Close(); err == nil && closeErr != nil {
+		err = errors.Wrapf(closeErr, "failed to close export file [%s]", path)
+	}
+	return n, err
+}
+
+type blockSource interface {
+	Next() (*common.Block, error)
+}
+
+func exportBlocks(w io.Writer, source blockSource, opts exportOptions) (int, error) {
+	bw := bufio.NewWriter(w)
+
+	n := 0
+	for {
+		block, err := source.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return n, errors.WithMessage(err, "failed to retrieve block")
+		}
+
+		if opts.redact {
+			block, err = redactBlock(block)
+			if err != nil {
+				return n, err
+			}
+		}
+
+		if err := writeBlock(bw, block, opts.format); err != nil {
+			return n, errors.WithMessage(err, "failed to write block")
+		}
+		n++
+	}
+
+	if err := bw.Flush(); err != nil {
+		return n, errors.Wrap(err, "failed to flush exported blocks")
+	}
+	return n, nil
+}
+
+func writeBlock(w io.Writer, block *common.Block, format ExportFormat) error {
+	switch format {
+	case ExportProtobuf:
+		blockBytes, err := proto.Marshal(block)
+		if err != nil {
+			return errors.Wrap(err, "marshal of block failed")
+		}
+		if _, err := w.Write(proto.EncodeVarint(uint64(len(blockBytes)))); err != nil {
+			return errors.WithStack(err)
+		}
+		_, err = w.Write(blockBytes)
+		return errors.WithStack(err)
+	case ExportJSON:
+		if err := (&jsonpb.Marshaler{}).Marshal(w, block); err != nil {
+			return errors.Wrap(err, "JSON marshal of block failed")
+		}
+		_, err := w.Write([]byte("\n"))
+		return errors.WithStack(err)
+	default:
+		return errors.Errorf("unsupported export format: %d", format)
+	}
+}
+
+// redactBlock returns a copy of the given block with the data of each transaction payload removed
+func redactBlock(block *common.Block) (*common.Block, error) {
+	redacted := proto.Clone(block).(*common.Block)
+	if redacted.Data == nil {
+		return redacted, nil
+	}
+
+	for i, envBytes := range redacted.Data.Data {
+		env := &common.Envelope{}
+		if err := proto.Unmarshal(envBytes, env); err != nil {
+			return nil, errors.Wrap(err, "unmarshal of envelope failed")
+		}
+		payload := &common.Payload{}
+		if err := proto.Unmarshal(env.Payload, payload); err != nil {
+			return nil, errors.Wrap(err, "unmarshal of payload failed")
+		}
+		payload.Data = nil
+
+		payloadBytes, err := proto.Marshal(payload)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal of payload failed")
+		}
+		env.Payload = payloadBytes
+
+		redacted.Data.Data[i], err = proto.Marshal(env)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal of envelope failed")
+		}
+	}
+	return redacted, nil
+}
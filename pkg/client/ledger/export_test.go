@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportBlocksProtobuf(t *testing.T) {
+	blocks := []*common.Block{
+		newTestBlock(1, newTestEnvelope(t, "tx1", time.Now())),
+		newTestBlock(2, newTestEnvelope(t, "tx2", time.Now())),
+	}
+
+	var buf bytes.Buffer
+	n, err := exportBlocks(&buf, &sliceBlockSource{blocks: blocks}, exportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	data := buf.Bytes()
+	for _, expected := range blocks {
+		size, prefixLen := proto.DecodeVarint(data)
+		require.NotEqual(t, 0, prefixLen)
+		data = data[prefixLen:]
+
+		block := &common.Block{}
+		require.NoError(t, proto.Unmarshal(data[:size], block))
+		assert.True(t, proto.Equal(expected, block))
+		data = data[size:]
+	}
+	assert.Empty(t, data)
+}
+
+func TestExportBlocksJSON(t *testing.T) {
+	blocks := []*common.Block{newTestBlock(1), newTestBlock(2)}
+
+	var buf bytes.Buffer
+	n, err := exportBlocks(&buf, &sliceBlockSource{blocks: blocks}, exportOptions{format: ExportJSON})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	scanner := bufio.NewScanner(&buf)
+	var numbers []uint64
+	for scanner.Scan() {
+		block := &common.Block{}
+		require.NoError(t, jsonpb.Unmarshal(strings.NewReader(scanner.Text()), block))
+		numbers = append(numbers, block.Header.Number)
+	}
+	assert.Equal(t, []uint64{1, 2}, numbers)
+}
+
+func TestExportBlocksRedacted(t *testing.T) {
+	block := newTestBlock(1, newTestEnvelope(t, "tx1", time.Now()))
+
+	var buf bytes.Buffer
+	_, err := exportBlocks(&buf, &sliceBlockSource{blocks: []*common.Block{block}}, exportOptions{redact: true})
+	require.NoError(t, err)
+
+	data := buf.Bytes()
+	size, prefixLen := proto.DecodeVarint(data)
+	exported := &common.Block{}
+	require.NoError(t, proto.Unmarshal(data[prefixLen:prefixLen+int(size)], exported))
+
+	env, err := utils.GetEnvelopeFromBlock(exported.Data.Data[0])
+	require.NoError(t, err)
+	payload, err := utils.GetPayload(env)
+	require.NoError(t, err)
+	assert.Empty(t, payload.Data)
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, "tx1", chdr.TxId)
+
+	// The original block must not be modified
+	env, err = utils.GetEnvelopeFromBlock(block.Data.Data[0])
+	require.NoError(t, err)
+	payload, err = utils.GetPayload(env)
+	require.NoError(t, err)
+	assert.NotEmpty(t, payload.Data)
+}
+
+func TestExportBlocksInvalidFormat(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := exportBlocks(&buf, &sliceBlockSource{blocks: []*common.Block{newTestBlock(1)}}, exportOptions{format: ExportFormat(5)})
+	assert.Error(t, err)
+}
+
+type sliceBlockSource struct {
+	blocks []*common.Block
+}
+
+func (s *sliceBlockSource) Next() (*common.Block, error) {
+	if len(s.blocks) == 0 {
+		return nil, io.EOF
+	}
+	block := s.blocks[0]
+	s.blocks = s.blocks[1:]
+	return block, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// HeightReport contains the ledger heights of the peers in a channel
+type HeightReport struct {
+	// MaxHeight is the highest ledger height reported by any peer
+	MaxHeight uint64
+	// Heights maps the URL of each peer that responded to its ledger height
+	Heights map[string]uint64
+	// Lagging contains the URLs of the peers whose ledger height is more than
+	// the allowed lag behind MaxHeight
+	Lagging []string
+	// Failed contains the URLs of the peers that didn't respond successfully
+	Failed []string
+	// Err contains the errors returned by the peers that didn't respond successfully
+	Err error
+}
+
+// Diverged returns true if one or more peers is lagging or didn't respond
+func (r *HeightReport) Diverged() bool {
+	return len(r.Lagging) > 0 || len(r.Failed) > 0
+}
+
+// QueryHeights concurrently queries the ledger height of all of the peers in the channel
+// (or the given targets) and reports the peers whose height is more than maxLag blocks behind
+// the highest height. Unlike other queries, the default target filter isn't applied and the
+// MaxTargets and MinTargets options are ignored. An error is returned only if no peer responded.
+func (c *Client) QueryHeights(maxLag uint64, options ...RequestOption) (*HeightReport, error) {
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryHeights failed to prepare request options")
+	}
+
+	targets, err := c.allTargets(opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryHeights failed to determine target peers")
+	}
+
+	reqCtx, cancel := c.createRequestContext(&opts)
+	defer cancel()
+
+	responses, err := c.ledger.QueryInfo(reqCtx, peersToTxnProcessors(targets), c.verifier)
+	if len(responses) == 0 {
+		if err == nil {
+			err = errors.New("no responses")
+		}
+		return nil, errors.WithMessage(err, "QueryHeights failed")
+	}
+
+	return newHeightReport(targets, responses, maxLag, err), nil
+}
+
+func (c *Client) allTargets(opts requestOptions) ([]fab.Peer, error) {
+	if opts.Targets != nil && opts.TargetFilter != nil {
+		return nil, errors.New("If targets are provided, filter cannot be provided")
+	}
+
+	targets := opts.Targets
+	if targets == nil {
+		var err error
+		targets, err = discovery.GetPeers(opts.ParentContext, c.discovery)
+		if err != nil {
+			return nil, err
+		}
+		targets = filterTargets(targets, opts.TargetFilter)
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+	return targets, nil
+}
+
+func newHeightReport(targets []fab.Peer, responses []*fab.BlockchainInfoResponse, maxLag uint64, err error) *HeightReport {
+	report := &HeightReport{
+		Heights: make(map[string]uint64),
+		Err:     err,
+	}
+
+	for _, r := range responses {
+		report.Heights[r.Endorser] = r.BCI.Height
+		if r.BCI.Height > report.MaxHeight {
+			report.MaxHeight = r.BCI.Height
+		}
+	}
+
+	for url, height := range report.Heights {
+		if report.MaxHeight-height > maxLag {
+			report.Lagging = append(report.Lagging, url)
+		}
+	}
+	sort.Strings(report.Lagging)
+
+	for _, target := range targets {
+		if _, ok := report.Heights[target.URL()]; !ok {
+			report.Failed = append(report.Failed, target.URL())
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,49 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryHeights(t *testing.T) {
+	peer1 := newHeightPeer(t, "http://peer1.com", "Org1MSP", 10)
+	peer2 := newHeightPeer(t, "http://peer2.com", "Org2MSP", 9)
+	peer3 := newHeightPeer(t, "http://peer3.com", "Org2MSP", 5)
+	peer4 := &mocks.MockPeer{MockName: "http://peer4.com", MockURL: "http://peer4.com", Status: 405, MockMSP: "Org2MSP"}
+
+	lc := setupLedgerClient([]fab.Peer{peer1, peer2, peer3, peer4}, t)
+
+	report, err := lc.QueryHeights(2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), report.MaxHeight)
+	assert.Equal(t, map[string]uint64{"http://peer1.com": 10, "http://peer2.com": 9, "http://peer3.com": 5}, report.Heights)
+	assert.Equal(t, []string{"http://peer3.com"}, report.Lagging)
+	assert.Equal(t, []string{"http://peer4.com"}, report.Failed)
+	assert.Error(t, report.Err)
+	assert.True(t, report.Diverged())
+
+	report, err = lc.QueryHeights(2, WithTargets(peer1, peer2))
+	require.NoError(t, err)
+	assert.False(t, report.Diverged())
+
+	_, err = lc.QueryHeights(2, WithTargets(peer4))
+	assert.Error(t, err)
+}
+
+func newHeightPeer(t *testing.T, url, mspID string, height uint64) *mocks.MockPeer {
+	payload, err := proto.Marshal(&common.BlockchainInfo{Height: height})
+	require.NoError(t, err)
+	return &mocks.MockPeer{MockName: url, MockURL: url, Status: 200, MockMSP: mspID, Payload: payload}
+}
@@ -5,11 +5,16 @@ SPDX-License-Identifier: Apache-2.0
 */
 
 // Package ledger enables ability to query ledger in a Fabric network.
+//
+// Ledger snapshots can't be managed with this client. Snapshot requests are generated, listed and
+// cancelled with the Generate, QueryPendings and Cancel calls of the peer's Snapshot gRPC service,
+// which was added in Fabric 2.3; Fabric 1.x peers don't serve it and its messages aren't part of the
+// Fabric protos vendored by this SDK. Use the "peer snapshot" commands of a Fabric 2.3+ peer to
+// orchestrate snapshots, with QueryInfo giving the block height at which to request one.
 package ledger
 
 import (
 	reqContext "context"
-	"math/rand"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -20,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
@@ -162,7 +168,7 @@ func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (*
 		return nil, errors.WithMessage(err, "QueryBlockByHash failed")
 	}
 
-	return matchBlockData(responses, opts.MinTargets)
+	return matchBlockData(responses, *opts)
 }
 
 // QueryBlockByTxID returns a block which contains a transaction
@@ -182,7 +188,7 @@ func (c *Client) QueryBlockByTxID(txID fab.TransactionID, options ...RequestOpti
 		return nil, errors.WithMessage(err, "QueryBlockByTxID failed")
 	}
 
-	return matchBlockData(responses, opts.MinTargets)
+	return matchBlockData(responses, *opts)
 }
 
 // QueryBlock queries the ledger for Block by block number.
@@ -203,7 +209,48 @@ func (c *Client) QueryBlock(blockNumber uint64, options ...RequestOption) (*comm
 		return nil, errors.WithMessage(err, "QueryBlock failed")
 	}
 
-	return matchBlockData(responses, opts.MinTargets)
+	return matchBlockData(responses, *opts)
+}
+
+// QueryDecodedBlock queries the ledger for Block by block number and returns the decoded block,
+// which includes the creator, endorsements, read-write sets, validation code and chaincode
+// events of each transaction.
+func (c *Client) QueryDecodedBlock(blockNumber uint64, options ...RequestOption) (*Block, error) {
+	block, err := c.QueryBlock(blockNumber, options...)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBlock(block)
+}
+
+// QueryDecodedBlockByHash queries the ledger for Block by block hash and returns the decoded block.
+func (c *Client) QueryDecodedBlockByHash(blockHash []byte, options ...RequestOption) (*Block, error) {
+	block, err := c.QueryBlockByHash(blockHash, options...)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBlock(block)
+}
+
+// QueryDecodedBlockByTxID queries the ledger for the block which contains the given
+// transaction and returns the decoded block.
+func (c *Client) QueryDecodedBlockByTxID(txID fab.TransactionID, options ...RequestOption) (*Block, error) {
+	block, err := c.QueryBlockByTxID(txID, options...)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBlock(block)
+}
+
+// QueryTransactionInBlock queries the ledger for the block which contains the given transaction
+// and returns the decoded block along with the transaction's index within the block. The
+// transaction's validation code is available from the returned transaction.
+func (c *Client) QueryTransactionInBlock(txID fab.TransactionID, options ...RequestOption) (*TransactionInBlock, error) {
+	block, err := c.QueryDecodedBlockByTxID(txID, options...)
+	if err != nil {
+		return nil, err
+	}
+	return findTransaction(block, txID)
 }
 
 func (c *Client) prepareRequestParams(options ...RequestOption) ([]fab.Peer, *requestOptions, error) {
@@ -220,9 +267,21 @@ func (c *Client) prepareRequestParams(options ...RequestOption) ([]fab.Peer, *re
 	return targets, &opts, nil
 }
 
-func matchBlockData(responses []*common.Block, minTargets int) (*common.Block, error) {
-	if len(responses) < minTargets {
-		return nil, errors.Errorf("Number of responses %d is less than MinTargets %d", len(responses), minTargets)
+func matchBlockData(responses []*common.Block, opts requestOptions) (*common.Block, error) {
+	if len(responses) < opts.MinTargets {
+		return nil, errors.Errorf("Number of responses %d is less than MinTargets %d", len(responses), opts.MinTargets)
+	}
+
+	if opts.Majority {
+		digests, err := blockDigests(responses)
+		if err != nil {
+			return nil, err
+		}
+		i, err := selectMajority(digests, opts.MinTargets)
+		if err != nil {
+			return nil, err
+		}
+		return responses[i], nil
 	}
 
 	response := responses[0]
@@ -262,6 +321,18 @@ func (c *Client) QueryTransaction(transactionID fab.TransactionID, options ...Re
 		return nil, errors.Errorf("QueryTransaction: Number of responses %d is less than MinTargets %d", len(responses), opts.MinTargets)
 	}
 
+	if opts.Majority {
+		payloads := make([]proto.Message, len(responses))
+		for i, r := range responses {
+			payloads[i] = r
+		}
+		i, err := selectMajorityPayload(payloads, opts.MinTargets)
+		if err != nil {
+			return nil, err
+		}
+		return responses[i], nil
+	}
+
 	response := responses[0]
 	for i, r := range responses {
 		if i == 0 {
@@ -277,6 +348,17 @@ func (c *Client) QueryTransaction(transactionID fab.TransactionID, options ...Re
 	return response, nil
 }
 
+// QueryDecodedTransaction queries the ledger for Transaction by ID and returns the decoded
+// transaction, which includes the transaction's validation code as well as the read-write
+// sets (including collection hashes) of each namespace.
+func (c *Client) QueryDecodedTransaction(transactionID fab.TransactionID, options ...RequestOption) (*Transaction, error) {
+	processedTx, err := c.QueryTransaction(transactionID, options...)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeProcessedTransaction(processedTx)
+}
+
 // QueryConfig config returns channel configuration
 func (c *Client) QueryConfig(options ...RequestOption) (fab.ChannelCfg, error) {
 
@@ -295,6 +377,29 @@ func (c *Client) QueryConfig(options ...RequestOption) (fab.ChannelCfg, error) {
 	return channelConfig.Query(reqCtx)
 }
 
+// QueryConfigBlock returns the current configuration block for the channel
+func (c *Client) QueryConfigBlock(options ...RequestOption) (*common.Block, error) {
+
+	targets, opts, err := c.prepareRequestParams(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryConfigBlock failed to prepare request parameters")
+	}
+	reqCtx, cancel := c.createRequestContext(opts)
+	defer cancel()
+
+	return c.ledger.QueryConfigBlock(reqCtx, peersToTxnProcessors(targets), c.verifier)
+}
+
+// QueryDecodedConfig returns the current channel configuration decoded into organizations,
+// capabilities, anchor peers and orderer settings
+func (c *Client) QueryDecodedConfig(options ...RequestOption) (*chconfig.Config, error) {
+	block, err := c.QueryConfigBlock(options...)
+	if err != nil {
+		return nil, err
+	}
+	return chconfig.DecodeConfigBlock(block)
+}
+
 //prepareRequestOpts Reads Opts from Option array
 func (c *Client) prepareRequestOpts(options ...RequestOption) (requestOptions, error) {
 	opts := requestOptions{}
@@ -335,7 +440,7 @@ func (c *Client) calculateTargets(opts requestOptions) ([]fab.Peer, error) {
 	var err error
 	if targets == nil {
 		// Retrieve targets from discovery
-		targets, err = c.discovery.GetPeers()
+		targets, err = discovery.GetPeers(opts.ParentContext, c.discovery)
 		if err != nil {
 			return nil, err
 		}
@@ -364,7 +469,7 @@ func (c *Client) calculateTargets(opts requestOptions) ([]fab.Peer, error) {
 	}
 
 	// Shuffle to randomize
-	shuffle(targets)
+	shuffle(c.ctx.RandomSource(), targets)
 
 	return targets[:numOfTargets], nil
 }
@@ -410,9 +515,9 @@ func peersToTxnProcessors(peers []fab.Peer) []fab.ProposalProcessor {
 	return tpp
 }
 
-func shuffle(a []fab.Peer) {
+func shuffle(source *random.Source, a []fab.Peer) {
 	for i := range a {
-		j := rand.Intn(i + 1)
+		j := source.Intn(i + 1)
 		a[i], a[j] = a[j], a[i]
 	}
 }
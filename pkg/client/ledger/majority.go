@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// DivergenceError is returned by a query made with the WithMajority option
+// when the required number of peers don't agree on the result
+type DivergenceError struct {
+	// Required is the number of peers that have to agree on the result
+	Required int
+	// Digests maps the hex encoded digest of each distinct result to the number of peers that returned it
+	Digests map[string]int
+}
+
+func (e *DivergenceError) Error() string {
+	responses := 0
+	for _, count := range e.Digests {
+		responses += count
+	}
+	return fmt.Sprintf("no result was returned by at least %d peers: %d responses contained %d distinct results", e.Required, responses, len(e.Digests))
+}
+
+// selectMajority returns the index of the first response whose digest was returned by
+// at least the required number of peers
+func selectMajority(digests [][]byte, required int) (int, error) {
+	counts := make(map[string]int)
+	first := make(map[string]int)
+	for i, digest := range digests {
+		key := hex.EncodeToString(digest)
+		if _, ok := first[key]; !ok {
+			first[key] = i
+		}
+		counts[key]++
+	}
+
+	for key, count := range counts {
+		if count >= required {
+			return first[key], nil
+		}
+	}
+
+	return -1, &DivergenceError{Required: required, Digests: counts}
+}
+
+// blockDigests returns the digests of the header and data of the given blocks.
+// The metadata is excluded since it may legitimately differ between peers.
+func blockDigests(blocks []*common.Block) ([][]byte, error) {
+	var digests [][]byte
+	for _, block := range blocks {
+		headerBytes, err := proto.Marshal(block.Header)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal of block header failed")
+		}
+		dataBytes, err := proto.Marshal(block.Data)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal of block data failed")
+		}
+		digest := sha256.Sum256(append(headerBytes, dataBytes...))
+		digests = append(digests, digest[:])
+	}
+	return digests, nil
+}
+
+// selectMajorityPayload returns the index of the first payload whose digest was returned by
+// at least the required number of peers
+func selectMajorityPayload(payloads []proto.Message, required int) (int, error) {
+	var digests [][]byte
+	for _, payload := range payloads {
+		payloadBytes, err := proto.Marshal(payload)
+		if err != nil {
+			return -1, errors.Wrap(err, "marshal of payload failed")
+		}
+		digest := sha256.Sum256(payloadBytes)
+		digests = append(digests, digest[:])
+	}
+	return selectMajority(digests, required)
+}
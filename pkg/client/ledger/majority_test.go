@@ -0,0 +1,63 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectMajority(t *testing.T) {
+	i, err := selectMajority([][]byte{[]byte("a"), []byte("b"), []byte("b")}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	_, err = selectMajority([][]byte{[]byte("a"), []byte("b"), []byte("c")}, 2)
+	require.Error(t, err)
+	divergenceErr, ok := err.(*DivergenceError)
+	require.True(t, ok, "expecting DivergenceError")
+	assert.Equal(t, 2, divergenceErr.Required)
+	assert.Equal(t, 3, len(divergenceErr.Digests))
+}
+
+func TestQueryBlockWithMajority(t *testing.T) {
+	block := mocks.NewSimpleMockBlock()
+	payload, err := proto.Marshal(block)
+	require.NoError(t, err)
+
+	otherBlock := mocks.NewSimpleMockBlock()
+	otherBlock.Data = &common.BlockData{Data: [][]byte{[]byte("other")}}
+	otherPayload, err := proto.Marshal(otherBlock)
+	require.NoError(t, err)
+
+	peer1 := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Payload: payload, Status: 200, MockMSP: "test"}
+	peer2 := mocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockRoles: []string{}, MockCert: nil, Payload: payload, Status: 200, MockMSP: "test"}
+	peer3 := mocks.MockPeer{MockName: "Peer3", MockURL: "http://peer3.com", MockRoles: []string{}, MockCert: nil, Payload: otherPayload, Status: 200, MockMSP: "test"}
+	peer4 := mocks.MockPeer{MockName: "Peer4", MockURL: "http://peer4.com", MockRoles: []string{}, MockCert: nil, Payload: otherPayload, Status: 200, MockMSP: "test"}
+
+	lc := setupLedgerClient([]fab.Peer{&peer1, &peer2, &peer3}, t)
+
+	response, err := lc.QueryBlock(1, WithMajority(3))
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(block.Data, response.Data), "expecting block returned by the majority")
+
+	lc = setupLedgerClient([]fab.Peer{&peer1, &peer2, &peer3, &peer4}, t)
+
+	_, err = lc.QueryBlock(1, WithMajority(4))
+	require.Error(t, err)
+	_, ok := err.(*DivergenceError)
+	assert.True(t, ok, "expecting DivergenceError")
+
+	_, err = lc.QueryBlock(1, WithMajority(0))
+	assert.Error(t, err)
+}
@@ -41,6 +41,7 @@ type requestOptions struct {
 	TargetFilter  fab.TargetFilter                  // target filter
 	MaxTargets    int                               // maximum number of targets to select
 	MinTargets    int                               // min number of targets that have to respond with no error (or agree on result)
+	Majority      bool                              // return the result agreed on by MinTargets targets instead of requiring all results to match
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for ledger query operations
 	ParentContext reqContext.Context                //parent grpc context for ledger operations
 }
@@ -112,6 +113,23 @@ func WithMinTargets(minTargets int) RequestOption {
 	}
 }
 
+// WithMajority fans the query out to n peers and returns the result agreed on by a majority
+// of them (i.e. more than n/2). Blocks are compared by header and data, other results by
+// payload digest. If no result is agreed on by a majority of the peers then the query
+// fails with a *DivergenceError. This option applies to the QueryBlock, QueryBlockByHash,
+// QueryBlockByTxID, QueryTransaction and QueryCollectionsConfig functions.
+func WithMajority(n int) RequestOption {
+	return func(ctx context.Client, opts *requestOptions) error {
+		if n < 1 {
+			return errors.New("number of peers for majority must be greater than zero")
+		}
+		opts.MaxTargets = n
+		opts.MinTargets = n/2 + 1
+		opts.Majority = true
+		return nil
+	}
+}
+
 //WithTimeout encapsulates key value pairs of timeout type, timeout duration to Options
 //for QueryInfo,QueryBlockByHash,QueryBlock,QueryTransaction,QueryConfig functions
 func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOption {
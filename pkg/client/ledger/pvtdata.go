@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/pkg/errors"
+)
+
+// CollectionHashes contains the hashed read-write set written by a transaction
+// to a private data collection
+type CollectionHashes struct {
+	// Namespace is the name of the chaincode that owns the collection
+	Namespace  string
+	Collection string
+	// PvtRwSetHash is the hash of the private read-write set of the collection
+	PvtRwSetHash []byte
+	Reads        []*kvrwset.KVReadHash
+	Writes       []*kvrwset.KVWriteHash
+}
+
+// Read returns the hashed read of the given key, or false if the key wasn't read
+func (h *CollectionHashes) Read(key string) (*kvrwset.KVReadHash, bool) {
+	keyHash := HashKey(key)
+	for _, r := range h.Reads {
+		if bytes.Equal(r.KeyHash, keyHash) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Write returns the hashed write of the given key, or false if the key wasn't written
+func (h *CollectionHashes) Write(key string) (*kvrwset.KVWriteHash, bool) {
+	keyHash := HashKey(key)
+	for _, w := range h.Writes {
+		if bytes.Equal(w.KeyHash, keyHash) {
+			return w, true
+		}
+	}
+	return nil, false
+}
+
+// HashKey returns the hash of a private data key as stored in the hashed read-write set
+func HashKey(key string) []byte {
+	return HashValue([]byte(key))
+}
+
+// HashValue returns the hash of a private data value as stored in the hashed read-write set
+func HashValue(value []byte) []byte {
+	hash := sha256.Sum256(value)
+	return hash[:]
+}
+
+// QueryCollectionsConfig queries the private data collection configuration of the given chaincode.
+// This query will be made to specified targets.
+func (c *Client) QueryCollectionsConfig(chaincodeName string, options ...RequestOption) (*common.CollectionConfigPackage, error) {
+
+	targets, opts, err := c.prepareRequestParams(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryCollectionsConfig failed to prepare request parameters")
+	}
+	reqCtx, cancel := c.createRequestContext(opts)
+	defer cancel()
+
+	responses, err := c.ledger.QueryCollectionsConfig(reqCtx, chaincodeName, peersToTxnProcessors(targets), c.verifier)
+	if err != nil && len(responses) == 0 {
+		return nil, errors.WithMessage(err, "QueryCollectionsConfig failed")
+	}
+
+	if len(responses) < opts.MinTargets {
+		return nil, errors.Errorf("QueryCollectionsConfig: Number of responses %d is less than MinTargets %d", len(responses), opts.MinTargets)
+	}
+
+	if opts.Majority {
+		payloads := make([]proto.Message, len(responses))
+		for i, r := range responses {
+			payloads[i] = r
+		}
+		i, err := selectMajorityPayload(payloads, opts.MinTargets)
+		if err != nil {
+			return nil, err
+		}
+		return responses[i], nil
+	}
+
+	response := responses[0]
+	for _, r := range responses[1:] {
+		// All payloads have to match
+		if !proto.Equal(response, r) {
+			return nil, errors.New("Payloads for QueryCollectionsConfig do not match")
+		}
+	}
+
+	return response, nil
+}
+
+// QueryPrivateDataHashes queries the ledger for the given transaction and returns the hashes
+// of the private data read and written by the transaction, which allows private writes to be
+// verified (using HashKey and HashValue) without access to the private data itself.
+func (c *Client) QueryPrivateDataHashes(txID fab.TransactionID, options ...RequestOption) ([]*CollectionHashes, error) {
+	tx, err := c.QueryDecodedTransaction(txID, options...)
+	if err != nil {
+		return nil, err
+	}
+	return collectionHashes(tx), nil
+}
+
+func collectionHashes(tx *Transaction) []*CollectionHashes {
+	var hashes []*CollectionHashes
+	for _, action := range tx.Actions {
+		if action.RWSet == nil {
+			continue
+		}
+		for _, nsRWSet := range action.RWSet.NsRwSets {
+			for _, collRWSet := range nsRWSet.CollHashedRwSets {
+				h := &CollectionHashes{
+					Namespace:    nsRWSet.NameSpace,
+					Collection:   collRWSet.CollectionName,
+					PvtRwSetHash: collRWSet.PvtRwSetHash,
+				}
+				if collRWSet.HashedRwSet != nil {
+					h.Reads = collRWSet.HashedRwSet.HashedReads
+					h.Writes = collRWSet.HashedRwSet.HashedWrites
+				}
+				hashes = append(hashes, h)
+			}
+		}
+	}
+	return hashes
+}
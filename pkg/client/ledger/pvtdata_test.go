@@ -0,0 +1,95 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCollectionsConfig(t *testing.T) {
+	collConfig := &common.CollectionConfigPackage{
+		Config: []*common.CollectionConfig{
+			{
+				Payload: &common.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &common.StaticCollectionConfig{Name: "coll1"},
+				},
+			},
+		},
+	}
+	payload, err := proto.Marshal(collConfig)
+	require.NoError(t, err)
+
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Payload: payload, Status: 200, MockMSP: "test"}
+	lc := setupLedgerClient([]fab.Peer{&peer}, t)
+
+	response, err := lc.QueryCollectionsConfig("examplecc")
+	require.NoError(t, err)
+	require.Equal(t, 1, len(response.Config))
+	assert.Equal(t, "coll1", response.Config[0].GetStaticCollectionConfig().Name)
+
+	peer2 := mocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockRoles: []string{}, MockCert: nil, Payload: payload, Status: 405, MockMSP: "test"}
+	lc = setupLedgerClient([]fab.Peer{&peer, &peer2}, t)
+
+	_, err = lc.QueryCollectionsConfig("examplecc", WithMinTargets(2))
+	assert.Error(t, err)
+}
+
+func TestCollectionHashes(t *testing.T) {
+	tx := &Transaction{
+		Actions: []*Action{
+			{
+				RWSet: &rwsetutil.TxRwSet{
+					NsRwSets: []*rwsetutil.NsRwSet{
+						{
+							NameSpace: "examplecc",
+							KvRwSet:   &kvrwset.KVRWSet{},
+							CollHashedRwSets: []*rwsetutil.CollHashedRwSet{
+								{
+									CollectionName: "coll1",
+									PvtRwSetHash:   []byte("pvthash"),
+									HashedRwSet: &kvrwset.HashedRWSet{
+										HashedReads:  []*kvrwset.KVReadHash{{KeyHash: HashKey("key1")}},
+										HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: HashKey("key2"), ValueHash: HashValue([]byte("value2"))}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{},
+		},
+	}
+
+	hashes := collectionHashes(tx)
+	require.Equal(t, 1, len(hashes))
+
+	h := hashes[0]
+	assert.Equal(t, "examplecc", h.Namespace)
+	assert.Equal(t, "coll1", h.Collection)
+	assert.Equal(t, []byte("pvthash"), h.PvtRwSetHash)
+
+	_, ok := h.Read("key1")
+	assert.True(t, ok)
+	_, ok = h.Read("key2")
+	assert.False(t, ok)
+
+	write, ok := h.Write("key2")
+	require.True(t, ok)
+	assert.Equal(t, HashValue([]byte("value2")), write.ValueHash)
+	_, ok = h.Write("key1")
+	assert.False(t, ok)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultTailPageSize     = 10
+	defaultTailPollInterval = 5 * time.Second
+)
+
+// ErrTailClosed is returned by BlockTail.NextPage after the tail has been closed
+var ErrTailClosed = errors.New("block tail is closed")
+
+// TailOption describes a functional parameter for the Tail function
+type TailOption func(*BlockTail)
+
+// WithPageSize sets the maximum number of blocks returned by each call to NextPage
+func WithPageSize(pageSize int) TailOption {
+	return func(t *BlockTail) {
+		if pageSize > 0 {
+			t.pageSize = pageSize
+		}
+	}
+}
+
+// WithPollInterval sets the interval at which the chain info is polled while waiting for new blocks
+func WithPollInterval(interval time.Duration) TailOption {
+	return func(t *BlockTail) {
+		if interval > 0 {
+			t.pollInterval = interval
+		}
+	}
+}
+
+// WithTailRequestOptions sets the request options (targets, timeouts, etc.) used by the
+// chain info and block queries issued by the tail
+func WithTailRequestOptions(options ...RequestOption) TailOption {
+	return func(t *BlockTail) {
+		t.reqOpts = options
+	}
+}
+
+// BlockTail is a pull-based alternative to the event service that periodically
+// polls the chain info and retrieves new blocks in pages
+type BlockTail struct {
+	next         uint64
+	pageSize     int
+	pollInterval time.Duration
+	clock        clock.Clock
+	reqOpts      []RequestOption
+	queryHeight  func() (uint64, error)
+	queryBlock   func(blockNumber uint64) (*common.Block, error)
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+// Tail returns a BlockTail that retrieves the blocks of the channel starting at the given block number
+func (c *Client) Tail(from uint64, options ...TailOption) *BlockTail {
+	t := &BlockTail{
+		next:         from,
+		pageSize:     defaultTailPageSize,
+		pollInterval: defaultTailPollInterval,
+		clock:        c.ctx.Clock(),
+		done:         make(chan struct{}),
+	}
+
+	for _, opt := range options {
+		opt(t)
+	}
+
+	t.queryHeight = func() (uint64, error) {
+		info, err := c.QueryInfo(t.reqOpts...)
+		if err != nil {
+			return 0, err
+		}
+		return info.BCI.Height, nil
+	}
+	t.queryBlock = func(blockNumber uint64) (*common.Block, error) {
+		return c.QueryBlock(blockNumber, t.reqOpts...)
+	}
+
+	return t
+}
+
+// NextPage waits until at least one new block is available and returns the new blocks, up
+// to the configured page size. If an error occurs while retrieving a page then the blocks
+// retrieved so far are returned along with the error, and the next call resumes after the
+// last block returned.
+func (t *BlockTail) NextPage() ([]*common.Block, error) {
+	for {
+		select {
+		case <-t.done:
+			return nil, ErrTailClosed
+		default:
+		}
+
+		height, err := t.queryHeight()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to query chain info")
+		}
+
+		if height > t.next {
+			return t.fetchPage(height)
+		}
+
+		select {
+		case <-t.done:
+			return nil, ErrTailClosed
+		case <-t.clock.After(t.pollInterval):
+		}
+	}
+}
+
+// Next returns the number of the next block to be retrieved
+func (t *BlockTail) Next() uint64 {
+	return t.next
+}
+
+// Close stops the tail. Any pending call to NextPage returns ErrTailClosed.
+func (t *BlockTail) Close() {
+	t.closeOnce.Do(func() {
+		close(t.done)
+	})
+}
+
+func (t *BlockTail) fetchPage(height uint64) ([]*common.Block, error) {
+	end := t.next + uint64(t.pageSize)
+	if end > height {
+		end = height
+	}
+
+	var blocks []*common.Block
+	for t.next < end {
+		block, err := t.queryBlock(t.next)
+		if err != nil {
+			return blocks, errors.WithMessage(err, "failed to query block")
+		}
+		blocks = append(blocks, block)
+		t.next++
+	}
+	return blocks, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockTail(t *testing.T) {
+	var height uint64 = 5
+
+	tail := newTestTail(2, &height, WithPageSize(2), WithPollInterval(10*time.Millisecond))
+	defer tail.Close()
+
+	blocks, err := tail.NextPage()
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3}, blockNumbers(blocks))
+
+	blocks, err = tail.NextPage()
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{4}, blockNumbers(blocks))
+	assert.Equal(t, uint64(5), tail.Next())
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		atomic.StoreUint64(&height, 7)
+	}()
+
+	blocks, err = tail.NextPage()
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{5, 6}, blockNumbers(blocks))
+}
+
+func TestBlockTailClose(t *testing.T) {
+	var height uint64 = 1
+
+	tail := newTestTail(1, &height, WithPollInterval(10*time.Millisecond))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		tail.Close()
+	}()
+
+	_, err := tail.NextPage()
+	assert.Equal(t, ErrTailClosed, err)
+
+	tail.Close()
+}
+
+func TestBlockTailError(t *testing.T) {
+	var height uint64 = 3
+
+	tail := newTestTail(0, &height)
+	tail.queryBlock = func(blockNumber uint64) (*common.Block, error) {
+		if blockNumber == 1 {
+			return nil, errors.New("query block error")
+		}
+		return &common.Block{Header: &common.BlockHeader{Number: blockNumber}}, nil
+	}
+
+	blocks, err := tail.NextPage()
+	assert.Error(t, err)
+	assert.Equal(t, []uint64{0}, blockNumbers(blocks))
+	assert.Equal(t, uint64(1), tail.Next())
+
+	tail.queryHeight = func() (uint64, error) {
+		return 0, errors.New("query info error")
+	}
+	_, err = tail.NextPage()
+	assert.Error(t, err)
+}
+
+func newTestTail(from uint64, height *uint64, options ...TailOption) *BlockTail {
+	ctx := mocks.NewMockChannelContext(&mocks.MockContext{MockProviderContext: mocks.NewMockProviderContext()}, "mychannel")
+	tail := (&Client{ctx: ctx}).Tail(from, options...)
+	tail.queryHeight = func() (uint64, error) {
+		return atomic.LoadUint64(height), nil
+	}
+	tail.queryBlock = func(blockNumber uint64) (*common.Block, error) {
+		return &common.Block{Header: &common.BlockHeader{Number: blockNumber}}, nil
+	}
+	return tail
+}
+
+func blockNumbers(blocks []*common.Block) []uint64 {
+	var numbers []uint64
+	for _, block := range blocks {
+		numbers = append(numbers, block.Header.Number)
+	}
+	return numbers
+}
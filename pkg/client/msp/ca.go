@@ -6,6 +6,10 @@ SPDX-License-Identifier: Apache-2.0
 
 package msp
 
+import (
+	"time"
+)
+
 // AttributeRequest is a request for an attribute.
 type AttributeRequest struct {
 	Name     string
@@ -72,3 +76,160 @@ type RevokedCert struct {
 	// AKI of the revoked certificate
 	AKI string
 }
+
+// IdentityRequest defines the attributes required to modify an identity registered with the CA
+type IdentityRequest struct {
+	// ID is the unique name of the identity
+	ID string
+	// Type of identity (e.g. "peer, app, user")
+	Type string
+	// MaxEnrollments is the number of times the secret can be reused to enroll
+	MaxEnrollments int
+	// The identity's affiliation e.g. org1.department1
+	Affiliation string
+	// Attributes associated with this identity
+	Attributes []Attribute
+	// Secret is an optional new enrollment secret for the identity
+	Secret string
+	// CAName is the name of the CA to connect to
+	CAName string
+}
+
+// RemoveIdentityRequest defines the attributes required to remove an identity registered with the CA
+type RemoveIdentityRequest struct {
+	// ID is the unique name of the identity
+	ID string
+	// Force forces removal of the identity's own identity
+	Force bool
+	// CAName is the name of the CA to connect to
+	CAName string
+}
+
+// IdentityResponse represents the response from the server for an identity request
+type IdentityResponse struct {
+	// ID is the unique name of the identity
+	ID string
+	// Type of identity (e.g. "peer, app, user")
+	Type string
+	// MaxEnrollments is the number of times the secret can be reused to enroll
+	MaxEnrollments int
+	// The identity's affiliation e.g. org1.department1
+	Affiliation string
+	// Attributes associated with this identity
+	Attributes []Attribute
+	// Secret is the enrollment secret, returned only when it was modified
+	Secret string
+	// CAName is the name of the CA that processed the request
+	CAName string
+}
+
+// AffiliationRequest defines the attributes required to add or remove an affiliation of the CA
+type AffiliationRequest struct {
+	// Name is the name of the affiliation (e.g. org1.department1)
+	Name string
+	// Force creates the missing parent affiliations when adding an affiliation, and removes the
+	// child affiliations and the identities of an affiliation when removing it
+	Force bool
+	// CAName is the name of the CA to connect to
+	CAName string
+}
+
+// ModifyAffiliationRequest defines the attributes required to rename an affiliation of the CA
+type ModifyAffiliationRequest struct {
+	AffiliationRequest
+	// NewName is the new name of the affiliation
+	NewName string
+}
+
+// AffiliationResponse represents the response from the server for an affiliation request
+type AffiliationResponse struct {
+	AffiliationInfo
+	// CAName is the name of the CA that processed the request
+	CAName string
+}
+
+// AffiliationInfo contains the name of an affiliation, its child affiliations and its identities
+type AffiliationInfo struct {
+	// Name is the name of the affiliation
+	Name string
+	// Affiliations are the child affiliations
+	Affiliations []AffiliationInfo
+	// Identities are the identities of the affiliation
+	Identities []IdentityInfo
+}
+
+// IdentityInfo contains information about an identity of an affiliation
+type IdentityInfo struct {
+	// ID is the unique name of the identity
+	ID string
+	// Type of identity (e.g. "peer, app, user")
+	Type string
+	// The identity's affiliation e.g. org1.department1
+	Affiliation string
+	// Attributes associated with this identity
+	Attributes []Attribute
+	// MaxEnrollments is the number of times the secret can be reused to enroll
+	MaxEnrollments int
+}
+
+// GetCAInfoResponse is the response to a request for the information of a CA
+type GetCAInfoResponse struct {
+	// CAName is the name of the CA
+	CAName string
+	// CAChain is the PEM-encoded certificate chain of the CA. The first certificate is the root CA certificate.
+	CAChain []byte
+	// IssuerPublicKey is the Idemix issuer public key, if the CA issues Idemix credentials
+	IssuerPublicKey []byte
+	// IssuerRevocationPublicKey is the PEM-encoded Idemix issuer revocation public key, if the CA issues Idemix credentials
+	IssuerRevocationPublicKey []byte
+	// Version is the version of the CA server
+	Version string
+}
+
+// GetCertificatesRequest is a request for the certificates issued by a CA. The zero values of the fields don't filter the certificates.
+type GetCertificatesRequest struct {
+	// ID returns the certificates of this enrollment ID
+	ID string
+	// AKI returns the certificates with this authority key identifier
+	AKI string
+	// Serial returns the certificates with this serial number
+	Serial string
+	// RevokedStart and RevokedEnd return the certificates revoked within this period
+	RevokedStart time.Time
+	RevokedEnd   time.Time
+	// ExpiredStart and ExpiredEnd return the certificates that expired within this period
+	ExpiredStart time.Time
+	ExpiredEnd   time.Time
+	// NotExpired excludes the expired certificates
+	NotExpired bool
+	// NotRevoked excludes the revoked certificates
+	NotRevoked bool
+	// CAName is the name of the CA to connect to
+	CAName string
+}
+
+// GetCertificatesResponse is the response to a request for certificates
+type GetCertificatesResponse struct {
+	// CAName is the name of the CA
+	CAName string
+	// Certs are the PEM-encoded certificates
+	Certs [][]byte
+}
+
+// GenCRLRequest is a request to generate a CRL. The zero values of the fields don't filter the revoked certificates.
+type GenCRLRequest struct {
+	// CAName is the name of the CA to connect to
+	CAName string
+	// RevokedAfter and RevokedBefore include the certificates revoked within this period
+	RevokedAfter  time.Time
+	RevokedBefore time.Time
+	// ExpireAfter and ExpireBefore include the certificates that expire within this period
+	ExpireAfter  time.Time
+	ExpireBefore time.Time
+}
+
+// GenCRLResponse is the response to a request to generate a CRL
+type GenCRLResponse struct {
+	// CRL is the PEM-encoded CRL that contains the requested unexpired revoked certificates
+	CRL []byte
+}
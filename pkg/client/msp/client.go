@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	reqContext "context"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
@@ -18,6 +20,7 @@ import (
 type Client struct {
 	orgName string
 	ctx     context.Client
+	reqCtx  reqContext.Context
 }
 
 // ClientOption describes a functional parameter for the New constructor
@@ -61,19 +64,37 @@ func New(clientProvider context.ClientProvider, opts ...ClientOption) (*Client,
 	return &msp, nil
 }
 
-func newCAClient(ctx context.Client, orgName string) (mspapi.CAClient, error) {
+// WithContext returns a copy of the client whose CA operations are bound to the given context,
+// so that the requests to the CA are cancelled when the context is done (e.g. when its deadline
+// expires)
+func (c *Client) WithContext(ctx reqContext.Context) *Client {
+	client := *c
+	client.reqCtx = ctx
+	return &client
+}
+
+func (c *Client) newCAClient() (mspapi.CAClient, error) {
 
-	caClient, err := msp.NewCAClient(orgName, ctx)
+	caClient, err := msp.NewCAClient(c.orgName, c.ctx)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to create CA Client")
 	}
 
+	if c.reqCtx != nil {
+		return caClient.WithContext(c.reqCtx), nil
+	}
 	return caClient, nil
 }
 
 // enrollmentOptions represent enrollment options
 type enrollmentOptions struct {
-	secret string
+	secret     string
+	profile    string
+	label      string
+	cn         string
+	hosts      []string
+	keyRequest *mspapi.KeyRequest
+	attrReqs   []*AttributeRequest
 }
 
 // EnrollmentOption describes a functional parameter for Enroll
@@ -87,6 +108,82 @@ func WithSecret(secret string) EnrollmentOption {
 	}
 }
 
+// WithProfile enrollment option sets the signing profile used by the CA to issue
+// the certificate (e.g. "tls")
+func WithProfile(profile string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.profile = profile
+		return nil
+	}
+}
+
+// WithLabel enrollment option sets the label used by the CA in HSM operations
+func WithLabel(label string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.label = label
+		return nil
+	}
+}
+
+// WithCN enrollment option sets the common name of the certificate request.
+// By default the enrollment ID is used. Note that the CA may require the common
+// name to be the enrollment ID.
+func WithCN(cn string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		if cn == "" {
+			return errors.New("common name is empty")
+		}
+		o.cn = cn
+		return nil
+	}
+}
+
+// WithCSRHosts enrollment option sets the subject alternative names (host names
+// or IP addresses) of the certificate request, e.g. to request a TLS certificate.
+// By default the host name of the local machine is used.
+func WithCSRHosts(hosts ...string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		for _, host := range hosts {
+			if host == "" {
+				return errors.New("host is empty")
+			}
+		}
+		o.hosts = append(o.hosts, hosts...)
+		return nil
+	}
+}
+
+// WithKeyRequest enrollment option sets the algorithm ("ecdsa" or "rsa") and the size
+// in bits of the key pair generated for the certificate request. By default an
+// ECDSA P-256 key pair is generated.
+func WithKeyRequest(algo string, size int) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		if algo != "ecdsa" && algo != "rsa" {
+			return errors.Errorf("unsupported key algorithm: %s", algo)
+		}
+		if size <= 0 {
+			return errors.Errorf("invalid key size: %d", size)
+		}
+		o.keyRequest = &mspapi.KeyRequest{Algo: algo, Size: size}
+		return nil
+	}
+}
+
+// WithEnrollmentAttributeRequests enrollment option requests attributes of the user to be
+// embedded in the enrollment certificate, e.g. for attribute-based access control in chaincode.
+// Each attribute is added only if the user owns it; the enrollment fails if the user doesn't own
+// an attribute that isn't optional. By default the CA adds the attributes that were registered
+// as default attributes (ecert) of the user.
+func WithEnrollmentAttributeRequests(attrReqs ...*AttributeRequest) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		if err := validateAttributeRequests(attrReqs); err != nil {
+			return err
+		}
+		o.attrReqs = append(o.attrReqs, attrReqs...)
+		return nil
+	}
+}
+
 // Enroll enrolls a registered user in order to receive a signed X509 certificate.
 // A new key pair is generated for the user. The private key and the
 // enrollment certificate issued by the CA are stored in SDK stores.
@@ -104,27 +201,107 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 		}
 	}
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.newCAClient()
 	if err != nil {
 		return err
 	}
-	return ca.Enroll(enrollmentID, eo.secret)
+	req := &mspapi.EnrollmentRequest{
+		Name:     enrollmentID,
+		Secret:   eo.secret,
+		Profile:  eo.profile,
+		Label:    eo.label,
+		AttrReqs: mspAttributeRequests(eo.attrReqs),
+	}
+	if eo.cn != "" || len(eo.hosts) > 0 || eo.keyRequest != nil {
+		req.CSR = &mspapi.CSRInfo{
+			CN:         eo.cn,
+			Hosts:      eo.hosts,
+			KeyRequest: eo.keyRequest,
+		}
+	}
+	return ca.Enroll(req)
+}
+
+type reenrollmentOptions struct {
+	newKey   bool
+	attrReqs []*AttributeRequest
+}
+
+// ReenrollmentOption describes a functional parameter for Reenroll
+type ReenrollmentOption func(*reenrollmentOptions) error
+
+// WithNewKey reenrollment option rotates the private key of the user: a new key pair is
+// generated and stored in the key store, and the certificate is issued for the new key.
+// By default the certificate is issued for the current key of the user.
+func WithNewKey() ReenrollmentOption {
+	return func(o *reenrollmentOptions) error {
+		o.newKey = true
+		return nil
+	}
+}
+
+// WithAttributeRequests reenrollment option requests attributes of the user to be added to
+// the certificate. Each attribute is added only if the user owns it; the reenrollment fails
+// if the user doesn't own an attribute that isn't optional.
+func WithAttributeRequests(attrReqs ...*AttributeRequest) ReenrollmentOption {
+	return func(o *reenrollmentOptions) error {
+		if err := validateAttributeRequests(attrReqs); err != nil {
+			return err
+		}
+		o.attrReqs = append(o.attrReqs, attrReqs...)
+		return nil
+	}
 }
 
-// Reenroll reenrolls an enrolled user in order to obtain a new signed X509 certificate
-func (c *Client) Reenroll(enrollmentID string) error {
-	ca, err := newCAClient(c.ctx, c.orgName)
+func validateAttributeRequests(attrReqs []*AttributeRequest) error {
+	for _, attrReq := range attrReqs {
+		if attrReq == nil || attrReq.Name == "" {
+			return errors.New("attribute name is empty")
+		}
+	}
+	return nil
+}
+
+func mspAttributeRequests(attrReqs []*AttributeRequest) []*mspapi.AttributeRequest {
+	var reqs []*mspapi.AttributeRequest
+	for _, attrReq := range attrReqs {
+		reqs = append(reqs, &mspapi.AttributeRequest{Name: attrReq.Name, Optional: attrReq.Optional})
+	}
+	return reqs
+}
+
+// Reenroll reenrolls an enrolled user in order to obtain a new signed X509 certificate.
+// The user record is replaced in the user store once the certificate has been issued.
+//
+// enrollmentID enrollment ID of an enrolled user
+// opts represent reenrollment options
+func (c *Client) Reenroll(enrollmentID string, opts ...ReenrollmentOption) error {
+
+	ro := reenrollmentOptions{}
+	for _, param := range opts {
+		err := param(&ro)
+		if err != nil {
+			return errors.WithMessage(err, "failed to reenroll")
+		}
+	}
+
+	ca, err := c.newCAClient()
 	if err != nil {
 		return err
 	}
-	return ca.Reenroll(enrollmentID)
+	req := &mspapi.ReenrollmentRequest{
+		Name:     enrollmentID,
+		NewKey:   ro.newKey,
+		AttrReqs: mspAttributeRequests(ro.attrReqs),
+	}
+	return ca.Reenroll(req)
 }
 
 // Register registers a User with the Fabric CA
 // request: Registration Request
 // Returns Enrolment Secret
 func (c *Client) Register(request *RegistrationRequest) (string, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.newCAClient()
 	if err != nil {
 		return "", err
 	}
@@ -149,7 +326,7 @@ func (c *Client) Register(request *RegistrationRequest) (string, error) {
 // Revoke revokes a User with the Fabric CA
 // request: Revocation Request
 func (c *Client) Revoke(request *RevocationRequest) (*RevocationResponse, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.newCAClient()
 	if err != nil {
 		return nil, err
 	}
@@ -174,6 +351,273 @@ func (c *Client) Revoke(request *RevocationRequest) (*RevocationResponse, error)
 	}, nil
 }
 
+// GetIdentity returns information about the requested identity
+// id: The identity to retrieve
+// caname: The name of the CA to connect to (optional)
+func (c *Client) GetIdentity(id, caname string) (*IdentityResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ca.GetIdentity(id, caname)
+	if err != nil {
+		return nil, err
+	}
+	return getIdentityResponse(resp), nil
+}
+
+// GetAllIdentities returns all identities that the registrar is authorized to see
+// caname: The name of the CA to connect to (optional)
+func (c *Client) GetAllIdentities(caname string) ([]*IdentityResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ca.GetAllIdentities(caname)
+	if err != nil {
+		return nil, err
+	}
+	var identities []*IdentityResponse
+	for _, identity := range resp {
+		identities = append(identities, getIdentityResponse(identity))
+	}
+	return identities, nil
+}
+
+// ModifyIdentity modifies an identity registered with the Fabric CA
+// request: Identity Request
+func (c *Client) ModifyIdentity(request *IdentityRequest) (*IdentityResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, errors.New("identity request is required")
+	}
+
+	var a []mspapi.Attribute
+	for i := range request.Attributes {
+		a = append(a, mspapi.Attribute{Name: request.Attributes[i].Name, Value: request.Attributes[i].Value, ECert: request.Attributes[i].ECert})
+	}
+
+	r := mspapi.IdentityRequest{
+		ID:             request.ID,
+		Type:           request.Type,
+		MaxEnrollments: request.MaxEnrollments,
+		Affiliation:    request.Affiliation,
+		Attributes:     a,
+		Secret:         request.Secret,
+		CAName:         request.CAName,
+	}
+	resp, err := ca.ModifyIdentity(&r)
+	if err != nil {
+		return nil, err
+	}
+	return getIdentityResponse(resp), nil
+}
+
+// RemoveIdentity removes an identity registered with the Fabric CA
+// request: Remove Identity Request
+func (c *Client) RemoveIdentity(request *RemoveIdentityRequest) (*IdentityResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, errors.New("remove identity request is required")
+	}
+	req := mspapi.RemoveIdentityRequest(*request)
+	resp, err := ca.RemoveIdentity(&req)
+	if err != nil {
+		return nil, err
+	}
+	return getIdentityResponse(resp), nil
+}
+
+func getIdentityResponse(resp *mspapi.IdentityResponse) *IdentityResponse {
+	var a []Attribute
+	for i := range resp.Attributes {
+		a = append(a, Attribute{Name: resp.Attributes[i].Name, Value: resp.Attributes[i].Value, ECert: resp.Attributes[i].ECert})
+	}
+	return &IdentityResponse{
+		ID:             resp.ID,
+		Type:           resp.Type,
+		MaxEnrollments: resp.MaxEnrollments,
+		Affiliation:    resp.Affiliation,
+		Attributes:     a,
+		Secret:         resp.Secret,
+		CAName:         resp.CAName,
+	}
+}
+
+// GetAffiliation returns information about the requested affiliation
+// affiliation: The affiliation to retrieve (e.g. org1.department1)
+// caname: The name of the CA to connect to (optional)
+func (c *Client) GetAffiliation(affiliation, caname string) (*AffiliationResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ca.GetAffiliation(affiliation, caname)
+	if err != nil {
+		return nil, err
+	}
+	return getAffiliationResponse(resp), nil
+}
+
+// GetAllAffiliations returns all affiliations that the registrar is authorized to see
+// caname: The name of the CA to connect to (optional)
+func (c *Client) GetAllAffiliations(caname string) (*AffiliationResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ca.GetAllAffiliations(caname)
+	if err != nil {
+		return nil, err
+	}
+	return getAffiliationResponse(resp), nil
+}
+
+// AddAffiliation adds an affiliation to the Fabric CA
+// request: Affiliation Request
+func (c *Client) AddAffiliation(request *AffiliationRequest) (*AffiliationResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, errors.New("affiliation request is required")
+	}
+	req := mspapi.AffiliationRequest(*request)
+	resp, err := ca.AddAffiliation(&req)
+	if err != nil {
+		return nil, err
+	}
+	return getAffiliationResponse(resp), nil
+}
+
+// ModifyAffiliation renames an affiliation of the Fabric CA
+// request: Modify Affiliation Request
+func (c *Client) ModifyAffiliation(request *ModifyAffiliationRequest) (*AffiliationResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, errors.New("modify affiliation request is required")
+	}
+	req := mspapi.ModifyAffiliationRequest{
+		AffiliationRequest: mspapi.AffiliationRequest(request.AffiliationRequest),
+		NewName:            request.NewName,
+	}
+	resp, err := ca.ModifyAffiliation(&req)
+	if err != nil {
+		return nil, err
+	}
+	return getAffiliationResponse(resp), nil
+}
+
+// RemoveAffiliation removes an affiliation from the Fabric CA
+// request: Affiliation Request
+func (c *Client) RemoveAffiliation(request *AffiliationRequest) (*AffiliationResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, errors.New("affiliation request is required")
+	}
+	req := mspapi.AffiliationRequest(*request)
+	resp, err := ca.RemoveAffiliation(&req)
+	if err != nil {
+		return nil, err
+	}
+	return getAffiliationResponse(resp), nil
+}
+
+// GetCAInfo returns the information of the CA, such as its certificate chain
+// caname: The name of the CA to connect to (optional)
+func (c *Client) GetCAInfo(caname string) (*GetCAInfoResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ca.GetCAInfo(caname)
+	if err != nil {
+		return nil, err
+	}
+	info := GetCAInfoResponse(*resp)
+	return &info, nil
+}
+
+// GetCertificates returns the certificates issued by the CA that match the request and that the registrar is authorized to see
+// request: Certificates Request
+func (c *Client) GetCertificates(request *GetCertificatesRequest) (*GetCertificatesResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, errors.New("certificates request is required")
+	}
+	req := mspapi.GetCertificatesRequest(*request)
+	resp, err := ca.GetCertificates(&req)
+	if err != nil {
+		return nil, err
+	}
+	certs := GetCertificatesResponse(*resp)
+	return &certs, nil
+}
+
+// GenCRL generates a CRL containing the unexpired certificates revoked by the CA that match the request.
+// The CRL can be added to the revocation list of the organization's MSP in a channel config update.
+// request: GenCRL Request
+func (c *Client) GenCRL(request *GenCRLRequest) (*GenCRLResponse, error) {
+	ca, err := c.newCAClient()
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, errors.New("gencrl request is required")
+	}
+	req := mspapi.GenCRLRequest(*request)
+	resp, err := ca.GenCRL(&req)
+	if err != nil {
+		return nil, err
+	}
+	crl := GenCRLResponse(*resp)
+	return &crl, nil
+}
+
+func getAffiliationResponse(resp *mspapi.AffiliationResponse) *AffiliationResponse {
+	return &AffiliationResponse{
+		AffiliationInfo: getAffiliationInfo(resp.AffiliationInfo),
+		CAName:          resp.CAName,
+	}
+}
+
+func getAffiliationInfo(info mspapi.AffiliationInfo) AffiliationInfo {
+	a := AffiliationInfo{Name: info.Name}
+	for _, child := range info.Affiliations {
+		a.Affiliations = append(a.Affiliations, getAffiliationInfo(child))
+	}
+	for _, identity := range info.Identities {
+		var attrs []Attribute
+		for i := range identity.Attributes {
+			attrs = append(attrs, Attribute{Name: identity.Attributes[i].Name, Value: identity.Attributes[i].Value, ECert: identity.Attributes[i].ECert})
+		}
+		a.Identities = append(a.Identities, IdentityInfo{
+			ID:             identity.ID,
+			Type:           identity.Type,
+			Affiliation:    identity.Affiliation,
+			Attributes:     attrs,
+			MaxEnrollments: identity.MaxEnrollments,
+		})
+	}
+	return a
+}
+
 // GetSigningIdentity returns signing identity for id
 func (c *Client) GetSigningIdentity(id string) (mspctx.SigningIdentity, error) {
 	im, _ := c.ctx.IdentityManager(c.orgName)
@@ -186,3 +630,32 @@ func (c *Client) GetSigningIdentity(id string) (mspctx.SigningIdentity, error) {
 	}
 	return si, nil
 }
+
+// adminIdentityResolver is implemented by identity managers that can resolve the admin of their organization
+type adminIdentityResolver interface {
+	GetAdminSigningIdentity(adminIDs ...string) (mspctx.SigningIdentity, error)
+}
+
+// GetAdminSigningIdentity returns the signing identity of the admin of the client's organization, for
+// use with the resource management client (see fabsdk.WithIdentity). The admin is looked up under each of
+// the given names in turn ("Admin" if none is given) in the user store, the embedded users and the MSP
+// directories of the organization, and is validated against its MSP directory (admin certificates and
+// NodeOUs) if the directory exists. ErrUserNotFound is returned if the admin isn't found.
+func (c *Client) GetAdminSigningIdentity(adminIDs ...string) (mspctx.SigningIdentity, error) {
+	im, ok := c.ctx.IdentityManager(c.orgName)
+	if !ok {
+		return nil, errors.Errorf("identity manager not found for org [%s]", c.orgName)
+	}
+	resolver, ok := im.(adminIdentityResolver)
+	if !ok {
+		return nil, errors.New("identity manager doesn't support resolving the admin identity")
+	}
+	si, err := resolver.GetAdminSigningIdentity(adminIDs...)
+	if err != nil {
+		if err == mspctx.ErrUserNotFound {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return si, nil
+}
@@ -7,15 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	reqContext "context"
+	"crypto/x509"
+	"encoding/pem"
 	"math/rand"
-	"net"
 	"strconv"
-	"strings"
 	"testing"
+	"time"
 
 	"fmt"
 	"os"
 
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/attrmgr"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -27,12 +30,11 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
 	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
-	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockca"
 )
 
 const (
-	caServerURLListen = "http://localhost:0"
-	configPath        = "../../core/config/testdata/config_test.yaml"
+	configPath = "../../core/config/testdata/config_test.yaml"
 )
 
 var caServerURL string
@@ -82,11 +84,139 @@ func TestMSP(t *testing.T) {
 		t.Fatalf("Reenroll return error %v", err)
 	}
 
+	// Reenroll with a new key
+	err = msp.Reenroll(enrolledUser.Identifier().ID, WithNewKey(), WithAttributeRequests(&AttributeRequest{Name: "hf.Type", Optional: true}))
+	if err != nil {
+		t.Fatalf("Reenroll with new key return error %v", err)
+	}
+	err = msp.Reenroll(enrolledUser.Identifier().ID, WithAttributeRequests(&AttributeRequest{}))
+	if err == nil {
+		t.Fatalf("Reenroll should return error for empty attribute name")
+	}
+
 	// Try with a non-default org
 	testWithOrg2(t, ctxProvider)
 
 }
 
+// TestEnrollWithCSR tests enrollment with CSR options
+func TestEnrollWithCSR(t *testing.T) {
+	f := textFixture{}
+	sdk := f.setup()
+	defer f.close()
+
+	msp, err := New(sdk.Context())
+	if err != nil {
+		t.Fatalf("failed to create CA client: %v", err)
+	}
+
+	enrollUsername := randomUsername()
+	err = msp.Enroll(enrollUsername, WithSecret("enrollmentSecret"), WithProfile("tls"),
+		WithCSRHosts("peer0.org1.example.com", "localhost"), WithKeyRequest("ecdsa", 384))
+	if err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+
+	enrolledUser, err := msp.GetSigningIdentity(enrollUsername)
+	if err != nil {
+		t.Fatalf("Expected to find user")
+	}
+	block, _ := pem.Decode(enrolledUser.EnrollmentCertificate())
+	if block == nil {
+		t.Fatalf("Expected PEM-encoded enrollment certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse enrollment certificate: %s", err)
+	}
+	if len(cert.DNSNames) != 2 || cert.DNSNames[0] != "peer0.org1.example.com" || cert.DNSNames[1] != "localhost" {
+		t.Fatalf("Expected requested hosts in certificate, got %v", cert.DNSNames)
+	}
+
+	// Invalid options
+	if err = msp.Enroll(randomUsername(), WithSecret("enrollmentSecret"), WithKeyRequest("dsa", 1024)); err == nil {
+		t.Fatalf("Enroll should return error for unsupported key algorithm")
+	}
+	if err = msp.Enroll(randomUsername(), WithSecret("enrollmentSecret"), WithCSRHosts("")); err == nil {
+		t.Fatalf("Enroll should return error for empty host")
+	}
+	if err = msp.Enroll(randomUsername(), WithSecret("enrollmentSecret"), WithCN("")); err == nil {
+		t.Fatalf("Enroll should return error for empty common name")
+	}
+}
+
+// TestEnrollWithAttributeRequests tests enrollment with attribute requests
+func TestEnrollWithAttributeRequests(t *testing.T) {
+	f := textFixture{}
+	sdk := f.setup()
+	defer f.close()
+
+	msp, err := New(sdk.Context())
+	if err != nil {
+		t.Fatalf("failed to create CA client: %v", err)
+	}
+
+	enrollUsername := randomUsername()
+	err = msp.Enroll(enrollUsername, WithSecret("enrollmentSecret"),
+		WithEnrollmentAttributeRequests(&AttributeRequest{Name: "hf.EnrollmentID"}, &AttributeRequest{Name: "unknown", Optional: true}))
+	if err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+
+	enrolledUser, err := msp.GetSigningIdentity(enrollUsername)
+	if err != nil {
+		t.Fatalf("Expected to find user")
+	}
+	block, _ := pem.Decode(enrolledUser.EnrollmentCertificate())
+	if block == nil {
+		t.Fatalf("Expected PEM-encoded enrollment certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse enrollment certificate: %s", err)
+	}
+	attrs, err := attrmgr.New().GetAttributesFromCert(cert)
+	if err != nil {
+		t.Fatalf("Failed to get attributes from certificate: %s", err)
+	}
+	if names := attrs.Names(); len(names) != 1 || attrs.Attrs["hf.EnrollmentID"] != enrollUsername {
+		t.Fatalf("Expected only the requested attribute in certificate, got %v", attrs.Attrs)
+	}
+
+	if err = msp.Enroll(randomUsername(), WithSecret("enrollmentSecret"), WithEnrollmentAttributeRequests(&AttributeRequest{Name: "unknown"})); err == nil {
+		t.Fatalf("Enroll should return error for required attribute that the user doesn't have")
+	}
+	if err = msp.Enroll(randomUsername(), WithSecret("enrollmentSecret"), WithEnrollmentAttributeRequests(&AttributeRequest{})); err == nil {
+		t.Fatalf("Enroll should return error for empty attribute name")
+	}
+}
+
+// TestEnrollWithContext tests that the CA operations are bound to the client's context
+func TestEnrollWithContext(t *testing.T) {
+	f := textFixture{}
+	sdk := f.setup()
+	defer f.close()
+
+	msp, err := New(sdk.Context())
+	if err != nil {
+		t.Fatalf("failed to create CA client: %v", err)
+	}
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 10*time.Second)
+	defer cancel()
+	if err := msp.WithContext(ctx).Enroll(randomUsername(), WithSecret("enrollmentSecret")); err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+
+	cancel()
+	if err := msp.WithContext(ctx).Enroll(randomUsername(), WithSecret("enrollmentSecret")); err == nil {
+		t.Fatalf("Enroll should return error for cancelled context")
+	}
+	if err := msp.Enroll(randomUsername(), WithSecret("enrollmentSecret")); err != nil {
+		t.Fatalf("Enroll without context return error %v", err)
+	}
+}
+
 func testWithOrg2(t *testing.T, ctxProvider contextApi.ClientProvider) {
 	msp, err := New(ctxProvider, WithOrg("Org2"))
 	if err != nil {
@@ -154,19 +284,16 @@ type textFixture struct {
 	identityConfig    msp.IdentityConfig
 }
 
-var caServer = &mockmsp.MockFabricCAServer{}
+var caServer = mockca.New(mockca.WithOpenEnrollment())
 
 func (f *textFixture) setup() *fabsdk.FabricSDK {
 
-	var lis net.Listener
-	var err error
+	// Start Http Server if it's not running
 	if !caServer.Running() {
-		lis, err = net.Listen("tcp", strings.TrimPrefix(caServerURLListen, "http://"))
-		if err != nil {
+		if err := caServer.Start(); err != nil {
 			panic(fmt.Sprintf("Error starting CA Server %s", err))
 		}
-
-		caServerURL = "http://" + lis.Addr().String()
+		caServerURL = caServer.URL()
 	}
 
 	backend, err := config.FromFile(configPath)()
@@ -203,17 +330,6 @@ func (f *textFixture) setup() *fabsdk.FabricSDK {
 	cleanup(f.cryptoSuiteConfig.KeyStorePath())
 	cleanup(f.identityConfig.CredentialStorePath())
 
-	ctxProvider := sdk.Context()
-	ctx, err := ctxProvider()
-	if err != nil {
-		panic(fmt.Sprintf("Failed to init context: %v", err))
-	}
-
-	// Start Http Server if it's not running
-	if !caServer.Running() {
-		caServer.Start(lis, ctx.CryptoSuite())
-	}
-
 	return sdk
 }
 
@@ -0,0 +1,406 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package jsoncmd executes identity management commands that read their request from JSON and write
+// their response as JSON. It allows a thin command line tool to script the enrollment, registration,
+// revocation, identity and affiliation operations of a Fabric CA through the same msp.Client that
+// is used by applications.
+//
+// The fields of a request are named after the fields of the corresponding msp request (matched case
+// insensitively), e.g. the request of the "register" command is a msp.RegistrationRequest:
+//
+//  {"name": "user1", "type": "client", "affiliation": "org1.department1"}
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create msp client
+//  3) Create executor
+//  4) Execute commands
+//
+//  executor := jsoncmd.New(mspClient)
+//  err := executor.Execute("register", os.Stdin, os.Stdout)
+package jsoncmd
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+)
+
+// Client is the subset of msp.Client used by the executor
+type Client interface {
+	Enroll(enrollmentID string, opts ...msp.EnrollmentOption) error
+	Reenroll(enrollmentID string, opts ...msp.ReenrollmentOption) error
+	Register(request *msp.RegistrationRequest) (string, error)
+	Revoke(request *msp.RevocationRequest) (*msp.RevocationResponse, error)
+	GetIdentity(id, caname string) (*msp.IdentityResponse, error)
+	GetAllIdentities(caname string) ([]*msp.IdentityResponse, error)
+	ModifyIdentity(request *msp.IdentityRequest) (*msp.IdentityResponse, error)
+	RemoveIdentity(request *msp.RemoveIdentityRequest) (*msp.IdentityResponse, error)
+	GetAffiliation(affiliation, caname string) (*msp.AffiliationResponse, error)
+	GetAllAffiliations(caname string) (*msp.AffiliationResponse, error)
+	AddAffiliation(request *msp.AffiliationRequest) (*msp.AffiliationResponse, error)
+	ModifyAffiliation(request *msp.ModifyAffiliationRequest) (*msp.AffiliationResponse, error)
+	RemoveAffiliation(request *msp.AffiliationRequest) (*msp.AffiliationResponse, error)
+	GetCAInfo(caname string) (*msp.GetCAInfoResponse, error)
+	GetCertificates(request *msp.GetCertificatesRequest) (*msp.GetCertificatesResponse, error)
+	GenCRL(request *msp.GenCRLRequest) (*msp.GenCRLResponse, error)
+	GetSigningIdentity(id string) (mspctx.SigningIdentity, error)
+}
+
+// Executor executes identity management commands
+type Executor struct {
+	client Client
+}
+
+// New returns an executor of the commands on the given client (usually a *msp.Client)
+func New(client Client) *Executor {
+	return &Executor{client: client}
+}
+
+// command decodes its request from the given decoder and returns the response to encode
+type command func(c Client, dec *json.Decoder) (interface{}, error)
+
+var commands = map[string]command{
+	"enroll":             enroll,
+	"reenroll":           reenroll,
+	"register":           register,
+	"revoke":             revoke,
+	"gencrl":             genCRL,
+	"cainfo":             caInfo,
+	"certificates.list":  listCertificates,
+	"identity.get":       getIdentity,
+	"identity.list":      listIdentities,
+	"identity.modify":    modifyIdentity,
+	"identity.remove":    removeIdentity,
+	"affiliation.get":    getAffiliation,
+	"affiliation.list":   listAffiliations,
+	"affiliation.add":    addAffiliation,
+	"affiliation.modify": modifyAffiliation,
+	"affiliation.remove": removeAffiliation,
+}
+
+// Commands returns the names of the supported commands
+func Commands() []string {
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Execute executes the given command with the JSON request read from in and writes the JSON response to out.
+// An empty input is an empty request. Unknown fields in the request are rejected so that a misspelled field
+// doesn't silently change the operation.
+func (e *Executor) Execute(name string, in io.Reader, out io.Writer) error {
+	cmd, ok := commands[name]
+	if !ok {
+		return errors.Errorf("unknown command: %s", name)
+	}
+
+	dec := json.NewDecoder(in)
+	dec.DisallowUnknownFields()
+
+	resp, err := cmd(e.client, dec)
+	if err != nil {
+		return errors.WithMessage(err, name+" failed")
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return errors.Wrap(enc.Encode(resp), "encoding response failed")
+}
+
+// decode decodes the request. An empty input leaves the request unchanged.
+func decode(dec *json.Decoder, req interface{}) error {
+	if err := dec.Decode(req); err != nil && err != io.EOF {
+		return errors.Wrap(err, "decoding request failed")
+	}
+	return nil
+}
+
+// EnrollRequest is the request of the "enroll" command
+type EnrollRequest struct {
+	EnrollmentID string                  `json:"enrollmentID"`
+	Secret       string                  `json:"secret,omitempty"`
+	Profile      string                  `json:"profile,omitempty"`
+	Label        string                  `json:"label,omitempty"`
+	CN           string                  `json:"cn,omitempty"`
+	Hosts        []string                `json:"hosts,omitempty"`
+	KeyRequest   *KeyRequest             `json:"keyRequest,omitempty"`
+	AttrReqs     []*msp.AttributeRequest `json:"attrReqs,omitempty"`
+}
+
+// KeyRequest is the key pair requested on enrollment
+type KeyRequest struct {
+	Algo string `json:"algo"`
+	Size int    `json:"size"`
+}
+
+// EnrollResponse is the response of the "enroll" and "reenroll" commands
+type EnrollResponse struct {
+	EnrollmentID string `json:"enrollmentID"`
+	// Certificate is the PEM-encoded enrollment certificate
+	Certificate string `json:"certificate"`
+}
+
+// ReenrollRequest is the request of the "reenroll" command
+type ReenrollRequest struct {
+	EnrollmentID string                  `json:"enrollmentID"`
+	NewKey       bool                    `json:"newKey,omitempty"`
+	AttrReqs     []*msp.AttributeRequest `json:"attrReqs,omitempty"`
+}
+
+// RegisterResponse is the response of the "register" command
+type RegisterResponse struct {
+	Secret string `json:"secret"`
+}
+
+// IdentityRequest is the request of the "identity.get" and "identity.list" commands
+type IdentityRequest struct {
+	ID     string `json:"id,omitempty"`
+	CAName string `json:"caname,omitempty"`
+}
+
+// AffiliationRequest is the request of the "affiliation.get" and "affiliation.list" commands
+type AffiliationRequest struct {
+	Name   string `json:"name,omitempty"`
+	CAName string `json:"caname,omitempty"`
+}
+
+// CAInfoRequest is the request of the "cainfo" command
+type CAInfoRequest struct {
+	CAName string `json:"caname,omitempty"`
+}
+
+// CAInfoResponse is the response of the "cainfo" command. The Idemix issuer public key is base64-encoded.
+type CAInfoResponse struct {
+	CAName                    string `json:"caname"`
+	CAChain                   string `json:"caChain"`
+	IssuerPublicKey           []byte `json:"issuerPublicKey,omitempty"`
+	IssuerRevocationPublicKey string `json:"issuerRevocationPublicKey,omitempty"`
+	Version                   string `json:"version"`
+}
+
+// CertificatesResponse is the response of the "certificates.list" command
+type CertificatesResponse struct {
+	CAName string `json:"caname"`
+	// Certs are the PEM-encoded certificates
+	Certs []string `json:"certs"`
+}
+
+// GenCRLResponse is the response of the "gencrl" command
+type GenCRLResponse struct {
+	// CRL is the PEM-encoded CRL
+	CRL string `json:"crl"`
+}
+
+func enroll(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &EnrollRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	if req.EnrollmentID == "" {
+		return nil, errors.New("enrollment ID is required")
+	}
+
+	opts := []msp.EnrollmentOption{msp.WithSecret(req.Secret), msp.WithProfile(req.Profile), msp.WithLabel(req.Label)}
+	if req.CN != "" {
+		opts = append(opts, msp.WithCN(req.CN))
+	}
+	if len(req.Hosts) > 0 {
+		opts = append(opts, msp.WithCSRHosts(req.Hosts...))
+	}
+	if req.KeyRequest != nil {
+		opts = append(opts, msp.WithKeyRequest(req.KeyRequest.Algo, req.KeyRequest.Size))
+	}
+	if len(req.AttrReqs) > 0 {
+		opts = append(opts, msp.WithEnrollmentAttributeRequests(req.AttrReqs...))
+	}
+	if err := c.Enroll(req.EnrollmentID, opts...); err != nil {
+		return nil, err
+	}
+	return enrollResponse(c, req.EnrollmentID)
+}
+
+func reenroll(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &ReenrollRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	if req.EnrollmentID == "" {
+		return nil, errors.New("enrollment ID is required")
+	}
+	var opts []msp.ReenrollmentOption
+	if req.NewKey {
+		opts = append(opts, msp.WithNewKey())
+	}
+	if len(req.AttrReqs) > 0 {
+		opts = append(opts, msp.WithAttributeRequests(req.AttrReqs...))
+	}
+	if err := c.Reenroll(req.EnrollmentID, opts...); err != nil {
+		return nil, err
+	}
+	return enrollResponse(c, req.EnrollmentID)
+}
+
+func enrollResponse(c Client, enrollmentID string) (*EnrollResponse, error) {
+	id, err := c.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get enrolled identity")
+	}
+	return &EnrollResponse{EnrollmentID: enrollmentID, Certificate: string(id.EnrollmentCertificate())}, nil
+}
+
+func register(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.RegistrationRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	secret, err := c.Register(req)
+	if err != nil {
+		return nil, err
+	}
+	return &RegisterResponse{Secret: secret}, nil
+}
+
+func revoke(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.RevocationRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	return c.Revoke(req)
+}
+
+func genCRL(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.GenCRLRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	resp, err := c.GenCRL(req)
+	if err != nil {
+		return nil, err
+	}
+	return &GenCRLResponse{CRL: string(resp.CRL)}, nil
+}
+
+func caInfo(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &CAInfoRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	resp, err := c.GetCAInfo(req.CAName)
+	if err != nil {
+		return nil, err
+	}
+	return &CAInfoResponse{
+		CAName:                    resp.CAName,
+		CAChain:                   string(resp.CAChain),
+		IssuerPublicKey:           resp.IssuerPublicKey,
+		IssuerRevocationPublicKey: string(resp.IssuerRevocationPublicKey),
+		Version:                   resp.Version,
+	}, nil
+}
+
+func listCertificates(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.GetCertificatesRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	resp, err := c.GetCertificates(req)
+	if err != nil {
+		return nil, err
+	}
+	certs := make([]string, len(resp.Certs))
+	for i, cert := range resp.Certs {
+		certs[i] = string(cert)
+	}
+	return &CertificatesResponse{CAName: resp.CAName, Certs: certs}, nil
+}
+
+func getIdentity(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &IdentityRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	if req.ID == "" {
+		return nil, errors.New("id is required")
+	}
+	return c.GetIdentity(req.ID, req.CAName)
+}
+
+func listIdentities(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &IdentityRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	return c.GetAllIdentities(req.CAName)
+}
+
+func modifyIdentity(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.IdentityRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	return c.ModifyIdentity(req)
+}
+
+func removeIdentity(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.RemoveIdentityRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	return c.RemoveIdentity(req)
+}
+
+func getAffiliation(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &AffiliationRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	return c.GetAffiliation(req.Name, req.CAName)
+}
+
+func listAffiliations(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &AffiliationRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	return c.GetAllAffiliations(req.CAName)
+}
+
+func addAffiliation(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.AffiliationRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	return c.AddAffiliation(req)
+}
+
+func modifyAffiliation(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.ModifyAffiliationRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	return c.ModifyAffiliation(req)
+}
+
+func removeAffiliation(c Client, dec *json.Decoder) (interface{}, error) {
+	req := &msp.AffiliationRequest{}
+	if err := decode(dec, req); err != nil {
+		return nil, err
+	}
+	return c.RemoveAffiliation(req)
+}
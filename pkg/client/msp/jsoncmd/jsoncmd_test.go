@@ -0,0 +1,143 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package jsoncmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+)
+
+const certPEM = "-----BEGIN CERTIFICATE-----\ncert\n-----END CERTIFICATE-----\n"
+
+// mockClient implements the commands used by the tests. The other methods panic.
+type mockClient struct {
+	Client
+	enrolled   string
+	enrollOpts int
+	registered *msp.RegistrationRequest
+}
+
+func (c *mockClient) Enroll(enrollmentID string, opts ...msp.EnrollmentOption) error {
+	c.enrolled = enrollmentID
+	c.enrollOpts = len(opts)
+	return nil
+}
+
+func (c *mockClient) GetSigningIdentity(id string) (mspctx.SigningIdentity, error) {
+	if id != c.enrolled {
+		return nil, errors.New("not enrolled")
+	}
+	identity := mspmocks.NewMockSigningIdentity(id, "Org1MSP")
+	identity.SetEnrollmentCertificate([]byte(certPEM))
+	return identity, nil
+}
+
+func (c *mockClient) Register(request *msp.RegistrationRequest) (string, error) {
+	c.registered = request
+	return "secret", nil
+}
+
+func (c *mockClient) GetAllIdentities(caname string) ([]*msp.IdentityResponse, error) {
+	return []*msp.IdentityResponse{{ID: "user1", CAName: caname}}, nil
+}
+
+func (c *mockClient) GetCertificates(request *msp.GetCertificatesRequest) (*msp.GetCertificatesResponse, error) {
+	return &msp.GetCertificatesResponse{CAName: "ca", Certs: [][]byte{[]byte(certPEM)}}, nil
+}
+
+func (c *mockClient) RemoveAffiliation(request *msp.AffiliationRequest) (*msp.AffiliationResponse, error) {
+	return nil, errors.New("affiliation not found")
+}
+
+func TestCommands(t *testing.T) {
+	names := Commands()
+	assert.Len(t, names, len(commands))
+	assert.Contains(t, names, "enroll")
+	assert.Contains(t, names, "affiliation.remove")
+
+	err := New(&mockClient{}).Execute("unknown", strings.NewReader(""), &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestEnroll(t *testing.T) {
+	client := &mockClient{}
+	executor := New(client)
+
+	var out bytes.Buffer
+	err := executor.Execute("enroll", strings.NewReader(`{"enrollmentID": "user1", "secret": "pw", "hosts": ["localhost"]}`), &out)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", client.enrolled)
+	assert.Equal(t, 4, client.enrollOpts, "expecting secret, profile, label and hosts options")
+
+	resp := &EnrollResponse{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), resp))
+	assert.Equal(t, EnrollResponse{EnrollmentID: "user1", Certificate: certPEM}, *resp)
+
+	err = executor.Execute("enroll", strings.NewReader(`{"enrollmentID": "user2", "attrReqs": [{"name": "role", "optional": true}]}`), &out)
+	require.NoError(t, err)
+	assert.Equal(t, 4, client.enrollOpts, "expecting secret, profile, label and attribute request options")
+
+	err = executor.Execute("enroll", strings.NewReader(`{}`), &out)
+	assert.Error(t, err, "expecting error without enrollment ID")
+}
+
+func TestRegister(t *testing.T) {
+	client := &mockClient{}
+	executor := New(client)
+
+	var out bytes.Buffer
+	err := executor.Execute("register", strings.NewReader(`{"name": "user1", "type": "client", "attributes": [{"name": "role", "value": "ops", "ecert": true}]}`), &out)
+	require.NoError(t, err)
+	assert.Equal(t, &msp.RegistrationRequest{
+		Name:       "user1",
+		Type:       "client",
+		Attributes: []msp.Attribute{{Name: "role", Value: "ops", ECert: true}},
+	}, client.registered)
+	assert.JSONEq(t, `{"secret": "secret"}`, out.String())
+
+	err = executor.Execute("register", strings.NewReader(`{"nmae": "user1"}`), &out)
+	assert.Error(t, err, "expecting unknown field to be rejected")
+}
+
+func TestEmptyRequest(t *testing.T) {
+	var out bytes.Buffer
+	err := New(&mockClient{}).Execute("identity.list", strings.NewReader(""), &out)
+	require.NoError(t, err)
+
+	var resp []*msp.IdentityResponse
+	require.NoError(t, json.Unmarshal(out.Bytes(), &resp))
+	require.Len(t, resp, 1)
+	assert.Equal(t, "user1", resp[0].ID)
+}
+
+func TestCertificates(t *testing.T) {
+	var out bytes.Buffer
+	err := New(&mockClient{}).Execute("certificates.list", strings.NewReader(`{"id": "user1", "notRevoked": true}`), &out)
+	require.NoError(t, err)
+
+	resp := &CertificatesResponse{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), resp))
+	assert.Equal(t, []string{certPEM}, resp.Certs, "expecting PEM-encoded certificates")
+}
+
+func TestCommandError(t *testing.T) {
+	var out bytes.Buffer
+	err := New(&mockClient{}).Execute("affiliation.remove", strings.NewReader(`{"name": "org1"}`), &out)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "affiliation.remove failed")
+	assert.Empty(t, out.String())
+}
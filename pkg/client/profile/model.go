@@ -0,0 +1,107 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profile
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Profile is a connection profile in the format read by the SDK's config backend
+type Profile struct {
+	Name                   string                          `yaml:"name"`
+	Description            string                          `yaml:"description,omitempty"`
+	Version                string                          `yaml:"version"`
+	Client                 ClientSection                   `yaml:"client"`
+	Channels               map[string]Channel              `yaml:"channels"`
+	Organizations          map[string]Organization         `yaml:"organizations"`
+	Orderers               map[string]Orderer              `yaml:"orderers"`
+	Peers                  map[string]Peer                 `yaml:"peers"`
+	CertificateAuthorities map[string]CertificateAuthority `yaml:"certificateAuthorities,omitempty"`
+}
+
+// ClientSection contains the client settings of the profile
+type ClientSection struct {
+	// Organization is the name of the organization of the identity that generated the profile
+	Organization string `yaml:"organization"`
+}
+
+// Channel contains the orderers and peers of a channel
+type Channel struct {
+	Orderers []string               `yaml:"orderers"`
+	Peers    map[string]ChannelPeer `yaml:"peers"`
+}
+
+// ChannelPeer contains the roles of a peer in a channel
+type ChannelPeer struct {
+	EndorsingPeer  bool `yaml:"endorsingPeer"`
+	ChaincodeQuery bool `yaml:"chaincodeQuery"`
+	LedgerQuery    bool `yaml:"ledgerQuery"`
+	EventSource    bool `yaml:"eventSource"`
+}
+
+// Organization contains the MSP ID, peers and certificate authorities of an organization
+type Organization struct {
+	MSPID                  string   `yaml:"mspid"`
+	Peers                  []string `yaml:"peers,omitempty"`
+	CertificateAuthorities []string `yaml:"certificateAuthorities,omitempty"`
+}
+
+// Orderer contains the endpoint of an orderer
+type Orderer struct {
+	URL         string                 `yaml:"url"`
+	GRPCOptions map[string]interface{} `yaml:"grpcOptions,omitempty"`
+	TLSCACerts  TLSCACert              `yaml:"tlsCACerts,omitempty"`
+}
+
+// Peer contains the endpoint of a peer
+type Peer struct {
+	URL         string                 `yaml:"url"`
+	GRPCOptions map[string]interface{} `yaml:"grpcOptions,omitempty"`
+	TLSCACerts  TLSCACert              `yaml:"tlsCACerts,omitempty"`
+}
+
+// CertificateAuthority contains the endpoint of a Fabric CA. The registrar credentials
+// are never written to a generated profile.
+type CertificateAuthority struct {
+	URL        string     `yaml:"url"`
+	CAName     string     `yaml:"caName,omitempty"`
+	TLSCACerts TLSCACerts `yaml:"tlsCACerts,omitempty"`
+}
+
+// TLSCACert contains the TLS CA certificate of an orderer or peer
+type TLSCACert struct {
+	Pem  string `yaml:"pem,omitempty"`
+	Path string `yaml:"path,omitempty"`
+}
+
+// TLSCACerts contains the TLS CA certificates of a certificate authority
+type TLSCACerts struct {
+	Pem  []string `yaml:"pem,omitempty"`
+	Path string   `yaml:"path,omitempty"`
+}
+
+// YAML returns the profile in YAML format
+func (p *Profile) YAML() ([]byte, error) {
+	bytes, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling connection profile failed")
+	}
+	return bytes, nil
+}
+
+// Write writes the profile in YAML format to the given writer
+func (p *Profile) Write(w io.Writer) error {
+	bytes, err := p.YAML()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bytes)
+	return errors.Wrap(err, "writing connection profile failed")
+}
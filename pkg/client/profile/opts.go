@@ -0,0 +1,37 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profile
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+)
+
+// Option configures the generator
+type Option func(g *Generator) error
+
+// WithName sets the name of the generated profile
+func WithName(name string) Option {
+	return func(g *Generator) error {
+		g.name = name
+		return nil
+	}
+}
+
+// WithCertificateAuthority adds a certificate authority to the organization with the given MSP ID.
+// Certificate authorities can't be discovered from the peers, so the generated profile only contains
+// the ones added with this option and the ones configured for the organizations of the client's config.
+func WithCertificateAuthority(mspID, name string, config msp.CAConfig) Option {
+	return func(g *Generator) error {
+		if mspID == "" || name == "" {
+			return errors.New("MSP ID and name of certificate authority are required")
+		}
+		g.cas[mspID] = append(g.cas[mspID], namedCA{name: name, config: config})
+		return nil
+	}
+}
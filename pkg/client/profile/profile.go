@@ -0,0 +1,329 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package profile generates a connection profile from a running network.
+//
+// Starting from a single bootstrap peer, the peers, orderers and MSPs of the given channels are
+// retrieved from Fabric's Discovery service and written as a connection profile that may be loaded
+// with config.FromRaw or config.FromFile. This allows the profile to be regenerated whenever peers
+// or orderers are added to the network, instead of being maintained by hand.
+//
+// The identity of the client context should be an admin of its organization, otherwise the peers
+// that haven't joined any of the given channels can't be discovered.
+//
+//  Basic Flow:
+//  1) Prepare client context for an admin identity
+//  2) Create generator
+//  3) Generate the profile from a bootstrap peer and write it
+package profile
+
+import (
+	reqContext "context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	mspcfg "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+const (
+	defaultName = "discovered-network"
+	version     = "1.0.0"
+)
+
+// Generator generates connection profiles from the information returned by Fabric's Discovery service
+type Generator struct {
+	ctx       context.Client
+	discovery *fabdiscovery.Client
+	name      string
+	cas       map[string][]namedCA
+}
+
+type namedCA struct {
+	name   string
+	config msp.CAConfig
+}
+
+// New returns a connection profile generator for the given client context
+func New(clientProvider context.ClientProvider, opts ...Option) (*Generator, error) {
+	ctx, err := clientProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create client context")
+	}
+
+	discovery, err := fabdiscovery.New(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create discovery client")
+	}
+
+	g := &Generator{
+		ctx:       ctx,
+		discovery: discovery,
+		name:      defaultName,
+		cas:       make(map[string][]namedCA),
+	}
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Generate queries the bootstrap peer for the peers, orderers and MSPs of the given channels and returns
+// the corresponding connection profile. The TLS CA certificates of the peers and orderers are taken from
+// the TLS root certificates of their organization's MSP, and the endpoints use the same scheme and
+// gRPC "allow-insecure" option as the bootstrap peer.
+func (g *Generator) Generate(bootstrap fab.PeerConfig, channelIDs ...string) (*Profile, error) {
+	if len(channelIDs) == 0 {
+		return nil, errors.New("at least one channel is required")
+	}
+
+	req := discclient.NewRequest().AddLocalPeersQuery()
+	for _, channelID := range channelIDs {
+		req = req.OfChannel(channelID).AddPeersQuery().AddConfigQuery()
+	}
+
+	reqCtx, cancel := reqContext.WithTimeout(reqContext.Background(), g.ctx.EndpointConfig().Timeout(fab.DiscoveryResponse))
+	defer cancel()
+
+	responses, err := g.discovery.Send(reqCtx, req, bootstrap)
+	if err != nil {
+		return nil, errors.WithMessage(err, "discovery request failed")
+	}
+	response := responses[0]
+
+	b := newBuilder(g.name, bootstrap)
+	for _, channelID := range channelIDs {
+		config, err := response.ForChannel(channelID).Config()
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("config query failed for channel [%s]", channelID))
+		}
+		peers, err := response.ForChannel(channelID).Peers()
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("peers query failed for channel [%s]", channelID))
+		}
+		b.addChannel(channelID, fabdiscovery.NewChannelConfig(config), peers)
+	}
+
+	localPeers, err := response.ForLocal().Peers()
+	if err != nil {
+		logger.Warnf("Peers that haven't joined the channels are not included in the profile since the local peers query failed: %s", err)
+	} else {
+		b.addPeers(localPeers)
+	}
+
+	cas, err := g.certificateAuthorities()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.build(g.ctx.Identifier().MSPID, cas), nil
+}
+
+// certificateAuthorities returns the certificate authorities by MSP ID that were added as options or
+// that are configured for an organization in the client's config
+func (g *Generator) certificateAuthorities() (map[string][]namedCA, error) {
+	cas := make(map[string][]namedCA)
+	for mspID, namedCAs := range g.cas {
+		cas[mspID] = append(cas[mspID], namedCAs...)
+	}
+
+	networkConfig, err := g.ctx.EndpointConfig().NetworkConfig()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get network config")
+	}
+	if networkConfig == nil {
+		return cas, nil
+	}
+
+	for _, org := range networkConfig.Organizations {
+		for _, name := range org.CertificateAuthorities {
+			config, ok := networkConfig.CertificateAuthorities[strings.ToLower(name)]
+			if ok {
+				cas[org.MSPID] = append(cas[org.MSPID], namedCA{name: name, config: config})
+			}
+		}
+	}
+	return cas, nil
+}
+
+// builder accumulates the discovered peers and orderers of a profile
+type builder struct {
+	profile   *Profile
+	bootstrap fab.PeerConfig
+	msps      map[string]*mspcfg.FabricMSPConfig
+	peers     map[string]string
+	orderers  map[string]string
+}
+
+func newBuilder(name string, bootstrap fab.PeerConfig) *builder {
+	return &builder{
+		profile: &Profile{
+			Name:                   name,
+			Description:            fmt.Sprintf("Generated from the network discovered by peer [%s]", bootstrap.URL),
+			Version:                version,
+			Channels:               make(map[string]Channel),
+			Organizations:          make(map[string]Organization),
+			Orderers:               make(map[string]Orderer),
+			Peers:                  make(map[string]Peer),
+			CertificateAuthorities: make(map[string]CertificateAuthority),
+		},
+		bootstrap: bootstrap,
+		msps:      make(map[string]*mspcfg.FabricMSPConfig),
+		peers:     make(map[string]string),
+		orderers:  make(map[string]string),
+	}
+}
+
+func (b *builder) addChannel(channelID string, config *fabdiscovery.ChannelConfig, peers []*discclient.Peer) {
+	for mspID, mspConfig := range config.MSPs {
+		b.msps[mspID] = mspConfig
+	}
+
+	channel := Channel{Peers: make(map[string]ChannelPeer)}
+	for mspID, endpoints := range config.Orderers {
+		for _, e := range endpoints {
+			name := b.addEndpoint(b.orderers, e.URL(), mspID)
+			channel.Orderers = append(channel.Orderers, name)
+		}
+	}
+	sort.Strings(channel.Orderers)
+
+	for _, name := range b.addPeers(peers) {
+		channel.Peers[name] = ChannelPeer{
+			EndorsingPeer:  true,
+			ChaincodeQuery: true,
+			LedgerQuery:    true,
+			EventSource:    true,
+		}
+	}
+	b.profile.Channels[channelID] = channel
+}
+
+func (b *builder) addPeers(peers []*discclient.Peer) []string {
+	var names []string
+	for _, p := range peers {
+		if p.AliveMessage == nil || p.AliveMessage.GetAliveMsg().GetMembership() == nil {
+			logger.Warnf("Ignoring peer of MSP [%s] without membership info", p.MSPID)
+			continue
+		}
+		names = append(names, b.addEndpoint(b.peers, p.AliveMessage.GetAliveMsg().Membership.Endpoint, p.MSPID))
+	}
+	return names
+}
+
+// addEndpoint records the MSP ID of the given endpoint and returns its name in the profile
+func (b *builder) addEndpoint(endpoints map[string]string, url, mspID string) string {
+	endpoints[url] = mspID
+	return host(url)
+}
+
+func (b *builder) build(clientMSPID string, cas map[string][]namedCA) *Profile {
+	p := b.profile
+	orgs := make(map[string]*Organization)
+	organization := func(mspID string) *Organization {
+		org, ok := orgs[mspID]
+		if !ok {
+			org = &Organization{MSPID: mspID}
+			orgs[mspID] = org
+		}
+		return org
+	}
+
+	for url, mspID := range b.orderers {
+		p.Orderers[host(url)] = Orderer{
+			URL:         b.url(url),
+			GRPCOptions: b.grpcOptions(url),
+			TLSCACerts:  b.tlsCACert(mspID),
+		}
+		organization(mspID)
+	}
+
+	for url, mspID := range b.peers {
+		p.Peers[host(url)] = Peer{
+			URL:         b.url(url),
+			GRPCOptions: b.grpcOptions(url),
+			TLSCACerts:  b.tlsCACert(mspID),
+		}
+		org := organization(mspID)
+		org.Peers = append(org.Peers, host(url))
+	}
+
+	for mspID, org := range orgs {
+		// Only the certificate authorities of the discovered organizations are included
+		for _, ca := range cas[mspID] {
+			p.CertificateAuthorities[ca.name] = CertificateAuthority{
+				URL:    ca.config.URL,
+				CAName: ca.config.CAName,
+				TLSCACerts: TLSCACerts{
+					Pem:  ca.config.TLSCACerts.Pem,
+					Path: ca.config.TLSCACerts.Path,
+				},
+			}
+			org.CertificateAuthorities = append(org.CertificateAuthorities, ca.name)
+		}
+		sort.Strings(org.Peers)
+		sort.Strings(org.CertificateAuthorities)
+		p.Organizations[orgName(mspID)] = *org
+	}
+
+	p.Client.Organization = orgName(clientMSPID)
+	return p
+}
+
+// url returns the URL of the given endpoint with the scheme of the bootstrap peer's URL
+func (b *builder) url(address string) string {
+	if i := strings.Index(b.bootstrap.URL, "://"); i >= 0 {
+		return b.bootstrap.URL[:i+3] + address
+	}
+	return address
+}
+
+func (b *builder) grpcOptions(url string) map[string]interface{} {
+	options := map[string]interface{}{
+		"ssl-target-name-override": host(url),
+	}
+	if allowInsecure, ok := b.bootstrap.GRPCOptions["allow-insecure"]; ok {
+		options["allow-insecure"] = allowInsecure
+	}
+	return options
+}
+
+// tlsCACert returns the first TLS root certificate of the given MSP
+func (b *builder) tlsCACert(mspID string) TLSCACert {
+	mspConfig, ok := b.msps[mspID]
+	if !ok || len(mspConfig.TlsRootCerts) == 0 {
+		logger.Warnf("TLS root certificate of MSP [%s] is unknown", mspID)
+		return TLSCACert{}
+	}
+	return TLSCACert{Pem: string(mspConfig.TlsRootCerts[0])}
+}
+
+// host returns the host of the given host:port address, which is used as the name of peers and orderers
+func host(address string) string {
+	h, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return h
+}
+
+// orgName returns the name of the organization with the given MSP ID
+func orgName(mspID string) string {
+	return strings.ToLower(mspID)
+}
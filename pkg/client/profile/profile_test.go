@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package profile
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	mspcfg "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+const (
+	channelID   = "mychannel"
+	org1TLSCert = "-----BEGIN CERTIFICATE-----\norg1\n-----END CERTIFICATE-----\n"
+	ordTLSCert  = "-----BEGIN CERTIFICATE-----\norderer\n-----END CERTIFICATE-----\n"
+)
+
+func TestGenerate(t *testing.T) {
+	bootstrap, stop := startDiscoveryServer(t,
+		discmocks.WithLocalPeers(
+			&discmocks.MockDiscoveryPeerEndpoint{MSPID: "Org1MSP", Endpoint: "peer0.org1.example.com:7051"},
+			&discmocks.MockDiscoveryPeerEndpoint{MSPID: "Org1MSP", Endpoint: "peer1.org1.example.com:7051"},
+		),
+		discmocks.WithPeers(
+			&discmocks.MockDiscoveryPeerEndpoint{MSPID: "Org1MSP", Endpoint: "peer0.org1.example.com:7051"},
+			&discmocks.MockDiscoveryPeerEndpoint{MSPID: "Org2MSP", Endpoint: "peer0.org2.example.com:8051"},
+		),
+		discmocks.WithConfig(&discovery.ConfigResult{
+			Msps: map[string]*mspcfg.FabricMSPConfig{
+				"Org1MSP":    {Name: "Org1MSP", TlsRootCerts: [][]byte{[]byte(org1TLSCert)}},
+				"OrdererMSP": {Name: "OrdererMSP", TlsRootCerts: [][]byte{[]byte(ordTLSCert)}},
+			},
+			Orderers: map[string]*discovery.Endpoints{
+				"OrdererMSP": {Endpoint: []*discovery.Endpoint{{Host: "orderer.example.com", Port: 7050}}},
+			},
+		}),
+	)
+	defer stop()
+
+	generator, err := New(newClientProvider(),
+		WithName("test-network"),
+		WithCertificateAuthority("Org1MSP", "ca.org1.example.com", msp.CAConfig{
+			URL:        "https://ca.org1.example.com:7054",
+			CAName:     "ca.org1",
+			TLSCACerts: endpoint.MutualTLSConfig{Pem: []string{org1TLSCert}},
+			Registrar:  msp.EnrollCredentials{EnrollID: "admin", EnrollSecret: "adminpw"},
+		}),
+		WithCertificateAuthority("Org3MSP", "ca.org3.example.com", msp.CAConfig{URL: "https://ca.org3.example.com:7054"}),
+	)
+	require.NoError(t, err)
+
+	_, err = generator.Generate(bootstrap)
+	assert.Error(t, err, "expecting error when no channel is specified")
+
+	p, err := generator.Generate(bootstrap, channelID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-network", p.Name)
+	assert.Equal(t, "org1msp", p.Client.Organization)
+
+	assert.Equal(t, []string{"orderer.example.com"}, p.Channels[channelID].Orderers)
+	assert.Len(t, p.Channels[channelID].Peers, 2, "expecting only the peers that joined the channel")
+	assert.True(t, p.Channels[channelID].Peers["peer0.org2.example.com"].EndorsingPeer)
+
+	assert.Len(t, p.Peers, 3, "expecting the local peers that haven't joined the channel to be included")
+	peer := p.Peers["peer1.org1.example.com"]
+	assert.Equal(t, "grpc://peer1.org1.example.com:7051", peer.URL, "expecting scheme of bootstrap peer")
+	assert.Equal(t, "peer1.org1.example.com", peer.GRPCOptions["ssl-target-name-override"])
+	assert.Equal(t, true, peer.GRPCOptions["allow-insecure"])
+	assert.Equal(t, org1TLSCert, peer.TLSCACerts.Pem)
+	assert.Empty(t, p.Peers["peer0.org2.example.com"].TLSCACerts.Pem, "expecting no TLS certificate for unknown MSP")
+
+	assert.Equal(t, "grpc://orderer.example.com:7050", p.Orderers["orderer.example.com"].URL)
+	assert.Equal(t, ordTLSCert, p.Orderers["orderer.example.com"].TLSCACerts.Pem)
+
+	assert.Equal(t, Organization{
+		MSPID:                  "Org1MSP",
+		Peers:                  []string{"peer0.org1.example.com", "peer1.org1.example.com"},
+		CertificateAuthorities: []string{"ca.org1.example.com"},
+	}, p.Organizations["org1msp"])
+	assert.Equal(t, "OrdererMSP", p.Organizations["orderermsp"].MSPID)
+	assert.Len(t, p.CertificateAuthorities, 1, "expecting only the CAs of discovered organizations")
+	assert.Equal(t, "ca.org1", p.CertificateAuthorities["ca.org1.example.com"].CAName)
+
+	var buf bytes.Buffer
+	require.NoError(t, p.Write(&buf))
+	assert.NotContains(t, buf.String(), "adminpw", "expecting registrar credentials not to be written")
+
+	backend, err := config.FromRaw(buf.Bytes(), "yaml")()
+	require.NoError(t, err)
+	peers, ok := backend.Lookup("peers")
+	require.True(t, ok, "expecting peers in generated profile")
+	assert.Contains(t, peers, "peer1.org1.example.com")
+}
+
+func TestGenerateWithoutLocalPeers(t *testing.T) {
+	bootstrap, stop := startDiscoveryServer(t,
+		discmocks.WithPeers(&discmocks.MockDiscoveryPeerEndpoint{MSPID: "Org1MSP", Endpoint: "peer0.org1.example.com:7051"}),
+		discmocks.WithConfig(&discovery.ConfigResult{}),
+	)
+	defer stop()
+
+	generator, err := New(newClientProvider())
+	require.NoError(t, err)
+
+	p, err := generator.Generate(bootstrap, channelID)
+	require.NoError(t, err, "expecting the profile to be generated when the local peers query fails")
+	assert.Equal(t, defaultName, p.Name)
+	assert.Len(t, p.Peers, 1)
+	assert.Empty(t, p.Orderers)
+}
+
+func TestGenerateConfigQueryFailed(t *testing.T) {
+	bootstrap, stop := startDiscoveryServer(t,
+		discmocks.WithPeers(&discmocks.MockDiscoveryPeerEndpoint{MSPID: "Org1MSP", Endpoint: "peer0.org1.example.com:7051"}),
+	)
+	defer stop()
+
+	generator, err := New(newClientProvider())
+	require.NoError(t, err)
+
+	_, err = generator.Generate(bootstrap, channelID)
+	assert.Error(t, err, "expecting error when the channel config isn't returned")
+}
+
+func TestNewInvalidCertificateAuthority(t *testing.T) {
+	_, err := New(newClientProvider(), WithCertificateAuthority("", "ca", msp.CAConfig{}))
+	assert.Error(t, err)
+}
+
+// startDiscoveryServer starts a mock discovery server and returns the config of the bootstrap peer
+// along with a function that stops the server
+func startDiscoveryServer(t *testing.T, opts ...discmocks.MockDiscoveryServerOpt) (fab.PeerConfig, func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	grpcServer := grpc.NewServer()
+	discovery.RegisterDiscoveryServer(grpcServer, discmocks.NewServer(opts...))
+	go grpcServer.Serve(lis) //nolint
+
+	bootstrap := fab.PeerConfig{
+		URL: "grpc://" + lis.Addr().String(),
+		GRPCOptions: map[string]interface{}{
+			"allow-insecure": true,
+		},
+	}
+	return bootstrap, grpcServer.Stop
+}
+
+func newClientProvider() context.ClientProvider {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("admin", "Org1MSP"))
+	ctx.SetCustomInfraProvider(comm.NewMockInfraProvider())
+	return func() (context.Client, error) {
+		return ctx, nil
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	commcfg "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/pkg/errors"
 )
@@ -80,6 +81,19 @@ func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOpti
 	}
 }
 
+// WithMaxMessageSize overrides the maximum sizes (in bytes) of the GRPC messages received from and sent
+// to the endpoints for the request, e.g. to install a chaincode package that is larger than the configured
+// limit. A size of zero keeps the limit of the endpoint.
+func WithMaxMessageSize(maxRecv, maxSend int) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if maxRecv < 0 || maxSend < 0 {
+			return errors.New("message size must not be negative")
+		}
+		o.MessageSizes = commcfg.MessageSizes{MaxRecv: maxRecv, MaxSend: maxSend}
+		return nil
+	}
+}
+
 // WithOrdererURL allows an orderer to be specified for the request.
 // The orderer will be looked-up based on the url argument.
 // A default orderer implementation will be used.
@@ -123,3 +137,15 @@ func WithRetry(retryOpt retry.Opts) RequestOption {
 		return nil
 	}
 }
+
+// parentContext returns the parent context of the request, carrying the message size overrides (if any)
+func (o *requestOptions) parentContext() reqContext.Context {
+	if o.MessageSizes == (commcfg.MessageSizes{}) {
+		return o.ParentContext
+	}
+	parent := o.ParentContext
+	if parent == nil {
+		parent = reqContext.Background()
+	}
+	return commcfg.WithMessageSizes(parent, o.MessageSizes)
+}
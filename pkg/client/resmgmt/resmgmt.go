@@ -11,7 +11,6 @@ import (
 	reqContext "context"
 	"io"
 	"io/ioutil"
-	"math/rand"
 	"os"
 	"time"
 
@@ -28,7 +27,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/readonly"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	commcfg "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
@@ -90,6 +91,7 @@ type requestOptions struct {
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for resmgmt operations
 	ParentContext reqContext.Context                //parent grpc context for resmgmt operations
 	Retry         retry.Opts
+	MessageSizes  commcfg.MessageSizes //overrides the maximum GRPC message sizes of the endpoints
 }
 
 //SaveChannelRequest used to save channel request
@@ -177,6 +179,9 @@ func New(ctxProvider context.ClientProvider, opts ...ClientOption) (*Client, err
 
 // JoinChannel allows for peers to join existing channel with optional custom options (specific peers, filtered peers)
 func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error {
+	if err := readonly.Check(rc.ctx, "resmgmt.JoinChannel"); err != nil {
+		return err
+	}
 
 	if channelID == "" {
 		return errors.New("must provide channel ID")
@@ -191,7 +196,7 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 	rc.resolveTimeouts(&opts)
 
 	//set parent request context for overall timeout
-	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
+	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.parentContext()))
 	parentReqCtx = reqContext.WithValue(parentReqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts)
 	defer parentReqCancel()
 
@@ -334,6 +339,10 @@ func (rc *Client) isChaincodeInstalled(reqCtx reqContext.Context, req InstallCCR
 
 // InstallCC installs chaincode with optional custom options (specific peers, filtered peers)
 func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]InstallCCResponse, error) {
+	if err := readonly.Check(rc.ctx, "resmgmt.InstallCC"); err != nil {
+		return nil, err
+	}
+
 	// For each peer query if chaincode installed. If cc is installed treat as success with message 'already installed'.
 	// If cc is not installed try to install, and if that fails add to the list with error and peer name.
 
@@ -351,7 +360,7 @@ func (rc *Client) InstallCC(req InstallCCRequest, options ...RequestOption) ([]I
 	rc.resolveTimeouts(&opts)
 
 	//set parent request context for overall timeout
-	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.ParentContext))
+	parentReqCtx, parentReqCancel := contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[fab.ResMgmt]), contextImpl.WithParent(opts.parentContext()))
 	parentReqCtx = reqContext.WithValue(parentReqCtx, contextImpl.ReqContextTimeoutOverrides, opts.Timeouts)
 	defer parentReqCancel()
 
@@ -447,6 +456,9 @@ func checkRequiredInstallCCParams(req InstallCCRequest) error {
 
 // InstantiateCC instantiates chaincode using default settings
 func (rc *Client) InstantiateCC(channelID string, req InstantiateCCRequest, options ...RequestOption) (InstantiateCCResponse, error) {
+	if err := readonly.Check(rc.ctx, "resmgmt.InstantiateCC"); err != nil {
+		return InstantiateCCResponse{}, err
+	}
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -462,6 +474,9 @@ func (rc *Client) InstantiateCC(channelID string, req InstantiateCCRequest, opti
 
 // UpgradeCC upgrades chaincode  with optional custom options (specific peers, filtered peers, timeout)
 func (rc *Client) UpgradeCC(channelID string, req UpgradeCCRequest, options ...RequestOption) (UpgradeCCResponse, error) {
+	if err := readonly.Check(rc.ctx, "resmgmt.UpgradeCC"); err != nil {
+		return UpgradeCCResponse{}, err
+	}
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -533,7 +548,7 @@ func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...Reque
 		}
 
 		// select random channel peer
-		randomNumber := rand.Intn(len(targets))
+		randomNumber := chCtx.RandomSource().Intn(len(targets))
 		target = targets[randomNumber]
 	}
 
@@ -769,6 +784,9 @@ func peersToTxnProcessors(peers []fab.Peer) []fab.ProposalProcessor {
 
 // SaveChannel creates or updates channel
 func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption) (SaveChannelResponse, error) {
+	if err := readonly.Check(rc.ctx, "resmgmt.SaveChannel"); err != nil {
+		return SaveChannelResponse{}, err
+	}
 
 	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
@@ -943,7 +961,7 @@ func (rc *Client) ordererConfig(channelID string) (*fab.OrdererConfig, error) {
 	}
 
 	// random channel orderer
-	randomNumber := rand.Intn(len(orderers))
+	randomNumber := rc.ctx.RandomSource().Intn(len(orderers))
 	return &orderers[randomNumber], nil
 }
 
@@ -973,7 +991,7 @@ func (rc *Client) createRequestContext(opts requestOptions, defaultTimeoutType f
 		opts.Timeouts[defaultTimeoutType] = rc.ctx.EndpointConfig().Timeout(defaultTimeoutType)
 	}
 
-	return contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[defaultTimeoutType]), contextImpl.WithParent(opts.ParentContext))
+	return contextImpl.NewRequest(rc.ctx, contextImpl.WithTimeout(opts.Timeouts[defaultTimeoutType]), contextImpl.WithParent(opts.parentContext()))
 }
 
 //resolveTimeouts sets default for timeouts from config if not provided through opts
@@ -0,0 +1,152 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit assigns request IDs to client operations and records audit events.
+//
+// A request ID is generated for each client operation unless one is provided by the
+// caller (see WithRequestID). The request ID is included in the SDK's log messages for the
+// operation, is sent to peers and orderers in the GRPC metadata and to the CA in an HTTP
+// header (see RequestIDKey), so that the operation may be correlated across systems.
+//
+// Audit events record who did what against which endpoints. No events are recorded until a
+// sink is set with fabsdk.WithAuditSink or Initialize.
+package audit
+
+import (
+	reqContext "context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDKey is the GRPC metadata key and HTTP header that carries the request ID
+const RequestIDKey = "x-request-id"
+
+type requestIDContextKey struct{}
+
+// NewRequestID generates a random request ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Extremely unlikely. Fall back to a time based ID which is still useful for correlation.
+		return hex.EncodeToString([]byte(time.Now().UTC().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID returns a context that carries the given request ID
+func WithRequestID(ctx reqContext.Context, requestID string) reqContext.Context {
+	return reqContext.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestID returns the request ID carried by the given context or an empty string if there's none
+func RequestID(ctx reqContext.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// EnsureRequestID returns the given context along with its request ID. If the context
+// doesn't carry a request ID then a new one is generated and a derived context is returned.
+func EnsureRequestID(ctx reqContext.Context) (reqContext.Context, string) {
+	if requestID := RequestID(ctx); requestID != "" {
+		return ctx, requestID
+	}
+	requestID := NewRequestID()
+	return WithRequestID(ctx, requestID), requestID
+}
+
+// OutgoingContext returns a context whose outgoing GRPC metadata carries the request ID of the
+// given context (if any), so that the request may be correlated by the receiving peer or orderer
+func OutgoingContext(ctx reqContext.Context) reqContext.Context {
+	requestID := RequestID(ctx)
+	if requestID == "" {
+		return ctx
+	}
+
+	md := metadata.Pairs(RequestIDKey, requestID)
+	if existing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(existing, md)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// Event records an operation performed by a client
+type Event struct {
+	// Time is the time at which the operation completed
+	Time time.Time
+	// RequestID is the ID of the request
+	RequestID string
+	// MSPID is the MSP ID of the identity that performed the operation
+	MSPID string
+	// Identity is the ID of the identity that performed the operation
+	Identity string
+	// Operation is the name of the operation, e.g. channel.Execute or ca.Enroll
+	Operation string
+	// Channel is the ID of the channel (if applicable)
+	Channel string
+	// Endpoints are the URLs of the peers, orderers or CA that were contacted
+	Endpoints []string
+	// Attributes describe the operation, e.g. the chaincode and function that were invoked
+	Attributes map[string]string
+	// Err is the error returned by the operation (if any)
+	Err error
+}
+
+// Sink receives audit events. Record is called synchronously at the end of each operation,
+// so implementations should not block for long.
+type Sink interface {
+	Record(event *Event)
+}
+
+// SinkFunc adapts a function to the Sink interface
+type SinkFunc func(event *Event)
+
+// Record calls f(event)
+func (f SinkFunc) Record(event *Event) {
+	f(event)
+}
+
+var (
+	sinkInstance Sink
+	sinkLock     sync.RWMutex
+)
+
+// Initialize sets the audit sink used by the SDK. Passing nil disables auditing.
+func Initialize(s Sink) {
+	sinkLock.Lock()
+	defer sinkLock.Unlock()
+	sinkInstance = s
+}
+
+func sink() Sink {
+	sinkLock.RLock()
+	defer sinkLock.RUnlock()
+	return sinkInstance
+}
+
+// Enabled returns true if an audit sink has been set
+func Enabled() bool {
+	return sink() != nil
+}
+
+// Record sends the given event to the audit sink. The time of the event is set if it's
+// missing. Nothing is recorded if auditing is disabled.
+func Record(event *Event) {
+	s := sink()
+	if s == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	s.Record(event)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	reqContext "context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestID(t *testing.T) {
+	ctx := reqContext.Background()
+	assert.Empty(t, RequestID(ctx))
+	assert.Equal(t, ctx, OutgoingContext(ctx), "expecting context to be unchanged without a request ID")
+
+	ctx, requestID := EnsureRequestID(ctx)
+	assert.Len(t, requestID, 32)
+	assert.Equal(t, requestID, RequestID(ctx))
+
+	ctx2, requestID2 := EnsureRequestID(ctx)
+	assert.Equal(t, ctx, ctx2, "expecting existing request ID to be kept")
+	assert.Equal(t, requestID, requestID2)
+
+	assert.NotEqual(t, requestID, NewRequestID())
+
+	ctx = WithRequestID(reqContext.Background(), "req1")
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("existing", "value"))
+	md, ok := metadata.FromOutgoingContext(OutgoingContext(ctx))
+	require.True(t, ok)
+	assert.Equal(t, []string{"req1"}, md[RequestIDKey])
+	assert.Equal(t, []string{"value"}, md["existing"])
+}
+
+func TestRecord(t *testing.T) {
+	Initialize(nil)
+	assert.False(t, Enabled())
+	Record(&Event{Operation: "ignored"})
+
+	var events []*Event
+	Initialize(SinkFunc(func(event *Event) {
+		events = append(events, event)
+	}))
+	defer Initialize(nil)
+	assert.True(t, Enabled())
+
+	Record(&Event{
+		RequestID: "req1",
+		MSPID:     "Org1MSP",
+		Identity:  "User1",
+		Operation: "channel.Execute",
+		Channel:   "mychannel",
+		Endpoints: []string{"peer0.org1.example.com:7051"},
+		Err:       errors.New("failed"),
+	})
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "req1", events[0].RequestID)
+	assert.Equal(t, "channel.Execute", events[0].Operation)
+	assert.False(t, events[0].Time.IsZero(), "expecting time to be set")
+	assert.Error(t, events[0].Err)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package clock provides the time source of the SDK. The timeouts, backoffs and timestamps
+// of the components created from an SDK instance are taken from the clock of the SDK, which
+// is the system clock unless another clock is set with fabsdk.WithClock, so that
+// time-dependent behavior can be tested deterministically with a fake clock.
+//
+//  Basic Flow:
+//  1) Create a fake clock
+//  2) Set it as the clock of the SDK
+//  3) Advance the fake clock to trigger the timeouts and backoffs
+//
+//  fake := clock.NewFake(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+//  sdk, err := fabsdk.New(config, fabsdk.WithClock(fake))
+//  ...
+//  fake.Advance(30 * time.Second)
+package clock
+
+import (
+	"time"
+)
+
+// Clock is a source of time
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After returns a channel on which the current time is sent once the given duration has elapsed
+	After(d time.Duration) <-chan time.Time
+	// Sleep pauses the current goroutine for the given duration
+	Sleep(d time.Duration)
+}
+
+// Real is the system clock
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
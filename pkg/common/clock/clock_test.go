@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeAfter(t *testing.T) {
+	fake := NewFake(start)
+
+	early := fake.After(time.Second)
+	late := fake.After(time.Minute)
+	assert.Equal(t, 2, fake.Waiters())
+
+	fake.Advance(30 * time.Second)
+	select {
+	case now := <-early:
+		assert.Equal(t, start.Add(30*time.Second), now)
+	default:
+		t.Fatal("expecting timer to fire")
+	}
+	select {
+	case <-late:
+		t.Fatal("expecting timer not to fire")
+	default:
+	}
+
+	fake.Set(start)
+	assert.Equal(t, start.Add(30*time.Second), fake.Now(), "expecting time not to go backwards")
+
+	fake.Advance(30 * time.Second)
+	<-late
+	assert.Equal(t, 0, fake.Waiters())
+
+	select {
+	case <-fake.After(0):
+	default:
+		t.Fatal("expecting timer with no duration to fire immediately")
+	}
+}
+
+func TestFakeSleep(t *testing.T) {
+	fake := NewFake(start)
+
+	done := make(chan struct{})
+	go func() {
+		fake.Sleep(time.Hour)
+		close(done)
+	}()
+
+	require.True(t, fake.BlockUntil(1, 5*time.Second), "expecting goroutine to sleep")
+	fake.Advance(time.Hour)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expecting goroutine to wake up")
+	}
+
+	assert.False(t, fake.BlockUntil(1, 10*time.Millisecond), "expecting timeout when nobody sleeps")
+}
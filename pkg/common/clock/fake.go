@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a clock whose time only changes when it is advanced. The timers created
+// with After and Sleep fire when the clock is advanced past their deadline.
+//
+// This component has been designed to be safe for concurrency.
+type Fake struct {
+	lock    sync.Mutex
+	now     time.Time
+	waiters []*waiter
+	added   chan struct{}
+}
+
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFake returns a fake clock set to the given time
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, added: make(chan struct{}, 1)}
+}
+
+// Now returns the time of the fake clock
+func (f *Fake) Now() time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.now
+}
+
+// After returns a channel on which the time is sent once the clock has been advanced by the given duration
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- f.now
+		return ch
+	}
+	f.waiters = append(f.waiters, &waiter{deadline: f.now.Add(d), ch: ch})
+
+	select {
+	case f.added <- struct{}{}:
+	default:
+	}
+	return ch
+}
+
+// Sleep blocks until the clock has been advanced by the given duration
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// Advance advances the clock by the given duration and fires the timers whose deadline has been reached
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set sets the time of the clock and fires the timers whose deadline has been reached.
+// The time of the clock never goes backwards.
+func (f *Fake) Set(now time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if now.Before(f.now) {
+		return
+	}
+	f.now = now
+
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].deadline.Before(f.waiters[j].deadline)
+	})
+	var pending []*waiter
+	for _, w := range f.waiters {
+		if w.deadline.After(now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- now
+	}
+	f.waiters = pending
+}
+
+// Waiters returns the number of timers which haven't fired yet
+func (f *Fake) Waiters() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil blocks until at least the given number of timers are waiting for the clock to be advanced,
+// or until the given timeout (on the system clock) has elapsed. It returns false on timeout.
+// This allows tests to advance the clock once the goroutine under test is waiting.
+func (f *Fake) BlockUntil(waiters int, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for f.Waiters() < waiters {
+		select {
+		case <-f.added:
+		case <-deadline:
+			return false
+		}
+	}
+	return true
+}
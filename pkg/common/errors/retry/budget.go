@@ -0,0 +1,127 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
+)
+
+const (
+	// DefaultBudgetRatio is the default ratio of retries to requests allowed by a retry budget
+	DefaultBudgetRatio = 0.2
+	// DefaultBudgetMinPerSecond is the default number of retries per second that a retry budget always allows
+	DefaultBudgetMinPerSecond = 10
+	// DefaultBudgetBurst is the default maximum number of retries that a retry budget accumulates
+	DefaultBudgetBurst = 100
+)
+
+var budgetExhausted = metrics.NewCounter(metrics.CounterOpts{
+	Subsystem: "retry",
+	Name:      "budget_exhausted_total",
+	Help:      "The number of retries that were abandoned because the retry budget was exhausted.",
+})
+
+// BudgetOpts defines the parameters of a retry budget
+type BudgetOpts struct {
+	// Ratio is the number of retries allowed per request, e.g. 0.2 allows 1 retry for every 5 requests
+	Ratio float64
+	// MinPerSecond is the number of retries per second that are allowed regardless of the number of requests
+	MinPerSecond float64
+	// Burst is the maximum number of retries that may be accumulated
+	Burst int
+}
+
+// DefaultBudgetOpts are the default retry budget parameters
+var DefaultBudgetOpts = BudgetOpts{
+	Ratio:        DefaultBudgetRatio,
+	MinPerSecond: DefaultBudgetMinPerSecond,
+	Burst:        DefaultBudgetBurst,
+}
+
+// Budget limits the retries made by all of the retry handlers so that cascading retries can't
+// overwhelm a degraded network. The budget is a token bucket: each request (i.e. each new retry
+// handler) deposits "Ratio" tokens, tokens also accrue at "MinPerSecond" per second up to "Burst"
+// tokens, and each retry withdraws a token. A retry is abandoned if there are no tokens left.
+//
+// This component has been designed to be safe for concurrency.
+type Budget struct {
+	lock   sync.Mutex
+	opts   BudgetOpts
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewBudget returns a full retry budget with the given parameters
+func NewBudget(opts BudgetOpts) *Budget {
+	if opts.Burst < 1 {
+		opts.Burst = DefaultBudgetBurst
+	}
+	return &Budget{
+		opts:   opts,
+		tokens: float64(opts.Burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// Deposit records a request
+func (b *Budget) Deposit() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refill()
+	b.add(b.opts.Ratio)
+}
+
+// Withdraw returns true if a retry is allowed, in which case the retry is charged to the budget
+func (b *Budget) Withdraw() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *Budget) refill() {
+	now := b.now()
+	b.add(now.Sub(b.last).Seconds() * b.opts.MinPerSecond)
+	b.last = now
+}
+
+func (b *Budget) add(tokens float64) {
+	b.tokens += tokens
+	if max := float64(b.opts.Burst); b.tokens > max {
+		b.tokens = max
+	}
+}
+
+var budget = struct {
+	sync.RWMutex
+	b *Budget
+}{}
+
+// SetBudget sets the retry budget shared by all of the retry handlers. Retries are
+// unlimited (apart from the attempts of each handler) if the budget is nil, which is the default.
+func SetBudget(b *Budget) {
+	budget.Lock()
+	defer budget.Unlock()
+	budget.b = b
+}
+
+func currentBudget() *Budget {
+	budget.RLock()
+	defer budget.RUnlock()
+	return budget.b
+}
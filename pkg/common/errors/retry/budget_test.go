@@ -0,0 +1,49 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	now := time.Now()
+	b := NewBudget(BudgetOpts{Ratio: 0.5, MinPerSecond: 1, Burst: 2})
+	b.now = func() time.Time { return now }
+	b.last = now
+
+	assert.True(t, b.Withdraw())
+	assert.True(t, b.Withdraw())
+	assert.False(t, b.Withdraw(), "expecting the budget to be exhausted")
+
+	// Two requests allow a retry
+	b.Deposit()
+	assert.False(t, b.Withdraw())
+	b.Deposit()
+	assert.True(t, b.Withdraw())
+
+	// Retries accrue over time up to the burst
+	now = now.Add(5 * time.Second)
+	assert.True(t, b.Withdraw())
+	assert.True(t, b.Withdraw())
+	assert.False(t, b.Withdraw())
+}
+
+func TestRetryBudget(t *testing.T) {
+	SetBudget(NewBudget(BudgetOpts{Burst: 1}))
+	defer SetBudget(nil)
+
+	transientErr := status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(), "", nil)
+	opts := Opts{Attempts: 3, BackoffFactor: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	assert.True(t, New(opts).Required(transientErr))
+	assert.False(t, New(opts).Required(transientErr), "expecting the retry to be abandoned once the budget is exhausted")
+}
@@ -65,6 +65,15 @@ var DefaultResMgmtOpts = Opts{
 	RetryableCodes: ResMgmtDefaultRetryableCodes,
 }
 
+// DefaultDiscoveryOpts default retry options for Discovery service queries
+var DefaultDiscoveryOpts = Opts{
+	Attempts:       DefaultAttempts,
+	InitialBackoff: DefaultInitialBackoff,
+	MaxBackoff:     DefaultMaxBackoff,
+	BackoffFactor:  DefaultBackoffFactor,
+	RetryableCodes: DiscoveryRetryableCodes,
+}
+
 // DefaultRetryableCodes these are the error codes, grouped by source of error,
 // that are considered to be transient error conditions by default
 var DefaultRetryableCodes = map[status.Group][]status.Code{
@@ -158,3 +167,14 @@ var ChannelClientRetryableCodes = map[status.Group][]status.Code{
 var ChannelConfigRetryableCodes = map[status.Group][]status.Code{
 	status.EndorserClientStatus: {status.EndorsementMismatch},
 }
+
+// DiscoveryRetryableCodes error codes that are considered to be transient when querying the Discovery service
+var DiscoveryRetryableCodes = map[status.Group][]status.Code{
+	status.ClientStatus: {
+		status.ConnectionFailed,
+	},
+	status.GRPCTransportStatus: {
+		status.Code(grpcCodes.Unavailable),
+		status.Code(grpcCodes.DeadlineExceeded),
+	},
+}
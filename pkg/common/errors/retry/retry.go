@@ -10,6 +10,7 @@ package retry
 import (
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 )
 
@@ -29,6 +30,8 @@ type Opts struct {
 	// RetryableCodes defines the status codes, mapped by group, returned by fabric-sdk-go
 	// that warrant a retry. This will default to retry.DefaultRetryableCodes.
 	RetryableCodes map[status.Group][]status.Code
+	// Clock the clock used to wait between retry attempts. This will default to clock.Real.
+	Clock clock.Clock
 }
 
 // Handler retry handler interface decides whether a retry is required for the given
@@ -41,6 +44,7 @@ type Handler interface {
 type impl struct {
 	opts    Opts
 	retries int
+	budget  *Budget
 }
 
 // New retry Handler with the given opts
@@ -48,19 +52,31 @@ func New(opts Opts) Handler {
 	if len(opts.RetryableCodes) == 0 {
 		opts.RetryableCodes = DefaultRetryableCodes
 	}
-	return &impl{opts: opts}
+	return newImpl(opts)
 }
 
 // WithDefaults new retry Handler with default opts
 func WithDefaults() Handler {
-	return &impl{opts: DefaultOpts}
+	return newImpl(DefaultOpts)
 }
 
 // WithAttempts new retry Handler with given attempts. Other opts are set to default.
 func WithAttempts(attempts int) Handler {
 	opts := DefaultOpts
 	opts.Attempts = attempts
-	return &impl{opts: opts}
+	return newImpl(opts)
+}
+
+// newImpl returns a handler that charges its retries to the retry budget (if any)
+func newImpl(opts Opts) *impl {
+	if opts.Clock == nil {
+		opts.Clock = clock.Real
+	}
+	i := &impl{opts: opts, budget: currentBudget()}
+	if i.budget != nil {
+		i.budget.Deposit()
+	}
+	return i
 }
 
 // Required determines if retry is required for the given error
@@ -72,7 +88,12 @@ func (i *impl) Required(err error) bool {
 
 	s, ok := status.FromError(err)
 	if ok && i.isRetryable(s.Group, s.Code) {
-		time.Sleep(i.backoffPeriod())
+		if i.budget != nil && !i.budget.Withdraw() {
+			logger.Warnf("retry budget exhausted - not retrying: %s", err)
+			budgetExhausted.Add(1)
+			return false
+		}
+		i.opts.Clock.Sleep(i.backoffPeriod())
 		i.retries++
 		return true
 	}
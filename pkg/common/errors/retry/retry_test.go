@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
@@ -63,3 +64,38 @@ func TestBackoffPeriod(t *testing.T) {
 	i.retries = 3
 	assert.Equal(t, testMaxBackoff, i.backoffPeriod(), "Expected max backoff")
 }
+
+func TestRetryBackoffWithClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	transientErr := status.New(status.EndorserClientStatus,
+		status.EndorsementMismatch.ToInt32(), "", nil)
+	r := New(Opts{
+		Attempts:       2,
+		BackoffFactor:  2,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Clock:          fake,
+	})
+
+	required := make(chan bool)
+	go func() {
+		required <- r.Required(transientErr)
+	}()
+
+	assert.True(t, fake.BlockUntil(1, 5*time.Second), "Expected retry to back off")
+	fake.Advance(500 * time.Millisecond)
+	select {
+	case <-required:
+		t.Fatal("Expected retry to back off for the initial backoff")
+	case <-time.After(10 * time.Millisecond):
+	}
+	fake.Advance(500 * time.Millisecond)
+	assert.True(t, <-required, "Expected retry to be required on transient error")
+
+	go func() {
+		required <- r.Required(transientErr)
+	}()
+	assert.True(t, fake.BlockUntil(1, 5*time.Second), "Expected retry to back off")
+	fake.Advance(2 * time.Second)
+	assert.True(t, <-required, "Expected retry to be required on transient error")
+}
@@ -63,6 +63,16 @@ const (
 
 	// NoMatchingChannelEntity is if entityMatchers are unable to find any matchingChannel
 	NoMatchingChannelEntity Code = 25
+
+	// AccessDenied is returned when the server denies access to the requested resource
+	AccessDenied Code = 26
+
+	// NoEndorsementLayout is returned when the Discovery service is unable to find a combination
+	// of peers that satisfies the endorsement policy
+	NoEndorsementLayout Code = 27
+
+	// ShuttingDown is returned when a request is made while the SDK is shutting down
+	ShuttingDown Code = 28
 )
 
 // CodeName maps the codes in this packages to human-readable strings
@@ -83,6 +93,9 @@ var CodeName = map[int32]string{
 	23: "NO_MATCHING_ORDERER_ENTITY",
 	24: "PREMATURE_CHAINCODE_EXECUTION",
 	25: "NO_MATCHING_CHANNEL_ENTITY",
+	26: "ACCESS_DENIED",
+	27: "NO_ENDORSEMENT_LAYOUT",
+	28: "SHUTTING_DOWN",
 }
 
 // ToInt32 cast to int32
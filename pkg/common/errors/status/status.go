@@ -75,6 +75,9 @@ const (
 
 	// ChaincodeStatus defines the status codes returned by chaincode
 	ChaincodeStatus
+
+	// DiscoveryServerStatus status returned by the Discovery service
+	DiscoveryServerStatus
 )
 
 // GroupName maps the groups in this packages to human-readable strings
@@ -90,6 +93,7 @@ var GroupName = map[int32]string{
 	8:  "Orderer Client Status",
 	9:  "Client Status",
 	10: "Chaincode status",
+	11: "Discovery Server Status",
 }
 
 func (g Group) String() string {
@@ -131,7 +135,7 @@ func (s *Status) codeString() string {
 		return ToFabricCommonStatusCode(s.Code).String()
 	case EventServerStatus:
 		return ToTransactionValidationCode(s.Code).String()
-	case EndorserClientStatus, OrdererClientStatus, ClientStatus:
+	case EndorserClientStatus, OrdererClientStatus, ClientStatus, DiscoveryServerStatus:
 		return ToSDKStatusCode(s.Code).String()
 	default:
 		return Unknown.String()
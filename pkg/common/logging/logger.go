@@ -18,6 +18,7 @@ import (
 type Logger struct {
 	instance api.Logger // access only via Logger.logger()
 	module   string
+	fields   []api.Field
 	once     sync.Once
 }
 
@@ -52,6 +53,40 @@ func NewLogger(module string) *Logger {
 	return &Logger{module: module}
 }
 
+// Field returns a key/value pair that adds context to log messages (see Logger.With)
+func Field(key string, value interface{}) api.Field {
+	return api.Field{Key: key, Value: value}
+}
+
+// Channel returns a field that holds the given channel ID
+func Channel(channelID string) api.Field {
+	return Field("channel", channelID)
+}
+
+// TxID returns a field that holds the given transaction ID
+func TxID(txID string) api.Field {
+	return Field("txID", txID)
+}
+
+// Peer returns a field that holds the given peer URL
+func Peer(url string) api.Field {
+	return Field("peer", url)
+}
+
+// RequestID returns a field that holds the given request ID
+func RequestID(requestID string) api.Field {
+	return Field("requestID", requestID)
+}
+
+// With returns a logger that adds the given fields to each log message. The fields are passed
+// to the underlying logger if it implements api.FieldLogger (otherwise they're ignored).
+func (l *Logger) With(fields ...api.Field) *Logger {
+	return &Logger{
+		module: l.module,
+		fields: append(append([]api.Field{}, l.fields...), fields...),
+	}
+}
+
 func loggerProvider() api.LoggerProvider {
 	loggerProviderOnce.Do(func() {
 		// A custom logger must be initialized prior to the first log output
@@ -208,6 +243,9 @@ func (l *Logger) Errorln(args ...interface{}) {
 func (l *Logger) logger() api.Logger {
 	l.once.Do(func() {
 		l.instance = loggerProvider().GetLogger(l.module)
+		if fieldLogger, ok := l.instance.(api.FieldLogger); ok && len(l.fields) > 0 {
+			l.instance = fieldLogger.WithFields(l.fields...)
+		}
 	})
 	return l.instance
 }
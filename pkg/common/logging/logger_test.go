@@ -140,6 +140,40 @@ func TestLoggerSetting(t *testing.T) {
 	assert.True(t, loggerProviderInstance != nil, "Logger is supposed to be initialized now")
 }
 
+func TestLoggerWithFields(t *testing.T) {
+	resetLoggerInstance()
+	provider := &fieldLoggerProvider{LoggerProvider: testdata.GetSampleLoggingProvider(&buf)}
+	Initialize(provider)
+
+	logger := NewLogger(moduleName)
+	logger.Info("brown fox jumps over the lazy dog")
+	assert.Empty(t, provider.fields, "fields aren't expected for logger without fields")
+
+	txLogger := logger.With(Channel("mychannel")).With(TxID("abc"), Field("peer", "peer0"))
+	txLogger.Info("brown fox jumps over the lazy dog")
+	assert.Equal(t, []api.Field{{Key: "channel", Value: "mychannel"}, {Key: "txID", Value: "abc"}, {Key: "peer", Value: "peer0"}}, provider.fields)
+	assert.Empty(t, logger.fields, "parent logger isn't expected to be modified")
+}
+
+type fieldLoggerProvider struct {
+	api.LoggerProvider
+	fields []api.Field
+}
+
+func (p *fieldLoggerProvider) GetLogger(module string) api.Logger {
+	return &fieldLogger{Logger: p.LoggerProvider.GetLogger(module), provider: p}
+}
+
+type fieldLogger struct {
+	api.Logger
+	provider *fieldLoggerProvider
+}
+
+func (l *fieldLogger) WithFields(fields ...api.Field) api.Logger {
+	l.provider.fields = fields
+	return l
+}
+
 func resetLoggerInstance() {
 	loggerProviderInstance = nil
 	loggerProviderOnce = sync.Once{}
@@ -0,0 +1,202 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metrics enables the SDK to report metrics such as transaction counts and latencies,
+// connection pool statistics, cache hit rates and event lag. The interfaces follow the metrics
+// API of Fabric. Metrics are discarded until a provider is set with fabsdk.WithMetricsProvider
+// or Initialize (see the prometheus package for a Prometheus provider).
+package metrics
+
+import (
+	"sync"
+)
+
+const namespace = "fabsdk"
+
+// Counter is a metric that only increases
+type Counter interface {
+	// With returns a counter with the given label values, in the order of the label names
+	With(labelValues ...string) Counter
+	// Add increments the counter by the given (non-negative) delta
+	Add(delta float64)
+}
+
+// Gauge is a metric that may increase and decrease
+type Gauge interface {
+	// With returns a gauge with the given label values, in the order of the label names
+	With(labelValues ...string) Gauge
+	// Add increments the gauge by the given (possibly negative) delta
+	Add(delta float64)
+	// Set sets the gauge to the given value
+	Set(value float64)
+}
+
+// Histogram samples observations, such as latencies, in buckets
+type Histogram interface {
+	// With returns a histogram with the given label values, in the order of the label names
+	With(labelValues ...string) Histogram
+	// Observe records the given value
+	Observe(value float64)
+}
+
+// CounterOpts describes a counter
+type CounterOpts struct {
+	Namespace  string
+	Subsystem  string
+	Name       string
+	Help       string
+	LabelNames []string
+}
+
+// GaugeOpts describes a gauge
+type GaugeOpts struct {
+	Namespace  string
+	Subsystem  string
+	Name       string
+	Help       string
+	LabelNames []string
+}
+
+// HistogramOpts describes a histogram
+type HistogramOpts struct {
+	Namespace  string
+	Subsystem  string
+	Name       string
+	Help       string
+	LabelNames []string
+	// Buckets are the upper bounds of the histogram buckets. The default buckets of
+	// the provider are used if not set.
+	Buckets []float64
+}
+
+// Provider creates the metrics reported by the SDK. A provider may be asked to create the
+// same metric more than once (e.g. if several SDK instances are created).
+type Provider interface {
+	NewCounter(opts CounterOpts) Counter
+	NewGauge(opts GaugeOpts) Gauge
+	NewHistogram(opts HistogramOpts) Histogram
+}
+
+var (
+	providerInstance Provider
+	providerLock     sync.RWMutex
+)
+
+// Initialize sets the metrics provider used by the SDK. Passing nil disables metrics.
+func Initialize(p Provider) {
+	providerLock.Lock()
+	defer providerLock.Unlock()
+	providerInstance = p
+}
+
+func provider() Provider {
+	providerLock.RLock()
+	defer providerLock.RUnlock()
+	return providerInstance
+}
+
+// NewCounter returns a counter that is created by the metrics provider when it's first
+// used, so SDK modules may declare their metrics before the provider is set. The namespace
+// defaults to "fabsdk".
+func NewCounter(opts CounterOpts) Counter {
+	if opts.Namespace == "" {
+		opts.Namespace = namespace
+	}
+	return &lazyCounter{base: &lazyMetric{create: func(p Provider) interface{} { return p.NewCounter(opts) }}}
+}
+
+// NewGauge returns a gauge that is created by the metrics provider when it's first used.
+// The namespace defaults to "fabsdk".
+func NewGauge(opts GaugeOpts) Gauge {
+	if opts.Namespace == "" {
+		opts.Namespace = namespace
+	}
+	return &lazyGauge{base: &lazyMetric{create: func(p Provider) interface{} { return p.NewGauge(opts) }}}
+}
+
+// NewHistogram returns a histogram that is created by the metrics provider when it's first
+// used. The namespace defaults to "fabsdk".
+func NewHistogram(opts HistogramOpts) Histogram {
+	if opts.Namespace == "" {
+		opts.Namespace = namespace
+	}
+	return &lazyHistogram{base: &lazyMetric{create: func(p Provider) interface{} { return p.NewHistogram(opts) }}}
+}
+
+// lazyMetric creates the metric with the current provider and recreates it if the provider changes
+type lazyMetric struct {
+	create   func(p Provider) interface{}
+	mutex    sync.Mutex
+	provider Provider
+	metric   interface{}
+}
+
+func (m *lazyMetric) get() interface{} {
+	p := provider()
+	if p == nil {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.provider != p {
+		m.metric = m.create(p)
+		m.provider = p
+	}
+	return m.metric
+}
+
+type lazyCounter struct {
+	base        *lazyMetric
+	labelValues []string
+}
+
+func (c *lazyCounter) With(labelValues ...string) Counter {
+	return &lazyCounter{base: c.base, labelValues: append(append([]string{}, c.labelValues...), labelValues...)}
+}
+
+func (c *lazyCounter) Add(delta float64) {
+	if counter, ok := c.base.get().(Counter); ok {
+		counter.With(c.labelValues...).Add(delta)
+	}
+}
+
+type lazyGauge struct {
+	base        *lazyMetric
+	labelValues []string
+}
+
+func (g *lazyGauge) With(labelValues ...string) Gauge {
+	return &lazyGauge{base: g.base, labelValues: append(append([]string{}, g.labelValues...), labelValues...)}
+}
+
+func (g *lazyGauge) Add(delta float64) {
+	if gauge, ok := g.base.get().(Gauge); ok {
+		gauge.With(g.labelValues...).Add(delta)
+	}
+}
+
+func (g *lazyGauge) Set(value float64) {
+	if gauge, ok := g.base.get().(Gauge); ok {
+		gauge.With(g.labelValues...).Set(value)
+	}
+}
+
+type lazyHistogram struct {
+	base        *lazyMetric
+	labelValues []string
+}
+
+func (h *lazyHistogram) With(labelValues ...string) Histogram {
+	return &lazyHistogram{base: h.base, labelValues: append(append([]string{}, h.labelValues...), labelValues...)}
+}
+
+func (h *lazyHistogram) Observe(value float64) {
+	if histogram, ok := h.base.get().(Histogram); ok {
+		histogram.With(h.labelValues...).Observe(value)
+	}
+}
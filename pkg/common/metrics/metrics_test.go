@@ -0,0 +1,107 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsDisabled(t *testing.T) {
+	Initialize(nil)
+
+	NewCounter(CounterOpts{Name: "counter"}).With("a").Add(1)
+	NewGauge(GaugeOpts{Name: "gauge"}).Set(1)
+	NewHistogram(HistogramOpts{Name: "histogram"}).Observe(1)
+}
+
+func TestLazyMetrics(t *testing.T) {
+	counter := NewCounter(CounterOpts{Name: "counter", LabelNames: []string{"channel", "status"}})
+	gauge := NewGauge(GaugeOpts{Namespace: "custom", Name: "gauge"})
+	histogram := NewHistogram(HistogramOpts{Name: "histogram", LabelNames: []string{"channel"}})
+
+	p := newMockProvider()
+	Initialize(p)
+	defer Initialize(nil)
+
+	counter.With("mychannel").With("success").Add(1)
+	counter.With("mychannel", "success").Add(2)
+	gauge.Set(5)
+	gauge.Add(-2)
+	histogram.With("mychannel").Observe(0.5)
+
+	require.Equal(t, 3, len(p.created), "expecting each metric to be created once")
+	assert.Equal(t, "fabsdk", p.created["counter"])
+	assert.Equal(t, "custom", p.created["gauge"])
+	assert.Equal(t, float64(3), p.values["counter[mychannel success]"])
+	assert.Equal(t, float64(3), p.values["gauge[]"])
+	assert.Equal(t, float64(0.5), p.values["histogram[mychannel]"])
+
+	p2 := newMockProvider()
+	Initialize(p2)
+	counter.With("mychannel", "failure").Add(1)
+	assert.Equal(t, 1, len(p2.created), "expecting metric to be recreated with new provider")
+}
+
+type mockProvider struct {
+	created map[string]string
+	values  map[string]float64
+}
+
+func newMockProvider() *mockProvider {
+	return &mockProvider{created: make(map[string]string), values: make(map[string]float64)}
+}
+
+func (p *mockProvider) NewCounter(opts CounterOpts) Counter {
+	p.created[opts.Name] = opts.Namespace
+	return &mockCounter{mockMetric{provider: p, name: opts.Name}}
+}
+
+func (p *mockProvider) NewGauge(opts GaugeOpts) Gauge {
+	p.created[opts.Name] = opts.Namespace
+	return &mockGauge{mockMetric{provider: p, name: opts.Name}}
+}
+
+func (p *mockProvider) NewHistogram(opts HistogramOpts) Histogram {
+	p.created[opts.Name] = opts.Namespace
+	return &mockHistogram{mockMetric{provider: p, name: opts.Name}}
+}
+
+type mockMetric struct {
+	provider    *mockProvider
+	name        string
+	labelValues []string
+}
+
+func (m mockMetric) with(labelValues []string) mockMetric {
+	return mockMetric{provider: m.provider, name: m.name, labelValues: append(append([]string{}, m.labelValues...), labelValues...)}
+}
+
+func (m mockMetric) key() string {
+	return m.name + "[" + strings.Join(m.labelValues, " ") + "]"
+}
+
+type mockCounter struct{ mockMetric }
+
+func (c *mockCounter) With(labelValues ...string) Counter { return &mockCounter{c.with(labelValues)} }
+func (c *mockCounter) Add(delta float64)                  { c.provider.values[c.key()] += delta }
+
+type mockGauge struct{ mockMetric }
+
+func (g *mockGauge) With(labelValues ...string) Gauge { return &mockGauge{g.with(labelValues)} }
+func (g *mockGauge) Add(delta float64)                { g.provider.values[g.key()] += delta }
+func (g *mockGauge) Set(value float64)                { g.provider.values[g.key()] = value }
+
+type mockHistogram struct{ mockMetric }
+
+func (h *mockHistogram) With(labelValues ...string) Histogram {
+	return &mockHistogram{h.with(labelValues)}
+}
+func (h *mockHistogram) Observe(value float64) { h.provider.values[h.key()] = value }
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package prometheus provides a metrics provider that reports the metrics of the SDK to Prometheus.
+//
+//  Usage:
+//  sdk, err := fabsdk.New(configProvider, fabsdk.WithMetricsProvider(prometheus.NewProvider(nil)))
+//  http.Handle("/metrics", promhttp.Handler())
+package prometheus
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Provider creates Prometheus metrics
+type Provider struct {
+	registerer prom.Registerer
+}
+
+// NewProvider returns a provider that registers the metrics with the given registerer.
+// The default Prometheus registerer is used if the registerer is nil.
+func NewProvider(registerer prom.Registerer) *Provider {
+	if registerer == nil {
+		registerer = prom.DefaultRegisterer
+	}
+	return &Provider{registerer: registerer}
+}
+
+// NewCounter creates a Prometheus counter
+func (p *Provider) NewCounter(opts metrics.CounterOpts) metrics.Counter {
+	vec := prom.NewCounterVec(prom.CounterOpts{
+		Namespace: opts.Namespace,
+		Subsystem: opts.Subsystem,
+		Name:      opts.Name,
+		Help:      opts.Help,
+	}, opts.LabelNames)
+	return &Counter{vec: p.register(vec).(*prom.CounterVec)}
+}
+
+// NewGauge creates a Prometheus gauge
+func (p *Provider) NewGauge(opts metrics.GaugeOpts) metrics.Gauge {
+	vec := prom.NewGaugeVec(prom.GaugeOpts{
+		Namespace: opts.Namespace,
+		Subsystem: opts.Subsystem,
+		Name:      opts.Name,
+		Help:      opts.Help,
+	}, opts.LabelNames)
+	return &Gauge{vec: p.register(vec).(*prom.GaugeVec)}
+}
+
+// NewHistogram creates a Prometheus histogram
+func (p *Provider) NewHistogram(opts metrics.HistogramOpts) metrics.Histogram {
+	vec := prom.NewHistogramVec(prom.HistogramOpts{
+		Namespace: opts.Namespace,
+		Subsystem: opts.Subsystem,
+		Name:      opts.Name,
+		Help:      opts.Help,
+		Buckets:   opts.Buckets,
+	}, opts.LabelNames)
+	return &Histogram{vec: p.register(vec).(*prom.HistogramVec)}
+}
+
+// register registers the given collector. If an identical collector has already been
+// registered (e.g. by another SDK instance) then the existing collector is returned.
+func (p *Provider) register(c prom.Collector) prom.Collector {
+	if err := p.registerer.Register(c); err != nil {
+		if are, ok := err.(prom.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+		panic(err)
+	}
+	return c
+}
+
+// Counter is a Prometheus counter
+type Counter struct {
+	vec         *prom.CounterVec
+	labelValues []string
+}
+
+// With returns a counter with the given label values
+func (c *Counter) With(labelValues ...string) metrics.Counter {
+	return &Counter{vec: c.vec, labelValues: append(append([]string{}, c.labelValues...), labelValues...)}
+}
+
+// Add increments the counter by the given delta
+func (c *Counter) Add(delta float64) {
+	c.vec.WithLabelValues(c.labelValues...).Add(delta)
+}
+
+// Gauge is a Prometheus gauge
+type Gauge struct {
+	vec         *prom.GaugeVec
+	labelValues []string
+}
+
+// With returns a gauge with the given label values
+func (g *Gauge) With(labelValues ...string) metrics.Gauge {
+	return &Gauge{vec: g.vec, labelValues: append(append([]string{}, g.labelValues...), labelValues...)}
+}
+
+// Add increments the gauge by the given delta
+func (g *Gauge) Add(delta float64) {
+	g.vec.WithLabelValues(g.labelValues...).Add(delta)
+}
+
+// Set sets the gauge to the given value
+func (g *Gauge) Set(value float64) {
+	g.vec.WithLabelValues(g.labelValues...).Set(value)
+}
+
+// Histogram is a Prometheus histogram
+type Histogram struct {
+	vec         *prom.HistogramVec
+	labelValues []string
+}
+
+// With returns a histogram with the given label values
+func (h *Histogram) With(labelValues ...string) metrics.Histogram {
+	return &Histogram{vec: h.vec, labelValues: append(append([]string{}, h.labelValues...), labelValues...)}
+}
+
+// Observe records the given value
+func (h *Histogram) Observe(value float64) {
+	h.vec.WithLabelValues(h.labelValues...).Observe(value)
+}
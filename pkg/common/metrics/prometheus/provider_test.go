@@ -0,0 +1,64 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider(t *testing.T) {
+	registry := prom.NewRegistry()
+	p := NewProvider(registry)
+
+	counter := p.NewCounter(metrics.CounterOpts{Namespace: "fabsdk", Subsystem: "channel", Name: "transactions_total", LabelNames: []string{"channel", "status"}})
+	counter.With("mychannel").With("success").Add(2)
+
+	gauge := p.NewGauge(metrics.GaugeOpts{Namespace: "fabsdk", Name: "connections"})
+	gauge.Set(3)
+	gauge.Add(-1)
+
+	histogram := p.NewHistogram(metrics.HistogramOpts{Namespace: "fabsdk", Name: "duration_seconds", Buckets: []float64{1, 2}})
+	histogram.Observe(1.5)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		m := family.GetMetric()[0]
+		switch {
+		case m.Counter != nil:
+			values[family.GetName()] = m.Counter.GetValue()
+		case m.Gauge != nil:
+			values[family.GetName()] = m.Gauge.GetValue()
+		case m.Histogram != nil:
+			values[family.GetName()] = m.Histogram.GetSampleSum()
+		}
+	}
+
+	assert.Equal(t, float64(2), values["fabsdk_channel_transactions_total"])
+	assert.Equal(t, float64(2), values["fabsdk_connections"])
+	assert.Equal(t, float64(1.5), values["fabsdk_duration_seconds"])
+}
+
+func TestProviderRegisterTwice(t *testing.T) {
+	registry := prom.NewRegistry()
+
+	opts := metrics.CounterOpts{Namespace: "fabsdk", Name: "requests_total", LabelNames: []string{"result"}}
+	NewProvider(registry).NewCounter(opts).With("hit").Add(1)
+	NewProvider(registry).NewCounter(opts).With("hit").Add(1)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, float64(2), families[0].GetMetric()[0].Counter.GetValue(), "expecting existing counter to be reused")
+}
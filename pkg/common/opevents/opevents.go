@@ -0,0 +1,160 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package opevents publishes the operational events of the SDK to application subscribers.
+//
+// Operational events report changes that an application may want to monitor or alert on, such as
+// an endpoint being marked unhealthy or a configuration reload, without having to parse the SDK's
+// logs. The events of all the SDK instances in the process are published to the same bus.
+//
+// Each subscriber receives the events on its own buffered channel. Publishing never blocks: an event
+// is dropped for a subscriber whose buffer is full (see Subscription.Dropped).
+package opevents
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Type is the type of an operational event
+type Type string
+
+const (
+	// EndpointUnhealthy is published when the circuit breaker of a peer or orderer opens, i.e. when the
+	// endpoint is marked unhealthy and requests are no longer sent to it. Source is the endpoint address.
+	EndpointUnhealthy Type = "endpoint.unhealthy"
+	// EndpointRecovered is published when the circuit breaker of an endpoint that was marked unhealthy
+	// closes again. Source is the endpoint address.
+	EndpointRecovered Type = "endpoint.recovered"
+	// DiscoveryRefreshed is published when the peers have been refreshed from Fabric's Discovery service.
+	// Source is the channel ID, or empty for the local peers of the client's MSP. The "peers" attribute
+	// is the number of peers that were discovered.
+	DiscoveryRefreshed Type = "discovery.refreshed"
+	// Reenrolled is published when an identity has been re-enrolled with the CA. Source is the
+	// enrollment ID and the "org" attribute is the organization of the CA.
+	Reenrolled Type = "identity.reenrolled"
+	// CertExpiring is published by the certificate renewal service of pkg/msp when the enrollment
+	// certificate of an identity is about to expire or the identity was re-enrolled. Source is the
+	// enrollment ID and the "mspID", "notAfter" and "renewed" attributes describe the certificate.
+	CertExpiring Type = "identity.certexpiring"
+	// ConfigReloaded is published when the configuration of an SDK instance has been reloaded.
+	ConfigReloaded Type = "config.reloaded"
+)
+
+// DefaultBufferSize is the size of a subscription's buffer if none is specified
+const DefaultBufferSize = 100
+
+// Event is an operational event
+type Event struct {
+	// Time is the time at which the event was published
+	Time time.Time
+	// Type is the type of the event
+	Type Type
+	// Source identifies what the event is about, e.g. an endpoint address (see the event types)
+	Source string
+	// Attributes describe the event
+	Attributes map[string]string
+	// Err is the error if the operation that the event reports failed (e.g. a discovery refresh)
+	Err error
+}
+
+// Subscription receives the published events of the types it subscribed to
+type Subscription struct {
+	types     map[Type]bool
+	events    chan *Event
+	dropped   uint64
+	closeOnce sync.Once
+}
+
+var bus struct {
+	sync.RWMutex
+	subscriptions []*Subscription
+}
+
+// Subscribe subscribes to the events of the given types, or to all events if no type is given.
+// The subscription buffers up to bufferSize events (DefaultBufferSize if zero). The subscription
+// must be closed when the events are no longer needed.
+func Subscribe(bufferSize int, types ...Type) *Subscription {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+
+	s := &Subscription{
+		events: make(chan *Event, bufferSize),
+	}
+	if len(types) > 0 {
+		s.types = make(map[Type]bool)
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+
+	bus.Lock()
+	defer bus.Unlock()
+	bus.subscriptions = append(bus.subscriptions, s)
+	return s
+}
+
+// Events returns the channel of the subscription's events. The channel is closed when
+// the subscription is closed.
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Dropped returns the number of events that were dropped since the subscription's buffer was full
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close unsubscribes and closes the events channel
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		bus.Lock()
+		defer bus.Unlock()
+
+		for i, sub := range bus.subscriptions {
+			if sub == s {
+				bus.subscriptions = append(bus.subscriptions[:i:i], bus.subscriptions[i+1:]...)
+				break
+			}
+		}
+		close(s.events)
+	})
+}
+
+// Enabled returns true if there's at least one subscription, which allows publishers to
+// skip building events that nobody receives
+func Enabled() bool {
+	bus.RLock()
+	defer bus.RUnlock()
+	return len(bus.subscriptions) > 0
+}
+
+// Publish publishes the given event to the subscribers of its type. The time of the event
+// is set if it's missing.
+func Publish(event *Event) {
+	bus.RLock()
+	defer bus.RUnlock()
+
+	if len(bus.subscriptions) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	for _, s := range bus.subscriptions {
+		if s.types != nil && !s.types[event.Type] {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package opevents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish(t *testing.T) {
+	assert.False(t, Enabled())
+	Publish(&Event{Type: ConfigReloaded})
+
+	all := Subscribe(0)
+	defer all.Close()
+	health := Subscribe(0, EndpointUnhealthy, EndpointRecovered)
+	defer health.Close()
+	assert.True(t, Enabled())
+
+	Publish(&Event{Type: EndpointUnhealthy, Source: "peer0.org1.example.com:7051"})
+	Publish(&Event{Type: ConfigReloaded})
+
+	event := <-health.Events()
+	assert.Equal(t, EndpointUnhealthy, event.Type)
+	assert.Equal(t, "peer0.org1.example.com:7051", event.Source)
+	assert.False(t, event.Time.IsZero(), "expecting time to be set")
+	assert.Empty(t, health.Events(), "expecting events of other types to be filtered")
+
+	require.Len(t, all.Events(), 2)
+	assert.Equal(t, EndpointUnhealthy, (<-all.Events()).Type)
+	assert.Equal(t, ConfigReloaded, (<-all.Events()).Type)
+}
+
+func TestDropped(t *testing.T) {
+	s := Subscribe(1)
+	defer s.Close()
+
+	Publish(&Event{Type: ConfigReloaded})
+	Publish(&Event{Type: ConfigReloaded})
+	assert.Len(t, s.Events(), 1)
+	assert.Equal(t, uint64(1), s.Dropped(), "expecting event to be dropped when the buffer is full")
+}
+
+func TestClose(t *testing.T) {
+	s := Subscribe(0)
+	s.Close()
+	s.Close()
+
+	_, ok := <-s.Events()
+	assert.False(t, ok, "expecting events channel to be closed")
+	assert.False(t, Enabled())
+
+	Publish(&Event{Type: ConfigReloaded})
+}
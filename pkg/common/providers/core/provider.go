@@ -6,6 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 
 package core
 
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
+)
+
 //CryptoSuiteConfig contains sdk configuration items for cryptosuite.
 type CryptoSuiteConfig interface {
 	IsSecurityEnabled() bool
@@ -23,6 +28,12 @@ type CryptoSuiteConfig interface {
 type Providers interface {
 	CryptoSuite() CryptoSuite
 	SigningManager() SigningManager
+	// ReadOnly returns true if the SDK is in read-only mode (see package readonly)
+	ReadOnly() bool
+	// Clock returns the clock of the SDK (see package clock)
+	Clock() clock.Clock
+	// RandomSource returns the source of the random bytes and pseudo-random numbers of the SDK (see package random)
+	RandomSource() *random.Source
 }
 
 //ConfigProvider provides config backend for SDK
@@ -7,11 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package fab
 
 import (
+	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
-// BlockEvent contains the data for the block event
+// BlockEvent contains the data for the block event. The same event is delivered to all of the
+// registrations of a channel and is read-only: neither the event nor the block may be modified.
+// Use Copy to get an event that may be modified.
 type BlockEvent struct {
 	// Block is the block that was committed
 	Block *cb.Block
@@ -19,7 +22,9 @@ type BlockEvent struct {
 	SourceURL string
 }
 
-// FilteredBlockEvent contains the data for a filtered block event
+// FilteredBlockEvent contains the data for a filtered block event. The same event is delivered to
+// all of the registrations of a channel and is read-only: neither the event nor the filtered block
+// may be modified. Use Copy to get an event that may be modified.
 type FilteredBlockEvent struct {
 	// FilteredBlock contains a filtered version of the block that was committed
 	FilteredBlock *pb.FilteredBlock
@@ -40,7 +45,9 @@ type TxStatusEvent struct {
 	SourceURL string
 }
 
-// CCEvent contains the data for a chaincode event
+// CCEvent contains the data for a chaincode event. The same event is delivered to all of the
+// matching registrations and is read-only: neither the event nor the payload may be modified.
+// Use Copy to get an event that may be modified.
 type CCEvent struct {
 	// TxID is the ID of the transaction in which the event was set
 	TxID string
@@ -58,6 +65,33 @@ type CCEvent struct {
 	SourceURL string
 }
 
+// Copy returns a deep copy of the event which may be modified
+func (e *BlockEvent) Copy() *BlockEvent {
+	c := *e
+	if e.Block != nil {
+		c.Block = proto.Clone(e.Block).(*cb.Block)
+	}
+	return &c
+}
+
+// Copy returns a deep copy of the event which may be modified
+func (e *FilteredBlockEvent) Copy() *FilteredBlockEvent {
+	c := *e
+	if e.FilteredBlock != nil {
+		c.FilteredBlock = proto.Clone(e.FilteredBlock).(*pb.FilteredBlock)
+	}
+	return &c
+}
+
+// Copy returns a copy of the event whose payload may be modified
+func (e *CCEvent) Copy() *CCEvent {
+	c := *e
+	if e.Payload != nil {
+		c.Payload = append([]byte(nil), e.Payload...)
+	}
+	return &c
+}
+
 // Registration is a handle that is returned from a successful RegisterXXXEvent.
 // This handle should be used in Unregister in order to unregister the event.
 type Registration interface{}
@@ -92,6 +92,7 @@ type PeerConfig struct {
 	EventURL    string
 	GRPCOptions map[string]interface{}
 	TLSCACerts  endpoint.TLSConfig
+	Labels      map[string]string
 }
 
 // MatchConfig contains match pattern and substitution pattern
@@ -18,3 +18,10 @@ type Peer interface {
 
 	// TODO: Roles, Name, EnrollmentCertificate (if needed)
 }
+
+// PeerState provides state information about the peer. Peers returned
+// by dynamic discovery implement this interface.
+type PeerState interface {
+	// BlockHeight returns the block height of the peer's ledger at the time it was discovered
+	BlockHeight() uint64
+}
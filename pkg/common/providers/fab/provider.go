@@ -38,6 +38,7 @@ type InfraProvider interface {
 	CreatePeerFromConfig(peerCfg *NetworkPeer) (Peer, error)
 	CreateOrdererFromConfig(cfg *OrdererConfig) (Orderer, error)
 	CommManager() CommManager
+	EndpointHealth() EndpointHealth
 	Close()
 }
 
@@ -65,6 +66,18 @@ type DiscoveryService interface {
 	GetPeers() ([]Peer, error)
 }
 
+// ContextDiscoveryService is implemented by discovery services that honour the deadline
+// and cancellation of the request context while retrieving peers
+type ContextDiscoveryService interface {
+	GetPeersContext(ctx reqContext.Context) ([]Peer, error)
+}
+
+// OrdererDiscoveryService is implemented by discovery services that are also able
+// to discover the orderers of a channel
+type OrdererDiscoveryService interface {
+	GetOrderers() ([]OrdererConfig, error)
+}
+
 // LocalDiscoveryProvider is used to discover peers in the local MSP
 type LocalDiscoveryProvider interface {
 	CreateLocalDiscoveryService() (DiscoveryService, error)
@@ -82,6 +95,23 @@ type CommManager interface {
 	ReleaseConn(conn *grpc.ClientConn)
 }
 
+// RequestTracker tracks the requests (e.g. endorsements and broadcasts) that are in flight so
+// that they can complete before the SDK is closed
+type RequestTracker interface {
+	// Acquire registers a new in-flight request. False is returned if the SDK is shutting down,
+	// in which case the request must not be made.
+	Acquire() bool
+	// Release unregisters an in-flight request
+	Release()
+}
+
+// EndpointHealth reports on the health of network endpoints as determined by
+// background health checks and the outcome of recent connection attempts.
+type EndpointHealth interface {
+	// Healthy returns false if the endpoint at the given URL is known to be down
+	Healthy(url string) bool
+}
+
 //EndpointConfig contains endpoint network configurations
 type EndpointConfig interface {
 	Timeout(TimeoutType) time.Duration
@@ -142,6 +172,12 @@ const (
 	DiscoveryResponse
 	// DiscoveryServiceRefresh discovery service refresh interval
 	DiscoveryServiceRefresh
+	// HealthCheckInterval is the interval between background health probes of endpoints
+	HealthCheckInterval
+	// HealthCheckTimeout is the timeout for a single health probe
+	HealthCheckTimeout
+	// CircuitBreakerReset is the time that an endpoint's circuit breaker stays open before a trial request is allowed
+	CircuitBreakerReset
 )
 
 // EventServiceType specifies the type of event service to use
@@ -154,6 +190,72 @@ const (
 	EventHubEventServiceType
 )
 
+// ConnectionPoolConfig contains the limits of the GRPC connection pool
+type ConnectionPoolConfig struct {
+	// MaxConnectionsPerEndpoint is the maximum number of connections opened to a single endpoint
+	MaxConnectionsPerEndpoint int
+	// MaxStreamsPerConnection is the maximum number of concurrent users of a connection (0 for no limit)
+	MaxStreamsPerConnection int
+}
+
+// EndpointType is the type of endpoint that the SDK connects to
+type EndpointType int
+
+const (
+	// PeerEndpoint is the endorser service of a peer
+	PeerEndpoint EndpointType = iota
+	// OrdererEndpoint is the broadcast service of an orderer
+	OrdererEndpoint
+	// EventEndpoint is the event service (deliver service or event hub) of a peer
+	EventEndpoint
+)
+
+// String returns the name of the endpoint type as used in the configuration
+func (t EndpointType) String() string {
+	switch t {
+	case PeerEndpoint:
+		return "peer"
+	case OrdererEndpoint:
+		return "orderer"
+	case EventEndpoint:
+		return "event"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionConfig contains the GRPC keepalive and reconnect backoff parameters of the
+// connections to a type of endpoint. Zero values leave the GRPC defaults in place.
+type ConnectionConfig struct {
+	// KeepAliveTime is the time after which the client pings the server if there's no activity
+	KeepAliveTime time.Duration
+	// KeepAliveTimeout is the time the client waits for a ping to be acknowledged
+	KeepAliveTimeout time.Duration
+	// KeepAlivePermit allows pings to be sent when there are no active calls
+	KeepAlivePermit bool
+	// BackoffMaxDelay is the maximum delay between reconnection attempts
+	BackoffMaxDelay time.Duration
+}
+
+// EventReconnectConfig contains the parameters of the reconnection of the event service
+// streams after the connection was lost. Zero values leave the event client defaults in place.
+type EventReconnectConfig struct {
+	// InitialDelay is the time waited before the first reconnection attempt
+	InitialDelay time.Duration
+	// TimeBetweenAttempts is the delay after the first failed reconnection attempt
+	TimeBetweenAttempts time.Duration
+	// BackoffFactor multiplies the delay after every failed attempt
+	BackoffFactor float64
+	// MaxDelay is the maximum delay between reconnection attempts
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay that is randomly added or subtracted
+	Jitter float64
+	// MaxAttempts is the maximum number of reconnection attempts
+	MaxAttempts uint
+	// MaxElapsedTime is the time after which the client stops reconnecting
+	MaxElapsedTime time.Duration
+}
+
 // Providers represents the SDK configured service providers context.
 type Providers interface {
 	DiscoveryProvider() DiscoveryProvider
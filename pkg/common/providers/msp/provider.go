@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	logApi "github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
@@ -50,12 +53,52 @@ type CCType struct {
 
 // CredentialStoreType defines pluggable KV store properties
 type CredentialStoreType struct {
+	// Type is the name of the user store implementation (see msp.RegisterUserStore in pkg/msp).
+	// The file based user store is used if empty.
+	Type string
+	// Format is the format of the stored user data (see msp.UserDataCodecForFormat in pkg/msp).
+	// The PEM encoded enrollment certificate is stored if empty.
+	Format      string
 	Path        string
+	CouchDB     CouchDBConfig
+	Redis       RedisConfig
+	Cache       UserCacheConfig
+	Encryption  UserStoreEncryptionConfig
 	CryptoStore struct {
 		Path string
 	}
 }
 
+// CouchDBConfig defines the CouchDB database used by the CouchDB user store
+type CouchDBConfig struct {
+	URL      string
+	Database string
+	Username string
+	Password string
+}
+
+// RedisConfig defines the Redis database used by the Redis user store
+type RedisConfig struct {
+	Address   string
+	Password  string
+	Database  int
+	KeyPrefix string
+}
+
+// UserCacheConfig defines the in-memory cache of the user store
+type UserCacheConfig struct {
+	Enabled bool
+	TTL     time.Duration
+}
+
+// UserStoreEncryptionConfig defines the key that encrypts the stored user data. The key is either
+// derived from a passphrase or is the (exportable) symmetric key of the crypto suite with the given
+// SKI (hex encoded).
+type UserStoreEncryptionConfig struct {
+	Passphrase string
+	KeySKI     string
+}
+
 // EnrollCredentials holds credentials used for enrollment
 type EnrollCredentials struct {
 	EnrollID     string
@@ -75,4 +118,9 @@ type Providers interface {
 	UserStore() UserStore
 	IdentityManagerProvider
 	IdentityConfig() IdentityConfig
+	// CATransport returns the function that wraps the HTTP transport of the CA clients, or nil
+	CATransport() CATransport
 }
+
+// CATransport wraps the HTTP transport used to send the requests to the CAs (e.g. to record or replay them)
+type CATransport func(http.RoundTripper) http.RoundTripper
@@ -14,7 +14,9 @@ type UserData struct {
 	EnrollmentCertificate []byte
 }
 
-// UserStore is responsible for UserData persistence
+// UserStore is responsible for UserData persistence.
+// Implementations other than the SDK's file, CouchDB and Redis based stores are plugged in with
+// RegisterUserStore in pkg/msp and selected with client.credentialStore.type.
 type UserStore interface {
 	Store(*UserData) error
 	Load(IdentityIdentifier) (*UserData, error)
@@ -8,9 +8,11 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	clock "github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	core "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	fab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	msp "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	random "github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 )
 
 // MockProviders is a mock of Providers interface
@@ -36,6 +38,18 @@ func (m *MockProviders) EXPECT() *MockProvidersMockRecorder {
 	return m.recorder
 }
 
+// CATransport mocks base method
+func (m *MockProviders) CATransport() msp.CATransport {
+	ret := m.ctrl.Call(m, "CATransport")
+	ret0, _ := ret[0].(msp.CATransport)
+	return ret0
+}
+
+// CATransport indicates an expected call of CATransport
+func (mr *MockProvidersMockRecorder) CATransport() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CATransport", reflect.TypeOf((*MockProviders)(nil).CATransport))
+}
+
 // ChannelProvider mocks base method
 func (m *MockProviders) ChannelProvider() fab.ChannelProvider {
 	ret := m.ctrl.Call(m, "ChannelProvider")
@@ -48,6 +62,18 @@ func (mr *MockProvidersMockRecorder) ChannelProvider() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChannelProvider", reflect.TypeOf((*MockProviders)(nil).ChannelProvider))
 }
 
+// Clock mocks base method
+func (m *MockProviders) Clock() clock.Clock {
+	ret := m.ctrl.Call(m, "Clock")
+	ret0, _ := ret[0].(clock.Clock)
+	return ret0
+}
+
+// Clock indicates an expected call of Clock
+func (mr *MockProvidersMockRecorder) Clock() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clock", reflect.TypeOf((*MockProviders)(nil).Clock))
+}
+
 // CryptoSuite mocks base method
 func (m *MockProviders) CryptoSuite() core.CryptoSuite {
 	ret := m.ctrl.Call(m, "CryptoSuite")
@@ -133,6 +159,30 @@ func (mr *MockProvidersMockRecorder) LocalDiscoveryProvider() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LocalDiscoveryProvider", reflect.TypeOf((*MockProviders)(nil).LocalDiscoveryProvider))
 }
 
+// RandomSource mocks base method
+func (m *MockProviders) RandomSource() *random.Source {
+	ret := m.ctrl.Call(m, "RandomSource")
+	ret0, _ := ret[0].(*random.Source)
+	return ret0
+}
+
+// RandomSource indicates an expected call of RandomSource
+func (mr *MockProvidersMockRecorder) RandomSource() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RandomSource", reflect.TypeOf((*MockProviders)(nil).RandomSource))
+}
+
+// ReadOnly mocks base method
+func (m *MockProviders) ReadOnly() bool {
+	ret := m.ctrl.Call(m, "ReadOnly")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ReadOnly indicates an expected call of ReadOnly
+func (mr *MockProvidersMockRecorder) ReadOnly() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOnly", reflect.TypeOf((*MockProviders)(nil).ReadOnly))
+}
+
 // SelectionProvider mocks base method
 func (m *MockProviders) SelectionProvider() fab.SelectionProvider {
 	ret := m.ctrl.Call(m, "SelectionProvider")
@@ -192,6 +242,18 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CATransport mocks base method
+func (m *MockClient) CATransport() msp.CATransport {
+	ret := m.ctrl.Call(m, "CATransport")
+	ret0, _ := ret[0].(msp.CATransport)
+	return ret0
+}
+
+// CATransport indicates an expected call of CATransport
+func (mr *MockClientMockRecorder) CATransport() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CATransport", reflect.TypeOf((*MockClient)(nil).CATransport))
+}
+
 // ChannelProvider mocks base method
 func (m *MockClient) ChannelProvider() fab.ChannelProvider {
 	ret := m.ctrl.Call(m, "ChannelProvider")
@@ -204,6 +266,18 @@ func (mr *MockClientMockRecorder) ChannelProvider() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChannelProvider", reflect.TypeOf((*MockClient)(nil).ChannelProvider))
 }
 
+// Clock mocks base method
+func (m *MockClient) Clock() clock.Clock {
+	ret := m.ctrl.Call(m, "Clock")
+	ret0, _ := ret[0].(clock.Clock)
+	return ret0
+}
+
+// Clock indicates an expected call of Clock
+func (mr *MockClientMockRecorder) Clock() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clock", reflect.TypeOf((*MockClient)(nil).Clock))
+}
+
 // CryptoSuite mocks base method
 func (m *MockClient) CryptoSuite() core.CryptoSuite {
 	ret := m.ctrl.Call(m, "CryptoSuite")
@@ -337,6 +411,30 @@ func (mr *MockClientMockRecorder) PublicVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicVersion", reflect.TypeOf((*MockClient)(nil).PublicVersion))
 }
 
+// RandomSource mocks base method
+func (m *MockClient) RandomSource() *random.Source {
+	ret := m.ctrl.Call(m, "RandomSource")
+	ret0, _ := ret[0].(*random.Source)
+	return ret0
+}
+
+// RandomSource indicates an expected call of RandomSource
+func (mr *MockClientMockRecorder) RandomSource() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RandomSource", reflect.TypeOf((*MockClient)(nil).RandomSource))
+}
+
+// ReadOnly mocks base method
+func (m *MockClient) ReadOnly() bool {
+	ret := m.ctrl.Call(m, "ReadOnly")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ReadOnly indicates an expected call of ReadOnly
+func (mr *MockClientMockRecorder) ReadOnly() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOnly", reflect.TypeOf((*MockClient)(nil).ReadOnly))
+}
+
 // SelectionProvider mocks base method
 func (m *MockClient) SelectionProvider() fab.SelectionProvider {
 	ret := m.ctrl.Call(m, "SelectionProvider")
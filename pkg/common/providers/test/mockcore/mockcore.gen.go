@@ -8,7 +8,9 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	clock "github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	core "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	random "github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 )
 
 // MockCryptoSuiteConfig is a mock of CryptoSuiteConfig interface
@@ -201,6 +203,18 @@ func (m *MockProviders) EXPECT() *MockProvidersMockRecorder {
 	return m.recorder
 }
 
+// Clock mocks base method
+func (m *MockProviders) Clock() clock.Clock {
+	ret := m.ctrl.Call(m, "Clock")
+	ret0, _ := ret[0].(clock.Clock)
+	return ret0
+}
+
+// Clock indicates an expected call of Clock
+func (mr *MockProvidersMockRecorder) Clock() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clock", reflect.TypeOf((*MockProviders)(nil).Clock))
+}
+
 // CryptoSuite mocks base method
 func (m *MockProviders) CryptoSuite() core.CryptoSuite {
 	ret := m.ctrl.Call(m, "CryptoSuite")
@@ -213,6 +227,30 @@ func (mr *MockProvidersMockRecorder) CryptoSuite() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CryptoSuite", reflect.TypeOf((*MockProviders)(nil).CryptoSuite))
 }
 
+// RandomSource mocks base method
+func (m *MockProviders) RandomSource() *random.Source {
+	ret := m.ctrl.Call(m, "RandomSource")
+	ret0, _ := ret[0].(*random.Source)
+	return ret0
+}
+
+// RandomSource indicates an expected call of RandomSource
+func (mr *MockProvidersMockRecorder) RandomSource() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RandomSource", reflect.TypeOf((*MockProviders)(nil).RandomSource))
+}
+
+// ReadOnly mocks base method
+func (m *MockProviders) ReadOnly() bool {
+	ret := m.ctrl.Call(m, "ReadOnly")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ReadOnly indicates an expected call of ReadOnly
+func (mr *MockProvidersMockRecorder) ReadOnly() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadOnly", reflect.TypeOf((*MockProviders)(nil).ReadOnly))
+}
+
 // SigningManager mocks base method
 func (m *MockProviders) SigningManager() core.SigningManager {
 	ret := m.ctrl.Call(m, "SigningManager")
@@ -182,6 +182,18 @@ func (m *MockProviders) EXPECT() *MockProvidersMockRecorder {
 	return m.recorder
 }
 
+// CATransport mocks base method
+func (m *MockProviders) CATransport() msp.CATransport {
+	ret := m.ctrl.Call(m, "CATransport")
+	ret0, _ := ret[0].(msp.CATransport)
+	return ret0
+}
+
+// CATransport indicates an expected call of CATransport
+func (mr *MockProvidersMockRecorder) CATransport() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CATransport", reflect.TypeOf((*MockProviders)(nil).CATransport))
+}
+
 // IdentityConfig mocks base method
 func (m *MockProviders) IdentityConfig() msp.IdentityConfig {
 	ret := m.ctrl.Call(m, "IdentityConfig")
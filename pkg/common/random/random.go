@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package random provides the randomness used by the SDK: the random bytes from which the
+// transaction nonces are generated and the pseudo-random numbers used for backoff jitter,
+// load balancing and shuffling. Each SDK instance has its own Source (see
+// fabsdk.WithEntropySource), so that the random choices of an SDK can be reproduced in
+// tests without affecting the other SDK instances of the process.
+package random
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	mathrand "math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// batchSize is the minimum number of bytes that are read from the entropy source at once
+const batchSize = 4096
+
+// Source is a source of random bytes and pseudo-random numbers.
+//
+// This component has been designed to be safe for concurrency.
+type Source struct {
+	entropyLock sync.Mutex
+	entropy     io.Reader
+	buf         []byte
+
+	randLock sync.Mutex
+	rand     *mathrand.Rand
+}
+
+var defaultSource = &Source{entropy: rand.Reader, rand: newTimeSeeded()}
+
+// Default returns the source that reads crypto/rand and whose pseudo-random numbers are
+// seeded with the time at which the process started
+func Default() *Source {
+	return defaultSource
+}
+
+// NewSource returns a source that reads the random bytes from the given entropy source and
+// seeds the pseudo-random numbers with the first 8 bytes read from it. The entropy source
+// is only read by one goroutine at a time.
+func NewSource(entropy io.Reader) (*Source, error) {
+	if entropy == nil {
+		return nil, errors.New("entropy source is nil")
+	}
+
+	var seed int64
+	if err := binary.Read(entropy, binary.BigEndian, &seed); err != nil {
+		return nil, errors.Wrap(err, "reading seed from entropy source failed")
+	}
+
+	return &Source{entropy: entropy, rand: mathrand.New(mathrand.NewSource(seed))}, nil //nolint
+}
+
+// Read fills p with random bytes. The bytes are read from the entropy source in batches so
+// that concurrent readers (e.g. transactions) don't each read from the entropy source.
+func (s *Source) Read(p []byte) (int, error) {
+	s.entropyLock.Lock()
+	defer s.entropyLock.Unlock()
+
+	if len(s.buf) < len(p) {
+		size := batchSize
+		if size < len(p) {
+			size = len(p)
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(s.entropy, buf); err != nil {
+			return 0, errors.Wrap(err, "reading from entropy source failed")
+		}
+		// The remaining bytes of the previous batch are discarded
+		s.buf = buf
+	}
+
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+// Intn returns a pseudo-random number in [0,n). It panics if n <= 0.
+func (s *Source) Intn(n int) int {
+	s.randLock.Lock()
+	defer s.randLock.Unlock()
+
+	return s.rand.Intn(n)
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0)
+func (s *Source) Float64() float64 {
+	s.randLock.Lock()
+	defer s.randLock.Unlock()
+
+	return s.rand.Float64()
+}
+
+// Intn returns a pseudo-random number in [0,n) from the default source. It panics if n <= 0.
+func Intn(n int) int {
+	return defaultSource.Intn(n)
+}
+
+// Float64 returns a pseudo-random number in [0.0,1.0) from the default source
+func Float64() float64 {
+	return defaultSource.Float64()
+}
+
+func newTimeSeeded() *mathrand.Rand {
+	return mathrand.New(mathrand.NewSource(time.Now().UnixNano())) //nolint
+}
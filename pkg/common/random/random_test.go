@@ -0,0 +1,49 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package random
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSource(t *testing.T) {
+	entropy := bytes.Repeat([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 1024)
+
+	s1, err := NewSource(bytes.NewReader(entropy))
+	require.NoError(t, err)
+	first := []interface{}{s1.Intn(1000), s1.Intn(1000), s1.Float64()}
+
+	s2, err := NewSource(bytes.NewReader(entropy))
+	require.NoError(t, err)
+	second := []interface{}{s2.Intn(1000), s2.Intn(1000), s2.Float64()}
+
+	assert.Equal(t, first, second, "expecting same numbers from same entropy")
+
+	_, err = NewSource(bytes.NewReader([]byte{1, 2}))
+	assert.Error(t, err, "expecting error for short entropy source")
+	_, err = NewSource(nil)
+	assert.Error(t, err, "expecting error for nil entropy source")
+}
+
+func TestRead(t *testing.T) {
+	entropy := append(make([]byte, 8), bytes.Repeat([]byte{7}, batchSize)...)
+	s, err := NewSource(bytes.NewReader(entropy))
+	require.NoError(t, err)
+
+	p := make([]byte, 24)
+	n, err := s.Read(p)
+	require.NoError(t, err)
+	assert.Equal(t, 24, n)
+	assert.Equal(t, bytes.Repeat([]byte{7}, 24), p)
+
+	_, err = s.Read(make([]byte, batchSize))
+	assert.Error(t, err, "expecting error for exhausted entropy source")
+}
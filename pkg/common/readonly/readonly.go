@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package readonly implements the read-only mode of the SDK. In read-only mode all write
+// paths (channel executions, resource management mutations, CA enrollments, registrations and
+// revocations and orderer broadcasts) fail with ErrReadOnly, so that a standby environment
+// (e.g. for disaster recovery) may run the same binaries as the primary safely. Queries and
+// event listening are still allowed.
+//
+// Read-only mode is enabled per SDK instance with fabsdk.WithReadOnly. It's carried by the
+// providers of the SDK (see core.Providers), so the clients created from an SDK in read-only
+// mode check it while other SDK instances of the process aren't affected.
+package readonly
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrReadOnly is the cause of the error returned by write operations in read-only mode.
+// The returned error describes the operation, so use errors.Cause (or IsReadOnly) to check for it.
+var ErrReadOnly = errors.New("SDK is in read-only mode")
+
+// Provider is implemented by the SDK providers and contexts, which carry the read-only mode of the SDK
+type Provider interface {
+	ReadOnly() bool
+}
+
+// Check returns an error whose cause is ErrReadOnly if the given provider is in read-only mode, or nil
+// otherwise. The given operation is included in the error message.
+func Check(provider Provider, operation string) error {
+	if !provider.ReadOnly() {
+		return nil
+	}
+	return errors.WithMessage(ErrReadOnly, operation+" is not allowed")
+}
+
+// IsReadOnly returns true if the cause of the given error is ErrReadOnly
+func IsReadOnly(err error) bool {
+	return err != nil && errors.Cause(err) == ErrReadOnly
+}
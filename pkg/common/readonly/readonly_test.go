@@ -0,0 +1,33 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package readonly
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mode bool
+
+func (m mode) ReadOnly() bool {
+	return bool(m)
+}
+
+func TestCheck(t *testing.T) {
+	assert.NoError(t, Check(mode(false), "Execute"))
+
+	err := Check(mode(true), "Execute")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Execute is not allowed")
+	assert.True(t, IsReadOnly(err))
+	assert.True(t, IsReadOnly(errors.WithMessage(err, "failed to submit transaction")))
+
+	assert.False(t, IsReadOnly(nil))
+	assert.False(t, IsReadOnly(errors.New("other")))
+}
@@ -0,0 +1,252 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"context"
+	"io"
+	"net"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Dialer returns the dialer of the connections to the peers and orderers. In replay mode the connections
+// are made to an in-process GRPC server, which never receives any call since the calls are answered with
+// the recorded responses. In record mode nil is returned.
+func (r *Recorder) Dialer() func(ctx context.Context, address string) (net.Conn, error) {
+	if r.mode != Replay {
+		return nil
+	}
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		s, err := r.startSink()
+		if err != nil {
+			return nil, err
+		}
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", s.address())
+	}
+}
+
+func (r *Recorder) recordUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	exchange := r.record(KindUnary, targetOf(cc), method)
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err != nil {
+		r.update(func() { setError(exchange, err) })
+		return err
+	}
+
+	msg, merr := marshal(reply)
+	if merr != nil {
+		logger.Warnf("failed to record response of %s: %s", method, merr)
+		return nil
+	}
+	r.update(func() { exchange.Messages = [][]byte{msg} })
+	return nil
+}
+
+func (r *Recorder) replayUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	exchange, err := r.next(KindUnary, targetOf(cc), method)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	if exchange.Code != uint32(codes.OK) {
+		return status.Error(codes.Code(exchange.Code), exchange.Error)
+	}
+	if len(exchange.Messages) == 0 {
+		return status.Errorf(codes.Internal, "recorded response of %s is missing", method)
+	}
+	return unmarshal(exchange.Messages[0], reply)
+}
+
+func (r *Recorder) recordStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	exchange := r.record(KindStream, targetOf(cc), method)
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		r.update(func() { setError(exchange, err) })
+		return nil, err
+	}
+	r.update(func() { exchange.Open = true })
+	return &recordingStream{ClientStream: stream, recorder: r, exchange: exchange}, nil
+}
+
+func (r *Recorder) replayStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	exchange, err := r.next(KindStream, targetOf(cc), method)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if exchange.Code != uint32(codes.OK) && !exchange.Open && len(exchange.Messages) == 0 {
+		return nil, status.Error(codes.Code(exchange.Code), exchange.Error)
+	}
+	return &replayingStream{ctx: ctx, exchange: exchange}, nil
+}
+
+// recordingStream records the messages received on a stream
+type recordingStream struct {
+	grpc.ClientStream
+	recorder *Recorder
+	exchange *Exchange
+}
+
+// RecvMsg receives and records a message
+func (s *recordingStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.recorder.update(func() {
+			if err == io.EOF {
+				s.exchange.Open = false
+				return
+			}
+			if codeOf(err) != codes.Canceled {
+				s.exchange.Open = false
+				setError(s.exchange, err)
+			}
+		})
+		return err
+	}
+
+	msg, merr := marshal(m)
+	if merr != nil {
+		logger.Warnf("failed to record message of stream: %s", merr)
+		return nil
+	}
+	s.recorder.update(func() { s.exchange.Messages = append(s.exchange.Messages, msg) })
+	return nil
+}
+
+// replayingStream replays the recorded messages of a stream. The messages sent by the client are discarded.
+type replayingStream struct {
+	ctx      context.Context
+	exchange *Exchange
+	next     int
+}
+
+// Header returns empty metadata
+func (s *replayingStream) Header() (metadata.MD, error) {
+	return metadata.MD{}, nil
+}
+
+// Trailer returns empty metadata
+func (s *replayingStream) Trailer() metadata.MD {
+	return metadata.MD{}
+}
+
+// CloseSend does nothing
+func (s *replayingStream) CloseSend() error {
+	return nil
+}
+
+// Context returns the context of the stream
+func (s *replayingStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendMsg discards the message
+func (s *replayingStream) SendMsg(m interface{}) error {
+	return nil
+}
+
+// RecvMsg returns the next recorded message. Once the recorded messages have been received, the stream
+// ends as it ended while recording. A stream that was still open blocks until its context is done.
+func (s *replayingStream) RecvMsg(m interface{}) error {
+	if s.next < len(s.exchange.Messages) {
+		msg := s.exchange.Messages[s.next]
+		s.next++
+		return unmarshal(msg, m)
+	}
+	if s.exchange.Open {
+		<-s.ctx.Done()
+		return status.Error(codes.Canceled, s.ctx.Err().Error())
+	}
+	if s.exchange.Code != uint32(codes.OK) {
+		return status.Error(codes.Code(s.exchange.Code), s.exchange.Error)
+	}
+	return io.EOF
+}
+
+// sink is the in-process GRPC server to which the connections are made in replay mode
+type sink struct {
+	listener net.Listener
+	server   *grpc.Server
+}
+
+func (r *Recorder) startSink() (*sink, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.sink != nil {
+		return r.sink, nil
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen")
+	}
+	s := &sink{listener: lis, server: grpc.NewServer()}
+	go func() {
+		if err := s.server.Serve(lis); err != nil {
+			logger.Debugf("replay server stopped: %s", err)
+		}
+	}()
+	r.sink = s
+	return s, nil
+}
+
+func (s *sink) address() string {
+	return s.listener.Addr().String()
+}
+
+func (s *sink) stop() {
+	s.server.Stop()
+}
+
+func setError(exchange *Exchange, err error) {
+	exchange.Code = uint32(codeOf(err))
+	exchange.Error = err.Error()
+	if st, ok := status.FromError(err); ok {
+		exchange.Error = st.Message()
+	}
+}
+
+func codeOf(err error) codes.Code {
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+	return codes.Unknown
+}
+
+func marshal(m interface{}) ([]byte, error) {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil, errors.Errorf("unsupported message type: %T", m)
+	}
+	return proto.Marshal(msg)
+}
+
+func unmarshal(b []byte, m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "unsupported message type: %T", m)
+	}
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return status.Errorf(codes.Internal, "failed to unmarshal recorded message: %s", err)
+	}
+	return nil
+}
+
+func targetOf(cc *grpc.ClientConn) string {
+	if cc == nil {
+		return ""
+	}
+	return cc.Target()
+}
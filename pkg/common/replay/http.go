@@ -0,0 +1,86 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// recordingTransport records or replays the requests sent to a CA
+type recordingTransport struct {
+	recorder  *Recorder
+	transport http.RoundTripper
+}
+
+// RoundTrip sends the request and records its response in record mode, or returns the recorded response in replay mode
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	method := req.Method + " " + req.URL.Path
+	if t.recorder.mode == Replay {
+		return t.replay(req, method)
+	}
+
+	exchange := t.recorder.record(KindHTTP, req.URL.Host, method)
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		t.recorder.update(func() { exchange.Error = err.Error() })
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if cerr := resp.Body.Close(); cerr != nil {
+		logger.Debugf("failed to close response body: %s", cerr)
+	}
+	if err != nil {
+		t.recorder.update(func() { exchange.Error = err.Error() })
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.recorder.update(func() {
+		exchange.Status = resp.StatusCode
+		exchange.Header = resp.Header
+		exchange.Messages = [][]byte{body}
+	})
+	return resp, nil
+}
+
+func (t *recordingTransport) replay(req *http.Request, method string) (*http.Response, error) {
+	if req.Body != nil {
+		if err := req.Body.Close(); err != nil {
+			logger.Debugf("failed to close request body: %s", err)
+		}
+	}
+
+	exchange, err := t.recorder.next(KindHTTP, req.URL.Host, method)
+	if err != nil {
+		return nil, err
+	}
+	if exchange.Error != "" {
+		return nil, errors.New(exchange.Error)
+	}
+
+	var body []byte
+	if len(exchange.Messages) > 0 {
+		body = exchange.Messages[0]
+	}
+	return &http.Response{
+		Status:        http.StatusText(exchange.Status),
+		StatusCode:    exchange.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        exchange.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
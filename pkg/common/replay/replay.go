@@ -0,0 +1,245 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package replay records the exchanges of the SDK with the peers, orderers and CAs to a fixture file
+// and replays them in tests, so that integration-style tests can run without a live network.
+//
+// In record mode the GRPC calls and the CA requests are sent to the network and their responses are
+// recorded. The fixture is written when the recorder is closed. In replay mode nothing is sent: every
+// call is answered with the next recorded response of the same kind, endpoint and operation, in the
+// order in which the responses were recorded. The requests themselves aren't compared since they
+// contain nonces, timestamps and signatures which differ from one run to the next.
+//
+// A recorder is installed with fabsdk.WithRecorder. In replay mode the connections to the peers and
+// orderers are made to an in-process GRPC server which never receives any call, so the peers and
+// orderers must be configured without TLS (e.g. grpc://peer0.org1.example.com:7051) in the tests that
+// replay a fixture. The certificates returned by replayed enrollments are bound to the keys that were
+// generated while recording, so the tests must use the key store of the recording.
+//
+//  Basic Flow:
+//  1) Record a fixture against a live network
+//  2) Replay the fixture in the tests
+//
+//  recorder, err := replay.New(replay.Record, "testdata/transfer.json")
+//  ...
+//  sdk, err := fabsdk.New(config, fabsdk.WithRecorder(recorder))
+//  ...
+//  sdk.Close()
+//  err = recorder.Close()
+//
+//  recorder, err = replay.New(replay.Replay, "testdata/transfer.json")
+package replay
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+)
+
+var logger = logging.NewLogger("fabsdk/common")
+
+// Mode is the mode of a recorder
+type Mode int
+
+const (
+	// Record sends the calls to the network and records the responses
+	Record Mode = iota
+	// Replay answers the calls with the recorded responses
+	Replay
+)
+
+// String returns the name of the mode
+func (m Mode) String() string {
+	switch m {
+	case Record:
+		return "record"
+	case Replay:
+		return "replay"
+	default:
+		return "unknown"
+	}
+}
+
+// Kinds of exchanges
+const (
+	// KindUnary is a unary GRPC call (e.g. an endorsement or a broadcast)
+	KindUnary = "unary"
+	// KindStream is a streaming GRPC call (e.g. event delivery)
+	KindStream = "stream"
+	// KindHTTP is an HTTP request to a CA
+	KindHTTP = "http"
+)
+
+// Fixture is the content of a fixture file
+type Fixture struct {
+	Exchanges []*Exchange `json:"exchanges"`
+}
+
+// Exchange is a recorded call and its responses
+type Exchange struct {
+	// Kind is the kind of exchange (KindUnary, KindStream or KindHTTP)
+	Kind string `json:"kind"`
+	// Target is the address of the endpoint
+	Target string `json:"target"`
+	// Method is the full name of the GRPC method, or the HTTP method and path of a CA request
+	Method string `json:"method"`
+	// Messages are the marshalled responses of a GRPC call, or the body of the response to a CA request
+	Messages [][]byte `json:"messages,omitempty"`
+	// Code is the GRPC status code of a failed call
+	Code uint32 `json:"code,omitempty"`
+	// Error is the error message of a failed call
+	Error string `json:"error,omitempty"`
+	// Open is true if a stream was still open (or was closed by the client) when recording stopped
+	Open bool `json:"open,omitempty"`
+	// Status is the HTTP status code of the response to a CA request
+	Status int `json:"status,omitempty"`
+	// Header is the HTTP header of the response to a CA request
+	Header http.Header `json:"header,omitempty"`
+}
+
+// Recorder records or replays the exchanges with the network.
+//
+// This component has been designed to be safe for concurrency.
+type Recorder struct {
+	mode    Mode
+	path    string
+	lock    sync.Mutex
+	fixture *Fixture
+	cursors map[string]int
+	sink    *sink
+}
+
+// New returns a recorder in the given mode. In replay mode the fixture file is loaded.
+func New(mode Mode, path string) (*Recorder, error) {
+	r := &Recorder{
+		mode:    mode,
+		path:    path,
+		fixture: &Fixture{},
+		cursors: make(map[string]int),
+	}
+
+	switch mode {
+	case Record:
+	case Replay:
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read fixture [%s]", path)
+		}
+		if err := json.Unmarshal(content, r.fixture); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse fixture [%s]", path)
+		}
+	default:
+		return nil, errors.Errorf("invalid mode: %d", mode)
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the recorder
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Exchanges returns the recorded exchanges
+func (r *Recorder) Exchanges() []*Exchange {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*Exchange{}, r.fixture.Exchanges...)
+}
+
+// Close writes the fixture file in record mode and stops the in-process GRPC server in replay mode
+func (r *Recorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.sink != nil {
+		r.sink.stop()
+		r.sink = nil
+	}
+	if r.mode != Record {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(r.fixture, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal fixture")
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create fixture directory [%s]", filepath.Dir(r.path))
+	}
+	if err := ioutil.WriteFile(r.path, content, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write fixture [%s]", r.path)
+	}
+	logger.Debugf("Recorded %d exchanges to [%s]", len(r.fixture.Exchanges), r.path)
+	return nil
+}
+
+// UnaryInterceptor returns the interceptor that records or replays the unary GRPC calls
+func (r *Recorder) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	if r.mode == Replay {
+		return r.replayUnary
+	}
+	return r.recordUnary
+}
+
+// StreamInterceptor returns the interceptor that records or replays the streaming GRPC calls
+func (r *Recorder) StreamInterceptor() grpc.StreamClientInterceptor {
+	if r.mode == Replay {
+		return r.replayStream
+	}
+	return r.recordStream
+}
+
+// Transport wraps the HTTP transport of a CA client so that the CA requests are recorded or replayed
+func (r *Recorder) Transport(transport http.RoundTripper) http.RoundTripper {
+	return &recordingTransport{recorder: r, transport: transport}
+}
+
+// record appends a new exchange to the fixture
+func (r *Recorder) record(kind, target, method string) *Exchange {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	exchange := &Exchange{Kind: kind, Target: target, Method: method}
+	r.fixture.Exchanges = append(r.fixture.Exchanges, exchange)
+	return exchange
+}
+
+// update updates a recorded exchange
+func (r *Recorder) update(update func()) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	update()
+}
+
+// next returns the next recorded exchange of the given kind, target and method
+func (r *Recorder) next(kind, target, method string) (*Exchange, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := kind + " " + target + " " + method
+	skip := r.cursors[key]
+	for i, exchange := range r.fixture.Exchanges {
+		if exchange.Kind != kind || exchange.Target != target || exchange.Method != method {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		r.cursors[key]++
+		logger.Debugf("Replaying exchange #%d: %s", i, key)
+		return exchange, nil
+	}
+	return nil, errors.Errorf("no recorded exchange left for %s call to [%s] %s", kind, target, method)
+}
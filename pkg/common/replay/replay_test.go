@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package replay
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	endorseMethod = "/protos.Endorser/ProcessProposal"
+	deliverMethod = "/protos.Deliver/Deliver"
+)
+
+func TestRecordReplayUnary(t *testing.T) {
+	path := fixturePath(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	recorder, err := New(Record, path)
+	require.NoError(t, err)
+
+	calls := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		if calls == 2 {
+			return status.Error(codes.Unavailable, "peer is down")
+		}
+		reply.(*timestamp.Timestamp).Seconds = int64(calls)
+		return nil
+	}
+
+	interceptor := recorder.UnaryInterceptor()
+	reply := &timestamp.Timestamp{}
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, reply, nil, invoker))
+	assert.Error(t, interceptor(context.Background(), endorseMethod, nil, reply, nil, invoker))
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, reply, nil, invoker))
+	require.Len(t, recorder.Exchanges(), 3)
+	require.NoError(t, recorder.Close())
+
+	recorder, err = New(Replay, path)
+	require.NoError(t, err)
+	defer recorder.Close()
+
+	interceptor = recorder.UnaryInterceptor()
+	calls = 0
+	reply = &timestamp.Timestamp{}
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, reply, nil, invoker))
+	assert.Equal(t, int64(1), reply.Seconds)
+
+	err = interceptor(context.Background(), endorseMethod, nil, reply, nil, invoker)
+	st, _ := status.FromError(err)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	assert.Equal(t, "peer is down", st.Message())
+
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, reply, nil, invoker))
+	assert.Equal(t, int64(3), reply.Seconds)
+	assert.Equal(t, 0, calls, "expecting calls not to be sent in replay mode")
+
+	assert.Error(t, interceptor(context.Background(), endorseMethod, nil, reply, nil, invoker), "expecting error once the recorded exchanges are exhausted")
+	assert.Error(t, interceptor(context.Background(), "/orderer.AtomicBroadcast/Broadcast", nil, reply, nil, invoker), "expecting error for calls that weren't recorded")
+}
+
+func TestRecordReplayStream(t *testing.T) {
+	path := fixturePath(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	recorder, err := New(Record, path)
+	require.NoError(t, err)
+
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &mockStream{messages: []int64{10, 20}}, nil
+	}
+	stream, err := recorder.StreamInterceptor()(context.Background(), &grpc.StreamDesc{}, nil, deliverMethod, streamer)
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(&timestamp.Timestamp{}))
+	for {
+		if err := stream.RecvMsg(&timestamp.Timestamp{}); err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+	}
+	require.NoError(t, recorder.Close())
+
+	recorder, err = New(Replay, path)
+	require.NoError(t, err)
+	defer recorder.Close()
+
+	stream, err = recorder.StreamInterceptor()(context.Background(), &grpc.StreamDesc{}, nil, deliverMethod, nil)
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(&timestamp.Timestamp{}))
+
+	msg := &timestamp.Timestamp{}
+	require.NoError(t, stream.RecvMsg(msg))
+	assert.Equal(t, int64(10), msg.Seconds)
+	require.NoError(t, stream.RecvMsg(msg))
+	assert.Equal(t, int64(20), msg.Seconds)
+	assert.Equal(t, io.EOF, stream.RecvMsg(msg))
+}
+
+func TestRecordReplayHTTP(t *testing.T) {
+	path := fixturePath(t)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true}`)) //nolint
+	}))
+	defer server.Close()
+
+	recorder, err := New(Record, path)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: recorder.Transport(http.DefaultTransport)}
+	resp, err := client.Post(server.URL+"/enroll", "application/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"success":true}`, readBody(t, resp))
+	require.NoError(t, recorder.Close())
+
+	recorder, err = New(Replay, path)
+	require.NoError(t, err)
+	defer recorder.Close()
+
+	client = &http.Client{Transport: recorder.Transport(http.DefaultTransport)}
+	resp, err = client.Post(server.URL+"/enroll", "application/json", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `{"success":true}`, readBody(t, resp))
+	assert.Equal(t, 1, requests, "expecting requests not to be sent in replay mode")
+
+	_, err = client.Post(server.URL+"/register", "application/json", nil)
+	assert.Error(t, err, "expecting error for requests that weren't recorded")
+}
+
+func TestReplayDialer(t *testing.T) {
+	recorder, err := New(Record, "unused.json")
+	require.NoError(t, err)
+	assert.Nil(t, recorder.Dialer(), "expecting no dialer in record mode")
+
+	_, err = New(Replay, "nonexistent/fixture.json")
+	assert.Error(t, err, "expecting error for missing fixture")
+
+	path := fixturePath(t)
+	defer os.RemoveAll(filepath.Dir(path))
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"exchanges":[]}`), 0644))
+
+	recorder, err = New(Replay, path)
+	require.NoError(t, err)
+	defer recorder.Close()
+
+	conn, err := recorder.Dialer()(context.Background(), "peer0.org1.example.com:7051")
+	require.NoError(t, err)
+	assert.NoError(t, conn.Close())
+}
+
+type mockStream struct {
+	grpc.ClientStream
+	messages []int64
+}
+
+func (s *mockStream) SendMsg(m interface{}) error {
+	return nil
+}
+
+func (s *mockStream) RecvMsg(m interface{}) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	m.(*timestamp.Timestamp).Seconds = s.messages[0]
+	s.messages = s.messages[1:]
+	return nil
+}
+
+func fixturePath(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "replay")
+	require.NoError(t, err)
+	return filepath.Join(dir, "fixture.json")
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
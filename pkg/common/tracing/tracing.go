@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tracing enables the SDK to report trace spans for the transaction lifecycle
+// (endorse, order, commit) and for CA calls, and to propagate the trace context to peers
+// and orderers.
+//
+// The interfaces follow the OpenTelemetry tracing API, so an OpenTelemetry tracer provider
+// and propagator can be plugged in with a thin adapter (see Provider). No spans are
+// recorded until a provider is set with fabsdk.WithTracingProvider or Initialize.
+package tracing
+
+import (
+	reqContext "context"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Attribute is a key/value pair that describes a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String returns a string attribute
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int64 returns an integer attribute
+func Int64(key string, value int64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a unit of work within a trace
+type Span interface {
+	// SetAttributes adds the given attributes to the span
+	SetAttributes(attrs ...Attribute)
+	// RecordError records the given error and marks the span as failed
+	RecordError(err error)
+	// End completes the span
+	End()
+}
+
+// Tracer creates spans
+type Tracer interface {
+	// Start creates a span that is a child of the span in the given context (if any) and
+	// returns a context that holds the new span
+	Start(ctx reqContext.Context, spanName string, attrs ...Attribute) (reqContext.Context, Span)
+}
+
+// Provider supplies the tracers used by the SDK and propagates the trace context over the
+// wire. An OpenTelemetry adapter delegates Tracer to a trace.TracerProvider and Inject to a
+// propagation.TextMapPropagator.
+type Provider interface {
+	// Tracer returns the tracer for the given instrumentation name (the SDK module)
+	Tracer(name string) Tracer
+	// Inject writes the trace context of the given context into the given carrier
+	Inject(ctx reqContext.Context, carrier map[string]string)
+}
+
+var (
+	providerInstance Provider
+	providerLock     sync.RWMutex
+)
+
+// Initialize sets the tracing provider used by the SDK. Passing nil disables tracing.
+func Initialize(p Provider) {
+	providerLock.Lock()
+	defer providerLock.Unlock()
+	providerInstance = p
+}
+
+func provider() Provider {
+	providerLock.RLock()
+	defer providerLock.RUnlock()
+	return providerInstance
+}
+
+// ModuleTracer creates spans on behalf of an SDK module. The tracing provider is resolved
+// when each span is started, so module tracers may be created before the provider is set.
+type ModuleTracer struct {
+	module string
+}
+
+// NewTracer creates a tracer for the given module
+func NewTracer(module string) *ModuleTracer {
+	return &ModuleTracer{module: module}
+}
+
+// Start creates a span that is a child of the span in the given context (if any). If tracing
+// is disabled then the given context and a span that does nothing are returned.
+func (t *ModuleTracer) Start(ctx reqContext.Context, spanName string, attrs ...Attribute) (reqContext.Context, Span) {
+	p := provider()
+	if p == nil {
+		return ctx, noopSpan{}
+	}
+	return p.Tracer(t.module).Start(ctx, spanName, attrs...)
+}
+
+// Inject writes the trace context of the given context into the given carrier. Nothing is
+// written if tracing is disabled.
+func Inject(ctx reqContext.Context, carrier map[string]string) {
+	if p := provider(); p != nil {
+		p.Inject(ctx, carrier)
+	}
+}
+
+// OutgoingContext returns a context whose outgoing GRPC metadata carries the trace context of
+// the given context, so that the trace is continued by the receiving peer or orderer
+func OutgoingContext(ctx reqContext.Context) reqContext.Context {
+	carrier := make(map[string]string)
+	Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return ctx
+	}
+
+	md := metadata.New(carrier)
+	if existing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(existing, md)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// End records the given error (if any) on the span and ends it
+func End(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
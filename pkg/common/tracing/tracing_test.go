@@ -0,0 +1,91 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	reqContext "context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNoopTracer(t *testing.T) {
+	Initialize(nil)
+
+	ctx := reqContext.Background()
+	spanCtx, span := NewTracer("test").Start(ctx, "noop", String("key", "value"))
+	assert.Equal(t, ctx, spanCtx)
+	End(span, errors.New("ignored"))
+
+	assert.Equal(t, ctx, OutgoingContext(ctx), "expecting context to be unchanged when tracing is disabled")
+}
+
+func TestTracer(t *testing.T) {
+	p := &mockProvider{}
+	Initialize(p)
+	defer Initialize(nil)
+
+	ctx, span := NewTracer("test").Start(reqContext.Background(), "parent", String("channel", "mychannel"))
+	span.SetAttributes(Int64("status", 200))
+	End(span, errors.New("failed"))
+
+	require.Equal(t, 1, len(p.spans))
+	s := p.spans[0]
+	assert.Equal(t, "test", s.module)
+	assert.Equal(t, "parent", s.name)
+	assert.Equal(t, []Attribute{String("channel", "mychannel"), Int64("status", 200)}, s.attrs)
+	assert.Error(t, s.err)
+	assert.True(t, s.ended)
+
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("existing", "value"))
+	md, ok := metadata.FromOutgoingContext(OutgoingContext(ctx))
+	require.True(t, ok)
+	assert.Equal(t, []string{"parent"}, md["traceparent"])
+	assert.Equal(t, []string{"value"}, md["existing"])
+}
+
+type spanKey struct{}
+
+type mockSpan struct {
+	module string
+	name   string
+	attrs  []Attribute
+	err    error
+	ended  bool
+}
+
+func (s *mockSpan) SetAttributes(attrs ...Attribute) { s.attrs = append(s.attrs, attrs...) }
+func (s *mockSpan) RecordError(err error)            { s.err = err }
+func (s *mockSpan) End()                             { s.ended = true }
+
+type mockProvider struct {
+	spans []*mockSpan
+}
+
+func (p *mockProvider) Tracer(name string) Tracer {
+	return &mockTracer{provider: p, module: name}
+}
+
+func (p *mockProvider) Inject(ctx reqContext.Context, carrier map[string]string) {
+	if span, ok := ctx.Value(spanKey{}).(*mockSpan); ok {
+		carrier["traceparent"] = span.name
+	}
+}
+
+type mockTracer struct {
+	provider *mockProvider
+	module   string
+}
+
+func (t *mockTracer) Start(ctx reqContext.Context, spanName string, attrs ...Attribute) (reqContext.Context, Span) {
+	span := &mockSpan{module: t.module, name: spanName, attrs: attrs}
+	t.provider.spans = append(t.provider.spans, span)
+	return reqContext.WithValue(ctx, spanKey{}, span), span
+}
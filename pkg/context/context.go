@@ -8,15 +8,18 @@ package context
 
 import (
 	reqContext "context"
+	"sync"
 
 	"github.com/pkg/errors"
 
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 )
 
 // Client supplies the configuration and signing identity to client objects.
@@ -74,6 +77,7 @@ func (c *Channel) ChannelID() string {
 
 //Provider implementation of Providers interface
 type Provider struct {
+	lock                   sync.RWMutex
 	cryptoSuiteConfig      core.CryptoSuiteConfig
 	endpointConfig         fab.EndpointConfig
 	identityConfig         msp.IdentityConfig
@@ -86,6 +90,10 @@ type Provider struct {
 	idMgmtProvider         msp.IdentityManagerProvider
 	infraProvider          fab.InfraProvider
 	channelProvider        fab.ChannelProvider
+	readOnly               bool
+	caTransport            msp.CATransport
+	clock                  clock.Clock
+	randomSource           *random.Source
 }
 
 // CryptoSuite returns the BCCSP provider of sdk.
@@ -95,7 +103,11 @@ func (c *Provider) CryptoSuite() core.CryptoSuite {
 
 // IdentityManager returns identity manager for organization
 func (c *Provider) IdentityManager(orgName string) (msp.IdentityManager, bool) {
-	return c.idMgmtProvider.IdentityManager(orgName)
+	c.lock.RLock()
+	idMgmtProvider := c.idMgmtProvider
+	c.lock.RUnlock()
+
+	return idMgmtProvider.IdentityManager(orgName)
 }
 
 // SigningManager returns signing manager
@@ -103,28 +115,68 @@ func (c *Provider) SigningManager() core.SigningManager {
 	return c.signingManager
 }
 
+// ReadOnly returns true if the SDK is in read-only mode
+func (c *Provider) ReadOnly() bool {
+	return c.readOnly
+}
+
+// CATransport returns the function that wraps the HTTP transport of the CA clients, or nil
+func (c *Provider) CATransport() msp.CATransport {
+	return c.caTransport
+}
+
+// Clock returns the clock of the SDK (the system clock unless set)
+func (c *Provider) Clock() clock.Clock {
+	if c.clock == nil {
+		return clock.Real
+	}
+	return c.clock
+}
+
+// RandomSource returns the source of the random bytes and pseudo-random numbers of the SDK
+// (the default source unless set)
+func (c *Provider) RandomSource() *random.Source {
+	if c.randomSource == nil {
+		return random.Default()
+	}
+	return c.randomSource
+}
+
 // UserStore returns state store
 func (c *Provider) UserStore() msp.UserStore {
 	return c.userStore
 }
 
+//CryptoSuiteConfig returns the cryptosuite config
+func (c *Provider) CryptoSuiteConfig() core.CryptoSuiteConfig {
+	return c.cryptoSuiteConfig
+}
+
 //IdentityConfig returns the Identity config
 func (c *Provider) IdentityConfig() msp.IdentityConfig {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	return c.identityConfig
 }
 
 // DiscoveryProvider returns discovery provider
 func (c *Provider) DiscoveryProvider() fab.DiscoveryProvider {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	return c.discoveryProvider
 }
 
 // LocalDiscoveryProvider returns the local discovery provider
 func (c *Provider) LocalDiscoveryProvider() fab.LocalDiscoveryProvider {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	return c.localDiscoveryProvider
 }
 
 // SelectionProvider returns selection provider
 func (c *Provider) SelectionProvider() fab.SelectionProvider {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	return c.selectionProvider
 }
 
@@ -140,6 +192,8 @@ func (c *Provider) InfraProvider() fab.InfraProvider {
 
 //EndpointConfig returns end point network config
 func (c *Provider) EndpointConfig() fab.EndpointConfig {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	return c.endpointConfig
 }
 
@@ -230,6 +284,34 @@ func WithChannelProvider(channelProvider fab.ChannelProvider) SDKContextParams {
 	}
 }
 
+// WithReadOnly sets the read-only mode of the SDK to Context Provider
+func WithReadOnly(readOnly bool) SDKContextParams {
+	return func(ctx *Provider) {
+		ctx.readOnly = readOnly
+	}
+}
+
+// WithCATransport sets the function that wraps the HTTP transport of the CA clients to Context Provider
+func WithCATransport(transport msp.CATransport) SDKContextParams {
+	return func(ctx *Provider) {
+		ctx.caTransport = transport
+	}
+}
+
+// WithClock sets the clock of the SDK to Context Provider
+func WithClock(c clock.Clock) SDKContextParams {
+	return func(ctx *Provider) {
+		ctx.clock = c
+	}
+}
+
+// WithRandomSource sets the source of the random bytes and pseudo-random numbers of the SDK to Context Provider
+func WithRandomSource(source *random.Source) SDKContextParams {
+	return func(ctx *Provider) {
+		ctx.randomSource = source
+	}
+}
+
 //NewProvider creates new context client provider
 // Not be used by end developers, fabsdk package use only
 func NewProvider(params ...SDKContextParams) *Provider {
@@ -240,6 +322,19 @@ func NewProvider(params ...SDKContextParams) *Provider {
 	return &ctxProvider
 }
 
+// Update applies the given parameters to the provider. It's used when the configuration of the
+// SDK is reloaded so that the clients that share the provider pick up the new configuration. Only
+// the configs, the identity manager provider and the discovery and selection providers may be updated.
+// Not be used by end developers, fabsdk package use only
+func (c *Provider) Update(params ...SDKContextParams) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, param := range params {
+		param(c)
+	}
+}
+
 // localServiceInit interface allows for initializing services
 // with the provided local context
 type localServiceInit interface {
@@ -16,9 +16,28 @@ import (
 	"github.com/pkg/errors"
 )
 
+// tlsOptionsProvider is implemented by the endpoint configurations that provide
+// global TLS options (versions, cipher suites and curves)
+type tlsOptionsProvider interface {
+	TLSOptions() *TLSOptions
+}
+
 // TLSConfig returns the appropriate config for TLS including the root CAs,
 // certs for mutual TLS, and server host override. Works with certs loaded either from a path or embedded pem.
+// The global TLS options of the configuration (if any) are applied to the returned config.
 func TLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointConfig) (*tls.Config, error) {
+	tlsConfig, err := newTLSConfig(cert, serverName, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if op, ok := config.(tlsOptionsProvider); ok {
+		op.TLSOptions().Apply(tlsConfig)
+	}
+	return tlsConfig, nil
+}
+
+func newTLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointConfig) (*tls.Config, error) {
 	certPool, err := config.TLSCACertPool()
 	if err != nil {
 		return nil, err
@@ -40,7 +59,38 @@ func TLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointCon
 		return nil, errors.Errorf("Error loading cert/key pair for TLS client credentials: %v", err)
 	}
 
-	return &tls.Config{RootCAs: tlsCaCertPool, Certificates: clientCerts, ServerName: serverName}, nil
+	return &tls.Config{
+		RootCAs:      tlsCaCertPool,
+		Certificates: clientCerts,
+		ServerName:   serverName,
+		// The client certificates are resolved on each handshake so that new connections
+		// use the current certificates after they've been rotated
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			certs, err := config.TLSClientCerts()
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to load TLS client credentials")
+			}
+			if len(certs) == 0 {
+				return &tls.Certificate{}, nil
+			}
+			return &certs[0], nil
+		},
+	}, nil
+}
+
+// connectionConfigProvider is implemented by the endpoint configurations that provide
+// keepalive and reconnect backoff parameters per type of endpoint
+type connectionConfigProvider interface {
+	ConnectionConfig(endpointType fab.EndpointType) fab.ConnectionConfig
+}
+
+// ConnectionConfig returns the keepalive and reconnect backoff parameters of the connections
+// to the given type of endpoint. The zero value is returned if the configuration doesn't provide them.
+func ConnectionConfig(config fab.EndpointConfig, endpointType fab.EndpointType) fab.ConnectionConfig {
+	if cp, ok := config.(connectionConfigProvider); ok {
+		return cp.ConnectionConfig(endpointType)
+	}
+	return fab.ConnectionConfig{}
 }
 
 // TLSCertHash is a utility method to calculate the SHA256 hash of the configured certificate (for usage in channel headers)
@@ -8,6 +8,7 @@ package comm
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"testing"
 
@@ -19,6 +20,8 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockfab"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
 )
 
 func TestTLSConfigErrorAddingCertificate(t *testing.T) {
@@ -136,3 +139,110 @@ func TestTlsCertHash(t *testing.T) {
 		t.Fatal("Cert hash calculated incorrectly")
 	}
 }
+
+func TestTLSOptions(t *testing.T) {
+	opts, err := NewTLSOptions("TLS1.1", "1.2", []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}, []string{"x25519", "P256"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	config := &tls.Config{}
+	opts.Apply(config)
+	if config.MinVersion != tls.VersionTLS11 || config.MaxVersion != tls.VersionTLS12 {
+		t.Fatalf("Unexpected TLS versions [%x, %x]", config.MinVersion, config.MaxVersion)
+	}
+	if !reflect.DeepEqual(config.CipherSuites, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256}) {
+		t.Fatalf("Unexpected cipher suites %v", config.CipherSuites)
+	}
+	if !reflect.DeepEqual(config.CurvePreferences, []tls.CurveID{tls.X25519, tls.CurveP256}) {
+		t.Fatalf("Unexpected curve preferences %v", config.CurvePreferences)
+	}
+
+	if _, err := NewTLSOptions("1.4", "", nil, nil); err == nil {
+		t.Fatal("Expected failure for unsupported TLS version")
+	}
+	if _, err := NewTLSOptions("1.2", "1.1", nil, nil); err == nil {
+		t.Fatal("Expected failure for minimum version greater than maximum version")
+	}
+	if _, err := NewTLSOptions("", "", []string{"TLS_RSA_WITH_RC4_128_SHA"}, nil); err == nil {
+		t.Fatal("Expected failure for unsupported cipher suite")
+	}
+}
+
+func TestTLSOptionsFromGRPCOptions(t *testing.T) {
+	opts, err := TLSOptionsFromGRPCOptions(map[string]interface{}{"ssl-target-name-override": "peer0"})
+	if err != nil || opts != nil {
+		t.Fatalf("Expected no TLS options [%v, %v]", opts, err)
+	}
+
+	opts, err = TLSOptionsFromGRPCOptions(map[string]interface{}{
+		"tls-min-version":   "1.2",
+		"tls-cipher-suites": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if opts.MinVersion != tls.VersionTLS12 || len(opts.CipherSuites) != 2 {
+		t.Fatalf("Unexpected TLS options %#v", opts)
+	}
+
+	// Options that aren't set are left untouched
+	config := &tls.Config{MaxVersion: tls.VersionTLS12, CurvePreferences: []tls.CurveID{tls.CurveP384}}
+	opts.Apply(config)
+	if config.MaxVersion != tls.VersionTLS12 || len(config.CurvePreferences) != 1 {
+		t.Fatalf("Unexpected TLS config %#v", config)
+	}
+
+	var nilOpts *TLSOptions
+	nilOpts.Apply(config)
+}
+
+func TestCompressionDialOpts(t *testing.T) {
+	if c := CompressionFromGRPCOptions(map[string]interface{}{"compression": " GZIP "}); c != GzipCompression {
+		t.Fatalf("Unexpected compression [%s]", c)
+	}
+	if c := CompressionFromGRPCOptions(map[string]interface{}{"compression": "none"}); c != "" {
+		t.Fatalf("Unexpected compression [%s]", c)
+	}
+
+	opts, err := CompressionDialOpts("")
+	if err != nil || len(opts) != 0 {
+		t.Fatalf("Expected no dial options for empty compression [%v, %v]", opts, err)
+	}
+
+	opts, err = CompressionDialOpts(GzipCompression)
+	if err != nil || len(opts) != 2 {
+		t.Fatalf("Expected compressor and decompressor dial options [%v, %v]", opts, err)
+	}
+
+	if _, err := CompressionDialOpts("snappy"); err == nil {
+		t.Fatal("Expected failure for unsupported compression")
+	}
+}
+
+func TestMessageSizes(t *testing.T) {
+	sizes, err := MessageSizesFromGRPCOptions(map[string]interface{}{"max-recv-msg-size": 200, "max-send-msg-size": "300"})
+	if err != nil || sizes != (MessageSizes{MaxRecv: 200, MaxSend: 300}) {
+		t.Fatalf("Unexpected message sizes [%#v, %v]", sizes, err)
+	}
+	if _, err := MessageSizesFromGRPCOptions(map[string]interface{}{"max-recv-msg-size": -1}); err == nil {
+		t.Fatal("Expected failure for negative message size")
+	}
+
+	if opts := MessageSizeCallOpts(context.Background()); len(opts) != 0 {
+		t.Fatalf("Expected no call options without override [%v]", opts)
+	}
+	ctx := WithMessageSizes(context.Background(), MessageSizes{MaxSend: 500})
+	if opts := MessageSizeCallOpts(ctx); len(opts) != 1 {
+		t.Fatalf("Expected send size call option [%v]", opts)
+	}
+
+	err = MessageSizeError(grpcstatus.Error(codes.ResourceExhausted, "grpc: received message larger than max"))
+	if !strings.Contains(err.Error(), "max-recv-msg-size") {
+		t.Fatalf("Expected message size hint [%s]", err)
+	}
+	err = MessageSizeError(grpcstatus.Error(codes.Unavailable, "unavailable"))
+	if strings.Contains(err.Error(), "max-recv-msg-size") {
+		t.Fatalf("Unexpected message size hint [%s]", err)
+	}
+}
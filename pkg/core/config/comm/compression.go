@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+)
+
+const (
+	// compressionOption is the GRPC option of a peer or orderer that selects the compression of the calls
+	compressionOption = "compression"
+
+	// GzipCompression compresses the messages exchanged with an endpoint using gzip
+	GzipCompression = "gzip"
+)
+
+// CompressionFromGRPCOptions returns the compression of the calls to a peer or orderer
+// that is set in its GRPC options ("compression"). An empty string is returned if the
+// calls aren't compressed.
+func CompressionFromGRPCOptions(grpcOpts map[string]interface{}) string {
+	compression := strings.ToLower(strings.TrimSpace(cast.ToString(grpcOpts[compressionOption])))
+	if compression == "none" {
+		return ""
+	}
+	return compression
+}
+
+// CompressionDialOpts returns the dial options that enable the given compression ("gzip")
+// on a connection. No option is returned if the compression is empty.
+func CompressionDialOpts(compression string) ([]grpc.DialOption, error) {
+	switch compression {
+	case "":
+		return nil, nil
+	case GzipCompression:
+		return []grpc.DialOption{
+			grpc.WithCompressor(grpc.NewGZIPCompressor()),
+			grpc.WithDecompressor(grpc.NewGZIPDecompressor()),
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported compression [%s]", compression)
+	}
+}
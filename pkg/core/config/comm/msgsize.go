@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	reqContext "context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMaxMsgSize is the maximum size of the messages sent to and received from
+	// an endpoint when it isn't configured (same as Fabric)
+	DefaultMaxMsgSize = 100 * 1024 * 1024
+
+	// maxRecvMsgSizeOption is the GRPC option of a peer or orderer that sets the maximum size of the received messages
+	maxRecvMsgSizeOption = "max-recv-msg-size"
+
+	// maxSendMsgSizeOption is the GRPC option of a peer or orderer that sets the maximum size of the sent messages
+	maxSendMsgSizeOption = "max-send-msg-size"
+)
+
+// MessageSizes holds the maximum sizes (in bytes) of the messages received from and sent to an
+// endpoint. A size of zero means that the default is used.
+type MessageSizes struct {
+	MaxRecv int
+	MaxSend int
+}
+
+type messageSizesContextKey struct{}
+
+// MessageSizesFromGRPCOptions returns the maximum message sizes of a peer or orderer that are
+// set in its GRPC options ("max-recv-msg-size" and "max-send-msg-size")
+func MessageSizesFromGRPCOptions(grpcOpts map[string]interface{}) (MessageSizes, error) {
+	var sizes MessageSizes
+	var err error
+	if sizes.MaxRecv, err = msgSizeOption(grpcOpts, maxRecvMsgSizeOption); err != nil {
+		return MessageSizes{}, err
+	}
+	if sizes.MaxSend, err = msgSizeOption(grpcOpts, maxSendMsgSizeOption); err != nil {
+		return MessageSizes{}, err
+	}
+	return sizes, nil
+}
+
+func msgSizeOption(grpcOpts map[string]interface{}, key string) (int, error) {
+	value, ok := grpcOpts[key]
+	if !ok {
+		return 0, nil
+	}
+	size, err := cast.ToIntE(value)
+	if err != nil || size < 0 {
+		return 0, errors.Errorf("invalid %s [%v]", key, value)
+	}
+	return size, nil
+}
+
+// MessageSizeDialOpts returns the dial options that set the maximum message sizes of a connection
+func MessageSizeDialOpts(sizes MessageSizes) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(orDefaultMsgSize(sizes.MaxRecv)),
+			grpc.MaxCallSendMsgSize(orDefaultMsgSize(sizes.MaxSend)),
+		),
+	}
+}
+
+func orDefaultMsgSize(size int) int {
+	if size > 0 {
+		return size
+	}
+	return DefaultMaxMsgSize
+}
+
+// WithMessageSizes returns a context that overrides the maximum message sizes of the calls made on its behalf
+func WithMessageSizes(ctx reqContext.Context, sizes MessageSizes) reqContext.Context {
+	return reqContext.WithValue(ctx, messageSizesContextKey{}, sizes)
+}
+
+// MessageSizeCallOpts returns the call options that apply the maximum message sizes
+// carried by the given context (see WithMessageSizes)
+func MessageSizeCallOpts(ctx reqContext.Context) []grpc.CallOption {
+	sizes, ok := ctx.Value(messageSizesContextKey{}).(MessageSizes)
+	if !ok {
+		return nil
+	}
+
+	var opts []grpc.CallOption
+	if sizes.MaxRecv > 0 {
+		opts = append(opts, grpc.MaxCallRecvMsgSize(sizes.MaxRecv))
+	}
+	if sizes.MaxSend > 0 {
+		opts = append(opts, grpc.MaxCallSendMsgSize(sizes.MaxSend))
+	}
+	return opts
+}
+
+// MessageSizeError returns the given error with a hint on how to raise the maximum message sizes
+// if the error was caused by a message that exceeds them. Otherwise the error is returned as is.
+func MessageSizeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if s, ok := grpcstatus.FromError(errors.Cause(err)); !ok || s.Code() != codes.ResourceExhausted {
+		return err
+	}
+	return errors.WithMessage(err, "message exceeds the maximum size - increase the max-recv-msg-size or max-send-msg-size GRPC options of the endpoint")
+}
@@ -0,0 +1,169 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"crypto/tls"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// The GRPC options of a peer or orderer that override the global TLS options
+const (
+	tlsMinVersionOption       = "tls-min-version"
+	tlsMaxVersionOption       = "tls-max-version"
+	tlsCipherSuitesOption     = "tls-cipher-suites"
+	tlsCurvePreferencesOption = "tls-curve-preferences"
+)
+
+// tlsVersions holds the supported TLS versions. TLS 1.3 is added when building with
+// Go 1.12 or later (see tlsoptions_go112.go).
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+}
+
+var cipherSuites = map[string]uint16{
+	"TLS_RSA_WITH_AES_128_CBC_SHA":            tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_RSA_WITH_AES_256_CBC_SHA":            tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":         tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":    tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":    tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":      tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":      tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":   tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256": tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":   tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384": tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305":    tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305":  tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+}
+
+var curves = map[string]tls.CurveID{
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+	"X25519": tls.X25519,
+}
+
+// TLSOptions restricts the TLS versions, cipher suites and curves used by a connection.
+// Zero values leave the defaults of the crypto/tls package in place. Note that the cipher
+// suites of TLS 1.3 (where supported) aren't configurable, so setting the minimum version
+// to 1.3 (TLS 1.3-only mode) makes CipherSuites irrelevant.
+type TLSOptions struct {
+	MinVersion       uint16
+	MaxVersion       uint16
+	CipherSuites     []uint16
+	CurvePreferences []tls.CurveID
+}
+
+// NewTLSOptions parses the TLS options. Versions are given as "1.0" to "1.2", or "1.3" if the
+// SDK is built with Go 1.12 or later (optionally prefixed with "TLS"), cipher suites by their IANA names (e.g. "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256")
+// and curves as "P256", "P384", "P521" or "X25519". Empty values are ignored.
+func NewTLSOptions(minVersion, maxVersion string, suites, curvePreferences []string) (*TLSOptions, error) {
+	opts := &TLSOptions{}
+
+	var err error
+	if opts.MinVersion, err = parseTLSVersion(minVersion); err != nil {
+		return nil, err
+	}
+	if opts.MaxVersion, err = parseTLSVersion(maxVersion); err != nil {
+		return nil, err
+	}
+	if opts.MinVersion != 0 && opts.MaxVersion != 0 && opts.MinVersion > opts.MaxVersion {
+		return nil, errors.Errorf("minimum TLS version [%s] is greater than maximum TLS version [%s]", minVersion, maxVersion)
+	}
+
+	for _, name := range suites {
+		suite, ok := cipherSuites[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, errors.Errorf("unsupported TLS cipher suite [%s]", name)
+		}
+		opts.CipherSuites = append(opts.CipherSuites, suite)
+	}
+
+	for _, name := range curvePreferences {
+		curve, ok := curves[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, errors.Errorf("unsupported TLS curve [%s]", name)
+		}
+		opts.CurvePreferences = append(opts.CurvePreferences, curve)
+	}
+
+	return opts, nil
+}
+
+// TLSOptionsFromGRPCOptions parses the TLS options of a peer or orderer from its GRPC options
+// ("tls-min-version", "tls-max-version", "tls-cipher-suites" and "tls-curve-preferences").
+// Nil is returned if none of these options are set.
+func TLSOptionsFromGRPCOptions(grpcOpts map[string]interface{}) (*TLSOptions, error) {
+	minVersion := cast.ToString(grpcOpts[tlsMinVersionOption])
+	maxVersion := cast.ToString(grpcOpts[tlsMaxVersionOption])
+	suites := toStringSlice(grpcOpts[tlsCipherSuitesOption])
+	curvePreferences := toStringSlice(grpcOpts[tlsCurvePreferencesOption])
+
+	if minVersion == "" && maxVersion == "" && len(suites) == 0 && len(curvePreferences) == 0 {
+		return nil, nil
+	}
+
+	opts, err := NewTLSOptions(minVersion, maxVersion, suites, curvePreferences)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid TLS options")
+	}
+	return opts, nil
+}
+
+// Apply sets the options that have a value on the given TLS config
+func (o *TLSOptions) Apply(config *tls.Config) {
+	if o == nil {
+		return
+	}
+	if o.MinVersion != 0 {
+		config.MinVersion = o.MinVersion
+	}
+	if o.MaxVersion != 0 {
+		config.MaxVersion = o.MaxVersion
+	}
+	if len(o.CipherSuites) > 0 {
+		config.CipherSuites = o.CipherSuites
+	}
+	if len(o.CurvePreferences) > 0 {
+		config.CurvePreferences = o.CurvePreferences
+	}
+}
+
+func parseTLSVersion(version string) (uint16, error) {
+	v := strings.TrimSpace(strings.ToUpper(version))
+	if v == "" {
+		return 0, nil
+	}
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "TLS"), "V")
+
+	tlsVersion, ok := tlsVersions[v]
+	if !ok {
+		return 0, errors.Errorf("unsupported TLS version [%s]", version)
+	}
+	return tlsVersion, nil
+}
+
+// toStringSlice converts a list or a comma separated string to a slice of strings
+func toStringSlice(value interface{}) []string {
+	if s, ok := value.(string); ok {
+		var values []string
+		for _, v := range strings.Split(s, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	return cast.ToStringSlice(value)
+}
@@ -0,0 +1,17 @@
+//go:build go1.12
+// +build go1.12
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import "crypto/tls"
+
+// TLS 1.3 is only supported by crypto/tls as of Go 1.12
+func init() {
+	tlsVersions["1.3"] = tls.VersionTLS13
+}
@@ -0,0 +1,32 @@
+//go:build go1.12
+// +build go1.12
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestTLSOptionsTLS13(t *testing.T) {
+	opts, err := NewTLSOptions("TLS1.2", "1.3", nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	config := &tls.Config{}
+	opts.Apply(config)
+	if config.MinVersion != tls.VersionTLS12 || config.MaxVersion != tls.VersionTLS13 {
+		t.Fatalf("Unexpected TLS versions [%x, %x]", config.MinVersion, config.MaxVersion)
+	}
+
+	if _, err := NewTLSOptions("1.3", "1.2", nil, nil); err == nil {
+		t.Fatal("Expected failure for minimum version greater than maximum version")
+	}
+}
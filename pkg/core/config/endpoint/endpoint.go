@@ -10,6 +10,7 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"io/ioutil"
+	"net"
 	"strings"
 
 	"regexp"
@@ -40,6 +41,37 @@ func ToAddress(url string) string {
 	return url
 }
 
+// Addresses returns the addresses of an endpoint. The URL of an endpoint that is reachable on
+// several networks may list the addresses separated by commas in order of preference, e.g.
+// "grpcs://peer0.internal:7051,peer0.example.com:7051"
+func Addresses(url string) []string {
+	var addresses []string
+	for _, address := range strings.Split(ToAddress(url), ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// TLSServerName returns the host name that the TLS certificate of an endpoint is verified against:
+// the override if set or, if the URL lists several addresses, the host of the first one. An empty
+// name is returned otherwise, in which case GRPC uses the host of the address it dials.
+func TLSServerName(url string, override string) string {
+	if override != "" {
+		return override
+	}
+	addresses := Addresses(url)
+	if len(addresses) < 2 {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addresses[0])
+	if err != nil {
+		return addresses[0]
+	}
+	return host
+}
+
 //AttemptSecured is a utility function which verifies URL and returns if secured connections needs to established
 // for protocol 'grpcs' in URL returns true
 // for protocol 'grpc' in URL returns false
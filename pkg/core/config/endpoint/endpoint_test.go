@@ -49,6 +49,23 @@ func TestToAddress(t *testing.T) {
 	}
 }
 
+func TestAddresses(t *testing.T) {
+	addresses := Addresses("grpcs://peer0.internal:7051, peer0.example.com:7051")
+	if len(addresses) != 2 || addresses[0] != "peer0.internal:7051" || addresses[1] != "peer0.example.com:7051" {
+		t.Fatalf("unexpected addresses %v", addresses)
+	}
+
+	if name := TLSServerName("grpcs://peer0.internal:7051,peer0.example.com:7051", ""); name != "peer0.internal" {
+		t.Fatalf("expected the host of the first address as server name but got [%s]", name)
+	}
+	if name := TLSServerName("grpcs://peer0.internal:7051,peer0.example.com:7051", "peer0"); name != "peer0" {
+		t.Fatalf("expected the override as server name but got [%s]", name)
+	}
+	if name := TLSServerName("grpcs://peer0.example.com:7051", ""); name != "" {
+		t.Fatalf("expected no server name for a single address but got [%s]", name)
+	}
+}
+
 func TestAttemptSecured(t *testing.T) {
 	b := AttemptSecured("http://some.url", true)
 	if b {
@@ -68,6 +68,19 @@ func (c *CryptoSuite) Verify(k core.Key, signature, digest []byte, opts core.Sig
 	return c.BCCSP.Verify(k.(*key).key, signature, digest, opts)
 }
 
+// CheckSession verifies the session with the HSM if the BCCSP is backed by an HSM (see the
+// pkcs11 cryptosuite). Nothing is verified for software-based BCCSPs.
+func (c *CryptoSuite) CheckSession() error {
+	if sc, ok := c.BCCSP.(sessionChecker); ok {
+		return sc.CheckSession()
+	}
+	return nil
+}
+
+type sessionChecker interface {
+	CheckSession() error
+}
+
 type key struct {
 	key bccsp.Key
 }
@@ -64,6 +64,9 @@ func TestCryptoSuiteByConfig(t *testing.T) {
 	assert.Empty(t, err, "Not supposed to get error on GetSuiteByConfig call : %s", err)
 	assert.NotEmpty(t, hashbytes, "Supposed to get valid hash from sample cryptosuite")
 
+	err = samplecryptoSuite.(*CryptoSuite).CheckSession()
+	assert.Empty(t, err, "Not supposed to get error on CheckSession call for SW cryptosuite : %s", err)
+
 }
 
 func TestCryptoSuiteByConfigFailures(t *testing.T) {
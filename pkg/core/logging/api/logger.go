@@ -63,6 +63,21 @@ type Logger interface {
 	Errorln(args ...interface{})
 }
 
+// Field is a key/value pair that adds context (e.g. the channel, transaction ID or peer) to log messages
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// FieldLogger is implemented by loggers that support structured fields. The fields given
+// to loggers that don't implement this interface are ignored.
+type FieldLogger interface {
+	Logger
+
+	// WithFields returns a logger that adds the given fields to each log message
+	WithFields(fields ...Field) Logger
+}
+
 // LoggerProvider is a factory for module loggers
 // TODO: should this be renamed to LoggerFactory?
 type LoggerProvider interface {
@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package logrusadapter provides a logger provider that writes the logs of the SDK to a logrus logger.
+//
+//  Usage:
+//  logging.Initialize(logrusadapter.NewProvider(logrusLogger))
+package logrusadapter
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/sirupsen/logrus"
+)
+
+// moduleKey is the key of the field that holds the name of the SDK module
+const moduleKey = "module"
+
+// Provider creates loggers that write to a logrus logger
+type Provider struct {
+	logger *logrus.Logger
+}
+
+// NewProvider returns a logger provider that writes to the given logrus logger. Log levels
+// are controlled by the logrus logger.
+func NewProvider(logger *logrus.Logger) *Provider {
+	return &Provider{logger: logger}
+}
+
+// GetLogger returns a logger that adds the name of the given module to each log message
+func (p *Provider) GetLogger(module string) api.Logger {
+	return &Logger{entry: p.logger.WithField(moduleKey, module)}
+}
+
+// Logger writes the logs of an SDK module to a logrus logger
+type Logger struct {
+	entry *logrus.Entry
+}
+
+// WithFields returns a logger that adds the given fields to each log message
+func (l *Logger) WithFields(fields ...api.Field) api.Logger {
+	logrusFields := make(logrus.Fields, len(fields))
+	for _, field := range fields {
+		logrusFields[field.Key] = field.Value
+	}
+	return &Logger{entry: l.entry.WithFields(logrusFields)}
+}
+
+// Fatal logs a message at fatal level and then calls os.Exit(1)
+func (l *Logger) Fatal(v ...interface{}) { l.entry.Fatal(v...) }
+
+// Fatalf logs a formatted message at fatal level and then calls os.Exit(1)
+func (l *Logger) Fatalf(format string, v ...interface{}) { l.entry.Fatalf(format, v...) }
+
+// Fatalln logs a message at fatal level and then calls os.Exit(1)
+func (l *Logger) Fatalln(v ...interface{}) { l.entry.Fatalln(v...) }
+
+// Panic logs a message at panic level and then panics
+func (l *Logger) Panic(v ...interface{}) { l.entry.Panic(v...) }
+
+// Panicf logs a formatted message at panic level and then panics
+func (l *Logger) Panicf(format string, v ...interface{}) { l.entry.Panicf(format, v...) }
+
+// Panicln logs a message at panic level and then panics
+func (l *Logger) Panicln(v ...interface{}) { l.entry.Panicln(v...) }
+
+// Print logs a message at info level
+func (l *Logger) Print(v ...interface{}) { l.entry.Print(v...) }
+
+// Printf logs a formatted message at info level
+func (l *Logger) Printf(format string, v ...interface{}) { l.entry.Printf(format, v...) }
+
+// Println logs a message at info level
+func (l *Logger) Println(v ...interface{}) { l.entry.Println(v...) }
+
+// Debug logs a message at debug level
+func (l *Logger) Debug(args ...interface{}) { l.entry.Debug(args...) }
+
+// Debugf logs a formatted message at debug level
+func (l *Logger) Debugf(format string, args ...interface{}) { l.entry.Debugf(format, args...) }
+
+// Debugln logs a message at debug level
+func (l *Logger) Debugln(args ...interface{}) { l.entry.Debugln(args...) }
+
+// Info logs a message at info level
+func (l *Logger) Info(args ...interface{}) { l.entry.Info(args...) }
+
+// Infof logs a formatted message at info level
+func (l *Logger) Infof(format string, args ...interface{}) { l.entry.Infof(format, args...) }
+
+// Infoln logs a message at info level
+func (l *Logger) Infoln(args ...interface{}) { l.entry.Infoln(args...) }
+
+// Warn logs a message at warn level
+func (l *Logger) Warn(args ...interface{}) { l.entry.Warn(args...) }
+
+// Warnf logs a formatted message at warn level
+func (l *Logger) Warnf(format string, args ...interface{}) { l.entry.Warnf(format, args...) }
+
+// Warnln logs a message at warn level
+func (l *Logger) Warnln(args ...interface{}) { l.entry.Warnln(args...) }
+
+// Error logs a message at error level
+func (l *Logger) Error(args ...interface{}) { l.entry.Error(args...) }
+
+// Errorf logs a formatted message at error level
+func (l *Logger) Errorf(format string, args ...interface{}) { l.entry.Errorf(format, args...) }
+
+// Errorln logs a message at error level
+func (l *Logger) Errorln(args ...interface{}) { l.entry.Errorln(args...) }
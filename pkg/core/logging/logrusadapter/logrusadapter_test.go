@@ -0,0 +1,34 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package logrusadapter
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	logrusLogger, hook := test.NewNullLogger()
+	provider := NewProvider(logrusLogger)
+
+	logger := provider.GetLogger("fabsdk/client")
+	logger.Debugf("debug isn't enabled")
+	assert.Empty(t, hook.AllEntries(), "debug messages aren't expected at info level")
+
+	logger.(api.FieldLogger).WithFields(api.Field{Key: "channel", Value: "mychannel"}, api.Field{Key: "txID", Value: "abc"}).Warnf("brown %s jumps over the lazy %s", "fox", "dog")
+
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.WarnLevel, entry.Level)
+	assert.Equal(t, "brown fox jumps over the lazy dog", entry.Message)
+	assert.Equal(t, logrus.Fields{"module": "fabsdk/client", "channel": "mychannel", "txID": "abc"}, entry.Data)
+}
@@ -8,6 +8,7 @@ package metadata
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
@@ -36,3 +37,15 @@ func ParseLevel(level string) (api.Level, error) {
 func ParseString(level api.Level) string {
 	return levelNames[level]
 }
+
+//FormatFields returns the string representation (key=value) of the given fields
+func FormatFields(fields []api.Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	formatted := make([]string, len(fields))
+	for i, field := range fields {
+		formatted[i] = fmt.Sprintf("%s=%v", field.Key, field.Value)
+	}
+	return strings.Join(formatted, " ")
+}
@@ -62,6 +62,7 @@ type Log struct {
 	deflogger    *log.Logger
 	customLogger api.Logger
 	module       string
+	fields       []api.Field
 	custom       bool
 	once         sync.Once
 }
@@ -387,6 +388,16 @@ func (l *Log) Errorln(args ...interface{}) {
 	l.logln(opts, api.ERROR, args...)
 }
 
+// WithFields returns a logger that adds the given fields to each log message.
+// The fields are passed on to the custom logger if it supports fields.
+func (l *Log) WithFields(fields ...api.Field) api.Logger {
+	return &Log{
+		deflogger: l.deflogger,
+		module:    l.module,
+		fields:    append(append([]api.Field{}, l.fields...), fields...),
+	}
+}
+
 //ChangeOutput for changing output destination for the logger.
 func (l *Log) ChangeOutput(output io.Writer) {
 	l.deflogger.SetOutput(output)
@@ -395,7 +406,7 @@ func (l *Log) ChangeOutput(output io.Writer) {
 func (l *Log) logf(opts *loggerOpts, level api.Level, format string, args ...interface{}) {
 	//Format prefix to show function name and log level and to indicate that timezone used is UTC
 	customPrefix := fmt.Sprintf(logLevelFormatter, l.getCallerInfo(opts), metadata.ParseString(level))
-	err := l.deflogger.Output(2, customPrefix+fmt.Sprintf(format, args...))
+	err := l.deflogger.Output(2, customPrefix+l.fieldsPrefix()+fmt.Sprintf(format, args...))
 	if err != nil {
 		fmt.Printf("error from deflogger.Output %v\n", err)
 	}
@@ -404,7 +415,7 @@ func (l *Log) logf(opts *loggerOpts, level api.Level, format string, args ...int
 func (l *Log) log(opts *loggerOpts, level api.Level, args ...interface{}) {
 	//Format prefix to show function name and log level and to indicate that timezone used is UTC
 	customPrefix := fmt.Sprintf(logLevelFormatter, l.getCallerInfo(opts), metadata.ParseString(level))
-	err := l.deflogger.Output(2, customPrefix+fmt.Sprint(args...))
+	err := l.deflogger.Output(2, customPrefix+l.fieldsPrefix()+fmt.Sprint(args...))
 	if err != nil {
 		fmt.Printf("error from deflogger.Output %v\n", err)
 	}
@@ -413,7 +424,7 @@ func (l *Log) log(opts *loggerOpts, level api.Level, args ...interface{}) {
 func (l *Log) logln(opts *loggerOpts, level api.Level, args ...interface{}) {
 	//Format prefix to show function name and log level and to indicate that timezone used is UTC
 	customPrefix := fmt.Sprintf(logLevelFormatter, l.getCallerInfo(opts), metadata.ParseString(level))
-	err := l.deflogger.Output(2, customPrefix+fmt.Sprintln(args...))
+	err := l.deflogger.Output(2, customPrefix+l.fieldsPrefix()+fmt.Sprintln(args...))
 	if err != nil {
 		fmt.Printf("error from deflogger.Output %v\n", err)
 	}
@@ -423,12 +434,22 @@ func (l *Log) loadCustomLogger() bool {
 	l.once.Do(func() {
 		if atomic.LoadInt32(&useCustomLogger) > 0 {
 			l.customLogger = loggerProviderInstance.GetLogger(l.module)
+			if fieldLogger, ok := l.customLogger.(api.FieldLogger); ok && len(l.fields) > 0 {
+				l.customLogger = fieldLogger.WithFields(l.fields...)
+			}
 			l.custom = true
 		}
 	})
 	return l.custom
 }
 
+func (l *Log) fieldsPrefix() string {
+	if len(l.fields) == 0 {
+		return ""
+	}
+	return "[" + metadata.FormatFields(l.fields) + "] "
+}
+
 func (l *Log) getCallerInfo(opts *loggerOpts) string {
 
 	if !opts.callerInfoEnabled {
@@ -93,6 +93,27 @@ func testDefaultLogging(t *testing.T) {
 	moduleLevels = &metadata.ModuleLevels{}
 }
 
+func TestDefaultLoggingWithFields(t *testing.T) {
+	logger := LoggerProvider().GetLogger(moduleName)
+	logger.(*Log).ChangeOutput(&buf)
+	defer buf.Reset()
+
+	fieldLogger, ok := logger.(api.FieldLogger)
+	assert.True(t, ok, "default logger should support fields")
+
+	channelLogger := fieldLogger.WithFields(api.Field{Key: "channel", Value: "mychannel"})
+	channelLogger.(api.FieldLogger).WithFields(api.Field{Key: "txID", Value: "abc"}).Info("brown fox jumps over the lazy dog")
+	assert.Regexp(t, "INFO \\[channel=mychannel txID=abc\\] brown fox jumps over the lazy dog", buf.String())
+
+	buf.Reset()
+	channelLogger.Info("brown fox jumps over the lazy dog")
+	assert.Regexp(t, "INFO \\[channel=mychannel\\] brown fox jumps over the lazy dog", buf.String())
+
+	buf.Reset()
+	logger.Info("brown fox jumps over the lazy dog")
+	assert.NotContains(t, buf.String(), "channel=", "fields should only be added by the derived logger")
+}
+
 func TestDefaultLoggingPanic(t *testing.T) {
 
 	//Reset custom logger, need default one
@@ -0,0 +1,178 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package slogadapter provides a logger provider that writes the logs of the SDK to a log/slog
+// logger. The package requires Go 1.21 or later.
+//
+//  Usage:
+//  logging.Initialize(slogadapter.NewProvider(slog.Default()))
+package slogadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+)
+
+// moduleKey is the key of the attribute that holds the name of the SDK module
+const moduleKey = "module"
+
+// callerSkip skips the frames of runtime.Callers, Logger.log, the Logger method and the SDK
+// logger when the source of a log message is reported
+const callerSkip = 4
+
+// Provider creates loggers that write to a slog logger
+type Provider struct {
+	logger *slog.Logger
+}
+
+// NewProvider returns a logger provider that writes to the given slog logger. Log levels are
+// controlled by the handler of the slog logger.
+func NewProvider(logger *slog.Logger) *Provider {
+	return &Provider{logger: logger}
+}
+
+// GetLogger returns a logger that adds the name of the given module to each log message
+func (p *Provider) GetLogger(module string) api.Logger {
+	return &Logger{logger: p.logger.With(moduleKey, module)}
+}
+
+// Logger writes the logs of an SDK module to a slog logger
+type Logger struct {
+	logger *slog.Logger
+}
+
+// WithFields returns a logger that adds the given fields to each log message
+func (l *Logger) WithFields(fields ...api.Field) api.Logger {
+	args := make([]interface{}, 0, len(fields))
+	for _, field := range fields {
+		args = append(args, slog.Any(field.Key, field.Value))
+	}
+	return &Logger{logger: l.logger.With(args...)}
+}
+
+// Fatal logs a message at error level and then calls os.Exit(1)
+func (l *Logger) Fatal(v ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprint(v...))
+	os.Exit(1)
+}
+
+// Fatalf logs a formatted message at error level and then calls os.Exit(1)
+func (l *Logger) Fatalf(format string, v ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprintf(format, v...))
+	os.Exit(1)
+}
+
+// Fatalln logs a message at error level and then calls os.Exit(1)
+func (l *Logger) Fatalln(v ...interface{}) {
+	l.log(slog.LevelError, sprintln(v...))
+	os.Exit(1)
+}
+
+// Panic logs a message at error level and then panics
+func (l *Logger) Panic(v ...interface{}) {
+	msg := fmt.Sprint(v...)
+	l.log(slog.LevelError, msg)
+	panic(msg)
+}
+
+// Panicf logs a formatted message at error level and then panics
+func (l *Logger) Panicf(format string, v ...interface{}) {
+	msg := fmt.Sprintf(format, v...)
+	l.log(slog.LevelError, msg)
+	panic(msg)
+}
+
+// Panicln logs a message at error level and then panics
+func (l *Logger) Panicln(v ...interface{}) {
+	msg := sprintln(v...)
+	l.log(slog.LevelError, msg)
+	panic(msg)
+}
+
+// Print logs a message at info level
+func (l *Logger) Print(v ...interface{}) { l.log(slog.LevelInfo, fmt.Sprint(v...)) }
+
+// Printf logs a formatted message at info level
+func (l *Logger) Printf(format string, v ...interface{}) {
+	l.log(slog.LevelInfo, fmt.Sprintf(format, v...))
+}
+
+// Println logs a message at info level
+func (l *Logger) Println(v ...interface{}) { l.log(slog.LevelInfo, sprintln(v...)) }
+
+// Debug logs a message at debug level
+func (l *Logger) Debug(args ...interface{}) { l.log(slog.LevelDebug, fmt.Sprint(args...)) }
+
+// Debugf logs a formatted message at debug level
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.log(slog.LevelDebug, fmt.Sprintf(format, args...))
+}
+
+// Debugln logs a message at debug level
+func (l *Logger) Debugln(args ...interface{}) { l.log(slog.LevelDebug, sprintln(args...)) }
+
+// Info logs a message at info level
+func (l *Logger) Info(args ...interface{}) { l.log(slog.LevelInfo, fmt.Sprint(args...)) }
+
+// Infof logs a formatted message at info level
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.log(slog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+// Infoln logs a message at info level
+func (l *Logger) Infoln(args ...interface{}) { l.log(slog.LevelInfo, sprintln(args...)) }
+
+// Warn logs a message at warn level
+func (l *Logger) Warn(args ...interface{}) { l.log(slog.LevelWarn, fmt.Sprint(args...)) }
+
+// Warnf logs a formatted message at warn level
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.log(slog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+// Warnln logs a message at warn level
+func (l *Logger) Warnln(args ...interface{}) { l.log(slog.LevelWarn, sprintln(args...)) }
+
+// Error logs a message at error level
+func (l *Logger) Error(args ...interface{}) { l.log(slog.LevelError, fmt.Sprint(args...)) }
+
+// Errorf logs a formatted message at error level
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.log(slog.LevelError, fmt.Sprintf(format, args...))
+}
+
+// Errorln logs a message at error level
+func (l *Logger) Errorln(args ...interface{}) { l.log(slog.LevelError, sprintln(args...)) }
+
+// log writes the message to the handler of the slog logger with the source of the SDK caller
+func (l *Logger) log(level slog.Level, msg string) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(callerSkip, pcs[:])
+	record := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	if err := l.logger.Handler().Handle(ctx, record); err != nil {
+		fmt.Fprintf(os.Stderr, "slog handler failed: %s\n", err)
+	}
+}
+
+// sprintln formats the arguments in the manner of fmt.Println, without the trailing newline
+func sprintln(args ...interface{}) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
@@ -0,0 +1,47 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package slogadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	provider := NewProvider(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{AddSource: true})))
+
+	logger := provider.GetLogger("fabsdk/client")
+	logger.Debugf("debug isn't enabled")
+	assert.Empty(t, buf.String(), "debug messages aren't expected at info level")
+
+	logger.(api.FieldLogger).WithFields(api.Field{Key: "channel", Value: "mychannel"}).Warnf("brown %s jumps over the lazy %s", "fox", "dog")
+
+	entry := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "brown fox jumps over the lazy dog", entry["msg"])
+	assert.Equal(t, "fabsdk/client", entry["module"])
+	assert.Equal(t, "mychannel", entry["channel"])
+}
+
+func TestLoggerPanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewProvider(slog.New(slog.NewTextHandler(&buf, nil))).GetLogger("fabsdk/client")
+
+	assert.PanicsWithValue(t, "brown fox", func() { logger.Panicln("brown", "fox") })
+	assert.Contains(t, buf.String(), "level=ERROR")
+}
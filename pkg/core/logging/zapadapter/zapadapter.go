@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package zapadapter provides a logger provider that writes the logs of the SDK to a zap logger.
+//
+//  Usage:
+//  logging.Initialize(zapadapter.NewProvider(zapLogger))
+package zapadapter
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"go.uber.org/zap"
+)
+
+// moduleKey is the key of the field that holds the name of the SDK module
+const moduleKey = "module"
+
+// callerSkip skips the frames of the SDK logger and of this adapter when zap reports the caller
+const callerSkip = 2
+
+// Provider creates loggers that write to a zap logger
+type Provider struct {
+	logger *zap.Logger
+}
+
+// NewProvider returns a logger provider that writes to the given zap logger. Log levels are
+// controlled by the zap logger.
+func NewProvider(logger *zap.Logger) *Provider {
+	return &Provider{logger: logger.WithOptions(zap.AddCallerSkip(callerSkip))}
+}
+
+// GetLogger returns a logger that adds the name of the given module to each log message
+func (p *Provider) GetLogger(module string) api.Logger {
+	return &Logger{logger: p.logger.Sugar().With(moduleKey, module)}
+}
+
+// Logger writes the logs of an SDK module to a zap logger
+type Logger struct {
+	logger *zap.SugaredLogger
+}
+
+// WithFields returns a logger that adds the given fields to each log message
+func (l *Logger) WithFields(fields ...api.Field) api.Logger {
+	args := make([]interface{}, 0, 2*len(fields))
+	for _, field := range fields {
+		args = append(args, field.Key, field.Value)
+	}
+	return &Logger{logger: l.logger.With(args...)}
+}
+
+// Fatal logs a message at fatal level and then calls os.Exit(1)
+func (l *Logger) Fatal(v ...interface{}) { l.logger.Fatal(v...) }
+
+// Fatalf logs a formatted message at fatal level and then calls os.Exit(1)
+func (l *Logger) Fatalf(format string, v ...interface{}) { l.logger.Fatalf(format, v...) }
+
+// Fatalln logs a message at fatal level and then calls os.Exit(1)
+func (l *Logger) Fatalln(v ...interface{}) { l.logger.Fatal(sprintln(v...)) }
+
+// Panic logs a message at panic level and then panics
+func (l *Logger) Panic(v ...interface{}) { l.logger.Panic(v...) }
+
+// Panicf logs a formatted message at panic level and then panics
+func (l *Logger) Panicf(format string, v ...interface{}) { l.logger.Panicf(format, v...) }
+
+// Panicln logs a message at panic level and then panics
+func (l *Logger) Panicln(v ...interface{}) { l.logger.Panic(sprintln(v...)) }
+
+// Print logs a message at info level
+func (l *Logger) Print(v ...interface{}) { l.logger.Info(v...) }
+
+// Printf logs a formatted message at info level
+func (l *Logger) Printf(format string, v ...interface{}) { l.logger.Infof(format, v...) }
+
+// Println logs a message at info level
+func (l *Logger) Println(v ...interface{}) { l.logger.Info(sprintln(v...)) }
+
+// Debug logs a message at debug level
+func (l *Logger) Debug(args ...interface{}) { l.logger.Debug(args...) }
+
+// Debugf logs a formatted message at debug level
+func (l *Logger) Debugf(format string, args ...interface{}) { l.logger.Debugf(format, args...) }
+
+// Debugln logs a message at debug level
+func (l *Logger) Debugln(args ...interface{}) { l.logger.Debug(sprintln(args...)) }
+
+// Info logs a message at info level
+func (l *Logger) Info(args ...interface{}) { l.logger.Info(args...) }
+
+// Infof logs a formatted message at info level
+func (l *Logger) Infof(format string, args ...interface{}) { l.logger.Infof(format, args...) }
+
+// Infoln logs a message at info level
+func (l *Logger) Infoln(args ...interface{}) { l.logger.Info(sprintln(args...)) }
+
+// Warn logs a message at warn level
+func (l *Logger) Warn(args ...interface{}) { l.logger.Warn(args...) }
+
+// Warnf logs a formatted message at warn level
+func (l *Logger) Warnf(format string, args ...interface{}) { l.logger.Warnf(format, args...) }
+
+// Warnln logs a message at warn level
+func (l *Logger) Warnln(args ...interface{}) { l.logger.Warn(sprintln(args...)) }
+
+// Error logs a message at error level
+func (l *Logger) Error(args ...interface{}) { l.logger.Error(args...) }
+
+// Errorf logs a formatted message at error level
+func (l *Logger) Errorf(format string, args ...interface{}) { l.logger.Errorf(format, args...) }
+
+// Errorln logs a message at error level
+func (l *Logger) Errorln(args ...interface{}) { l.logger.Error(sprintln(args...)) }
+
+// sprintln formats the arguments in the manner of fmt.Println, without the trailing newline
+func sprintln(args ...interface{}) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
@@ -0,0 +1,38 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package zapadapter
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	provider := NewProvider(zap.New(core))
+
+	logger := provider.GetLogger("fabsdk/client")
+	logger.Debugf("debug isn't enabled")
+	assert.Equal(t, 0, logs.Len(), "debug messages aren't expected at info level")
+
+	logger.(api.FieldLogger).WithFields(api.Field{Key: "channel", Value: "mychannel"}).Warnf("brown %s jumps over the lazy %s", "fox", "dog")
+	logger.Infoln("brown", "fox")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, "brown fox jumps over the lazy dog", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{"module": "fabsdk/client", "channel": "mychannel"}, entries[0].ContextMap())
+	assert.Equal(t, "brown fox", entries[1].Message)
+	assert.Equal(t, map[string]interface{}{"module": "fabsdk/client"}, entries[1].ContextMap())
+}
@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package mocks
 
 import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 )
 
 // MockCoreContext is a mock core context
@@ -18,6 +20,7 @@ type MockCoreContext struct {
 	MockUserStore         msp.UserStore
 	MockSigningManager    core.SigningManager
 	MockCryptoSuiteConfig core.CryptoSuiteConfig
+	MockReadOnly          bool
 }
 
 // CryptoSuite ...
@@ -30,6 +33,21 @@ func (m *MockCoreContext) SigningManager() core.SigningManager {
 	return m.MockSigningManager
 }
 
+// ReadOnly ...
+func (m *MockCoreContext) ReadOnly() bool {
+	return m.MockReadOnly
+}
+
+// Clock ...
+func (m *MockCoreContext) Clock() clock.Clock {
+	return clock.Real
+}
+
+// RandomSource ...
+func (m *MockCoreContext) RandomSource() *random.Source {
+	return random.Default()
+}
+
 //CryptoSuiteConfig ...
 func (m *MockCoreContext) CryptoSuiteConfig() core.CryptoSuiteConfig {
 	return m.MockCryptoSuiteConfig
@@ -25,8 +25,9 @@ import (
 var logger = logging.NewLogger("fabsdk/fab")
 
 const (
-	lscc           = "lscc"
-	lsccChaincodes = "getchaincodes"
+	lscc                  = "lscc"
+	lsccChaincodes        = "getchaincodes"
+	lsccCollectionsConfig = "GetCollectionsConfig"
 )
 
 // Ledger is a client that provides access to the underlying ledger of a channel.
@@ -207,6 +208,33 @@ func createChaincodeQueryResponse(tpr *fab.TransactionProposalResponse) (*pb.Cha
 	return &response, nil
 }
 
+// QueryCollectionsConfig queries the collections config for a chaincode on this channel.
+// This query will be made to specified targets.
+func (c *Ledger) QueryCollectionsConfig(reqCtx reqContext.Context, chaincodeName string, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*common.CollectionConfigPackage, error) {
+	cir := createCollectionsConfigInvokeRequest(chaincodeName)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+
+	responses := []*common.CollectionConfigPackage{}
+	for _, tpr := range tprs {
+		r, err := createCollectionConfigPackage(tpr)
+		if err != nil {
+			errs = multi.Append(errs, errors.WithMessage(err, "From target: "+tpr.Endorser))
+		} else {
+			responses = append(responses, r)
+		}
+	}
+	return responses, errs
+}
+
+func createCollectionConfigPackage(tpr *fab.TransactionProposalResponse) (*common.CollectionConfigPackage, error) {
+	response := common.CollectionConfigPackage{}
+	err := proto.Unmarshal(tpr.ProposalResponse.GetResponse().Payload, &response)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal of collection config package failed")
+	}
+	return &response, nil
+}
+
 // QueryConfigBlock returns the current configuration block for the specified channel. If the
 // peer doesn't belong to the channel, return error
 func (c *Ledger) QueryConfigBlock(reqCtx reqContext.Context, targets []fab.ProposalProcessor, verifier ResponseVerifier) (*common.Block, error) {
@@ -273,3 +301,12 @@ func createChaincodeInvokeRequest() fab.ChaincodeInvokeRequest {
 	}
 	return cir
 }
+
+func createCollectionsConfigInvokeRequest(chaincodeName string) fab.ChaincodeInvokeRequest {
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lscc,
+		Fcn:         lsccCollectionsConfig,
+		Args:        [][]byte{[]byte(chaincodeName)},
+	}
+	return cir
+}
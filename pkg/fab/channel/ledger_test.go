@@ -99,6 +99,33 @@ func TestQueryInstantiatedChaincodes(t *testing.T) {
 
 }
 
+func TestQueryCollectionsConfig(t *testing.T) {
+	channel, _ := setupTestLedger()
+
+	collConfig := &common.CollectionConfigPackage{
+		Config: []*common.CollectionConfig{
+			{
+				Payload: &common.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &common.StaticCollectionConfig{Name: "coll1", RequiredPeerCount: 1, MaximumPeerCount: 2},
+				},
+			},
+		},
+	}
+	payload, err := proto.Marshal(collConfig)
+	assert.Nil(t, err)
+
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Payload: payload, Status: 200}
+
+	reqCtx, cancel := context.NewRequest(setupContext(), context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	res, err := channel.QueryCollectionsConfig(reqCtx, "examplecc", []fab.ProposalProcessor{&peer}, nil)
+	if err != nil || len(res) != 1 {
+		t.Fatalf("Test QueryCollectionsConfig failed: %v", err)
+	}
+	assert.Equal(t, "coll1", res[0].Config[0].GetStaticCollectionConfig().Name)
+}
+
 func TestQueryTransaction(t *testing.T) {
 	channel, _ := setupTestLedger()
 	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 
@@ -60,8 +62,16 @@ func orderersFromChannelCfg(ctx context.Client, cfg fab.ChannelCfg) ([]fab.Order
 		return nil, err
 	}
 
+	// Orderers returned by the Discovery service are only used if no static config exists for an orderer
+	discovered := newDiscoveredOrderers(ctx)
+
+	targets := cfg.Orderers()
+	if len(targets) == 0 {
+		targets = discovered.targets()
+	}
+
 	// Add orderer if specified in config
-	for _, target := range cfg.Orderers() {
+	for _, target := range targets {
 
 		// Figure out orderer configuration
 		oCfg, ok := ordererDict[target]
@@ -77,6 +87,12 @@ func orderersFromChannelCfg(ctx context.Client, cfg fab.ChannelCfg) ([]fab.Order
 			}
 
 		}
+		if !ok {
+			oCfg, ok = discovered.get(target)
+			if ok {
+				logger.Debugf("Found ordererConfig from discovery for channel Cfg Orderer [%s]", target)
+			}
+		}
 		if !ok {
 			logger.Debugf("Unable to find matching ordererConfig from entity Matchers for channel Cfg Orderer [%s]", target)
 			oCfg = fab.OrdererConfig{
@@ -109,6 +125,59 @@ func orderersByTarget(ctx context.Client) (map[string]fab.OrdererConfig, error)
 	return ordererDict, nil
 }
 
+// discoveredOrderers lazily retrieves the orderers of a channel from the channel's
+// discovery service (if the discovery service supports orderer discovery)
+type discoveredOrderers struct {
+	ctx      context.Client
+	orderers map[string]fab.OrdererConfig
+	urls     []string
+	loaded   bool
+}
+
+func newDiscoveredOrderers(ctx context.Client) *discoveredOrderers {
+	return &discoveredOrderers{ctx: ctx}
+}
+
+func (d *discoveredOrderers) get(target string) (fab.OrdererConfig, bool) {
+	d.load()
+	oCfg, ok := d.orderers[endpoint.ToAddress(target)]
+	return oCfg, ok
+}
+
+func (d *discoveredOrderers) targets() []string {
+	d.load()
+	return d.urls
+}
+
+func (d *discoveredOrderers) load() {
+	if d.loaded {
+		return
+	}
+	d.loaded = true
+
+	chCtx, ok := d.ctx.(context.Channel)
+	if !ok {
+		return
+	}
+	ordererDiscovery, ok := chCtx.DiscoveryService().(fab.OrdererDiscoveryService)
+	if !ok {
+		return
+	}
+
+	orderers, err := ordererDiscovery.GetOrderers()
+	if err != nil {
+		logger.With(logging.Channel(chCtx.ChannelID())).Warnf("Unable to discover orderers of channel: %s", err)
+		return
+	}
+
+	d.orderers = make(map[string]fab.OrdererConfig)
+	for _, oc := range orderers {
+		address := endpoint.ToAddress(oc.URL)
+		d.orderers[address] = oc
+		d.urls = append(d.urls, address)
+	}
+}
+
 // CreateTransactionHeader creates a Transaction Header based on the current context.
 func (t *Transactor) CreateTransactionHeader() (fab.TransactionHeader, error) {
 
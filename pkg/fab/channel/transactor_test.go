@@ -13,6 +13,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
@@ -59,6 +60,51 @@ func TestTransactionBadStatus(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestDiscoveredOrderers(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "test")
+	ctx := mocks.NewMockContext(user)
+	chCtx := mocks.NewMockChannelContext(ctx, "testChannel")
+	chCtx.Discovery = &mockOrdererDiscovery{
+		orderers: []fab.OrdererConfig{
+			{
+				URL:         "orderer.example.com:7050",
+				GRPCOptions: map[string]interface{}{"ssl-target-name-override": "orderer.example.com"},
+				TLSCACerts:  endpoint.TLSConfig{Pem: "tlsrootcert"},
+			},
+		},
+	}
+
+	discovered := newDiscoveredOrderers(chCtx)
+	oCfg, ok := discovered.get("grpcs://orderer.example.com:7050")
+	assert.True(t, ok)
+	assert.Equal(t, "tlsrootcert", oCfg.TLSCACerts.Pem)
+	assert.Equal(t, []string{"orderer.example.com:7050"}, discovered.targets())
+
+	_, ok = discovered.get("orderer2.example.com:7050")
+	assert.False(t, ok)
+
+	// Discovered orderers are used if the channel config has no orderers
+	orderers, err := orderersFromChannelCfg(chCtx, mocks.NewMockChannelCfg("testChannel"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(orderers))
+
+	// Orderers aren't discovered without a channel context
+	_, ok = newDiscoveredOrderers(ctx).get("orderer.example.com:7050")
+	assert.False(t, ok)
+}
+
+type mockOrdererDiscovery struct {
+	orderers []fab.OrdererConfig
+}
+
+func (m *mockOrdererDiscovery) GetPeers() ([]fab.Peer, error) {
+	return nil, nil
+}
+
+func (m *mockOrdererDiscovery) GetOrderers() ([]fab.OrdererConfig, error) {
+	return m.orderers, nil
+}
+
 func createTransactor(t *testing.T) *Transactor {
 	user := mspmocks.NewMockSigningIdentity("test", "test")
 	ctx := mocks.NewMockContext(user)
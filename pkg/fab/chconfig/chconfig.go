@@ -8,7 +8,6 @@ package chconfig
 
 import (
 	reqContext "context"
-	"math/rand"
 
 	"github.com/golang/protobuf/proto"
 
@@ -19,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
@@ -191,7 +191,7 @@ func (c *ChannelConfig) calculateTargetsFromConfig(ctx context.Client) ([]fab.Pr
 		targets = append(targets, newPeer)
 	}
 
-	targets = randomMaxTargets(targets, c.opts.MaxTargets)
+	targets = randomMaxTargets(ctx.RandomSource(), targets, c.opts.MaxTargets)
 	return targets, nil
 }
 
@@ -636,12 +636,12 @@ func peersToTxnProcessors(peers []fab.Peer) []fab.ProposalProcessor {
 }
 
 //randomMaxTargets returns random sub set of max length targets
-func randomMaxTargets(targets []fab.ProposalProcessor, max int) []fab.ProposalProcessor {
+func randomMaxTargets(source *random.Source, targets []fab.ProposalProcessor, max int) []fab.ProposalProcessor {
 	if len(targets) <= max {
 		return targets
 	}
 	for i := range targets {
-		j := rand.Intn(i + 1)
+		j := source.Intn(i + 1)
 		targets[i], targets[j] = targets[j], targets[i]
 	}
 	return targets[:max]
@@ -14,6 +14,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
@@ -152,7 +153,7 @@ func TestRandomMaxTargetsSelections(t *testing.T) {
 		before = before + v.(*mockProposalProcessor).name
 	}
 
-	responseTargets := randomMaxTargets(testTargets, max)
+	responseTargets := randomMaxTargets(random.Default(), testTargets, max)
 	assert.True(t, responseTargets != nil && len(responseTargets) == max, "response target not as expected")
 
 	after := ""
@@ -163,11 +164,11 @@ func TestRandomMaxTargetsSelections(t *testing.T) {
 	assert.False(t, before == after, "response targets are not random")
 
 	max = 0 //when zero minimum supplied, result should be empty
-	responseTargets = randomMaxTargets(testTargets, max)
+	responseTargets = randomMaxTargets(random.Default(), testTargets, max)
 	assert.True(t, responseTargets != nil && len(responseTargets) == max, "response target not as expected")
 
 	max = 12 //greater than targets length
-	responseTargets = randomMaxTargets(testTargets, max)
+	responseTargets = randomMaxTargets(random.Default(), testTargets, max)
 	assert.True(t, responseTargets != nil && len(responseTargets) == len(testTargets), "response target not as expected")
 
 }
@@ -0,0 +1,266 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chconfig
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ob "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const applicationGroupKey = "Application"
+
+// Config is a decoded channel configuration
+type Config struct {
+	ChannelID   string
+	BlockNumber uint64
+	// Sequence is the sequence number of the configuration
+	Sequence     uint64
+	Capabilities []string
+	// OrdererAddresses contains the channel-wide orderer addresses
+	OrdererAddresses []string
+	Application      *ApplicationConfig
+	Orderer          *OrdererConfig
+}
+
+// ApplicationConfig contains the application section of the channel configuration
+type ApplicationConfig struct {
+	Capabilities  []string
+	Organizations map[string]*Organization
+}
+
+// OrdererConfig contains the orderer section of the channel configuration
+type OrdererConfig struct {
+	Capabilities  []string
+	ConsensusType string
+	BatchSize     BatchSize
+	BatchTimeout  time.Duration
+	Organizations map[string]*Organization
+}
+
+// BatchSize contains the batch size settings of the orderer
+type BatchSize struct {
+	MaxMessageCount   uint32
+	AbsoluteMaxBytes  uint32
+	PreferredMaxBytes uint32
+}
+
+// Organization contains the configuration of an organization
+type Organization struct {
+	// Name is the name of the organization's config group
+	Name        string
+	MSPID       string
+	MSP         *mb.FabricMSPConfig
+	AnchorPeers []*pb.AnchorPeer
+}
+
+// DecodeConfigBlock decodes the channel configuration contained in the given config block
+func DecodeConfigBlock(block *common.Block) (*Config, error) {
+	if block == nil || block.Header == nil {
+		return nil, errors.New("expected header in block")
+	}
+	if block.Data == nil || len(block.Data.Data) == 0 {
+		return nil, errors.New("expected data in block")
+	}
+
+	configEnvelope, err := resource.CreateConfigEnvelope(block.Data.Data[0])
+	if err != nil {
+		return nil, err
+	}
+	if configEnvelope.Config == nil || configEnvelope.Config.ChannelGroup == nil {
+		return nil, errors.New("channel group is missing from config")
+	}
+
+	channelID, err := channelIDFromEnvelope(block.Data.Data[0])
+	if err != nil {
+		return nil, err
+	}
+
+	group := configEnvelope.Config.ChannelGroup
+
+	config := &Config{
+		ChannelID:   channelID,
+		BlockNumber: block.Header.Number,
+		Sequence:    configEnvelope.Config.Sequence,
+	}
+
+	if config.Capabilities, err = decodeCapabilities(group); err != nil {
+		return nil, err
+	}
+
+	if value, ok := group.Values[channelConfig.OrdererAddressesKey]; ok {
+		addresses := &common.OrdererAddresses{}
+		if err := proto.Unmarshal(value.Value, addresses); err != nil {
+			return nil, errors.Wrap(err, "unmarshal orderer addresses from config failed")
+		}
+		config.OrdererAddresses = addresses.Addresses
+	}
+
+	if appGroup, ok := group.Groups[applicationGroupKey]; ok {
+		if config.Application, err = decodeApplication(appGroup); err != nil {
+			return nil, err
+		}
+	}
+
+	if ordererGroup, ok := group.Groups[channelConfig.OrdererGroupKey]; ok {
+		if config.Orderer, err = decodeOrderer(ordererGroup); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+func channelIDFromEnvelope(data []byte) (string, error) {
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return "", errors.Wrap(err, "unmarshal envelope from config block failed")
+	}
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return "", errors.Wrap(err, "unmarshal payload from envelope failed")
+	}
+	if payload.Header == nil {
+		return "", errors.New("payload header is missing from config envelope")
+	}
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, channelHeader); err != nil {
+		return "", errors.Wrap(err, "unmarshal channel header from payload failed")
+	}
+	return channelHeader.ChannelId, nil
+}
+
+func decodeApplication(group *common.ConfigGroup) (*ApplicationConfig, error) {
+	capabilities, err := decodeCapabilities(group)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs, err := decodeOrganizations(group)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ApplicationConfig{Capabilities: capabilities, Organizations: orgs}, nil
+}
+
+func decodeOrderer(group *common.ConfigGroup) (*OrdererConfig, error) {
+	capabilities, err := decodeCapabilities(group)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs, err := decodeOrganizations(group)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &OrdererConfig{Capabilities: capabilities, Organizations: orgs}
+
+	if value, ok := group.Values[channelConfig.ConsensusTypeKey]; ok {
+		consensusType := &ob.ConsensusType{}
+		if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+			return nil, errors.Wrap(err, "unmarshal ConsensusType from config failed")
+		}
+		config.ConsensusType = consensusType.Type
+	}
+
+	if value, ok := group.Values[channelConfig.BatchSizeKey]; ok {
+		batchSize := &ob.BatchSize{}
+		if err := proto.Unmarshal(value.Value, batchSize); err != nil {
+			return nil, errors.Wrap(err, "unmarshal batch size from config failed")
+		}
+		config.BatchSize = BatchSize{
+			MaxMessageCount:   batchSize.MaxMessageCount,
+			AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
+			PreferredMaxBytes: batchSize.PreferredMaxBytes,
+		}
+	}
+
+	if value, ok := group.Values[channelConfig.BatchTimeoutKey]; ok {
+		batchTimeout := &ob.BatchTimeout{}
+		if err := proto.Unmarshal(value.Value, batchTimeout); err != nil {
+			return nil, errors.Wrap(err, "unmarshal batch timeout from config failed")
+		}
+		timeout, err := time.ParseDuration(batchTimeout.Timeout)
+		if err != nil {
+			logger.Warnf("invalid batch timeout [%s] in config: %s", batchTimeout.Timeout, err)
+		}
+		config.BatchTimeout = timeout
+	}
+
+	return config, nil
+}
+
+func decodeOrganizations(group *common.ConfigGroup) (map[string]*Organization, error) {
+	orgs := make(map[string]*Organization)
+	for name, orgGroup := range group.Groups {
+		org, err := decodeOrganization(name, orgGroup)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode organization "+name)
+		}
+		orgs[org.MSPID] = org
+	}
+	return orgs, nil
+}
+
+func decodeOrganization(name string, group *common.ConfigGroup) (*Organization, error) {
+	org := &Organization{Name: name}
+
+	if value, ok := group.Values[channelConfig.MSPKey]; ok {
+		mspConfig := &mb.MSPConfig{}
+		if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+			return nil, errors.Wrap(err, "unmarshal MSPConfig from config failed")
+		}
+		fabricMSPConfig := &mb.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+			return nil, errors.Wrap(err, "unmarshal FabricMSPConfig from config failed")
+		}
+		org.MSP = fabricMSPConfig
+		org.MSPID = fabricMSPConfig.Name
+	}
+	if org.MSPID == "" {
+		org.MSPID = name
+	}
+
+	if value, ok := group.Values[channelConfig.AnchorPeersKey]; ok {
+		anchorPeers := &pb.AnchorPeers{}
+		if err := proto.Unmarshal(value.Value, anchorPeers); err != nil {
+			return nil, errors.Wrap(err, "unmarshal anchor peers from config failed")
+		}
+		org.AnchorPeers = anchorPeers.AnchorPeers
+	}
+
+	return org, nil
+}
+
+func decodeCapabilities(group *common.ConfigGroup) ([]string, error) {
+	value, ok := group.Values[channelConfig.CapabilitiesKey]
+	if !ok {
+		return nil, nil
+	}
+
+	capabilities := &common.Capabilities{}
+	if err := proto.Unmarshal(value.Value, capabilities); err != nil {
+		return nil, errors.Wrap(err, "unmarshal capabilities from config failed")
+	}
+
+	var names []string
+	for name := range capabilities.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
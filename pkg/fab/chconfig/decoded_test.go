@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chconfig
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeConfigBlock(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP", "Org2MSP"},
+			OrdererAddress: "localhost:7050",
+			RootCA:         validRootCA,
+		},
+		Index: 3,
+	}
+
+	config, err := DecodeConfigBlock(builder.Build())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(3), config.BlockNumber)
+	assert.Equal(t, []string{"localhost:7050"}, config.OrdererAddresses)
+
+	require.NotNil(t, config.Application)
+	require.Equal(t, 2, len(config.Application.Organizations))
+	org1 := config.Application.Organizations["Org1MSP"]
+	require.NotNil(t, org1)
+	assert.Equal(t, "Org1MSP", org1.Name)
+	require.NotNil(t, org1.MSP)
+	assert.Equal(t, [][]byte{[]byte(validRootCA)}, org1.MSP.RootCerts)
+
+	require.NotNil(t, config.Orderer)
+	assert.Equal(t, "sample-Consensus-Type", config.Orderer.ConsensusType)
+	assert.Equal(t, uint32(10), config.Orderer.BatchSize.MaxMessageCount)
+	assert.Equal(t, uint32(524288), config.Orderer.BatchSize.PreferredMaxBytes)
+	require.NotNil(t, config.Orderer.Organizations["OrdererMSP"])
+}
+
+func TestDecodeConfigBlockInvalid(t *testing.T) {
+	_, err := DecodeConfigBlock(nil)
+	assert.Error(t, err)
+
+	_, err = DecodeConfigBlock(&common.Block{Header: &common.BlockHeader{}, Data: &common.BlockData{}})
+	assert.Error(t, err)
+
+	_, err = DecodeConfigBlock(mocks.NewSimpleMockBlock())
+	assert.Error(t, err)
+}
@@ -39,6 +39,11 @@ func NewRef(refresh time.Duration, pvdr Provider, channel string, ctx fab.Client
 	return cfgRef
 }
 
+// ChannelID returns the ID of the channel
+func (ref *Ref) ChannelID() string {
+	return ref.channelID
+}
+
 func (ref *Ref) initializer() lazyref.Initializer {
 	return func() (interface{}, error) {
 		chConfigProvider, err := ref.pvdr(ref.channelID)
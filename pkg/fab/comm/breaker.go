@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed indicates that requests flow through to the endpoint
+	BreakerClosed BreakerState = iota
+	// BreakerOpen indicates that the endpoint is considered down and requests are rejected
+	BreakerOpen
+	// BreakerHalfOpen indicates that a single trial request is allowed through
+	// in order to determine whether the endpoint has recovered
+	BreakerHalfOpen
+)
+
+// String returns the name of the state
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker tracks the failures of a single endpoint. The breaker opens after
+// "failureThreshold" consecutive failures, after which requests are rejected. Once
+// "resetTimeout" has elapsed the breaker becomes half-open and allows a single trial
+// request through. A success closes the breaker and a failure opens it again.
+//
+// This component has been designed to be safe for concurrency.
+type CircuitBreaker struct {
+	lock             sync.Mutex
+	state            BreakerState
+	failures         int
+	failureThreshold int
+	resetTimeout     time.Duration
+	openedAt         time.Time
+	trialPending     bool
+}
+
+// NewCircuitBreaker returns a new circuit breaker in the closed state
+func NewCircuitBreaker(failureThreshold int, resetTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		resetTimeout:     resetTimeout,
+	}
+}
+
+// Allow returns true if a request may be sent to the endpoint
+func (b *CircuitBreaker) Allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.currentState() {
+	case BreakerClosed:
+		return true
+	case BreakerHalfOpen:
+		if b.trialPending {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.trialPending = true
+		return true
+	default:
+		return false
+	}
+}
+
+// Success records a successful request and closes the breaker
+func (b *CircuitBreaker) Success() {
+	b.success()
+}
+
+// Failure records a failed request. The breaker is opened if the failure
+// threshold is reached or if the failure occurred while half-open.
+func (b *CircuitBreaker) Failure() {
+	b.failure()
+}
+
+// success closes the breaker and returns true if it wasn't closed
+func (b *CircuitBreaker) success() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	recovered := b.currentState() != BreakerClosed
+	b.state = BreakerClosed
+	b.failures = 0
+	b.trialPending = false
+	return recovered
+}
+
+// failure records a failure and returns true if the breaker was closed and has opened
+func (b *CircuitBreaker) failure() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	previous := b.currentState()
+	b.failures++
+	if previous == BreakerHalfOpen || b.failures >= b.failureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+	b.trialPending = false
+	return previous == BreakerClosed && b.state == BreakerOpen
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() BreakerState {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.currentState()
+}
+
+func (b *CircuitBreaker) currentState() BreakerState {
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.resetTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
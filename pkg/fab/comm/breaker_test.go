@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	breaker := NewCircuitBreaker(2, 100*time.Millisecond)
+	assert.Equal(t, BreakerClosed, breaker.State())
+	assert.True(t, breaker.Allow(), "closed breaker should allow requests")
+
+	breaker.Failure()
+	assert.Equal(t, BreakerClosed, breaker.State(), "breaker should not open before the threshold is reached")
+
+	breaker.Failure()
+	assert.Equal(t, BreakerOpen, breaker.State(), "breaker should open once the threshold is reached")
+	assert.False(t, breaker.Allow(), "open breaker should reject requests")
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, BreakerHalfOpen, breaker.State(), "breaker should be half-open after the reset timeout")
+	assert.True(t, breaker.Allow(), "half-open breaker should allow a trial request")
+	assert.False(t, breaker.Allow(), "half-open breaker should allow only one trial request")
+
+	breaker.Failure()
+	assert.Equal(t, BreakerOpen, breaker.State(), "failed trial should open the breaker")
+
+	time.Sleep(150 * time.Millisecond)
+	assert.True(t, breaker.Allow(), "half-open breaker should allow a trial request")
+	breaker.Success()
+	assert.Equal(t, BreakerClosed, breaker.State(), "successful trial should close the breaker")
+	assert.True(t, breaker.Allow())
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Minute)
+
+	breaker.Failure()
+	breaker.Success()
+	breaker.Failure()
+	assert.Equal(t, BreakerClosed, breaker.State(), "failures should be consecutive to open the breaker")
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -25,12 +26,7 @@ import (
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
-
-const (
-	// GRPC max message size (same as Fabric)
-	maxCallRecvMsgSize = 100 * 1024 * 1024
-	maxCallSendMsgSize = 100 * 1024 * 1024
-)
+var tracer = tracing.NewTracer("fabsdk/fab")
 
 // GRPCConnection manages the GRPC connection and client stream
 type GRPCConnection struct {
@@ -55,7 +51,7 @@ func NewConnection(ctx fabcontext.Client, url string, opts ...options.Opt) (*GRP
 		return nil, err
 	}
 
-	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(params.connectTimeout))
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(params.connectTimeout), context.WithParent(params.parentContext))
 	defer cancel()
 
 	commManager, ok := context.RequestCommManager(reqCtx)
@@ -116,17 +112,34 @@ func (c *GRPCConnection) Context() fabcontext.Client {
 func newDialOpts(config fab.EndpointConfig, url string, params *params) ([]grpc.DialOption, error) {
 	var dialOpts []grpc.DialOption
 
-	if params.keepAliveParams.Time > 0 || params.keepAliveParams.Timeout > 0 {
-		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(params.keepAliveParams))
+	// The parameters of the event endpoints apply unless they're set in the GRPC options of the peer
+	connConfig := comm.ConnectionConfig(config, fab.EventEndpoint)
+	kap := params.keepAliveParams
+	if kap.Time == 0 && kap.Timeout == 0 {
+		kap.Time = connConfig.KeepAliveTime
+		kap.Timeout = connConfig.KeepAliveTimeout
+		kap.PermitWithoutStream = kap.PermitWithoutStream || connConfig.KeepAlivePermit
+	}
+	if kap.Time > 0 || kap.Timeout > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(kap))
+	}
+
+	backoffMaxDelay := params.backoffMaxDelay
+	if backoffMaxDelay == 0 {
+		backoffMaxDelay = connConfig.BackoffMaxDelay
+	}
+	if backoffMaxDelay > 0 {
+		dialOpts = append(dialOpts, grpc.WithBackoffMaxDelay(backoffMaxDelay))
 	}
 
 	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.FailFast(params.failFast)))
 
 	if endpoint.AttemptSecured(url, params.insecure) {
-		tlsConfig, err := comm.TLSConfig(params.certificate, params.hostOverride, config)
+		tlsConfig, err := comm.TLSConfig(params.certificate, endpoint.TLSServerName(url, params.hostOverride), config)
 		if err != nil {
 			return nil, err
 		}
+		params.tlsOptions.Apply(tlsConfig)
 		//verify if certificate was expired or not yet valid
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
@@ -139,8 +152,13 @@ func newDialOpts(config fab.EndpointConfig, url string, params *params) ([]grpc.
 		dialOpts = append(dialOpts, grpc.WithInsecure())
 	}
 
-	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
-		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
+	dialOpts = append(dialOpts, comm.MessageSizeDialOpts(params.msgSizes)...)
+
+	compressionOpts, err := comm.CompressionDialOpts(params.compression)
+	if err != nil {
+		return nil, err
+	}
+	dialOpts = append(dialOpts, compressionOpts...)
 
 	return dialOpts, nil
 }
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package comm
 
 import (
+	reqContext "context"
 	"testing"
 	"time"
 
@@ -44,6 +45,16 @@ func TestConnection(t *testing.T) {
 	conn.Close()
 }
 
+func TestConnectionParentContext(t *testing.T) {
+	ctx, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+
+	_, err := NewConnection(newMockContext(), peerURL, WithParentContext(ctx))
+	if err == nil {
+		t.Fatalf("expected error creating new connection with cancelled parent context")
+	}
+}
+
 // Use the Event Hub server for testing
 var testServer *eventmocks.MockEventhubServer
 var endorserAddr []string
@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package comm
 
 import (
+	reqContext "context"
 	"crypto/x509"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/spf13/cast"
 	"google.golang.org/grpc/keepalive"
 )
@@ -24,6 +26,11 @@ type params struct {
 	failFast        bool
 	insecure        bool
 	connectTimeout  time.Duration
+	parentContext   reqContext.Context
+	tlsOptions      *comm.TLSOptions
+	compression     string
+	backoffMaxDelay time.Duration
+	msgSizes        comm.MessageSizes
 }
 
 func defaultParams() *params {
@@ -88,6 +95,53 @@ func WithInsecure() options.Opt {
 	}
 }
 
+// WithParentContext sets the context of the request on whose behalf the connection is made.
+// The connection attempt is abandoned if the parent context is done before the connect timeout.
+func WithParentContext(value reqContext.Context) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(parentContextSetter); ok {
+			setter.SetParentContext(value)
+		}
+	}
+}
+
+// WithTLSOptions sets the TLS versions, cipher suites and curves of the connection,
+// overriding the global TLS options of the configuration
+func WithTLSOptions(value *comm.TLSOptions) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(tlsOptionsSetter); ok {
+			setter.SetTLSOptions(value)
+		}
+	}
+}
+
+// WithBackoffMaxDelay sets the maximum delay between reconnection attempts
+func WithBackoffMaxDelay(value time.Duration) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(backoffMaxDelaySetter); ok {
+			setter.SetBackoffMaxDelay(value)
+		}
+	}
+}
+
+// WithCompression sets the compression of the messages exchanged on the connection ("gzip")
+func WithCompression(value string) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(compressionSetter); ok {
+			setter.SetCompression(value)
+		}
+	}
+}
+
+// WithMessageSizes sets the maximum sizes of the messages received and sent on the connection
+func WithMessageSizes(value comm.MessageSizes) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(messageSizesSetter); ok {
+			setter.SetMessageSizes(value)
+		}
+	}
+}
+
 func (p *params) SetHostOverride(value string) {
 	logger.Debugf("HostOverride: %s", value)
 	p.hostOverride = value
@@ -122,6 +176,30 @@ func (p *params) SetInsecure(value bool) {
 	p.insecure = value
 }
 
+func (p *params) SetParentContext(value reqContext.Context) {
+	p.parentContext = value
+}
+
+func (p *params) SetTLSOptions(value *comm.TLSOptions) {
+	logger.Debugf("TLSOptions: %#v", value)
+	p.tlsOptions = value
+}
+
+func (p *params) SetCompression(value string) {
+	logger.Debugf("Compression: %s", value)
+	p.compression = value
+}
+
+func (p *params) SetBackoffMaxDelay(value time.Duration) {
+	logger.Debugf("BackoffMaxDelay: %s", value)
+	p.backoffMaxDelay = value
+}
+
+func (p *params) SetMessageSizes(value comm.MessageSizes) {
+	logger.Debugf("MessageSizes: %#v", value)
+	p.msgSizes = value
+}
+
 type hostOverrideSetter interface {
 	SetHostOverride(value string)
 }
@@ -146,6 +224,26 @@ type connectTimeoutSetter interface {
 	SetConnectTimeout(value time.Duration)
 }
 
+type parentContextSetter interface {
+	SetParentContext(value reqContext.Context)
+}
+
+type tlsOptionsSetter interface {
+	SetTLSOptions(value *comm.TLSOptions)
+}
+
+type compressionSetter interface {
+	SetCompression(value string)
+}
+
+type backoffMaxDelaySetter interface {
+	SetBackoffMaxDelay(value time.Duration)
+}
+
+type messageSizesSetter interface {
+	SetMessageSizes(value comm.MessageSizes)
+}
+
 // OptsFromPeerConfig returns a set of connection options from the given peer config
 func OptsFromPeerConfig(peerCfg *fab.PeerConfig) ([]options.Opt, error) {
 	certificate, err := peerCfg.TLSCACerts.TLSCert()
@@ -163,6 +261,27 @@ func OptsFromPeerConfig(peerCfg *fab.PeerConfig) ([]options.Opt, error) {
 		WithKeepAliveParams(getKeepAliveOptions(peerCfg)),
 		WithCertificate(certificate),
 	}
+
+	tlsOptions, err := comm.TLSOptionsFromGRPCOptions(peerCfg.GRPCOptions)
+	if err != nil {
+		return nil, err
+	}
+	if tlsOptions != nil {
+		opts = append(opts, WithTLSOptions(tlsOptions))
+	}
+	if compression := comm.CompressionFromGRPCOptions(peerCfg.GRPCOptions); compression != "" {
+		opts = append(opts, WithCompression(compression))
+	}
+	msgSizes, err := comm.MessageSizesFromGRPCOptions(peerCfg.GRPCOptions)
+	if err != nil {
+		return nil, err
+	}
+	if msgSizes != (comm.MessageSizes{}) {
+		opts = append(opts, WithMessageSizes(msgSizes))
+	}
+	if maxDelay, ok := peerCfg.GRPCOptions["backoff-max-delay"]; ok {
+		opts = append(opts, WithBackoffMaxDelay(cast.ToDuration(maxDelay)))
+	}
 	if isInsecureAllowed(peerCfg) {
 		opts = append(opts, WithInsecure())
 	}
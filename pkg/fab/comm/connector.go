@@ -8,9 +8,13 @@ package comm
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
@@ -20,25 +24,124 @@ const (
 	connShutdownTimeout = 50 * time.Millisecond
 )
 
-// CachingConnector provides the ability to cache GRPC connections.
+var (
+	cachedConnections = metrics.NewGauge(metrics.GaugeOpts{
+		Subsystem: "comm",
+		Name:      "cached_connections",
+		Help:      "The number of GRPC connections held by the connection cache.",
+	})
+	connectionDials = metrics.NewCounter(metrics.CounterOpts{
+		Subsystem:  "comm",
+		Name:       "dials_total",
+		Help:       "The number of connection requests made to the connection cache.",
+		LabelNames: []string{"status"},
+	})
+	openStreams = metrics.NewGauge(metrics.GaugeOpts{
+		Subsystem: "comm",
+		Name:      "open_streams",
+		Help:      "The number of connections acquired from the connection pool that haven't been released.",
+	})
+	poolWaits = metrics.NewCounter(metrics.CounterOpts{
+		Subsystem:  "comm",
+		Name:       "pool_waits_total",
+		Help:       "The number of connection requests that had to wait for the connection pool of an endpoint.",
+		LabelNames: []string{"status"},
+	})
+	connectionEvictions = metrics.NewCounter(metrics.CounterOpts{
+		Subsystem:  "comm",
+		Name:       "evictions_total",
+		Help:       "The number of connections removed from the connection pool.",
+		LabelNames: []string{"reason"},
+	})
+)
+
+// CachingConnector provides the ability to pool GRPC connections.
 // It provides a GRPC compatible Context Dialer interface via the "DialContext" method.
-// Connections provided by this component are monitored for becoming idle or entering shutdown state.
-// When connections has its usages closed for longer than "idleTime", the connection is closed and removed
-// from the connection cache. Callers must release connections by calling the "ReleaseConn" method.
-// The Close method will flush all remaining open connections. This component should be considered
-// unusable after calling Close.
+//
+// Each endpoint has a pool of up to "maxConnsPerEndpoint" connections (one by default). Each call
+// to DialContext acquires a stream on a connection of the pool and callers must release it by
+// calling the "ReleaseConn" method. An idle connection is preferred; otherwise a new connection
+// is created if the pool isn't full, or else the least loaded connection is shared. If a limit
+// of streams per connection is set and all of the connections of the pool are at that limit,
+// DialContext waits for a stream to be released until its context is done.
+//
+// Connections are monitored for becoming idle or entering shutdown state. When connections
+// have their usages closed for longer than "idleTime", the connection is closed and evicted
+// from the pool. The Close method will flush all remaining open connections. This component
+// should be considered unusable after calling Close.
+//
+// The registered connection listeners (see AddConnectionListener) are notified when a connection
+// is established, when it's closed (or shut down) and when a connection attempt fails.
+//
+// If a health monitor is provided, connection attempts to endpoints whose circuit breaker
+// is open are rejected immediately and the outcome of each connection attempt is reported
+// to the monitor.
 //
 // This component has been designed to be safe for concurrency.
 type CachingConnector struct {
-	conns         sync.Map
-	sweepTime     time.Duration
-	idleTime      time.Duration
-	index         map[*grpc.ClientConn]*cachedConn
-	lock          sync.Mutex
-	waitgroup     sync.WaitGroup
-	janitorChan   chan *cachedConn
-	janitorDone   chan bool
-	janitorClosed chan bool
+	sweepTime           time.Duration
+	idleTime            time.Duration
+	maxConnsPerEndpoint int
+	maxStreamsPerConn   int
+	pools               map[string]*endpointPool
+	index               map[*grpc.ClientConn]*cachedConn
+	lock                sync.Mutex
+	waitgroup           sync.WaitGroup
+	sweeping            bool
+	closed              bool
+	done                chan struct{}
+	stats               map[string]*endpointStats
+	health              *HealthMonitor
+	srv                 *SRVResolver
+	dialOpts            []grpc.DialOption
+}
+
+// CachingConnectorOpt is a caching connector option
+type CachingConnectorOpt func(cc *CachingConnector)
+
+// WithHealthMonitor sets the health monitor that is consulted before connecting to an endpoint
+func WithHealthMonitor(value *HealthMonitor) CachingConnectorOpt {
+	return func(cc *CachingConnector) {
+		cc.health = value
+	}
+}
+
+// WithSRVResolver sets the resolver of the targets that are names of DNS SRV records (see SRVResolver).
+// Every new connection to such a target is made to the next of the addresses of the records.
+func WithSRVResolver(value *SRVResolver) CachingConnectorOpt {
+	return func(cc *CachingConnector) {
+		cc.srv = value
+	}
+}
+
+// WithDialOptions sets additional GRPC dial options that are applied to every connection
+// created by the connector. These options are applied after the options provided by the caller
+// of DialContext and therefore take precedence.
+func WithDialOptions(opts ...grpc.DialOption) CachingConnectorOpt {
+	return func(cc *CachingConnector) {
+		cc.dialOpts = append(cc.dialOpts, opts...)
+	}
+}
+
+// WithMaxConnectionsPerEndpoint sets the maximum number of connections opened to a single
+// endpoint (1 by default). Additional connections are only opened when none of the existing
+// connections is idle.
+func WithMaxConnectionsPerEndpoint(value int) CachingConnectorOpt {
+	return func(cc *CachingConnector) {
+		if value > 0 {
+			cc.maxConnsPerEndpoint = value
+		}
+	}
+}
+
+// WithMaxStreamsPerConnection sets the maximum number of concurrent users (requests or streams)
+// of a single connection (unlimited by default).
+func WithMaxStreamsPerConnection(value int) CachingConnectorOpt {
+	return func(cc *CachingConnector) {
+		if value > 0 {
+			cc.maxStreamsPerConn = value
+		}
+	}
 }
 
 type cachedConn struct {
@@ -47,336 +150,596 @@ type cachedConn struct {
 	open      int
 	lastOpen  time.Time
 	lastClose time.Time
+	connected bool
+	// stale is true if the connection has been refreshed while in use. Stale connections
+	// are no longer handed out and are closed once they've been released.
+	stale bool
+}
+
+// endpointPool holds the connections to a single endpoint
+type endpointPool struct {
+	conns []*cachedConn
+	// released is closed (and replaced) when a stream is released or a connection is
+	// removed so that the callers waiting for the pool can try again
+	released chan struct{}
+}
+
+func newEndpointPool() *endpointPool {
+	return &endpointPool{released: make(chan struct{})}
+}
+
+// pick selects the connection on which a stream is acquired: an idle connection if there is one,
+// otherwise the least loaded connection with spare streams. create is true if a new connection
+// should be opened instead.
+func (p *endpointPool) pick(maxConns, maxStreams int) (c *cachedConn, create bool) {
+	var best *cachedConn
+	for _, cconn := range p.conns {
+		if maxStreams > 0 && cconn.open >= maxStreams {
+			continue
+		}
+		if cconn.open == 0 {
+			return cconn, false
+		}
+		if best == nil || cconn.open < best.open {
+			best = cconn
+		}
+	}
+	if len(p.conns) < maxConns {
+		return nil, true
+	}
+	return best, false
 }
 
-// NewCachingConnector creates a GRPC connection cache. The cache is governed by
+func (p *endpointPool) remove(c *cachedConn) {
+	for i, cconn := range p.conns {
+		if cconn == c {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			break
+		}
+	}
+	p.signal()
+}
+
+func (p *endpointPool) signal() {
+	close(p.released)
+	p.released = make(chan struct{})
+}
+
+// NewCachingConnector creates a GRPC connection pool. The pool is governed by
 // sweepTime and idleTime.
-func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *CachingConnector {
+func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration, opts ...CachingConnectorOpt) *CachingConnector {
 	cc := CachingConnector{
-		conns:         sync.Map{},
-		index:         map[*grpc.ClientConn]*cachedConn{},
-		janitorChan:   make(chan *cachedConn),
-		janitorDone:   make(chan bool),
-		janitorClosed: make(chan bool, 1),
-		sweepTime:     sweepTime,
-		idleTime:      idleTime,
-	}
-
-	// cc.janitorClosed determines if a goroutine needs to be spun up.
-	// The janitor is able to shut itself down when it has no connection to monitor.
-	// When it shuts itself down, it pushes a value onto janitorClosed. We initialize
-	// the go chan with a bootstrap value so that cachingConnector spins up the
-	// goroutine on first usage.
-	cc.janitorClosed <- true
+		pools:               map[string]*endpointPool{},
+		index:               map[*grpc.ClientConn]*cachedConn{},
+		stats:               map[string]*endpointStats{},
+		done:                make(chan struct{}),
+		sweepTime:           sweepTime,
+		idleTime:            idleTime,
+		maxConnsPerEndpoint: 1,
+	}
+
+	for _, opt := range opts {
+		opt(&cc)
+	}
+
 	return &cc
 }
 
 // Close cleans up cached connections.
 func (cc *CachingConnector) Close() {
+	var closed []string
+	defer func() { notifyDisconnected(closed, nil) }()
+
 	cc.lock.Lock()
-	defer cc.lock.Unlock()
 
 	// Safety check to see if the connector has been closed. This represents a
 	// bug in the calling code, but it's not good to panic here.
-	if cc.janitorDone == nil {
+	if cc.closed {
+		cc.lock.Unlock()
 		logger.Warn("Trying to close connector after already closed")
 		return
 	}
 	logger.Debug("closing caching GRPC connector")
 
-	select {
-	case <-cc.janitorClosed:
-		logger.Debugf("janitor not running")
-	default:
-		logger.Debugf("janitor running")
-		cc.janitorDone <- true
-		cc.waitgroup.Wait()
+	cc.closed = true
+	close(cc.done)
+
+	for conn, cconn := range cc.index {
+		logger.Debugf("closing connection [%s]", cconn.target)
+		closeConn(conn)
+		if cconn.connected {
+			closed = append(closed, cconn.target)
+		}
+		cachedConnections.Add(-1)
+		addStreams(cconn.target, -cconn.open)
+	}
+	for _, pool := range cc.pools {
+		pool.signal()
 	}
+	cc.index = map[*grpc.ClientConn]*cachedConn{}
+	cc.pools = map[string]*endpointPool{}
 
-	close(cc.janitorChan)
-	close(cc.janitorClosed)
-	close(cc.janitorDone)
-	cc.janitorDone = nil
+	cc.lock.Unlock()
+
+	cc.waitgroup.Wait()
 }
 
-// DialContext is a wrapper for grpc.DialContext where connections are cached.
+// DialContext acquires a connection to the target from the pool. A new connection is created
+// (using grpc.DialContext) if necessary.
 func (cc *CachingConnector) DialContext(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	logger.Debugf("DialContext: %s", target)
 
-	c, ok := cc.loadConn(target)
-	if !ok {
-		createdConn, err := cc.createConn(ctx, target, opts...)
-		if err != nil {
+	if cc.health != nil && !cc.health.Allow(target) {
+		err := errors.Errorf("circuit breaker is open for [%s]", target)
+		NotifyError(&ConnectionEvent{Target: target, Err: err})
+		return nil, err
+	}
+
+	if needsDialer(target) {
+		// The addresses of the target are raced (and proxied) by the default dialer. A dialer set in
+		// the dial options of the connector takes precedence and races (and proxies) the addresses itself.
+		opts = append([]grpc.DialOption{DialerDialOpt(TCPDialer)}, opts...)
+	}
+
+	srvTarget := cc.srv != nil && IsSRVAddress(target)
+	if srvTarget {
+		// Look up the records outside of the lock of the connector since the lookup may block
+		if err := cc.srv.Refresh(ctx, target); err != nil {
+			cc.reportFailure(target, err)
+			NotifyError(&ConnectionEvent{Target: target, Err: err})
 			return nil, errors.WithMessage(err, "connection creation failed")
 		}
-		c = createdConn
 	}
 
-	if err := cc.openConn(ctx, c); err != nil {
+	_, span := tracer.Start(ctx, "comm.Dial", tracing.String("target", target))
+	c, err := cc.acquire(ctx, target, opts...)
+	tracing.End(span, err)
+	if err != nil {
+		cc.reportFailure(target, err)
+		NotifyError(&ConnectionEvent{Target: target, Err: err})
+		return nil, errors.WithMessage(err, "connection creation failed")
+	}
+
+	connected, err := cc.openConn(ctx, c)
+	if err != nil {
+		cc.reportFailure(target, err)
+		NotifyError(&ConnectionEvent{Target: target, Err: err})
 		return nil, errors.Errorf("dialing connection timed out [%s]", target)
 	}
+	if connected {
+		NotifyConnect(&ConnectionEvent{Target: target})
+	}
+
+	if cc.health != nil {
+		// SRV targets can't be dialed as is so they aren't probed. Their circuit breakers are
+		// closed again by the requests that are let through once the breaker reset has elapsed.
+		if !srvTarget {
+			cc.health.Register(target, append(opts[:len(opts):len(opts)], cc.dialOpts...)...)
+		}
+		cc.health.Success(target)
+	}
+	cc.recordDial(target, nil)
 	return c.conn, nil
 }
 
-// ReleaseConn notifies the cache that the connection is no longer in use.
-func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
+// acquire reserves a stream on a connection of the pool of the target, waiting for the pool
+// if all of its connections are at their limit
+func (cc *CachingConnector) acquire(ctx context.Context, target string, opts ...grpc.DialOption) (*cachedConn, error) {
+	waited := false
+	for {
+		c, released, err := cc.tryAcquire(ctx, target, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			if waited {
+				poolWaits.With("acquired").Add(1)
+			}
+			return c, nil
+		}
+
+		logger.Debugf("waiting for a connection of the pool [%s]", target)
+		waited = true
+		select {
+		case <-released:
+		case <-ctx.Done():
+			poolWaits.With("timeout").Add(1)
+			return nil, errors.Wrapf(ctx.Err(), "connection pool is exhausted [%s]", target)
+		}
+	}
+}
+
+// tryAcquire reserves a stream on a connection of the pool. If the pool is exhausted then
+// the channel that is closed once the pool changes is returned instead.
+func (cc *CachingConnector) tryAcquire(ctx context.Context, target string, opts ...grpc.DialOption) (*cachedConn, <-chan struct{}, error) {
+	var lost []string
+	defer func() { notifyDisconnected(lost, errors.New("connection was shut down")) }()
+
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
 
-	// Safety check to see if the connector has been closed. This represents a
-	// bug in the calling code, but it's not good to panic here.
-	if cc.janitorDone == nil {
-		logger.Warn("Trying to release connection after connector closed")
+	if cc.closed {
+		return nil, nil, errors.New("caching connector is closed")
+	}
 
-		if conn.GetState() != connectivity.Shutdown {
-			logger.Warn("Connection is not shutdown, trying to close ...")
-			if err := conn.Close(); err != nil {
-				logger.Warnf("conn close failed err %v", err)
+	if pool, ok := cc.pools[target]; ok {
+		for _, cconn := range append([]*cachedConn{}, pool.conns...) {
+			if cconn.conn.GetState() == connectivity.Shutdown {
+				logger.Debugf("connection was shutdown [%s]", cconn.target)
+				if cconn.connected {
+					lost = append(lost, cconn.target)
+				}
+				cc.removeConn(cconn, "shutdown")
 			}
 		}
-		return
 	}
 
-	cconn, ok := cc.index[conn]
+	pool, ok := cc.pools[target]
 	if !ok {
-		logger.Warnf("connection not found [%p]", conn)
-		return
+		pool = newEndpointPool()
+		cc.pools[target] = pool
 	}
-	logger.Debugf("ReleaseConn [%s]", cconn.target)
 
-	if cconn.open > 0 {
-		cconn.lastClose = time.Now()
-		cconn.open--
-	}
-
-	cc.updateJanitor(cconn)
-}
-
-func (cc *CachingConnector) loadConn(target string) (*cachedConn, bool) {
-	connRaw, ok := cc.conns.Load(target)
-	if ok {
-		c, ok := connRaw.(*cachedConn)
-		if ok {
-			if c.conn.GetState() != connectivity.Shutdown {
-				logger.Debugf("using cached connection [%s: %p]", target, c)
-				return c, true
+	c, create := pool.pick(cc.maxConnsPerEndpoint, cc.maxStreamsPerConn)
+	if create {
+		var err error
+		c, err = cc.createConn(ctx, pool, target, opts...)
+		if err != nil {
+			if len(pool.conns) == 0 {
+				delete(cc.pools, target)
 			}
-			cc.shutdownConn(c)
+			return nil, nil, err
 		}
 	}
-	return nil, false
-}
-
-func (cc *CachingConnector) createConn(ctx context.Context, target string, opts ...grpc.DialOption) (*cachedConn, error) {
-	cc.lock.Lock()
-	defer cc.lock.Unlock()
-
-	if cc.janitorDone == nil {
-		return nil, errors.New("caching connector is closed")
+	if c == nil {
+		return nil, pool.released, nil
 	}
 
-	cconn, ok := cc.loadConn(target)
-	if ok {
-		return cconn, nil
-	}
+	logger.Debugf("using pooled connection [%s: %p]", target, c)
+	c.open++
+	c.lastOpen = time.Now()
+	addStreams(target, 1)
+	return c, nil, nil
+}
 
+// createConn dials a new connection and adds it to the pool. It must be called while holding
+// the lock of the connector.
+func (cc *CachingConnector) createConn(ctx context.Context, pool *endpointPool, target string, opts ...grpc.DialOption) (*cachedConn, error) {
 	logger.Debugf("creating connection [%s]", target)
-	conn, err := grpc.DialContext(ctx, target, opts...)
+	address := target
+	if cc.srv != nil && IsSRVAddress(target) {
+		var err error
+		if address, err = cc.srv.Next(target); err != nil {
+			return nil, err
+		}
+		logger.Debugf("connecting to [%s] for SRV target [%s]", address, target)
+	}
+	opts = append(opts, cc.dialOpts...)
+	conn, err := grpc.DialContext(ctx, address, opts...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "dialing peer failed")
 	}
 
 	logger.Debugf("storing connection [%s]", target)
-	cconn = &cachedConn{
+	cconn := &cachedConn{
 		target: target,
 		conn:   conn,
 	}
-	cc.conns.Store(target, cconn)
+	pool.conns = append(pool.conns, cconn)
 	cc.index[conn] = cconn
+	cachedConnections.Add(1)
+	cc.startSweeper()
 
 	return cconn, nil
 }
 
-func (cc *CachingConnector) openConn(ctx context.Context, c *cachedConn) error {
-
-	err := waitConn(ctx, c.conn, connectivity.Ready)
-	if err != nil {
-		return err
+// openConn waits for the connection to be ready. The stream reserved on the connection is
+// released if the connection doesn't become ready. True is returned if the connection has
+// become ready for the first time.
+func (cc *CachingConnector) openConn(ctx context.Context, c *cachedConn) (bool, error) {
+	if err := waitConn(ctx, c.conn, connectivity.Ready); err != nil {
+		cc.ReleaseConn(c.conn)
+		return false, err
 	}
 
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
-	c.open++
-	c.lastOpen = time.Now()
-	cc.updateJanitor(c)
+
+	connected := !c.connected
+	c.connected = true
 
 	logger.Debugf("connection was opened [%s]", c.target)
-	return nil
+	return connected, nil
 }
 
-func waitConn(ctx context.Context, conn *grpc.ClientConn, targetState connectivity.State) error {
-	for {
-		state := conn.GetState()
-		if state == targetState {
-			break
+// Reconcile closes the idle connections whose target isn't accepted by the given function,
+// e.g. connections to endpoints that have been removed from the configuration. Connections
+// that are in use are evicted by the sweeper once they've been released and become idle.
+func (cc *CachingConnector) Reconcile(accept func(target string) bool) {
+	var closed []string
+	defer func() { notifyDisconnected(closed, nil) }()
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	if cc.closed {
+		logger.Debug("Connector already closed")
+		return
+	}
+
+	for _, cconn := range cc.index {
+		if cconn.open > 0 || accept(cconn.target) {
+			continue
 		}
-		if !conn.WaitForStateChange(ctx, state) {
-			return errors.Wrap(ctx.Err(), "waiting for connection failed")
+
+		logger.Debugf("closing idle connection to endpoint that is no longer configured [%s]", cconn.target)
+		if cconn.connected {
+			closed = append(closed, cconn.target)
+		}
+		cc.removeConn(cconn, "reconcile")
+	}
+
+	for target := range cc.stats {
+		if _, ok := cc.pools[target]; !ok && !accept(target) {
+			for _, state := range connectivityStates {
+				endpointConnections.With(target, state.String()).Set(0)
+			}
+			delete(cc.stats, target)
 		}
 	}
-	return nil
 }
 
-func (cc *CachingConnector) shutdownConn(cconn *cachedConn) {
+// Refresh makes subsequent calls to DialContext use new connections, e.g. after the client's TLS
+// credentials have been rotated. Idle connections are closed immediately; connections that are in
+// use are no longer handed out and are closed once they've been released.
+func (cc *CachingConnector) Refresh() {
+	var closed []string
+	defer func() { notifyDisconnected(closed, nil) }()
+
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
 
-	if cc.janitorDone == nil {
+	if cc.closed {
 		logger.Debug("Connector already closed")
 		return
 	}
 
-	logger.Debugf("connection was shutdown [%s]", cconn.target)
-	cc.conns.Delete(cconn.target)
-	delete(cc.index, cconn.conn)
+	for _, cconn := range cc.index {
+		if cconn.open > 0 {
+			logger.Debugf("connection will be closed once released [%s]", cconn.target)
+			cc.retireConn(cconn)
+			continue
+		}
 
-	cconn.open = 0
-	cconn.lastClose = time.Time{}
+		logger.Debugf("closing idle connection [%s]", cconn.target)
+		if cconn.connected {
+			closed = append(closed, cconn.target)
+		}
+		cc.removeConn(cconn, "refresh")
+	}
+}
+
+// retireConn removes the connection from its pool without closing it. It must be called while
+// holding the lock of the connector.
+func (cc *CachingConnector) retireConn(c *cachedConn) {
+	c.stale = true
+	if pool, ok := cc.pools[c.target]; ok {
+		pool.remove(c)
+		if len(pool.conns) == 0 {
+			delete(cc.pools, c.target)
+		}
+	}
+}
 
-	cc.updateJanitor(cconn)
+// ConnectionInfo contains diagnostic information about a cached connection
+type ConnectionInfo struct {
+	Target    string    `json:"target"`
+	State     string    `json:"state"`
+	Open      int       `json:"open"`
+	LastOpen  time.Time `json:"lastOpen"`
+	LastClose time.Time `json:"lastClose"`
+	Breaker   string    `json:"breaker,omitempty"`
 }
 
-func (cc *CachingConnector) removeConn(target string) {
+// Connections returns diagnostic information about the cached connections, sorted by target
+func (cc *CachingConnector) Connections() []ConnectionInfo {
 	cc.lock.Lock()
 	defer cc.lock.Unlock()
 
-	logger.Debugf("removing connection [%s]", target)
-	connRaw, ok := cc.conns.Load(target)
-	if ok {
-		c, ok := connRaw.(*cachedConn)
-		if ok {
-			delete(cc.index, c.conn)
-			cc.conns.Delete(target)
-			if err := c.conn.Close(); err != nil {
-				logger.Debugf("unable to close connection [%s]", err)
-			}
+	var infos []ConnectionInfo
+	for conn, cconn := range cc.index {
+		info := ConnectionInfo{
+			Target:    cconn.target,
+			State:     conn.GetState().String(),
+			Open:      cconn.open,
+			LastOpen:  cconn.lastOpen,
+			LastClose: cconn.lastClose,
+		}
+		if cc.health != nil {
+			info.Breaker = cc.health.State(cconn.target).String()
 		}
+		infos = append(infos, info)
 	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Target < infos[j].Target
+	})
+	return infos
 }
 
-func (cc *CachingConnector) updateJanitor(c *cachedConn) {
-	select {
-	case <-cc.janitorClosed:
-		logger.Debugf("janitor not started")
-		cc.waitgroup.Add(1)
-		go janitor(cc.sweepTime, cc.idleTime, &cc.waitgroup, cc.janitorChan, cc.janitorClosed, cc.janitorDone, cc.removeConn)
-	default:
-		logger.Debugf("janitor already started")
+func (cc *CachingConnector) reportFailure(target string, err error) {
+	cc.recordDial(target, err)
+	if cc.health != nil {
+		cc.health.Failure(target)
 	}
-	cClone := *c
-
-	cc.janitorChan <- &cClone
 }
 
-// The janitor monitors open connections for shutdown state or extended non-usage.
-// This component operates by running a sweep with a period determined by "sweepTime".
-// When a connection returned the GRPC status connectivity.Shutdown or when the connection
-// has its usages closed for longer than "idleTime", the connection is closed and the
-// "connRemove" notifier is called.
-//
-// The caching connector:
-//    pushes connection information via the "conn" go channel.
-//    notifies the janitor of close by closing the "done" go channel.
-//
-// The janitor:
-//    calls "connRemove" callback when closing a connection.
-//    decrements the "wg" waitgroup when exiting.
-//    writes to the "done" go channel when closing due to becoming empty.
+// ReleaseConn notifies the pool that a stream acquired on the connection is no longer in use.
+func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
+	var closed []string
+	defer func() { notifyDisconnected(closed, nil) }()
 
-type connRemoveNotifier func(target string)
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
 
-func janitor(sweepTime time.Duration, idleTime time.Duration, wg *sync.WaitGroup, conn chan *cachedConn, close chan bool, done chan bool, connRemove connRemoveNotifier) {
-	logger.Debugf("starting connection janitor")
-	defer wg.Done()
+	// Safety check to see if the connector has been closed. This represents a
+	// bug in the calling code, but it's not good to panic here.
+	if cc.closed {
+		logger.Warn("Trying to release connection after connector closed")
 
-	conns := map[string]*cachedConn{}
-	ticker := time.NewTicker(sweepTime)
-	for {
-		select {
-		case <-done:
-			if len(conns) > 0 {
-				logger.Debugf("flushing connection janitor with open connections [%d]", len(conns))
-			} else {
-				logger.Debugf("flushing connection janitor")
-			}
-			flush(conns)
-			return
-		case c := <-conn:
-			cache(conns, c)
-		case <-ticker.C:
-			rm := sweep(conns, idleTime)
-			for _, target := range rm {
-				connRemove(target)
-				delete(conns, target)
+		if conn.GetState() != connectivity.Shutdown {
+			logger.Warn("Connection is not shutdown, trying to close ...")
+			if err := conn.Close(); err != nil {
+				logger.Warnf("conn close failed err %v", err)
 			}
+		}
+		return
+	}
 
-			if len(conns) == 0 {
-				logger.Debugf("closing connection janitor")
-				close <- true
-				return
+	cconn, ok := cc.index[conn]
+	if !ok {
+		logger.Warnf("connection not found [%p]", conn)
+		return
+	}
+	logger.Debugf("ReleaseConn [%s]", cconn.target)
+
+	if cconn.open > 0 {
+		cconn.lastClose = time.Now()
+		cconn.open--
+		addStreams(cconn.target, -1)
+	}
+
+	if cconn.stale {
+		if cconn.open == 0 {
+			logger.Debugf("closing released stale connection [%s]", cconn.target)
+			if cconn.connected {
+				closed = append(closed, cconn.target)
 			}
+			cc.removeConn(cconn, "refresh")
 		}
+		return
+	}
+
+	if pool, ok := cc.pools[cconn.target]; ok {
+		pool.signal()
 	}
 }
 
-func cache(conns map[string]*cachedConn, updateConn *cachedConn) {
+func waitConn(ctx context.Context, conn *grpc.ClientConn, targetState connectivity.State) error {
+	for {
+		state := conn.GetState()
+		if state == targetState {
+			break
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return errors.Wrap(ctx.Err(), "waiting for connection failed")
+		}
+	}
+	return nil
+}
 
-	c, ok := conns[updateConn.target]
-	if ok && updateConn.lastClose.IsZero() && updateConn.conn.GetState() == connectivity.Shutdown {
-		logger.Debugf("connection shutdown detected in connection janitor")
-		// We need to remove the connection from sweep consideration immediately
-		// since the connector has already removed it. Otherwise we can have a race
-		// between shutdown and creating a connection concurrently.
-		delete(conns, updateConn.target)
-		return
+// removeConn closes the connection and evicts it from its pool. It must be called while
+// holding the lock of the connector.
+func (cc *CachingConnector) removeConn(c *cachedConn, reason string) {
+	logger.Debugf("removing connection [%s]", c.target)
+	if err := c.conn.Close(); err != nil {
+		logger.Debugf("unable to close connection [%s]", err)
 	}
 
-	if !ok {
-		logger.Debugf("new connection in connection janitor")
-	} else if c.conn != updateConn.conn {
-		logger.Debugf("connection change in connection janitor")
+	if !c.stale {
+		cc.retireConn(c)
+	}
+	delete(cc.index, c.conn)
 
-		if err := c.conn.Close(); err != nil {
-			logger.Debugf("unable to close connection [%s]", err)
-		}
+	cachedConnections.Add(-1)
+	addStreams(c.target, -c.open)
+	connectionEvictions.With(reason).Add(1)
+}
+
+// notifyDisconnected notifies the connection listeners that the connections to the given targets
+// have been closed. It must be called without holding the lock of the connector.
+func notifyDisconnected(targets []string, err error) {
+	for _, target := range targets {
+		NotifyDisconnect(&ConnectionEvent{Target: target, Err: err})
+	}
+}
 
-	} else {
-		logger.Debugf("updating existing connection in connection janitor")
+// startSweeper starts the sweeper if it isn't running. It must be called while holding the
+// lock of the connector.
+func (cc *CachingConnector) startSweeper() {
+	if cc.sweeping {
+		return
 	}
 
-	conns[updateConn.target] = updateConn
+	logger.Debugf("starting connection sweeper")
+	cc.sweeping = true
+	cc.waitgroup.Add(1)
+	go cc.sweeper()
 }
 
-func flush(conns map[string]*cachedConn) {
-	for _, c := range conns {
-		logger.Debugf("connection janitor closing connection [%s]", c.target)
-		closeConn(c.conn)
+// The sweeper monitors the pooled connections for shutdown state or extended non-usage.
+// It runs a sweep with a period determined by "sweepTime" and stops itself once the pool
+// is empty (it is restarted when a connection is created) or the connector is closed.
+func (cc *CachingConnector) sweeper() {
+	defer cc.waitgroup.Done()
+
+	ticker := time.NewTicker(cc.sweepTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-cc.done:
+			logger.Debugf("closing connection sweeper")
+			return
+		case <-ticker.C:
+			if !cc.sweep() {
+				logger.Debugf("closing connection sweeper")
+				return
+			}
+		}
 	}
 }
 
-func sweep(conns map[string]*cachedConn, idleTime time.Duration) []string {
-	rm := make([]string, 0, len(conns))
+// sweep evicts the connections that are shut down or that have had their usages closed for
+// longer than "idleTime". False is returned if there is nothing left to monitor.
+func (cc *CachingConnector) sweep() bool {
+	var closed, lost []string
+	defer func() {
+		notifyDisconnected(closed, nil)
+		notifyDisconnected(lost, errors.New("connection was shut down"))
+	}()
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	if cc.closed {
+		return false
+	}
+
 	now := time.Now()
-	for _, c := range conns {
-		if c.open == 0 && now.After(c.lastClose.Add(idleTime)) {
-			logger.Debugf("connection janitor closing connection [%s]", c.target)
-			rm = append(rm, c.target)
-		} else if c.conn.GetState() == connectivity.Shutdown {
-			logger.Debugf("connection already closed [%s]", c.target)
-			rm = append(rm, c.target)
+	for conn, cconn := range cc.index {
+		if conn.GetState() == connectivity.Shutdown {
+			logger.Debugf("connection already closed [%s]", cconn.target)
+			if cconn.connected {
+				lost = append(lost, cconn.target)
+			}
+			cc.removeConn(cconn, "shutdown")
+		} else if cconn.open == 0 && now.After(cconn.lastClose.Add(cc.idleTime)) {
+			logger.Debugf("connection sweeper closing connection [%s]", cconn.target)
+			if cconn.connected {
+				closed = append(closed, cconn.target)
+			}
+			cc.removeConn(cconn, "idle")
 		}
 	}
-	return rm
+
+	cc.reportStates()
+
+	if len(cc.index) == 0 {
+		cc.sweeping = false
+		return false
+	}
+	return true
 }
 
 func closeConn(conn *grpc.ClientConn) {
@@ -390,3 +753,12 @@ func closeConn(conn *grpc.ClientConn) {
 	}
 	cancel()
 }
+
+// needsDialer returns true if the target lists several addresses or is connected to through
+// a proxy, neither of which is supported by the default dialer of GRPC
+func needsDialer(target string) bool {
+	if strings.Contains(target, ",") {
+		return true
+	}
+	return ProxyFor(target) != nil
+}
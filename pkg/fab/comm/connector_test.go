@@ -59,6 +59,27 @@ func TestConnectorHappyPath(t *testing.T) {
 	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn3), "connections should not match")
 }
 
+func TestConnectorDialOptions(t *testing.T) {
+	intercepted := 0
+	interceptor := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		intercepted++
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime, WithDialOptions(grpc.WithUnaryInterceptor(interceptor)))
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	defer connector.ReleaseConn(conn)
+
+	_, err = pb.NewEndorserClient(conn).ProcessProposal(context.Background(), &pb.SignedProposal{})
+	assert.Nil(t, err, "peer process proposal should not have error")
+	assert.Equal(t, 1, intercepted, "expecting dial option interceptor to be invoked")
+}
+
 func TestConnectorDoubleClose(t *testing.T) {
 	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
 	defer connector.Close()
@@ -176,6 +197,206 @@ func TestConnectorShouldSweep(t *testing.T) {
 	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn4), "connections should be different due to disconnect")
 }
 
+func TestConnectorReconcile(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[1], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn3, err := connector.DialContext(ctx, peerAddress, grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	connector.ReleaseConn(conn1)
+	connector.ReleaseConn(conn3)
+
+	// Only the endpoint of conn3 is still configured
+	connector.Reconcile(func(target string) bool { return target == peerAddress })
+
+	assert.Equal(t, connectivity.Shutdown, conn1.GetState(), "idle connection to removed endpoint should be shutdown")
+	assert.NotEqual(t, connectivity.Shutdown, conn2.GetState(), "connection in use should not be shutdown")
+	assert.NotEqual(t, connectivity.Shutdown, conn3.GetState(), "connection to configured endpoint should not be shutdown")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn4, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn4), "connections should be different due to reconcile")
+}
+
+func TestConnectorConnections(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime, WithHealthMonitor(NewHealthMonitor()))
+	defer connector.Close()
+
+	assert.Empty(t, connector.Connections())
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[1], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	_, err = connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	connector.ReleaseConn(conn1)
+
+	infos := make(map[string]ConnectionInfo)
+	for _, info := range connector.Connections() {
+		infos[info.Target] = info
+	}
+	assert.Len(t, infos, 2)
+
+	info := infos[endorserAddr[0]]
+	assert.Equal(t, 1, info.Open)
+	assert.Equal(t, BreakerClosed.String(), info.Breaker)
+	assert.NotEmpty(t, info.State)
+
+	info = infos[endorserAddr[1]]
+	assert.Equal(t, 0, info.Open)
+	assert.False(t, info.LastClose.IsZero(), "expecting last close time of released connection")
+}
+
+func TestConnectorPoolLimits(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime, WithMaxConnectionsPerEndpoint(2), WithMaxStreamsPerConnection(1))
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn2), "expecting a second connection when the first one is at its stream limit")
+	assert.Len(t, connector.Connections(), 2)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_, err = connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.NotNil(t, err, "DialContext should have failed since the pool is exhausted")
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		connector.ReleaseConn(conn2)
+	}()
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn3, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded once a connection was released")
+	assert.Equal(t, unsafe.Pointer(conn2), unsafe.Pointer(conn3), "expecting the released connection to be reused")
+	assert.Len(t, connector.Connections(), 2)
+}
+
+func TestConnectorRefresh(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[1], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	connector.ReleaseConn(conn2)
+
+	connector.Refresh()
+	assert.Equal(t, connectivity.Shutdown, conn2.GetState(), "idle connection should be closed on refresh")
+	assert.NotEqual(t, connectivity.Shutdown, conn1.GetState(), "connection in use should not be closed on refresh")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn3, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	assert.NotEqual(t, unsafe.Pointer(conn1), unsafe.Pointer(conn3), "expecting a new connection after refresh")
+
+	connector.ReleaseConn(conn1)
+	assert.Equal(t, connectivity.Shutdown, conn1.GetState(), "refreshed connection should be closed once released")
+	assert.NotEqual(t, connectivity.Shutdown, conn3.GetState())
+}
+
+type connectionRecorder struct {
+	lock   sync.Mutex
+	target string
+	events []string
+}
+
+func (r *connectionRecorder) record(kind string, event *ConnectionEvent) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if event.Target == r.target {
+		r.events = append(r.events, kind)
+	}
+}
+
+func (r *connectionRecorder) OnConnect(event *ConnectionEvent)    { r.record("connect", event) }
+func (r *connectionRecorder) OnDisconnect(event *ConnectionEvent) { r.record("disconnect", event) }
+func (r *connectionRecorder) OnError(event *ConnectionEvent)      { r.record("error", event) }
+
+func (r *connectionRecorder) Events() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]string{}, r.events...)
+}
+
+func TestConnectorLifecycleEvents(t *testing.T) {
+	recorder := &connectionRecorder{target: endorserAddr[0]}
+	AddConnectionListener(recorder)
+	defer RemoveConnectionListener(recorder)
+
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	assert.Equal(t, []string{"connect"}, recorder.Events(), "expecting a single connect event for a cached connection")
+
+	connector.ReleaseConn(conn1)
+	connector.ReleaseConn(conn2)
+	connector.Reconcile(func(target string) bool { return false })
+	assert.Equal(t, []string{"connect", "disconnect"}, recorder.Events())
+
+	badRecorder := &connectionRecorder{target: "127.0.0.1:0"}
+	AddConnectionListener(badRecorder)
+	defer RemoveConnectionListener(badRecorder)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_, err = connector.DialContext(ctx, "127.0.0.1:0", grpc.WithInsecure())
+	cancel()
+	assert.NotNil(t, err, "DialContext should have failed")
+	assert.Equal(t, []string{"error"}, badRecorder.Events())
+
+	RemoveConnectionListener(recorder)
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	_, err = connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+	assert.Len(t, recorder.Events(), 2, "expecting no events after the listener has been removed")
+}
+
 func TestConnectorConcurrent(t *testing.T) {
 	const goroutines = 50
 
@@ -233,3 +454,40 @@ func testDial(t *testing.T, wg *sync.WaitGroup, connector *CachingConnector, add
 	randomSleep := rand.Intn(maxSleepBeforeRelease)
 	time.Sleep(time.Duration(minSleepBeforeRelease)*time.Millisecond + time.Duration(randomSleep)*time.Millisecond)
 }
+
+func TestConnectorEndpoints(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime)
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.Nil(t, err, "DialContext should have succeeded")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_, err = connector.DialContext(ctx, "127.0.0.1:0", grpc.WithInsecure())
+	cancel()
+	assert.NotNil(t, err, "DialContext should have failed")
+
+	endpoints := connector.Endpoints()
+	assert.Len(t, endpoints, 2)
+	for _, e := range endpoints {
+		assert.Equal(t, uint64(1), e.Dials)
+		switch e.Target {
+		case endorserAddr[0]:
+			assert.Equal(t, uint64(0), e.Failures)
+			assert.Equal(t, 1, e.Streams)
+			assert.Equal(t, 1, e.States["READY"])
+		case "127.0.0.1:0":
+			assert.Equal(t, uint64(1), e.Failures)
+			assert.NotEmpty(t, e.LastError)
+			assert.Equal(t, 0, e.Streams)
+		default:
+			t.Fatalf("unexpected endpoint [%s]", e.Target)
+		}
+	}
+
+	connector.ReleaseConn(conn)
+	connector.Reconcile(func(target string) bool { return target == endorserAddr[0] })
+	assert.Len(t, connector.Endpoints(), 1, "expecting the statistics of the endpoint that is no longer configured to be removed")
+}
@@ -0,0 +1,160 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
+	"google.golang.org/grpc/connectivity"
+)
+
+var (
+	endpointDials = metrics.NewCounter(metrics.CounterOpts{
+		Subsystem:  "comm",
+		Name:       "endpoint_dials_total",
+		Help:       "The number of connection requests made to each endpoint.",
+		LabelNames: []string{"target", "status"},
+	})
+	endpointStreams = metrics.NewGauge(metrics.GaugeOpts{
+		Subsystem:  "comm",
+		Name:       "endpoint_open_streams",
+		Help:       "The number of connections to each endpoint that have been acquired and not released.",
+		LabelNames: []string{"target"},
+	})
+	endpointConnections = metrics.NewGauge(metrics.GaugeOpts{
+		Subsystem:  "comm",
+		Name:       "endpoint_connections",
+		Help:       "The number of pooled connections to each endpoint by connectivity state.",
+		LabelNames: []string{"target", "state"},
+	})
+)
+
+// connectivityStates are the states reported by the endpoint_connections gauge
+var connectivityStates = []connectivity.State{
+	connectivity.Idle,
+	connectivity.Connecting,
+	connectivity.Ready,
+	connectivity.TransientFailure,
+	connectivity.Shutdown,
+}
+
+// endpointStats holds the connection statistics of an endpoint
+type endpointStats struct {
+	dials       uint64
+	failures    uint64
+	lastDial    time.Time
+	lastFailure time.Time
+	lastError   string
+}
+
+// EndpointInfo contains diagnostic information about the connections to an endpoint
+type EndpointInfo struct {
+	Target      string         `json:"target"`
+	Dials       uint64         `json:"dials"`
+	Failures    uint64         `json:"failures"`
+	LastDial    time.Time      `json:"lastDial"`
+	LastFailure time.Time      `json:"lastFailure"`
+	LastError   string         `json:"lastError,omitempty"`
+	Streams     int            `json:"streams"`
+	States      map[string]int `json:"states"`
+	Breaker     string         `json:"breaker,omitempty"`
+}
+
+// Endpoints returns diagnostic information about every endpoint that the connector has
+// dialed (the number of connection requests and failures, the number of streams in use
+// and the states of the pooled connections), sorted by target
+func (cc *CachingConnector) Endpoints() []EndpointInfo {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	infos := make(map[string]*EndpointInfo)
+	for target, stats := range cc.stats {
+		infos[target] = &EndpointInfo{
+			Target:      target,
+			Dials:       stats.dials,
+			Failures:    stats.failures,
+			LastDial:    stats.lastDial,
+			LastFailure: stats.lastFailure,
+			LastError:   stats.lastError,
+			States:      make(map[string]int),
+		}
+	}
+	for conn, cconn := range cc.index {
+		info, ok := infos[cconn.target]
+		if !ok {
+			continue
+		}
+		info.Streams += cconn.open
+		info.States[conn.GetState().String()]++
+	}
+
+	var result []EndpointInfo
+	for target, info := range infos {
+		if cc.health != nil {
+			info.Breaker = cc.health.State(target).String()
+		}
+		result = append(result, *info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Target < result[j].Target
+	})
+	return result
+}
+
+// recordDial records the outcome of a connection request to the target
+func (cc *CachingConnector) recordDial(target string, err error) {
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	connectionDials.With(status).Add(1)
+	endpointDials.With(target, status).Add(1)
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	stats, ok := cc.stats[target]
+	if !ok {
+		stats = &endpointStats{}
+		cc.stats[target] = stats
+	}
+	now := time.Now()
+	stats.dials++
+	stats.lastDial = now
+	if err != nil {
+		stats.failures++
+		stats.lastFailure = now
+		stats.lastError = err.Error()
+	}
+}
+
+// addStreams adjusts the number of streams in use on the connections to the target.
+// It must be called while holding the lock of the connector.
+func addStreams(target string, delta int) {
+	openStreams.Add(float64(delta))
+	endpointStreams.With(target).Add(float64(delta))
+}
+
+// reportStates updates the connectivity states of the connections of every endpoint.
+// It must be called while holding the lock of the connector.
+func (cc *CachingConnector) reportStates() {
+	counts := make(map[string]map[connectivity.State]int)
+	for conn, cconn := range cc.index {
+		if counts[cconn.target] == nil {
+			counts[cconn.target] = make(map[connectivity.State]int)
+		}
+		counts[cconn.target][conn.GetState()]++
+	}
+
+	for target := range cc.stats {
+		for _, state := range connectivityStates {
+			endpointConnections.With(target, state.String()).Set(float64(counts[target][state]))
+		}
+	}
+}
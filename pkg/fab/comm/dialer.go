@@ -0,0 +1,155 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// UnixDialerName is the name of the built-in dialer that connects to unix domain sockets
+const UnixDialerName = "unix"
+
+// FallbackDelay is the delay after which the next address of an endpoint with several addresses
+// is dialed if the previous ones haven't connected yet
+var FallbackDelay = 300 * time.Millisecond
+
+// ContextDialer creates the network connection to an endpoint address. Custom dialers
+// may be used to connect through a proxy (e.g. SOCKS5), over unix domain sockets or to
+// in-memory listeners in tests.
+type ContextDialer func(ctx context.Context, address string) (net.Conn, error)
+
+var dialers = struct {
+	sync.RWMutex
+	registry map[string]ContextDialer
+}{
+	registry: map[string]ContextDialer{
+		UnixDialerName: UnixDialer,
+	},
+}
+
+// RegisterDialer registers a dialer under the given name so that it can be selected
+// in the configuration (client.global.dialer)
+func RegisterDialer(name string, dialer ContextDialer) {
+	dialers.Lock()
+	defer dialers.Unlock()
+	dialers.registry[name] = dialer
+}
+
+// Dialer returns the dialer that was registered under the given name
+func Dialer(name string) (ContextDialer, bool) {
+	dialers.RLock()
+	defer dialers.RUnlock()
+	dialer, ok := dialers.registry[name]
+	return dialer, ok
+}
+
+// DialerDialOpt returns the dial option that makes GRPC create its network connections
+// with the given dialer. The addresses of an endpoint that lists several (comma separated)
+// addresses are raced (see DialAddresses) and the addresses that have an HTTP CONNECT proxy
+// (see SetGlobalProxy and SetEndpointProxy) are connected to through the proxy.
+func DialerDialOpt(dialer ContextDialer) grpc.DialOption {
+	dialer = proxied(dialer)
+	return grpc.WithDialer(func(address string, timeout time.Duration) (net.Conn, error) {
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		if addresses := strings.Split(address, ","); len(addresses) > 1 {
+			return DialAddresses(ctx, addresses, dialer)
+		}
+		return dialer(ctx, address)
+	})
+}
+
+// DialAddresses dials the given addresses of an endpoint in order, starting the next dial
+// if the previous ones haven't connected within the fallback delay or as soon as they have
+// all failed ("happy eyeballs"). The first connection that is established is returned and
+// the other attempts are abandoned.
+func DialAddresses(ctx context.Context, addresses []string, dialer ContextDialer) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, len(addresses))
+
+	next := 0
+	pending := 0
+	dialNext := func() {
+		address := strings.TrimSpace(addresses[next])
+		next++
+		pending++
+		go func() {
+			conn, err := dialer(ctx, address)
+			results <- dialResult{conn: conn, err: errors.Wrapf(err, "dialing [%s] failed", address)}
+		}()
+	}
+
+	var errs error
+	dialNext()
+	fallback := time.After(FallbackDelay)
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				go closeConns(results, pending)
+				return r.conn, nil
+			}
+			logger.Debugf("%s", r.err)
+			errs = multi.Append(errs, r.err)
+			if next < len(addresses) {
+				dialNext()
+				fallback = time.After(FallbackDelay)
+			}
+		case <-fallback:
+			if next < len(addresses) {
+				logger.Debugf("no connection within %s - dialing [%s]", FallbackDelay, addresses[next])
+				dialNext()
+				fallback = time.After(FallbackDelay)
+			}
+		}
+	}
+	return nil, errs
+}
+
+type dialResult struct {
+	conn net.Conn
+	err  error
+}
+
+// closeConns closes the connections established by the dials that complete after the winner
+func closeConns(results <-chan dialResult, pending int) {
+	for ; pending > 0; pending-- {
+		if r := <-results; r.conn != nil {
+			if err := r.conn.Close(); err != nil {
+				logger.Debugf("unable to close connection [%s]", err)
+			}
+		}
+	}
+}
+
+// TCPDialer connects to the TCP address
+func TCPDialer(ctx context.Context, address string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", address)
+}
+
+// UnixDialer connects to the unix domain socket at the given address. The address
+// may be prefixed with "unix://".
+func UnixDialer(ctx context.Context, address string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", strings.TrimPrefix(address, "unix://"))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestConnectorWithDialer(t *testing.T) {
+	var lock sync.Mutex
+	var dialed []string
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		lock.Lock()
+		dialed = append(dialed, address)
+		lock.Unlock()
+
+		// Resolve the alias to the address of the endorser
+		var d net.Dialer
+		return d.DialContext(ctx, "tcp", endorserAddr[0])
+	}
+
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime, WithDialOptions(DialerDialOpt(dialer)))
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn, err := connector.DialContext(ctx, "endorser-alias:7051", grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err)
+	assert.Equal(t, connectivity.Ready, conn.GetState())
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.NotEmpty(t, dialed)
+	assert.Equal(t, "endorser-alias:7051", dialed[0])
+}
+
+func TestUnixDialer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dialer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "peer.sock")
+	lis, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer lis.Close()
+
+	go func() {
+		if conn, err := lis.Accept(); err == nil {
+			conn.Close()
+		}
+	}()
+
+	dialer, ok := Dialer(UnixDialerName)
+	require.True(t, ok, "expecting the unix dialer to be registered")
+
+	conn, err := dialer(context.Background(), "unix://"+socket)
+	require.NoError(t, err)
+	conn.Close()
+
+	_, ok = Dialer("socks5")
+	assert.False(t, ok)
+
+	RegisterDialer("socks5", UnixDialer)
+	_, ok = Dialer("socks5")
+	assert.True(t, ok)
+}
+
+func TestDialAddresses(t *testing.T) {
+	defer func(delay time.Duration) { FallbackDelay = delay }(FallbackDelay)
+	FallbackDelay = 10 * time.Millisecond
+
+	var lock sync.Mutex
+	var dialed []string
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		lock.Lock()
+		dialed = append(dialed, address)
+		lock.Unlock()
+
+		switch address {
+		case "unreachable:7051":
+			return nil, errors.New("connection refused")
+		case "slow:7051":
+			<-ctx.Done()
+			return nil, ctx.Err()
+		default:
+			client, server := net.Pipe()
+			go server.Close()
+			return client, nil
+		}
+	}
+
+	// A failed address falls back to the next one immediately
+	conn, err := DialAddresses(context.Background(), []string{"unreachable:7051", "external:7051"}, dialer)
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, []string{"unreachable:7051", "external:7051"}, dialed)
+
+	// An address that doesn't connect within the fallback delay is raced with the next one
+	dialed = nil
+	conn, err = DialAddresses(context.Background(), []string{"slow:7051", "external:7051"}, dialer)
+	require.NoError(t, err)
+	conn.Close()
+
+	// All of the failures are reported
+	_, err = DialAddresses(context.Background(), []string{"unreachable:7051", "unreachable:7051"}, dialer)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+}
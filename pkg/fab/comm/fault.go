@@ -0,0 +1,254 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fault describes a fault that is injected into the GRPC calls made to an endpoint
+type Fault struct {
+	// Endpoint is the address of the endpoint (e.g. peer0.org1.example.com:7051). The fault is
+	// injected into the calls made to every endpoint if empty.
+	Endpoint string
+	// Operation is the full name of the GRPC method (e.g. /protos.Endorser/ProcessProposal). The
+	// fault is injected into every operation if empty.
+	Operation string
+	// Latency delays the call (or the creation of the stream) by the given duration
+	Latency time.Duration
+	// Drop simulates a dropped connection: the call fails with codes.Unavailable without being sent
+	// and the messages of the streams that are already open fail to be received
+	Drop bool
+	// Code makes the call fail with the given GRPC status code without being sent (unless it's codes.OK)
+	Code codes.Code
+	// Probability is the probability, between 0 and 1, that the fault is injected into a call.
+	// The fault is injected into every call if it's 0.
+	Probability float64
+}
+
+// FaultInjector injects faults (latency, dropped connections and error codes) into the GRPC calls made
+// to the peers and orderers in order to test how applications handle retries and failover. Faults are
+// registered by name and are toggled individually, per endpoint and per operation, while the SDK is in use.
+// The interceptors of the injector are installed with fabsdk.WithFaultInjector.
+//
+// This component has been designed to be safe for concurrency.
+type FaultInjector struct {
+	lock     sync.RWMutex
+	faults   map[string]*injectedFault
+	disabled bool
+	random   *rand.Rand
+}
+
+type injectedFault struct {
+	Fault
+	enabled bool
+	count   int
+}
+
+// NewFaultInjector returns a new fault injector without faults
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{
+		faults: make(map[string]*injectedFault),
+		random: rand.New(rand.NewSource(time.Now().UnixNano())), //nolint
+	}
+}
+
+// Set registers (or replaces) the fault with the given name. The fault is enabled.
+func (f *FaultInjector) Set(name string, fault Fault) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.faults[name] = &injectedFault{Fault: fault, enabled: true}
+}
+
+// Remove unregisters the fault with the given name
+func (f *FaultInjector) Remove(name string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.faults, name)
+}
+
+// Clear unregisters all the faults
+func (f *FaultInjector) Clear() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.faults = make(map[string]*injectedFault)
+}
+
+// Enable enables the fault with the given name. It returns false if no such fault is registered.
+func (f *FaultInjector) Enable(name string) bool {
+	return f.setEnabled(name, true)
+}
+
+// Disable disables the fault with the given name without unregistering it. It returns false if
+// no such fault is registered.
+func (f *FaultInjector) Disable(name string) bool {
+	return f.setEnabled(name, false)
+}
+
+// Pause stops injecting all the faults until Resume is called
+func (f *FaultInjector) Pause() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.disabled = true
+}
+
+// Resume resumes injecting the enabled faults
+func (f *FaultInjector) Resume() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.disabled = false
+}
+
+// Injected returns the number of times the fault with the given name has been injected
+func (f *FaultInjector) Injected(name string) int {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if fault, ok := f.faults[name]; ok {
+		return fault.count
+	}
+	return 0
+}
+
+// UnaryInterceptor returns the interceptor that injects the faults into unary calls
+func (f *FaultInjector) UnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if err := f.inject(ctx, targetOf(cc), method); err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamInterceptor returns the interceptor that injects the faults into streaming calls. Dropped
+// connections are also simulated on the streams that are already open.
+func (f *FaultInjector) StreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		target := targetOf(cc)
+		if err := f.inject(ctx, target, method); err != nil {
+			return nil, err
+		}
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &faultStream{ClientStream: stream, injector: f, target: target, method: method}, nil
+	}
+}
+
+// inject applies the enabled faults that match the call. The latencies of the matching faults are
+// added up and the call fails with the first error of the matching faults, if any.
+func (f *FaultInjector) inject(ctx context.Context, target, method string) error {
+	latency, err := f.match(target, method, false)
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return err
+}
+
+// match returns the total latency and the error of the enabled faults that match the call. Only the
+// faults that drop connections are considered if dropOnly is true.
+func (f *FaultInjector) match(target, method string, dropOnly bool) (time.Duration, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.disabled {
+		return 0, nil
+	}
+
+	var latency time.Duration
+	var err error
+	for _, fault := range f.faults {
+		if !fault.enabled || !fault.matches(target, method) || (dropOnly && !fault.Drop) {
+			continue
+		}
+		if fault.Probability > 0 && f.random.Float64() >= fault.Probability {
+			continue
+		}
+		fault.count++
+		if !dropOnly {
+			latency += fault.Latency
+		}
+		if err == nil {
+			err = fault.err(target, method)
+		}
+	}
+	return latency, err
+}
+
+func (f *FaultInjector) setEnabled(name string, enabled bool) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	fault, ok := f.faults[name]
+	if !ok {
+		return false
+	}
+	fault.enabled = enabled
+	return true
+}
+
+func (fault *injectedFault) matches(target, method string) bool {
+	if fault.Operation != "" && fault.Operation != method {
+		return false
+	}
+	if fault.Endpoint == "" {
+		return true
+	}
+	// The target may be prefixed with the scheme of a resolver (e.g. dns:///peer0.org1.example.com:7051)
+	return target == fault.Endpoint || strings.HasSuffix(target, "/"+fault.Endpoint)
+}
+
+func (fault *injectedFault) err(target, method string) error {
+	switch {
+	case fault.Drop:
+		return status.Errorf(codes.Unavailable, "connection to %s dropped by fault injection [%s]", target, method)
+	case fault.Code != codes.OK:
+		return status.Errorf(fault.Code, "fault injected into call to %s [%s]", target, method)
+	default:
+		return nil
+	}
+}
+
+// faultStream fails to receive messages while a fault that drops the connection is enabled
+type faultStream struct {
+	grpc.ClientStream
+	injector *FaultInjector
+	target   string
+	method   string
+}
+
+// RecvMsg receives a message unless the connection is dropped. A receive that is blocked when the
+// connection is dropped fails once the next message arrives.
+func (s *faultStream) RecvMsg(m interface{}) error {
+	if _, err := s.injector.match(s.target, s.method, true); err != nil {
+		return err
+	}
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		return err
+	}
+	_, err := s.injector.match(s.target, s.method, true)
+	return err
+}
+
+func targetOf(cc *grpc.ClientConn) string {
+	if cc == nil {
+		return ""
+	}
+	return cc.Target()
+}
@@ -0,0 +1,130 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	endorseMethod = "/protos.Endorser/ProcessProposal"
+	deliverMethod = "/protos.Deliver/Deliver"
+)
+
+func TestFaultInjectorUnary(t *testing.T) {
+	injector := NewFaultInjector()
+	interceptor := injector.UnaryInterceptor()
+
+	invoked := 0
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		invoked++
+		return nil
+	}
+
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker))
+	assert.Equal(t, 1, invoked)
+
+	injector.Set("unavailable", Fault{Operation: endorseMethod, Code: codes.Unavailable})
+	err := interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker)
+	assert.Equal(t, codes.Unavailable, codeOf(err))
+	assert.Equal(t, 1, invoked, "expecting call not to be sent")
+	assert.Equal(t, 1, injector.Injected("unavailable"))
+
+	require.NoError(t, interceptor(context.Background(), "/orderer.AtomicBroadcast/Broadcast", nil, nil, nil, invoker), "expecting other operations not to be affected")
+
+	assert.True(t, injector.Disable("unavailable"))
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker))
+	assert.True(t, injector.Enable("unavailable"))
+	injector.Pause()
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker))
+	injector.Resume()
+	assert.Error(t, interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker))
+
+	injector.Remove("unavailable")
+	assert.False(t, injector.Enable("unavailable"))
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker))
+
+	injector.Set("other endpoint", Fault{Endpoint: "peer1.org1.example.com:7051", Drop: true})
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker), "expecting other endpoints not to be affected")
+
+	injector.Set("never", Fault{Drop: true, Probability: 0.000001})
+	injector.Set("always", Fault{Code: codes.ResourceExhausted, Probability: 1})
+	err = interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker)
+	assert.Equal(t, codes.ResourceExhausted, codeOf(err))
+
+	injector.Clear()
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker))
+}
+
+func TestFaultInjectorLatency(t *testing.T) {
+	injector := NewFaultInjector()
+	interceptor := injector.UnaryInterceptor()
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+
+	injector.Set("latency", Fault{Latency: 50 * time.Millisecond})
+	start := time.Now()
+	require.NoError(t, interceptor(context.Background(), endorseMethod, nil, nil, nil, invoker))
+	assert.True(t, time.Since(start) >= 50*time.Millisecond, "expecting call to be delayed")
+
+	injector.Set("latency", Fault{Latency: time.Minute})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := interceptor(ctx, endorseMethod, nil, nil, nil, invoker)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestFaultInjectorStream(t *testing.T) {
+	injector := NewFaultInjector()
+	interceptor := injector.StreamInterceptor()
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return &mockClientStream{}, nil
+	}
+
+	stream, err := interceptor(context.Background(), &grpc.StreamDesc{}, nil, deliverMethod, streamer)
+	require.NoError(t, err)
+	require.NoError(t, stream.RecvMsg(nil))
+
+	injector.Set("drop", Fault{Operation: deliverMethod, Drop: true})
+	err = stream.RecvMsg(nil)
+	assert.Equal(t, codes.Unavailable, codeOf(err), "expecting open stream to be dropped")
+
+	_, err = interceptor(context.Background(), &grpc.StreamDesc{}, nil, deliverMethod, streamer)
+	assert.Equal(t, codes.Unavailable, codeOf(err), "expecting new stream to be dropped")
+
+	injector.Disable("drop")
+	require.NoError(t, stream.RecvMsg(nil))
+}
+
+func TestFaultMatchesTarget(t *testing.T) {
+	fault := &injectedFault{Fault: Fault{Endpoint: "peer0.org1.example.com:7051"}}
+	assert.True(t, fault.matches("peer0.org1.example.com:7051", endorseMethod))
+	assert.True(t, fault.matches("dns:///peer0.org1.example.com:7051", endorseMethod))
+	assert.False(t, fault.matches("peer10.org1.example.com:7051", endorseMethod))
+}
+
+type mockClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *mockClientStream) RecvMsg(m interface{}) error {
+	return nil
+}
+
+func codeOf(err error) codes.Code {
+	s, _ := status.FromError(err)
+	return s.Code()
+}
@@ -0,0 +1,267 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/opevents"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultFailureThreshold = 3
+	defaultBreakerReset     = 10 * time.Second
+	defaultProbeTimeout     = 5 * time.Second
+)
+
+// Prober checks whether the endpoint at the given target is alive
+type Prober func(ctx context.Context, target string, opts ...grpc.DialOption) error
+
+// HealthMonitor maintains a circuit breaker for each endpoint that the SDK connects to.
+// The breakers are fed by the outcome of connection attempts made by the comm layer and,
+// if a probe interval is configured, by background probes of each known endpoint. This
+// allows dead endpoints to be skipped proactively rather than timing out on each request.
+// Callers must stop the background probes by calling the "Close" method.
+//
+// This component has been designed to be safe for concurrency.
+type HealthMonitor struct {
+	endpoints        sync.Map
+	failureThreshold int
+	resetTimeout     time.Duration
+	probeInterval    time.Duration
+	probeTimeout     time.Duration
+	prober           Prober
+	done             chan struct{}
+	closeOnce        sync.Once
+	wg               sync.WaitGroup
+}
+
+type monitoredEndpoint struct {
+	breaker  *CircuitBreaker
+	lock     sync.RWMutex
+	dialOpts []grpc.DialOption
+}
+
+// HealthMonitorOpt is a health monitor option
+type HealthMonitorOpt func(m *HealthMonitor)
+
+// WithFailureThreshold sets the number of consecutive failures after which an endpoint's breaker opens
+func WithFailureThreshold(value int) HealthMonitorOpt {
+	return func(m *HealthMonitor) {
+		m.failureThreshold = value
+	}
+}
+
+// WithBreakerReset sets the time that an endpoint's breaker stays open before a trial request is allowed
+func WithBreakerReset(value time.Duration) HealthMonitorOpt {
+	return func(m *HealthMonitor) {
+		m.resetTimeout = value
+	}
+}
+
+// WithProbeInterval sets the interval of the background health probes. A zero value disables probing.
+func WithProbeInterval(value time.Duration) HealthMonitorOpt {
+	return func(m *HealthMonitor) {
+		m.probeInterval = value
+	}
+}
+
+// WithProbeTimeout sets the timeout of a single health probe
+func WithProbeTimeout(value time.Duration) HealthMonitorOpt {
+	return func(m *HealthMonitor) {
+		m.probeTimeout = value
+	}
+}
+
+// WithProber overrides the default prober (which dials the endpoint and waits for the connection to become ready)
+func WithProber(value Prober) HealthMonitorOpt {
+	return func(m *HealthMonitor) {
+		m.prober = value
+	}
+}
+
+// NewHealthMonitor creates a new health monitor and starts the background probes (if enabled)
+func NewHealthMonitor(opts ...HealthMonitorOpt) *HealthMonitor {
+	m := &HealthMonitor{
+		failureThreshold: defaultFailureThreshold,
+		resetTimeout:     defaultBreakerReset,
+		probeTimeout:     defaultProbeTimeout,
+		prober:           dialProbe,
+		done:             make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.probeInterval > 0 {
+		m.wg.Add(1)
+		go m.probeLoop()
+	}
+
+	return m
+}
+
+// Register adds the given target to the set of monitored endpoints. The dial options
+// are used by the background probes to connect to the endpoint.
+func (m *HealthMonitor) Register(target string, opts ...grpc.DialOption) {
+	e := m.endpoint(target)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.dialOpts = opts
+}
+
+// Allow returns true if a request may be sent to the given target
+func (m *HealthMonitor) Allow(target string) bool {
+	return m.endpoint(target).breaker.Allow()
+}
+
+// Success records a successful request to the given target
+func (m *HealthMonitor) Success(target string) {
+	if m.endpoint(target).breaker.success() {
+		publishHealth(opevents.EndpointRecovered, target)
+	}
+}
+
+// Failure records a failed request to the given target
+func (m *HealthMonitor) Failure(target string) {
+	e := m.endpoint(target)
+	if e.breaker.failure() {
+		publishHealth(opevents.EndpointUnhealthy, target)
+	}
+	if e.breaker.State() == BreakerOpen {
+		logger.Debugf("circuit breaker is open for [%s]", target)
+	}
+}
+
+// Healthy returns false if the circuit breaker for the given URL is open. Unknown endpoints are considered healthy.
+func (m *HealthMonitor) Healthy(url string) bool {
+	value, ok := m.endpoints.Load(endpoint.ToAddress(url))
+	if !ok {
+		return true
+	}
+	return value.(*monitoredEndpoint).breaker.State() != BreakerOpen
+}
+
+// State returns the state of the circuit breaker for the given target or URL
+func (m *HealthMonitor) State(target string) BreakerState {
+	value, ok := m.endpoints.Load(endpoint.ToAddress(target))
+	if !ok {
+		return BreakerClosed
+	}
+	return value.(*monitoredEndpoint).breaker.State()
+}
+
+// Reconcile stops monitoring the endpoints whose target isn't accepted by the given function,
+// e.g. endpoints that have been removed from the configuration
+func (m *HealthMonitor) Reconcile(accept func(target string) bool) {
+	m.endpoints.Range(func(key, value interface{}) bool {
+		if target := key.(string); !accept(target) {
+			logger.Debugf("no longer monitoring [%s]", target)
+			m.endpoints.Delete(target)
+		}
+		return true
+	})
+}
+
+// Close stops the background probes
+func (m *HealthMonitor) Close() {
+	m.closeOnce.Do(func() {
+		logger.Debug("closing health monitor")
+		close(m.done)
+	})
+	m.wg.Wait()
+}
+
+func (m *HealthMonitor) endpoint(target string) *monitoredEndpoint {
+	value, ok := m.endpoints.Load(target)
+	if !ok {
+		value, _ = m.endpoints.LoadOrStore(target, &monitoredEndpoint{
+			breaker: NewCircuitBreaker(m.failureThreshold, m.resetTimeout),
+		})
+	}
+	return value.(*monitoredEndpoint)
+}
+
+func (m *HealthMonitor) probeLoop() {
+	defer m.wg.Done()
+
+	logger.Debugf("starting health monitor with probe interval %s", m.probeInterval)
+	ticker := time.NewTicker(m.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			logger.Debugf("stopping health monitor")
+			return
+		case <-ticker.C:
+			m.probeAll()
+		}
+	}
+}
+
+func (m *HealthMonitor) probeAll() {
+	m.endpoints.Range(func(key, value interface{}) bool {
+		select {
+		case <-m.done:
+			return false
+		default:
+		}
+		m.probe(key.(string), value.(*monitoredEndpoint))
+		return true
+	})
+}
+
+func (m *HealthMonitor) probe(target string, e *monitoredEndpoint) {
+	e.lock.RLock()
+	opts := e.dialOpts
+	e.lock.RUnlock()
+
+	if opts == nil {
+		// The endpoint was never connected to so we don't know how to dial it
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.probeTimeout)
+	defer cancel()
+
+	if err := m.prober(ctx, target, opts...); err != nil {
+		logger.Debugf("health probe failed for [%s]: %s", target, err)
+		if e.breaker.failure() {
+			publishHealth(opevents.EndpointUnhealthy, target)
+		}
+		return
+	}
+
+	if e.breaker.success() {
+		logger.Debugf("health probe succeeded for [%s] - closed circuit breaker", target)
+		publishHealth(opevents.EndpointRecovered, target)
+	}
+}
+
+func publishHealth(eventType opevents.Type, target string) {
+	opevents.Publish(&opevents.Event{Type: eventType, Source: target})
+}
+
+func dialProbe(ctx context.Context, target string, opts ...grpc.DialOption) error {
+	opts = append(opts[:len(opts):len(opts)], grpc.WithBlock())
+	conn, err := grpc.DialContext(ctx, target, opts...)
+	if err != nil {
+		return errors.Wrapf(err, "dialing [%s] failed", target)
+	}
+	if err := conn.Close(); err != nil {
+		logger.Debugf("unable to close probe connection [%s]", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/opevents"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+const unreachableAddr = "127.0.0.1:1"
+
+func TestHealthMonitorBreaker(t *testing.T) {
+	monitor := NewHealthMonitor(WithFailureThreshold(2), WithBreakerReset(time.Minute))
+	defer monitor.Close()
+
+	assert.True(t, monitor.Healthy("grpcs://"+unreachableAddr), "unknown endpoint should be healthy")
+
+	monitor.Failure(unreachableAddr)
+	monitor.Failure(unreachableAddr)
+	assert.Equal(t, BreakerOpen, monitor.State(unreachableAddr))
+	assert.Equal(t, BreakerOpen, monitor.State("grpcs://"+unreachableAddr))
+	assert.False(t, monitor.Healthy("grpcs://"+unreachableAddr), "endpoint should be unhealthy")
+	assert.False(t, monitor.Allow(unreachableAddr), "request should be rejected")
+
+	monitor.Success(unreachableAddr)
+	assert.True(t, monitor.Healthy(unreachableAddr), "endpoint should be healthy")
+}
+
+func TestHealthMonitorEvents(t *testing.T) {
+	const target = "127.0.0.1:2"
+
+	sub := opevents.Subscribe(0, opevents.EndpointUnhealthy, opevents.EndpointRecovered)
+	defer sub.Close()
+
+	monitor := NewHealthMonitor(WithFailureThreshold(2), WithBreakerReset(time.Minute))
+	defer monitor.Close()
+
+	monitor.Failure(target)
+	monitor.Failure(target)
+	monitor.Failure(target)
+	monitor.Success(target)
+	monitor.Success(target)
+
+	var events []opevents.Type
+	for len(sub.Events()) > 0 {
+		event := <-sub.Events()
+		if event.Source == target {
+			events = append(events, event.Type)
+		}
+	}
+	assert.Equal(t, []opevents.Type{opevents.EndpointUnhealthy, opevents.EndpointRecovered}, events,
+		"expecting a single event for each transition")
+}
+
+func TestHealthMonitorReconcile(t *testing.T) {
+	monitor := NewHealthMonitor(WithFailureThreshold(1), WithBreakerReset(time.Minute))
+	defer monitor.Close()
+
+	monitor.Failure(unreachableAddr)
+	assert.Equal(t, BreakerOpen, monitor.State(unreachableAddr))
+
+	monitor.Reconcile(func(target string) bool { return target != unreachableAddr })
+	assert.Equal(t, BreakerClosed, monitor.State(unreachableAddr), "removed endpoint should no longer be monitored")
+}
+
+func TestHealthMonitorProbe(t *testing.T) {
+	var healthy int32
+	prober := func(ctx context.Context, target string, opts ...grpc.DialOption) error {
+		if atomic.LoadInt32(&healthy) == 1 {
+			return nil
+		}
+		return errors.New("endpoint down")
+	}
+
+	monitor := NewHealthMonitor(
+		WithFailureThreshold(1),
+		WithBreakerReset(time.Minute),
+		WithProbeInterval(50*time.Millisecond),
+		WithProber(prober),
+	)
+	defer monitor.Close()
+
+	monitor.Register(endorserAddr[0], grpc.WithInsecure())
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, BreakerOpen, monitor.State(endorserAddr[0]), "failed probe should open the breaker")
+
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, BreakerClosed, monitor.State(endorserAddr[0]), "successful probe should close the breaker")
+}
+
+func TestHealthMonitorDialProbe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	err := dialProbe(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.NoError(t, err, "probe of running endpoint should succeed")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	err = dialProbe(ctx, unreachableAddr, grpc.WithInsecure())
+	cancel()
+	assert.Error(t, err, "probe of unreachable endpoint should fail")
+}
+
+func TestConnectorWithHealthMonitor(t *testing.T) {
+	monitor := NewHealthMonitor(WithFailureThreshold(1), WithBreakerReset(time.Minute))
+	defer monitor.Close()
+
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime, WithHealthMonitor(monitor))
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	assert.NoError(t, err, "DialContext should have succeeded")
+	connector.ReleaseConn(conn)
+	assert.Equal(t, BreakerClosed, monitor.State(endorserAddr[0]))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	_, err = connector.DialContext(ctx, unreachableAddr, grpc.WithInsecure())
+	cancel()
+	assert.Error(t, err, "DialContext should have failed")
+	assert.Equal(t, BreakerOpen, monitor.State(unreachableAddr))
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	start := time.Now()
+	_, err = connector.DialContext(ctx, unreachableAddr, grpc.WithInsecure())
+	cancel()
+	assert.Error(t, err, "DialContext should have failed")
+	assert.True(t, time.Since(start) < time.Second, "DialContext should fail fast when the breaker is open")
+}
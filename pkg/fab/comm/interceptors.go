@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// ChainUnaryInterceptors combines the given interceptors into a single interceptor since
+// only one interceptor may be set per connection. The interceptors are invoked in the given order.
+func ChainUnaryInterceptors(interceptors ...grpc.UnaryClientInterceptor) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		next := invoker
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, invoke := interceptors[i], next
+			next = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				return interceptor(ctx, method, req, reply, cc, invoke, opts...)
+			}
+		}
+		return next(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// ChainStreamInterceptors combines the given interceptors into a single interceptor since
+// only one interceptor may be set per connection. The interceptors are invoked in the given order.
+func ChainStreamInterceptors(interceptors ...grpc.StreamClientInterceptor) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		next := streamer
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, stream := interceptors[i], next
+			next = func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return interceptor(ctx, desc, cc, method, stream, opts...)
+			}
+		}
+		return next(ctx, desc, cc, method, opts...)
+	}
+}
+
+// InterceptorDialOpts returns the dial options that install the given interceptors. No option
+// is returned for a type of interceptor if none are provided.
+func InterceptorDialOpts(unary []grpc.UnaryClientInterceptor, stream []grpc.StreamClientInterceptor) []grpc.DialOption {
+	var opts []grpc.DialOption
+	if len(unary) > 0 {
+		opts = append(opts, grpc.WithUnaryInterceptor(ChainUnaryInterceptors(unary...)))
+	}
+	if len(stream) > 0 {
+		opts = append(opts, grpc.WithStreamInterceptor(ChainStreamInterceptors(stream...)))
+	}
+	return opts
+}
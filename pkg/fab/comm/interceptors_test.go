@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestChainUnaryInterceptors(t *testing.T) {
+	var calls []string
+	newInterceptor := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			calls = append(calls, name)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls = append(calls, "invoker")
+		return nil
+	}
+
+	chained := ChainUnaryInterceptors(newInterceptor("first"), newInterceptor("second"))
+	require.NoError(t, chained(context.Background(), "method", nil, nil, nil, invoker))
+	assert.Equal(t, []string{"first", "second", "invoker"}, calls)
+}
+
+func TestChainStreamInterceptors(t *testing.T) {
+	var calls []string
+	newInterceptor := func(name string) grpc.StreamClientInterceptor {
+		return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			calls = append(calls, name)
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+	}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		calls = append(calls, "streamer")
+		return nil, nil
+	}
+
+	chained := ChainStreamInterceptors(newInterceptor("first"), newInterceptor("second"))
+	_, err := chained(context.Background(), &grpc.StreamDesc{}, nil, "method", streamer)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "streamer"}, calls)
+}
+
+func TestInterceptorDialOpts(t *testing.T) {
+	assert.Empty(t, InterceptorDialOpts(nil, nil))
+
+	unary := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	assert.Len(t, InterceptorDialOpts([]grpc.UnaryClientInterceptor{unary, unary}, nil), 1)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"sync"
+)
+
+// ConnectionEvent describes a change in the state of a connection to a peer, orderer or event server
+type ConnectionEvent struct {
+	// Target is the address of the endpoint
+	Target string
+	// Stream is true if the connection is an event stream, false if it's a (cached) GRPC connection
+	Stream bool
+	// Err is the error that caused the failure or disconnection (if any)
+	Err error
+}
+
+// ConnectionListener is notified of the lifecycle events of the connections. The callbacks are
+// invoked synchronously by the goroutine that observed the event, so they must not block.
+type ConnectionListener interface {
+	// OnConnect is invoked when a connection has been established
+	OnConnect(event *ConnectionEvent)
+	// OnDisconnect is invoked when a connection has been closed or lost
+	OnDisconnect(event *ConnectionEvent)
+	// OnError is invoked when a connection couldn't be established
+	OnError(event *ConnectionEvent)
+}
+
+var connListeners struct {
+	sync.RWMutex
+	list []ConnectionListener
+}
+
+// AddConnectionListener registers a listener that is notified of the lifecycle events of all
+// the connections created in the process
+func AddConnectionListener(l ConnectionListener) {
+	connListeners.Lock()
+	defer connListeners.Unlock()
+	connListeners.list = append(connListeners.list, l)
+}
+
+// RemoveConnectionListener unregisters the given listener
+func RemoveConnectionListener(l ConnectionListener) {
+	connListeners.Lock()
+	defer connListeners.Unlock()
+
+	for i, listener := range connListeners.list {
+		if listener == l {
+			connListeners.list = append(connListeners.list[:i:i], connListeners.list[i+1:]...)
+			return
+		}
+	}
+}
+
+func connectionListeners() []ConnectionListener {
+	connListeners.RLock()
+	defer connListeners.RUnlock()
+	return connListeners.list
+}
+
+// NotifyConnect notifies the listeners that a connection has been established
+func NotifyConnect(event *ConnectionEvent) {
+	for _, l := range connectionListeners() {
+		l.OnConnect(event)
+	}
+}
+
+// NotifyDisconnect notifies the listeners that a connection has been closed or lost
+func NotifyDisconnect(event *ConnectionEvent) {
+	for _, l := range connectionListeners() {
+		l.OnDisconnect(event)
+	}
+}
+
+// NotifyError notifies the listeners that a connection couldn't be established
+func NotifyError(event *ConnectionEvent) {
+	for _, l := range connectionListeners() {
+		l.OnError(event)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyDialer(t *testing.T) {
+	// The proxy accepts CONNECT requests with the expected credentials and echoes the tunnelled data
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	requests := make(chan *http.Request, 1)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+				requests <- req
+				if req.Header.Get("Proxy-Authorization") != "Basic dXNlcjpzZWNyZXQ=" {
+					conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\n\r\n")) //nolint
+					return
+				}
+				conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")) //nolint
+				io.Copy(conn, conn)                                               //nolint
+			}(conn)
+		}
+	}()
+
+	proxyURL, err := ParseProxyURL("http://user:secret@" + lis.Addr().String())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := ProxyDialer(proxyURL, TCPDialer)(ctx, "peer0.org1.example.com:7051")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	req := <-requests
+	assert.Equal(t, http.MethodConnect, req.Method)
+	assert.Equal(t, "peer0.org1.example.com:7051", req.Host)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+	b := make([]byte, 4)
+	_, err = io.ReadFull(conn, b)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(b))
+
+	// Wrong credentials
+	proxyURL, err = ParseProxyURL("http://user:wrong@" + lis.Addr().String())
+	require.NoError(t, err)
+	_, err = ProxyDialer(proxyURL, TCPDialer)(ctx, "peer0.org1.example.com:7051")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "407")
+}
+
+func TestProxyFor(t *testing.T) {
+	defer SetGlobalProxy(nil)
+
+	global, err := ParseProxyURL("http://proxy.example.com:3128")
+	require.NoError(t, err)
+	SetGlobalProxy(global)
+	SetEndpointProxy("direct.example.com:7051", nil)
+	defer func() {
+		proxies.Lock()
+		delete(proxies.endpoints, "direct.example.com:7051")
+		proxies.Unlock()
+	}()
+
+	assert.Equal(t, global, ProxyFor("peer0.org1.example.com:7051"))
+	assert.Nil(t, ProxyFor("direct.example.com:7051"))
+
+	proxyURL, err := ParseProxyURL(NoProxy)
+	assert.NoError(t, err)
+	assert.Nil(t, proxyURL)
+	_, err = ParseProxyURL("socks5://proxy.example.com:1080")
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultSRVRefreshInterval is the interval after which the SRV records of an endpoint are looked up again
+const DefaultSRVRefreshInterval = 30 * time.Second
+
+// SRVLookup looks up the SRV records of the given name (e.g. net.DefaultResolver.LookupSRV)
+type SRVLookup func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+
+// SRVResolver resolves endpoint addresses from DNS SRV records so that a single logical endpoint
+// (e.g. a Kubernetes headless service) may be backed by several peers or orderers. An address whose
+// host starts with an underscore (e.g. "_grpc._tcp.peers.default.svc.cluster.local") is the name of
+// SRV records; any port of the address is ignored since the records carry the ports.
+//
+// The resolved addresses are cached for the refresh interval (the TTLs of the records aren't exposed
+// by the Go resolver) and are handed out in a round-robin fashion. The previously resolved addresses
+// are kept if a lookup fails.
+type SRVResolver struct {
+	lookup  SRVLookup
+	refresh time.Duration
+	now     func() time.Time
+	lock    sync.Mutex
+	entries map[string]*srvEntry
+}
+
+type srvEntry struct {
+	addresses []string
+	expires   time.Time
+	next      int
+}
+
+// NewSRVResolver returns a resolver that looks up the SRV records of an endpoint again once the
+// given interval has elapsed (DefaultSRVRefreshInterval if zero)
+func NewSRVResolver(refresh time.Duration) *SRVResolver {
+	if refresh <= 0 {
+		refresh = DefaultSRVRefreshInterval
+	}
+	return &SRVResolver{
+		lookup:  net.DefaultResolver.LookupSRV,
+		refresh: refresh,
+		now:     time.Now,
+		entries: make(map[string]*srvEntry),
+	}
+}
+
+// IsSRVAddress returns true if the given address is the name of SRV records
+func IsSRVAddress(address string) bool {
+	return strings.HasPrefix(srvName(address), "_")
+}
+
+// Refresh looks up the SRV records of the address unless the addresses resolved previously are still fresh
+func (r *SRVResolver) Refresh(ctx context.Context, address string) error {
+	r.lock.Lock()
+	e, ok := r.entries[address]
+	fresh := ok && r.now().Before(e.expires)
+	r.lock.Unlock()
+
+	if fresh {
+		return nil
+	}
+
+	addresses, err := r.resolve(ctx, address)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	e, ok = r.entries[address]
+	if err != nil {
+		if ok && len(e.addresses) > 0 {
+			logger.Warnf("looking up SRV records of [%s] failed - using the previous addresses: %s", address, err)
+			e.expires = r.now().Add(r.refresh)
+			return nil
+		}
+		return err
+	}
+
+	if !ok {
+		e = &srvEntry{}
+		r.entries[address] = e
+	}
+	logger.Debugf("resolved SRV records of [%s]: %v", address, addresses)
+	e.addresses = addresses
+	e.expires = r.now().Add(r.refresh)
+	return nil
+}
+
+// Next returns the next of the addresses resolved by the last refresh of the given address
+func (r *SRVResolver) Next(address string) (string, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	e, ok := r.entries[address]
+	if !ok || len(e.addresses) == 0 {
+		return "", errors.Errorf("SRV records of [%s] haven't been resolved", address)
+	}
+	resolved := e.addresses[e.next%len(e.addresses)]
+	e.next++
+	return resolved, nil
+}
+
+func (r *SRVResolver) resolve(ctx context.Context, address string) ([]string, error) {
+	_, records, err := r.lookup(ctx, "", "", srvName(address))
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking up SRV records of [%s] failed", address)
+	}
+	if len(records) == 0 {
+		return nil, errors.Errorf("no SRV records found for [%s]", address)
+	}
+
+	// The records are sorted by priority and randomized by weight by the lookup
+	var addresses []string
+	for _, srv := range records {
+		addresses = append(addresses, net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port))))
+	}
+	return addresses, nil
+}
+
+func srvName(address string) string {
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
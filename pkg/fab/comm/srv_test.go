@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package comm
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSRVResolver(t *testing.T) {
+	assert.True(t, IsSRVAddress("_grpc._tcp.peers.default.svc.cluster.local"))
+	assert.True(t, IsSRVAddress("_grpc._tcp.peers.default.svc.cluster.local:7051"))
+	assert.False(t, IsSRVAddress("peer0.org1.example.com:7051"))
+
+	now := time.Now()
+	lookups := 0
+	var lookupErr error
+	records := []*net.SRV{
+		{Target: "peer-0.peers.default.svc.cluster.local.", Port: 7051},
+		{Target: "peer-1.peers.default.svc.cluster.local.", Port: 8051},
+	}
+
+	r := NewSRVResolver(time.Minute)
+	r.now = func() time.Time { return now }
+	r.lookup = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		lookups++
+		assert.Equal(t, "_grpc._tcp.peers.default.svc.cluster.local", name)
+		return "", records, lookupErr
+	}
+
+	target := "_grpc._tcp.peers.default.svc.cluster.local:7051"
+	_, err := r.Next(target)
+	assert.Error(t, err, "expected failure before the records are resolved")
+
+	require.NoError(t, r.Refresh(context.Background(), target))
+	require.NoError(t, r.Refresh(context.Background(), target))
+	assert.Equal(t, 1, lookups, "expected the records to be cached")
+
+	for _, expected := range []string{"peer-0.peers.default.svc.cluster.local:7051", "peer-1.peers.default.svc.cluster.local:8051", "peer-0.peers.default.svc.cluster.local:7051"} {
+		address, err := r.Next(target)
+		require.NoError(t, err)
+		assert.Equal(t, expected, address)
+	}
+
+	// The records are looked up again once the refresh interval has elapsed
+	now = now.Add(time.Minute)
+	records = records[1:]
+	require.NoError(t, r.Refresh(context.Background(), target))
+	assert.Equal(t, 2, lookups)
+	address, err := r.Next(target)
+	require.NoError(t, err)
+	assert.Equal(t, "peer-1.peers.default.svc.cluster.local:8051", address)
+
+	// The previous addresses are kept if the lookup fails
+	now = now.Add(time.Minute)
+	lookupErr = errors.New("lookup failed")
+	require.NoError(t, r.Refresh(context.Background(), target))
+	address, err = r.Next(target)
+	require.NoError(t, err)
+	assert.Equal(t, "peer-1.peers.default.svc.cluster.local:8051", address)
+
+	// Refreshing a target whose records have never been resolved fails if the lookup fails
+	r.lookup = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+		return "", nil, errors.New("no such host")
+	}
+	assert.Error(t, r.Refresh(context.Background(), "_grpc._tcp.orderers.default.svc.cluster.local"))
+}
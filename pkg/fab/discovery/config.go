@@ -0,0 +1,107 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// OrdererEndpoint is the endpoint of an orderer as returned by a config query
+type OrdererEndpoint struct {
+	Host string
+	Port uint32
+}
+
+// URL returns the URL (host:port) of the orderer
+func (e OrdererEndpoint) URL() string {
+	return fmt.Sprintf("%s:%d", e.Host, e.Port)
+}
+
+// ChannelConfig contains the channel configuration returned by a Discovery config query
+type ChannelConfig struct {
+	// MSPs maps an MSP ID to the MSP configuration (which includes the root and TLS root certificates)
+	MSPs map[string]*msp.FabricMSPConfig
+	// Orderers maps an MSP ID to the endpoints of that organization's orderers
+	Orderers map[string][]OrdererEndpoint
+}
+
+// NewChannelConfig returns the channel configuration contained in the given config query result
+func NewChannelConfig(result *discovery.ConfigResult) *ChannelConfig {
+	config := &ChannelConfig{
+		MSPs:     result.Msps,
+		Orderers: make(map[string][]OrdererEndpoint),
+	}
+	for mspID, endpoints := range result.Orderers {
+		for _, e := range endpoints.Endpoint {
+			config.Orderers[mspID] = append(config.Orderers[mspID], OrdererEndpoint{Host: e.Host, Port: e.Port})
+		}
+	}
+	return config
+}
+
+// OrdererConfigs returns the configuration of all orderers in the channel. The TLS CA certificate
+// of each orderer is the first TLS root certificate of the orderer organization's MSP.
+func (c *ChannelConfig) OrdererConfigs() []fab.OrdererConfig {
+	var mspIDs []string
+	for mspID := range c.Orderers {
+		mspIDs = append(mspIDs, mspID)
+	}
+	sort.Strings(mspIDs)
+
+	var configs []fab.OrdererConfig
+	for _, mspID := range mspIDs {
+		var tlsCACert endpoint.TLSConfig
+		if mspConfig, ok := c.MSPs[mspID]; ok && len(mspConfig.TlsRootCerts) > 0 {
+			tlsCACert.Pem = string(mspConfig.TlsRootCerts[0])
+		}
+		for _, e := range c.Orderers[mspID] {
+			configs = append(configs, fab.OrdererConfig{
+				URL: e.URL(),
+				GRPCOptions: map[string]interface{}{
+					"ssl-target-name-override": e.Host,
+				},
+				TLSCACerts: tlsCACert,
+			})
+		}
+	}
+	return configs
+}
+
+// QueryConfig retrieves the MSPs and orderer endpoints of the given channel from the given set of
+// peers. The configuration is returned from the first peer that responds successfully; an error is
+// returned only if none of the peers responded successfully.
+func (c *Client) QueryConfig(ctx context.Context, channelID string, targets ...fab.PeerConfig) (*ChannelConfig, error) {
+	req := discclient.NewRequest().OfChannel(channelID).AddConfigQuery()
+
+	responses, err := c.Send(ctx, req, targets...)
+
+	errs := err
+	for _, resp := range responses {
+		result, err := resp.ForChannel(channelID).Config()
+		if err != nil {
+			errs = multi.Append(errs, errors.WithMessage(err, "From target: "+resp.Target()))
+			continue
+		}
+
+		return NewChannelConfig(result), nil
+	}
+
+	if errs == nil {
+		return nil, errors.Errorf("no config returned for channel [%s]", channelID)
+	}
+	return nil, errors.WithMessage(errs, fmt.Sprintf("config query failed for channel [%s]", channelID))
+}
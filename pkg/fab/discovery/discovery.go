@@ -50,7 +50,8 @@ type Response interface {
 // Send retrieves information about channel peers, endorsers, and MSP config from the
 // given set of peers. A set of successful responses is returned and/or an error
 // is returned from each of the peers that was unsuccessful (note that if more than one peer returned
-// an error then the returned error may be cast to multi.Errors).
+// an error then the returned error may be cast to multi.Errors). Errors are returned as a status
+// (see IsAccessDenied and IsNoEndorsementLayout) so that transient errors may be retried.
 func (c *Client) Send(ctx context.Context, req *discclient.Request, targets ...fab.PeerConfig) ([]Response, error) {
 	if len(targets) == 0 {
 		return nil, errors.New("no targets specified")
@@ -87,11 +88,11 @@ func (c *Client) send(reqCtx context.Context, req *discclient.Request, target fa
 	if err != nil {
 		return nil, err
 	}
-	opts = append(opts, comm.WithConnectTimeout(c.ctx.EndpointConfig().Timeout(fab.DiscoveryConnection)))
+	opts = append(opts, comm.WithConnectTimeout(c.ctx.EndpointConfig().Timeout(fab.DiscoveryConnection)), comm.WithParentContext(reqCtx))
 
 	conn, err := comm.NewConnection(c.ctx, target.URL, opts...)
 	if err != nil {
-		return nil, err
+		return nil, newTransportError(err, target.URL)
 	}
 	defer conn.Close()
 
@@ -103,7 +104,11 @@ func (c *Client) send(reqCtx context.Context, req *discclient.Request, target fa
 			return c.ctx.SigningManager().Sign(msg, c.ctx.PrivateKey())
 		},
 	)
-	return discClient.Send(reqCtx, req, c.authInfo)
+	resp, err := discClient.Send(reqCtx, req, c.authInfo)
+	if err != nil {
+		return nil, newTransportError(err, target.URL)
+	}
+	return resp, nil
 }
 
 type response struct {
@@ -116,6 +121,16 @@ func (r *response) Target() string {
 	return r.target
 }
 
+// ForChannel returns a ChannelResponse in the context of a given channel
+func (r *response) ForChannel(channelID string) discclient.ChannelResponse {
+	return &channelResponse{ChannelResponse: r.Response.ForChannel(channelID)}
+}
+
+// ForLocal returns a LocalResponse in the context of no channel
+func (r *response) ForLocal() discclient.LocalResponse {
+	return &localResponse{LocalResponse: r.Response.ForLocal()}
+}
+
 func newAuthInfo(ctx fabcontext.Client) (*discovery.AuthInfo, error) {
 	identity, err := ctx.Serialize()
 	if err != nil {
@@ -22,6 +22,7 @@ import (
 	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 )
@@ -87,6 +88,48 @@ func TestDiscoveryClient(t *testing.T) {
 	t.Logf("Got error responses: %v", errs)
 }
 
+func TestQueryConfig(t *testing.T) {
+	channelID := "mychannel"
+
+	client, err := New(newMockContext())
+	assert.NoError(t, err)
+
+	target := fab.PeerConfig{
+		URL: peerAddress,
+		GRPCOptions: map[string]interface{}{
+			"allow-insecure": true,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	config, err := client.QueryConfig(ctx, channelID, target)
+	cancel()
+	if err != nil {
+		t.Fatalf("config query failed: %s", err)
+	}
+
+	assert.Equal(t, 1, len(config.MSPs))
+	assert.Equal(t, []OrdererEndpoint{{Host: "orderer.example.com", Port: 7050}}, config.Orderers[ordererMSPID])
+
+	ordererConfigs := config.OrdererConfigs()
+	if len(ordererConfigs) != 1 {
+		t.Fatalf("expecting 1 orderer config but got %d", len(ordererConfigs))
+	}
+	assert.Equal(t, "orderer.example.com:7050", ordererConfigs[0].URL)
+	assert.Equal(t, "orderer.example.com", ordererConfigs[0].GRPCOptions["ssl-target-name-override"])
+	assert.Equal(t, ordererTLSCert, ordererConfigs[0].TLSCACerts.Pem)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	_, err = client.QueryConfig(ctx, channelID)
+	cancel()
+	assert.Error(t, err, "expecting error when no targets are specified")
+}
+
+const (
+	ordererMSPID   = "OrdererMSP"
+	ordererTLSCert = "-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----\n"
+)
+
 var discoverServer *discmocks.MockDiscoveryServer
 
 func TestMain(m *testing.M) {
@@ -118,6 +161,21 @@ func TestMain(m *testing.M) {
 				LedgerHeight: 25,
 			},
 		),
+		discmocks.WithConfig(
+			&discovery.ConfigResult{
+				Msps: map[string]*msp.FabricMSPConfig{
+					ordererMSPID: {
+						Name:         ordererMSPID,
+						TlsRootCerts: [][]byte{[]byte(ordererTLSCert)},
+					},
+				},
+				Orderers: map[string]*discovery.Endpoints{
+					ordererMSPID: {
+						Endpoint: []*discovery.Endpoint{{Host: "orderer.example.com", Port: 7050}},
+					},
+				},
+			},
+		),
 	)
 
 	discovery.RegisterDiscoveryServer(grpcServer, discoverServer)
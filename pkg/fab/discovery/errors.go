@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"strings"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// Error messages returned by the Discovery service (and client) that are used to classify errors
+var (
+	accessDeniedMsgs = []string{"access denied"}
+	noLayoutMsgs     = []string{
+		"no endorsement combination can be satisfied",
+		"no peer combination can satisfy the endorsement policy",
+		"cannot satisfy any principal combination",
+		"failed constructing descriptor",
+	}
+)
+
+// IsAccessDenied returns true if the given error indicates that the
+// Discovery service denied access to the client. If the error holds the
+// errors of multiple targets then true is returned if any of the targets
+// denied access.
+func IsAccessDenied(err error) bool {
+	return hasCode(err, status.AccessDenied)
+}
+
+// IsNoEndorsementLayout returns true if the given error indicates that the Discovery
+// service could not find a set of peers that satisfies the endorsement policy. If the
+// error holds the errors of multiple targets then true is returned if any of the
+// targets returned this error.
+func IsNoEndorsementLayout(err error) bool {
+	return hasCode(err, status.NoEndorsementLayout)
+}
+
+func hasCode(err error, code status.Code) bool {
+	if errs, ok := errors.Cause(err).(multi.Errors); ok {
+		for _, e := range errs {
+			if hasCode(e, code) {
+				return true
+			}
+		}
+		return false
+	}
+
+	s, ok := status.FromError(err)
+	return ok && s.Group == status.DiscoveryServerStatus && status.ToSDKStatusCode(s.Code) == code
+}
+
+// newServerError converts an error returned in a Discovery response into a status
+func newServerError(err error) error {
+	if err == nil || err == discclient.ErrNotFound {
+		return err
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := status.Unknown
+	if containsAny(err.Error(), accessDeniedMsgs) {
+		code = status.AccessDenied
+	} else if containsAny(err.Error(), noLayoutMsgs) {
+		code = status.NoEndorsementLayout
+	}
+	return status.New(status.DiscoveryServerStatus, code.ToInt32(), err.Error(), nil)
+}
+
+// newTransportError converts an error that occurred while sending a request to the Discovery service into a status
+func newTransportError(err error, target string) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if s, ok := grpcstatus.FromError(errors.Cause(err)); ok {
+		return status.NewFromGRPCStatus(s)
+	}
+	if containsAny(err.Error(), accessDeniedMsgs) {
+		return status.New(status.DiscoveryServerStatus, status.AccessDenied.ToInt32(), err.Error(), []interface{}{target})
+	}
+	return status.New(status.ClientStatus, status.ConnectionFailed.ToInt32(), err.Error(), []interface{}{target})
+}
+
+func containsAny(msg string, substrs []string) bool {
+	for _, s := range substrs {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// channelResponse classifies the errors returned by the Discovery channel response
+type channelResponse struct {
+	discclient.ChannelResponse
+}
+
+// Config returns a response for a config query
+func (r *channelResponse) Config() (*discovery.ConfigResult, error) {
+	res, err := r.ChannelResponse.Config()
+	return res, newServerError(err)
+}
+
+// Peers returns a response for a peer membership query
+func (r *channelResponse) Peers() ([]*discclient.Peer, error) {
+	peers, err := r.ChannelResponse.Peers()
+	return peers, newServerError(err)
+}
+
+// Endorsers returns the response for an endorser query
+func (r *channelResponse) Endorsers(cc string, ps discclient.PrioritySelector, ef discclient.ExclusionFilter) (discclient.Endorsers, error) {
+	endorsers, err := r.ChannelResponse.Endorsers(cc, ps, ef)
+	return endorsers, newServerError(err)
+}
+
+// localResponse classifies the errors returned by the Discovery local response
+type localResponse struct {
+	discclient.LocalResponse
+}
+
+// Peers returns a response for a local peer membership query
+func (r *localResponse) Peers() ([]*discclient.Peer, error) {
+	peers, err := r.LocalResponse.Peers()
+	return peers, newServerError(err)
+}
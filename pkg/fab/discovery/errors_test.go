@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"testing"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+func TestServerErrors(t *testing.T) {
+	err := newServerError(errors.New("access denied"))
+	assert.True(t, IsAccessDenied(err))
+	assert.False(t, IsNoEndorsementLayout(err))
+
+	err = newServerError(errors.New("no endorsement combination can be satisfied"))
+	assert.True(t, IsNoEndorsementLayout(err))
+	assert.False(t, IsAccessDenied(err))
+
+	err = newServerError(errors.New("failed constructing descriptor for chaincodes:<name:\"cc1\" >"))
+	assert.True(t, IsNoEndorsementLayout(err))
+
+	err = newServerError(errors.New("some other error"))
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.DiscoveryServerStatus, s.Group)
+	assert.Equal(t, status.Unknown.ToInt32(), s.Code)
+
+	err = multi.New(
+		errors.WithMessage(newTransportError(errors.New("could not connect"), peerAddress), "From target: "+peerAddress),
+		errors.WithMessage(newServerError(errors.New("access denied")), "From target: "+peerAddress),
+	)
+	assert.True(t, IsAccessDenied(err), "expecting access denied to be found in multiple errors")
+	assert.False(t, IsNoEndorsementLayout(err))
+
+	assert.Equal(t, discclient.ErrNotFound, newServerError(discclient.ErrNotFound))
+	assert.Nil(t, newServerError(nil))
+}
+
+func TestTransportErrors(t *testing.T) {
+	err := newTransportError(errors.Wrap(grpcstatus.Error(grpccodes.Unavailable, "unavailable"), "send failed"), peerAddress)
+	s, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.GRPCTransportStatus, s.Group)
+	assert.Equal(t, int32(grpccodes.Unavailable), s.Code)
+
+	err = newTransportError(errors.New("could not connect"), peerAddress)
+	s, ok = status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, status.ClientStatus, s.Group)
+	assert.Equal(t, status.ConnectionFailed.ToInt32(), s.Code)
+}
@@ -20,6 +20,7 @@ import (
 type MockDiscoveryServer struct {
 	localPeersByOrg map[string]*discovery.Peers
 	peersByOrg      map[string]*discovery.Peers
+	config          *discovery.ConfigResult
 }
 
 // MockDiscoveryServerOpt is an option for the MockDiscoveryServer
@@ -39,6 +40,13 @@ func WithLocalPeers(peers ...*MockDiscoveryPeerEndpoint) MockDiscoveryServerOpt
 	}
 }
 
+// WithConfig sets the result of config queries to the MockDiscoveryServer
+func WithConfig(config *discovery.ConfigResult) MockDiscoveryServerOpt {
+	return func(s *MockDiscoveryServer) {
+		s.config = config
+	}
+}
+
 // NewServer returns a new MockDiscoveryServer
 func NewServer(opts ...MockDiscoveryServerOpt) *MockDiscoveryServer {
 	s := &MockDiscoveryServer{}
@@ -136,6 +144,13 @@ func (s *MockDiscoveryServer) getPeerQueryResult(q *discovery.PeerMembershipQuer
 }
 
 func (s *MockDiscoveryServer) getConfigQueryResult(q *discovery.ConfigQuery) *discovery.QueryResult {
+	if s.config != nil {
+		return &discovery.QueryResult{
+			Result: &discovery.QueryResult_ConfigResult{
+				ConfigResult: s.config,
+			},
+		}
+	}
 	return &discovery.QueryResult{
 		Result: &discovery.QueryResult_Error{
 			Error: &discovery.Error{
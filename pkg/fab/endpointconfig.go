@@ -9,27 +9,33 @@ package fab
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"io/ioutil"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	commtls "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/tls"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/limiter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
+	"github.com/spf13/cast"
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
@@ -52,8 +58,13 @@ const (
 	defaultChannelConfigRefreshInterval   = time.Minute * 90
 	defaultChannelMemshpRefreshInterval   = time.Second * 60
 	defaultDiscoveryRefreshInterval       = time.Second * 10
+	defaultHealthCheckInterval            = time.Second * 30
+	defaultHealthCheckTimeout             = time.Second * 5
+	defaultCircuitBreakerReset            = time.Second * 10
 
 	defaultCacheSweepInterval = time.Second * 15
+
+	defaultMaxConnectionsPerEndpoint = 1
 )
 
 //ConfigFromBackend returns endpoint config implementation for given backend
@@ -81,6 +92,16 @@ func ConfigFromBackend(coreBackend core.ConfigBackend) (fab.EndpointConfig, erro
 		return nil, errors.WithMessage(err, "cert pool load failed")
 	}
 
+	config.tlsOptions, err = comm.NewTLSOptions(
+		config.backend.GetString("client.tlsCerts.minVersion"),
+		config.backend.GetString("client.tlsCerts.maxVersion"),
+		config.getStringSlice("client.tlsCerts.cipherSuites"),
+		config.getStringSlice("client.tlsCerts.curvePreferences"),
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid TLS options")
+	}
+
 	//Compile the entityMatchers
 	matchError := config.compileMatchers()
 	if matchError != nil {
@@ -95,6 +116,11 @@ type EndpointConfig struct {
 	backend             *lookup.ConfigLookup
 	networkConfig       *fab.NetworkConfig
 	tlsCertPool         commtls.CertPool
+	tlsOptions          *comm.TLSOptions
+	clientCertsLock     sync.RWMutex
+	clientCerts         []tls.Certificate
+	connConfigLock      sync.RWMutex
+	connConfigs         map[fab.EndpointType]fab.ConnectionConfig
 	networkConfigCached bool
 	peerMatchers        map[int]*regexp.Regexp
 	ordererMatchers     map[int]*regexp.Regexp
@@ -474,9 +500,165 @@ func (c *EndpointConfig) EventServiceType() fab.EventServiceType {
 	}
 }
 
+// EventReconnectConfig returns the parameters of the reconnection of the event service streams
+// (client.eventService.reconnect), which are independent of the retries of the other requests
+func (c *EndpointConfig) EventReconnectConfig() fab.EventReconnectConfig {
+	key := "client.eventService.reconnect"
+	return fab.EventReconnectConfig{
+		InitialDelay:        c.backend.GetDuration(key + ".initialDelay"),
+		TimeBetweenAttempts: c.backend.GetDuration(key + ".timeBetweenAttempts"),
+		BackoffFactor:       cast.ToFloat64(c.backend.GetString(key + ".backoffFactor")),
+		MaxDelay:            c.backend.GetDuration(key + ".maxDelay"),
+		Jitter:              cast.ToFloat64(c.backend.GetString(key + ".jitter")),
+		MaxAttempts:         uint(c.backend.GetInt(key + ".maxAttempts")),
+		MaxElapsedTime:      c.backend.GetDuration(key + ".maxElapsedTime"),
+	}
+}
+
+// ConnectionPoolConfig returns the limits of the GRPC connection pool
+func (c *EndpointConfig) ConnectionPoolConfig() fab.ConnectionPoolConfig {
+	config := fab.ConnectionPoolConfig{
+		MaxConnectionsPerEndpoint: c.backend.GetInt("client.global.connectionPool.maxConnectionsPerEndpoint"),
+		MaxStreamsPerConnection:   c.backend.GetInt("client.global.connectionPool.maxStreamsPerConnection"),
+	}
+	if config.MaxConnectionsPerEndpoint <= 0 {
+		config.MaxConnectionsPerEndpoint = defaultMaxConnectionsPerEndpoint
+	}
+	return config
+}
+
+// ConnectionConfig returns the keepalive and reconnect backoff parameters of the connections to
+// the given type of endpoint (client.global.connection.<peer|orderer|event>). These parameters may be
+// overridden in the GRPC options of a peer or orderer.
+func (c *EndpointConfig) ConnectionConfig(endpointType fab.EndpointType) fab.ConnectionConfig {
+	c.connConfigLock.RLock()
+	config, ok := c.connConfigs[endpointType]
+	c.connConfigLock.RUnlock()
+
+	if ok {
+		return config
+	}
+
+	key := "client.global.connection." + endpointType.String()
+	return fab.ConnectionConfig{
+		KeepAliveTime:    c.backend.GetDuration(key + ".keepAlive.time"),
+		KeepAliveTimeout: c.backend.GetDuration(key + ".keepAlive.timeout"),
+		KeepAlivePermit:  c.backend.GetBool(key + ".keepAlive.permit"),
+		BackoffMaxDelay:  c.backend.GetDuration(key + ".backoff.maxDelay"),
+	}
+}
+
+// SetConnectionConfig overrides the keepalive and reconnect backoff parameters of the
+// connections to the given type of endpoint
+func (c *EndpointConfig) SetConnectionConfig(endpointType fab.EndpointType, config fab.ConnectionConfig) {
+	c.connConfigLock.Lock()
+	defer c.connConfigLock.Unlock()
+
+	if c.connConfigs == nil {
+		c.connConfigs = make(map[fab.EndpointType]fab.ConnectionConfig)
+	}
+	c.connConfigs[endpointType] = config
+}
+
+// DialerName returns the name of the registered dialer (see comm.RegisterDialer) that is used
+// to create the network connections to the peers and orderers. An empty name selects the default dialer.
+func (c *EndpointConfig) DialerName() string {
+	return c.backend.GetString("client.global.dialer")
+}
+
+// CircuitBreakerFailureThreshold returns the number of consecutive failures after which the circuit
+// breaker of an endpoint opens. Zero is returned if not configured, in which case the default applies.
+func (c *EndpointConfig) CircuitBreakerFailureThreshold() int {
+	return c.backend.GetInt("client.global.healthCheck.failureThreshold")
+}
+
+// RetryBudget returns the parameters of the retry budget shared by all of the clients (see retry.Budget),
+// or nil if no retry budget is configured (client.global.retryBudget)
+func (c *EndpointConfig) RetryBudget() *retry.BudgetOpts {
+	if _, ok := c.backend.Lookup("client.global.retryBudget"); !ok {
+		return nil
+	}
+
+	opts := retry.DefaultBudgetOpts
+	if ratio, ok := c.backend.Lookup("client.global.retryBudget.ratio"); ok {
+		opts.Ratio = cast.ToFloat64(ratio)
+	}
+	if minPerSecond, ok := c.backend.Lookup("client.global.retryBudget.minPerSecond"); ok {
+		opts.MinPerSecond = cast.ToFloat64(minPerSecond)
+	}
+	if burst := c.backend.GetInt("client.global.retryBudget.burst"); burst > 0 {
+		opts.Burst = burst
+	}
+	return &opts
+}
+
+// EndorsementLimiter returns the parameters of the adaptive limits of the concurrent endorsements sent
+// to each peer (see limiter.Adaptive), or nil if endorsements aren't limited (client.global.endorsementLimiter)
+func (c *EndpointConfig) EndorsementLimiter() *limiter.Opts {
+	if _, ok := c.backend.Lookup("client.global.endorsementLimiter"); !ok {
+		return nil
+	}
+
+	opts := limiter.DefaultOpts
+	if initialLimit := c.backend.GetInt("client.global.endorsementLimiter.initialLimit"); initialLimit > 0 {
+		opts.InitialLimit = initialLimit
+	}
+	if minLimit := c.backend.GetInt("client.global.endorsementLimiter.minLimit"); minLimit > 0 {
+		opts.MinLimit = minLimit
+	}
+	if maxLimit := c.backend.GetInt("client.global.endorsementLimiter.maxLimit"); maxLimit > 0 {
+		opts.MaxLimit = maxLimit
+	}
+	if ratio, ok := c.backend.Lookup("client.global.endorsementLimiter.backoffRatio"); ok {
+		opts.BackoffRatio = cast.ToFloat64(ratio)
+	}
+	if tolerance, ok := c.backend.Lookup("client.global.endorsementLimiter.latencyTolerance"); ok {
+		opts.LatencyTolerance = cast.ToFloat64(tolerance)
+	}
+	return &opts
+}
+
+// HTTPProxy returns the URL of the HTTP CONNECT proxy through which the peers and orderers are
+// connected to unless they have their own proxy (the http-proxy GRPC option). An empty URL is
+// returned if the endpoints are connected to directly.
+func (c *EndpointConfig) HTTPProxy() string {
+	return c.backend.GetString("client.global.proxy.url")
+}
+
+// SRVRefreshInterval returns the interval after which the DNS SRV records of the peers and orderers
+// whose addresses are SRV names are looked up again (see comm.SRVResolver)
+func (c *EndpointConfig) SRVRefreshInterval() time.Duration {
+	return c.backend.GetDuration("client.global.dnsSRV.refreshInterval")
+}
+
+// TLSOptions returns the TLS versions, cipher suites and curves that apply to all of the connections
+// unless they're overridden in the GRPC options of a peer or orderer
+func (c *EndpointConfig) TLSOptions() *comm.TLSOptions {
+	return c.tlsOptions
+}
+
 // TLSClientCerts loads the client's certs for mutual TLS
 // It checks the config for embedded pem files before looking for cert files
 func (c *EndpointConfig) TLSClientCerts() ([]tls.Certificate, error) {
+	c.clientCertsLock.RLock()
+	clientCerts := c.clientCerts
+	c.clientCertsLock.RUnlock()
+
+	if clientCerts != nil {
+		return clientCerts, nil
+	}
+	return c.loadTLSClientCerts()
+}
+
+// SetTLSClientCerts replaces the client's certs for mutual TLS with the given certs, e.g. after
+// they've been renewed. The certs of the configuration are used again if nil is provided.
+func (c *EndpointConfig) SetTLSClientCerts(certs []tls.Certificate) {
+	c.clientCertsLock.Lock()
+	defer c.clientCertsLock.Unlock()
+	c.clientCerts = certs
+}
+
+func (c *EndpointConfig) loadTLSClientCerts() ([]tls.Certificate, error) {
 	clientConfig, err := c.client()
 	if err != nil {
 		return nil, err
@@ -540,6 +722,34 @@ func (c *EndpointConfig) CryptoConfigPath() string {
 	return pathvar.Subst(c.backend.GetString("client.cryptoconfig.path"))
 }
 
+// getStringSlice returns the list at the given key, which may also be set as a comma separated string
+func (c *EndpointConfig) getStringSlice(key string) []string {
+	value, ok := c.backend.Lookup(key)
+	if !ok {
+		return nil
+	}
+
+	var values []string
+	switch v := value.(type) {
+	case string:
+		values = strings.Split(v, ",")
+	case []string:
+		values = v
+	case []interface{}:
+		for _, item := range v {
+			values = append(values, fmt.Sprint(item))
+		}
+	}
+
+	var result []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
 func (c *EndpointConfig) getTimeout(tType fab.TimeoutType) time.Duration { //nolint
 	var timeout time.Duration
 	switch tType {
@@ -628,6 +838,21 @@ func (c *EndpointConfig) getTimeout(tType fab.TimeoutType) time.Duration { //nol
 		if timeout == 0 {
 			timeout = defaultDiscoveryRefreshInterval
 		}
+	case fab.HealthCheckInterval:
+		timeout = c.backend.GetDuration("client.global.healthCheck.interval")
+		if timeout == 0 {
+			timeout = defaultHealthCheckInterval
+		}
+	case fab.HealthCheckTimeout:
+		timeout = c.backend.GetDuration("client.global.healthCheck.timeout")
+		if timeout == 0 {
+			timeout = defaultHealthCheckTimeout
+		}
+	case fab.CircuitBreakerReset:
+		timeout = c.backend.GetDuration("client.global.healthCheck.breakerReset")
+		if timeout == 0 {
+			timeout = defaultCircuitBreakerReset
+		}
 
 	case fab.CacheSweepInterval: // EXPERIMENTAL - do we need this to be configurable?
 		timeout = c.backend.GetDuration("client.cache.interval.sweep")
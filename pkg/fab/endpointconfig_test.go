@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/limiter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/pathvar"
 	"github.com/stretchr/testify/assert"
 )
@@ -294,6 +295,92 @@ func checkDefaultTimeout(endpointConfig fab.EndpointConfig, t *testing.T, errStr
 	}
 }
 
+func TestConnectionConfig(t *testing.T) {
+	customBackend := getCustomBackend()
+	customBackend.KeyValueMap["client.global.connection.peer.keepAlive.time"] = "30s"
+	customBackend.KeyValueMap["client.global.connection.peer.keepAlive.timeout"] = "10s"
+	customBackend.KeyValueMap["client.global.connection.event.keepAlive.permit"] = true
+	customBackend.KeyValueMap["client.global.connection.event.backoff.maxDelay"] = "5s"
+
+	config, err := ConfigFromBackend(customBackend)
+	if err != nil {
+		t.Fatal("Failed to get endpoint config from backend")
+	}
+	endpointConfig := config.(*EndpointConfig)
+
+	peerConfig := endpointConfig.ConnectionConfig(fab.PeerEndpoint)
+	if peerConfig.KeepAliveTime != 30*time.Second || peerConfig.KeepAliveTimeout != 10*time.Second || peerConfig.KeepAlivePermit {
+		t.Fatalf("Unexpected peer connection config %#v", peerConfig)
+	}
+
+	eventConfig := endpointConfig.ConnectionConfig(fab.EventEndpoint)
+	if !eventConfig.KeepAlivePermit || eventConfig.BackoffMaxDelay != 5*time.Second {
+		t.Fatalf("Unexpected event connection config %#v", eventConfig)
+	}
+
+	if ordererConfig := endpointConfig.ConnectionConfig(fab.OrdererEndpoint); ordererConfig != (fab.ConnectionConfig{}) {
+		t.Fatalf("Expected empty orderer connection config but got %#v", ordererConfig)
+	}
+
+	override := fab.ConnectionConfig{KeepAliveTime: time.Minute, BackoffMaxDelay: time.Second}
+	endpointConfig.SetConnectionConfig(fab.OrdererEndpoint, override)
+	if ordererConfig := endpointConfig.ConnectionConfig(fab.OrdererEndpoint); ordererConfig != override {
+		t.Fatalf("Expected overridden orderer connection config but got %#v", ordererConfig)
+	}
+}
+
+func TestEventReconnectConfig(t *testing.T) {
+	customBackend := getCustomBackend()
+	customBackend.KeyValueMap["client.eventService.reconnect.timeBetweenAttempts"] = "2s"
+	customBackend.KeyValueMap["client.eventService.reconnect.backoffFactor"] = 1.5
+	customBackend.KeyValueMap["client.eventService.reconnect.maxDelay"] = "30s"
+	customBackend.KeyValueMap["client.eventService.reconnect.jitter"] = "0.3"
+	customBackend.KeyValueMap["client.eventService.reconnect.maxElapsedTime"] = "10m"
+
+	config, err := ConfigFromBackend(customBackend)
+	if err != nil {
+		t.Fatal("Failed to get endpoint config from backend")
+	}
+
+	expected := fab.EventReconnectConfig{
+		TimeBetweenAttempts: 2 * time.Second,
+		BackoffFactor:       1.5,
+		MaxDelay:            30 * time.Second,
+		Jitter:              0.3,
+		MaxElapsedTime:      10 * time.Minute,
+	}
+	if reconnectConfig := config.(*EndpointConfig).EventReconnectConfig(); reconnectConfig != expected {
+		t.Fatalf("Unexpected event reconnect config %#v", reconnectConfig)
+	}
+}
+
+func TestEndorsementLimiter(t *testing.T) {
+	config, err := ConfigFromBackend(getCustomBackend())
+	if err != nil {
+		t.Fatal("Failed to get endpoint config from backend")
+	}
+	if opts := config.(*EndpointConfig).EndorsementLimiter(); opts != nil {
+		t.Fatalf("Expecting endorsements not to be limited but got %#v", opts)
+	}
+
+	customBackend := getCustomBackend()
+	customBackend.KeyValueMap["client.global.endorsementLimiter"] = map[string]interface{}{"maxLimit": 100}
+	customBackend.KeyValueMap["client.global.endorsementLimiter.maxLimit"] = 100
+	customBackend.KeyValueMap["client.global.endorsementLimiter.backoffRatio"] = "0.5"
+
+	config, err = ConfigFromBackend(customBackend)
+	if err != nil {
+		t.Fatal("Failed to get endpoint config from backend")
+	}
+
+	expected := limiter.DefaultOpts
+	expected.MaxLimit = 100
+	expected.BackoffRatio = 0.5
+	if opts := config.(*EndpointConfig).EndorsementLimiter(); opts == nil || *opts != expected {
+		t.Fatalf("Unexpected endorsement limiter %#v", opts)
+	}
+}
+
 func TestOrdererConfig(t *testing.T) {
 	endpointConfig, err := ConfigFromBackend(configBackend)
 	if err != nil {
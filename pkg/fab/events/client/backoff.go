@@ -0,0 +1,57 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"math"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
+)
+
+// backoff computes the delay before a connection attempt. The delay starts at initial
+// and is multiplied by factor after every attempt until it reaches max. A random
+// amount of up to +/- jitter (a fraction of the delay) is added so that clients that
+// were disconnected at the same time don't reconnect at the same time. The jitter is
+// taken from the random source, which must be set if jitter is set.
+type backoff struct {
+	initial time.Duration
+	factor  float64
+	max     time.Duration
+	jitter  float64
+	random  *random.Source
+}
+
+func constantBackoff(delay time.Duration) backoff {
+	return backoff{initial: delay, factor: 1}
+}
+
+// delay returns the delay after the given (one-based) failed attempt
+func (b backoff) delay(attempt uint) time.Duration {
+	d := float64(b.initial)
+	if b.factor > 1 && attempt > 1 {
+		d *= math.Pow(b.factor, float64(attempt-1))
+	}
+	if b.max > 0 && d > float64(b.max) {
+		d = float64(b.max)
+	}
+	if b.jitter > 0 {
+		d += d * b.jitter * (2*b.random.Float64() - 1)
+	}
+	return time.Duration(d)
+}
+
+// reconnectBackoff returns the backoff that applies when reconnecting after the connection was lost
+func (p *params) reconnectBackoff() backoff {
+	return backoff{
+		initial: p.timeBetweenConnAttempts,
+		factor:  p.reconnBackoffFactor,
+		max:     p.reconnMaxDelay,
+		jitter:  p.reconnJitter,
+		random:  p.randomSource,
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	b := constantBackoff(time.Second)
+	assert.Equal(t, time.Second, b.delay(1))
+	assert.Equal(t, time.Second, b.delay(10))
+
+	b = backoff{initial: time.Second, factor: 2, max: 5 * time.Second}
+	assert.Equal(t, time.Second, b.delay(1))
+	assert.Equal(t, 2*time.Second, b.delay(2))
+	assert.Equal(t, 4*time.Second, b.delay(3))
+	assert.Equal(t, 5*time.Second, b.delay(4))
+	assert.Equal(t, 5*time.Second, b.delay(100))
+
+	b.jitter = 0.5
+	b.random = random.Default()
+	for i := 0; i < 100; i++ {
+		d := b.delay(3)
+		assert.True(t, d >= 2*time.Second && d <= 6*time.Second, "unexpected delay %s", d)
+	}
+}
+
+func TestReconnectBackoffOpts(t *testing.T) {
+	p := defaultParams()
+	options.Apply(p, []options.Opt{
+		WithTimeBetweenConnectAttempts(2 * time.Second),
+		WithReconnectBackoffFactor(3),
+		WithReconnectMaxDelay(30 * time.Second),
+		WithReconnectJitter(0.1),
+		WithReconnectMaxElapsedTime(5 * time.Minute),
+	})
+
+	assert.Equal(t, backoff{initial: 2 * time.Second, factor: 3, max: 30 * time.Second, jitter: 0.1, random: random.Default()}, p.reconnectBackoff())
+	assert.Equal(t, 5*time.Minute, p.reconnMaxElapsedTime)
+}
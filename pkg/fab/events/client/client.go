@@ -99,7 +99,7 @@ func (c *Client) Connect() error {
 	if c.maxConnAttempts == 1 {
 		return c.connect()
 	}
-	return c.connectWithRetry(c.maxConnAttempts, c.timeBetweenConnAttempts)
+	return c.connectWithRetry(c.maxConnAttempts, constantBackoff(c.timeBetweenConnAttempts), 0)
 }
 
 // CloseIfIdle closes the connection to the event server only if there are no outstanding
@@ -117,6 +117,15 @@ func (c *Client) Close() {
 	c.close(true)
 }
 
+// RegistrationInfo returns a snapshot of the current event registrations
+func (c *Client) RegistrationInfo() (*esdispatcher.RegistrationInfo, error) {
+	regInfoCh := make(chan *esdispatcher.RegistrationInfo)
+	if err := c.Submit(esdispatcher.NewRegistrationInfoEvent(regInfoCh)); err != nil {
+		return nil, err
+	}
+	return <-regInfoCh, nil
+}
+
 func (c *Client) close(force bool) bool {
 	logger.Debugf("Attempting to close event client...")
 
@@ -128,13 +137,11 @@ func (c *Client) close(force bool) bool {
 
 	if !force {
 		// Check if there are any outstanding registrations
-		regInfoCh := make(chan *esdispatcher.RegistrationInfo)
-		err := c.Submit(esdispatcher.NewRegistrationInfoEvent(regInfoCh))
+		regInfo, err := c.RegistrationInfo()
 		if err != nil {
 			logger.Debugf("Submit failed %v", err)
 			return false
 		}
-		regInfo := <-regInfoCh
 
 		logger.Debugf("Outstanding registrations: %d", regInfo.TotalRegistrations)
 
@@ -243,7 +250,7 @@ func (c *Client) t(handlerImp handler, errch chan error) error {
 			} else {
 				logger.Debugf("Received success from disconnect request")
 			}
-		case <-time.After(c.respTimeout):
+		case <-c.clock.After(c.respTimeout):
 			logger.Warnf("Timed out waiting for disconnect response")
 		}
 
@@ -254,14 +261,15 @@ func (c *Client) t(handlerImp handler, errch chan error) error {
 	return nil
 }
 
-func (c *Client) connectWithRetry(maxAttempts uint, timeBetweenAttempts time.Duration) error {
+func (c *Client) connectWithRetry(maxAttempts uint, b backoff, maxElapsedTime time.Duration) error {
 	if c.Stopped() {
 		return errors.New("event client is closed")
 	}
-	if timeBetweenAttempts < time.Second {
-		timeBetweenAttempts = time.Second
+	if b.initial < time.Second {
+		b.initial = time.Second
 	}
 
+	start := c.clock.Now()
 	var attempts uint
 	for {
 		attempts++
@@ -272,7 +280,12 @@ func (c *Client) connectWithRetry(maxAttempts uint, timeBetweenAttempts time.Dur
 				logger.Warnf("maximum connect attempts exceeded")
 				return errors.New("maximum connect attempts exceeded")
 			}
-			time.Sleep(timeBetweenAttempts)
+			delay := b.delay(attempts)
+			if maxElapsedTime > 0 && c.clock.Now().Sub(start)+delay > maxElapsedTime {
+				logger.Warnf("maximum connect time exceeded")
+				return errors.New("maximum connect time exceeded")
+			}
+			c.clock.Sleep(delay)
 		} else {
 			logger.Debugf("... connect succeeded.")
 			return nil
@@ -374,7 +387,7 @@ func (c *Client) monitorConnection() {
 
 func (c *Client) reconnect() {
 	logger.Debugf("Waiting %s before attempting to reconnect event client...", c.reconnInitialDelay)
-	time.Sleep(c.reconnInitialDelay)
+	c.clock.Sleep(c.reconnInitialDelay)
 
 	logger.Debugf("Attempting to reconnect event client...")
 
@@ -386,7 +399,7 @@ func (c *Client) reconnect() {
 		}
 	}
 
-	if err := c.connectWithRetry(c.maxReconnAttempts, c.timeBetweenConnAttempts); err != nil {
+	if err := c.connectWithRetry(c.maxReconnAttempts, c.reconnectBackoff(), c.reconnMaxElapsedTime); err != nil {
 		logger.Warnf("Could not reconnect event client: %s. Closing.", err)
 		c.Close()
 	}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 	"github.com/pkg/errors"
@@ -31,6 +32,7 @@ type Dispatcher struct {
 	context                context.Client
 	chConfig               fab.ChannelCfg
 	connection             api.Connection
+	target                 string
 	connectionRegistration *ConnectionReg
 	connectionProvider     api.ConnectionProvider
 }
@@ -121,11 +123,13 @@ func (ed *Dispatcher) HandleConnectEvent(e esdispatcher.Event) {
 	conn, err := ed.connectionProvider(ed.context, ed.chConfig, peer)
 	if err != nil {
 		logger.Warnf("error creating connection: %s", err)
+		comm.NotifyError(&comm.ConnectionEvent{Target: connectionTarget(peer), Stream: true, Err: err})
 		evt.ErrCh <- errors.WithMessage(err, fmt.Sprintf("could not create client conn"))
 		return
 	}
 
 	ed.connection = conn
+	ed.target = connectionTarget(peer)
 
 	go ed.connection.Receive(eventch)
 
@@ -145,6 +149,7 @@ func (ed *Dispatcher) HandleDisconnectEvent(e esdispatcher.Event) {
 
 	ed.connection.Close()
 	ed.connection = nil
+	comm.NotifyDisconnect(&comm.ConnectionEvent{Target: ed.target, Stream: true})
 
 	evt.Errch <- nil
 }
@@ -168,6 +173,8 @@ func (ed *Dispatcher) HandleConnectedEvent(e esdispatcher.Event) {
 
 	logger.Debugf("Handling connected event: %v", evt)
 
+	comm.NotifyConnect(&comm.ConnectionEvent{Target: ed.target, Stream: true})
+
 	if ed.connectionRegistration != nil && ed.connectionRegistration.Eventch != nil {
 		select {
 		case ed.connectionRegistration.Eventch <- NewConnectionEvent(true, nil):
@@ -186,6 +193,7 @@ func (ed *Dispatcher) HandleDisconnectedEvent(e esdispatcher.Event) {
 	if ed.connection != nil {
 		ed.connection.Close()
 		ed.connection = nil
+		comm.NotifyDisconnect(&comm.ConnectionEvent{Target: ed.target, Stream: true, Err: evt.Err})
 	}
 
 	if ed.connectionRegistration != nil {
@@ -219,3 +227,12 @@ func (ed *Dispatcher) clearConnectionRegistration() {
 		ed.connectionRegistration = nil
 	}
 }
+
+// connectionTarget returns the URL of the event server to which the connection is made, i.e.
+// the event URL of an event endpoint that has one, otherwise the URL of the peer
+func connectionTarget(peer fab.Peer) string {
+	if ep, ok := peer.(api.EventEndpoint); ok && ep.EventURL() != "" {
+		return ep.EventURL()
+	}
+	return peer.URL()
+}
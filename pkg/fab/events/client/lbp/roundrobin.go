@@ -7,10 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package lbp
 
 import (
-	"math/rand"
 	"sync"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 )
 
 // RoundRobin implements a round-robin load-balance policy
@@ -38,7 +38,7 @@ func (lbp *RoundRobin) Choose(peers []fab.Peer) (fab.Peer, error) {
 
 	if lbp.index < 0 {
 		// First time - start at a random index
-		lbp.index = rand.Intn(len(peers))
+		lbp.index = random.Intn(len(peers))
 	} else {
 		lbp.index++
 	}
@@ -9,7 +9,9 @@ package client
 import (
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
 )
 
@@ -17,12 +19,18 @@ type params struct {
 	connEventCh             chan *dispatcher.ConnectionEvent
 	reconnInitialDelay      time.Duration
 	timeBetweenConnAttempts time.Duration
+	reconnMaxDelay          time.Duration
+	reconnMaxElapsedTime    time.Duration
 	respTimeout             time.Duration
+	reconnBackoffFactor     float64
+	reconnJitter            float64
 	eventConsumerBufferSize uint
 	maxConnAttempts         uint
 	maxReconnAttempts       uint
 	permitBlockEvents       bool
 	reconn                  bool
+	clock                   clock.Clock
+	randomSource            *random.Source
 }
 
 func defaultParams() *params {
@@ -33,7 +41,12 @@ func defaultParams() *params {
 		maxReconnAttempts:       0, // Try forever
 		reconnInitialDelay:      0,
 		timeBetweenConnAttempts: 5 * time.Second,
+		reconnBackoffFactor:     2,
+		reconnMaxDelay:          time.Minute,
+		reconnJitter:            0.2,
 		respTimeout:             5 * time.Second,
+		clock:                   clock.Real,
+		randomSource:            random.Default(),
 	}
 }
 
@@ -87,6 +100,47 @@ func WithReconnectInitialDelay(value time.Duration) options.Opt {
 	}
 }
 
+// WithReconnectBackoffFactor sets the factor by which the time between reconnection attempts
+// (see WithTimeBetweenConnectAttempts) is multiplied after every failed attempt. A factor
+// of 1 retries at a constant rate.
+func WithReconnectBackoffFactor(value float64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(reconnectBackoffFactorSetter); ok {
+			setter.SetReconnectBackoffFactor(value)
+		}
+	}
+}
+
+// WithReconnectMaxDelay sets the maximum time between reconnection attempts.
+func WithReconnectMaxDelay(value time.Duration) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(reconnectMaxDelaySetter); ok {
+			setter.SetReconnectMaxDelay(value)
+		}
+	}
+}
+
+// WithReconnectJitter sets the fraction (between 0 and 1) of the time between reconnection
+// attempts that is randomly added or subtracted so that clients that lost their connections
+// at the same time don't reconnect at the same time.
+func WithReconnectJitter(value float64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(reconnectJitterSetter); ok {
+			setter.SetReconnectJitter(value)
+		}
+	}
+}
+
+// WithReconnectMaxElapsedTime sets the time after which the client stops attempting to reconnect
+// to the server after a connection has been lost. If set to 0 then there is no time limit.
+func WithReconnectMaxElapsedTime(value time.Duration) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(reconnectMaxElapsedTimeSetter); ok {
+			setter.SetReconnectMaxElapsedTime(value)
+		}
+	}
+}
+
 // WithConnectionEvent sets the channel that is to receive connection events, i.e. when the client connects and/or
 // disconnects from the channel event service.
 func WithConnectionEvent(value chan *dispatcher.ConnectionEvent) options.Opt {
@@ -115,6 +169,16 @@ func WithResponseTimeout(value time.Duration) options.Opt {
 	}
 }
 
+// WithRandomSource sets the source of the reconnection jitter (the default source by default).
+// The event clients are created with the random source of their context.
+func WithRandomSource(value *random.Source) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(randomSourceSetter); ok {
+			setter.SetRandomSource(value)
+		}
+	}
+}
+
 func (p *params) SetEventConsumerBufferSize(value uint) {
 	p.eventConsumerBufferSize = value
 }
@@ -144,6 +208,26 @@ func (p *params) SetTimeBetweenConnectAttempts(value time.Duration) {
 	p.timeBetweenConnAttempts = value
 }
 
+func (p *params) SetReconnectBackoffFactor(value float64) {
+	logger.Debugf("ReconnectBackoffFactor: %g", value)
+	p.reconnBackoffFactor = value
+}
+
+func (p *params) SetReconnectMaxDelay(value time.Duration) {
+	logger.Debugf("ReconnectMaxDelay: %s", value)
+	p.reconnMaxDelay = value
+}
+
+func (p *params) SetReconnectJitter(value float64) {
+	logger.Debugf("ReconnectJitter: %g", value)
+	p.reconnJitter = value
+}
+
+func (p *params) SetReconnectMaxElapsedTime(value time.Duration) {
+	logger.Debugf("ReconnectMaxElapsedTime: %s", value)
+	p.reconnMaxElapsedTime = value
+}
+
 func (p *params) SetConnectEventCh(value chan *dispatcher.ConnectionEvent) {
 	logger.Debugf("ConnectEventCh: %#v", value)
 	p.connEventCh = value
@@ -159,6 +243,17 @@ func (p *params) PermitBlockEvents() {
 	p.permitBlockEvents = true
 }
 
+// SetClock sets the clock of the client (see dispatcher.WithClock of the event service)
+func (p *params) SetClock(value clock.Clock) {
+	logger.Debugf("Clock: %T", value)
+	p.clock = value
+}
+
+func (p *params) SetRandomSource(value *random.Source) {
+	logger.Debugf("RandomSource: %p", value)
+	p.randomSource = value
+}
+
 type reconnectSetter interface {
 	SetReconnect(value bool)
 }
@@ -175,6 +270,22 @@ type reconnectInitialDelaySetter interface {
 	SetReconnectInitialDelay(value time.Duration)
 }
 
+type reconnectBackoffFactorSetter interface {
+	SetReconnectBackoffFactor(value float64)
+}
+
+type reconnectMaxDelaySetter interface {
+	SetReconnectMaxDelay(value time.Duration)
+}
+
+type reconnectJitterSetter interface {
+	SetReconnectJitter(value float64)
+}
+
+type reconnectMaxElapsedTimeSetter interface {
+	SetReconnectMaxElapsedTime(value time.Duration)
+}
+
 type connectEventChSetter interface {
 	SetConnectEventCh(value chan *dispatcher.ConnectionEvent)
 }
@@ -190,3 +301,7 @@ type responseTimeoutSetter interface {
 type permitBlockEventsSetter interface {
 	PermitBlockEvents()
 }
+
+type randomSourceSetter interface {
+	SetRandomSource(value *random.Source)
+}
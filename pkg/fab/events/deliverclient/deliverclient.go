@@ -7,24 +7,27 @@ SPDX-License-Identifier: Apache-2.0
 package deliverclient
 
 import (
+	reqContext "context"
 	"math"
-	"time"
 
 	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	fabcontext "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
 	deliverconn "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/connection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/dispatcher"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/endpoint"
+	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 	"github.com/pkg/errors"
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
+var tracer = tracing.NewTracer("fabsdk/fab")
 
 // deliverProvider is the connection provider used for connecting to the Deliver service
 var deliverProvider = func(context fabcontext.Client, chConfig fab.ChannelCfg, peer fab.Peer) (api.Connection, error) {
@@ -53,6 +56,9 @@ type Client struct {
 // New returns a new deliver event client
 func New(context fabcontext.Client, chConfig fab.ChannelCfg, opts ...options.Opt) (*Client, error) {
 	params := defaultParams()
+
+	// The timeouts and the reconnection jitter are taken from the clock and the random source of the context
+	opts = append([]options.Opt{esdispatcher.WithClock(context.Clock()), client.WithRandomSource(context.RandomSource())}, opts...)
 	options.Apply(params, opts)
 
 	// Use a context that returns a custom Discovery Provider which
@@ -76,9 +82,12 @@ func New(context fabcontext.Client, chConfig fab.ChannelCfg, opts ...options.Opt
 	return client, nil
 }
 
-func (c *Client) seek() error {
+func (c *Client) seek() (err error) {
 	logger.Debugf("Sending seek request....")
 
+	_, span := tracer.Start(reqContext.Background(), "deliver.Seek")
+	defer func() { tracing.End(span, err) }()
+
 	seekInfo, err := c.seekInfo()
 	if err != nil {
 		return err
@@ -91,7 +100,7 @@ func (c *Client) seek() error {
 	}
 	select {
 	case err = <-errch:
-	case <-time.After(c.respTimeout):
+	case <-c.clock.After(c.respTimeout):
 		err = errors.New("timeout waiting for deliver status response")
 	}
 
@@ -9,6 +9,7 @@ package deliverclient
 import (
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
@@ -19,6 +20,7 @@ type params struct {
 	seekType     seek.Type
 	fromBlock    uint64
 	respTimeout  time.Duration
+	clock        clock.Clock
 }
 
 func defaultParams() *params {
@@ -26,6 +28,7 @@ func defaultParams() *params {
 		connProvider: deliverFilteredProvider,
 		seekType:     seek.Newest,
 		respTimeout:  5 * time.Second,
+		clock:        clock.Real,
 	}
 }
 
@@ -81,3 +84,8 @@ func (p *params) SetResponseTimeout(value time.Duration) {
 	logger.Debugf("ResponseTimeout: %s", value)
 	p.respTimeout = value
 }
+
+func (p *params) SetClock(value clock.Clock) {
+	logger.Debugf("Clock: %T", value)
+	p.clock = value
+}
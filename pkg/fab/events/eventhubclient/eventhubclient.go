@@ -7,8 +7,6 @@ SPDX-License-Identifier: Apache-2.0
 package eventhubclient
 
 import (
-	"time"
-
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -16,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/eventhubclient/connection"
+	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
@@ -43,6 +42,9 @@ type Client struct {
 func New(context context.Client, chConfig fab.ChannelCfg, opts ...options.Opt) (*Client, error) {
 	params := defaultParams()
 
+	// The timeouts and the reconnection jitter are taken from the clock and the random source of the context
+	opts = append([]options.Opt{esdispatcher.WithClock(context.Clock()), client.WithRandomSource(context.RandomSource())}, opts...)
+
 	// FIXME: Temporarily set the default to block events since Fabric 1.0 does
 	// not support filtered block events
 	opts = append(opts, client.WithBlockEvents())
@@ -82,7 +84,7 @@ func (c *Client) registerInterests() error {
 	var err error
 	select {
 	case err = <-errch:
-	case <-time.After(c.respTimeout):
+	case <-c.clock.After(c.respTimeout):
 		err = errors.New("timeout waiting for register interests response")
 	}
 
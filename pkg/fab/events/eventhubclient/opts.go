@@ -9,6 +9,7 @@ package eventhubclient
 import (
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/api"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
@@ -20,6 +21,7 @@ type params struct {
 	connProvider api.ConnectionProvider
 	interests    []*pb.Interest
 	respTimeout  time.Duration
+	clock        clock.Clock
 }
 
 func defaultParams() *params {
@@ -27,6 +29,7 @@ func defaultParams() *params {
 		connProvider: ehConnProvider,
 		interests:    filteredBlockInterests,
 		respTimeout:  5 * time.Second,
+		clock:        clock.Real,
 	}
 }
 
@@ -35,6 +38,11 @@ func (p *params) SetResponseTimeout(value time.Duration) {
 	p.respTimeout = value
 }
 
+func (p *params) SetClock(value clock.Clock) {
+	logger.Debugf("Clock: %T", value)
+	p.clock = value
+}
+
 // SetConnectionProvider is used only for testing
 func (p *params) SetConnectionProvider(connProvider api.ConnectionProvider) {
 	logger.Debugf("ConnProvider: %#v", connProvider)
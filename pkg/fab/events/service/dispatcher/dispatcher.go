@@ -11,12 +11,11 @@ import (
 	"reflect"
 	"regexp"
 	"sync/atomic"
-	"time"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/protopool"
 	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
 	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
@@ -305,9 +304,24 @@ func (ed *Dispatcher) HandleBlock(block *cb.Block, sourceURL string) {
 		logger.Error(err.Error())
 		return
 	}
+	recordBlock(block, sourceURL)
+
+	if ed.configBlockHandler != nil && isConfigBlock(block) {
+		go ed.configBlockHandler(block.Header.Number)
+	}
 
 	ed.publishBlockEvents(block, sourceURL)
-	ed.publishFilteredBlockEvents(toFilteredBlock(block), sourceURL)
+
+	// Extracting the filtered block is only worthwhile if somebody is interested in it
+	if ed.hasFilteredBlockConsumers() {
+		ed.publishFilteredBlockEvents(toFilteredBlock(block), sourceURL)
+	}
+}
+
+// hasFilteredBlockConsumers returns true if there are registrations for filtered block,
+// transaction status or chaincode events
+func (ed *Dispatcher) hasFilteredBlockConsumers() bool {
+	return len(ed.filteredBlockRegistrations) > 0 || len(ed.txRegistrations) > 0 || len(ed.ccRegistrations) > 0
 }
 
 // HandleFilteredBlock handles a filtered block event
@@ -318,6 +332,11 @@ func (ed *Dispatcher) HandleFilteredBlock(fblock *pb.FilteredBlock, sourceURL st
 		logger.Error(err.Error())
 		return
 	}
+	recordFilteredBlock(sourceURL)
+
+	if ed.configBlockHandler != nil && isConfigFilteredBlock(fblock) {
+		go ed.configBlockHandler(fblock.Number)
+	}
 
 	logger.Debugf("Publishing filtered block event...")
 	ed.publishFilteredBlockEvents(fblock, sourceURL)
@@ -375,6 +394,8 @@ func (ed *Dispatcher) unregisterTXEvents(registration *TxStatusReg) error {
 }
 
 func (ed *Dispatcher) publishBlockEvents(block *cb.Block, sourceURL string) {
+	// One event is created per block and shared by all of the registrations, which must treat it as read-only
+	event := NewBlockEvent(block, sourceURL)
 	for _, reg := range ed.blockRegistrations {
 		if !reg.Filter(block) {
 			logger.Debugf("Not sending block event for block #%d since it was filtered out.", block.Header.Number)
@@ -383,16 +404,16 @@ func (ed *Dispatcher) publishBlockEvents(block *cb.Block, sourceURL string) {
 
 		if ed.eventConsumerTimeout < 0 {
 			select {
-			case reg.Eventch <- NewBlockEvent(block, sourceURL):
+			case reg.Eventch <- event:
 			default:
 				logger.Warnf("Unable to send to block event channel.")
 			}
 		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewBlockEvent(block, sourceURL)
+			reg.Eventch <- event
 		} else {
 			select {
-			case reg.Eventch <- NewBlockEvent(block, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
+			case reg.Eventch <- event:
+			case <-ed.clock.After(ed.eventConsumerTimeout):
 				logger.Warnf("Timed out sending block event.")
 			}
 		}
@@ -428,19 +449,20 @@ func (ed *Dispatcher) publishFilteredBlockEvents(fblock *pb.FilteredBlock, sourc
 }
 
 func checkFilteredBlockRegistrations(ed *Dispatcher, fblock *pb.FilteredBlock, sourceURL string) {
+	event := NewFilteredBlockEvent(fblock, sourceURL)
 	for _, reg := range ed.filteredBlockRegistrations {
 		if ed.eventConsumerTimeout < 0 {
 			select {
-			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
+			case reg.Eventch <- event:
 			default:
 				logger.Warnf("Unable to send to filtered block event channel.")
 			}
 		} else if ed.eventConsumerTimeout == 0 {
-			reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL)
+			reg.Eventch <- event
 		} else {
 			select {
-			case reg.Eventch <- NewFilteredBlockEvent(fblock, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
+			case reg.Eventch <- event:
+			case <-ed.clock.After(ed.eventConsumerTimeout):
 				logger.Warnf("Timed out sending filtered block event.")
 			}
 		}
@@ -450,44 +472,50 @@ func checkFilteredBlockRegistrations(ed *Dispatcher, fblock *pb.FilteredBlock, s
 func (ed *Dispatcher) publishTxStatusEvents(tx *pb.FilteredTransaction, blockNum uint64, sourceURL string) {
 	logger.Debugf("Publishing Tx Status event for TxID [%s]...", tx.Txid)
 	if reg, ok := ed.txRegistrations[tx.Txid]; ok {
-		logger.Debugf("Sending Tx Status event for TxID [%s] to registrant...", tx.Txid)
+		txLogger := logger.With(logging.TxID(tx.Txid))
+		txLogger.Debug("Sending Tx Status event to registrant...")
 
 		if ed.eventConsumerTimeout < 0 {
 			select {
 			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
 			default:
-				logger.Warnf("Unable to send to Tx Status event channel.")
+				txLogger.Warnf("Unable to send to Tx Status event channel.")
 			}
 		} else if ed.eventConsumerTimeout == 0 {
 			reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL)
 		} else {
 			select {
 			case reg.Eventch <- NewTxStatusEvent(tx.Txid, tx.TxValidationCode, blockNum, sourceURL):
-			case <-time.After(ed.eventConsumerTimeout):
-				logger.Warnf("Timed out sending Tx Status event.")
+			case <-ed.clock.After(ed.eventConsumerTimeout):
+				txLogger.Warnf("Timed out sending Tx Status event.")
 			}
 		}
 	}
 }
 
 func (ed *Dispatcher) publishCCEvents(ccEvent *pb.ChaincodeEvent, blockNum uint64, sourceURL string) {
+	var event *fab.CCEvent
 	for _, reg := range ed.ccRegistrations {
 		logger.Debugf("Matching CCEvent[%s,%s] against Reg[%s,%s] ...", ccEvent.ChaincodeId, ccEvent.EventName, reg.ChaincodeID, reg.EventFilter)
 		if reg.ChaincodeID == ccEvent.ChaincodeId && reg.EventRegExp.MatchString(ccEvent.EventName) {
 			logger.Debugf("... matched CCEvent[%s,%s] against Reg[%s,%s]", ccEvent.ChaincodeId, ccEvent.EventName, reg.ChaincodeID, reg.EventFilter)
 
+			if event == nil {
+				event = NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
+			}
+
 			if ed.eventConsumerTimeout < 0 {
 				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
+				case reg.Eventch <- event:
 				default:
 					logger.Warnf("Unable to send to CC event channel.")
 				}
 			} else if ed.eventConsumerTimeout == 0 {
-				reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL)
+				reg.Eventch <- event
 			} else {
 				select {
-				case reg.Eventch <- NewChaincodeEvent(ccEvent.ChaincodeId, ccEvent.EventName, ccEvent.TxId, ccEvent.Payload, blockNum, sourceURL):
-				case <-time.After(ed.eventConsumerTimeout):
+				case reg.Eventch <- event:
+				case <-ed.clock.After(ed.eventConsumerTimeout):
 					logger.Warnf("Timed out sending CC event.")
 				}
 			}
@@ -510,6 +538,47 @@ func getCCKey(ccID, eventFilter string) string {
 	return ccID + "/" + eventFilter
 }
 
+// isConfigBlock returns true if the given block contains a channel configuration update
+func isConfigBlock(block *cb.Block) bool {
+	if block.Data == nil || len(block.Data.Data) != 1 {
+		// A config block contains a single transaction
+		return false
+	}
+
+	env, err := protopool.Envelope(block.Data.Data[0])
+	if err != nil {
+		return false
+	}
+	defer protopool.Release(env)
+
+	payload, err := protopool.Payload(env)
+	if err != nil {
+		return false
+	}
+	defer protopool.Release(payload)
+
+	if payload.Header == nil {
+		return false
+	}
+	channelHeader, err := protopool.ChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return false
+	}
+	defer protopool.Release(channelHeader)
+
+	return cb.HeaderType(channelHeader.Type) == cb.HeaderType_CONFIG
+}
+
+// isConfigFilteredBlock returns true if the given filtered block contains a channel configuration update
+func isConfigFilteredBlock(fblock *pb.FilteredBlock) bool {
+	for _, tx := range fblock.FilteredTransactions {
+		if tx.Type == cb.HeaderType_CONFIG {
+			return true
+		}
+	}
+	return false
+}
+
 func toFilteredBlock(block *cb.Block) *pb.FilteredBlock {
 	var channelID string
 	var filteredTxs []*pb.FilteredTransaction
@@ -533,24 +602,26 @@ func toFilteredBlock(block *cb.Block) *pb.FilteredBlock {
 }
 
 func getFilteredTx(data []byte, txValidationCode pb.TxValidationCode) (*pb.FilteredTransaction, string, error) {
-	env, err := utils.GetEnvelopeFromBlock(data)
+	env, err := protopool.Envelope(data)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "error extracting Envelope from block")
 	}
-	if env == nil {
-		return nil, "", errors.New("nil envelope")
-	}
+	defer protopool.Release(env)
 
-	payload, err := utils.GetPayload(env)
+	payload, err := protopool.Payload(env)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "error extracting Payload from envelope")
 	}
+	defer protopool.Release(payload)
 
-	channelHeaderBytes := payload.Header.ChannelHeader
-	channelHeader := &cb.ChannelHeader{}
-	if err := proto.Unmarshal(channelHeaderBytes, channelHeader); err != nil {
+	if payload.Header == nil {
+		return nil, "", errors.New("nil payload header")
+	}
+	channelHeader, err := protopool.ChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
 		return nil, "", errors.Wrap(err, "error extracting ChannelHeader from payload")
 	}
+	defer protopool.Release(channelHeader)
 
 	filteredTx := &pb.FilteredTransaction{
 		Type:             cb.HeaderType(channelHeader.Type),
@@ -572,22 +643,31 @@ func getFilteredTransactionActions(data []byte) (*pb.FilteredTransaction_Transac
 	actions := &pb.FilteredTransaction_TransactionActions{
 		TransactionActions: &pb.FilteredTransactionActions{},
 	}
-	tx, err := utils.GetTransaction(data)
+	tx, err := protopool.Transaction(data)
 	if err != nil {
 		return nil, errors.Wrap(err, "error unmarshalling transaction payload")
 	}
-	chaincodeActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	defer protopool.Release(tx)
+
+	chaincodeActionPayload, err := protopool.ChaincodeActionPayload(tx.Actions[0].Payload)
 	if err != nil {
 		return nil, errors.Wrap(err, "error unmarshalling chaincode action payload")
 	}
-	propRespPayload, err := utils.GetProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
+	defer protopool.Release(chaincodeActionPayload)
+
+	propRespPayload, err := protopool.ProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
 	if err != nil {
 		return nil, errors.Wrap(err, "error unmarshalling response payload")
 	}
-	ccAction, err := utils.GetChaincodeAction(propRespPayload.Extension)
+	defer protopool.Release(propRespPayload)
+
+	ccAction, err := protopool.ChaincodeAction(propRespPayload.Extension)
 	if err != nil {
 		return nil, errors.Wrap(err, "error unmarshalling chaincode action")
 	}
+	defer protopool.Release(ccAction)
+
+	// The chaincode event is passed on to the consumers, so it isn't pooled
 	ccEvent, err := utils.GetChaincodeEvents(ccAction.Events)
 	if err != nil {
 		return nil, errors.Wrap(err, "error getting chaincode events")
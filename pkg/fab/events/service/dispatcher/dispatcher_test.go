@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/blockfilter/headertypefilter"
@@ -87,6 +88,66 @@ func TestBlockEvents(t *testing.T) {
 	}
 }
 
+// TestSharedBlockEvents ensures that all of the registrations receive the same
+// block event and that a modifiable copy of the event may be made
+func TestSharedBlockEvents(t *testing.T) {
+	dispatcher := New(WithEventConsumerTimeout(2 * time.Second))
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	regch := make(chan fab.Registration)
+	errch := make(chan error)
+
+	eventch1 := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, eventch1, regch, errch)
+	reg1 := getRegistration(regch, errch, t)
+
+	eventch2 := make(chan *fab.BlockEvent, 10)
+	dispatcherEventch <- NewRegisterBlockEvent(blockfilter.AcceptAny, eventch2, regch, errch)
+	reg2 := getRegistration(regch, errch, t)
+
+	dispatcherEventch <- NewBlockEvent(servicemocks.NewBlockProducer().NewBlock("testchannel",
+		servicemocks.NewTransaction("1234", pb.TxValidationCode_VALID, cb.HeaderType_ENDORSER_TRANSACTION)), sourceURL,
+	)
+
+	var events []*fab.BlockEvent
+	for _, eventch := range []chan *fab.BlockEvent{eventch1, eventch2} {
+		select {
+		case event := <-eventch:
+			events = append(events, event)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for block event")
+		}
+	}
+	if events[0] != events[1] {
+		t.Fatalf("expecting the registrations to share the block event")
+	}
+
+	eventCopy := events[0].Copy()
+	if eventCopy.Block == events[0].Block || !proto.Equal(eventCopy.Block, events[0].Block) {
+		t.Fatalf("expecting a deep copy of the block")
+	}
+	eventCopy.Block.Header.Number++
+	if eventCopy.Block.Header.Number == events[0].Block.Header.Number {
+		t.Fatalf("expecting the shared block to remain unchanged")
+	}
+
+	dispatcherEventch <- NewUnregisterEvent(reg1)
+	dispatcherEventch <- NewUnregisterEvent(reg2)
+
+	stopResp := make(chan error)
+	dispatcherEventch <- NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+}
+
 func TestBlockEventsWithFilter(t *testing.T) {
 	channelID := "testchannel"
 	dispatcher := New()
@@ -820,3 +881,59 @@ func checkCCEvent(t *testing.T, event *fab.CCEvent, expectedCCID string, expecte
 		t.Fatalf("expecting one of [%v] but received [%s]", expectedEventNames, event.EventName)
 	}
 }
+
+func TestConfigBlockHandler(t *testing.T) {
+	configBlocks := make(chan uint64, 10)
+	dispatcher := New(WithConfigBlockHandler(func(blockNum uint64) { configBlocks <- blockNum }))
+	if err := dispatcher.Start(); err != nil {
+		t.Fatalf("Error starting dispatcher: %s", err)
+	}
+
+	dispatcherEventch, err := dispatcher.EventCh()
+	if err != nil {
+		t.Fatalf("Error getting event channel from dispatcher: %s", err)
+	}
+
+	eventProducer := servicemocks.NewBlockProducer()
+	dispatcherEventch <- NewBlockEvent(eventProducer.NewBlock("testchannel",
+		servicemocks.NewTransaction("1234", pb.TxValidationCode_VALID, cb.HeaderType_ENDORSER_TRANSACTION)), sourceURL,
+	)
+	configBlock := eventProducer.NewBlock("testchannel",
+		servicemocks.NewTransaction("5678", pb.TxValidationCode_VALID, cb.HeaderType_CONFIG))
+	dispatcherEventch <- NewBlockEvent(configBlock, sourceURL)
+
+	select {
+	case blockNum := <-configBlocks:
+		if blockNum != configBlock.Header.Number {
+			t.Fatalf("expecting config block %d but got %d", configBlock.Header.Number, blockNum)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for config block handler")
+	}
+
+	configTx := servicemocks.NewFilteredTx("9012", pb.TxValidationCode_VALID)
+	configTx.Type = cb.HeaderType_CONFIG
+	fblock := eventProducer.NewFilteredBlock("testchannel", configTx)
+	dispatcherEventch <- NewFilteredBlockEvent(fblock, sourceURL)
+
+	select {
+	case blockNum := <-configBlocks:
+		if blockNum != fblock.Number {
+			t.Fatalf("expecting config block %d but got %d", fblock.Number, blockNum)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for config block handler")
+	}
+
+	select {
+	case blockNum := <-configBlocks:
+		t.Fatalf("unexpected config block %d", blockNum)
+	default:
+	}
+
+	stopResp := make(chan error)
+	dispatcherEventch <- NewStopEvent(stopResp)
+	if err := <-stopResp; err != nil {
+		t.Fatalf("Error stopping dispatcher: %s", err)
+	}
+}
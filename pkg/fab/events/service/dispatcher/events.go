@@ -58,11 +58,11 @@ type UnregisterEvent struct {
 
 // RegistrationInfo contains a snapshot of the current event registrations
 type RegistrationInfo struct {
-	TotalRegistrations            int
-	NumBlockRegistrations         int
-	NumFilteredBlockRegistrations int
-	NumCCRegistrations            int
-	NumTxStatusRegistrations      int
+	TotalRegistrations            int `json:"total"`
+	NumBlockRegistrations         int `json:"block"`
+	NumFilteredBlockRegistrations int `json:"filteredBlock"`
+	NumCCRegistrations            int `json:"chaincode"`
+	NumTxStatusRegistrations      int `json:"txStatus"`
 }
 
 // RegistrationInfoEvent requests registration information
@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dispatcher
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/protopool"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+var (
+	blocksReceived = metrics.NewCounter(metrics.CounterOpts{
+		Subsystem:  "events",
+		Name:       "blocks_total",
+		Help:       "The number of blocks (or filtered blocks) received from the event service.",
+		LabelNames: []string{"source", "type"},
+	})
+	blockLag = metrics.NewGauge(metrics.GaugeOpts{
+		Subsystem:  "events",
+		Name:       "block_lag_seconds",
+		Help:       "The time between the creation of the first transaction of the last block received and the receipt of the block.",
+		LabelNames: []string{"source"},
+	})
+)
+
+// recordBlock reports the receipt of a block along with its lag, which is measured from the
+// timestamp of the block's first transaction
+func recordBlock(block *cb.Block, sourceURL string) {
+	blocksReceived.With(sourceURL, "block").Add(1)
+
+	if timestamp, ok := blockTimestamp(block); ok {
+		blockLag.With(sourceURL).Set(time.Since(timestamp).Seconds())
+	}
+}
+
+// recordFilteredBlock reports the receipt of a filtered block. Filtered blocks don't contain
+// timestamps so the lag can't be measured.
+func recordFilteredBlock(sourceURL string) {
+	blocksReceived.With(sourceURL, "filtered").Add(1)
+}
+
+func blockTimestamp(block *cb.Block) (time.Time, bool) {
+	if block.Data == nil || len(block.Data.Data) == 0 {
+		return time.Time{}, false
+	}
+
+	env, err := protopool.Envelope(block.Data.Data[0])
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer protopool.Release(env)
+
+	payload, err := protopool.Payload(env)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer protopool.Release(payload)
+
+	if payload.Header == nil {
+		return time.Time{}, false
+	}
+	channelHeader, err := protopool.ChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer protopool.Release(channelHeader)
+
+	if channelHeader.Timestamp == nil {
+		return time.Time{}, false
+	}
+	timestamp, err := ptypes.Timestamp(channelHeader.Timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return timestamp, true
+}
@@ -9,18 +9,22 @@ package dispatcher
 import (
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 )
 
 type params struct {
 	eventConsumerBufferSize uint
 	eventConsumerTimeout    time.Duration
+	configBlockHandler      func(blockNum uint64)
+	clock                   clock.Clock
 }
 
 func defaultParams() *params {
 	return &params{
 		eventConsumerBufferSize: 100,
 		eventConsumerTimeout:    500 * time.Millisecond,
+		clock:                   clock.Real,
 	}
 }
 
@@ -45,6 +49,26 @@ func WithEventConsumerTimeout(value time.Duration) options.Opt {
 	}
 }
 
+// WithConfigBlockHandler sets a handler that is invoked (in a separate Go routine) whenever a
+// block (or filtered block) that contains a channel configuration update is received.
+func WithConfigBlockHandler(value func(blockNum uint64)) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(configBlockHandlerSetter); ok {
+			setter.SetConfigBlockHandler(value)
+		}
+	}
+}
+
+// WithClock sets the clock on which the timeouts of the event service elapse (the system clock by default).
+// The event clients are created with the clock of their context.
+func WithClock(value clock.Clock) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(clockSetter); ok {
+			setter.SetClock(value)
+		}
+	}
+}
+
 type eventConsumerBufferSizeSetter interface {
 	SetEventConsumerBufferSize(value uint)
 }
@@ -53,6 +77,14 @@ type eventEventConsumerTimeoutSetter interface {
 	SetEventConsumerTimeout(value time.Duration)
 }
 
+type configBlockHandlerSetter interface {
+	SetConfigBlockHandler(value func(blockNum uint64))
+}
+
+type clockSetter interface {
+	SetClock(value clock.Clock)
+}
+
 func (p *params) SetEventConsumerBufferSize(value uint) {
 	logger.Debugf("EventConsumerBufferSize: %d", value)
 	p.eventConsumerBufferSize = value
@@ -62,3 +94,13 @@ func (p *params) SetEventConsumerTimeout(value time.Duration) {
 	logger.Debugf("EventConsumerTimeout: %s", value)
 	p.eventConsumerTimeout = value
 }
+
+func (p *params) SetConfigBlockHandler(value func(blockNum uint64)) {
+	logger.Debugf("ConfigBlockHandler: %t", value != nil)
+	p.configBlockHandler = value
+}
+
+func (p *params) SetClock(value clock.Clock) {
+	logger.Debugf("Clock: %T", value)
+	p.clock = value
+}
@@ -0,0 +1,365 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultCouchDBTimeout = 10 * time.Second
+	// maxConflictRetries is the number of times a store is retried when the document was
+	// updated concurrently (e.g. by another instance of the application)
+	maxConflictRetries = 3
+)
+
+// CouchDBKeyValueStore stores each value into a separate document of a CouchDB database, so that
+// the values can be shared by several instances of an application. The value is stored in the
+// "value" field of the document whose ID is derived from the key by the KeySerializer.
+//
+// This is the SDK's CouchDB client; the CouchDB wallet of the gateway package is built on it.
+type CouchDBKeyValueStore struct {
+	url           string
+	username      string
+	password      string
+	client        *http.Client
+	stringData    bool
+	keySerializer KeySerializer
+	marshaller    Marshaller
+	unmarshaller  Unmarshaller
+}
+
+// CouchDBKeyValueStoreOptions allow overriding store defaults
+type CouchDBKeyValueStoreOptions struct {
+	// CouchDB URL (e.g. http://localhost:5984), mandatory
+	URL string
+	// Database name, mandatory. The database is created if it doesn't exist.
+	Database string
+	// Optional. Credentials used for basic authentication.
+	Username string
+	Password string
+	// Optional. Timeout of the requests to CouchDB (10s by default).
+	Timeout time.Duration
+	// Optional. HTTP client used to access CouchDB, e.g. to configure TLS. Timeout doesn't
+	// apply to a client that is provided.
+	HTTPClient *http.Client
+	// Optional. Stores the value as a string in the "data" field of the documents instead of
+	// base64-encoded in the "value" field. This is the document format of the CouchDB wallets of
+	// the Fabric Node SDK.
+	StringData bool
+	// Optional. If not provided, the key (which must be a string) is used as the document ID.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+}
+
+type couchDBDocument struct {
+	ID    string  `json:"_id"`
+	Rev   string  `json:"_rev,omitempty"`
+	Value []byte  `json:"value"`
+	Data  *string `json:"data"`
+}
+
+// NewCouchDB creates a new instance of CouchDBKeyValueStore using provided options
+func NewCouchDB(opts *CouchDBKeyValueStoreOptions) (*CouchDBKeyValueStore, error) {
+	if opts == nil {
+		return nil, errors.New("CouchDBKeyValueStoreOptions is nil")
+	}
+	if opts.URL == "" || opts.Database == "" {
+		return nil, errors.New("CouchDBKeyValueStore URL and database are required")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultCouchDBTimeout
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: opts.Timeout}
+	}
+	if opts.KeySerializer == nil {
+		opts.KeySerializer = func(key interface{}) (string, error) {
+			keyString, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			return keyString, nil
+		}
+	}
+	if opts.Marshaller == nil {
+		opts.Marshaller = defaultMarshaller
+	}
+	if opts.Unmarshaller == nil {
+		opts.Unmarshaller = defaultUnmarshaller
+	}
+
+	s := &CouchDBKeyValueStore{
+		url:           strings.TrimSuffix(opts.URL, "/") + "/" + url.PathEscape(opts.Database),
+		username:      opts.Username,
+		password:      opts.Password,
+		client:        opts.HTTPClient,
+		stringData:    opts.StringData,
+		keySerializer: opts.KeySerializer,
+		marshaller:    opts.Marshaller,
+		unmarshaller:  opts.Unmarshaller,
+	}
+	if err := s.createDatabase(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (s *CouchDBKeyValueStore) Load(key interface{}) (interface{}, error) {
+	id, err := s.keySerializer(key)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	value := s.value(doc)
+	if value == nil {
+		return nil, core.ErrKeyValueNotFound
+	}
+	return s.unmarshaller(value)
+}
+
+// Store sets the value for the key.
+func (s *CouchDBKeyValueStore) Store(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	id, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; ; i++ {
+		doc, err := s.get(id)
+		if err != nil {
+			return err
+		}
+		var rev string
+		if doc != nil {
+			rev = doc.Rev
+		}
+
+		status, err := s.put(id, rev, valueBytes)
+		if err != nil {
+			return err
+		}
+		if status != http.StatusConflict || i == maxConflictRetries {
+			return checkStatus(status, "storing document "+id)
+		}
+	}
+}
+
+// StoreIfAbsent sets the value for the key unless a value is already stored for the key, in which
+// case false is returned. The document is created without a revision, which CouchDB rejects if the
+// document exists, so that concurrent callers agree on the stored value.
+func (s *CouchDBKeyValueStore) StoreIfAbsent(key interface{}, value interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if value == nil {
+		return false, errors.New("value is nil")
+	}
+	id, err := s.keySerializer(key)
+	if err != nil {
+		return false, err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return false, err
+	}
+
+	status, err := s.put(id, "", valueBytes)
+	if err != nil {
+		return false, err
+	}
+	if status == http.StatusConflict {
+		return false, nil
+	}
+	if err := checkStatus(status, "creating document "+id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete deletes the value for a key.
+func (s *CouchDBKeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	id, err := s.keySerializer(key)
+	if err != nil {
+		return err
+	}
+	doc, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	if doc == nil {
+		// Doesn't exist, OK
+		return nil
+	}
+
+	resp, err := s.do(http.MethodDelete, s.docURL(id)+"?rev="+url.QueryEscape(doc.Rev), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return checkStatus(resp.StatusCode, "deleting document "+id)
+}
+
+// Keys returns the IDs of the documents of the database (i.e. the serialized keys), excluding
+// design documents
+func (s *CouchDBKeyValueStore) Keys() ([]string, error) {
+	resp, err := s.do(http.MethodGet, s.url+"/_all_docs", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp.StatusCode, "listing documents"); err != nil {
+		return nil, err
+	}
+
+	var allDocs struct {
+		Rows []struct {
+			ID string `json:"id"`
+		} `json:"rows"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&allDocs); err != nil {
+		return nil, errors.Wrap(err, "decoding document list failed")
+	}
+	var ids []string
+	for _, row := range allDocs.Rows {
+		if !strings.HasPrefix(row.ID, "_design/") {
+			ids = append(ids, row.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *CouchDBKeyValueStore) createDatabase() error {
+	resp, err := s.do(http.MethodPut, s.url, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		// The database already exists
+		return nil
+	}
+	return checkStatus(resp.StatusCode, "creating database")
+}
+
+// get returns the document with the given ID or nil if it doesn't exist
+func (s *CouchDBKeyValueStore) get(id string) (*couchDBDocument, error) {
+	resp, err := s.do(http.MethodGet, s.docURL(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err := checkStatus(resp.StatusCode, "loading document "+id); err != nil {
+		return nil, err
+	}
+	doc := &couchDBDocument{}
+	if err := json.NewDecoder(resp.Body).Decode(doc); err != nil {
+		return nil, errors.Wrapf(err, "decoding document %s failed", id)
+	}
+	return doc, nil
+}
+
+// value returns the value of a document or nil if there is none
+func (s *CouchDBKeyValueStore) value(doc *couchDBDocument) []byte {
+	if doc == nil {
+		return nil
+	}
+	if s.stringData {
+		if doc.Data == nil {
+			return nil
+		}
+		return []byte(*doc.Data)
+	}
+	return doc.Value
+}
+
+// put stores the value into the document with the given ID and revision (empty for a new document)
+func (s *CouchDBKeyValueStore) put(id, rev string, value []byte) (int, error) {
+	doc := map[string]interface{}{"_id": id}
+	if rev != "" {
+		doc["_rev"] = rev
+	}
+	if s.stringData {
+		doc["data"] = string(value)
+	} else {
+		doc["value"] = value
+	}
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return 0, errors.Wrap(err, "encoding document failed")
+	}
+	resp, err := s.do(http.MethodPut, s.docURL(id), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (s *CouchDBKeyValueStore) do(method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating CouchDB request failed")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "CouchDB request %s %s failed", method, url)
+	}
+	return resp, nil
+}
+
+func (s *CouchDBKeyValueStore) docURL(id string) string {
+	return s.url + "/" + url.PathEscape(id)
+}
+
+func checkStatus(status int, operation string) error {
+	if status >= http.StatusOK && status < http.StatusMultipleChoices {
+		return nil
+	}
+	return errors.Errorf("%s failed with status %d (%s)", operation, status, http.StatusText(status))
+}
@@ -0,0 +1,241 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+)
+
+func TestCouchDBKVS(t *testing.T) {
+	couchDB := newMockCouchDB()
+	server := httptest.NewServer(couchDB)
+	defer server.Close()
+
+	var store core.KVStore
+	store, err := NewCouchDB(&CouchDBKeyValueStoreOptions{URL: server.URL, Database: "users", Username: "admin", Password: "adminpw"})
+	if err != nil {
+		t.Fatalf("NewCouchDB failed [%s]", err)
+	}
+	if !couchDB.databases["users"] {
+		t.Fatal("Database should have been created")
+	}
+
+	err = store.Store(nil, []byte("1234"))
+	if err == nil || err.Error() != "key is nil" {
+		t.Fatal("Store(nil, ...) should throw error")
+	}
+	err = store.Store("key", nil)
+	if err == nil || err.Error() != "value is nil" {
+		t.Fatal("Store(..., nil) should throw error")
+	}
+
+	if err1 := store.Store("user1@Org1MSP-cert.pem", []byte("value1")); err1 != nil {
+		t.Fatalf("Store failed [%s]", err1)
+	}
+	checkCouchDBValue(store, "user1@Org1MSP-cert.pem", []byte("value1"), t)
+
+	// Update the existing document, then delete it
+	if err1 := store.Store("user1@Org1MSP-cert.pem", []byte("value2")); err1 != nil {
+		t.Fatalf("Store failed [%s]", err1)
+	}
+	checkCouchDBValue(store, "user1@Org1MSP-cert.pem", []byte("value2"), t)
+	if err1 := store.Delete("user1@Org1MSP-cert.pem"); err1 != nil {
+		t.Fatalf("Delete failed [%s]", err1)
+	}
+	checkNonExistingKey(store, t)
+	checkStoreIfAbsent(store.(*CouchDBKeyValueStore), t)
+	if _, err1 := store.Load("user1@Org1MSP-cert.pem"); err1 != core.ErrKeyValueNotFound {
+		t.Fatalf("Load of deleted key should return ErrKeyValueNotFound, got [%v]", err1)
+	}
+	if err1 := store.Delete("user1@Org1MSP-cert.pem"); err1 != nil {
+		t.Fatalf("Delete of non-existing key should succeed [%s]", err1)
+	}
+
+	// The database exists already
+	if _, err1 := NewCouchDB(&CouchDBKeyValueStoreOptions{URL: server.URL, Database: "users", Username: "admin", Password: "adminpw"}); err1 != nil {
+		t.Fatalf("NewCouchDB failed for existing database [%s]", err1)
+	}
+}
+
+func TestCouchDBKVSConflict(t *testing.T) {
+	couchDB := newMockCouchDB()
+	server := httptest.NewServer(couchDB)
+	defer server.Close()
+
+	store, err := NewCouchDB(&CouchDBKeyValueStoreOptions{URL: server.URL, Database: "users", Username: "admin", Password: "adminpw"})
+	if err != nil {
+		t.Fatalf("NewCouchDB failed [%s]", err)
+	}
+
+	couchDB.conflicts = 1
+	if err1 := store.Store("key", []byte("value")); err1 != nil {
+		t.Fatalf("Store should be retried on conflict [%s]", err1)
+	}
+	checkCouchDBValue(store, "key", []byte("value"), t)
+
+	couchDB.conflicts = maxConflictRetries + 1
+	if err1 := store.Store("key", []byte("value")); err1 == nil {
+		t.Fatal("Store should fail when the conflict persists")
+	}
+}
+
+func TestCouchDBKVSStringData(t *testing.T) {
+	couchDB := newMockCouchDB()
+	server := httptest.NewServer(couchDB)
+	defer server.Close()
+
+	store, err := NewCouchDB(&CouchDBKeyValueStoreOptions{URL: server.URL, Database: "wallet", Username: "admin", Password: "adminpw", StringData: true})
+	if err != nil {
+		t.Fatalf("NewCouchDB failed [%s]", err)
+	}
+
+	if err1 := store.Store("user1", []byte(`{"type":"X.509"}`)); err1 != nil {
+		t.Fatalf("Store failed [%s]", err1)
+	}
+	checkCouchDBValue(store, "user1", []byte(`{"type":"X.509"}`), t)
+	if data := couchDB.docs["user1"]["data"]; data != `{"type":"X.509"}` {
+		t.Fatalf("Value should be stored as a string in the data field, got [%v]", data)
+	}
+	if _, ok := couchDB.docs["user1"]["value"]; ok {
+		t.Fatal("Value field should not be set")
+	}
+
+	couchDB.docs["_design/views"] = map[string]interface{}{"_id": "_design/views"}
+	keys, err := store.Keys()
+	if err != nil {
+		t.Fatalf("Keys failed [%s]", err)
+	}
+	if len(keys) != 1 || keys[0] != "user1" {
+		t.Fatalf("Unexpected keys %v", keys)
+	}
+}
+
+func TestCouchDBKVSInvalidOptions(t *testing.T) {
+	if _, err := NewCouchDB(nil); err == nil {
+		t.Fatal("NewCouchDB(nil) should throw error")
+	}
+	if _, err := NewCouchDB(&CouchDBKeyValueStoreOptions{URL: "http://localhost:5984"}); err == nil {
+		t.Fatal("NewCouchDB without database should throw error")
+	}
+
+	server := httptest.NewServer(newMockCouchDB())
+	defer server.Close()
+	if _, err := NewCouchDB(&CouchDBKeyValueStoreOptions{URL: server.URL, Database: "users", Username: "admin", Password: "wrong"}); err == nil {
+		t.Fatal("NewCouchDB with invalid credentials should throw error")
+	}
+}
+
+func checkCouchDBValue(store core.KVStore, key string, expected []byte, t *testing.T) {
+	v, err := store.Load(key)
+	if err != nil {
+		t.Fatalf("Load %s failed [%s]", key, err)
+	}
+	if err := compare(v, expected); err != nil {
+		t.Fatalf("Unexpected value for %s [%s]", key, err)
+	}
+}
+
+// mockCouchDB implements the subset of the CouchDB API that is used by the store
+type mockCouchDB struct {
+	sync.Mutex
+	databases map[string]bool
+	docs      map[string]map[string]interface{}
+	revs      int
+	conflicts int
+}
+
+func newMockCouchDB() *mockCouchDB {
+	return &mockCouchDB{
+		databases: make(map[string]bool),
+		docs:      make(map[string]map[string]interface{}),
+	}
+}
+
+func (m *mockCouchDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.Lock()
+	defer m.Unlock()
+
+	if user, password, ok := r.BasicAuth(); !ok || user != "admin" || password != "adminpw" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if len(parts) == 1 {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if m.databases[parts[0]] {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		m.databases[parts[0]] = true
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	if !m.databases[parts[0]] {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	id := parts[1]
+	if id == "_all_docs" {
+		var rows []map[string]string
+		for id := range m.docs {
+			rows = append(rows, map[string]string{"id": id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"rows": rows}) //nolint
+		return
+	}
+	doc, exists := m.docs[id]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(doc) //nolint
+	case http.MethodPut:
+		newDoc := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&newDoc); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if m.conflicts > 0 || (exists && newDoc["_rev"] != doc["_rev"]) {
+			m.conflicts--
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		m.revs++
+		newDoc["_rev"] = fmt.Sprintf("%d-rev", m.revs)
+		m.docs[id] = newDoc
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("rev") != doc["_rev"] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		delete(m.docs, id)
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -157,7 +157,84 @@ func (fkvs *FileKeyValueStore) Store(key interface{}, value interface{}) error {
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(file, valueBytes, newFileMode)
+	return writeFileAtomic(file, valueBytes)
+}
+
+// StoreIfAbsent sets the value for the key unless a value is already stored for the key, in which
+// case false is returned. The file is created atomically, so that concurrent callers (including the
+// ones of other processes sharing the store path) agree on the stored value.
+func (fkvs *FileKeyValueStore) StoreIfAbsent(key interface{}, value interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if value == nil {
+		return false, errors.New("value is nil")
+	}
+	file, err := fkvs.keySerializer(key)
+	if err != nil {
+		return false, err
+	}
+	valueBytes, err := fkvs.marshaller(value)
+	if err != nil {
+		return false, err
+	}
+	err = os.MkdirAll(path.Dir(file), newDirMode)
+	if err != nil {
+		return false, err
+	}
+	return createFileAtomic(file, valueBytes)
+}
+
+// writeFileAtomic writes the file through a temporary file that is renamed, so that
+// a reader never sees a partially written (or truncated) value
+func writeFileAtomic(file string, data []byte) error {
+	tmp, err := writeTempFile(file, data)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp) //nolint
+		return err
+	}
+	return nil
+}
+
+// createFileAtomic writes the file through a temporary file that is linked to the file, which
+// fails if the file exists. It returns false if the file exists.
+func createFileAtomic(file string, data []byte) (bool, error) {
+	tmp, err := writeTempFile(file, data)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp) //nolint
+
+	if err := os.Link(tmp, file); err != nil {
+		if os.IsExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// writeTempFile writes the data to a temporary file next to the given file and returns its name
+func writeTempFile(file string, data []byte) (string, error) {
+	tmp, err := ioutil.TempFile(path.Dir(file), path.Base(file)+".tmp")
+	if err != nil {
+		return "", err
+	}
+	_, err = tmp.Write(data)
+	if err1 := tmp.Close(); err == nil {
+		err = err1
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), newFileMode)
+	}
+	if err != nil {
+		os.Remove(tmp.Name()) //nolint
+		return "", err
+	}
+	return tmp.Name(), nil
 }
 
 // Delete deletes the value for a key.
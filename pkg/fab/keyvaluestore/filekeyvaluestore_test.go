@@ -82,6 +82,7 @@ func testFKVS(t *testing.T, KeySerializer KeySerializer) {
 
 	// Check empty string value
 	checkEmptyStringValue(store, t)
+	checkStoreIfAbsent(store.(*FileKeyValueStore), t)
 }
 
 func checkKeyValue(store core.KVStore, key string, value []byte, t *testing.T) {
@@ -103,6 +104,35 @@ func checkNonExistingKey(store core.KVStore, t *testing.T) {
 	}
 }
 
+type storeIfAbsent interface {
+	core.KVStore
+	StoreIfAbsent(key interface{}, value interface{}) (bool, error)
+}
+
+func checkStoreIfAbsent(store storeIfAbsent, t *testing.T) {
+	if _, err := store.StoreIfAbsent(nil, []byte("1234")); err == nil || err.Error() != "key is nil" {
+		t.Fatal("StoreIfAbsent(nil, ...) should throw error")
+	}
+	created, err := store.StoreIfAbsent("absent", []byte("value1"))
+	if err != nil || !created {
+		t.Fatalf("StoreIfAbsent should store the value of an absent key [%v]", err)
+	}
+	created, err = store.StoreIfAbsent("absent", []byte("value2"))
+	if err != nil || created {
+		t.Fatalf("StoreIfAbsent should not store the value of an existing key [%v]", err)
+	}
+	v, err := store.Load("absent")
+	if err != nil {
+		t.Fatalf("Load failed [%s]", err)
+	}
+	if err := compare(v, []byte("value1")); err != nil {
+		t.Fatalf("Unexpected value [%s]", err)
+	}
+	if err := store.Delete("absent"); err != nil {
+		t.Fatalf("Delete failed [%s]", err)
+	}
+}
+
 func checkEmptyStringValue(store core.KVStore, t *testing.T) {
 	keyEmptyString := "empty-string"
 	valueEmptyString := []byte("")
@@ -0,0 +1,344 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRedisTimeout      = 5 * time.Second
+	defaultRedisMaxIdleConns = 4
+)
+
+// RedisKeyValueStore stores each value under a separate key of a Redis database, so that the values
+// can be shared by several instances of an application. The Redis key is the key prefix followed by
+// the key derived by the KeySerializer.
+//
+// This component has been designed to be safe for concurrency.
+type RedisKeyValueStore struct {
+	address       string
+	password      string
+	database      int
+	keyPrefix     string
+	timeout       time.Duration
+	idle          chan *redisConn
+	keySerializer KeySerializer
+	marshaller    Marshaller
+	unmarshaller  Unmarshaller
+}
+
+// RedisKeyValueStoreOptions allow overriding store defaults
+type RedisKeyValueStoreOptions struct {
+	// Redis address (host:port), mandatory
+	Address string
+	// Optional. Password used to authenticate (AUTH).
+	Password string
+	// Optional. Database number (SELECT), 0 by default.
+	Database int
+	// Optional. Prefix of the Redis keys, e.g. "fabric:users:"
+	KeyPrefix string
+	// Optional. Timeout of the connections and commands (5s by default).
+	Timeout time.Duration
+	// Optional. Maximum number of idle connections that are kept open (4 by default).
+	MaxIdleConns int
+	// Optional. If not provided, the key (which must be a string) is used as is.
+	KeySerializer KeySerializer
+	// Optional. If not provided, default Marshaller is used.
+	Marshaller Marshaller
+	// Optional. If not provided, default Unmarshaller is used.
+	Unmarshaller Unmarshaller
+}
+
+// NewRedis creates a new instance of RedisKeyValueStore using provided options. An error is
+// returned if the Redis server can't be reached.
+func NewRedis(opts *RedisKeyValueStoreOptions) (*RedisKeyValueStore, error) {
+	if opts == nil {
+		return nil, errors.New("RedisKeyValueStoreOptions is nil")
+	}
+	if opts.Address == "" {
+		return nil, errors.New("RedisKeyValueStore address is required")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = defaultRedisTimeout
+	}
+	if opts.MaxIdleConns == 0 {
+		opts.MaxIdleConns = defaultRedisMaxIdleConns
+	}
+	if opts.KeySerializer == nil {
+		opts.KeySerializer = func(key interface{}) (string, error) {
+			keyString, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			return keyString, nil
+		}
+	}
+	if opts.Marshaller == nil {
+		opts.Marshaller = defaultMarshaller
+	}
+	if opts.Unmarshaller == nil {
+		opts.Unmarshaller = defaultUnmarshaller
+	}
+
+	s := &RedisKeyValueStore{
+		address:       opts.Address,
+		password:      opts.Password,
+		database:      opts.Database,
+		keyPrefix:     opts.KeyPrefix,
+		timeout:       opts.Timeout,
+		idle:          make(chan *redisConn, opts.MaxIdleConns),
+		keySerializer: opts.KeySerializer,
+		marshaller:    opts.Marshaller,
+		unmarshaller:  opts.Unmarshaller,
+	}
+	if _, err := s.do("PING"); err != nil {
+		return nil, errors.WithMessage(err, "connecting to Redis failed")
+	}
+	return s, nil
+}
+
+// Load returns the value stored in the store for a key.
+// If a value for the key was not found, returns (nil, ErrNotFound)
+func (s *RedisKeyValueStore) Load(key interface{}) (interface{}, error) {
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := s.do("GET", []byte(redisKey))
+	if err != nil {
+		return nil, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, core.ErrKeyValueNotFound
+	}
+	return s.unmarshaller(value)
+}
+
+// Store sets the value for the key.
+func (s *RedisKeyValueStore) Store(key interface{}, value interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	if value == nil {
+		return errors.New("value is nil")
+	}
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return err
+	}
+	_, err = s.do("SET", []byte(redisKey), valueBytes)
+	return err
+}
+
+// StoreIfAbsent sets the value for the key unless a value is already stored for the key, in which
+// case false is returned. The value is set with SET NX, so that concurrent callers agree on the
+// stored value.
+func (s *RedisKeyValueStore) StoreIfAbsent(key interface{}, value interface{}) (bool, error) {
+	if key == nil {
+		return false, errors.New("key is nil")
+	}
+	if value == nil {
+		return false, errors.New("value is nil")
+	}
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return false, err
+	}
+	valueBytes, err := s.marshaller(value)
+	if err != nil {
+		return false, err
+	}
+	reply, err := s.do("SET", []byte(redisKey), valueBytes, []byte("NX"))
+	if err != nil {
+		return false, err
+	}
+	// The reply is nil if the key exists
+	return reply != nil, nil
+}
+
+// Delete deletes the value for a key.
+func (s *RedisKeyValueStore) Delete(key interface{}) error {
+	if key == nil {
+		return errors.New("key is nil")
+	}
+	redisKey, err := s.redisKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.do("DEL", []byte(redisKey))
+	return err
+}
+
+// Close closes the idle connections
+func (s *RedisKeyValueStore) Close() {
+	for {
+		select {
+		case c := <-s.idle:
+			c.close()
+		default:
+			return
+		}
+	}
+}
+
+func (s *RedisKeyValueStore) redisKey(key interface{}) (string, error) {
+	k, err := s.keySerializer(key)
+	if err != nil {
+		return "", err
+	}
+	return s.keyPrefix + k, nil
+}
+
+// do sends a command on an idle (or new) connection and returns the reply, which is
+// either a string, an int64, a []byte or nil
+func (s *RedisKeyValueStore) do(command string, args ...[]byte) (interface{}, error) {
+	c, err := s.conn()
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := c.do(s.timeout, command, args...)
+	if _, ok := err.(redisError); err != nil && !ok {
+		// The connection is in an unknown state
+		c.close()
+	} else {
+		s.release(c)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Redis command %s failed", command)
+	}
+	return reply, nil
+}
+
+func (s *RedisKeyValueStore) conn() (*redisConn, error) {
+	select {
+	case c := <-s.idle:
+		return c, nil
+	default:
+	}
+
+	conn, err := net.DialTimeout("tcp", s.address, s.timeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "dialing Redis at %s failed", s.address)
+	}
+	c := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+
+	if s.password != "" {
+		if _, err := c.do(s.timeout, "AUTH", []byte(s.password)); err != nil {
+			c.close()
+			return nil, errors.Wrap(err, "Redis authentication failed")
+		}
+	}
+	if s.database != 0 {
+		if _, err := c.do(s.timeout, "SELECT", []byte(strconv.Itoa(s.database))); err != nil {
+			c.close()
+			return nil, errors.Wrapf(err, "selecting Redis database %d failed", s.database)
+		}
+	}
+	return c, nil
+}
+
+func (s *RedisKeyValueStore) release(c *redisConn) {
+	select {
+	case s.idle <- c:
+	default:
+		c.close()
+	}
+}
+
+// redisError is an error reply of the Redis server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// redisConn is a connection that speaks the Redis serialization protocol (RESP)
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (c *redisConn) do(timeout time.Duration, command string, args ...[]byte) (interface{}, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	buf := []byte(fmt.Sprintf("*%d\r\n$%d\r\n%s\r\n", len(args)+1, len(command), command))
+	for _, arg := range args {
+		buf = append(buf, fmt.Sprintf("$%d\r\n", len(arg))...)
+		buf = append(buf, arg...)
+		buf = append(buf, "\r\n"...)
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("empty Redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid Redis bulk length")
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	default:
+		return nil, errors.Errorf("unsupported Redis reply [%s]", line)
+	}
+}
+
+func (c *redisConn) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errors.New("invalid Redis reply line")
+	}
+	return line[:len(line)-2], nil
+}
+
+func (c *redisConn) close() {
+	c.conn.Close() //nolint
+}
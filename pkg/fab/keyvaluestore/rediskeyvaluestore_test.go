@@ -0,0 +1,252 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package keyvaluestore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+)
+
+func TestRedisKVS(t *testing.T) {
+	server := newMockRedis(t, "secret")
+	defer server.close()
+
+	var store core.KVStore
+	store, err := NewRedis(&RedisKeyValueStoreOptions{Address: server.address(), Password: "secret", Database: 2, KeyPrefix: "users:"})
+	if err != nil {
+		t.Fatalf("NewRedis failed [%s]", err)
+	}
+	defer store.(*RedisKeyValueStore).Close()
+
+	err = store.Store(nil, []byte("1234"))
+	if err == nil || err.Error() != "key is nil" {
+		t.Fatal("Store(nil, ...) should throw error")
+	}
+	err = store.Store("key", nil)
+	if err == nil || err.Error() != "value is nil" {
+		t.Fatal("Store(..., nil) should throw error")
+	}
+
+	value := []byte("binary\r\nvalue")
+	if err1 := store.Store("user1@Org1MSP-cert.pem", value); err1 != nil {
+		t.Fatalf("Store failed [%s]", err1)
+	}
+	if _, ok := server.value(2, "users:user1@Org1MSP-cert.pem"); !ok {
+		t.Fatal("Value should be stored under the prefixed key of the selected database")
+	}
+
+	v, err := store.Load("user1@Org1MSP-cert.pem")
+	if err != nil {
+		t.Fatalf("Load failed [%s]", err)
+	}
+	if err1 := compare(v, value); err1 != nil {
+		t.Fatalf("Unexpected value [%s]", err1)
+	}
+
+	checkNonExistingKey(store, t)
+	checkStoreIfAbsent(store.(*RedisKeyValueStore), t)
+
+	if err1 := store.Delete("user1@Org1MSP-cert.pem"); err1 != nil {
+		t.Fatalf("Delete failed [%s]", err1)
+	}
+	if _, err1 := store.Load("user1@Org1MSP-cert.pem"); err1 != core.ErrKeyValueNotFound {
+		t.Fatalf("Load of deleted key should return ErrKeyValueNotFound, got [%v]", err1)
+	}
+}
+
+func TestRedisKVSErrors(t *testing.T) {
+	if _, err := NewRedis(nil); err == nil {
+		t.Fatal("NewRedis(nil) should throw error")
+	}
+	if _, err := NewRedis(&RedisKeyValueStoreOptions{}); err == nil {
+		t.Fatal("NewRedis without address should throw error")
+	}
+
+	server := newMockRedis(t, "secret")
+	defer server.close()
+
+	if _, err := NewRedis(&RedisKeyValueStoreOptions{Address: server.address(), Password: "wrong"}); err == nil {
+		t.Fatal("NewRedis with invalid password should throw error")
+	}
+
+	store, err := NewRedis(&RedisKeyValueStoreOptions{Address: server.address(), Password: "secret"})
+	if err != nil {
+		t.Fatalf("NewRedis failed [%s]", err)
+	}
+	defer store.Close()
+
+	server.close()
+	if _, err := store.Load("key"); err == nil || err == core.ErrKeyValueNotFound {
+		t.Fatalf("Load should fail when the server is down, got [%v]", err)
+	}
+}
+
+// mockRedis implements the subset of the Redis commands that is used by the store
+type mockRedis struct {
+	sync.Mutex
+	listener  net.Listener
+	password  string
+	databases map[int]map[string][]byte
+	conns     []net.Conn
+}
+
+func newMockRedis(t *testing.T, password string) *mockRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed [%s]", err)
+	}
+	m := &mockRedis{listener: listener, password: password, databases: make(map[int]map[string][]byte)}
+	go m.serve()
+	return m
+}
+
+func (m *mockRedis) address() string {
+	return m.listener.Addr().String()
+}
+
+// close stops the server and closes the connections of the clients
+func (m *mockRedis) close() {
+	m.listener.Close() //nolint
+
+	m.Lock()
+	defer m.Unlock()
+	for _, conn := range m.conns {
+		conn.Close() //nolint
+	}
+	m.conns = nil
+}
+
+func (m *mockRedis) value(db int, key string) ([]byte, bool) {
+	m.Lock()
+	defer m.Unlock()
+	v, ok := m.databases[db][key]
+	return v, ok
+}
+
+func (m *mockRedis) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		m.Lock()
+		m.conns = append(m.conns, conn)
+		m.Unlock()
+		go m.handle(conn)
+	}
+}
+
+func (m *mockRedis) handle(conn net.Conn) {
+	defer conn.Close() //nolint
+
+	reader := bufio.NewReader(conn)
+	authenticated := m.password == ""
+	db := 0
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "AUTH":
+			authenticated = args[1] == m.password
+			reply = "+OK\r\n"
+			if !authenticated {
+				reply = "-ERR invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		default:
+			reply = m.execute(&db, cmd, args[1:])
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (m *mockRedis) execute(db *int, cmd string, args []string) string {
+	m.Lock()
+	defer m.Unlock()
+
+	values, ok := m.databases[*db]
+	if !ok {
+		values = make(map[string][]byte)
+		m.databases[*db] = values
+	}
+
+	switch cmd {
+	case "PING":
+		return "+PONG\r\n"
+	case "SELECT":
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return "-ERR invalid DB index\r\n"
+		}
+		*db = n
+		return "+OK\r\n"
+	case "GET":
+		v, ok := values[args[0]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "SET":
+		if _, ok := values[args[0]]; ok && len(args) > 2 && strings.ToUpper(args[2]) == "NX" {
+			return "$-1\r\n"
+		}
+		values[args[0]] = []byte(args[1])
+		return "+OK\r\n"
+	case "DEL":
+		_, ok := values[args[0]]
+		delete(values, args[0])
+		if ok {
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
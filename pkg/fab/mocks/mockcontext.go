@@ -12,9 +12,11 @@ import (
 	"hash"
 
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 
@@ -41,6 +43,10 @@ type MockProviderContext struct {
 	selectionProvider      fab.SelectionProvider
 	infraProvider          fab.InfraProvider
 	channelProvider        fab.ChannelProvider
+	readOnly               bool
+	caTransport            msp.CATransport
+	clock                  clock.Clock
+	randomSource           *random.Source
 }
 
 // ProviderUsersOptions ...
@@ -133,6 +139,26 @@ func (pc *MockProviderContext) SetIdentityConfig(config msp.IdentityConfig) {
 	pc.identityConfig = config
 }
 
+// SetReadOnly sets the read-only mode of the mock context.
+func (pc *MockProviderContext) SetReadOnly(readOnly bool) {
+	pc.readOnly = readOnly
+}
+
+// SetCATransport sets the function that wraps the HTTP transport of the CA clients.
+func (pc *MockProviderContext) SetCATransport(transport msp.CATransport) {
+	pc.caTransport = transport
+}
+
+// SetClock sets the clock of the mock context.
+func (pc *MockProviderContext) SetClock(c clock.Clock) {
+	pc.clock = c
+}
+
+// SetRandomSource sets the source of the random bytes and pseudo-random numbers of the mock context.
+func (pc *MockProviderContext) SetRandomSource(source *random.Source) {
+	pc.randomSource = source
+}
+
 // CryptoSuite returns the mock crypto suite.
 func (pc *MockProviderContext) CryptoSuite() core.CryptoSuite {
 	return pc.cryptoSuite
@@ -148,6 +174,32 @@ func (pc *MockProviderContext) SigningManager() core.SigningManager {
 	return pc.signingManager
 }
 
+// ReadOnly returns true if the mock context is in read-only mode
+func (pc *MockProviderContext) ReadOnly() bool {
+	return pc.readOnly
+}
+
+// CATransport returns the function that wraps the HTTP transport of the CA clients, or nil
+func (pc *MockProviderContext) CATransport() msp.CATransport {
+	return pc.caTransport
+}
+
+// Clock returns the clock of the mock context (the system clock unless set)
+func (pc *MockProviderContext) Clock() clock.Clock {
+	if pc.clock == nil {
+		return clock.Real
+	}
+	return pc.clock
+}
+
+// RandomSource returns the random source of the mock context (the default source unless set)
+func (pc *MockProviderContext) RandomSource() *random.Source {
+	if pc.randomSource == nil {
+		return random.Default()
+	}
+	return pc.randomSource
+}
+
 // UserStore returns the mock usser store
 func (pc *MockProviderContext) UserStore() msp.UserStore {
 	return pc.userStore
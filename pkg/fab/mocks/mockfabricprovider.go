@@ -77,6 +77,11 @@ func (f *MockInfraProvider) CommManager() fab.CommManager {
 	return nil
 }
 
+//EndpointHealth returns the endpoint health monitor
+func (f *MockInfraProvider) EndpointHealth() fab.EndpointHealth {
+	return nil
+}
+
 // SetCustomOrderer creates a default implementation of Orderer based on configuration.
 func (f *MockInfraProvider) SetCustomOrderer(customOrderer fab.Orderer) {
 	f.customOrderer = customOrderer
@@ -20,9 +20,11 @@ import (
 
 	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -30,25 +32,24 @@ import (
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
-
-const (
-	// GRPC max message size (same as Fabric)
-	maxCallRecvMsgSize = 100 * 1024 * 1024
-	maxCallSendMsgSize = 100 * 1024 * 1024
-)
+var tracer = tracing.NewTracer("fabsdk/fab")
 
 // Orderer allows a client to broadcast a transaction.
 type Orderer struct {
-	config         fab.EndpointConfig
-	url            string
-	serverName     string
-	tlsCACert      *x509.Certificate
-	grpcDialOption []grpc.DialOption
-	kap            keepalive.ClientParameters
-	dialTimeout    time.Duration
-	failFast       bool
-	allowInsecure  bool
-	commManager    fab.CommManager
+	config          fab.EndpointConfig
+	url             string
+	serverName      string
+	tlsCACert       *x509.Certificate
+	grpcDialOption  []grpc.DialOption
+	kap             keepalive.ClientParameters
+	backoffMaxDelay time.Duration
+	dialTimeout     time.Duration
+	failFast        bool
+	allowInsecure   bool
+	tlsOptions      *comm.TLSOptions
+	compression     string
+	msgSizes        comm.MessageSizes
+	commManager     fab.CommManager
 }
 
 // Option describes a functional parameter for the New constructor
@@ -73,12 +74,16 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 		grpcOpts = append(grpcOpts, grpc.WithKeepaliveParams(orderer.kap))
 	}
 	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.FailFast(orderer.failFast)))
+	if orderer.backoffMaxDelay > 0 {
+		grpcOpts = append(grpcOpts, grpc.WithBackoffMaxDelay(orderer.backoffMaxDelay))
+	}
 	if endpoint.AttemptSecured(orderer.url, orderer.allowInsecure) {
 		//tls config
-		tlsConfig, err := comm.TLSConfig(orderer.tlsCACert, orderer.serverName, config)
+		tlsConfig, err := comm.TLSConfig(orderer.tlsCACert, endpoint.TLSServerName(orderer.url, orderer.serverName), config)
 		if err != nil {
 			return nil, err
 		}
+		orderer.tlsOptions.Apply(tlsConfig)
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
 		}
@@ -88,8 +93,13 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
 	}
 
-	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
-		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
+	grpcOpts = append(grpcOpts, comm.MessageSizeDialOpts(orderer.msgSizes)...)
+
+	compressionOpts, err := comm.CompressionDialOpts(orderer.compression)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, compressionOpts...)
 
 	orderer.dialTimeout = config.Timeout(fab.OrdererConnection)
 	orderer.url = endpoint.ToAddress(orderer.url)
@@ -162,9 +172,20 @@ func FromOrdererConfig(ordererCfg *fab.OrdererConfig) Option {
 		}
 
 		o.serverName = getServerNameOverride(ordererCfg)
-		o.kap = getKeepAliveOptions(ordererCfg)
+		connConfig := comm.ConnectionConfig(o.config, fab.OrdererEndpoint)
+		o.kap = getKeepAliveOptions(ordererCfg, connConfig)
+		o.backoffMaxDelay = getBackoffMaxDelay(ordererCfg, connConfig)
 		o.failFast = getFailFast(ordererCfg)
 		o.allowInsecure = isInsecureConnectionAllowed(ordererCfg)
+		o.tlsOptions, err = comm.TLSOptionsFromGRPCOptions(ordererCfg.GRPCOptions)
+		if err != nil {
+			return errors.WithMessage(err, "invalid orderer config")
+		}
+		o.compression = comm.CompressionFromGRPCOptions(ordererCfg.GRPCOptions)
+		o.msgSizes, err = comm.MessageSizesFromGRPCOptions(ordererCfg.GRPCOptions)
+		if err != nil {
+			return errors.WithMessage(err, "invalid orderer config")
+		}
 
 		return nil
 	}
@@ -200,9 +221,13 @@ func getFailFast(ordererCfg *fab.OrdererConfig) bool {
 	return failFast
 }
 
-func getKeepAliveOptions(ordererCfg *fab.OrdererConfig) keepalive.ClientParameters {
+func getKeepAliveOptions(ordererCfg *fab.OrdererConfig, connConfig fab.ConnectionConfig) keepalive.ClientParameters {
 
-	var kap keepalive.ClientParameters
+	kap := keepalive.ClientParameters{
+		Time:                connConfig.KeepAliveTime,
+		Timeout:             connConfig.KeepAliveTimeout,
+		PermitWithoutStream: connConfig.KeepAlivePermit,
+	}
 	if kaTime, ok := ordererCfg.GRPCOptions["keep-alive-time"].(time.Duration); ok {
 		kap.Time = cast.ToDuration(kaTime)
 	}
@@ -215,6 +240,13 @@ func getKeepAliveOptions(ordererCfg *fab.OrdererConfig) keepalive.ClientParamete
 	return kap
 }
 
+func getBackoffMaxDelay(ordererCfg *fab.OrdererConfig, connConfig fab.ConnectionConfig) time.Duration {
+	if maxDelay, ok := ordererCfg.GRPCOptions["backoff-max-delay"]; ok {
+		return cast.ToDuration(maxDelay)
+	}
+	return connConfig.BackoffMaxDelay
+}
+
 func isInsecureConnectionAllowed(ordererCfg *fab.OrdererConfig) bool {
 	allowInsecure, ok := ordererCfg.GRPCOptions["allow-insecure"].(bool)
 	if ok {
@@ -253,6 +285,13 @@ func (o *Orderer) URL() string {
 
 // SendBroadcast Send the created transaction to Orderer.
 func (o *Orderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
+	ctx, span := tracer.Start(ctx, "orderer.Broadcast", tracing.String("orderer", o.url))
+	broadcastStatus, err := o.sendBroadcast(audit.OutgoingContext(tracing.OutgoingContext(ctx)), envelope)
+	tracing.End(span, err)
+	return broadcastStatus, err
+}
+
+func (o *Orderer) sendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnvelope) (*common.Status, error) {
 	conn, err := o.conn(ctx)
 	if err != nil {
 		rpcStatus, ok := grpcstatus.FromError(err)
@@ -264,7 +303,7 @@ func (o *Orderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnve
 	}
 	defer o.releaseConn(ctx, conn)
 
-	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Broadcast(ctx)
+	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Broadcast(ctx, comm.MessageSizeCallOpts(ctx)...)
 	if err != nil {
 		rpcStatus, ok := grpcstatus.FromError(err)
 		if ok {
@@ -283,7 +322,7 @@ func (o *Orderer) SendBroadcast(ctx reqContext.Context, envelope *fab.SignedEnve
 		Signature: envelope.Signature,
 	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to send envelope to orderer")
+		return nil, errors.Wrap(comm.MessageSizeError(err), "failed to send envelope to orderer")
 	}
 	if err = broadcastClient.CloseSend(); err != nil {
 		logger.Debugf("unable to close broadcast client [%s]", err)
@@ -338,7 +377,7 @@ func (o *Orderer) SendDeliver(ctx reqContext.Context, envelope *fab.SignedEnvelo
 	}
 
 	// Create atomic broadcast client
-	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Deliver(ctx)
+	broadcastClient, err := ab.NewAtomicBroadcastClient(conn).Deliver(audit.OutgoingContext(tracing.OutgoingContext(ctx)), comm.MessageSizeCallOpts(ctx)...)
 	if err != nil {
 		logger.Errorf("deliver failed [%s]", err)
 		o.releaseConn(ctx, conn)
@@ -377,7 +416,7 @@ func blockStream(deliverClient ab.AtomicBroadcast_DeliverClient, responses chan
 	for {
 		response, err := deliverClient.Recv()
 		if err != nil {
-			errs <- errors.Wrap(err, "recv from ordering service failed")
+			errs <- errors.Wrap(comm.MessageSizeError(err), "recv from ordering service failed")
 			return
 		}
 		// Assert response type
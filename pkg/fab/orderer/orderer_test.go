@@ -372,7 +372,7 @@ func TestGetKeepAliveOptions(t *testing.T) {
 	ordererConfig := &fab.OrdererConfig{
 		GRPCOptions: grpcOpts,
 	}
-	kap := getKeepAliveOptions(ordererConfig)
+	kap := getKeepAliveOptions(ordererConfig, fab.ConnectionConfig{})
 	if kap.Time != 0 {
 		t.Fatalf("Expected 0 time for incorrect keep-alive-time")
 	}
@@ -0,0 +1,71 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/limiter"
+)
+
+// endorsementLimits holds the adaptive concurrency limiters of the endorsers. The limiters are shared by
+// all of the peer instances of an endpoint since peers are usually created for each request.
+var endorsementLimits = struct {
+	sync.RWMutex
+	opts     *limiter.Opts
+	limiters sync.Map
+}{}
+
+// SetEndorsementLimits enables the adaptive limits of the number of concurrent endorsements sent to
+// each peer (see limiter.Adaptive). Endorsements aren't limited if the options are nil, which is the default.
+func SetEndorsementLimits(opts *limiter.Opts) {
+	endorsementLimits.Lock()
+	defer endorsementLimits.Unlock()
+
+	endorsementLimits.opts = opts
+	endorsementLimits.limiters.Range(func(key, value interface{}) bool {
+		endorsementLimits.limiters.Delete(key)
+		return true
+	})
+}
+
+// endorsementLimiter returns the limiter of the given endorser, or nil if endorsements aren't limited
+func endorsementLimiter(target string) *limiter.Adaptive {
+	endorsementLimits.RLock()
+	defer endorsementLimits.RUnlock()
+
+	if endorsementLimits.opts == nil {
+		return nil
+	}
+	value, ok := endorsementLimits.limiters.Load(target)
+	if !ok {
+		value, _ = endorsementLimits.limiters.LoadOrStore(target, limiter.New(*endorsementLimits.opts))
+	}
+	return value.(*limiter.Adaptive)
+}
+
+// isOverloaded returns true if the endorsement failed because the endorser is overloaded or unavailable,
+// as opposed to a chaincode or validation error
+func isOverloaded(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Group {
+	case status.GRPCTransportStatus:
+		switch codes.Code(s.Code) {
+		case codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded:
+			return true
+		}
+	case status.EndorserClientStatus:
+		return s.Code == status.ConnectionFailed.ToInt32()
+	}
+	return false
+}
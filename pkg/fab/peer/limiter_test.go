@@ -0,0 +1,44 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peer
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/limiter"
+)
+
+func TestEndorsementLimiter(t *testing.T) {
+	defer SetEndorsementLimits(nil)
+
+	assert.Nil(t, endorsementLimiter("peer1:7051"), "expecting endorsements not to be limited by default")
+
+	SetEndorsementLimits(&limiter.Opts{InitialLimit: 5})
+	l := endorsementLimiter("peer1:7051")
+	assert.NotNil(t, l)
+	assert.Equal(t, 5, l.Limit())
+	assert.True(t, l == endorsementLimiter("peer1:7051"), "expecting the limiter to be shared by the peers of an endpoint")
+	assert.False(t, l == endorsementLimiter("peer2:7051"))
+
+	SetEndorsementLimits(&limiter.Opts{InitialLimit: 10})
+	assert.Equal(t, 10, endorsementLimiter("peer1:7051").Limit())
+}
+
+func TestIsOverloaded(t *testing.T) {
+	assert.True(t, isOverloaded(status.NewFromGRPCStatus(grpcstatus.New(codes.Unavailable, "unavailable"))))
+	assert.True(t, isOverloaded(errors.WithMessage(status.NewFromGRPCStatus(grpcstatus.New(codes.ResourceExhausted, "exhausted")), "connection failed")))
+	assert.True(t, isOverloaded(status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "failed", nil)))
+	assert.False(t, isOverloaded(status.NewFromExtractedChaincodeError(500, "chaincode error")))
+	assert.False(t, isOverloaded(errors.New("other error")))
+	assert.False(t, isOverloaded(nil))
+}
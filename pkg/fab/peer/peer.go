@@ -10,7 +10,9 @@ import (
 	reqContext "context"
 
 	"crypto/x509"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cast"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
@@ -19,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 )
 
 var logger = logging.NewLogger("fabsdk/fab")
@@ -26,16 +29,20 @@ var logger = logging.NewLogger("fabsdk/fab")
 // Peer represents a node in the target blockchain network to which
 // HFC sends endorsement proposals, transaction ordering or query requests.
 type Peer struct {
-	config      fab.EndpointConfig
-	certificate *x509.Certificate
-	serverName  string
-	processor   fab.ProposalProcessor
-	mspID       string
-	url         string
-	kap         keepalive.ClientParameters
-	failFast    bool
-	inSecure    bool
-	commManager fab.CommManager
+	config          fab.EndpointConfig
+	certificate     *x509.Certificate
+	serverName      string
+	processor       fab.ProposalProcessor
+	mspID           string
+	url             string
+	kap             keepalive.ClientParameters
+	backoffMaxDelay time.Duration
+	failFast        bool
+	inSecure        bool
+	tlsOptions      *comm.TLSOptions
+	compression     string
+	msgSizes        comm.MessageSizes
+	commManager     fab.CommManager
 }
 
 // Option describes a functional parameter for the New constructor
@@ -66,6 +73,10 @@ func New(config fab.EndpointConfig, opts ...Option) (*Peer, error) {
 			kap:                peer.kap,
 			failFast:           peer.failFast,
 			allowInsecure:      peer.inSecure,
+			tlsOptions:         peer.tlsOptions,
+			compression:        peer.compression,
+			backoffMaxDelay:    peer.backoffMaxDelay,
+			msgSizes:           peer.msgSizes,
 			commManager:        peer.commManager,
 		}
 		processor, err := newPeerEndorser(&endorseRequest)
@@ -153,8 +164,19 @@ func FromPeerConfig(peerCfg *fab.NetworkPeer) Option {
 
 		// TODO: Remove upon making peer interface immutable
 		p.mspID = peerCfg.MSPID
-		p.kap = getKeepAliveOptions(peerCfg)
+		connConfig := comm.ConnectionConfig(p.config, fab.PeerEndpoint)
+		p.kap = getKeepAliveOptions(peerCfg, connConfig)
+		p.backoffMaxDelay = getBackoffMaxDelay(peerCfg, connConfig)
 		p.failFast = getFailFast(peerCfg)
+		p.tlsOptions, err = comm.TLSOptionsFromGRPCOptions(peerCfg.GRPCOptions)
+		if err != nil {
+			return errors.WithMessage(err, "invalid peer config")
+		}
+		p.compression = comm.CompressionFromGRPCOptions(peerCfg.GRPCOptions)
+		p.msgSizes, err = comm.MessageSizesFromGRPCOptions(peerCfg.GRPCOptions)
+		if err != nil {
+			return errors.WithMessage(err, "invalid peer config")
+		}
 		return nil
 	}
 }
@@ -177,9 +199,13 @@ func getFailFast(peerCfg *fab.NetworkPeer) bool {
 	return failFast
 }
 
-func getKeepAliveOptions(peerCfg *fab.NetworkPeer) keepalive.ClientParameters {
+func getKeepAliveOptions(peerCfg *fab.NetworkPeer, connConfig fab.ConnectionConfig) keepalive.ClientParameters {
 
-	var kap keepalive.ClientParameters
+	kap := keepalive.ClientParameters{
+		Time:                connConfig.KeepAliveTime,
+		Timeout:             connConfig.KeepAliveTimeout,
+		PermitWithoutStream: connConfig.KeepAlivePermit,
+	}
 	if kaTime, ok := peerCfg.GRPCOptions["keep-alive-time"]; ok {
 		kap.Time = cast.ToDuration(kaTime)
 	}
@@ -192,6 +218,13 @@ func getKeepAliveOptions(peerCfg *fab.NetworkPeer) keepalive.ClientParameters {
 	return kap
 }
 
+func getBackoffMaxDelay(peerCfg *fab.NetworkPeer, connConfig fab.ConnectionConfig) time.Duration {
+	if maxDelay, ok := peerCfg.GRPCOptions["backoff-max-delay"]; ok {
+		return cast.ToDuration(maxDelay)
+	}
+	return connConfig.BackoffMaxDelay
+}
+
 func isInsecureConnectionAllowed(peerCfg *fab.NetworkPeer) bool {
 	allowInsecure, ok := peerCfg.GRPCOptions["allow-insecure"].(bool)
 	if ok {
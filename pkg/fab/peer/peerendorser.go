@@ -9,6 +9,7 @@ package peer
 import (
 	reqContext "context"
 	"crypto/x509"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -21,8 +22,11 @@ import (
 	grpcstatus "google.golang.org/grpc/status"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -30,11 +34,29 @@ import (
 	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
 )
 
-const (
-	// GRPC max message size (same as Fabric)
-	maxCallRecvMsgSize = 100 * 1024 * 1024
-	maxCallSendMsgSize = 100 * 1024 * 1024
-)
+var tracer = tracing.NewTracer("fabsdk/fab")
+
+// EndorserError is returned when an endorser fails to process a transaction proposal.
+// It identifies the endorser; the error returned by the endorser (usually a *status.Status)
+// is its cause.
+type EndorserError struct {
+	Endorser string
+	err      error
+}
+
+// NewEndorserError returns an error indicating that the given endorser failed to process a proposal
+func NewEndorserError(endorser string, cause error) *EndorserError {
+	return &EndorserError{Endorser: endorser, err: cause}
+}
+
+func (e *EndorserError) Error() string {
+	return fmt.Sprintf("Transaction processing for endorser [%s]: %s", e.Endorser, e.err)
+}
+
+// Cause returns the error returned by the endorser
+func (e *EndorserError) Cause() error {
+	return e.err
+}
 
 // peerEndorser enables access to a GRPC-based endorser for running transaction proposal simulations
 type peerEndorser struct {
@@ -52,6 +74,10 @@ type peerEndorserRequest struct {
 	kap                keepalive.ClientParameters
 	failFast           bool
 	allowInsecure      bool
+	tlsOptions         *comm.TLSOptions
+	compression        string
+	backoffMaxDelay    time.Duration
+	msgSizes           comm.MessageSizes
 	commManager        fab.CommManager
 }
 
@@ -66,12 +92,16 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 		grpcOpts = append(grpcOpts, grpc.WithKeepaliveParams(endorseReq.kap))
 	}
 	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.FailFast(endorseReq.failFast)))
+	if endorseReq.backoffMaxDelay > 0 {
+		grpcOpts = append(grpcOpts, grpc.WithBackoffMaxDelay(endorseReq.backoffMaxDelay))
+	}
 
 	if endpoint.AttemptSecured(endorseReq.target, endorseReq.allowInsecure) {
-		tlsConfig, err := comm.TLSConfig(endorseReq.certificate, endorseReq.serverHostOverride, endorseReq.config)
+		tlsConfig, err := comm.TLSConfig(endorseReq.certificate, endpoint.TLSServerName(endorseReq.target, endorseReq.serverHostOverride), endorseReq.config)
 		if err != nil {
 			return nil, err
 		}
+		endorseReq.tlsOptions.Apply(tlsConfig)
 		//verify if certificate was expired or not yet valid
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
@@ -81,8 +111,13 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 		grpcOpts = append(grpcOpts, grpc.WithInsecure())
 	}
 
-	grpcOpts = append(grpcOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize),
-		grpc.MaxCallSendMsgSize(maxCallSendMsgSize)))
+	grpcOpts = append(grpcOpts, comm.MessageSizeDialOpts(endorseReq.msgSizes)...)
+
+	compressionOpts, err := comm.CompressionDialOpts(endorseReq.compression)
+	if err != nil {
+		return nil, err
+	}
+	grpcOpts = append(grpcOpts, compressionOpts...)
 
 	timeout := endorseReq.config.Timeout(fab.EndorserConnection)
 
@@ -98,13 +133,17 @@ func newPeerEndorser(endorseReq *peerEndorserRequest) (*peerEndorser, error) {
 
 // ProcessTransactionProposal sends the transaction proposal to a peer and returns the response.
 func (p *peerEndorser) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
-	logger.Debugf("Processing proposal using endorser: %s", p.target)
+	logger.With(logging.Peer(p.target), logging.RequestID(audit.RequestID(ctx))).Debug("Processing proposal using endorser")
 
-	proposalResponse, err := p.sendProposal(ctx, request)
+	ctx, span := tracer.Start(ctx, "peer.ProcessProposal", tracing.String("peer", p.target))
+	proposalResponse, err := p.sendLimitedProposal(audit.OutgoingContext(tracing.OutgoingContext(ctx)), request)
 	if err != nil {
+		tracing.End(span, err)
 		tpr := fab.TransactionProposalResponse{Endorser: p.target}
-		return &tpr, errors.Wrapf(err, "Transaction processing for endorser [%s]", p.target)
+		return &tpr, errors.WithStack(NewEndorserError(p.target, err))
 	}
+	span.SetAttributes(tracing.Int64("status", int64(proposalResponse.GetResponse().Status)))
+	span.End()
 
 	tpr := fab.TransactionProposalResponse{
 		ProposalResponse: proposalResponse,
@@ -136,6 +175,22 @@ func (p *peerEndorser) releaseConn(ctx reqContext.Context, conn *grpc.ClientConn
 	commManager.ReleaseConn(conn)
 }
 
+// sendLimitedProposal sends the proposal once the endorser's concurrency limit allows it, if endorsements are limited
+func (p *peerEndorser) sendLimitedProposal(ctx reqContext.Context, proposal fab.ProcessProposalRequest) (*pb.ProposalResponse, error) {
+	l := endorsementLimiter(p.target)
+	if l == nil {
+		return p.sendProposal(ctx, proposal)
+	}
+
+	if err := l.Acquire(ctx); err != nil {
+		return nil, status.New(status.EndorserClientStatus, status.Timeout.ToInt32(), err.Error(), []interface{}{p.target})
+	}
+	start := time.Now()
+	resp, err := p.sendProposal(ctx, proposal)
+	l.Release(time.Since(start), isOverloaded(err))
+	return resp, err
+}
+
 func (p *peerEndorser) sendProposal(ctx reqContext.Context, proposal fab.ProcessProposalRequest) (*pb.ProposalResponse, error) {
 	conn, err := p.conn(ctx)
 	if err != nil {
@@ -148,10 +203,11 @@ func (p *peerEndorser) sendProposal(ctx reqContext.Context, proposal fab.Process
 	defer p.releaseConn(ctx, conn)
 
 	endorserClient := pb.NewEndorserClient(conn)
-	resp, err := endorserClient.ProcessProposal(ctx, proposal.SignedProposal)
+	resp, err := endorserClient.ProcessProposal(ctx, proposal.SignedProposal, comm.MessageSizeCallOpts(ctx)...)
 
 	if err != nil {
-		logger.Errorf("process proposal failed [%s]", err)
+		err = comm.MessageSizeError(err)
+		logger.With(logging.Peer(p.target), logging.RequestID(audit.RequestID(ctx))).Errorf("process proposal failed [%s]", err)
 		rpcStatus, ok := grpcstatus.FromError(err)
 
 		if ok {
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
 	grpcCodes "google.golang.org/grpc/codes"
@@ -268,6 +269,14 @@ func TestEndorserConnectionError(t *testing.T) {
 	assert.Equal(t, int32(status.ConnectionFailed), statusError.Code)
 }
 
+func TestEndorserError(t *testing.T) {
+	cause := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+	err := NewEndorserError("peer1.example.com:7051", cause)
+
+	assert.Equal(t, "Transaction processing for endorser [peer1.example.com:7051]: "+cause.Error(), err.Error())
+	assert.Equal(t, cause, errors.Cause(errors.WithStack(err)))
+}
+
 func TestEndorserRPCError(t *testing.T) {
 	testErrorMessage := "RPC error condition"
 
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package api
 
 import (
+	"io"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	common "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
@@ -55,4 +57,9 @@ type JoinChannelRequest struct {
 type CCPackage struct {
 	Type pb.ChaincodeSpec_Type
 	Code []byte
+	// Reader, if set, is read instead of Code, so that large packages needn't be loaded
+	// into memory ahead of the install. Size is the number of bytes of the package to read;
+	// if it isn't set the package is read until EOF.
+	Reader io.Reader
+	Size   int64
 }
@@ -7,9 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package resource
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
@@ -35,43 +40,105 @@ type ChaincodeInstallRequest struct {
 type ChaincodePackage struct {
 	Type pb.ChaincodeSpec_Type
 	Code []byte
+	// Reader, if set, is read instead of Code. Size is the number of bytes of the package
+	// to read; if it isn't set the package is read until EOF.
+	Reader io.Reader
+	Size   int64
 }
 
 // CreateChaincodeInstallProposal creates an install chaincode proposal.
+// The code package is read directly into the proposal payload so that it's held in memory
+// only once while the proposal is created.
 func CreateChaincodeInstallProposal(txh fab.TransactionHeader, request ChaincodeInstallRequest) (*fab.TransactionProposal, error) {
-	cir, err := createInstallInvokeRequest(request)
+	if request.Package == nil {
+		return nil, errors.New("chaincode package is required")
+	}
+
+	ts, err := ptypes.TimestampProto(time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create timestamp in install proposal")
+	}
+
+	payload, err := createInstallProposalPayload(request, ts)
 	if err != nil {
 		return nil, errors.WithMessage(err, "creating lscc install invocation request failed")
 	}
 
-	return txn.CreateChaincodeInvokeProposal(txh, cir)
+	return txn.CreateChaincodeProposalWithPayload(txh, lscc, payload)
 }
 
-func createInstallInvokeRequest(request ChaincodeInstallRequest) (fab.ChaincodeInvokeRequest, error) {
-	// Generate arguments for install
-	args := [][]byte{}
-	timestamp := time.Now()
-	ts, err := ptypes.TimestampProto(timestamp)
+// createInstallProposalPayload returns the marshalled ChaincodeProposalPayload of the lscc install
+// invocation. Rather than marshalling the deployment spec, the invocation spec and the payload in
+// turn (each of which copies the code package), the enclosing fields are encoded ahead of the code
+// package and the package is read into the end of the payload.
+func createInstallProposalPayload(request ChaincodeInstallRequest, ts *timestamp.Timestamp) ([]byte, error) {
+	code, size, err := packageReader(request.Package)
 	if err != nil {
-		return fab.ChaincodeInvokeRequest{}, errors.Wrap(err, "failed to create timestamp in install proposal")
+		return nil, err
 	}
 
 	ccds := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{
 		Type: request.Package.Type, ChaincodeId: &pb.ChaincodeID{Name: request.Name, Path: request.Path, Version: request.Version}},
-		CodePackage: request.Package.Code, EffectiveDate: ts}
+		EffectiveDate: ts}
+	ccdsPrefix, err := protos_utils.Marshal(ccds)
+	if err != nil {
+		return nil, errors.WithMessage(err, "marshal of chaincode deployment spec failed")
+	}
 
-	ccdsBytes, err := protos_utils.Marshal(ccds)
+	input, err := protos_utils.Marshal(&pb.ChaincodeInput{Args: [][]byte{[]byte(lsccInstall)}})
 	if err != nil {
-		return fab.ChaincodeInvokeRequest{}, errors.WithMessage(err, "marshal of chaincode deployment spec failed")
+		return nil, errors.WithMessage(err, "marshal of chaincode input failed")
 	}
-	args = append(args, ccdsBytes)
 
-	cir := fab.ChaincodeInvokeRequest{
-		ChaincodeID: lscc,
-		Fcn:         lsccInstall,
-		Args:        args,
+	spec, err := protos_utils.Marshal(&pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: lscc}})
+	if err != nil {
+		return nil, errors.WithMessage(err, "marshal of chaincode spec failed")
+	}
+
+	// The prefixes of the nested messages, from the innermost to the outermost:
+	// ChaincodeDeploymentSpec.code_package (3), ChaincodeInput.args (1), ChaincodeSpec.input (3),
+	// ChaincodeInvocationSpec.chaincode_spec (1) and ChaincodeProposalPayload.input (1)
+	length := uint64(size)
+	var prefixes [][]byte
+	for _, p := range []struct {
+		msg   []byte
+		field uint64
+	}{{ccdsPrefix, 3}, {input, 1}, {spec, 3}, {nil, 1}, {nil, 1}} {
+		prefix := append(append([]byte(nil), p.msg...), proto.EncodeVarint(p.field<<3|proto.WireBytes)...)
+		prefix = append(prefix, proto.EncodeVarint(length)...)
+		length += uint64(len(prefix))
+		prefixes = append(prefixes, prefix)
+	}
+
+	payload := make([]byte, 0, length)
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		payload = append(payload, prefixes[i]...)
+	}
+
+	offset := len(payload)
+	payload = payload[:length]
+	if _, err := io.ReadFull(code, payload[offset:]); err != nil {
+		return nil, errors.Wrap(err, "reading chaincode package failed")
+	}
+
+	return payload, nil
+}
+
+// packageReader returns the reader and the size of the code package
+func packageReader(pkg *ChaincodePackage) (io.Reader, int64, error) {
+	if pkg.Reader == nil {
+		return bytes.NewReader(pkg.Code), int64(len(pkg.Code)), nil
+	}
+	if pkg.Size > 0 {
+		return pkg.Reader, pkg.Size, nil
+	}
+
+	// The size must be known ahead of the package, so the package is read into memory
+	code, err := ioutil.ReadAll(pkg.Reader)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "reading chaincode package failed")
 	}
-	return cir, nil
+	return bytes.NewReader(code), int64(len(code)), nil
 }
 
 func createInstalledChaincodesInvokeRequest() fab.ChaincodeInvokeRequest {
@@ -7,8 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package resource
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/require"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
@@ -42,3 +48,39 @@ func TestCreateChaincodeInstallProposal(t *testing.T) {
 	_, err = txn.SendProposal(reqCtx, prop, []fab.ProposalProcessor{&peer})
 	assert.Nil(t, err, "sending mock proposal failed")
 }
+
+func TestCreateInstallProposalPayload(t *testing.T) {
+	code := bytes.Repeat([]byte("package"), 1000)
+	ts, err := ptypes.TimestampProto(time.Now())
+	require.NoError(t, err)
+
+	// The expected payload is marshalled message by message
+	ccds, err := proto.Marshal(&pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: "examplecc", Path: "github.com/examplecc", Version: "1"}},
+		CodePackage: code, EffectiveDate: ts})
+	require.NoError(t, err)
+	cis, err := proto.Marshal(&pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: lscc},
+		Input: &pb.ChaincodeInput{Args: [][]byte{[]byte(lsccInstall), ccds}}}})
+	require.NoError(t, err)
+	expected, err := proto.Marshal(&pb.ChaincodeProposalPayload{Input: cis})
+	require.NoError(t, err)
+
+	packages := []*ChaincodePackage{
+		{Type: pb.ChaincodeSpec_GOLANG, Code: code},
+		{Type: pb.ChaincodeSpec_GOLANG, Reader: bytes.NewReader(code), Size: int64(len(code))},
+		{Type: pb.ChaincodeSpec_GOLANG, Reader: bytes.NewReader(code)},
+	}
+	for _, pkg := range packages {
+		request := ChaincodeInstallRequest{Name: "examplecc", Path: "github.com/examplecc", Version: "1", Package: pkg}
+		payload, err := createInstallProposalPayload(request, ts)
+		require.NoError(t, err)
+		assert.Equal(t, expected, payload)
+	}
+
+	// The reader returns less than the size of the package
+	request := ChaincodeInstallRequest{Name: "examplecc", Path: "github.com/examplecc", Version: "1",
+		Package: &ChaincodePackage{Reader: bytes.NewReader(code), Size: int64(len(code) + 1)}}
+	_, err = createInstallProposalPayload(request, ts)
+	assert.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/readonly"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	ccomm "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource/api"
@@ -78,6 +79,10 @@ func SignChannelConfig(ctx context.Client, config []byte, signer msp.SigningIden
 
 // CreateChannel calls the orderer to start building the new channel.
 func CreateChannel(reqCtx reqContext.Context, request api.CreateChannelRequest, opts ...Opt) (fab.TransactionID, error) {
+	if err := checkReadOnly(reqCtx, "create or update channel"); err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
 	if request.Orderer == nil {
 		return fab.EmptyTransactionID, errors.New("missing orderer request parameter for the initialize channel")
 	}
@@ -177,6 +182,9 @@ func LastConfigFromOrderer(reqCtx reqContext.Context, channelName string, ordere
 //
 // TODO extract targets from request into parameter.
 func JoinChannel(reqCtx reqContext.Context, request api.JoinChannelRequest, targets []fab.ProposalProcessor, opts ...Opt) error {
+	if err := checkReadOnly(reqCtx, "join channel"); err != nil {
+		return err
+	}
 
 	if request.GenesisBlock == nil {
 		return errors.New("missing block input parameter with the required genesis block")
@@ -312,6 +320,9 @@ func QueryInstalledChaincodes(reqCtx reqContext.Context, peer fab.ProposalProces
 
 // InstallChaincode sends an install proposal to one or more endorsing peers.
 func InstallChaincode(reqCtx reqContext.Context, req api.InstallChaincodeRequest, targets []fab.ProposalProcessor, opts ...Opt) ([]*fab.TransactionProposalResponse, fab.TransactionID, error) {
+	if err := checkReadOnly(reqCtx, "install chaincode"); err != nil {
+		return nil, fab.EmptyTransactionID, err
+	}
 
 	if req.Name == "" {
 		return nil, fab.EmptyTransactionID, errors.New("chaincode name required")
@@ -331,8 +342,10 @@ func InstallChaincode(reqCtx reqContext.Context, req api.InstallChaincodeRequest
 		Path:    req.Path,
 		Version: req.Version,
 		Package: &ChaincodePackage{
-			Type: req.Package.Type,
-			Code: req.Package.Code,
+			Type:   req.Package.Type,
+			Code:   req.Package.Code,
+			Reader: req.Package.Reader,
+			Size:   req.Package.Size,
 		},
 	}
 
@@ -410,6 +423,16 @@ func validateResponse(response *fab.TransactionProposalResponse) error {
 	return nil
 }
 
+// checkReadOnly returns an error if the client context of the request is in read-only mode. A request
+// without a client context is let through since it fails as soon as it needs to be signed.
+func checkReadOnly(reqCtx reqContext.Context, operation string) error {
+	ctx, ok := contextImpl.RequestClientContext(reqCtx)
+	if !ok {
+		return nil
+	}
+	return readonly.Check(ctx, operation)
+}
+
 func getOpts(opts ...Opt) options {
 	var optionsValue options
 	for _, opt := range opts {
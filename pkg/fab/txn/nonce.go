@@ -0,0 +1,32 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"io"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	"github.com/pkg/errors"
+)
+
+// newNonces reads the given number of nonces from the given source in a single read. The random
+// source of the SDK reads its entropy source in batches, so that concurrent transactions don't
+// each read from the entropy source (see random.Source).
+func newNonces(source io.Reader, count int) ([][]byte, error) {
+	buf := make([]byte, count*crypto.NonceSize)
+	if _, err := io.ReadFull(source, buf); err != nil {
+		return nil, errors.WithMessage(err, "reading nonces failed")
+	}
+
+	result := make([][]byte, count)
+	for i := range result {
+		// The capacity is limited so that appending to a nonce doesn't overwrite the next one
+		end := (i + 1) * crypto.NonceSize
+		result[i] = buf[i*crypto.NonceSize : end : end]
+	}
+	return result, nil
+}
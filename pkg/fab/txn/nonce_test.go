@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+)
+
+type failingReader struct{}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+func TestNewHeaders(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	creator, err := ctx.Serialize()
+	require.NoError(t, err)
+
+	headers, err := NewHeaders(ctx, testChannel, 300)
+	require.NoError(t, err)
+	require.Len(t, headers, 300)
+
+	ids := make(map[string]bool)
+	for i, h := range headers {
+		assert.Equal(t, testChannel, h.ChannelID())
+		assert.Equal(t, creator, h.Creator())
+		require.Len(t, h.Nonce(), crypto.NonceSize)
+
+		digest := sha256.Sum256(append(append([]byte(nil), h.Nonce()...), creator...))
+		assert.Equal(t, hex.EncodeToString(digest[:]), string(h.TransactionID()), "unexpected ID of header %d", i)
+
+		assert.False(t, ids[string(h.TransactionID())], "duplicate transaction ID")
+		ids[string(h.TransactionID())] = true
+	}
+
+	// Appending to a nonce must not overwrite the next one
+	next := append([]byte(nil), headers[1].Nonce()...)
+	extended := append(headers[0].Nonce(), 1, 2, 3)
+	assert.Len(t, extended, crypto.NonceSize+3)
+	assert.Equal(t, next, headers[1].Nonce())
+
+	_, err = NewHeaders(ctx, testChannel, 0)
+	assert.Error(t, err)
+}
+
+func TestRandomSource(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	seed := make([]byte, 8)
+	source, err := random.NewSource(bytes.NewReader(append(seed, bytes.Repeat([]byte{7}, 4096)...)))
+	require.NoError(t, err)
+	ctx.SetRandomSource(source)
+	txh, err := NewHeader(ctx, testChannel)
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Repeat([]byte{7}, crypto.NonceSize), txh.Nonce())
+
+	source, err = random.NewSource(io.MultiReader(bytes.NewReader(seed), &failingReader{}))
+	require.NoError(t, err)
+	ctx.SetRandomSource(source)
+	_, err = NewHeader(ctx, testChannel)
+	assert.Error(t, err)
+}
+
+func BenchmarkNewHeader(b *testing.B) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := NewHeader(ctx, testChannel); err != nil {
+				b.Fatalf("create transaction header failed: %s", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	reqContext "context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+const (
+	ordererBackoffInitial = time.Second
+	ordererBackoffMax     = 30 * time.Second
+)
+
+// OrdererSelector determines the order in which orderers are tried. Orderers are rotated in
+// a round-robin fashion so that the load is spread across the ordering service. An orderer
+// that can't be reached is skipped for a backoff period, which doubles with every consecutive
+// failure, and is only tried as a last resort until the period has elapsed.
+//
+// The selection state belongs to a channel context: the channel services that keep a selector
+// (see ordererSelectorProvider) share it between the requests made on their context.
+type OrdererSelector struct {
+	lock     sync.Mutex
+	next     int
+	failures map[string]*ordererFailure
+	now      func() time.Time
+}
+
+type ordererFailure struct {
+	count     int
+	skipUntil time.Time
+}
+
+// NewOrdererSelector returns an orderer selector whose backoff periods elapse according to the given time source
+func NewOrdererSelector(now func() time.Time) *OrdererSelector {
+	return &OrdererSelector{
+		failures: make(map[string]*ordererFailure),
+		now:      now,
+	}
+}
+
+// ordererSelectorProvider is implemented by the channel services that keep the orderer selector of their channel context
+type ordererSelectorProvider interface {
+	OrdererSelector() *OrdererSelector
+}
+
+// ordererSelection returns the orderer selector of the channel context of the request. A request that isn't
+// made on a channel context (e.g. a channel creation) gets a new selector, i.e. failures aren't remembered.
+func ordererSelection(reqCtx reqContext.Context) *OrdererSelector {
+	ctx, ok := context.RequestClientContext(reqCtx)
+	if !ok {
+		return NewOrdererSelector(time.Now)
+	}
+	if chCtx, ok := ctx.(contextApi.Channel); ok {
+		if p, ok := chCtx.ChannelService().(ordererSelectorProvider); ok {
+			return p.OrdererSelector()
+		}
+	}
+	return NewOrdererSelector(ctx.Clock().Now)
+}
+
+// isOrdererUnavailable returns true if the error shows that the orderer couldn't be reached or didn't
+// respond in time. Errors returned by an orderer that processed the request (e.g. BAD_REQUEST or
+// FORBIDDEN) don't count as failures of the orderer.
+func isOrdererUnavailable(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		// Not a status, so the envelope couldn't be sent or the stream broke
+		return true
+	}
+
+	switch s.Group {
+	case status.OrdererClientStatus:
+		return status.ToSDKStatusCode(s.Code) == status.ConnectionFailed
+	case status.GRPCTransportStatus:
+		code := status.ToGRPCStatusCode(s.Code)
+		return code == grpcCodes.Unavailable || code == grpcCodes.DeadlineExceeded
+	default:
+		return false
+	}
+}
+
+// order returns the orderers in the order in which they should be tried: the available orderers
+// starting at the next position of the rotation, followed by the orderers that are backing off
+// (those whose backoff ends first come first)
+func (s *OrdererSelector) order(candidates []fab.Orderer) []fab.Orderer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	start := s.next % len(candidates)
+	s.next++
+
+	now := s.now()
+	var available, backingOff []fab.Orderer
+	for i := range candidates {
+		o := candidates[(start+i)%len(candidates)]
+		if f, ok := s.failures[o.URL()]; ok && now.Before(f.skipUntil) {
+			backingOff = append(backingOff, o)
+		} else {
+			available = append(available, o)
+		}
+	}
+
+	sort.SliceStable(backingOff, func(i, j int) bool {
+		return s.failures[backingOff[i].URL()].skipUntil.Before(s.failures[backingOff[j].URL()].skipUntil)
+	})
+
+	return append(available, backingOff...)
+}
+
+// success clears the failure memory of the orderer
+func (s *OrdererSelector) success(url string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.failures, url)
+}
+
+// failure records a failure of the orderer and extends its backoff period
+func (s *OrdererSelector) failure(url string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	f, ok := s.failures[url]
+	if !ok {
+		f = &ordererFailure{}
+		s.failures[url] = f
+	}
+	f.count++
+
+	backoff := ordererBackoffInitial
+	for i := 1; i < f.count && backoff < ordererBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > ordererBackoffMax {
+		backoff = ordererBackoffMax
+	}
+	f.skipUntil = s.now().Add(backoff)
+
+	logger.Debugf("orderer [%s] failed %d time(s) - skipping it for %s", url, f.count, backoff)
+}
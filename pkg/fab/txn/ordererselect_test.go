@@ -0,0 +1,100 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	grpcCodes "google.golang.org/grpc/codes"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestOrdererSelection(t *testing.T) {
+	now := time.Now()
+	s := NewOrdererSelector(func() time.Time { return now })
+
+	o1 := mocks.NewMockOrderer("orderer1", nil)
+	o2 := mocks.NewMockOrderer("orderer2", nil)
+	o3 := mocks.NewMockOrderer("orderer3", nil)
+	candidates := []fab.Orderer{o1, o2, o3}
+
+	// The first orderer is rotated
+	assert.Equal(t, []string{"orderer1", "orderer2", "orderer3"}, urls(s.order(candidates)))
+	assert.Equal(t, []string{"orderer2", "orderer3", "orderer1"}, urls(s.order(candidates)))
+	assert.Equal(t, []string{"orderer3", "orderer1", "orderer2"}, urls(s.order(candidates)))
+
+	// Failed orderers are tried last, the one whose backoff ends first comes first
+	s.failure("orderer1")
+	s.failure("orderer1")
+	s.failure("orderer2")
+	assert.Equal(t, []string{"orderer3", "orderer2", "orderer1"}, urls(s.order(candidates)))
+	assert.Equal(t, 2*ordererBackoffInitial, s.failures["orderer1"].skipUntil.Sub(now))
+
+	// Once the backoff has elapsed the orderer is available again
+	now = now.Add(ordererBackoffInitial)
+	assert.Equal(t, []string{"orderer2", "orderer3", "orderer1"}, urls(s.order(candidates)))
+
+	// A success clears the failure memory
+	s.success("orderer1")
+	assert.Equal(t, []string{"orderer3", "orderer1", "orderer2"}, urls(s.order(candidates)))
+
+	// The backoff is capped
+	for i := 0; i < 10; i++ {
+		s.failure("orderer3")
+	}
+	assert.Equal(t, ordererBackoffMax, s.failures["orderer3"].skipUntil.Sub(now))
+}
+
+func TestIsOrdererUnavailable(t *testing.T) {
+	assert.True(t, isOrdererUnavailable(errors.New("broken stream")))
+	assert.True(t, isOrdererUnavailable(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)))
+	assert.True(t, isOrdererUnavailable(status.New(status.GRPCTransportStatus, int32(grpcCodes.Unavailable), "unavailable", nil)))
+	assert.True(t, isOrdererUnavailable(errors.Wrap(status.New(status.GRPCTransportStatus, int32(grpcCodes.DeadlineExceeded), "deadline exceeded", nil), "broadcast failed")))
+
+	assert.False(t, isOrdererUnavailable(status.New(status.OrdererServerStatus, int32(common.Status_BAD_REQUEST), "bad request", nil)))
+	assert.False(t, isOrdererUnavailable(status.New(status.OrdererServerStatus, int32(common.Status_FORBIDDEN), "forbidden", nil)))
+	assert.False(t, isOrdererUnavailable(status.New(status.GRPCTransportStatus, int32(grpcCodes.PermissionDenied), "permission denied", nil)))
+}
+
+func TestOrdererSelectionPerChannelContext(t *testing.T) {
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+	assert.True(t, ordererSelection(reqCtx) != ordererSelection(reqCtx), "expecting a new selector without a channel context")
+
+	chCtx := mocks.NewMockChannelContext(ctx, "mychannel")
+	chCtx.Channel = &selectorChannelService{MockChannelService: &mocks.MockChannelService{}, selector: NewOrdererSelector(time.Now)}
+	reqCtx, cancel = context.NewRequest(chCtx, context.WithTimeout(10*time.Second))
+	defer cancel()
+	assert.True(t, ordererSelection(reqCtx) == ordererSelection(reqCtx), "expecting the selector of the channel context")
+}
+
+type selectorChannelService struct {
+	*mocks.MockChannelService
+	selector *OrdererSelector
+}
+
+func (cs *selectorChannelService) OrdererSelector() *OrdererSelector {
+	return cs.selector
+}
+
+func urls(orderers []fab.Orderer) []string {
+	var result []string
+	for _, o := range orderers {
+		result = append(result, o.URL())
+	}
+	return result
+}
@@ -17,9 +17,7 @@ import (
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
-	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
-	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
 )
 
 // CreateChaincodeInvokeProposal creates a proposal for transaction.
@@ -32,21 +30,36 @@ func CreateChaincodeInvokeProposal(txh fab.TransactionHeader, request fab.Chainc
 		return nil, errors.New("Fcn is required")
 	}
 
-	// Add function name to arguments
-	argsArray := make([][]byte, len(request.Args)+1)
-	argsArray[0] = []byte(request.Fcn)
-	for i, arg := range request.Args {
-		argsArray[i+1] = arg
+	b := getProposalBuilder()
+	defer b.release()
+
+	proposal, err := b.build(txh, request)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create chaincode proposal")
+	}
+
+	tp := fab.TransactionProposal{
+		TxnID:    txh.TransactionID(),
+		Proposal: proposal,
+	}
+
+	return &tp, nil
+}
+
+// CreateChaincodeProposalWithPayload creates a proposal for an invocation of the given chaincode
+// whose ChaincodeProposalPayload is already marshalled. This allows large payloads (e.g. chaincode
+// packages) to be built without holding intermediate copies of them.
+func CreateChaincodeProposalWithPayload(txh fab.TransactionHeader, ccID string, payload []byte) (*fab.TransactionProposal, error) {
+	if ccID == "" {
+		return nil, errors.New("ChaincodeID is required")
 	}
 
-	// create invocation spec to target a chaincode with arguments
-	ccis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
-		Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: request.ChaincodeID},
-		Input: &pb.ChaincodeInput{Args: argsArray}}}
+	b := getProposalBuilder()
+	defer b.release()
 
-	proposal, _, err := protos_utils.CreateChaincodeProposalWithTxIDNonceAndTransient(string(txh.TransactionID()), common.HeaderType_ENDORSER_TRANSACTION, txh.ChannelID(), ccis, txh.Nonce(), txh.Creator(), request.TransientMap)
+	proposal, err := b.buildWithPayload(txh, ccID, payload)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create chaincode proposal")
+		return nil, errors.WithMessage(err, "failed to create chaincode proposal")
 	}
 
 	tp := fab.TransactionProposal{
@@ -0,0 +1,145 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+// maxPooledBufferSize is the maximum size of a marshal buffer that is kept for reuse,
+// so that a large proposal doesn't pin its buffer in the pool
+const maxPooledBufferSize = 1024 * 1024
+
+var proposalBuilders = sync.Pool{
+	New: func() interface{} {
+		return &proposalBuilder{}
+	},
+}
+
+// proposalBuilder builds chaincode proposals. The intermediate messages (headers, extension,
+// invocation spec) and the marshal buffer are only needed until they are marshalled, so they
+// are reused from one proposal to the next rather than allocated for each request.
+type proposalBuilder struct {
+	args      [][]byte
+	ccID      pb.ChaincodeID
+	input     pb.ChaincodeInput
+	spec      pb.ChaincodeSpec
+	cis       pb.ChaincodeInvocationSpec
+	ext       pb.ChaincodeHeaderExtension
+	payload   pb.ChaincodeProposalPayload
+	chHeader  common.ChannelHeader
+	sigHeader common.SignatureHeader
+	header    common.Header
+	buf       proto.Buffer
+}
+
+func getProposalBuilder() *proposalBuilder {
+	return proposalBuilders.Get().(*proposalBuilder)
+}
+
+// release clears the references to the request and returns the builder to the pool
+func (b *proposalBuilder) release() {
+	for i := range b.args {
+		b.args[i] = nil
+	}
+	b.args = b.args[:0]
+	b.ccID.Reset()
+	b.input.Reset()
+	b.spec.Reset()
+	b.cis.Reset()
+	b.ext.Reset()
+	b.payload.Reset()
+	b.chHeader.Reset()
+	b.sigHeader.Reset()
+	b.header.Reset()
+	if cap(b.buf.Bytes()) > maxPooledBufferSize {
+		b.buf.SetBuf(nil)
+	}
+	proposalBuilders.Put(b)
+}
+
+// build creates the proposal of the given chaincode invocation
+func (b *proposalBuilder) build(txh fab.TransactionHeader, request fab.ChaincodeInvokeRequest) (*pb.Proposal, error) {
+	// Add function name to arguments
+	b.args = append(b.args[:0], []byte(request.Fcn))
+	b.args = append(b.args, request.Args...)
+
+	// create invocation spec to target a chaincode with arguments
+	b.ccID.Name = request.ChaincodeID
+	b.input.Args = b.args
+	b.spec.Type = pb.ChaincodeSpec_GOLANG
+	b.spec.ChaincodeId = &b.ccID
+	b.spec.Input = &b.input
+	b.cis.ChaincodeSpec = &b.spec
+
+	cisBytes, err := b.marshal(&b.cis)
+	if err != nil {
+		return nil, err
+	}
+
+	b.payload.Input = cisBytes
+	b.payload.TransientMap = request.TransientMap
+	payloadBytes, err := b.marshal(&b.payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.buildWithPayload(txh, request.ChaincodeID, payloadBytes)
+}
+
+// buildWithPayload creates the proposal of an invocation of the given chaincode with the given marshalled payload
+func (b *proposalBuilder) buildWithPayload(txh fab.TransactionHeader, ccID string, payload []byte) (*pb.Proposal, error) {
+	b.ccID.Name = ccID
+	b.ext.ChaincodeId = &b.ccID
+	extBytes, err := b.marshal(&b.ext)
+	if err != nil {
+		return nil, err
+	}
+
+	b.chHeader.Type = int32(common.HeaderType_ENDORSER_TRANSACTION)
+	b.chHeader.TxId = string(txh.TransactionID())
+	b.chHeader.Timestamp = util.CreateUtcTimestamp()
+	b.chHeader.ChannelId = txh.ChannelID()
+	b.chHeader.Extension = extBytes
+	chHeaderBytes, err := b.marshal(&b.chHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	b.sigHeader.Nonce = txh.Nonce()
+	b.sigHeader.Creator = txh.Creator()
+	sigHeaderBytes, err := b.marshal(&b.sigHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	b.header.ChannelHeader = chHeaderBytes
+	b.header.SignatureHeader = sigHeaderBytes
+	headerBytes, err := b.marshal(&b.header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Proposal{Header: headerBytes, Payload: payload}, nil
+}
+
+// marshal marshals the message into the reused buffer and returns a copy of the bytes
+func (b *proposalBuilder) marshal(msg proto.Message) ([]byte, error) {
+	b.buf.Reset()
+	if err := b.buf.Marshal(msg); err != nil {
+		return nil, errors.Wrap(err, "marshal of proposal message failed")
+	}
+	return append([]byte(nil), b.buf.Bytes()...), nil
+}
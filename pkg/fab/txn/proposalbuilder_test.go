@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestProposalBuilder(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	txh, err := NewHeader(ctx, testChannel)
+	require.NoError(t, err)
+
+	request := fab.ChaincodeInvokeRequest{
+		ChaincodeID:  "mycc",
+		Fcn:          "move",
+		Args:         [][]byte{[]byte("a"), []byte("b")},
+		TransientMap: map[string][]byte{"key": []byte("value")},
+	}
+
+	// Build several proposals so that the pooled builders are reused
+	for i := 0; i < 3; i++ {
+		tp, err := CreateChaincodeInvokeProposal(txh, request)
+		require.NoError(t, err)
+		assert.Equal(t, txh.TransactionID(), tp.TxnID)
+
+		expected, _, err := protos_utils.CreateChaincodeProposalWithTxIDNonceAndTransient(string(txh.TransactionID()), common.HeaderType_ENDORSER_TRANSACTION, txh.ChannelID(),
+			&pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: "mycc"},
+				Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("move"), []byte("a"), []byte("b")}}}},
+			txh.Nonce(), txh.Creator(), request.TransientMap)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Payload, tp.Proposal.Payload)
+
+		header := &common.Header{}
+		require.NoError(t, proto.Unmarshal(tp.Proposal.Header, header))
+		expectedHeader := &common.Header{}
+		require.NoError(t, proto.Unmarshal(expected.Header, expectedHeader))
+		assert.Equal(t, expectedHeader.SignatureHeader, header.SignatureHeader)
+
+		chHeader := &common.ChannelHeader{}
+		require.NoError(t, proto.Unmarshal(header.ChannelHeader, chHeader))
+		expectedChHeader := &common.ChannelHeader{}
+		require.NoError(t, proto.Unmarshal(expectedHeader.ChannelHeader, expectedChHeader))
+		require.NotNil(t, chHeader.Timestamp)
+		chHeader.Timestamp = expectedChHeader.Timestamp
+		assert.True(t, proto.Equal(expectedChHeader, chHeader))
+	}
+}
+
+func BenchmarkCreateChaincodeInvokeProposal(b *testing.B) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	txh, err := NewHeader(ctx, testChannel)
+	if err != nil {
+		b.Fatalf("create transaction header failed: %s", err)
+	}
+	request := fab.ChaincodeInvokeRequest{ChaincodeID: "mycc", Fcn: "move", Args: [][]byte{[]byte("a"), []byte("b")}}
+
+	b.ReportAllocs()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			if _, err := CreateChaincodeInvokeProposal(txh, request); err != nil {
+				b.Fatalf("create proposal failed: %s", err)
+			}
+		}
+	})
+}
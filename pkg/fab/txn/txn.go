@@ -9,12 +9,12 @@ package txn
 
 import (
 	reqContext "context"
-	"math/rand"
 
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/readonly"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
@@ -144,6 +144,12 @@ func BroadcastPayload(reqCtx reqContext.Context, payload *common.Payload, ordere
 	if !ok {
 		return nil, errors.New("failed get client context from reqContext for signPayload")
 	}
+
+	// Nothing is sent to the orderer in read-only mode, whichever client built the payload
+	if err := readonly.Check(ctx, "broadcast to orderer"); err != nil {
+		return nil, err
+	}
+
 	envelope, err := signPayload(ctx, payload)
 	if err != nil {
 		return nil, err
@@ -160,23 +166,30 @@ func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope,
 		return nil, errors.New("orderers not set")
 	}
 
-	// Copy aside the ordering service endpoints
-	randOrderers := []fab.Orderer{}
-	randOrderers = append(randOrderers, orderers...)
-
-	// Iterate them in a random order and try broadcasting 1 by 1
+	// Try the ordering service endpoints 1 by 1, rotating the first one and skipping the ones that failed recently
+	selector := ordererSelection(reqCtx)
 	var errResp error
-	for _, i := range rand.Perm(len(randOrderers)) {
-		resp, err := sendBroadcast(reqCtx, envelope, randOrderers[i])
+	for _, o := range selector.order(orderers) {
+		resp, err := sendBroadcast(reqCtx, envelope, o)
 		if err != nil {
 			errResp = err
+			recordOrdererFailure(reqCtx, selector, o, err)
 		} else {
+			selector.success(o.URL())
 			return resp, nil
 		}
 	}
 	return nil, errResp
 }
 
+// recordOrdererFailure remembers that the orderer failed if it couldn't be reached, unless the request
+// was cancelled or timed out, which isn't the orderer's fault
+func recordOrdererFailure(reqCtx reqContext.Context, selector *OrdererSelector, o fab.Orderer, err error) {
+	if reqCtx.Err() == nil && isOrdererUnavailable(err) {
+		selector.failure(o.URL())
+	}
+}
+
 func sendBroadcast(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer) (*fab.TransactionResponse, error) {
 	logger.Debugf("Broadcasting envelope to orderer :%s\n", orderer.URL())
 	// Send request
@@ -204,17 +217,16 @@ func SendPayload(reqCtx reqContext.Context, payload *common.Payload, orderers []
 		return nil, err
 	}
 
-	// Copy aside the ordering service endpoints
-	randOrderers := []fab.Orderer{}
-	randOrderers = append(randOrderers, orderers...)
-
-	// Iterate them in a random order and try broadcasting 1 by 1
+	// Try the ordering service endpoints 1 by 1, rotating the first one and skipping the ones that failed recently
+	selector := ordererSelection(reqCtx)
 	var errResp error
-	for _, i := range rand.Perm(len(randOrderers)) {
-		resp, err := sendEnvelope(reqCtx, envelope, randOrderers[i])
+	for _, o := range selector.order(orderers) {
+		resp, err := sendEnvelope(reqCtx, envelope, o)
 		if err != nil {
 			errResp = err
+			recordOrdererFailure(reqCtx, selector, o, err)
 		} else {
+			selector.success(o.URL())
 			return resp, nil
 		}
 	}
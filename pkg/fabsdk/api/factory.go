@@ -10,6 +10,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"google.golang.org/grpc"
 )
 
 // Providers represents the SDK configured providers context.
@@ -26,12 +27,25 @@ type CoreProviderFactory interface {
 	CreateInfraProvider(config fab.EndpointConfig) (fab.InfraProvider, error)
 }
 
+// DialOptsInfraProviderFactory is implemented by core provider factories that are able to create
+// an infra provider which applies additional GRPC dial options (such as interceptors) to all of
+// its connections
+type DialOptsInfraProviderFactory interface {
+	CreateInfraProviderWithDialOpts(config fab.EndpointConfig, opts ...grpc.DialOption) (fab.InfraProvider, error)
+}
+
 // MSPProviderFactory allows overriding providers of MSP services
 type MSPProviderFactory interface {
 	CreateUserStore(config msp.IdentityConfig) (msp.UserStore, error)
 	CreateIdentityManagerProvider(config fab.EndpointConfig, cryptoProvider core.CryptoSuite, userStore msp.UserStore) (msp.IdentityManagerProvider, error)
 }
 
+// CryptoSuiteUserStoreFactory is implemented by MSP provider factories that are able to create a
+// user store which takes keys from the SDK's crypto suite (e.g. the key that encrypts the user data)
+type CryptoSuiteUserStoreFactory interface {
+	CreateUserStoreWithCryptoSuite(config msp.IdentityConfig, cryptoSuite core.CryptoSuite) (msp.UserStore, error)
+}
+
 // ServiceProviderFactory allows overriding default service providers (such as peer discovery)
 type ServiceProviderFactory interface {
 	CreateDiscoveryProvider(config fab.EndpointConfig) (fab.DiscoveryProvider, error)
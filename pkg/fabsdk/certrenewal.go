@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/pkg/errors"
+)
+
+// WithCertRenewal makes the SDK watch the enrollment certificates of the identities in its user store
+// (see msp.CertRenewalService). The identities that are enrolled or used through the SDK are watched,
+// as well as the identities given in the options. Expiry warnings are emitted on the events channel
+// of the service (see CertRenewalService) and identities are re-enrolled with the CA of their
+// organization if RenewBefore is set. The identities of tenants aren't watched.
+func WithCertRenewal(renewalOpts mspImpl.CertRenewalOpts) Option {
+	return func(opts *options) error {
+		if renewalOpts.Interval < 0 || renewalOpts.WarnBefore < 0 || renewalOpts.RenewBefore < 0 {
+			return errors.New("certificate renewal durations must not be negative")
+		}
+		opts.CertRenewal = &renewalOpts
+		return nil
+	}
+}
+
+// CertRenewalService returns the service that watches the enrollment certificates, or nil
+// if certificate renewal isn't enabled (see WithCertRenewal)
+func (sdk *FabricSDK) CertRenewalService() *mspImpl.CertRenewalService {
+	return sdk.certRenewal
+}
+
+// initCertRenewal creates the certificate renewal service, if it is enabled, and returns the user store
+// that watches the identities
+func (sdk *FabricSDK) initCertRenewal(userStore msp.UserStore) (msp.UserStore, error) {
+	if sdk.opts.CertRenewal == nil {
+		return userStore, nil
+	}
+	renewalOpts := *sdk.opts.CertRenewal
+	if renewalOpts.Clock == nil {
+		renewalOpts.Clock = sdk.opts.Clock
+	}
+	service, err := mspImpl.NewCertRenewalService(userStore, sdk.reenroll, renewalOpts)
+	if err != nil {
+		return nil, err
+	}
+	sdk.certRenewal = service
+	return service.WatchUserStore(userStore), nil
+}
+
+// reenroll re-enrolls an identity with the CA of the organization of its MSP
+func (sdk *FabricSDK) reenroll(id msp.IdentityIdentifier, newKey bool) error {
+	netConfig, err := sdk.provider.EndpointConfig().NetworkConfig()
+	if err != nil {
+		return errors.Wrap(err, "network config retrieval failed")
+	}
+
+	for orgName, orgConfig := range netConfig.Organizations {
+		if orgConfig.MSPID != id.MSPID {
+			continue
+		}
+		caClient, err := mspImpl.NewCAClient(orgName, &context.Client{Providers: sdk.provider})
+		if err != nil {
+			return errors.WithMessage(err, "failed to create CA client")
+		}
+		return caClient.Reenroll(&api.ReenrollmentRequest{Name: id.ID, NewKey: newKey})
+	}
+	return errors.Errorf("organization of MSP [%s] not found", id.MSPID)
+}
@@ -0,0 +1,36 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertRenewal(t *testing.T) {
+	_, err := New(config.FromFile(sdkConfigFile), WithCertRenewal(mspImpl.CertRenewalOpts{Interval: -time.Hour}))
+	assert.Error(t, err, "expecting error for negative interval")
+
+	sdk, err := New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	assert.Nil(t, sdk.CertRenewalService())
+	sdk.Close()
+
+	sdk, err = New(config.FromFile(sdkConfigFile), WithCertRenewal(mspImpl.CertRenewalOpts{RenewBefore: 7 * 24 * time.Hour}))
+	require.NoError(t, err)
+	service := sdk.CertRenewalService()
+	require.NotNil(t, service)
+
+	sdk.Close()
+	_, ok := <-service.Events()
+	assert.False(t, ok, "expecting events channel to be closed when the SDK is closed")
+}
@@ -0,0 +1,170 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+)
+
+// ConnectionType is the type of endpoint of a connection
+type ConnectionType int
+
+const (
+	// UnknownConnection is a connection to an endpoint that isn't in the configuration of the SDK
+	UnknownConnection ConnectionType = iota
+	// PeerConnection is a connection to a peer
+	PeerConnection
+	// OrdererConnection is a connection to an orderer
+	OrdererConnection
+	// EventConnection is an event stream from a peer (or event hub)
+	EventConnection
+)
+
+// String returns the name of the connection type
+func (t ConnectionType) String() string {
+	switch t {
+	case PeerConnection:
+		return "peer"
+	case OrdererConnection:
+		return "orderer"
+	case EventConnection:
+		return "event"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionEvent describes a change in the state of a connection
+type ConnectionEvent struct {
+	// Type is the type of endpoint
+	Type ConnectionType
+	// Target is the address of the endpoint
+	Target string
+	// Err is the error that caused the failure or disconnection (if any)
+	Err error
+}
+
+// ConnectionHook is a callback that is invoked on a connection lifecycle event. Hooks are
+// invoked synchronously by the goroutine that observed the event, so they must not block.
+type ConnectionHook func(event *ConnectionEvent)
+
+// connectionHooks dispatches the connection lifecycle events to the hooks registered on the SDK
+type connectionHooks struct {
+	sdk          *FabricSDK
+	lock         sync.RWMutex
+	registered   bool
+	onConnect    []ConnectionHook
+	onDisconnect []ConnectionHook
+	onError      []ConnectionHook
+}
+
+// OnConnect registers a hook that is invoked when a connection to a peer or orderer is
+// established or when an event stream is connected
+func (sdk *FabricSDK) OnConnect(hook ConnectionHook) {
+	sdk.connHooks.add(&sdk.connHooks.onConnect, hook)
+}
+
+// OnDisconnect registers a hook that is invoked when a connection to a peer or orderer is
+// closed or lost or when an event stream is disconnected. The error of the event is nil if
+// the connection was closed by the SDK (e.g. because it was idle).
+func (sdk *FabricSDK) OnDisconnect(hook ConnectionHook) {
+	sdk.connHooks.add(&sdk.connHooks.onDisconnect, hook)
+}
+
+// OnError registers a hook that is invoked when a connection to a peer, orderer or event
+// server couldn't be established
+func (sdk *FabricSDK) OnError(hook ConnectionHook) {
+	sdk.connHooks.add(&sdk.connHooks.onError, hook)
+}
+
+func (h *connectionHooks) add(hooks *[]ConnectionHook, hook ConnectionHook) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	*hooks = append(*hooks, hook)
+
+	// The listener is only registered once a hook has been added since the
+	// connection events of all of the SDK instances in the process are dispatched to it
+	if !h.registered {
+		comm.AddConnectionListener(h)
+		h.registered = true
+	}
+}
+
+// close unregisters the hooks from the connection lifecycle events
+func (h *connectionHooks) close() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.registered {
+		comm.RemoveConnectionListener(h)
+		h.registered = false
+	}
+}
+
+// OnConnect dispatches a connect event to the hooks
+func (h *connectionHooks) OnConnect(event *comm.ConnectionEvent) {
+	h.dispatch(h.hooks(&h.onConnect), event)
+}
+
+// OnDisconnect dispatches a disconnect event to the hooks
+func (h *connectionHooks) OnDisconnect(event *comm.ConnectionEvent) {
+	h.dispatch(h.hooks(&h.onDisconnect), event)
+}
+
+// OnError dispatches a connection failure to the hooks
+func (h *connectionHooks) OnError(event *comm.ConnectionEvent) {
+	h.dispatch(h.hooks(&h.onError), event)
+}
+
+func (h *connectionHooks) hooks(hooks *[]ConnectionHook) []ConnectionHook {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+	return *hooks
+}
+
+func (h *connectionHooks) dispatch(hooks []ConnectionHook, event *comm.ConnectionEvent) {
+	if len(hooks) == 0 {
+		return
+	}
+
+	e := &ConnectionEvent{Type: h.connectionType(event), Target: event.Target, Err: event.Err}
+	for _, hook := range hooks {
+		hook(e)
+	}
+}
+
+// connectionType resolves the type of endpoint from the configuration of the SDK
+func (h *connectionHooks) connectionType(event *comm.ConnectionEvent) ConnectionType {
+	if event.Stream {
+		return EventConnection
+	}
+
+	endpointConfig := h.sdk.provider.EndpointConfig()
+	target := endpoint.ToAddress(event.Target)
+
+	if peers, err := endpointConfig.NetworkPeers(); err == nil {
+		for _, p := range peers {
+			if endpoint.ToAddress(p.URL) == target {
+				return PeerConnection
+			}
+		}
+	}
+
+	if orderers, err := endpointConfig.OrderersConfig(); err == nil {
+		for _, o := range orderers {
+			if endpoint.ToAddress(o.URL) == target {
+				return OrdererConnection
+			}
+		}
+	}
+
+	return UnknownConnection
+}
@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionHooks(t *testing.T) {
+	sdk, err := New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+
+	peers, err := sdk.provider.EndpointConfig().NetworkPeers()
+	require.NoError(t, err)
+	require.NotEmpty(t, peers)
+	orderers, err := sdk.provider.EndpointConfig().OrderersConfig()
+	require.NoError(t, err)
+	require.NotEmpty(t, orderers)
+
+	var connected, disconnected, failed []*ConnectionEvent
+	sdk.OnConnect(func(event *ConnectionEvent) { connected = append(connected, event) })
+	sdk.OnDisconnect(func(event *ConnectionEvent) { disconnected = append(disconnected, event) })
+	sdk.OnError(func(event *ConnectionEvent) { failed = append(failed, event) })
+
+	comm.NotifyConnect(&comm.ConnectionEvent{Target: peers[0].URL})
+	comm.NotifyConnect(&comm.ConnectionEvent{Target: peers[0].URL, Stream: true})
+	comm.NotifyDisconnect(&comm.ConnectionEvent{Target: orderers[0].URL, Err: errors.New("lost")})
+	comm.NotifyError(&comm.ConnectionEvent{Target: "unknown:7051", Err: errors.New("refused")})
+
+	require.Len(t, connected, 2)
+	assert.Equal(t, PeerConnection, connected[0].Type)
+	assert.Equal(t, EventConnection, connected[1].Type)
+
+	require.Len(t, disconnected, 1)
+	assert.Equal(t, OrdererConnection, disconnected[0].Type)
+	assert.EqualError(t, disconnected[0].Err, "lost")
+
+	require.Len(t, failed, 1)
+	assert.Equal(t, UnknownConnection, failed[0].Type)
+	assert.Equal(t, "unknown:7051", failed[0].Target)
+
+	sdk.Close()
+
+	comm.NotifyConnect(&comm.ConnectionEvent{Target: peers[0].URL})
+	assert.Len(t, connected, 2, "expecting hooks to be unregistered when the SDK is closed")
+}
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package fabsdk
 
 import (
+	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/pkg/errors"
 )
 
@@ -44,12 +46,25 @@ func WithOrg(org string) ContextOption {
 	}
 }
 
+// newClientProvider returns a provider of client contexts that are backed by the given providers
+func newClientProvider(provider *context.Provider, options ...ContextOption) contextApi.ClientProvider {
+	return func() (contextApi.Client, error) {
+		identity, err := newIdentity(provider, options...)
+		if err == ErrAnonymousIdentity {
+			identity = nil
+			err = nil
+		}
+		return &context.Client{Providers: provider, SigningIdentity: identity}, err
+	}
+}
+
 // ErrAnonymousIdentity is returned when options for identity creation
 // don't include neither username nor identity
 var ErrAnonymousIdentity = errors.New("missing credentials")
 
-func (sdk *FabricSDK) newIdentity(options ...ContextOption) (msp.SigningIdentity, error) { //nolint
-	clientConfig, err := sdk.provider.IdentityConfig().Client()
+// newIdentity resolves the identity described by the options using the identity managers of the given providers
+func newIdentity(provider contextApi.Providers, options ...ContextOption) (msp.SigningIdentity, error) { //nolint
+	clientConfig, err := provider.IdentityConfig().Client()
 	if err != nil {
 		return nil, errors.WithMessage(err, "retrieving client configuration failed")
 	}
@@ -77,7 +92,7 @@ func (sdk *FabricSDK) newIdentity(options ...ContextOption) (msp.SigningIdentity
 		return nil, errors.New("invalid options to create identity")
 	}
 
-	mgr, ok := sdk.provider.IdentityManager(opts.orgName)
+	mgr, ok := provider.IdentityManager(opts.orgName)
 	if !ok {
 		return nil, errors.New("invalid options to create identity, invalid org name")
 	}
@@ -0,0 +1,139 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+	"github.com/pkg/errors"
+)
+
+// ChannelContextPool is a pool of channel contexts keyed by identity and channel. A channel context
+// is created the first time it's requested for an identity and channel and is then shared by all
+// callers, so that the context doesn't have to be created for every request. Channel contexts are
+// safe for concurrent use.
+//
+// Since the key includes the enrollment certificate of the identity, a new context is created
+// after the identity has been re-enrolled. The context of the previous certificate remains in the
+// pool until it's evicted or until the pool is closed.
+type ChannelContextPool struct {
+	provider *context.Provider
+	cache    *lazycache.Cache
+}
+
+func newChannelContextPool(provider *context.Provider) *ChannelContextPool {
+	return &ChannelContextPool{
+		provider: provider,
+		cache: lazycache.New(
+			"Channel_Context_Cache",
+			func(key lazycache.Key) (interface{}, error) {
+				ck := key.(*contextKey)
+				logger.Debugf("Creating channel context for [%s]", ck)
+				return context.NewChannel(func() (contextApi.Client, error) {
+					return &context.Client{Providers: provider, SigningIdentity: ck.identity}, nil
+				}, ck.channelID)
+			},
+		),
+	}
+}
+
+// ChannelContextPool returns the pool of channel contexts of the SDK. The pool is closed when the SDK is closed.
+func (sdk *FabricSDK) ChannelContextPool() *ChannelContextPool {
+	return sdk.contextPool
+}
+
+// Get returns the channel context for the given channel and identity, creating it if necessary.
+//  Parameters:
+//  channelID is the ID of the channel
+//  options identify the user (see WithUser, WithIdentity and WithOrg)
+//
+//  Returns:
+//  the pooled channel context
+func (p *ChannelContextPool) Get(channelID string, options ...ContextOption) (contextApi.Channel, error) {
+	key, err := p.newKey(channelID, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := p.cache.Get(key)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel context")
+	}
+	return ctx.(contextApi.Channel), nil
+}
+
+// Provider returns a channel context provider that is backed by the pool. The provider
+// may be passed to a client constructor, such as channel.New.
+func (p *ChannelContextPool) Provider(channelID string, options ...ContextOption) contextApi.ChannelProvider {
+	return func() (contextApi.Channel, error) {
+		return p.Get(channelID, options...)
+	}
+}
+
+// Evict removes the channel context for the given channel and identity from the pool. The next
+// request for the context creates a new one. Clients that hold on to the evicted context may
+// continue to use it.
+func (p *ChannelContextPool) Evict(channelID string, options ...ContextOption) error {
+	key, err := p.newKey(channelID, options...)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Evicting channel context for [%s]", key)
+	p.cache.Delete(key)
+	return nil
+}
+
+// Clear removes all of the channel contexts from the pool. The pool remains usable.
+func (p *ChannelContextPool) Clear() {
+	p.cache.Clear()
+}
+
+// Close removes all of the channel contexts from the pool. Subsequent requests for a context fail.
+func (p *ChannelContextPool) Close() {
+	p.cache.Close()
+}
+
+func (p *ChannelContextPool) newKey(channelID string, options ...ContextOption) (*contextKey, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	identity, err := newIdentity(p.provider, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to resolve identity for channel context")
+	}
+	return newContextKey(channelID, identity), nil
+}
+
+// contextKey is the key of a pooled channel context
+type contextKey struct {
+	channelID string
+	identity  msp.SigningIdentity
+	key       string
+}
+
+func newContextKey(channelID string, identity msp.SigningIdentity) *contextKey {
+	certHash := sha256.Sum256(identity.EnrollmentCertificate())
+	id := identity.Identifier()
+
+	return &contextKey{
+		channelID: channelID,
+		identity:  identity,
+		key:       channelID + "/" + id.MSPID + "/" + id.ID + "/" + hex.EncodeToString(certHash[:]),
+	}
+}
+
+// String returns the key of the context
+func (k *contextKey) String() string {
+	return k.key
+}
@@ -0,0 +1,76 @@
+// +build testing
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/fabpvdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelContextPool(t *testing.T) {
+	sdk, err := New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	sdk.provider.InfraProvider().(*fabpvdr.InfraProvider).SetChannelConfig(mocks.NewMockChannelCfg("mychannel"))
+	sdk.provider.InfraProvider().(*fabpvdr.InfraProvider).SetChannelConfig(mocks.NewMockChannelCfg("orgchannel"))
+
+	pool := sdk.ChannelContextPool()
+
+	var wg sync.WaitGroup
+	contexts := make([]context.Channel, 10)
+	for i := range contexts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			contexts[i], _ = pool.Get("mychannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg1))
+		}(i)
+	}
+	wg.Wait()
+
+	require.NotNil(t, contexts[0])
+	for _, ctx := range contexts {
+		assert.True(t, ctx == contexts[0], "expecting the same context to be shared")
+	}
+	assert.Equal(t, "mychannel", contexts[0].ChannelID())
+
+	other, err := pool.Get("orgchannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg1))
+	require.NoError(t, err)
+	assert.False(t, other == contexts[0], "expecting a different context for another channel")
+
+	other, err = pool.Get("mychannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg2))
+	require.NoError(t, err)
+	assert.False(t, other == contexts[0], "expecting a different context for another identity")
+
+	ctx, err := pool.Provider("mychannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg1))()
+	require.NoError(t, err)
+	assert.True(t, ctx == contexts[0], "expecting provider to return the pooled context")
+
+	require.NoError(t, pool.Evict("mychannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg1)))
+	ctx, err = pool.Get("mychannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg1))
+	require.NoError(t, err)
+	assert.False(t, ctx == contexts[0], "expecting a new context after eviction")
+
+	_, err = pool.Get("mychannel")
+	assert.Error(t, err, "expecting error for anonymous identity")
+
+	_, err = pool.Get("", WithUser(sdkValidClientUser))
+	assert.Error(t, err, "expecting error for missing channel ID")
+
+	pool.Close()
+	_, err = pool.Get("mychannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg1))
+	assert.Error(t, err, "expecting error after the pool is closed")
+}
@@ -0,0 +1,170 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/pkg/errors"
+)
+
+// Diagnostics is a snapshot of the internal state of the SDK which is intended to be
+// attached to support tickets. It doesn't contain keys, certificates or other secrets.
+type Diagnostics struct {
+	Time            time.Time            `json:"time"`
+	Config          ConfigSummary        `json:"config"`
+	ContextPoolSize int                  `json:"contextPoolSize"`
+	Infra           interface{}          `json:"infra,omitempty"`
+	Selection       SelectionDiagnostics `json:"selection"`
+	Errors          []string             `json:"errors,omitempty"`
+}
+
+// ConfigSummary summarizes the resolved endpoint and identity configuration of the SDK
+type ConfigSummary struct {
+	Organization     string        `json:"organization"`
+	EventServiceType string        `json:"eventServiceType"`
+	Channels         []string      `json:"channels"`
+	Peers            []PeerSummary `json:"peers"`
+	Orderers         []string      `json:"orderers"`
+}
+
+// PeerSummary summarizes the configuration of a peer
+type PeerSummary struct {
+	URL      string            `json:"url"`
+	EventURL string            `json:"eventUrl,omitempty"`
+	MSPID    string            `json:"mspId"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// SelectionDiagnostics contains the state of the selection provider
+type SelectionDiagnostics struct {
+	BlockedPeers []string    `json:"blockedPeers,omitempty"`
+	Stats        interface{} `json:"stats,omitempty"`
+}
+
+// diagnosable is implemented by providers that are able to report their internal state
+type diagnosable interface {
+	Diagnostics() interface{}
+}
+
+// endpointsProvider is implemented by infra providers that report the statistics of their connections
+type endpointsProvider interface {
+	Endpoints() []comm.EndpointInfo
+}
+
+type accessListProvider interface {
+	AccessList() *filter.PeerAccessList
+}
+
+// Diagnostics returns a snapshot of the internal state of the SDK: the resolved configuration,
+// the open connections and their states, the cache sizes, the active event registrations and
+// the stats of the selection provider. Providers that aren't able to report their state are
+// omitted. Errors encountered while collecting the snapshot are included in the snapshot.
+func (sdk *FabricSDK) Diagnostics() *Diagnostics {
+	d := &Diagnostics{
+		Time:            time.Now().UTC(),
+		ContextPoolSize: sdk.contextPool.cache.Len(),
+	}
+
+	if err := sdk.summarizeConfig(&d.Config); err != nil {
+		d.Errors = append(d.Errors, err.Error())
+	}
+
+	if p, ok := sdk.provider.InfraProvider().(diagnosable); ok {
+		d.Infra = p.Diagnostics()
+	}
+
+	selectionProvider := sdk.provider.SelectionProvider()
+	if p, ok := selectionProvider.(accessListProvider); ok && p.AccessList() != nil {
+		d.Selection.BlockedPeers = p.AccessList().Blocked()
+	}
+	if p, ok := selectionProvider.(diagnosable); ok {
+		d.Selection.Stats = p.Diagnostics()
+	}
+
+	return d
+}
+
+// Endpoints returns the connection statistics of every endpoint that the SDK has dialed: the number of
+// connection requests and failures, the last error, the number of streams in use and the connectivity
+// states of the pooled connections. The same statistics are reported by the metrics provider (see the
+// comm_endpoint_* metrics).
+func (sdk *FabricSDK) Endpoints() ([]comm.EndpointInfo, error) {
+	p, ok := sdk.provider.InfraProvider().(endpointsProvider)
+	if !ok {
+		return nil, errors.New("the infra provider doesn't report endpoint statistics")
+	}
+	return p.Endpoints(), nil
+}
+
+// DumpDiagnostics returns the diagnostics of the SDK (see Diagnostics) as an indented JSON document
+func (sdk *FabricSDK) DumpDiagnostics() ([]byte, error) {
+	doc, err := json.MarshalIndent(sdk.Diagnostics(), "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal diagnostics")
+	}
+	return doc, nil
+}
+
+func (sdk *FabricSDK) summarizeConfig(summary *ConfigSummary) error {
+	endpointConfig := sdk.provider.EndpointConfig()
+
+	summary.EventServiceType = eventServiceTypeName(endpointConfig.EventServiceType())
+
+	clientConfig, err := sdk.provider.IdentityConfig().Client()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load client config")
+	}
+	summary.Organization = clientConfig.Organization
+
+	networkConfig, err := endpointConfig.NetworkConfig()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load network config")
+	}
+	for name := range networkConfig.Channels {
+		summary.Channels = append(summary.Channels, name)
+	}
+	sort.Strings(summary.Channels)
+
+	peers, err := endpointConfig.NetworkPeers()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load peer config")
+	}
+	for _, p := range peers {
+		summary.Peers = append(summary.Peers, PeerSummary{URL: p.URL, EventURL: p.EventURL, MSPID: p.MSPID, Labels: p.Labels})
+	}
+	sort.Slice(summary.Peers, func(i, j int) bool {
+		return summary.Peers[i].URL < summary.Peers[j].URL
+	})
+
+	orderers, err := endpointConfig.OrderersConfig()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load orderer config")
+	}
+	for _, o := range orderers {
+		summary.Orderers = append(summary.Orderers, o.URL)
+	}
+	sort.Strings(summary.Orderers)
+
+	return nil
+}
+
+func eventServiceTypeName(t fab.EventServiceType) string {
+	switch t {
+	case fab.DeliverEventServiceType:
+		return "deliver"
+	case fab.EventHubEventServiceType:
+		return "eventhub"
+	default:
+		return "unknown"
+	}
+}
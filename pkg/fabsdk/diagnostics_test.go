@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpDiagnostics(t *testing.T) {
+	sdk, err := New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	doc, err := sdk.DumpDiagnostics()
+	require.NoError(t, err)
+
+	var d Diagnostics
+	require.NoError(t, json.Unmarshal(doc, &d))
+
+	assert.Empty(t, d.Errors)
+	assert.Equal(t, "org1", d.Config.Organization)
+	assert.Equal(t, "deliver", d.Config.EventServiceType)
+	assert.Contains(t, d.Config.Channels, "mychannel")
+	assert.NotEmpty(t, d.Config.Peers)
+	assert.NotEmpty(t, d.Config.Orderers)
+	assert.Equal(t, 0, d.ContextPoolSize)
+	assert.NotNil(t, d.Infra, "expecting diagnostics of the infra provider")
+
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(doc, &raw))
+	infra, ok := raw["infra"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, infra, "connections")
+	assert.Contains(t, infra, "cacheSizes")
+	assert.Contains(t, infra, "eventClients")
+	assert.Contains(t, infra, "endpoints")
+
+	endpoints, err := sdk.Endpoints()
+	require.NoError(t, err)
+	assert.Empty(t, endpoints, "no endpoint has been dialed")
+}
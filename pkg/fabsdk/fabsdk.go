@@ -8,31 +8,53 @@ SPDX-License-Identifier: Apache-2.0
 package fabsdk
 
 import (
+	reqContext "context"
+	"io"
 	"math/rand"
+	"sync"
 	"time"
 
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/replay"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/chpvdr"
 	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/limiter"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 var logger = logging.NewLogger("fabsdk")
 
 // FabricSDK provides access (and context) to clients being managed by the SDK.
 type FabricSDK struct {
-	opts     options
-	provider *context.Provider
+	opts         options
+	provider     *context.Provider
+	contextPool  *ChannelContextPool
+	hooksOnce    sync.Once
+	reloadLock   sync.Mutex
+	retired      retiredProviders
+	tenants      map[string]*Tenant
+	connHooks    connectionHooks
+	certRotation certRotation
+	certRenewal  *mspImpl.CertRenewalService
 }
 
 type configs struct {
@@ -42,23 +64,51 @@ type configs struct {
 }
 
 type options struct {
-	Core              sdkApi.CoreProviderFactory
-	MSP               sdkApi.MSPProviderFactory
-	Service           sdkApi.ServiceProviderFactory
-	Logger            api.LoggerProvider
-	CryptoSuiteConfig core.CryptoSuiteConfig
-	endpointConfig    fab.EndpointConfig
-	IdentityConfig    msp.IdentityConfig
-	ConfigBackend     core.ConfigBackend
+	Core               sdkApi.CoreProviderFactory
+	MSP                sdkApi.MSPProviderFactory
+	Service            sdkApi.ServiceProviderFactory
+	Logger             api.LoggerProvider
+	Tracing            tracing.Provider
+	Metrics            metrics.Provider
+	Audit              audit.Sink
+	ReadOnly           bool
+	ShutdownTimeout    time.Duration
+	ShutdownHooks      []ShutdownHook
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
+	Dialer             comm.ContextDialer
+	Recorder           *replay.Recorder
+	Clock              clock.Clock
+	Entropy            io.Reader
+	CertRotation       time.Duration
+	CertRenewal        *mspImpl.CertRenewalOpts
+	ConnectionConfigs  map[fab.EndpointType]fab.ConnectionConfig
+	RetryBudget        *retry.BudgetOpts
+	EndorsementLimiter *limiter.Opts
+	CryptoSuiteConfig  core.CryptoSuiteConfig
+	endpointConfig     fab.EndpointConfig
+	IdentityConfig     msp.IdentityConfig
+	ConfigBackend      core.ConfigBackend
+	customCore         bool
+	customMSP          bool
+	customService      bool
 }
 
 // Option configures the SDK.
 type Option func(opts *options) error
 
+// ShutdownHook is invoked when the SDK is shut down, after in-flight requests have been
+// drained and before connections are closed (e.g. to flush event checkpoints)
+type ShutdownHook func(ctx reqContext.Context) error
+
 type closeable interface {
 	Close()
 }
 
+type drainable interface {
+	Drain(ctx reqContext.Context) error
+}
+
 // New initializes the SDK based on the set of options provided.
 // ConfigOptions provides the application configuration.
 func New(configProvider core.ConfigProvider, opts ...Option) (*FabricSDK, error) {
@@ -142,6 +192,7 @@ func WithConfigIdentity(identityConfig msp.IdentityConfig) Option {
 func WithCorePkg(core sdkApi.CoreProviderFactory) Option {
 	return func(opts *options) error {
 		opts.Core = core
+		opts.customCore = true
 		return nil
 	}
 }
@@ -150,6 +201,7 @@ func WithCorePkg(core sdkApi.CoreProviderFactory) Option {
 func WithMSPPkg(msp sdkApi.MSPProviderFactory) Option {
 	return func(opts *options) error {
 		opts.MSP = msp
+		opts.customMSP = true
 		return nil
 	}
 }
@@ -158,6 +210,7 @@ func WithMSPPkg(msp sdkApi.MSPProviderFactory) Option {
 func WithServicePkg(service sdkApi.ServiceProviderFactory) Option {
 	return func(opts *options) error {
 		opts.Service = service
+		opts.customService = true
 		return nil
 	}
 }
@@ -170,6 +223,223 @@ func WithLoggerPkg(logger api.LoggerProvider) Option {
 	}
 }
 
+// WithTracingProvider sets the provider of the tracers used to report the spans of the
+// transaction lifecycle (endorse, order, commit) and of CA calls. Tracing is disabled by default.
+func WithTracingProvider(provider tracing.Provider) Option {
+	return func(opts *options) error {
+		opts.Tracing = provider
+		return nil
+	}
+}
+
+// WithMetricsProvider sets the provider of the metrics reported by the SDK (see the
+// metrics/prometheus package for a Prometheus provider). Metrics are disabled by default.
+func WithMetricsProvider(provider metrics.Provider) Option {
+	return func(opts *options) error {
+		opts.Metrics = provider
+		return nil
+	}
+}
+
+// WithRetryBudget sets the budget that limits the retries of all of the clients (see retry.Budget),
+// so that cascading retries can't overwhelm a degraded network. It takes precedence over the budget
+// of the configuration (client.global.retryBudget). Retries aren't budgeted by default.
+func WithRetryBudget(budget retry.BudgetOpts) Option {
+	return func(opts *options) error {
+		if budget.Ratio < 0 || budget.MinPerSecond < 0 {
+			return errors.New("retry budget ratio and minimum per second must not be negative")
+		}
+		opts.RetryBudget = &budget
+		return nil
+	}
+}
+
+// WithEndorsementLimiter enables the adaptive limits of the number of concurrent endorsements sent to
+// each peer (see limiter.Adaptive), so that the throughput is maximized without a static concurrency limit.
+// It takes precedence over the limiter of the configuration (client.global.endorsementLimiter).
+// Endorsements aren't limited by default.
+func WithEndorsementLimiter(limits limiter.Opts) Option {
+	return func(opts *options) error {
+		if limits.MaxLimit > 0 && limits.MinLimit > limits.MaxLimit {
+			return errors.New("minimum endorsement limit must not be greater than the maximum")
+		}
+		opts.EndorsementLimiter = &limits
+		return nil
+	}
+}
+
+// WithAuditSink sets the sink that receives an audit event for each client operation (channel
+// queries and executions, CA enrollments, registrations and revocations). Auditing is disabled by default.
+func WithAuditSink(sink audit.Sink) Option {
+	return func(opts *options) error {
+		opts.Audit = sink
+		return nil
+	}
+}
+
+// WithReadOnly enables read-only mode: all write paths of the clients created from the SDK (channel
+// executions, resource management mutations, orderer broadcasts and CA enrollments, registrations
+// and revocations) fail with readonly.ErrReadOnly. This allows a standby environment to run the same
+// binaries safely. Other SDK instances of the process aren't affected.
+func WithReadOnly() Option {
+	return func(opts *options) error {
+		opts.ReadOnly = true
+		return nil
+	}
+}
+
+// WithShutdownTimeout enables graceful shutdown: Close waits up to the given timeout for
+// in-flight requests (endorsements and broadcasts) to complete before closing connections.
+// By default connections are closed immediately.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(opts *options) error {
+		opts.ShutdownTimeout = timeout
+		return nil
+	}
+}
+
+// WithShutdownHook adds a hook that is invoked when the SDK is closed, after in-flight requests
+// have been drained and before connections are closed. Hooks are typically used to flush event
+// checkpoints so that event listening resumes at the right block after a restart.
+func WithShutdownHook(hook ShutdownHook) Option {
+	return func(opts *options) error {
+		if hook == nil {
+			return errors.New("shutdown hook is nil")
+		}
+		opts.ShutdownHooks = append(opts.ShutdownHooks, hook)
+		return nil
+	}
+}
+
+// WithUnaryInterceptor registers a GRPC interceptor for the unary calls (e.g. endorsements and
+// broadcasts) made on every peer and orderer connection of the SDK. Interceptors are invoked in
+// the order in which they're registered and may be used, for example, to add authentication
+// headers, to trace calls or to retry calls.
+func WithUnaryInterceptor(interceptor grpc.UnaryClientInterceptor) Option {
+	return func(opts *options) error {
+		if interceptor == nil {
+			return errors.New("unary interceptor is nil")
+		}
+		opts.UnaryInterceptors = append(opts.UnaryInterceptors, interceptor)
+		return nil
+	}
+}
+
+// WithStreamInterceptor registers a GRPC interceptor for the streaming calls (e.g. event delivery)
+// made on every peer and orderer connection of the SDK. Interceptors are invoked in the order in
+// which they're registered.
+func WithStreamInterceptor(interceptor grpc.StreamClientInterceptor) Option {
+	return func(opts *options) error {
+		if interceptor == nil {
+			return errors.New("stream interceptor is nil")
+		}
+		opts.StreamInterceptors = append(opts.StreamInterceptors, interceptor)
+		return nil
+	}
+}
+
+// WithFaultInjector installs the interceptors of the given fault injector on every peer and orderer
+// connection of the SDK, so that latency, dropped connections and error codes may be injected into the
+// calls in order to test how the application handles retries and failover. The faults are injected
+// after the interceptors that are registered before this option.
+func WithFaultInjector(injector *comm.FaultInjector) Option {
+	return func(opts *options) error {
+		if injector == nil {
+			return errors.New("fault injector is nil")
+		}
+		opts.UnaryInterceptors = append(opts.UnaryInterceptors, injector.UnaryInterceptor())
+		opts.StreamInterceptors = append(opts.StreamInterceptors, injector.StreamInterceptor())
+		return nil
+	}
+}
+
+// WithRecorder installs the given recorder, which records the exchanges of the SDK with the peers, orderers
+// and CAs to a fixture file, or replays them in tests (see package replay). In replay mode the recorder's
+// dialer is used, unless a dialer is set with WithDialer. The recorder must be closed once the SDK is closed.
+func WithRecorder(recorder *replay.Recorder) Option {
+	return func(opts *options) error {
+		if recorder == nil {
+			return errors.New("recorder is nil")
+		}
+		opts.UnaryInterceptors = append(opts.UnaryInterceptors, recorder.UnaryInterceptor())
+		opts.StreamInterceptors = append(opts.StreamInterceptors, recorder.StreamInterceptor())
+		opts.Recorder = recorder
+		return nil
+	}
+}
+
+// WithClock sets the clock from which the SDK takes the current time and on which its timeouts,
+// backoffs and expiries elapse (the system clock by default). Together with WithEntropySource it
+// allows time-dependent behavior to be tested deterministically, e.g. with a clock.Fake.
+// The clock only applies to this SDK instance (see context.Providers.Clock): it's used by the
+// components that are created from the SDK's contexts (e.g. transaction headers, event clients,
+// orderer selection, ledger clients) and by the certificate renewal service. Other SDK instances
+// of the process are unaffected.
+func WithClock(c clock.Clock) Option {
+	return func(opts *options) error {
+		if c == nil {
+			return errors.New("clock is nil")
+		}
+		opts.Clock = c
+		return nil
+	}
+}
+
+// WithEntropySource sets the source of the random bytes from which the SDK generates transaction
+// nonces and seeds its pseudo-random choices (backoff jitter, load balancing). A reader returning
+// fixed bytes makes the nonces and choices reproducible. It must never be used in production.
+// The source only applies to the components that are created from the contexts of this SDK
+// instance (see context.Providers.RandomSource). Other SDK instances of the process are unaffected.
+func WithEntropySource(r io.Reader) Option {
+	return func(opts *options) error {
+		if r == nil {
+			return errors.New("entropy source is nil")
+		}
+		opts.Entropy = r
+		return nil
+	}
+}
+
+// WithDialer sets the dialer that creates the network connections to the peers and orderers
+// (e.g. to connect through a SOCKS5 proxy, over unix domain sockets or to in-memory listeners
+// in tests). It takes precedence over the dialer selected in the configuration (client.global.dialer).
+func WithDialer(dialer comm.ContextDialer) Option {
+	return func(opts *options) error {
+		if dialer == nil {
+			return errors.New("dialer is nil")
+		}
+		opts.Dialer = dialer
+		return nil
+	}
+}
+
+// WithConnectionConfig overrides the GRPC keepalive and reconnect backoff parameters of the connections
+// to the given type of endpoint (peers, orderers or event services), taking precedence over the
+// parameters of the configuration (client.global.connection). The parameters set in the GRPC options
+// of a peer or orderer still apply to that endpoint.
+func WithConnectionConfig(endpointType fab.EndpointType, config fab.ConnectionConfig) Option {
+	return func(opts *options) error {
+		if opts.ConnectionConfigs == nil {
+			opts.ConnectionConfigs = make(map[fab.EndpointType]fab.ConnectionConfig)
+		}
+		opts.ConnectionConfigs[endpointType] = config
+		return nil
+	}
+}
+
+// WithTLSCertRotation makes the SDK check the client's TLS certificate for mutual TLS at the given
+// interval. When the certificate files have changed (e.g. short-lived certificates have been renewed)
+// new connections are established with the new certificate. See also UpdateTLSClientCert.
+func WithTLSCertRotation(interval time.Duration) Option {
+	return func(opts *options) error {
+		if interval <= 0 {
+			return errors.New("TLS certificate rotation interval must be greater than zero")
+		}
+		opts.CertRotation = interval
+		return nil
+	}
+}
+
 // providerInit interface allows for initializing providers
 // TODO: minimize interface
 type providerInit interface {
@@ -177,6 +447,9 @@ type providerInit interface {
 }
 
 func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option) error { //nolint
+	sdk.connHooks.sdk = sdk
+	sdk.certRotation.sdk = sdk
+
 	for _, option := range opts {
 		err := option(&sdk.opts)
 		if err != nil {
@@ -190,12 +463,34 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 	}
 	logging.Initialize(sdk.opts.Logger)
 
+	if sdk.opts.Tracing != nil {
+		tracing.Initialize(sdk.opts.Tracing)
+	}
+	if sdk.opts.Metrics != nil {
+		metrics.Initialize(sdk.opts.Metrics)
+	}
+	if sdk.opts.Audit != nil {
+		audit.Initialize(sdk.opts.Audit)
+	}
 	//Initialize configs if not passed through options
 	cfg, err := sdk.loadConfigs(configProvider)
 	if err != nil {
 		return errors.WithMessage(err, "failed to initialize configuration")
 	}
 
+	randomSource, err := sdk.newRandomSource()
+	if err != nil {
+		return err
+	}
+	sdk.initRetryBudget(cfg.endpointConfig)
+	sdk.initEndorsementLimiter(cfg.endpointConfig)
+	sdk.initRecorder()
+
+	// Use the registered pkgs that are named in the configuration
+	if err := sdk.loadRegisteredPkgs(); err != nil {
+		return errors.WithMessage(err, "failed to load registered pkgs")
+	}
+
 	// Initialize crypto provider
 	cryptoSuite, err := sdk.opts.Core.CreateCryptoSuiteProvider(cfg.cryptoSuiteConfig)
 	if err != nil {
@@ -216,10 +511,14 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 	}
 
 	// Initialize state store
-	userStore, err := sdk.opts.MSP.CreateUserStore(cfg.identityConfig)
+	userStore, err := sdk.createUserStore(cfg.identityConfig, cryptoSuite)
 	if err != nil {
 		return errors.WithMessage(err, "failed to create state store")
 	}
+	userStore, err = sdk.initCertRenewal(userStore)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create certificate renewal service")
+	}
 
 	// Initialize Signing Manager
 	signingManager, err := sdk.opts.Core.CreateSigningManager(cryptoSuite)
@@ -234,7 +533,7 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 	}
 
 	// Initialize Fabric provider
-	infraProvider, err := sdk.opts.Core.CreateInfraProvider(cfg.endpointConfig)
+	infraProvider, err := sdk.createInfraProvider(cfg.endpointConfig)
 	if err != nil {
 		return errors.WithMessage(err, "failed to create infra provider")
 	}
@@ -274,7 +573,11 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		context.WithSelectionProvider(selectionProvider),
 		context.WithIdentityManagerProvider(identityManagerProvider),
 		context.WithInfraProvider(infraProvider),
-		context.WithChannelProvider(channelProvider))
+		context.WithChannelProvider(channelProvider),
+		context.WithReadOnly(sdk.opts.ReadOnly),
+		context.WithCATransport(sdk.caTransport()),
+		context.WithClock(sdk.opts.Clock),
+		context.WithRandomSource(randomSource))
 
 	//initialize
 	if pi, ok := infraProvider.(providerInit); ok {
@@ -305,20 +608,187 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		}
 	}
 
+	sdk.contextPool = newChannelContextPool(sdk.provider)
+
+	if sdk.opts.CertRotation > 0 {
+		sdk.certRotation.start(sdk.opts.CertRotation)
+	}
+	if sdk.certRenewal != nil {
+		if err := sdk.certRenewal.Start(); err != nil {
+			return errors.WithMessage(err, "failed to start certificate renewal service")
+		}
+	}
+
 	return nil
 }
 
-// Close frees up caches and connections being maintained by the SDK
+// retryBudgetConfigProvider is implemented by the endpoint configurations that configure a retry budget
+type retryBudgetConfigProvider interface {
+	RetryBudget() *retry.BudgetOpts
+}
+
+// initRetryBudget sets the retry budget shared by all of the clients, if one is set in the options or
+// in the configuration
+func (sdk *FabricSDK) initRetryBudget(endpointConfig fab.EndpointConfig) {
+	budgetOpts := sdk.opts.RetryBudget
+	if budgetOpts == nil {
+		if rc, ok := endpointConfig.(retryBudgetConfigProvider); ok {
+			budgetOpts = rc.RetryBudget()
+		}
+	}
+	if budgetOpts != nil {
+		logger.Debugf("Retry budget: %#v", *budgetOpts)
+		retry.SetBudget(retry.NewBudget(*budgetOpts))
+	}
+}
+
+// endorsementLimiterConfigProvider is implemented by the endpoint configurations that limit the concurrent endorsements
+type endorsementLimiterConfigProvider interface {
+	EndorsementLimiter() *limiter.Opts
+}
+
+// initEndorsementLimiter enables the adaptive limits of the concurrent endorsements, if they're set in
+// the options or in the configuration
+func (sdk *FabricSDK) initEndorsementLimiter(endpointConfig fab.EndpointConfig) {
+	limiterOpts := sdk.opts.EndorsementLimiter
+	if limiterOpts == nil {
+		if lc, ok := endpointConfig.(endorsementLimiterConfigProvider); ok {
+			limiterOpts = lc.EndorsementLimiter()
+		}
+	}
+	if limiterOpts != nil {
+		logger.Debugf("Endorsement limiter: %#v", *limiterOpts)
+		peer.SetEndorsementLimits(limiterOpts)
+	}
+}
+
+// newRandomSource returns the random source that reads the entropy source set in the options, if any
+func (sdk *FabricSDK) newRandomSource() (*random.Source, error) {
+	if sdk.opts.Entropy == nil {
+		return nil, nil
+	}
+	source, err := random.NewSource(sdk.opts.Entropy)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to initialize entropy source")
+	}
+	return source, nil
+}
+
+// initRecorder connects to the in-process server of the recorder in replay mode unless a dialer is set.
+// The exchanges with the CAs are recorded or replayed by the CA clients of the SDK (see caTransport).
+func (sdk *FabricSDK) initRecorder() {
+	recorder := sdk.opts.Recorder
+	if recorder == nil {
+		return
+	}
+	if dialer := recorder.Dialer(); dialer != nil && sdk.opts.Dialer == nil {
+		sdk.opts.Dialer = dialer
+	}
+}
+
+// caTransport returns the function that wraps the HTTP transport of the CA clients: the recorder's
+// transport if a recorder is set in the options, otherwise nil
+func (sdk *FabricSDK) caTransport() msp.CATransport {
+	if sdk.opts.Recorder == nil {
+		return nil
+	}
+	return sdk.opts.Recorder.Transport
+}
+
+// createInfraProvider creates the infra provider using the core provider factory. If interceptors
+// or a dialer have been registered then the factory must be able to apply them to the connections.
+func (sdk *FabricSDK) createInfraProvider(endpointConfig fab.EndpointConfig) (fab.InfraProvider, error) {
+	dialOpts := comm.InterceptorDialOpts(sdk.opts.UnaryInterceptors, sdk.opts.StreamInterceptors)
+	if sdk.opts.Dialer != nil {
+		dialOpts = append(dialOpts, comm.DialerDialOpt(sdk.opts.Dialer))
+	}
+	if len(dialOpts) == 0 {
+		return sdk.opts.Core.CreateInfraProvider(endpointConfig)
+	}
+
+	factory, ok := sdk.opts.Core.(sdkApi.DialOptsInfraProviderFactory)
+	if !ok {
+		return nil, errors.New("GRPC interceptors and dialers are not supported by the core provider factory")
+	}
+	return factory.CreateInfraProviderWithDialOpts(endpointConfig, dialOpts...)
+}
+
+// createUserStore creates the user store using the MSP provider factory, which is given the crypto
+// suite of the SDK if it's able to use it
+func (sdk *FabricSDK) createUserStore(identityConfig msp.IdentityConfig, cryptoSuite core.CryptoSuite) (msp.UserStore, error) {
+	if factory, ok := sdk.opts.MSP.(sdkApi.CryptoSuiteUserStoreFactory); ok {
+		return factory.CreateUserStoreWithCryptoSuite(identityConfig, cryptoSuite)
+	}
+	return sdk.opts.MSP.CreateUserStore(identityConfig)
+}
+
+// Close frees up caches and connections being maintained by the SDK. If a shutdown timeout
+// is configured (see WithShutdownTimeout) then in-flight requests are drained first (see Shutdown).
 func (sdk *FabricSDK) Close() {
-	if pvdr, ok := sdk.provider.DiscoveryProvider().(closeable); ok {
-		pvdr.Close()
+	if sdk.opts.ShutdownTimeout <= 0 {
+		if err := sdk.runShutdownHooks(reqContext.Background()); err != nil {
+			logger.Warnf("shutdown hook failed: %s", err)
+		}
+		sdk.close()
+		return
 	}
-	if pvdr, ok := sdk.provider.LocalDiscoveryProvider().(closeable); ok {
-		pvdr.Close()
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), sdk.opts.ShutdownTimeout)
+	defer cancel()
+
+	if err := sdk.Shutdown(ctx); err != nil {
+		logger.Warnf("graceful shutdown failed: %s", err)
 	}
-	if pvdr, ok := sdk.provider.SelectionProvider().(closeable); ok {
-		pvdr.Close()
+}
+
+// Shutdown gracefully shuts down the SDK. New requests are refused, in-flight requests are given
+// until the context is done to complete, the shutdown hooks are invoked and the caches and
+// connections of the SDK are then freed up. The SDK is closed even if an error is returned.
+//  Parameters:
+//  ctx is the context that bounds the time to wait for in-flight requests and shutdown hooks
+//
+//  Returns:
+//  an error if in-flight requests didn't complete in time or if a shutdown hook failed
+func (sdk *FabricSDK) Shutdown(ctx reqContext.Context) error {
+	var drainErr error
+	if pvdr, ok := sdk.provider.InfraProvider().(drainable); ok {
+		drainErr = pvdr.Drain(ctx)
+	}
+
+	hookErr := sdk.runShutdownHooks(ctx)
+	sdk.close()
+
+	if drainErr != nil {
+		return errors.WithMessage(drainErr, "failed to drain in-flight requests")
+	}
+	return hookErr
+}
+
+// runShutdownHooks invokes the shutdown hooks (once)
+func (sdk *FabricSDK) runShutdownHooks(ctx reqContext.Context) error {
+	var err error
+	sdk.hooksOnce.Do(func() {
+		for _, hook := range sdk.opts.ShutdownHooks {
+			if hookErr := hook(ctx); hookErr != nil && err == nil {
+				err = errors.WithMessage(hookErr, "shutdown hook failed")
+			}
+		}
+	})
+	return err
+}
+
+func (sdk *FabricSDK) close() {
+	if sdk.contextPool != nil {
+		sdk.contextPool.Close()
 	}
+	sdk.closeTenants()
+	sdk.connHooks.close()
+	sdk.certRotation.stop()
+	if sdk.certRenewal != nil {
+		sdk.certRenewal.Stop()
+	}
+	closeProviders(sdk.provider.DiscoveryProvider(), sdk.provider.LocalDiscoveryProvider(), sdk.provider.SelectionProvider())
+	sdk.retired.closeAll()
 	sdk.provider.InfraProvider().Close()
 }
 
@@ -332,17 +802,7 @@ func (sdk *FabricSDK) Config() (core.ConfigBackend, error) {
 
 //Context creates and returns context client which has all the necessary providers
 func (sdk *FabricSDK) Context(options ...ContextOption) contextApi.ClientProvider {
-
-	clientProvider := func() (contextApi.Client, error) {
-		identity, err := sdk.newIdentity(options...)
-		if err == ErrAnonymousIdentity {
-			identity = nil
-			err = nil
-		}
-		return &context.Client{Providers: sdk.provider, SigningIdentity: identity}, err
-	}
-
-	return clientProvider
+	return newClientProvider(sdk.provider, options...)
 }
 
 //ChannelContext creates and returns channel context
@@ -392,9 +852,25 @@ func (sdk *FabricSDK) loadConfigs(configProvider core.ConfigProvider) (*configs,
 		sdk.opts.ConfigBackend = configBackend
 	}
 
+	if len(sdk.opts.ConnectionConfigs) > 0 {
+		setter, ok := c.endpointConfig.(connectionConfigSetter)
+		if !ok {
+			return nil, errors.New("connection parameters can't be overridden in the endpoint config")
+		}
+		for endpointType, connConfig := range sdk.opts.ConnectionConfigs {
+			setter.SetConnectionConfig(endpointType, connConfig)
+		}
+	}
+
 	return c, nil
 }
 
+// connectionConfigSetter is implemented by the endpoint configs whose keepalive and
+// reconnect backoff parameters can be overridden
+type connectionConfigSetter interface {
+	SetConnectionConfig(endpointType fab.EndpointType, config fab.ConnectionConfig)
+}
+
 //loadEndpointConfig loads config from config backend when configs are not provided through opts or override missing interfaces from opts with config backend
 func (sdk *FabricSDK) loadEndpointConfig(configBackend core.ConfigBackend) (fab.EndpointConfig, error) {
 	endpointConfigOpt, ok := sdk.opts.endpointConfig.(*fabImpl.EndpointConfigOptions)
@@ -7,18 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package fabsdk
 
 import (
+	"bytes"
+	reqContext "context"
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/random"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/replay"
 	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	mockapisdk "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/test/mocksdkapi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 )
 
 const (
@@ -89,6 +97,144 @@ func TestWithCorePkg(t *testing.T) {
 	}
 }
 
+func TestWithInterceptors(t *testing.T) {
+	unary := func(ctx reqContext.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	stream := func(ctx reqContext.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+
+	c := configImpl.FromFile(sdkConfigFile)
+	sdk, err := New(c, WithUnaryInterceptor(unary), WithStreamInterceptor(stream))
+	if err != nil {
+		t.Fatalf("Error initializing SDK with interceptors: %s", err)
+	}
+	sdk.Close()
+
+	if _, err := New(c, WithUnaryInterceptor(nil)); err == nil {
+		t.Fatalf("Expected error for nil interceptor")
+	}
+
+	// The mock core factory doesn't support dial options
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	factory := mockapisdk.NewMockCoreProviderFactory(mockCtrl)
+
+	factory.EXPECT().CreateCryptoSuiteProvider(gomock.Any()).Return(nil, nil)
+	factory.EXPECT().CreateSigningManager(nil).Return(nil, nil)
+
+	if _, err := New(c, WithCorePkg(factory), WithUnaryInterceptor(unary)); err == nil {
+		t.Fatalf("Expected error for core factory that doesn't support interceptors")
+	}
+}
+
+func TestWithFaultInjector(t *testing.T) {
+	c := configImpl.FromFile(sdkConfigFile)
+	sdk, err := New(c, WithFaultInjector(comm.NewFaultInjector()))
+	if err != nil {
+		t.Fatalf("Error initializing SDK with fault injector: %s", err)
+	}
+	if len(sdk.opts.UnaryInterceptors) != 1 || len(sdk.opts.StreamInterceptors) != 1 {
+		t.Fatalf("Expected fault injector interceptors to be registered")
+	}
+	sdk.Close()
+
+	if _, err := New(c, WithFaultInjector(nil)); err == nil {
+		t.Fatalf("Expected error for nil fault injector")
+	}
+}
+
+func TestWithRecorder(t *testing.T) {
+	c := configImpl.FromFile(sdkConfigFile)
+	recorder, err := replay.New(replay.Record, "unused.json")
+	if err != nil {
+		t.Fatalf("Error creating recorder: %s", err)
+	}
+	sdk, err := New(c, WithRecorder(recorder))
+	if err != nil {
+		t.Fatalf("Error initializing SDK with recorder: %s", err)
+	}
+	if len(sdk.opts.UnaryInterceptors) != 1 || len(sdk.opts.StreamInterceptors) != 1 {
+		t.Fatalf("Expected recorder interceptors to be registered")
+	}
+	if sdk.opts.Dialer != nil {
+		t.Fatalf("Expected no dialer to be set in record mode")
+	}
+	if sdk.provider.CATransport() == nil {
+		t.Fatalf("Expected the recorder to wrap the transport of the CA clients")
+	}
+	sdk.Close()
+
+	other, err := New(c)
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer other.Close()
+	if other.provider.CATransport() != nil {
+		t.Fatalf("Expected the recorder to be limited to its SDK")
+	}
+
+	if _, err := New(c, WithRecorder(nil)); err == nil {
+		t.Fatalf("Expected error for nil recorder")
+	}
+}
+
+func TestWithClockAndEntropy(t *testing.T) {
+	c := configImpl.FromFile(sdkConfigFile)
+	fake := clock.NewFake(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	sdk, err := New(c, WithClock(fake), WithEntropySource(bytes.NewReader(bytes.Repeat([]byte{7}, 1024))))
+	if err != nil {
+		t.Fatalf("Error initializing SDK with clock and entropy source: %s", err)
+	}
+	defer sdk.Close()
+	if sdk.provider.Clock() != fake {
+		t.Fatalf("Expected fake clock to be set")
+	}
+	if sdk.provider.RandomSource() == random.Default() {
+		t.Fatalf("Expected random source to be read from the entropy source")
+	}
+
+	other, err := New(c)
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer other.Close()
+	if other.provider.Clock() != clock.Real || other.provider.RandomSource() != random.Default() {
+		t.Fatalf("Expected the clock and the random source to be limited to their SDK")
+	}
+
+	if _, err := New(c, WithClock(nil)); err == nil {
+		t.Fatalf("Expected error for nil clock")
+	}
+	if _, err := New(c, WithEntropySource(nil)); err == nil {
+		t.Fatalf("Expected error for nil entropy source")
+	}
+	if _, err := New(c, WithEntropySource(bytes.NewReader([]byte{7}))); err == nil {
+		t.Fatalf("Expected error for exhausted entropy source")
+	}
+}
+
+func TestWithReadOnly(t *testing.T) {
+	sdk, err := New(configImpl.FromFile(sdkConfigFile), WithReadOnly())
+	if err != nil {
+		t.Fatalf("Error initializing SDK in read-only mode: %s", err)
+	}
+	defer sdk.Close()
+	if !sdk.provider.ReadOnly() {
+		t.Fatalf("Expected read-only mode to be enabled")
+	}
+
+	other, err := New(configImpl.FromFile(sdkConfigFile))
+	if err != nil {
+		t.Fatalf("Error initializing SDK: %s", err)
+	}
+	defer other.Close()
+	if other.provider.ReadOnly() {
+		t.Fatalf("Expected read-only mode not to apply to other SDK instances")
+	}
+}
+
 func TestWithMSPPkg(t *testing.T) {
 	// Test New SDK with valid config file
 	c := configImpl.FromFile(sdkConfigFile)
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/fabpvdr"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/modlog"
+	"google.golang.org/grpc"
 )
 
 // ProviderFactory represents the default SDK provider factory.
@@ -44,6 +45,12 @@ func (f *ProviderFactory) CreateInfraProvider(config fab.EndpointConfig) (fab.In
 	return fabpvdr.New(config), nil
 }
 
+// CreateInfraProviderWithDialOpts returns a new default implementation of fabric primitives
+// whose connections use the given dial options
+func (f *ProviderFactory) CreateInfraProviderWithDialOpts(config fab.EndpointConfig, opts ...grpc.DialOption) (fab.InfraProvider, error) {
+	return fabpvdr.New(config, fabpvdr.WithDialOptions(opts...)), nil
+}
+
 // NewLoggerProvider returns a new default implementation of a logger backend
 // This function is separated from the factory to allow logger creation first.
 func NewLoggerProvider() api.LoggerProvider {
@@ -10,7 +10,6 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
-	kvs "github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/msppvdr"
 	mspimpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
 	"github.com/pkg/errors"
@@ -26,23 +25,25 @@ func NewProviderFactory() *ProviderFactory {
 	return &f
 }
 
-// CreateUserStore creates a UserStore using the SDK's default implementation
+// CreateUserStore creates the UserStore of the configured type (client.credentialStore.type).
+// The SDK's file based implementation is used by default. Custom types are registered with msp.RegisterUserStore.
+// The user store can't be encrypted with a key of the crypto suite (see CreateUserStoreWithCryptoSuite).
 func (f *ProviderFactory) CreateUserStore(config msp.IdentityConfig) (msp.UserStore, error) {
+	return f.CreateUserStoreWithCryptoSuite(config, nil)
+}
+
+// CreateUserStoreWithCryptoSuite creates the UserStore of the configured type, whose encryption key
+// (client.credentialStore.encryption.keySKI) is taken from the given crypto suite
+func (f *ProviderFactory) CreateUserStoreWithCryptoSuite(config msp.IdentityConfig, cryptoSuite core.CryptoSuite) (msp.UserStore, error) {
 
 	clientCofig, err := config.Client()
 	if err != nil {
 		return nil, errors.WithMessage(err, "Unable to retrieve client config")
 	}
-	stateStorePath := clientCofig.CredentialStore.Path
-
-	stateStore, err := kvs.New(&kvs.FileKeyValueStoreOptions{Path: stateStorePath})
-	if err != nil {
-		return nil, errors.WithMessage(err, "CreateNewFileKeyValueStore failed")
-	}
 
-	userStore, err := mspimpl.NewCertFileUserStore1(stateStore)
+	userStore, err := mspimpl.NewUserStore(clientCofig.CredentialStore, cryptoSuite)
 	if err != nil {
-		return nil, errors.Wrapf(err, "creating a user store failed")
+		return nil, errors.WithMessage(err, "creating a user store failed")
 	}
 
 	return userStore, nil
@@ -0,0 +1,346 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	reqContext "context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// caTimeout bounds the CA info request of a CA health check
+	caTimeout  = 10 * time.Second
+	caInfoPath = "/api/v1/cainfo"
+)
+
+// HealthStatus is the status of a component in a health report
+type HealthStatus string
+
+const (
+	// HealthStatusUp indicates that the component is reachable
+	HealthStatusUp HealthStatus = "UP"
+	// HealthStatusDown indicates that the component is unreachable
+	HealthStatusDown HealthStatus = "DOWN"
+)
+
+// ComponentType is the type of a component in a health report
+type ComponentType string
+
+const (
+	// PeerComponent is a peer
+	PeerComponent ComponentType = "peer"
+	// OrdererComponent is an orderer
+	OrdererComponent ComponentType = "orderer"
+	// CAComponent is a certificate authority
+	CAComponent ComponentType = "ca"
+	// HSMComponent is the HSM used by the PKCS11 crypto suite
+	HSMComponent ComponentType = "hsm"
+)
+
+// ComponentHealth is the health of a single component
+type ComponentHealth struct {
+	Type     ComponentType `json:"type"`
+	Name     string        `json:"name"`
+	Status   HealthStatus  `json:"status"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// HealthReport is the result of a health check. The report can be marshalled to JSON, e.g.
+// to serve it from a Kubernetes readiness probe.
+type HealthReport struct {
+	Status     HealthStatus      `json:"status"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// Healthy returns true if all of the components are up
+func (r *HealthReport) Healthy() bool {
+	return r.Status == HealthStatusUp
+}
+
+// HealthCheck verifies the reachability of the peers, orderers and CAs in the configuration and,
+// if the PKCS11 crypto suite is used, that a session with the HSM can be obtained. The components
+// are checked concurrently; the context bounds the duration of the check.
+//  Parameters:
+//  ctx is the context of the check. Peers and orderers are also bounded by their connection timeouts.
+//
+//  Returns:
+//  the health report, whose status is UP if all of the components are up
+func (sdk *FabricSDK) HealthCheck(ctx reqContext.Context) *HealthReport {
+	var checks []healthCheck
+	checks = append(checks, sdk.peerChecks()...)
+	checks = append(checks, sdk.ordererChecks()...)
+	checks = append(checks, sdk.caChecks()...)
+	checks = append(checks, sdk.hsmChecks()...)
+
+	report := &HealthReport{
+		Status:     HealthStatusUp,
+		Timestamp:  time.Now(),
+		Components: make([]ComponentHealth, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check healthCheck) {
+			defer wg.Done()
+			report.Components[i] = check.run(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, component := range report.Components {
+		if component.Status != HealthStatusUp {
+			report.Status = HealthStatusDown
+			break
+		}
+	}
+
+	return report
+}
+
+type healthCheck struct {
+	componentType ComponentType
+	name          string
+	check         func(ctx reqContext.Context) error
+}
+
+func (c *healthCheck) run(ctx reqContext.Context) ComponentHealth {
+	start := time.Now()
+	err := c.check(ctx)
+
+	health := ComponentHealth{
+		Type:     c.componentType,
+		Name:     c.name,
+		Status:   HealthStatusUp,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		logger.Debugf("health check failed for %s [%s]: %s", c.componentType, c.name, err)
+		health.Status = HealthStatusDown
+		health.Error = err.Error()
+	}
+	return health
+}
+
+// failedCheck returns a check that reports the given configuration error
+func failedCheck(componentType ComponentType, name string, err error) healthCheck {
+	return healthCheck{componentType: componentType, name: name, check: func(reqContext.Context) error { return err }}
+}
+
+func (sdk *FabricSDK) peerChecks() []healthCheck {
+	config := sdk.provider.EndpointConfig()
+
+	peers, err := config.NetworkPeers()
+	if err != nil {
+		return []healthCheck{failedCheck(PeerComponent, "", errors.WithMessage(err, "failed to load peer configuration"))}
+	}
+
+	var checks []healthCheck
+	for _, p := range peers {
+		checks = append(checks, sdk.grpcCheck(PeerComponent, p.URL, p.TLSCACerts, p.GRPCOptions, config.Timeout(fab.EndorserConnection)))
+	}
+	return checks
+}
+
+func (sdk *FabricSDK) ordererChecks() []healthCheck {
+	config := sdk.provider.EndpointConfig()
+
+	orderers, err := config.OrderersConfig()
+	if err != nil {
+		return []healthCheck{failedCheck(OrdererComponent, "", errors.WithMessage(err, "failed to load orderer configuration"))}
+	}
+
+	var checks []healthCheck
+	for _, o := range orderers {
+		checks = append(checks, sdk.grpcCheck(OrdererComponent, o.URL, o.TLSCACerts, o.GRPCOptions, config.Timeout(fab.OrdererConnection)))
+	}
+	return checks
+}
+
+// grpcCheck returns a check that connects to the given endpoint using the comm manager of the SDK,
+// so that the outcome is also reported to the endpoint health monitor
+func (sdk *FabricSDK) grpcCheck(componentType ComponentType, url string, tlsCACert endpoint.TLSConfig, grpcOptions map[string]interface{}, timeout time.Duration) healthCheck {
+	return healthCheck{
+		componentType: componentType,
+		name:          url,
+		check: func(ctx reqContext.Context) error {
+			opts, err := sdk.dialOptions(url, tlsCACert, grpcOptions)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := reqContext.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			commManager := sdk.provider.InfraProvider().CommManager()
+			conn, err := commManager.DialContext(ctx, endpoint.ToAddress(url), opts...)
+			if err != nil {
+				return err
+			}
+			commManager.ReleaseConn(conn)
+			return nil
+		},
+	}
+}
+
+func (sdk *FabricSDK) dialOptions(url string, tlsCACert endpoint.TLSConfig, grpcOptions map[string]interface{}) ([]grpc.DialOption, error) {
+	allowInsecure, _ := grpcOptions["allow-insecure"].(bool)
+	if !endpoint.AttemptSecured(url, allowInsecure) {
+		return []grpc.DialOption{grpc.WithInsecure()}, nil
+	}
+
+	cert, err := tlsCACert.TLSCert()
+	if err != nil {
+		//Ignore empty cert errors (the cert pool of the config is used)
+		errStatus, ok := err.(*status.Status)
+		if !ok || errStatus.Code != status.EmptyCert.ToInt32() {
+			return nil, errors.WithMessage(err, "failed to load TLS CA certificate")
+		}
+	}
+
+	serverHostOverride, _ := grpcOptions["ssl-target-name-override"].(string)
+	tlsConfig, err := comm.TLSConfig(cert, endpoint.TLSServerName(url, serverHostOverride), sdk.provider.EndpointConfig())
+	if err != nil {
+		return nil, err
+	}
+	return []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}, nil
+}
+
+func (sdk *FabricSDK) caChecks() []healthCheck {
+	networkConfig, err := sdk.provider.EndpointConfig().NetworkConfig()
+	if err != nil {
+		return []healthCheck{failedCheck(CAComponent, "", errors.WithMessage(err, "failed to load network configuration"))}
+	}
+
+	var checks []healthCheck
+	checked := make(map[string]bool)
+	for org, orgConfig := range networkConfig.Organizations {
+		if len(orgConfig.CertificateAuthorities) == 0 {
+			continue
+		}
+		org := org
+
+		caConfig, err := sdk.provider.IdentityConfig().CAConfig(org)
+		if err != nil {
+			checks = append(checks, failedCheck(CAComponent, org, errors.WithMessage(err, "failed to load CA configuration")))
+			continue
+		}
+		if checked[caConfig.URL] {
+			continue
+		}
+		checked[caConfig.URL] = true
+
+		checks = append(checks, healthCheck{
+			componentType: CAComponent,
+			name:          caConfig.URL,
+			check: func(ctx reqContext.Context) error {
+				return sdk.checkCA(ctx, org, caConfig.URL, caConfig.CAName)
+			},
+		})
+	}
+	return checks
+}
+
+// checkCA requests the CA info from the given CA
+func (sdk *FabricSDK) checkCA(ctx reqContext.Context, org, url, caName string) error {
+	client := &http.Client{Timeout: caTimeout}
+	if strings.HasPrefix(url, "https://") {
+		tlsConfig, err := sdk.caTLSConfig(org)
+		if err != nil {
+			return err
+		}
+		client.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(url, "/")+caInfoPath, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create CA info request")
+	}
+	if caName != "" {
+		q := req.URL.Query()
+		q.Set("ca", caName)
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.Wrap(err, "CA info request failed")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("CA info request failed with status [%s]", resp.Status)
+	}
+	return nil
+}
+
+func (sdk *FabricSDK) caTLSConfig(org string) (*tls.Config, error) {
+	identityConfig := sdk.provider.IdentityConfig()
+
+	serverCerts, err := identityConfig.CAServerCerts(org)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load CA server certificates")
+	}
+	pool := x509.NewCertPool()
+	for _, cert := range serverCerts {
+		pool.AppendCertsFromPEM(cert)
+	}
+	tlsConfig := &tls.Config{RootCAs: pool}
+
+	// The client certificate is optional unless the CA requires mutual TLS
+	clientCert, certErr := identityConfig.CAClientCert(org)
+	clientKey, keyErr := identityConfig.CAClientKey(org)
+	if certErr == nil && keyErr == nil && len(clientCert) > 0 && len(clientKey) > 0 {
+		keyPair, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load CA client key pair")
+		}
+		tlsConfig.Certificates = []tls.Certificate{keyPair}
+	}
+	return tlsConfig, nil
+}
+
+// sessionChecker is implemented by crypto suites that are backed by an HSM
+type sessionChecker interface {
+	CheckSession() error
+}
+
+func (sdk *FabricSDK) hsmChecks() []healthCheck {
+	config := sdk.provider.CryptoSuiteConfig()
+	if !strings.EqualFold(config.SecurityProvider(), "pkcs11") {
+		return nil
+	}
+
+	sc, ok := sdk.provider.CryptoSuite().(sessionChecker)
+	if !ok {
+		logger.Debug("crypto suite doesn't support HSM session checks")
+		return nil
+	}
+
+	return []healthCheck{{
+		componentType: HSMComponent,
+		name:          config.SecurityProviderLabel(),
+		check: func(reqContext.Context) error {
+			return sc.CheckSession()
+		},
+	}}
+}
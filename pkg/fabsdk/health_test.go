@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	reqContext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheckUnreachable(t *testing.T) {
+	sdk, err := New(configImpl.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	report := sdk.HealthCheck(ctx)
+	assert.False(t, report.Healthy(), "expecting report to be unhealthy since the network isn't running")
+	assert.Equal(t, HealthStatusDown, report.Status)
+
+	types := make(map[ComponentType]bool)
+	for _, component := range report.Components {
+		types[component.Type] = true
+		assert.Equal(t, HealthStatusDown, component.Status)
+		assert.NotEmpty(t, component.Error)
+	}
+	assert.True(t, types[PeerComponent], "expecting peers to be checked")
+	assert.True(t, types[OrdererComponent], "expecting orderers to be checked")
+	assert.True(t, types[CAComponent], "expecting CAs to be checked")
+	assert.False(t, types[HSMComponent], "HSM isn't expected to be checked for the SW crypto suite")
+
+	_, err = json.Marshal(report)
+	assert.NoError(t, err)
+}
+
+func TestHealthCheckCA(t *testing.T) {
+	sdk, err := New(configImpl.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != caInfoPath || r.URL.Query().Get("ca") != "ca.org1.example.com" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	err = sdk.checkCA(reqContext.Background(), "org1", server.URL, "ca.org1.example.com")
+	assert.NoError(t, err)
+
+	err = sdk.checkCA(reqContext.Background(), "org1", server.URL, "unknown")
+	assert.Error(t, err, "expecting error for unknown CA")
+}
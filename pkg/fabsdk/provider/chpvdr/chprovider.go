@@ -10,6 +10,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
 )
 
 // ChannelProvider keeps context across ChannelService instances.
@@ -33,6 +34,7 @@ func (cp *ChannelProvider) ChannelService(ctx fab.ClientContext, channelID strin
 		infraProvider: cp.infraProvider,
 		context:       ctx,
 		channelID:     channelID,
+		selector:      txn.NewOrdererSelector(ctx.Clock().Now),
 	}
 
 	return &cs, nil
@@ -45,6 +47,7 @@ type ChannelService struct {
 	infraProvider fab.InfraProvider
 	context       context.Client
 	channelID     string
+	selector      *txn.OrdererSelector
 }
 
 // Config returns the Config for the named channel
@@ -52,6 +55,12 @@ func (cs *ChannelService) Config() (fab.ChannelConfig, error) {
 	return cs.infraProvider.CreateChannelConfig(cs.channelID)
 }
 
+// OrdererSelector returns the selector that determines the order in which the orderers are tried
+// by the requests made on this channel context
+func (cs *ChannelService) OrdererSelector() *txn.OrdererSelector {
+	return cs.selector
+}
+
 // EventService returns the EventService.
 func (cs *ChannelService) EventService(opts ...options.Opt) (fab.EventService, error) {
 	return cs.infraProvider.CreateEventService(cs.context, cs.channelID, opts...)
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabpvdr
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+)
+
+// configBlockTracker keeps track of the latest config block of each channel so that a
+// config block that is received by several event clients refreshes the channel only once
+type configBlockTracker struct {
+	mutex  sync.Mutex
+	blocks map[string]uint64
+}
+
+func newConfigBlockTracker() *configBlockTracker {
+	return &configBlockTracker{blocks: make(map[string]uint64)}
+}
+
+// update returns true if the given config block is newer than the latest config block of the channel
+func (t *configBlockTracker) update(channelID string, blockNum uint64) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if last, ok := t.blocks[channelID]; ok && blockNum <= last {
+		return false
+	}
+	t.blocks[channelID] = blockNum
+	return true
+}
+
+// refreshChannelConfig refreshes the cached configuration (orderers, MSPs, capabilities) and the
+// membership of the given channel after an event client received a config block, so that the
+// changes take effect without waiting for the periodic refresh
+func (f *InfraProvider) refreshChannelConfig(channelID string, blockNum uint64) {
+	if !f.configBlocks.update(channelID, blockNum) {
+		logger.Debugf("Channel config of [%s] is already refreshed for config block %d", channelID, blockNum)
+		return
+	}
+
+	logger.Debugf("Config block %d received for channel [%s] - refreshing channel config...", blockNum, channelID)
+
+	// The membership is refreshed after the channel config since it's loaded from the channel config
+	var refs []*lazyref.Reference
+	if c, ok := f.chCfgCache.(*lazycache.Cache); ok {
+		c.Range(func(key string, value interface{}) bool {
+			if ref, ok := value.(*chconfig.Ref); ok && ref.ChannelID() == channelID {
+				refs = append(refs, ref.Reference)
+			}
+			return true
+		})
+	}
+	if c, ok := f.membershipCache.(*lazycache.Cache); ok {
+		if key, err := membership.NewCacheKey(membership.Context{}, nil, channelID); err == nil {
+			c.Range(func(k string, value interface{}) bool {
+				if ref, ok := value.(*membership.Ref); ok && k == key.String() {
+					refs = append(refs, ref.Reference)
+				}
+				return true
+			})
+		}
+	}
+
+	for _, ref := range refs {
+		if err := ref.Refresh(); err != nil {
+			logger.Warnf("Error refreshing channel config of [%s] after config block %d: %s", channelID, blockNum, err)
+		}
+	}
+}
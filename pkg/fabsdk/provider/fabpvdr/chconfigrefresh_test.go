@@ -0,0 +1,23 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabpvdr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigBlockTracker(t *testing.T) {
+	tracker := newConfigBlockTracker()
+
+	assert.True(t, tracker.update("ch1", 5))
+	assert.False(t, tracker.update("ch1", 5), "a config block received by a second event client must be ignored")
+	assert.False(t, tracker.update("ch1", 4))
+	assert.True(t, tracker.update("ch2", 5))
+	assert.True(t, tracker.update("ch1", 9))
+}
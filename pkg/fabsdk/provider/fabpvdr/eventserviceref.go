@@ -7,10 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package fabpvdr
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
 	"github.com/pkg/errors"
 )
@@ -30,7 +33,25 @@ type EventClientRef struct {
 	ref         *lazyref.Reference
 	provider    eventClientProvider
 	eventClient fab.EventClient
+	lock        sync.RWMutex
 	closed      int32
+	channelID   string
+}
+
+// EventClientInfo contains diagnostic information about an event client
+type EventClientInfo struct {
+	ChannelID     string                         `json:"channelId"`
+	Connected     bool                           `json:"connected"`
+	State         string                         `json:"state,omitempty"`
+	Registrations *esdispatcher.RegistrationInfo `json:"registrations,omitempty"`
+}
+
+type registrationInfoProvider interface {
+	RegistrationInfo() (*esdispatcher.RegistrationInfo, error)
+}
+
+type connectionStateProvider interface {
+	ConnectionState() client.ConnectionState
 }
 
 // NewEventClientRef returns a new EventClientRef
@@ -68,6 +89,31 @@ func (ref *EventClientRef) Closed() bool {
 	return atomic.LoadInt32(&ref.closed) == 1
 }
 
+// Info returns diagnostic information about the event client. The event client
+// is not connected if it isn't already.
+func (ref *EventClientRef) Info() *EventClientInfo {
+	info := &EventClientInfo{ChannelID: ref.channelID}
+
+	eventClient := ref.getEventClient()
+	if eventClient == nil || ref.Closed() {
+		return info
+	}
+
+	info.Connected = true
+	if sp, ok := eventClient.(connectionStateProvider); ok {
+		info.State = sp.ConnectionState().String()
+	}
+	if rp, ok := eventClient.(registrationInfoProvider); ok {
+		regInfo, err := rp.RegistrationInfo()
+		if err != nil {
+			logger.Debugf("Unable to get registration info of event client: %s", err)
+		} else {
+			info.Registrations = regInfo
+		}
+	}
+	return info
+}
+
 // RegisterBlockEvent registers for block events.
 func (ref *EventClientRef) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
 	service, err := ref.get()
@@ -127,9 +173,9 @@ func (ref *EventClientRef) get() (fab.EventService, error) {
 
 func (ref *EventClientRef) initializer() lazyref.Initializer {
 	return func() (interface{}, error) {
-		if ref.eventClient != nil {
+		if eventClient := ref.getEventClient(); eventClient != nil {
 			// Already connected
-			return ref.eventClient, nil
+			return eventClient, nil
 		}
 
 		logger.Debugf("Creating event client...")
@@ -141,7 +187,7 @@ func (ref *EventClientRef) initializer() lazyref.Initializer {
 		if err := eventClient.Connect(); err != nil {
 			return nil, err
 		}
-		ref.eventClient = eventClient
+		ref.setEventClient(eventClient)
 		logger.Debugf("...event client successfully connected.")
 		return eventClient, nil
 	}
@@ -150,17 +196,17 @@ func (ref *EventClientRef) initializer() lazyref.Initializer {
 func (ref *EventClientRef) finalizer() lazyref.Finalizer {
 	return func(interface{}) {
 		logger.Debug("Finalizer called")
-		if ref.eventClient != nil {
+		if eventClient := ref.getEventClient(); eventClient != nil {
 			if ref.Closed() {
 				logger.Debug("Forcing close the event client")
-				ref.eventClient.Close()
+				eventClient.Close()
 			} else {
 				logger.Debugf("Closing the event client if no outstanding connections...")
 
 				// Only close the client if there are not outstanding registrations
-				if ref.eventClient.CloseIfIdle() {
+				if eventClient.CloseIfIdle() {
 					logger.Debugf("... closed event client.")
-					ref.eventClient = nil
+					ref.setEventClient(nil)
 				} else {
 					logger.Debugf("... event client was not closed since there are outstanding registrations.")
 				}
@@ -168,3 +214,15 @@ func (ref *EventClientRef) finalizer() lazyref.Finalizer {
 		}
 	}
 }
+
+func (ref *EventClientRef) getEventClient() fab.EventClient {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+	return ref.eventClient
+}
+
+func (ref *EventClientRef) setEventClient(eventClient fab.EventClient) {
+	ref.lock.Lock()
+	defer ref.lock.Unlock()
+	ref.eventClient = eventClient
+}
@@ -55,6 +55,24 @@ func (m *chCfgCache) Get(k lazycache.Key) (interface{}, error) {
 	return cfg, nil
 }
 
+// Len returns the number of channel config references in the mock cache
+func (m *chCfgCache) Len() int {
+	n := 0
+	m.cfgMap.Range(func(key, value interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Clear removes all channel config references from the mock cache
+func (m *chCfgCache) Clear() {
+	m.cfgMap.Range(func(key, value interface{}) bool {
+		m.cfgMap.Delete(key)
+		return true
+	})
+}
+
 // Close not implemented
 func (m *chCfgCache) Close() {
 }
@@ -8,21 +8,27 @@ package fabpvdr
 
 import (
 	reqContext "context"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	channelImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel/membership"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	esclient "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/eventhubclient"
+	esdispatcher "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/dispatcher"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
 	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"google.golang.org/grpc"
 )
 
 var logger = logging.NewLogger("fabsdk")
@@ -36,6 +42,8 @@ type cacheKey interface {
 
 type cache interface {
 	Get(lazycache.Key) (interface{}, error)
+	Len() int
+	Clear()
 	Close()
 }
 
@@ -43,38 +51,146 @@ type cache interface {
 type InfraProvider struct {
 	providerContext   context.Providers
 	commManager       *comm.CachingConnector
+	healthMonitor     *comm.HealthMonitor
+	requests          *requestTracker
 	eventServiceCache cache
 	chCfgCache        cache
 	membershipCache   cache
+	configBlocks      *configBlockTracker
+}
+
+// Option configures the InfraProvider
+type Option func(opts *providerOptions)
+
+// connectionPoolConfigProvider is implemented by the endpoint configurations that
+// provide the limits of the connection pool
+type connectionPoolConfigProvider interface {
+	ConnectionPoolConfig() fab.ConnectionPoolConfig
+}
+
+// dialerConfigProvider is implemented by the endpoint configurations that
+// select the dialer of the connections
+type dialerConfigProvider interface {
+	DialerName() string
+}
+
+// breakerConfigProvider is implemented by the endpoint configurations that
+// set the failure threshold of the circuit breakers of the endpoints
+type breakerConfigProvider interface {
+	CircuitBreakerFailureThreshold() int
+}
+
+// proxyConfigProvider is implemented by the endpoint configurations that
+// set a global HTTP CONNECT proxy
+type proxyConfigProvider interface {
+	HTTPProxy() string
+}
+
+// srvConfigProvider is implemented by the endpoint configurations that
+// set the refresh interval of the DNS SRV records of the endpoints
+type srvConfigProvider interface {
+	SRVRefreshInterval() time.Duration
+}
+
+// eventReconnectConfigProvider is implemented by the endpoint configurations that
+// set the reconnection parameters of the event service streams
+type eventReconnectConfigProvider interface {
+	EventReconnectConfig() fab.EventReconnectConfig
+}
+
+type providerOptions struct {
+	dialOpts []grpc.DialOption
+}
+
+// WithDialOptions sets additional GRPC dial options that are applied to all of the
+// connections (peers, orderers and event services) created by the provider
+func WithDialOptions(dialOpts ...grpc.DialOption) Option {
+	return func(opts *providerOptions) {
+		opts.dialOpts = append(opts.dialOpts, dialOpts...)
+	}
 }
 
 // New creates a InfraProvider enabling access to core Fabric objects and functionality.
-func New(config fab.EndpointConfig) *InfraProvider {
+func New(config fab.EndpointConfig, opts ...Option) *InfraProvider {
+	pOpts := providerOptions{}
+	for _, opt := range opts {
+		opt(&pOpts)
+	}
+
 	idleTime := config.Timeout(fab.ConnectionIdle)
 	sweepTime := config.Timeout(fab.CacheSweepInterval)
 	eventIdleTime := config.Timeout(fab.EventServiceIdle)
 	chConfigRefresh := config.Timeout(fab.ChannelConfigRefresh)
 	membershipRefresh := config.Timeout(fab.ChannelMembershipRefresh)
 
+	f := &InfraProvider{}
 	eventServiceCache := lazycache.New(
 		"Event_Service_Cache",
 		func(key lazycache.Key) (interface{}, error) {
 			ck := key.(cacheKey)
-			return NewEventClientRef(
+			ref := NewEventClientRef(
 				eventIdleTime,
 				func() (fab.EventClient, error) {
-					return getEventClient(ck.Context(), ck.ChannelConfig(), ck.Opts()...)
+					// Config blocks received by the event client refresh the cached channel config
+					channelID := ck.ChannelConfig().ID()
+					opts := append([]options.Opt{esdispatcher.WithConfigBlockHandler(func(blockNum uint64) {
+						f.refreshChannelConfig(channelID, blockNum)
+					})}, ck.Opts()...)
+					return getEventClient(ck.Context(), ck.ChannelConfig(), opts...)
 				},
-			), nil
+			)
+			ref.channelID = ck.ChannelConfig().ID()
+			return ref, nil
 		},
 	)
 
-	return &InfraProvider{
-		commManager:       comm.NewCachingConnector(sweepTime, idleTime),
-		eventServiceCache: eventServiceCache,
-		chCfgCache:        chconfig.NewRefCache(chConfigRefresh),
-		membershipCache:   membership.NewRefCache(membershipRefresh),
+	healthMonitorOpts := []comm.HealthMonitorOpt{
+		comm.WithProbeInterval(config.Timeout(fab.HealthCheckInterval)),
+		comm.WithProbeTimeout(config.Timeout(fab.HealthCheckTimeout)),
+		comm.WithBreakerReset(config.Timeout(fab.CircuitBreakerReset)),
 	}
+	if bc, ok := config.(breakerConfigProvider); ok && bc.CircuitBreakerFailureThreshold() > 0 {
+		healthMonitorOpts = append(healthMonitorOpts, comm.WithFailureThreshold(bc.CircuitBreakerFailureThreshold()))
+	}
+	healthMonitor := comm.NewHealthMonitor(healthMonitorOpts...)
+
+	initProxies(config)
+
+	var srvRefresh time.Duration
+	if sc, ok := config.(srvConfigProvider); ok {
+		srvRefresh = sc.SRVRefreshInterval()
+	}
+
+	connectorOpts := []comm.CachingConnectorOpt{
+		comm.WithHealthMonitor(healthMonitor),
+		comm.WithSRVResolver(comm.NewSRVResolver(srvRefresh)),
+	}
+	if dc, ok := config.(dialerConfigProvider); ok && dc.DialerName() != "" {
+		if dialer, ok := comm.Dialer(dc.DialerName()); ok {
+			connectorOpts = append(connectorOpts, comm.WithDialOptions(comm.DialerDialOpt(dialer)))
+		} else {
+			logger.Warnf("dialer [%s] is not registered - using the default dialer", dc.DialerName())
+		}
+	}
+	// The dial options of the provider are applied last so that they take precedence over the configuration
+	connectorOpts = append(connectorOpts, comm.WithDialOptions(pOpts.dialOpts...))
+	if pc, ok := config.(connectionPoolConfigProvider); ok {
+		poolConfig := pc.ConnectionPoolConfig()
+		connectorOpts = append(connectorOpts,
+			comm.WithMaxConnectionsPerEndpoint(poolConfig.MaxConnectionsPerEndpoint),
+			comm.WithMaxStreamsPerConnection(poolConfig.MaxStreamsPerConnection),
+		)
+	}
+
+	f.commManager = comm.NewCachingConnector(sweepTime, idleTime, connectorOpts...)
+	f.healthMonitor = healthMonitor
+	f.requests = newRequestTracker()
+	f.eventServiceCache = eventServiceCache
+	f.chCfgCache = chconfig.NewRefCache(chConfigRefresh)
+	f.membershipCache = membership.NewRefCache(membershipRefresh)
+	f.configBlocks = newConfigBlockTracker()
+
+	return f
 }
 
 // Initialize sets the provider context
@@ -83,6 +199,150 @@ func (f *InfraProvider) Initialize(providers context.Providers) error {
 	return nil
 }
 
+// Acquire registers a new in-flight request. False is returned if the provider is draining.
+func (f *InfraProvider) Acquire() bool {
+	return f.requests.Acquire()
+}
+
+// Release unregisters an in-flight request
+func (f *InfraProvider) Release() {
+	f.requests.Release()
+}
+
+// Drain refuses new requests and waits for the in-flight requests to complete. An error
+// is returned if the context is done before all of the requests have completed.
+func (f *InfraProvider) Drain(ctx reqContext.Context) error {
+	logger.Debug("Draining in-flight requests...")
+	return f.requests.Drain(ctx)
+}
+
+// Reconcile brings the provider in line with the current endpoint configuration after the
+// configuration of the SDK has been reloaded. The channel config and membership caches are
+// invalidated, and the idle connections to (and health checks of) endpoints that are no
+// longer configured are closed. Event services and connections that are in use are
+// unaffected so that existing clients keep working.
+func (f *InfraProvider) Reconcile() error {
+	config := f.providerContext.EndpointConfig()
+
+	peers, err := config.NetworkPeers()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load peer configuration")
+	}
+	orderers, err := config.OrderersConfig()
+	if err != nil {
+		return errors.WithMessage(err, "failed to load orderer configuration")
+	}
+
+	configured := make(map[string]bool)
+	for _, p := range peers {
+		configured[endpoint.ToAddress(p.URL)] = true
+	}
+	for _, o := range orderers {
+		configured[endpoint.ToAddress(o.URL)] = true
+	}
+	accept := func(target string) bool {
+		return configured[target]
+	}
+
+	logger.Debug("Clearing channel configuration and membership caches...")
+	f.chCfgCache.Clear()
+	f.membershipCache.Clear()
+
+	f.healthMonitor.Reconcile(accept)
+	f.commManager.Reconcile(accept)
+
+	return nil
+}
+
+// RefreshConnections makes new requests use new connections, e.g. after the client's TLS
+// credentials have been rotated. Connections that are in use (including the connections of
+// event services) are closed once they've been released.
+func (f *InfraProvider) RefreshConnections() {
+	logger.Debug("Refreshing connections...")
+	f.commManager.Refresh()
+}
+
+// initProxies sets the global HTTP CONNECT proxy and the proxies of the configured peers and
+// orderers (the http-proxy GRPC option)
+func initProxies(config fab.EndpointConfig) {
+	if pc, ok := config.(proxyConfigProvider); ok {
+		proxyURL, err := comm.ParseProxyURL(pc.HTTPProxy())
+		if err != nil {
+			logger.Warnf("ignoring global proxy: %s", err)
+		}
+		comm.SetGlobalProxy(proxyURL)
+	}
+
+	setProxy := func(grpcOptions map[string]interface{}, urls ...string) {
+		value, ok := grpcOptions["http-proxy"]
+		if !ok {
+			return
+		}
+		proxyURL, err := comm.ParseProxyURL(cast.ToString(value))
+		if err != nil {
+			logger.Warnf("ignoring proxy of [%v]: %s", urls, err)
+			return
+		}
+		for _, url := range urls {
+			for _, address := range endpoint.Addresses(url) {
+				comm.SetEndpointProxy(address, proxyURL)
+			}
+		}
+	}
+
+	if peers, err := config.NetworkPeers(); err == nil {
+		for _, p := range peers {
+			setProxy(p.GRPCOptions, p.URL, p.EventURL)
+		}
+	}
+	if orderers, err := config.OrderersConfig(); err == nil {
+		for _, o := range orderers {
+			setProxy(o.GRPCOptions, o.URL)
+		}
+	}
+}
+
+// Endpoints returns the connection statistics and the states of the connections of every
+// endpoint (peer, orderer or event service) that has been dialed, e.g. for operational dashboards
+func (f *InfraProvider) Endpoints() []comm.EndpointInfo {
+	return f.commManager.Endpoints()
+}
+
+// Diagnostics contains a snapshot of the internal state of the provider
+type Diagnostics struct {
+	InFlightRequests int                   `json:"inFlightRequests"`
+	Connections      []comm.ConnectionInfo `json:"connections"`
+	Endpoints        []comm.EndpointInfo   `json:"endpoints"`
+	EventClients     []*EventClientInfo    `json:"eventClients"`
+	CacheSizes       map[string]int        `json:"cacheSizes"`
+}
+
+// Diagnostics returns a snapshot of the open connections, the event clients (along
+// with their registrations) and the sizes of the caches maintained by the provider
+func (f *InfraProvider) Diagnostics() interface{} {
+	d := &Diagnostics{
+		InFlightRequests: f.requests.Count(),
+		Connections:      f.commManager.Connections(),
+		Endpoints:        f.commManager.Endpoints(),
+		CacheSizes: map[string]int{
+			"eventService":  f.eventServiceCache.Len(),
+			"channelConfig": f.chCfgCache.Len(),
+			"membership":    f.membershipCache.Len(),
+		},
+	}
+
+	if c, ok := f.eventServiceCache.(*lazycache.Cache); ok {
+		c.Range(func(key string, value interface{}) bool {
+			if ref, ok := value.(*EventClientRef); ok {
+				d.EventClients = append(d.EventClients, ref.Info())
+			}
+			return true
+		})
+	}
+
+	return d
+}
+
 // Close frees resources and caches.
 func (f *InfraProvider) Close() {
 	logger.Debug("Closing event service cache...")
@@ -94,6 +354,9 @@ func (f *InfraProvider) Close() {
 	logger.Debug("Closing channel configuration cache...")
 	f.chCfgCache.Close()
 
+	logger.Debug("Closing health monitor...")
+	f.healthMonitor.Close()
+
 	// Comm Manager must be closed last since other resources
 	// may still be using it.
 	logger.Debug("Closing comm manager...")
@@ -105,6 +368,11 @@ func (f *InfraProvider) CommManager() fab.CommManager {
 	return f.commManager
 }
 
+// EndpointHealth provides the health of the endpoints that the SDK connects to
+func (f *InfraProvider) EndpointHealth() fab.EndpointHealth {
+	return f.healthMonitor
+}
+
 // CreateEventService creates the event service.
 func (f *InfraProvider) CreateEventService(ctx fab.ClientContext, channelID string, opts ...options.Opt) (fab.EventService, error) {
 	chnlCfg, err := f.CreateChannelCfg(ctx, channelID)
@@ -198,6 +466,11 @@ func (f *InfraProvider) loadChannelCfgRef(ctx fab.ClientContext, channelID strin
 }
 
 func getEventClient(ctx context.Client, chConfig fab.ChannelCfg, opts ...options.Opt) (fab.EventClient, error) {
+	// The options of the caller take precedence over the configured reconnection parameters
+	if rc, ok := ctx.EndpointConfig().(eventReconnectConfigProvider); ok {
+		opts = append(reconnectOpts(rc.EventReconnectConfig()), opts...)
+	}
+
 	// TODO: This logic should be based on the channel capabilities. For now,
 	// look at the EventServiceType specified in the config file.
 	switch ctx.EndpointConfig().EventServiceType() {
@@ -210,3 +483,30 @@ func getEventClient(ctx context.Client, chConfig fab.ChannelCfg, opts ...options
 		return nil, errors.Errorf("unsupported event service type: %d", ctx.EndpointConfig().EventServiceType())
 	}
 }
+
+// reconnectOpts returns the event client options for the configured reconnection parameters
+func reconnectOpts(config fab.EventReconnectConfig) []options.Opt {
+	var opts []options.Opt
+	if config.InitialDelay > 0 {
+		opts = append(opts, esclient.WithReconnectInitialDelay(config.InitialDelay))
+	}
+	if config.TimeBetweenAttempts > 0 {
+		opts = append(opts, esclient.WithTimeBetweenConnectAttempts(config.TimeBetweenAttempts))
+	}
+	if config.BackoffFactor > 0 {
+		opts = append(opts, esclient.WithReconnectBackoffFactor(config.BackoffFactor))
+	}
+	if config.MaxDelay > 0 {
+		opts = append(opts, esclient.WithReconnectMaxDelay(config.MaxDelay))
+	}
+	if config.Jitter > 0 {
+		opts = append(opts, esclient.WithReconnectJitter(config.Jitter))
+	}
+	if config.MaxAttempts > 0 {
+		opts = append(opts, esclient.WithMaxReconnectAttempts(config.MaxAttempts))
+	}
+	if config.MaxElapsedTime > 0 {
+		opts = append(opts, esclient.WithReconnectMaxElapsedTime(config.MaxElapsedTime))
+	}
+	return opts
+}
@@ -83,6 +83,24 @@ func TestCreateMembership(t *testing.T) {
 	assert.NotNil(t, m)
 }
 
+func TestDiagnostics(t *testing.T) {
+	p := newInfraProvider(t)
+
+	assert.True(t, p.Acquire())
+	defer p.Release()
+
+	d, ok := p.Diagnostics().(*Diagnostics)
+	if !ok {
+		t.Fatalf("Unexpected diagnostics type")
+	}
+	assert.Equal(t, 1, d.InFlightRequests)
+	assert.Empty(t, d.Connections)
+	assert.Empty(t, d.EventClients)
+	assert.Equal(t, 0, d.CacheSizes["eventService"])
+	assert.Contains(t, d.CacheSizes, "channelConfig")
+	assert.Contains(t, d.CacheSizes, "membership")
+}
+
 func newInfraProvider(t *testing.T) *InfraProvider {
 	configBackend, err := config.FromFile("../../../../test/fixtures/config/config_test.yaml")()
 	if err != nil {
@@ -0,0 +1,84 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabpvdr
+
+import (
+	reqContext "context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// requestTracker counts the requests that are in flight. Once draining has started,
+// new requests are refused and the drained channel is closed when the last
+// in-flight request completes.
+type requestTracker struct {
+	lock     sync.Mutex
+	count    int
+	draining bool
+	drained  chan struct{}
+}
+
+func newRequestTracker() *requestTracker {
+	return &requestTracker{drained: make(chan struct{})}
+}
+
+// Acquire registers a new in-flight request. False is returned if the tracker is draining.
+func (t *requestTracker) Acquire() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.draining {
+		return false
+	}
+	t.count++
+	return true
+}
+
+// Release unregisters an in-flight request
+func (t *requestTracker) Release() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.count == 0 {
+		logger.Warn("Release called without a matching Acquire")
+		return
+	}
+	t.count--
+	if t.draining && t.count == 0 {
+		close(t.drained)
+	}
+}
+
+// Count returns the number of requests that are in flight
+func (t *requestTracker) Count() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.count
+}
+
+// Drain refuses new requests and waits for the in-flight requests to complete
+// or for the context to be done (in which case an error is returned)
+func (t *requestTracker) Drain(ctx reqContext.Context) error {
+	t.lock.Lock()
+	if !t.draining {
+		t.draining = true
+		if t.count == 0 {
+			close(t.drained)
+		}
+	}
+	t.lock.Unlock()
+
+	select {
+	case <-t.drained:
+		return nil
+	case <-ctx.Done():
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		return errors.Errorf("%d request(s) still in flight: %s", t.count, ctx.Err())
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabpvdr
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTrackerDrain(t *testing.T) {
+	tracker := newRequestTracker()
+
+	assert.True(t, tracker.Acquire())
+	assert.True(t, tracker.Acquire())
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		tracker.Release()
+		tracker.Release()
+	}()
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.NoError(t, tracker.Drain(ctx), "expecting in-flight requests to be drained")
+	assert.False(t, tracker.Acquire(), "expecting new requests to be refused after draining")
+	assert.NoError(t, tracker.Drain(ctx), "expecting drain to be idempotent")
+}
+
+func TestRequestTrackerDrainTimeout(t *testing.T) {
+	tracker := newRequestTracker()
+	assert.True(t, tracker.Acquire())
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := tracker.Drain(ctx)
+	assert.Error(t, err, "expecting drain to time out")
+	assert.Contains(t, err.Error(), "1 request(s) still in flight")
+
+	tracker.Release()
+}
+
+func TestRequestTrackerNoRequests(t *testing.T) {
+	tracker := newRequestTracker()
+	tracker.Release()
+
+	assert.NoError(t, tracker.Drain(reqContext.Background()))
+}
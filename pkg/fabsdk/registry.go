@@ -0,0 +1,164 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
+	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CorePkgConfigKey is the config key of the name of the registered core pkg to use
+	CorePkgConfigKey = "client.providers.core"
+	// MSPPkgConfigKey is the config key of the name of the registered MSP pkg to use
+	MSPPkgConfigKey = "client.providers.msp"
+	// ServicePkgConfigKey is the config key of the name of the registered service pkg to use
+	ServicePkgConfigKey = "client.providers.service"
+)
+
+// CorePkgFactory creates a core pkg. The config backend of the SDK is provided so
+// that the pkg can load its own settings.
+type CorePkgFactory func(config core.ConfigBackend) (sdkApi.CoreProviderFactory, error)
+
+// MSPPkgFactory creates an MSP pkg. The config backend of the SDK is provided so
+// that the pkg can load its own settings.
+type MSPPkgFactory func(config core.ConfigBackend) (sdkApi.MSPProviderFactory, error)
+
+// ServicePkgFactory creates a service pkg. The config backend of the SDK is provided so
+// that the pkg can load its own settings.
+type ServicePkgFactory func(config core.ConfigBackend) (sdkApi.ServiceProviderFactory, error)
+
+var pkgRegistry = struct {
+	sync.RWMutex
+	core    map[string]CorePkgFactory
+	msp     map[string]MSPPkgFactory
+	service map[string]ServicePkgFactory
+}{
+	core:    make(map[string]CorePkgFactory),
+	msp:     make(map[string]MSPPkgFactory),
+	service: make(map[string]ServicePkgFactory),
+}
+
+// RegisterCorePkg registers a core pkg under the given name. The pkg is used by an SDK whose
+// configuration sets client.providers.core to the name, unless a core pkg is passed to New
+// (see WithCorePkg). Registration is typically done from the init function of the package
+// that implements the pkg. RegisterCorePkg panics if the name is already registered.
+func RegisterCorePkg(name string, factory CorePkgFactory) {
+	pkgRegistry.Lock()
+	defer pkgRegistry.Unlock()
+
+	if _, exists := pkgRegistry.core[name]; exists || factory == nil {
+		panic("invalid or duplicate registration of core pkg " + name)
+	}
+	pkgRegistry.core[name] = factory
+}
+
+// RegisterMSPPkg registers an MSP pkg under the given name. The pkg is used by an SDK whose
+// configuration sets client.providers.msp to the name, unless an MSP pkg is passed to New
+// (see WithMSPPkg). RegisterMSPPkg panics if the name is already registered.
+func RegisterMSPPkg(name string, factory MSPPkgFactory) {
+	pkgRegistry.Lock()
+	defer pkgRegistry.Unlock()
+
+	if _, exists := pkgRegistry.msp[name]; exists || factory == nil {
+		panic("invalid or duplicate registration of MSP pkg " + name)
+	}
+	pkgRegistry.msp[name] = factory
+}
+
+// RegisterServicePkg registers a service pkg (e.g. discovery and selection providers) under the
+// given name. The pkg is used by an SDK whose configuration sets client.providers.service to the
+// name, unless a service pkg is passed to New (see WithServicePkg). RegisterServicePkg panics if
+// the name is already registered.
+func RegisterServicePkg(name string, factory ServicePkgFactory) {
+	pkgRegistry.Lock()
+	defer pkgRegistry.Unlock()
+
+	if _, exists := pkgRegistry.service[name]; exists || factory == nil {
+		panic("invalid or duplicate registration of service pkg " + name)
+	}
+	pkgRegistry.service[name] = factory
+}
+
+// RegisteredPkgs returns the sorted names of the registered core, MSP and service pkgs
+func RegisteredPkgs() (corePkgs, mspPkgs, servicePkgs []string) {
+	pkgRegistry.RLock()
+	defer pkgRegistry.RUnlock()
+
+	for name := range pkgRegistry.core {
+		corePkgs = append(corePkgs, name)
+	}
+	for name := range pkgRegistry.msp {
+		mspPkgs = append(mspPkgs, name)
+	}
+	for name := range pkgRegistry.service {
+		servicePkgs = append(servicePkgs, name)
+	}
+	sort.Strings(corePkgs)
+	sort.Strings(mspPkgs)
+	sort.Strings(servicePkgs)
+	return
+}
+
+// loadRegisteredPkgs replaces the pkgs that weren't passed as options with the
+// registered pkgs that are named in the configuration
+func (sdk *FabricSDK) loadRegisteredPkgs() error {
+	if sdk.opts.ConfigBackend == nil {
+		return nil
+	}
+
+	backend := lookup.New(sdk.opts.ConfigBackend)
+
+	pkgRegistry.RLock()
+	defer pkgRegistry.RUnlock()
+
+	if name := backend.GetString(CorePkgConfigKey); name != "" && !sdk.opts.customCore {
+		factory, ok := pkgRegistry.core[name]
+		if !ok {
+			return errors.Errorf("core pkg [%s] is not registered", name)
+		}
+		pkg, err := factory(sdk.opts.ConfigBackend)
+		if err != nil {
+			return errors.WithMessage(err, "failed to create core pkg "+name)
+		}
+		logger.Debugf("Using registered core pkg [%s]", name)
+		sdk.opts.Core = pkg
+	}
+
+	if name := backend.GetString(MSPPkgConfigKey); name != "" && !sdk.opts.customMSP {
+		factory, ok := pkgRegistry.msp[name]
+		if !ok {
+			return errors.Errorf("MSP pkg [%s] is not registered", name)
+		}
+		pkg, err := factory(sdk.opts.ConfigBackend)
+		if err != nil {
+			return errors.WithMessage(err, "failed to create MSP pkg "+name)
+		}
+		logger.Debugf("Using registered MSP pkg [%s]", name)
+		sdk.opts.MSP = pkg
+	}
+
+	if name := backend.GetString(ServicePkgConfigKey); name != "" && !sdk.opts.customService {
+		factory, ok := pkgRegistry.service[name]
+		if !ok {
+			return errors.Errorf("service pkg [%s] is not registered", name)
+		}
+		pkg, err := factory(sdk.opts.ConfigBackend)
+		if err != nil {
+			return errors.WithMessage(err, "failed to create service pkg "+name)
+		}
+		logger.Debugf("Using registered service pkg [%s]", name)
+		sdk.opts.Service = pkg
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defsvc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registeredSvcPkg struct {
+	*defsvc.ProviderFactory
+}
+
+// backendWithProviders overrides the provider pkg names of a config backend
+type backendWithProviders struct {
+	core.ConfigBackend
+	values map[string]interface{}
+}
+
+func (b *backendWithProviders) Lookup(key string) (interface{}, bool) {
+	if value, ok := b.values[key]; ok {
+		return value, true
+	}
+	return b.ConfigBackend.Lookup(key)
+}
+
+func configWithProviders(values map[string]interface{}) core.ConfigProvider {
+	return func() (core.ConfigBackend, error) {
+		backend, err := configImpl.FromFile(sdkConfigFile)()
+		if err != nil {
+			return nil, err
+		}
+		return &backendWithProviders{ConfigBackend: backend, values: values}, nil
+	}
+}
+
+func TestRegisteredServicePkg(t *testing.T) {
+	created := 0
+	RegisterServicePkg("test-svc", func(config core.ConfigBackend) (sdkApi.ServiceProviderFactory, error) {
+		created++
+		return &registeredSvcPkg{ProviderFactory: defsvc.NewProviderFactory()}, nil
+	})
+
+	_, _, servicePkgs := RegisteredPkgs()
+	assert.Contains(t, servicePkgs, "test-svc")
+
+	sdk, err := New(configWithProviders(map[string]interface{}{ServicePkgConfigKey: "test-svc"}))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	assert.Equal(t, 1, created)
+	_, ok := sdk.opts.Service.(*registeredSvcPkg)
+	assert.True(t, ok, "expecting registered service pkg to be used")
+
+	// A pkg passed as an option takes precedence
+	sdk2, err := New(configWithProviders(map[string]interface{}{ServicePkgConfigKey: "test-svc"}), WithServicePkg(defsvc.NewProviderFactory()))
+	require.NoError(t, err)
+	defer sdk2.Close()
+
+	assert.Equal(t, 1, created)
+
+	assert.Panics(t, func() {
+		RegisterServicePkg("test-svc", func(config core.ConfigBackend) (sdkApi.ServiceProviderFactory, error) { return nil, nil })
+	}, "expecting duplicate registration to panic")
+}
+
+func TestUnregisteredPkg(t *testing.T) {
+	_, err := New(configWithProviders(map[string]interface{}{CorePkgConfigKey: "unknown"}))
+	assert.Error(t, err, "expecting error for unregistered core pkg")
+}
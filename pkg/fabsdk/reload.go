@@ -0,0 +1,223 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/opevents"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/pkg/errors"
+)
+
+// retiredProvidersGracePeriod is the time given to the requests that were started before a
+// reload to complete before the providers that were replaced by the reload are closed
+var retiredProvidersGracePeriod = time.Minute
+
+// reconciler is implemented by infra providers that are able to drop the connections
+// and cached state of endpoints that are no longer configured
+type reconciler interface {
+	Reconcile() error
+}
+
+// Reload reloads the endpoint and identity configuration of the SDK without recreating it.
+// The identity manager, discovery and selection providers are recreated from the new
+// configuration and swapped in, so that clients that were created before the reload pick
+// up the new configuration on their next request. The connections to endpoints that are
+// no longer configured are closed (once idle) and the cached channel contexts are dropped.
+// The tenants of the SDK (see NewTenant) are updated as well.
+//
+// Configs passed to New through options take priority over the reloaded configuration.
+// The crypto suite configuration is not reloaded. The replaced providers are closed once
+// the requests that were started before the reload have had time to complete.
+//  Parameters:
+//  configBackends provide the new configuration. A key is looked up in each of the backends,
+//  in order, until it's found.
+//
+//  Returns:
+//  an error if the configuration couldn't be loaded, in which case the SDK keeps its current configuration
+func (sdk *FabricSDK) Reload(configBackends ...core.ConfigBackend) error {
+	err := sdk.reload(configBackends)
+	opevents.Publish(&opevents.Event{Type: opevents.ConfigReloaded, Err: err})
+	return err
+}
+
+func (sdk *FabricSDK) reload(configBackends []core.ConfigBackend) error {
+	if len(configBackends) == 0 {
+		return errors.New("no config backend provided")
+	}
+
+	sdk.reloadLock.Lock()
+	defer sdk.reloadLock.Unlock()
+
+	cfg, err := sdk.loadConfigs(func() (core.ConfigBackend, error) {
+		if len(configBackends) == 1 {
+			return configBackends[0], nil
+		}
+		return compositeBackend(configBackends), nil
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to reload configuration")
+	}
+
+	identityManagerProvider, err := sdk.opts.MSP.CreateIdentityManagerProvider(cfg.endpointConfig, sdk.provider.CryptoSuite(), sdk.provider.UserStore())
+	if err != nil {
+		return errors.WithMessage(err, "failed to create identity manager provider")
+	}
+
+	discoveryProvider, err := sdk.opts.Service.CreateDiscoveryProvider(cfg.endpointConfig)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create discovery provider")
+	}
+
+	localDiscoveryProvider, err := sdk.opts.Service.CreateLocalDiscoveryProvider(cfg.endpointConfig)
+	if err != nil {
+		closeProviders(discoveryProvider)
+		return errors.WithMessage(err, "failed to create local discovery provider")
+	}
+
+	selectionProvider, err := sdk.opts.Service.CreateSelectionProvider(cfg.endpointConfig)
+	if err != nil {
+		closeProviders(discoveryProvider, localDiscoveryProvider)
+		return errors.WithMessage(err, "failed to create selection provider")
+	}
+
+	// The new providers are initialized with the updated configuration before they're swapped in
+	providers := context.NewProvider(context.WithCryptoSuiteConfig(sdk.provider.CryptoSuiteConfig()),
+		context.WithEndpointConfig(cfg.endpointConfig),
+		context.WithIdentityConfig(cfg.identityConfig),
+		context.WithCryptoSuite(sdk.provider.CryptoSuite()),
+		context.WithSigningManager(sdk.provider.SigningManager()),
+		context.WithUserStore(sdk.provider.UserStore()),
+		context.WithDiscoveryProvider(discoveryProvider),
+		context.WithLocalDiscoveryProvider(localDiscoveryProvider),
+		context.WithSelectionProvider(selectionProvider),
+		context.WithIdentityManagerProvider(identityManagerProvider),
+		context.WithInfraProvider(sdk.provider.InfraProvider()),
+		context.WithChannelProvider(sdk.provider.ChannelProvider()),
+		context.WithReadOnly(sdk.provider.ReadOnly()),
+		context.WithCATransport(sdk.provider.CATransport()),
+		context.WithClock(sdk.provider.Clock()),
+		context.WithRandomSource(sdk.provider.RandomSource()))
+
+	for _, p := range []interface{}{discoveryProvider, localDiscoveryProvider, selectionProvider} {
+		if pi, ok := p.(providerInit); ok {
+			if err := pi.Initialize(providers); err != nil {
+				closeProviders(discoveryProvider, localDiscoveryProvider, selectionProvider)
+				return errors.WithMessage(err, "failed to initialize provider")
+			}
+		}
+	}
+
+	// The previous providers may still be in use by in-flight requests so they're closed after a grace period
+	sdk.retired.add(retiredProvidersGracePeriod, sdk.provider.DiscoveryProvider(), sdk.provider.LocalDiscoveryProvider(), sdk.provider.SelectionProvider())
+
+	sdk.provider.Update(context.WithEndpointConfig(cfg.endpointConfig),
+		context.WithIdentityConfig(cfg.identityConfig),
+		context.WithIdentityManagerProvider(identityManagerProvider),
+		context.WithDiscoveryProvider(discoveryProvider),
+		context.WithLocalDiscoveryProvider(localDiscoveryProvider),
+		context.WithSelectionProvider(selectionProvider))
+
+	// Certificates that were provided programmatically remain in effect
+	sdk.certRotation.reapply()
+
+	sdk.contextPool.Clear()
+
+	if err := sdk.reloadTenants(); err != nil {
+		return errors.WithMessage(err, "failed to reload tenants")
+	}
+
+	if r, ok := sdk.provider.InfraProvider().(reconciler); ok {
+		if err := r.Reconcile(); err != nil {
+			return errors.WithMessage(err, "failed to reconcile infra provider")
+		}
+	}
+
+	logger.Debug("SDK configuration reloaded")
+	return nil
+}
+
+func closeProviders(providers ...interface{}) {
+	for _, p := range providers {
+		if c, ok := p.(closeable); ok {
+			c.Close()
+		}
+	}
+}
+
+// compositeBackend looks up a key in each of the backends, in order, until it's found
+type compositeBackend []core.ConfigBackend
+
+// Lookup returns the value of the key from the first backend that has it
+func (c compositeBackend) Lookup(key string) (interface{}, bool) {
+	for _, backend := range c {
+		if value, ok := backend.Lookup(key); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// retiredProviders holds the providers that were replaced by a reload until they're closed
+type retiredProviders struct {
+	lock        sync.Mutex
+	retirements []*retirement
+}
+
+type retirement struct {
+	providers []interface{}
+	timer     *time.Timer
+}
+
+// add closes the given providers once the grace period has elapsed
+func (r *retiredProviders) add(gracePeriod time.Duration, providers ...interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	rt := &retirement{providers: providers}
+	rt.timer = time.AfterFunc(gracePeriod, func() { r.close(rt) })
+	r.retirements = append(r.retirements, rt)
+}
+
+func (r *retiredProviders) close(rt *retirement) {
+	r.lock.Lock()
+	found := false
+	for i, e := range r.retirements {
+		if e == rt {
+			r.retirements = append(r.retirements[:i], r.retirements[i+1:]...)
+			found = true
+			break
+		}
+	}
+	r.lock.Unlock()
+
+	if found {
+		closeProviders(rt.providers...)
+	}
+}
+
+// closeAll closes all of the retired providers without waiting for their grace period
+func (r *retiredProviders) closeAll() {
+	r.lock.Lock()
+	retirements := r.retirements
+	r.retirements = nil
+	r.lock.Unlock()
+
+	for _, rt := range retirements {
+		rt.timer.Stop()
+		closeProviders(rt.providers...)
+	}
+}
+
+func (r *retiredProviders) count() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.retirements)
+}
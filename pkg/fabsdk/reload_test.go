@@ -0,0 +1,112 @@
+// +build testing
+
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/opevents"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	coreMocks "github.com/hyperledger/fabric-sdk-go/pkg/core/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/fabpvdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReload(t *testing.T) {
+	sdk, err := New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	sdk.provider.InfraProvider().(*fabpvdr.InfraProvider).SetChannelConfig(mocks.NewMockChannelCfg("mychannel"))
+
+	ctx, err := sdk.Context()()
+	require.NoError(t, err)
+	endpointConfig := ctx.EndpointConfig()
+	discoveryProvider := ctx.DiscoveryProvider()
+
+	chCtx, err := sdk.ChannelContextPool().Get("mychannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg1))
+	require.NoError(t, err)
+
+	configBackend, err := config.FromFile(sdkConfigFile)()
+	require.NoError(t, err)
+	require.NoError(t, sdk.Reload(configBackend))
+
+	// Contexts created before the reload share the updated providers
+	assert.False(t, ctx.EndpointConfig() == endpointConfig, "expecting reloaded endpoint config")
+	assert.False(t, ctx.DiscoveryProvider() == discoveryProvider, "expecting new discovery provider")
+	assert.Equal(t, 1, sdk.retired.count(), "expecting the replaced providers to be retired")
+
+	sdk.provider.InfraProvider().(*fabpvdr.InfraProvider).SetChannelConfig(mocks.NewMockChannelCfg("mychannel"))
+
+	newChCtx, err := sdk.ChannelContextPool().Get("mychannel", WithUser(sdkValidClientUser), WithOrg(sdkValidClientOrg1))
+	require.NoError(t, err)
+	assert.False(t, newChCtx == chCtx, "expecting channel contexts to be dropped on reload")
+}
+
+func TestReloadError(t *testing.T) {
+	sdk, err := New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	endpointConfig := sdk.provider.EndpointConfig()
+
+	sub := opevents.Subscribe(0, opevents.ConfigReloaded)
+	defer sub.Close()
+
+	err = sdk.Reload()
+	assert.Error(t, err, "expecting error for missing config backend")
+	require.Len(t, sub.Events(), 1)
+	assert.Error(t, (<-sub.Events()).Err, "expecting failed reload to be published")
+	assert.True(t, sdk.provider.EndpointConfig() == endpointConfig, "expecting configuration to be unchanged")
+	assert.Equal(t, 0, sdk.retired.count())
+}
+
+func TestReloadRetiredProviders(t *testing.T) {
+	gracePeriod := retiredProvidersGracePeriod
+	retiredProvidersGracePeriod = 10 * time.Millisecond
+	defer func() { retiredProvidersGracePeriod = gracePeriod }()
+
+	sdk, err := New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	configBackend, err := config.FromFile(sdkConfigFile)()
+	require.NoError(t, err)
+	require.NoError(t, sdk.Reload(configBackend, &coreMocks.MockConfigBackend{}))
+	require.NoError(t, sdk.Reload(configBackend))
+
+	// The retired providers are closed and dropped once the grace period has elapsed
+	timeout := time.After(5 * time.Second)
+	for sdk.retired.count() > 0 {
+		select {
+		case <-timeout:
+			t.Fatal("timed out waiting for the retired providers to be closed")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestCompositeBackend(t *testing.T) {
+	backend := compositeBackend{
+		&coreMocks.MockConfigBackend{KeyValueMap: map[string]interface{}{"key1": "value1"}},
+		&coreMocks.MockConfigBackend{KeyValueMap: map[string]interface{}{"key1": "other", "key2": "value2"}},
+	}
+
+	value, ok := backend.Lookup("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", value, "expecting the first backend to take precedence")
+	value, ok = backend.Lookup("key2")
+	assert.True(t, ok)
+	assert.Equal(t, "value2", value)
+	_, ok = backend.Lookup("key3")
+	assert.False(t, ok)
+}
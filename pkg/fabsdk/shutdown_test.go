@@ -0,0 +1,65 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown(t *testing.T) {
+	hookCalls := 0
+	sdk, err := New(configImpl.FromFile(sdkConfigFile), WithShutdownHook(func(ctx reqContext.Context) error {
+		hookCalls++
+		return nil
+	}))
+	require.NoError(t, err)
+
+	tracker, ok := sdk.provider.InfraProvider().(fab.RequestTracker)
+	require.True(t, ok, "expecting infra provider to track requests")
+	require.True(t, tracker.Acquire())
+	tracker.Release()
+
+	err = sdk.Shutdown(reqContext.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, hookCalls)
+	assert.False(t, tracker.Acquire(), "expecting new requests to be refused after shutdown")
+
+	sdk.Close()
+	assert.Equal(t, 1, hookCalls, "expecting shutdown hooks to be invoked once")
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	hookErr := errors.New("flush failed")
+	sdk, err := New(configImpl.FromFile(sdkConfigFile),
+		WithShutdownTimeout(100*time.Millisecond),
+		WithShutdownHook(func(ctx reqContext.Context) error { return hookErr }))
+	require.NoError(t, err)
+
+	tracker := sdk.provider.InfraProvider().(fab.RequestTracker)
+	require.True(t, tracker.Acquire())
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), sdk.opts.ShutdownTimeout)
+	defer cancel()
+
+	err = sdk.Shutdown(ctx)
+	assert.Error(t, err, "expecting drain to time out with a request in flight")
+
+	tracker.Release()
+}
+
+func TestWithShutdownHookNil(t *testing.T) {
+	_, err := New(configImpl.FromFile(sdkConfigFile), WithShutdownHook(nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,431 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	reqContext "context"
+	"sync"
+	"time"
+
+	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// ErrTenantQuotaExceeded is the cause of the error returned when a request of a tenant
+// can't be sent within its deadline because of the tenant's connection budget or rate limit
+var ErrTenantQuotaExceeded = errors.New("tenant quota exceeded")
+
+// Tenant is an isolated scope of the SDK that serves a single tenant (client organization)
+// of a multi-tenant application. A tenant has its own credential store, channel context pool,
+// connection budget and rate limit, while the configuration, connections, caches and event
+// services are shared with the parent SDK.
+//
+// The connection budget and rate limit apply to the GRPC connections to peers and orderers
+// that are acquired by the clients created from the contexts of the tenant.
+type Tenant struct {
+	name        string
+	sdk         *FabricSDK
+	userStore   msp.UserStore
+	provider    *context.Provider
+	commManager *tenantCommManager
+	contextPool *ChannelContextPool
+}
+
+type tenantOptions struct {
+	userStore      msp.UserStore
+	credentialPath string
+	maxConnections int
+	rateLimit      float64
+	burst          int
+}
+
+// TenantOption describes a functional parameter for NewTenant
+type TenantOption func(opts *tenantOptions) error
+
+// WithTenantUserStore uses the given user store as the credential store of the tenant
+func WithTenantUserStore(userStore msp.UserStore) TenantOption {
+	return func(opts *tenantOptions) error {
+		opts.userStore = userStore
+		return nil
+	}
+}
+
+// WithTenantCredentialStore stores the credentials of the tenant's users in the given directory
+func WithTenantCredentialStore(path string) TenantOption {
+	return func(opts *tenantOptions) error {
+		if path == "" {
+			return errors.New("credential store path is empty")
+		}
+		opts.credentialPath = path
+		return nil
+	}
+}
+
+// WithConnectionBudget limits the number of connections that the tenant may hold at the same time.
+// Requests wait (up to their connection timeout) for a connection to be released once the budget is spent.
+func WithConnectionBudget(maxConnections int) TenantOption {
+	return func(opts *tenantOptions) error {
+		if maxConnections <= 0 {
+			return errors.New("connection budget must be greater than zero")
+		}
+		opts.maxConnections = maxConnections
+		return nil
+	}
+}
+
+// WithRateLimit limits the rate at which the tenant may acquire connections (i.e. send requests)
+// to the given number per second, allowing bursts of up to burst requests.
+func WithRateLimit(requestsPerSecond float64, burst int) TenantOption {
+	return func(opts *tenantOptions) error {
+		if requestsPerSecond <= 0 || burst <= 0 {
+			return errors.New("rate limit and burst must be greater than zero")
+		}
+		opts.rateLimit = requestsPerSecond
+		opts.burst = burst
+		return nil
+	}
+}
+
+// NewTenant creates an isolated scope of the SDK for the given tenant. A credential store
+// must be provided (see WithTenantUserStore and WithTenantCredentialStore) so that the users
+// of the tenant are isolated from those of the SDK and of the other tenants.
+//  Parameters:
+//  name is the unique name of the tenant
+//  options specify the credential store and quotas of the tenant
+//
+//  Returns:
+//  the tenant, which is closed when the SDK is closed
+func (sdk *FabricSDK) NewTenant(name string, options ...TenantOption) (*Tenant, error) {
+	if name == "" {
+		return nil, errors.New("tenant name is required")
+	}
+
+	opts := tenantOptions{}
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return nil, errors.WithMessage(err, "error in option passed to NewTenant")
+		}
+	}
+
+	userStore, err := opts.newUserStore()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create credential store of tenant ["+name+"]")
+	}
+
+	sdk.reloadLock.Lock()
+	defer sdk.reloadLock.Unlock()
+
+	if _, ok := sdk.tenants[name]; ok {
+		return nil, errors.Errorf("tenant [%s] already exists", name)
+	}
+
+	t := &Tenant{
+		name:        name,
+		sdk:         sdk,
+		userStore:   userStore,
+		commManager: newTenantCommManager(name, sdk.provider.InfraProvider().CommManager(), opts),
+	}
+
+	identityManagerProvider, err := t.newIdentityManagerProvider(sdk.provider.EndpointConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	t.provider = context.NewProvider(context.WithCryptoSuiteConfig(sdk.provider.CryptoSuiteConfig()),
+		context.WithEndpointConfig(sdk.provider.EndpointConfig()),
+		context.WithIdentityConfig(sdk.provider.IdentityConfig()),
+		context.WithCryptoSuite(sdk.provider.CryptoSuite()),
+		context.WithSigningManager(sdk.provider.SigningManager()),
+		context.WithUserStore(userStore),
+		context.WithDiscoveryProvider(sdk.provider.DiscoveryProvider()),
+		context.WithLocalDiscoveryProvider(sdk.provider.LocalDiscoveryProvider()),
+		context.WithSelectionProvider(sdk.provider.SelectionProvider()),
+		context.WithIdentityManagerProvider(identityManagerProvider),
+		context.WithInfraProvider(&tenantInfraProvider{InfraProvider: sdk.provider.InfraProvider(), commManager: t.commManager}),
+		context.WithChannelProvider(sdk.provider.ChannelProvider()),
+		context.WithReadOnly(sdk.provider.ReadOnly()),
+		context.WithCATransport(sdk.provider.CATransport()),
+		context.WithClock(sdk.provider.Clock()),
+		context.WithRandomSource(sdk.provider.RandomSource()))
+	t.contextPool = newChannelContextPool(t.provider)
+
+	if sdk.tenants == nil {
+		sdk.tenants = make(map[string]*Tenant)
+	}
+	sdk.tenants[name] = t
+
+	logger.Debugf("Created tenant [%s]", name)
+	return t, nil
+}
+
+// Tenant returns the tenant with the given name or false if there's no such tenant
+func (sdk *FabricSDK) Tenant(name string) (*Tenant, bool) {
+	sdk.reloadLock.Lock()
+	defer sdk.reloadLock.Unlock()
+
+	t, ok := sdk.tenants[name]
+	return t, ok
+}
+
+// Name returns the name of the tenant
+func (t *Tenant) Name() string {
+	return t.name
+}
+
+// Context creates a client context for a user of the tenant
+func (t *Tenant) Context(options ...ContextOption) contextApi.ClientProvider {
+	return newClientProvider(t.provider, options...)
+}
+
+// ChannelContext creates a channel context for a user of the tenant
+func (t *Tenant) ChannelContext(channelID string, options ...ContextOption) contextApi.ChannelProvider {
+	return func() (contextApi.Channel, error) {
+		return context.NewChannel(t.Context(options...), channelID)
+	}
+}
+
+// ChannelContextPool returns the pool of channel contexts of the tenant
+func (t *Tenant) ChannelContextPool() *ChannelContextPool {
+	return t.contextPool
+}
+
+// ConnectionsInUse returns the number of connections currently held by the tenant
+func (t *Tenant) ConnectionsInUse() int {
+	return t.commManager.inUse()
+}
+
+// Close removes the tenant from the SDK and frees up its channel contexts. The resources shared
+// with the SDK are not closed. Clients created from the contexts of the tenant must no longer be used.
+func (t *Tenant) Close() {
+	t.sdk.reloadLock.Lock()
+	if t.sdk.tenants[t.name] == t {
+		delete(t.sdk.tenants, t.name)
+	}
+	t.sdk.reloadLock.Unlock()
+
+	t.contextPool.Close()
+	logger.Debugf("Closed tenant [%s]", t.name)
+}
+
+func (t *Tenant) newIdentityManagerProvider(endpointConfig fab.EndpointConfig) (msp.IdentityManagerProvider, error) {
+	identityManagerProvider, err := t.sdk.opts.MSP.CreateIdentityManagerProvider(endpointConfig, t.sdk.provider.CryptoSuite(), t.userStore)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create identity manager provider of tenant ["+t.name+"]")
+	}
+	return identityManagerProvider, nil
+}
+
+// reloadTenants swaps the reloaded configuration and providers of the SDK into its tenants.
+// The caller must hold the reload lock.
+func (sdk *FabricSDK) reloadTenants() error {
+	for _, t := range sdk.tenants {
+		identityManagerProvider, err := t.newIdentityManagerProvider(sdk.provider.EndpointConfig())
+		if err != nil {
+			return err
+		}
+
+		t.provider.Update(context.WithEndpointConfig(sdk.provider.EndpointConfig()),
+			context.WithIdentityConfig(sdk.provider.IdentityConfig()),
+			context.WithIdentityManagerProvider(identityManagerProvider),
+			context.WithDiscoveryProvider(sdk.provider.DiscoveryProvider()),
+			context.WithLocalDiscoveryProvider(sdk.provider.LocalDiscoveryProvider()),
+			context.WithSelectionProvider(sdk.provider.SelectionProvider()))
+
+		t.contextPool.Clear()
+	}
+	return nil
+}
+
+// closeTenants closes the channel context pools of the tenants
+func (sdk *FabricSDK) closeTenants() {
+	sdk.reloadLock.Lock()
+	defer sdk.reloadLock.Unlock()
+
+	for name, t := range sdk.tenants {
+		t.contextPool.Close()
+		delete(sdk.tenants, name)
+	}
+}
+
+func (opts *tenantOptions) newUserStore() (msp.UserStore, error) {
+	if opts.userStore != nil {
+		return opts.userStore, nil
+	}
+	if opts.credentialPath == "" {
+		return nil, errors.New("a user store or credential store path is required")
+	}
+	return mspImpl.NewCertFileUserStore(opts.credentialPath)
+}
+
+// tenantInfraProvider shares the infra provider of the SDK with a tenant, except for the comm
+// manager which enforces the quotas of the tenant
+type tenantInfraProvider struct {
+	fab.InfraProvider
+	commManager fab.CommManager
+}
+
+// CommManager returns the comm manager of the tenant
+func (p *tenantInfraProvider) CommManager() fab.CommManager {
+	return p.commManager
+}
+
+// Close does nothing since the infra provider is owned by the SDK
+func (p *tenantInfraProvider) Close() {
+}
+
+// tenantCommManager enforces the connection budget and rate limit of a tenant on top of the
+// comm manager of the SDK. Since the SDK's comm manager may return the same (cached) connection
+// to several callers, the connections are counted per acquisition.
+type tenantCommManager struct {
+	fab.CommManager
+	tenant   string
+	budget   chan struct{}
+	limiter  *rateLimiter
+	lock     sync.Mutex
+	acquired map[*grpc.ClientConn]int
+}
+
+func newTenantCommManager(tenant string, commManager fab.CommManager, opts tenantOptions) *tenantCommManager {
+	cm := &tenantCommManager{
+		CommManager: commManager,
+		tenant:      tenant,
+		acquired:    make(map[*grpc.ClientConn]int),
+	}
+	if opts.maxConnections > 0 {
+		cm.budget = make(chan struct{}, opts.maxConnections)
+	}
+	if opts.rateLimit > 0 {
+		cm.limiter = newRateLimiter(opts.rateLimit, opts.burst)
+	}
+	return cm
+}
+
+// DialContext waits for the rate limit and connection budget of the tenant to allow a new
+// connection and then acquires the connection from the comm manager of the SDK
+func (cm *tenantCommManager) DialContext(ctx reqContext.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if cm.limiter != nil {
+		if err := cm.limiter.wait(ctx); err != nil {
+			return nil, errors.Wrapf(ErrTenantQuotaExceeded, "rate limit of tenant [%s] exceeded: %s", cm.tenant, err)
+		}
+	}
+
+	if cm.budget != nil {
+		select {
+		case cm.budget <- struct{}{}:
+		case <-ctx.Done():
+			return nil, errors.Wrapf(ErrTenantQuotaExceeded, "connection budget of tenant [%s] exhausted: %s", cm.tenant, ctx.Err())
+		}
+	}
+
+	conn, err := cm.CommManager.DialContext(ctx, target, opts...)
+	if err != nil {
+		cm.releaseBudget()
+		return nil, err
+	}
+
+	cm.lock.Lock()
+	cm.acquired[conn]++
+	cm.lock.Unlock()
+
+	return conn, nil
+}
+
+// ReleaseConn releases the connection to the comm manager of the SDK and returns it to the budget of the tenant
+func (cm *tenantCommManager) ReleaseConn(conn *grpc.ClientConn) {
+	cm.lock.Lock()
+	count, ok := cm.acquired[conn]
+	if ok {
+		if count <= 1 {
+			delete(cm.acquired, conn)
+		} else {
+			cm.acquired[conn] = count - 1
+		}
+	}
+	cm.lock.Unlock()
+
+	if ok {
+		cm.releaseBudget()
+	}
+	cm.CommManager.ReleaseConn(conn)
+}
+
+func (cm *tenantCommManager) releaseBudget() {
+	if cm.budget != nil {
+		<-cm.budget
+	}
+}
+
+func (cm *tenantCommManager) inUse() int {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
+	n := 0
+	for _, count := range cm.acquired {
+		n += count
+	}
+	return n
+}
+
+// rateLimiter is a token bucket which is refilled at a constant rate
+type rateLimiter struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or until the context is done
+func (l *rateLimiter) wait(ctx reqContext.Context) error {
+	for {
+		delay := l.take()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// take takes a token if one is available and returns 0; otherwise it returns the time until the next token is available
+func (l *rateLimiter) take() time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	reqContext "context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestNewTenant(t *testing.T) {
+	sdk, err := New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	dir, err := ioutil.TempDir("", "tenant")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = sdk.NewTenant("tenant1")
+	assert.Error(t, err, "expecting error without a credential store")
+
+	_, err = sdk.NewTenant("tenant1", WithTenantCredentialStore(dir), WithConnectionBudget(0))
+	assert.Error(t, err, "expecting error for invalid connection budget")
+
+	tenant, err := sdk.NewTenant("tenant1", WithTenantCredentialStore(dir), WithConnectionBudget(10), WithRateLimit(100, 10))
+	require.NoError(t, err)
+	assert.Equal(t, "tenant1", tenant.Name())
+
+	_, err = sdk.NewTenant("tenant1", WithTenantCredentialStore(dir))
+	assert.Error(t, err, "expecting error for duplicate tenant")
+
+	found, ok := sdk.Tenant("tenant1")
+	require.True(t, ok)
+	assert.True(t, found == tenant)
+
+	ctx, err := tenant.Context(WithUser(sdkValidClientUser))()
+	require.NoError(t, err)
+	assert.True(t, ctx.UserStore() == tenant.userStore, "expecting the credential store of the tenant")
+	assert.False(t, ctx.UserStore() == sdk.provider.UserStore())
+	assert.True(t, ctx.InfraProvider().CommManager() == tenant.commManager, "expecting the comm manager of the tenant")
+	assert.Equal(t, 0, tenant.ConnectionsInUse())
+
+	tenant.Close()
+	_, ok = sdk.Tenant("tenant1")
+	assert.False(t, ok, "expecting tenant to be removed")
+}
+
+type stubCommManager struct {
+	released int
+}
+
+func (cm *stubCommManager) DialContext(ctx reqContext.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if target == "invalid" {
+		return nil, errors.New("dial failed")
+	}
+	return &grpc.ClientConn{}, nil
+}
+
+func (cm *stubCommManager) ReleaseConn(conn *grpc.ClientConn) {
+	cm.released++
+}
+
+func TestTenantConnectionBudget(t *testing.T) {
+	stub := &stubCommManager{}
+	cm := newTenantCommManager("tenant1", stub, tenantOptions{maxConnections: 2})
+
+	conn1, err := cm.DialContext(reqContext.Background(), "peer1")
+	require.NoError(t, err)
+	conn2, err := cm.DialContext(reqContext.Background(), "peer2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cm.inUse())
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = cm.DialContext(ctx, "peer3")
+	require.Error(t, err)
+	assert.Equal(t, ErrTenantQuotaExceeded, errors.Cause(err))
+
+	cm.ReleaseConn(conn1)
+	assert.Equal(t, 1, stub.released)
+	assert.Equal(t, 1, cm.inUse())
+
+	_, err = cm.DialContext(reqContext.Background(), "invalid")
+	assert.Error(t, err)
+	assert.Equal(t, 1, cm.inUse(), "expecting budget to be returned after a failed dial")
+
+	_, err = cm.DialContext(reqContext.Background(), "peer3")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cm.inUse())
+
+	cm.ReleaseConn(conn2)
+	assert.Equal(t, 1, cm.inUse())
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(20, 2)
+
+	require.NoError(t, limiter.wait(reqContext.Background()))
+	require.NoError(t, limiter.wait(reqContext.Background()))
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 5*time.Millisecond)
+	defer cancel()
+	assert.Error(t, limiter.wait(ctx), "expecting rate limit to be exceeded after burst")
+
+	start := time.Now()
+	require.NoError(t, limiter.wait(reqContext.Background()))
+	assert.True(t, time.Since(start) < time.Second, "expecting token to be available after refill")
+}
@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	reqContext "context"
+	"math"
+
+	"github.com/golang/protobuf/proto"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// deliverBlocks delivers the blocks requested by the given seek envelope using the given function
+// and returns the status that ends the delivery
+func (n *Network) deliverBlocks(ctx reqContext.Context, env *cb.Envelope, send func(channelID string, block *cb.Block) error) cb.Status {
+	payload, err := protos_utils.GetPayload(env)
+	if err != nil || payload.Header == nil {
+		logger.Debugf("invalid seek envelope: %v", err)
+		return cb.Status_BAD_REQUEST
+	}
+	chHeader, err := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		logger.Debugf("invalid channel header of seek envelope: %s", err)
+		return cb.Status_BAD_REQUEST
+	}
+	seekInfo := &ab.SeekInfo{}
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		logger.Debugf("invalid seek info: %s", err)
+		return cb.Status_BAD_REQUEST
+	}
+
+	ledger, ok := n.Ledger(chHeader.ChannelId)
+	if !ok {
+		return cb.Status_NOT_FOUND
+	}
+
+	start := seekPosition(seekInfo.Start, ledger, 0)
+	stop := seekPosition(seekInfo.Stop, ledger, math.MaxUint64)
+	if stop < start {
+		return cb.Status_BAD_REQUEST
+	}
+
+	for number := start; number <= stop; number++ {
+		if seekInfo.Behavior == ab.SeekInfo_FAIL_IF_NOT_READY && number >= ledger.Height() {
+			return cb.Status_NOT_FOUND
+		}
+		block, err := ledger.waitForBlock(ctx, number)
+		if err != nil {
+			// The stream was closed
+			return cb.Status_SERVICE_UNAVAILABLE
+		}
+		if err := send(ledger.ChannelID(), block); err != nil {
+			logger.Debugf("error sending block %d: %s", number, err)
+			return cb.Status_SERVICE_UNAVAILABLE
+		}
+		if number == math.MaxUint64 {
+			break
+		}
+	}
+	return cb.Status_SUCCESS
+}
+
+// seekPosition returns the block number of the given position. The default applies if the position isn't set.
+func seekPosition(position *ab.SeekPosition, ledger *Ledger, defaultNumber uint64) uint64 {
+	switch {
+	case position.GetOldest() != nil:
+		return 0
+	case position.GetNewest() != nil:
+		if height := ledger.Height(); height > 0 {
+			return height - 1
+		}
+		return 0
+	case position.GetSpecified() != nil:
+		return position.GetSpecified().Number
+	default:
+		return defaultNumber
+	}
+}
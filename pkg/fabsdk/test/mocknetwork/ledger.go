@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	reqContext "context"
+	"crypto/sha256"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Ledger holds the blocks of a channel. The blocks are cut by the orderer (or emitted by the test)
+// and delivered by the deliver services of the peers and of the orderer.
+//
+// This component has been designed to be safe for concurrency.
+type Ledger struct {
+	mutex     sync.RWMutex
+	channelID string
+	blocks    []*cb.Block
+	added     chan struct{}
+}
+
+func newLedger(channelID string) *Ledger {
+	return &Ledger{
+		channelID: channelID,
+		added:     make(chan struct{}),
+	}
+}
+
+// ChannelID returns the ID of the channel
+func (l *Ledger) ChannelID() string {
+	return l.channelID
+}
+
+// Height returns the number of blocks of the ledger
+func (l *Ledger) Height() uint64 {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return uint64(len(l.blocks))
+}
+
+// Block returns the block with the given number, or nil if the ledger doesn't have the block yet
+func (l *Ledger) Block(number uint64) *cb.Block {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if number >= uint64(len(l.blocks)) {
+		return nil
+	}
+	return l.blocks[number]
+}
+
+// AddBlock cuts a block that contains the given envelopes with the given validation codes
+// (one per envelope) and appends it to the ledger
+func (l *Ledger) AddBlock(envelopes []*cb.Envelope, codes []pb.TxValidationCode) (*cb.Block, error) {
+	if len(codes) != len(envelopes) {
+		return nil, errors.New("expecting one validation code per envelope")
+	}
+
+	data := &cb.BlockData{}
+	for _, env := range envelopes {
+		envBytes, err := proto.Marshal(env)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal of envelope failed")
+		}
+		data.Data = append(data.Data, envBytes)
+	}
+
+	txFilter := make([]byte, len(codes))
+	for i, code := range codes {
+		txFilter[i] = byte(code)
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	block := &cb.Block{
+		Header: &cb.BlockHeader{
+			Number:       uint64(len(l.blocks)),
+			PreviousHash: l.lastHash(),
+			DataHash:     hash(data),
+		},
+		Data:     data,
+		Metadata: &cb.BlockMetadata{Metadata: make([][]byte, len(cb.BlockMetadataIndex_name))},
+	}
+	block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER] = txFilter
+
+	l.append(block)
+	return block, nil
+}
+
+// EmitBlock appends the given block to the ledger as is, e.g. to deliver a config block.
+// The block number must be the height of the ledger.
+func (l *Ledger) EmitBlock(block *cb.Block) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if block.GetHeader().GetNumber() != uint64(len(l.blocks)) {
+		return errors.Errorf("expecting block number %d but got %d", len(l.blocks), block.GetHeader().GetNumber())
+	}
+	l.append(block)
+	return nil
+}
+
+func (l *Ledger) append(block *cb.Block) {
+	l.blocks = append(l.blocks, block)
+	close(l.added)
+	l.added = make(chan struct{})
+}
+
+func (l *Ledger) lastHash() []byte {
+	if len(l.blocks) == 0 {
+		return nil
+	}
+	return hash(l.blocks[len(l.blocks)-1].Header)
+}
+
+// waitForBlock returns the block with the given number, waiting until it is added
+// to the ledger or until the context is done
+func (l *Ledger) waitForBlock(ctx reqContext.Context, number uint64) (*cb.Block, error) {
+	for {
+		l.mutex.RLock()
+		if number < uint64(len(l.blocks)) {
+			block := l.blocks[number]
+			l.mutex.RUnlock()
+			return block, nil
+		}
+		added := l.added
+		l.mutex.RUnlock()
+
+		select {
+		case <-added:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func hash(msg proto.Message) []byte {
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	digest := sha256.Sum256(msgBytes)
+	return digest[:]
+}
+
+// toFilteredBlock returns the filtered block of the given block
+func toFilteredBlock(channelID string, block *cb.Block) *pb.FilteredBlock {
+	fb := &pb.FilteredBlock{
+		ChannelId: channelID,
+		Number:    block.GetHeader().GetNumber(),
+	}
+
+	var txFilter []byte
+	if metadata := block.GetMetadata().GetMetadata(); len(metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txFilter = metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	}
+
+	for i, data := range block.GetData().GetData() {
+		env, err := protos_utils.GetEnvelopeFromBlock(data)
+		if err != nil {
+			continue
+		}
+		payload, err := protos_utils.GetPayload(env)
+		if err != nil {
+			continue
+		}
+		chHeader, err := protos_utils.UnmarshalChannelHeader(payload.GetHeader().GetChannelHeader())
+		if err != nil {
+			continue
+		}
+
+		code := pb.TxValidationCode_VALID
+		if i < len(txFilter) {
+			code = pb.TxValidationCode(txFilter[i])
+		}
+		fb.FilteredTransactions = append(fb.FilteredTransactions, &pb.FilteredTransaction{
+			Txid:             chHeader.TxId,
+			Type:             cb.HeaderType(chHeader.Type),
+			TxValidationCode: code,
+		})
+	}
+	return fb
+}
@@ -0,0 +1,190 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mocknetwork provides an in-process mock Fabric network for unit tests. The network is
+// made of peers, which serve the endorser and deliver services, and an orderer, which serves the
+// broadcast and deliver services, all listening on local GRPC ports without TLS. The peers simulate
+// chaincode invocations with scriptable handlers, the orderer cuts a block for every transaction
+// that is broadcast and the blocks are delivered by the peers and the orderer, so that applications
+// can test complete transaction flows without a Docker-based network.
+//
+//  Basic Flow:
+//  1) Create and start the network with its peers and channels
+//  2) Set the chaincode handlers of the peers
+//  3) Point the SDK configuration at the URLs of the peers and the orderer
+//  4) Stop the network at the end of the test
+//
+//  network := mocknetwork.New(mocknetwork.WithPeers("peer0", "peer1"), mocknetwork.WithChannels("mychannel"))
+//  if err := network.Start(); err != nil {
+//      t.Fatal(err)
+//  }
+//  defer network.Stop()
+//
+//  for _, peer := range network.Peers() {
+//      peer.HandleChaincode("mycc", func(inv *mocknetwork.Invocation) (*mocknetwork.Response, error) {
+//          return &mocknetwork.Response{Payload: []byte("value")}, nil
+//      })
+//  }
+package mocknetwork
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+)
+
+var logger = logging.NewLogger("fabsdk/mocknetwork")
+
+// Network is an in-process mock Fabric network
+type Network struct {
+	opts    options
+	mutex   sync.RWMutex
+	ledgers map[string]*Ledger
+	peers   []*Peer
+	orderer *Orderer
+	started bool
+}
+
+// Opt is a network option
+type Opt func(o *options)
+
+// WithPeers sets the names of the peers of the network (a single peer named "peer0" by default)
+func WithPeers(names ...string) Opt {
+	return func(o *options) {
+		o.peers = names
+	}
+}
+
+// WithChannels sets the channels of the network. Channels may also be added once the network is started.
+func WithChannels(channelIDs ...string) Opt {
+	return func(o *options) {
+		o.channels = append(o.channels, channelIDs...)
+	}
+}
+
+// WithAddress sets the host on which the peers and the orderer listen (127.0.0.1 by default).
+// Each of them listens on a random port.
+func WithAddress(host string) Opt {
+	return func(o *options) {
+		o.host = host
+	}
+}
+
+type options struct {
+	peers    []string
+	channels []string
+	host     string
+}
+
+// New returns a new mock network. The network must be started before it's used.
+func New(opts ...Opt) *Network {
+	o := options{
+		peers: []string{"peer0"},
+		host:  "127.0.0.1",
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	n := &Network{
+		opts:    o,
+		ledgers: make(map[string]*Ledger),
+	}
+	for _, name := range o.peers {
+		n.peers = append(n.peers, newPeer(name, n))
+	}
+	n.orderer = newOrderer("orderer", n)
+	for _, channelID := range o.channels {
+		n.AddChannel(channelID)
+	}
+	return n
+}
+
+// Start starts the peers and the orderer
+func (n *Network) Start() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.started {
+		return errors.New("network is already started")
+	}
+
+	address := n.opts.host + ":0"
+	for _, p := range n.peers {
+		if err := p.start(address); err != nil {
+			n.stop()
+			return err
+		}
+	}
+	if err := n.orderer.start(address); err != nil {
+		n.stop()
+		return err
+	}
+
+	n.started = true
+	return nil
+}
+
+// Stop stops the peers and the orderer. The streams that are open are closed.
+func (n *Network) Stop() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.stop()
+	n.started = false
+}
+
+func (n *Network) stop() {
+	for _, p := range n.peers {
+		p.stop()
+	}
+	n.orderer.stop()
+}
+
+// AddChannel adds a channel with an empty ledger and returns the ledger. The ledger of the channel
+// is returned if the channel already exists.
+func (n *Network) AddChannel(channelID string) *Ledger {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	ledger, ok := n.ledgers[channelID]
+	if !ok {
+		ledger = newLedger(channelID)
+		n.ledgers[channelID] = ledger
+	}
+	return ledger
+}
+
+// Ledger returns the ledger of the given channel
+func (n *Network) Ledger(channelID string) (*Ledger, bool) {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	ledger, ok := n.ledgers[channelID]
+	return ledger, ok
+}
+
+// Peers returns the peers of the network
+func (n *Network) Peers() []*Peer {
+	return n.peers
+}
+
+// Peer returns the peer with the given name
+func (n *Network) Peer(name string) (*Peer, bool) {
+	for _, p := range n.peers {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// Orderer returns the orderer of the network
+func (n *Network) Orderer() *Orderer {
+	return n.orderer
+}
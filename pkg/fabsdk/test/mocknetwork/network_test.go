@@ -0,0 +1,184 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+const channelID = "mychannel"
+
+func TestTransactionFlow(t *testing.T) {
+	network := New(WithPeers("peer0", "peer1"), WithChannels(channelID))
+	require.NoError(t, network.Start())
+	defer network.Stop()
+
+	for _, p := range network.Peers() {
+		p.HandleChaincode("mycc", func(inv *Invocation) (*Response, error) {
+			assert.Equal(t, channelID, inv.ChannelID)
+			assert.Equal(t, "move", inv.Fcn)
+			assert.Equal(t, [][]byte{[]byte("a")}, inv.Args)
+			return &Response{Payload: []byte("moved")}, nil
+		})
+	}
+	network.Orderer().SetValidator(func(channelID, txID string, env *cb.Envelope) pb.TxValidationCode {
+		if txID == "tx2" {
+			return pb.TxValidationCode_MVCC_READ_CONFLICT
+		}
+		return pb.TxValidationCode_VALID
+	})
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 10*time.Second)
+	defer cancel()
+
+	// Listen for the filtered blocks of the peer
+	peerConn := dial(t, network.Peers()[0].URL())
+	defer peerConn.Close()
+	deliver, err := pb.NewDeliverClient(peerConn).DeliverFiltered(ctx)
+	require.NoError(t, err)
+	require.NoError(t, deliver.Send(seekEnvelope(t, &ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}})))
+
+	// Endorse
+	for _, p := range network.Peers() {
+		conn := dial(t, p.URL())
+		resp, err := pb.NewEndorserClient(conn).ProcessProposal(ctx, signedProposal(t, "tx1", "mycc"))
+		conn.Close()
+		require.NoError(t, err)
+		assert.Equal(t, int32(200), resp.Response.Status)
+		assert.Equal(t, []byte("moved"), resp.Response.Payload)
+		assert.Equal(t, []byte(p.Name()), resp.Endorsement.Endorser)
+	}
+
+	conn := dial(t, network.Peers()[0].URL())
+	_, err = pb.NewEndorserClient(conn).ProcessProposal(ctx, signedProposal(t, "tx1", "othercc"))
+	conn.Close()
+	assert.Error(t, err, "expecting an error for a chaincode without a handler")
+
+	// Order
+	ordererConn := dial(t, network.Orderer().URL())
+	defer ordererConn.Close()
+	broadcast, err := ab.NewAtomicBroadcastClient(ordererConn).Broadcast(ctx)
+	require.NoError(t, err)
+	for _, txID := range []string{"tx1", "tx2"} {
+		require.NoError(t, broadcast.Send(txEnvelope(t, txID)))
+		resp, err := broadcast.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, cb.Status_SUCCESS, resp.Status)
+	}
+
+	// Commit
+	for i, expected := range []pb.TxValidationCode{pb.TxValidationCode_VALID, pb.TxValidationCode_MVCC_READ_CONFLICT} {
+		resp, err := deliver.Recv()
+		require.NoError(t, err)
+		fb := resp.GetFilteredBlock()
+		require.NotNil(t, fb)
+		assert.Equal(t, uint64(i), fb.Number)
+		require.Len(t, fb.FilteredTransactions, 1)
+		assert.Equal(t, expected, fb.FilteredTransactions[0].TxValidationCode)
+	}
+
+	ledger, ok := network.Ledger(channelID)
+	require.True(t, ok)
+	assert.Equal(t, uint64(2), ledger.Height())
+	assert.Equal(t, hash(ledger.Block(0).Header), ledger.Block(1).Header.PreviousHash)
+}
+
+func TestOrdererDeliver(t *testing.T) {
+	network := New(WithChannels(channelID))
+	require.NoError(t, network.Start())
+	defer network.Stop()
+
+	ledger, _ := network.Ledger(channelID)
+	_, err := ledger.AddBlock([]*cb.Envelope{txEnvelope(t, "tx1")}, []pb.TxValidationCode{pb.TxValidationCode_VALID})
+	require.NoError(t, err)
+	assert.Error(t, ledger.EmitBlock(&cb.Block{Header: &cb.BlockHeader{Number: 5}}))
+	require.NoError(t, ledger.EmitBlock(&cb.Block{Header: &cb.BlockHeader{Number: 1}}))
+
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 10*time.Second)
+	defer cancel()
+
+	conn := dial(t, network.Orderer().URL())
+	defer conn.Close()
+	deliver, err := ab.NewAtomicBroadcastClient(conn).Deliver(ctx)
+	require.NoError(t, err)
+
+	// The newest block
+	newest := &ab.SeekPosition{Type: &ab.SeekPosition_Newest{Newest: &ab.SeekNewest{}}}
+	require.NoError(t, deliver.Send(seekEnvelope(t, newest, newest)))
+	resp, err := deliver.Recv()
+	require.NoError(t, err)
+	require.NotNil(t, resp.GetBlock())
+	assert.Equal(t, uint64(1), resp.GetBlock().Header.Number)
+	resp, err = deliver.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, cb.Status_SUCCESS, resp.GetStatus())
+
+	// Unknown channel
+	require.NoError(t, deliver.Send(seekEnvelopeForChannel(t, "unknown", newest, newest)))
+	resp, err = deliver.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, cb.Status_NOT_FOUND, resp.GetStatus())
+}
+
+func dial(t *testing.T, url string) *grpc.ClientConn {
+	conn, err := grpc.Dial(url, grpc.WithInsecure())
+	require.NoError(t, err)
+	return conn
+}
+
+func signedProposal(t *testing.T, txID, ccID string) *pb.SignedProposal {
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+		Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: ccID},
+		Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("move"), []byte("a")}}}}
+	proposal, _, err := protos_utils.CreateChaincodeProposalWithTxIDNonceAndTransient(txID, cb.HeaderType_ENDORSER_TRANSACTION, channelID, cis, []byte("nonce"), []byte("creator"), nil)
+	require.NoError(t, err)
+	proposalBytes, err := proto.Marshal(proposal)
+	require.NoError(t, err)
+	return &pb.SignedProposal{ProposalBytes: proposalBytes, Signature: []byte("signature")}
+}
+
+func envelope(t *testing.T, channel, txID string, headerType cb.HeaderType, data []byte) *cb.Envelope {
+	chHeader := protos_utils.MakeChannelHeader(headerType, 0, channel, 0)
+	chHeader.TxId = txID
+	payload := &cb.Payload{
+		Header: protos_utils.MakePayloadHeader(chHeader, &cb.SignatureHeader{Creator: []byte("creator")}),
+		Data:   data,
+	}
+	payloadBytes, err := proto.Marshal(payload)
+	require.NoError(t, err)
+	return &cb.Envelope{Payload: payloadBytes, Signature: []byte("signature")}
+}
+
+func txEnvelope(t *testing.T, txID string) *cb.Envelope {
+	return envelope(t, channelID, txID, cb.HeaderType_ENDORSER_TRANSACTION, []byte("transaction"))
+}
+
+func seekEnvelope(t *testing.T, start *ab.SeekPosition, stop ...*ab.SeekPosition) *cb.Envelope {
+	return seekEnvelopeForChannel(t, channelID, start, stop...)
+}
+
+func seekEnvelopeForChannel(t *testing.T, channel string, start *ab.SeekPosition, stop ...*ab.SeekPosition) *cb.Envelope {
+	seekInfo := &ab.SeekInfo{Start: start, Behavior: ab.SeekInfo_BLOCK_UNTIL_READY}
+	if len(stop) > 0 {
+		seekInfo.Stop = stop[0]
+	}
+	data, err := proto.Marshal(seekInfo)
+	require.NoError(t, err)
+	return envelope(t, channel, "", cb.HeaderType_DELIVER_SEEK_INFO, data)
+}
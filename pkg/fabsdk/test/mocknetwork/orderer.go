@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	"io"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	ab "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/orderer"
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Validator returns the validation code of a transaction that is committed
+type Validator func(channelID, txID string, env *cb.Envelope) pb.TxValidationCode
+
+// Orderer is an in-process orderer that serves the broadcast and deliver services. Every
+// transaction that is broadcast is cut into its own block.
+type Orderer struct {
+	name    string
+	network *Network
+	server  *grpc.Server
+	url     string
+
+	mutex          sync.RWMutex
+	validator      Validator
+	broadcastError cb.Status
+}
+
+func newOrderer(name string, network *Network) *Orderer {
+	return &Orderer{name: name, network: network}
+}
+
+// Name returns the name of the orderer
+func (o *Orderer) Name() string {
+	return o.name
+}
+
+// URL returns the address (host:port) on which the orderer listens
+func (o *Orderer) URL() string {
+	return o.url
+}
+
+// SetValidator sets the function that determines the validation codes of the transactions.
+// All of the transactions are valid by default.
+func (o *Orderer) SetValidator(validator Validator) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.validator = validator
+}
+
+// SetBroadcastStatus sets the status returned to the broadcasts, e.g. SERVICE_UNAVAILABLE. The
+// transactions aren't ordered unless the status is SUCCESS (the default).
+func (o *Orderer) SetBroadcastStatus(status cb.Status) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.broadcastError = status
+}
+
+func (o *Orderer) start(address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen for orderer [%s]", o.name)
+	}
+	o.url = lis.Addr().String()
+	o.server = grpc.NewServer()
+	ab.RegisterAtomicBroadcastServer(o.server, &ordererServer{orderer: o})
+
+	go func() {
+		if err := o.server.Serve(lis); err != nil {
+			logger.Debugf("orderer [%s] stopped serving: %s", o.name, err)
+		}
+	}()
+	logger.Debugf("orderer [%s] started on %s", o.name, o.url)
+	return nil
+}
+
+func (o *Orderer) stop() {
+	if o.server != nil {
+		o.server.Stop()
+	}
+}
+
+// order cuts a block with the given transaction and returns the status of the broadcast
+func (o *Orderer) order(env *cb.Envelope) (cb.Status, string) {
+	o.mutex.RLock()
+	validator := o.validator
+	status := o.broadcastError
+	o.mutex.RUnlock()
+
+	if status != cb.Status_UNKNOWN && status != cb.Status_SUCCESS {
+		return status, "broadcast rejected by mock orderer"
+	}
+
+	payload, err := protos_utils.GetPayload(env)
+	if err != nil || payload.Header == nil {
+		return cb.Status_BAD_REQUEST, "invalid envelope"
+	}
+	chHeader, err := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return cb.Status_BAD_REQUEST, "invalid channel header"
+	}
+
+	ledger, ok := o.network.Ledger(chHeader.ChannelId)
+	if !ok {
+		return cb.Status_NOT_FOUND, "channel not found"
+	}
+
+	code := pb.TxValidationCode_VALID
+	if validator != nil {
+		code = validator(chHeader.ChannelId, chHeader.TxId, env)
+	}
+	if _, err := ledger.AddBlock([]*cb.Envelope{env}, []pb.TxValidationCode{code}); err != nil {
+		return cb.Status_INTERNAL_SERVER_ERROR, err.Error()
+	}
+	return cb.Status_SUCCESS, ""
+}
+
+type ordererServer struct {
+	orderer *Orderer
+}
+
+// Broadcast orders the transactions that are received
+func (s *ordererServer) Broadcast(srv ab.AtomicBroadcast_BroadcastServer) error {
+	for {
+		env, err := srv.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		status, info := s.orderer.order(env)
+		if err := srv.Send(&ab.BroadcastResponse{Status: status, Info: info}); err != nil {
+			return err
+		}
+	}
+}
+
+// Deliver delivers the blocks of the channel
+func (s *ordererServer) Deliver(srv ab.AtomicBroadcast_DeliverServer) error {
+	for {
+		env, err := srv.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		status := s.orderer.network.deliverBlocks(srv.Context(), env, func(channelID string, block *cb.Block) error {
+			return srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Block{Block: block}})
+		})
+		if err := srv.Send(&ab.DeliverResponse{Type: &ab.DeliverResponse_Status{Status: status}}); err != nil {
+			return err
+		}
+	}
+}
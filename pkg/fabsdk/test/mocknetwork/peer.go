@@ -0,0 +1,300 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocknetwork
+
+import (
+	"crypto/sha256"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	cb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// Invocation contains the parameters of a chaincode invocation received by a peer
+type Invocation struct {
+	ChannelID    string
+	TxID         string
+	ChaincodeID  string
+	Fcn          string
+	Args         [][]byte
+	TransientMap map[string][]byte
+	Creator      []byte
+}
+
+// Response is the response of a chaincode invocation
+type Response struct {
+	// Status is the status of the chaincode response (200 if not set)
+	Status int32
+	// Message is the message of the chaincode response
+	Message string
+	// Payload is the payload of the chaincode response
+	Payload []byte
+	// Results are the marshalled read/write sets of the invocation
+	Results []byte
+	// Event is the chaincode event of the invocation, if any
+	Event *pb.ChaincodeEvent
+}
+
+// ChaincodeHandler simulates the invocation of a chaincode. An error fails the endorsement.
+type ChaincodeHandler func(inv *Invocation) (*Response, error)
+
+// EndorsementSigner signs the proposal responses of a peer. It returns the serialized identity
+// of the endorser and the signature of the given message.
+type EndorsementSigner func(msg []byte) (endorser []byte, signature []byte, err error)
+
+// Peer is an in-process peer that serves the endorser and the deliver services
+type Peer struct {
+	name    string
+	network *Network
+	server  *grpc.Server
+	url     string
+
+	mutex     sync.RWMutex
+	handlers  map[string]ChaincodeHandler
+	signer    EndorsementSigner
+	endorseFn func(proposal *pb.SignedProposal) (*pb.ProposalResponse, error)
+}
+
+func newPeer(name string, network *Network) *Peer {
+	return &Peer{
+		name:     name,
+		network:  network,
+		handlers: make(map[string]ChaincodeHandler),
+		signer:   defaultSigner(name),
+	}
+}
+
+func defaultSigner(name string) EndorsementSigner {
+	return func(msg []byte) ([]byte, []byte, error) {
+		digest := sha256.Sum256(msg)
+		return []byte(name), digest[:], nil
+	}
+}
+
+// Name returns the name of the peer
+func (p *Peer) Name() string {
+	return p.name
+}
+
+// URL returns the address (host:port) on which the peer listens
+func (p *Peer) URL() string {
+	return p.url
+}
+
+// HandleChaincode sets the handler that simulates the invocations of the given chaincode.
+// Proposals for chaincodes without a handler are rejected.
+func (p *Peer) HandleChaincode(ccID string, handler ChaincodeHandler) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.handlers[ccID] = handler
+}
+
+// SetSigner sets the function that signs the proposal responses. By default the endorser is the
+// name of the peer and the signature is the SHA-256 digest of the signed message, so the SDK's
+// endorsement signature validation must be satisfied by a signer if it's enabled.
+func (p *Peer) SetSigner(signer EndorsementSigner) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.signer = signer
+}
+
+// SetEndorseFunc overrides the processing of the proposals, e.g. to return a GRPC error or a
+// malformed response. Nil restores the default processing.
+func (p *Peer) SetEndorseFunc(endorse func(proposal *pb.SignedProposal) (*pb.ProposalResponse, error)) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.endorseFn = endorse
+}
+
+func (p *Peer) start(address string) error {
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen for peer [%s]", p.name)
+	}
+	p.url = lis.Addr().String()
+	p.server = grpc.NewServer()
+	pb.RegisterEndorserServer(p.server, &endorserServer{peer: p})
+	pb.RegisterDeliverServer(p.server, &peerDeliverServer{peer: p})
+
+	go func() {
+		if err := p.server.Serve(lis); err != nil {
+			logger.Debugf("peer [%s] stopped serving: %s", p.name, err)
+		}
+	}()
+	logger.Debugf("peer [%s] started on %s", p.name, p.url)
+	return nil
+}
+
+func (p *Peer) stop() {
+	if p.server != nil {
+		p.server.Stop()
+	}
+}
+
+type endorserServer struct {
+	peer *Peer
+}
+
+// ProcessProposal simulates the proposal with the handler of the chaincode and returns the signed response
+func (s *endorserServer) ProcessProposal(ctx context.Context, signedProposal *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	s.peer.mutex.RLock()
+	endorse := s.peer.endorseFn
+	s.peer.mutex.RUnlock()
+
+	if endorse != nil {
+		return endorse(signedProposal)
+	}
+	return s.peer.endorse(signedProposal)
+}
+
+func (p *Peer) endorse(signedProposal *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	proposal := &pb.Proposal{}
+	if err := proto.Unmarshal(signedProposal.ProposalBytes, proposal); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of proposal failed")
+	}
+	inv, err := invocation(proposal)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.network.Ledger(inv.ChannelID); !ok && inv.ChannelID != "" {
+		return nil, errors.Errorf("channel [%s] not found", inv.ChannelID)
+	}
+
+	p.mutex.RLock()
+	handler, ok := p.handlers[inv.ChaincodeID]
+	signer := p.signer
+	p.mutex.RUnlock()
+
+	if !ok {
+		return nil, errors.Errorf("chaincode [%s] is not installed on peer [%s]", inv.ChaincodeID, p.name)
+	}
+
+	resp, err := handler(inv)
+	if err != nil {
+		return nil, err
+	}
+	return newProposalResponse(proposal, inv.ChaincodeID, resp, signer)
+}
+
+// invocation decodes the chaincode invocation of the proposal
+func invocation(proposal *pb.Proposal) (*Invocation, error) {
+	header, err := protos_utils.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proposal header")
+	}
+	chHeader, err := protos_utils.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid channel header")
+	}
+	sigHeader, err := protos_utils.GetSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature header")
+	}
+	ccPayload, err := protos_utils.GetChaincodeProposalPayload(proposal.Payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid chaincode proposal payload")
+	}
+	cis := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(ccPayload.Input, cis); err != nil {
+		return nil, errors.Wrap(err, "invalid chaincode invocation spec")
+	}
+
+	inv := &Invocation{
+		ChannelID:    chHeader.ChannelId,
+		TxID:         chHeader.TxId,
+		ChaincodeID:  cis.GetChaincodeSpec().GetChaincodeId().GetName(),
+		TransientMap: ccPayload.TransientMap,
+		Creator:      sigHeader.Creator,
+	}
+	if args := cis.GetChaincodeSpec().GetInput().GetArgs(); len(args) > 0 {
+		inv.Fcn = string(args[0])
+		inv.Args = args[1:]
+	}
+	return inv, nil
+}
+
+func newProposalResponse(proposal *pb.Proposal, ccID string, resp *Response, signer EndorsementSigner) (*pb.ProposalResponse, error) {
+	status := resp.Status
+	if status == 0 {
+		status = 200
+	}
+	response := &pb.Response{Status: status, Message: resp.Message, Payload: resp.Payload}
+
+	var eventBytes []byte
+	if resp.Event != nil {
+		var err error
+		if eventBytes, err = proto.Marshal(resp.Event); err != nil {
+			return nil, errors.Wrap(err, "marshal of chaincode event failed")
+		}
+	}
+
+	proposalHash := sha256.Sum256(append(append([]byte(nil), proposal.Header...), proposal.Payload...))
+	payload, err := protos_utils.GetBytesProposalResponsePayload(proposalHash[:], response, resp.Results, eventBytes, &pb.ChaincodeID{Name: ccID})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of proposal response payload failed")
+	}
+
+	endorser, signature, err := signer(payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing of proposal response failed")
+	}
+
+	return &pb.ProposalResponse{
+		Version:     1,
+		Response:    response,
+		Payload:     payload,
+		Endorsement: &pb.Endorsement{Endorser: endorser, Signature: signature},
+	}, nil
+}
+
+type peerDeliverServer struct {
+	peer *Peer
+}
+
+// Deliver delivers the blocks of the channel
+func (s *peerDeliverServer) Deliver(srv pb.Deliver_DeliverServer) error {
+	return s.deliver(srv, func(channelID string, block *cb.Block) error {
+		return srv.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_Block{Block: block}})
+	}, srv.Send)
+}
+
+// DeliverFiltered delivers the filtered blocks of the channel
+func (s *peerDeliverServer) DeliverFiltered(srv pb.Deliver_DeliverFilteredServer) error {
+	return s.deliver(srv, func(channelID string, block *cb.Block) error {
+		return srv.Send(&pb.DeliverResponse{Type: &pb.DeliverResponse_FilteredBlock{FilteredBlock: toFilteredBlock(channelID, block)}})
+	}, srv.Send)
+}
+
+type envelopeReceiver interface {
+	Recv() (*cb.Envelope, error)
+	Context() context.Context
+}
+
+func (s *peerDeliverServer) deliver(srv envelopeReceiver, sendBlock func(string, *cb.Block) error, send func(*pb.DeliverResponse) error) error {
+	for {
+		env, err := srv.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		status := s.peer.network.deliverBlocks(srv.Context(), env, sendBlock)
+		if err := send(&pb.DeliverResponse{Type: &pb.DeliverResponse_Status{Status: status}}); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"bytes"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/pkg/errors"
+)
+
+// tlsClientCertSetter is implemented by the endpoint configurations whose
+// client certificates for mutual TLS can be replaced at runtime
+type tlsClientCertSetter interface {
+	SetTLSClientCerts(certs []tls.Certificate)
+}
+
+// connectionRefresher is implemented by the infra providers that can replace their connections
+type connectionRefresher interface {
+	RefreshConnections()
+}
+
+// certRotation tracks the client certificate for mutual TLS and refreshes the connections
+// when it changes
+type certRotation struct {
+	sdk      *FabricSDK
+	lock     sync.Mutex
+	hash     []byte
+	override []tls.Certificate
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+// UpdateTLSClientCert replaces the client's TLS certificate for mutual TLS, e.g. after it has been
+// renewed. Connections that are established after the update use the new certificate; existing
+// connections are closed as soon as they're no longer in use. The certificate remains in effect
+// when the configuration is reloaded.
+func (sdk *FabricSDK) UpdateTLSClientCert(cert tls.Certificate) error {
+	if len(cert.Certificate) == 0 {
+		return errors.New("TLS client certificate is empty")
+	}
+	return sdk.certRotation.update([]tls.Certificate{cert})
+}
+
+func (r *certRotation) update(certs []tls.Certificate) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	setter, ok := r.sdk.provider.EndpointConfig().(tlsClientCertSetter)
+	if !ok {
+		return errors.New("updating the TLS client certificate is not supported by the endpoint configuration")
+	}
+
+	setter.SetTLSClientCerts(certs)
+	r.override = certs
+	r.checkLocked()
+	return nil
+}
+
+// reapply sets the certificates that were provided programmatically on the current endpoint configuration
+func (r *certRotation) reapply() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.override == nil {
+		return
+	}
+	if setter, ok := r.sdk.provider.EndpointConfig().(tlsClientCertSetter); ok {
+		setter.SetTLSClientCerts(r.override)
+	}
+}
+
+func (r *certRotation) start(interval time.Duration) {
+	r.lock.Lock()
+	r.hash = comm.TLSCertHash(r.sdk.provider.EndpointConfig())
+	r.done = make(chan struct{})
+	r.lock.Unlock()
+
+	r.wg.Add(1)
+	go r.watch(interval, r.done)
+}
+
+func (r *certRotation) stop() {
+	r.lock.Lock()
+	done := r.done
+	r.done = nil
+	r.lock.Unlock()
+
+	if done != nil {
+		close(done)
+		r.wg.Wait()
+	}
+}
+
+func (r *certRotation) watch(interval time.Duration, done chan struct{}) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			r.lock.Lock()
+			r.checkLocked()
+			r.lock.Unlock()
+		}
+	}
+}
+
+// checkLocked refreshes the connections if the client certificate has changed
+// since the last check. It must be called while holding the lock.
+func (r *certRotation) checkLocked() {
+	hash := comm.TLSCertHash(r.sdk.provider.EndpointConfig())
+	if bytes.Equal(hash, r.hash) {
+		return
+	}
+	r.hash = hash
+
+	logger.Info("TLS client certificate has changed - refreshing connections")
+	if refresher, ok := r.sdk.provider.InfraProvider().(connectionRefresher); ok {
+		refresher.RefreshConnections()
+	}
+}
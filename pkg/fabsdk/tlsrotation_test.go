@@ -0,0 +1,48 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTLSClientCert(t *testing.T) {
+	_, err := New(config.FromFile(sdkConfigFile), WithTLSCertRotation(0))
+	assert.Error(t, err, "expecting error for invalid rotation interval")
+
+	sdk, err := New(config.FromFile(sdkConfigFile), WithTLSCertRotation(time.Hour))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	assert.Error(t, sdk.UpdateTLSClientCert(tls.Certificate{}), "expecting error for empty certificate")
+
+	hash := comm.TLSCertHash(sdk.provider.EndpointConfig())
+
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("renewed certificate")}}
+	require.NoError(t, sdk.UpdateTLSClientCert(cert))
+
+	certs, err := sdk.provider.EndpointConfig().TLSClientCerts()
+	require.NoError(t, err)
+	require.Len(t, certs, 1)
+	assert.Equal(t, cert.Certificate, certs[0].Certificate)
+	assert.NotEqual(t, hash, comm.TLSCertHash(sdk.provider.EndpointConfig()))
+	assert.Equal(t, comm.TLSCertHash(sdk.provider.EndpointConfig()), sdk.certRotation.hash)
+
+	configBackend, err := config.FromFile(sdkConfigFile)()
+	require.NoError(t, err)
+	require.NoError(t, sdk.Reload(configBackend))
+	certs, err = sdk.provider.EndpointConfig().TLSClientCerts()
+	require.NoError(t, err)
+	assert.Equal(t, cert.Certificate, certs[0].Certificate, "expecting certificate to remain in effect after reload")
+}
@@ -0,0 +1,207 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/readonly"
+	"github.com/pkg/errors"
+)
+
+// Checkpoint holds the position from which event listening resumes
+type Checkpoint interface {
+	// BlockNumber returns the number of the next block to be processed
+	BlockNumber() uint64
+	// TransactionID returns the ID of the last transaction processed in the current
+	// block, or an empty string if no transaction in the block has been processed
+	TransactionID() string
+}
+
+// Checkpointer records the progress of an event listener so that listening can resume
+// after the last processed event. The semantics match the checkpointers of the Fabric
+// Gateway SDKs.
+type Checkpointer interface {
+	Checkpoint
+	// CheckpointBlock records that the given block has been fully processed
+	CheckpointBlock(blockNumber uint64) error
+	// CheckpointTransaction records that the given transaction in the given block has been processed
+	CheckpointTransaction(blockNumber uint64, txID string) error
+	// CheckpointChaincodeEvent records that the transaction of the given chaincode event has been processed
+	CheckpointChaincodeEvent(event *fab.CCEvent) error
+}
+
+// InMemoryCheckpointer is a Checkpointer that holds the checkpoint in memory only
+type InMemoryCheckpointer struct {
+	mutex       sync.RWMutex
+	blockNumber uint64
+	txID        string
+}
+
+// NewInMemoryCheckpointer creates a checkpointer that holds the checkpoint in memory only
+func NewInMemoryCheckpointer() *InMemoryCheckpointer {
+	return &InMemoryCheckpointer{}
+}
+
+// BlockNumber returns the number of the next block to be processed
+func (c *InMemoryCheckpointer) BlockNumber() uint64 {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.blockNumber
+}
+
+// TransactionID returns the ID of the last transaction processed in the current block
+func (c *InMemoryCheckpointer) TransactionID() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.txID
+}
+
+// CheckpointBlock records that the given block has been fully processed
+func (c *InMemoryCheckpointer) CheckpointBlock(blockNumber uint64) error {
+	c.set(blockNumber+1, "")
+	return nil
+}
+
+// CheckpointTransaction records that the given transaction in the given block has been processed
+func (c *InMemoryCheckpointer) CheckpointTransaction(blockNumber uint64, txID string) error {
+	c.set(blockNumber, txID)
+	return nil
+}
+
+// CheckpointChaincodeEvent records that the transaction of the given chaincode event has been processed
+func (c *InMemoryCheckpointer) CheckpointChaincodeEvent(event *fab.CCEvent) error {
+	return c.CheckpointTransaction(event.BlockNumber, event.TxID)
+}
+
+func (c *InMemoryCheckpointer) set(blockNumber uint64, txID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.blockNumber = blockNumber
+	c.txID = txID
+}
+
+// FileCheckpointer is a Checkpointer that persists the checkpoint to a file, so that
+// event listening can resume after the application is restarted. The file is replaced
+// atomically on each checkpoint. A read-only checkpointer (see WithReadOnlyCheckpoint)
+// never writes the file and checkpointing fails with readonly.ErrReadOnly.
+type FileCheckpointer struct {
+	InMemoryCheckpointer
+	path     string
+	readOnly bool
+}
+
+// FileCheckpointerOption describes a functional parameter for the NewFileCheckpointer function
+type FileCheckpointerOption func(*FileCheckpointer)
+
+// WithReadOnlyCheckpoint makes the checkpointer read-only, e.g. for the listeners of an SDK in
+// read-only mode (see fabsdk.WithReadOnly). The checkpoint is loaded from the file if it exists
+// but the file is never written.
+func WithReadOnlyCheckpoint() FileCheckpointerOption {
+	return func(c *FileCheckpointer) {
+		c.readOnly = true
+	}
+}
+
+type fileCheckpoint struct {
+	BlockNumber   uint64 `json:"blockNumber"`
+	TransactionID string `json:"transactionId"`
+}
+
+// NewFileCheckpointer creates a checkpointer that persists the checkpoint to the given file.
+// If the file exists then the checkpoint is loaded from it; otherwise it's created.
+func NewFileCheckpointer(path string, options ...FileCheckpointerOption) (*FileCheckpointer, error) {
+	c := &FileCheckpointer{path: path}
+	for _, option := range options {
+		option(c)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "failed to read checkpoint file [%s]", path)
+	}
+
+	if len(data) > 0 {
+		checkpoint := fileCheckpoint{}
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			return nil, errors.Wrapf(err, "invalid checkpoint file [%s]", path)
+		}
+		c.blockNumber = checkpoint.BlockNumber
+		c.txID = checkpoint.TransactionID
+		return c, nil
+	}
+
+	if c.readOnly {
+		// Start from the beginning without creating the file
+		return c, nil
+	}
+	if err := c.save(0, ""); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ReadOnly returns true if the checkpointer never writes the checkpoint file
+func (c *FileCheckpointer) ReadOnly() bool {
+	return c.readOnly
+}
+
+// CheckpointBlock records that the given block has been fully processed
+func (c *FileCheckpointer) CheckpointBlock(blockNumber uint64) error {
+	return c.checkpoint(blockNumber+1, "")
+}
+
+// CheckpointTransaction records that the given transaction in the given block has been processed
+func (c *FileCheckpointer) CheckpointTransaction(blockNumber uint64, txID string) error {
+	return c.checkpoint(blockNumber, txID)
+}
+
+// CheckpointChaincodeEvent records that the transaction of the given chaincode event has been processed
+func (c *FileCheckpointer) CheckpointChaincodeEvent(event *fab.CCEvent) error {
+	return c.checkpoint(event.BlockNumber, event.TxID)
+}
+
+func (c *FileCheckpointer) checkpoint(blockNumber uint64, txID string) error {
+	if err := readonly.Check(c, "checkpoint"); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.save(blockNumber, txID); err != nil {
+		return err
+	}
+	c.blockNumber = blockNumber
+	c.txID = txID
+	return nil
+}
+
+func (c *FileCheckpointer) save(blockNumber uint64, txID string) error {
+	data, err := json.Marshal(&fileCheckpoint{BlockNumber: blockNumber, TransactionID: txID})
+	if err != nil {
+		return errors.Wrap(err, "marshal of checkpoint failed")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return errors.Wrapf(err, "failed to create directory for checkpoint file [%s]", c.path)
+	}
+
+	tmpPath := c.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write checkpoint file [%s]", tmpPath)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		return errors.Wrapf(err, "failed to replace checkpoint file [%s]", c.path)
+	}
+	return nil
+}
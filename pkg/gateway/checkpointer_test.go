@@ -0,0 +1,85 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/readonly"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryCheckpointer(t *testing.T) {
+	c := NewInMemoryCheckpointer()
+	assert.Equal(t, uint64(0), c.BlockNumber())
+	assert.Equal(t, "", c.TransactionID())
+
+	require.NoError(t, c.CheckpointTransaction(5, "tx1"))
+	assert.Equal(t, uint64(5), c.BlockNumber())
+	assert.Equal(t, "tx1", c.TransactionID())
+
+	require.NoError(t, c.CheckpointBlock(5))
+	assert.Equal(t, uint64(6), c.BlockNumber())
+	assert.Equal(t, "", c.TransactionID())
+
+	require.NoError(t, c.CheckpointChaincodeEvent(&fab.CCEvent{BlockNumber: 7, TxID: "tx2"}))
+	assert.Equal(t, uint64(7), c.BlockNumber())
+	assert.Equal(t, "tx2", c.TransactionID())
+}
+
+func TestFileCheckpointer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpointer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "checkpoints", "mychannel.json")
+
+	c, err := NewFileCheckpointer(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), c.BlockNumber())
+
+	require.NoError(t, c.CheckpointChaincodeEvent(&fab.CCEvent{BlockNumber: 3, TxID: "tx1"}))
+
+	c, err = NewFileCheckpointer(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), c.BlockNumber())
+	assert.Equal(t, "tx1", c.TransactionID())
+
+	require.NoError(t, c.CheckpointBlock(3))
+
+	c, err = NewFileCheckpointer(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), c.BlockNumber())
+	assert.Equal(t, "", c.TransactionID())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("invalid"), 0600))
+	_, err = NewFileCheckpointer(path)
+	assert.Error(t, err, "expecting error for invalid checkpoint file")
+}
+
+func TestFileCheckpointerReadOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpointer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "mychannel.json")
+
+	c, err := NewFileCheckpointer(path, WithReadOnlyCheckpoint())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), c.BlockNumber())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "expecting checkpoint file not to be created in read-only mode")
+
+	err = c.CheckpointBlock(3)
+	assert.True(t, readonly.IsReadOnly(err), "expecting read-only error")
+	assert.Equal(t, uint64(0), c.BlockNumber())
+}
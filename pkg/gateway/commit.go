@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// CommitStatus is the outcome of committing a transaction
+type CommitStatus struct {
+	TransactionID string
+	BlockNumber   uint64
+	Code          pb.TxValidationCode
+}
+
+// Successful returns true if the transaction was committed as valid
+func (s *CommitStatus) Successful() bool {
+	return s.Code == pb.TxValidationCode_VALID
+}
+
+// Commit is a handle to a transaction that has been submitted to the orderer but
+// may not yet have been committed
+type Commit struct {
+	txID   string
+	done   chan struct{}
+	status *CommitStatus
+	err    error
+}
+
+// TransactionID returns the ID of the transaction
+func (c *Commit) TransactionID() string {
+	return c.txID
+}
+
+// Done returns a channel that is closed once the commit status is available. It may be
+// used in a select statement to poll for the commit status without blocking.
+func (c *Commit) Done() <-chan struct{} {
+	return c.done
+}
+
+// Status waits for the transaction to be committed and returns its commit status. An
+// error is returned if the commit status wasn't received within the gateway timeout;
+// a transaction that was committed as invalid is reported by the commit status.
+func (c *Commit) Status() (*CommitStatus, error) {
+	<-c.done
+	return c.status, c.err
+}
+
+func newCommit(txID string, eventService fab.EventService, reg fab.Registration, statusNotifier <-chan *fab.TxStatusEvent, timeout time.Duration) *Commit {
+	c := &Commit{
+		txID: txID,
+		done: make(chan struct{}),
+	}
+	go c.wait(eventService, reg, statusNotifier, timeout)
+	return c
+}
+
+func (c *Commit) wait(eventService fab.EventService, reg fab.Registration, statusNotifier <-chan *fab.TxStatusEvent, timeout time.Duration) {
+	defer close(c.done)
+	defer eventService.Unregister(reg)
+
+	select {
+	case txStatus, ok := <-statusNotifier:
+		if !ok {
+			c.err = errors.Errorf("event service closed before commit status of transaction [%s] was received", c.txID)
+			return
+		}
+		c.status = &CommitStatus{
+			TransactionID: txStatus.TxID,
+			BlockNumber:   txStatus.BlockNumber,
+			Code:          txStatus.TxValidationCode,
+		}
+	case <-time.After(timeout):
+		c.err = errors.Errorf("timed out waiting for commit status of transaction [%s]", c.txID)
+	}
+}
+
+// submitAsyncHandler sends the endorsed transaction to the orderer without waiting for
+// it to be committed. The commit status is delivered through the resulting Commit.
+type submitAsyncHandler struct {
+	timeout time.Duration
+	commit  *Commit
+}
+
+// Handle registers for the commit status of the transaction and sends it to the orderer
+func (h *submitAsyncHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	txID := string(requestContext.Response.TransactionID)
+
+	reg, statusNotifier, err := clientContext.EventService.RegisterTxStatusEvent(txID)
+	if err != nil {
+		requestContext.Error = errors.Wrap(err, "error registering for TxStatus event")
+		return
+	}
+
+	txnRequest := fab.TransactionRequest{
+		Proposal:          requestContext.Response.Proposal,
+		ProposalResponses: requestContext.Response.Responses,
+	}
+	tx, err := clientContext.Transactor.CreateTransaction(txnRequest)
+	if err != nil {
+		clientContext.EventService.Unregister(reg)
+		requestContext.Error = errors.WithMessage(err, "CreateTransaction failed")
+		return
+	}
+	if _, err := clientContext.Transactor.SendTransaction(tx); err != nil {
+		clientContext.EventService.Unregister(reg)
+		requestContext.Error = errors.WithMessage(err, "SendTransaction failed")
+		return
+	}
+
+	h.commit = newCommit(txID, clientContext.EventService, reg, statusNotifier, h.timeout)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitAsyncHandler(t *testing.T) {
+	eventService := mocks.NewMockEventService()
+	eventService.TxValidationCode = pb.TxValidationCode_MVCC_READ_CONFLICT
+
+	handler := &submitAsyncHandler{timeout: time.Second}
+	requestContext := &invoke.RequestContext{Response: invoke.Response{TransactionID: "tx1"}}
+	handler.Handle(requestContext, &invoke.ClientContext{EventService: eventService, Transactor: &mocks.MockTransactor{}})
+	require.NoError(t, requestContext.Error)
+	require.NotNil(t, handler.commit)
+	assert.Equal(t, "tx1", handler.commit.TransactionID())
+
+	status, err := handler.commit.Status()
+	require.NoError(t, err)
+	assert.Equal(t, "tx1", status.TransactionID)
+	assert.False(t, status.Successful())
+
+	select {
+	case <-handler.commit.Done():
+	default:
+		t.Fatal("expecting commit to be done")
+	}
+}
+
+func TestSubmitAsyncHandlerTimeout(t *testing.T) {
+	eventService := mocks.NewMockEventService()
+	eventService.Timeout = true
+
+	handler := &submitAsyncHandler{timeout: 10 * time.Millisecond}
+	requestContext := &invoke.RequestContext{Response: invoke.Response{TransactionID: "tx1"}}
+	handler.Handle(requestContext, &invoke.ClientContext{EventService: eventService, Transactor: &mocks.MockTransactor{}})
+	require.NoError(t, requestContext.Error)
+
+	_, err := handler.commit.Status()
+	assert.Error(t, err, "expecting timeout error")
+}
+
+func TestSubmitAsync(t *testing.T) {
+	client := &mockChannelClient{payload: []byte("result")}
+	contract := newTestNetwork(client).GetContract("mycc")
+
+	_, _, err := contract.SubmitAsync("move", "a", "b", "10")
+	assert.Error(t, err, "expecting error since the mock client doesn't invoke the handler")
+	assert.Equal(t, "move", client.execute.Fcn)
+
+	client.err = errors.New("endorsement failed")
+	_, _, err = contract.SubmitAsync("move")
+	assert.Error(t, err)
+}
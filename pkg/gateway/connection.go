@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"crypto/tls"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defcore"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/fabpvdr"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	grpcCredentials "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+)
+
+// ConnectionBuilder builds the GRPC dial options that are applied to all of the connections
+// (peers, orderers and event services) made by a gateway. The options are applied after the
+// options derived from the connection profile and therefore take precedence over them.
+type ConnectionBuilder struct {
+	dialOpts           []grpc.DialOption
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+}
+
+// NewConnectionBuilder returns a new connection builder
+func NewConnectionBuilder() *ConnectionBuilder {
+	return &ConnectionBuilder{}
+}
+
+// WithDialOptions adds custom GRPC dial options
+func (b *ConnectionBuilder) WithDialOptions(opts ...grpc.DialOption) *ConnectionBuilder {
+	b.dialOpts = append(b.dialOpts, opts...)
+	return b
+}
+
+// WithKeepalive overrides the keepalive settings of the connection profile
+func (b *ConnectionBuilder) WithKeepalive(params keepalive.ClientParameters) *ConnectionBuilder {
+	return b.WithDialOptions(grpc.WithKeepaliveParams(params))
+}
+
+// WithTLSConfig overrides the TLS configuration of the connection profile. The override only
+// applies to endpoints that are configured with TLS (grpcs://).
+func (b *ConnectionBuilder) WithTLSConfig(config *tls.Config) *ConnectionBuilder {
+	return b.WithDialOptions(grpc.WithTransportCredentials(grpcCredentials.NewTLS(config)))
+}
+
+// WithUnaryInterceptor adds an interceptor for unary calls (e.g. endorsements and broadcasts).
+// Interceptors are invoked in the order in which they're added.
+func (b *ConnectionBuilder) WithUnaryInterceptor(interceptor grpc.UnaryClientInterceptor) *ConnectionBuilder {
+	b.unaryInterceptors = append(b.unaryInterceptors, interceptor)
+	return b
+}
+
+// WithStreamInterceptor adds an interceptor for streaming calls (e.g. event delivery).
+// Interceptors are invoked in the order in which they're added.
+func (b *ConnectionBuilder) WithStreamInterceptor(interceptor grpc.StreamClientInterceptor) *ConnectionBuilder {
+	b.streamInterceptors = append(b.streamInterceptors, interceptor)
+	return b
+}
+
+// DialOptions returns the GRPC dial options built from the settings of the builder
+func (b *ConnectionBuilder) DialOptions() []grpc.DialOption {
+	opts := append([]grpc.DialOption{}, b.dialOpts...)
+	return append(opts, comm.InterceptorDialOpts(b.unaryInterceptors, b.streamInterceptors)...)
+}
+
+// WithConnection applies the settings of the given connection builder to all of the connections
+// made by the gateway. It can't be used with an existing SDK instance (see WithSDK) since the
+// connections of the SDK have already been configured.
+func WithConnection(builder *ConnectionBuilder) Option {
+	return func(gw *Gateway) error {
+		if builder == nil {
+			return errors.New("connection builder is nil")
+		}
+		if gw.sdk != nil {
+			return errors.New("connection builder can't be used with an existing SDK")
+		}
+		gw.sdkOpts = append(gw.sdkOpts, fabsdk.WithCorePkg(&connectionCoreFactory{
+			ProviderFactory: defcore.NewProviderFactory(),
+			dialOpts:        builder.dialOpts,
+		}))
+
+		// Interceptors are registered with the SDK so that they're chained with the SDK-wide interceptors
+		for _, interceptor := range builder.unaryInterceptors {
+			gw.sdkOpts = append(gw.sdkOpts, fabsdk.WithUnaryInterceptor(interceptor))
+		}
+		for _, interceptor := range builder.streamInterceptors {
+			gw.sdkOpts = append(gw.sdkOpts, fabsdk.WithStreamInterceptor(interceptor))
+		}
+		return nil
+	}
+}
+
+// connectionCoreFactory is the default core factory with an infra provider that applies
+// custom dial options
+type connectionCoreFactory struct {
+	*defcore.ProviderFactory
+	dialOpts []grpc.DialOption
+}
+
+// CreateInfraProvider returns the default infra provider configured with the custom dial options
+func (f *connectionCoreFactory) CreateInfraProvider(config fab.EndpointConfig) (fab.InfraProvider, error) {
+	return fabpvdr.New(config, fabpvdr.WithDialOptions(f.dialOpts...)), nil
+}
+
+// CreateInfraProviderWithDialOpts returns the default infra provider configured with the custom
+// dial options followed by the given dial options
+func (f *connectionCoreFactory) CreateInfraProviderWithDialOpts(config fab.EndpointConfig, opts ...grpc.DialOption) (fab.InfraProvider, error) {
+	dialOpts := append(append([]grpc.DialOption{}, f.dialOpts...), opts...)
+	return fabpvdr.New(config, fabpvdr.WithDialOptions(dialOpts...)), nil
+}
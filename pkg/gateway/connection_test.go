@@ -0,0 +1,59 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	reqContext "context"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+func TestConnectionBuilderDialOptions(t *testing.T) {
+	b := NewConnectionBuilder()
+	assert.Equal(t, 0, len(b.DialOptions()))
+
+	b.WithKeepalive(keepalive.ClientParameters{Time: time.Minute}).
+		WithTLSConfig(&tls.Config{}).
+		WithDialOptions(grpc.WithBlock())
+	assert.Equal(t, 3, len(b.DialOptions()))
+
+	noopUnary := func(ctx reqContext.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	noopStream := func(ctx reqContext.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	b.WithUnaryInterceptor(noopUnary).WithUnaryInterceptor(noopUnary).WithStreamInterceptor(noopStream)
+	assert.Equal(t, 5, len(b.DialOptions()), "expecting interceptors to be chained into a single option each")
+}
+
+func TestConnectWithConnection(t *testing.T) {
+	b := NewConnectionBuilder().WithKeepalive(keepalive.ClientParameters{Time: time.Minute})
+
+	gw, err := Connect(WithConfig(config.FromFile(sdkConfigFile)), WithUser("User1"), WithConnection(b))
+	require.NoError(t, err)
+	defer gw.Close()
+	assert.Equal(t, 1, len(gw.sdkOpts))
+
+	sdk, err := fabsdk.New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	_, err = Connect(WithSDK(sdk), WithUser("User1"), WithConnection(b))
+	assert.Error(t, err, "expecting error for connection builder with existing SDK")
+
+	_, err = Connect(WithConfig(config.FromFile(sdkConfigFile)), WithUser("User1"), WithConnection(nil))
+	assert.Error(t, err, "expecting error for nil connection builder")
+}
@@ -0,0 +1,194 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// Contract represents a smart contract implemented by a chaincode on a network
+type Contract struct {
+	chaincodeID string
+	name        string
+	network     *Network
+	client      channelClient
+}
+
+func newContract(network *Network, chaincodeID, name string) *Contract {
+	return &Contract{
+		chaincodeID: chaincodeID,
+		name:        name,
+		network:     network,
+		client:      network.client,
+	}
+}
+
+// Name returns the name of the contract, which is empty if the chaincode implements a single contract
+func (c *Contract) Name() string {
+	return c.name
+}
+
+// ChaincodeID returns the ID of the chaincode that implements the contract
+func (c *Contract) ChaincodeID() string {
+	return c.chaincodeID
+}
+
+// EvaluateTransaction evaluates a transaction function on a single peer and returns its
+// result. The transaction is not sent to the orderer, so the ledger is not updated.
+//  Parameters:
+//  name is the name of the transaction function.
+//  args are the arguments passed to the transaction function.
+//
+//  Returns:
+//  The result returned by the transaction function.
+func (c *Contract) EvaluateTransaction(name string, args ...string) ([]byte, error) {
+	return c.evaluate(name, args, proposalOptions{})
+}
+
+func (c *Contract) evaluate(name string, args []string, opts proposalOptions) ([]byte, error) {
+	reqOpts, err := c.requestOptions(fab.Query, opts)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.client.Query(c.request(name, args, opts), reqOpts...)
+	if err != nil {
+		return nil, newEndorseError("failed to evaluate transaction ["+name+"]", err)
+	}
+	return response.Payload, nil
+}
+
+// SubmitTransaction submits a transaction to the ledger. The transaction function is
+// endorsed by the peers required by the endorsement policy, the transaction is sent to the
+// orderer and the call returns once the transaction has been committed.
+//  Parameters:
+//  name is the name of the transaction function.
+//  args are the arguments passed to the transaction function.
+//
+//  Returns:
+//  The result returned by the transaction function.
+func (c *Contract) SubmitTransaction(name string, args ...string) ([]byte, error) {
+	return c.submit(name, args, proposalOptions{})
+}
+
+func (c *Contract) submit(name string, args []string, opts proposalOptions) ([]byte, error) {
+	reqOpts, err := c.requestOptions(fab.Execute, opts)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.client.Execute(c.request(name, args, opts), reqOpts...)
+	if err != nil {
+		return nil, newEndorseError("failed to submit transaction ["+name+"]", err)
+	}
+	return response.Payload, nil
+}
+
+// SubmitAsync submits a transaction to the ledger and returns as soon as the transaction
+// has been sent to the orderer, without waiting for it to be committed. The returned Commit
+// may be used to wait for, or poll, the commit status of the transaction.
+//  Parameters:
+//  name is the name of the transaction function.
+//  args are the arguments passed to the transaction function.
+//
+//  Returns:
+//  The result returned by the transaction function and a handle to its commit status.
+func (c *Contract) SubmitAsync(name string, args ...string) ([]byte, *Commit, error) {
+	return c.submitAsync(name, args, proposalOptions{})
+}
+
+func (c *Contract) submitAsync(name string, args []string, opts proposalOptions) ([]byte, *Commit, error) {
+	reqOpts, err := c.requestOptions(fab.Execute, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	handler := &submitAsyncHandler{timeout: c.timeout()}
+	chain := invoke.NewProposalProcessorHandler(
+		invoke.NewEndorsementHandler(
+			invoke.NewEndorsementValidationHandler(
+				invoke.NewSignatureValidationHandler(handler),
+			),
+		),
+	)
+
+	response, err := c.client.InvokeHandler(chain, c.request(name, args, opts), reqOpts...)
+	if err != nil {
+		return nil, nil, newEndorseError("failed to submit transaction ["+name+"]", err)
+	}
+	if handler.commit == nil {
+		return nil, nil, errors.Errorf("transaction [%s] was not sent to the orderer", name)
+	}
+	return response.Payload, handler.commit, nil
+}
+
+func (c *Contract) request(name string, args []string, opts proposalOptions) channel.Request {
+	return channel.Request{
+		ChaincodeID:  c.chaincodeID,
+		Fcn:          c.qualifiedName(name),
+		Args:         bytesArgs(args),
+		TransientMap: opts.transient,
+	}
+}
+
+func (c *Contract) requestOptions(timeoutType fab.TimeoutType, opts proposalOptions) ([]channel.RequestOption, error) {
+	reqOpts := []channel.RequestOption{
+		channel.WithRetry(retry.DefaultChannelOpts),
+		channel.WithTimeout(timeoutType, c.timeout()),
+	}
+
+	if len(opts.endorsingOrgs) > 0 {
+		endpointType := filter.EndorsingPeer
+		if timeoutType == fab.Query {
+			endpointType = filter.ChaincodeQuery
+		}
+		targetFilter, err := c.endorsingOrgsFilter(endpointType, opts.endorsingOrgs)
+		if err != nil {
+			return nil, err
+		}
+		reqOpts = append(reqOpts, channel.WithTargetFilter(targetFilter))
+	}
+
+	return reqOpts, nil
+}
+
+// endorsingOrgsFilter returns a filter that restricts the target peers of the given endpoint
+// type (i.e. the peers selected by the default filter of the channel client) to the given organizations
+func (c *Contract) endorsingOrgsFilter(endpointType filter.EndpointType, mspIDs []string) (fab.TargetFilter, error) {
+	ctx, err := c.network.channelProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel context")
+	}
+	return filter.All(filter.NewEndpointFilter(ctx, endpointType), filter.NewMSPFilter(mspIDs...)), nil
+}
+
+func (c *Contract) timeout() time.Duration {
+	if c.network.gateway == nil {
+		return defaultTimeout
+	}
+	return c.network.gateway.timeout
+}
+
+func (c *Contract) qualifiedName(name string) string {
+	if c.name == "" {
+		return name
+	}
+	return c.name + ":" + name
+}
+
+func bytesArgs(args []string) [][]byte {
+	bytes := make([][]byte, len(args))
+	for i, arg := range args {
+		bytes[i] = []byte(arg)
+	}
+	return bytes
+}
@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateTransaction(t *testing.T) {
+	client := &mockChannelClient{payload: []byte("result")}
+	contract := newTestNetwork(client).GetContract("mycc")
+	assert.Equal(t, "mycc", contract.ChaincodeID())
+	assert.Equal(t, "", contract.Name())
+
+	result, err := contract.EvaluateTransaction("query", "a", "b")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("result"), result)
+	assert.Equal(t, "query", client.query.Fcn)
+	assert.Equal(t, "mycc", client.query.ChaincodeID)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, client.query.Args)
+
+	client.err = errors.New("query failed")
+	_, err = contract.EvaluateTransaction("query")
+	assert.Error(t, err)
+}
+
+func TestSubmitTransaction(t *testing.T) {
+	client := &mockChannelClient{payload: []byte("result")}
+	contract := newTestNetwork(client).GetContractWithName("mycc", "org.example.contract")
+	assert.Equal(t, "org.example.contract", contract.Name())
+
+	result, err := contract.SubmitTransaction("move", "a", "b", "10")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("result"), result)
+	assert.Equal(t, "org.example.contract:move", client.execute.Fcn)
+	assert.Equal(t, 3, len(client.execute.Args))
+
+	client.err = errors.New("execute failed")
+	_, err = contract.SubmitTransaction("move")
+	assert.Error(t, err)
+}
+
+func newTestNetwork(client channelClient) *Network {
+	return &Network{
+		name:    "mychannel",
+		gateway: &Gateway{timeout: defaultTimeout},
+		client:  client,
+	}
+}
+
+type mockChannelClient struct {
+	payload []byte
+	err     error
+	query   channel.Request
+	execute channel.Request
+}
+
+func (c *mockChannelClient) Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	c.query = request
+	if c.err != nil {
+		return channel.Response{}, c.err
+	}
+	return channel.Response{Payload: c.payload}, nil
+}
+
+func (c *mockChannelClient) Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	c.execute = request
+	if c.err != nil {
+		return channel.Response{}, c.err
+	}
+	return channel.Response{Payload: c.payload}, nil
+}
+
+func (c *mockChannelClient) InvokeHandler(handler invoke.Handler, request channel.Request, options ...channel.RequestOption) (channel.Response, error) {
+	c.execute = request
+	if c.err != nil {
+		return channel.Response{}, c.err
+	}
+	return channel.Response{Payload: c.payload}, nil
+}
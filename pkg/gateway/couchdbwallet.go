@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	"github.com/pkg/errors"
+)
+
+// CouchDBWalletOption describes a functional parameter for the NewCouchDBWallet function
+type CouchDBWalletOption func(*keyvaluestore.CouchDBKeyValueStoreOptions)
+
+// WithCouchDBHTTPClient sets the HTTP client used to access CouchDB (e.g. to configure TLS)
+func WithCouchDBHTTPClient(client *http.Client) CouchDBWalletOption {
+	return func(opts *keyvaluestore.CouchDBKeyValueStoreOptions) {
+		opts.HTTPClient = client
+	}
+}
+
+// WithCouchDBTimeout sets the timeout of the requests to CouchDB (10s by default). It doesn't
+// apply to a client set with WithCouchDBHTTPClient.
+func WithCouchDBTimeout(timeout time.Duration) CouchDBWalletOption {
+	return func(opts *keyvaluestore.CouchDBKeyValueStoreOptions) {
+		opts.Timeout = timeout
+	}
+}
+
+// WithCouchDBCredentials sets the credentials used to authenticate with CouchDB
+func WithCouchDBCredentials(username, password string) CouchDBWalletOption {
+	return func(opts *keyvaluestore.CouchDBKeyValueStoreOptions) {
+		opts.Username = username
+		opts.Password = password
+	}
+}
+
+type couchDBWalletStore struct {
+	store *keyvaluestore.CouchDBKeyValueStore
+}
+
+// NewCouchDBWallet creates an instance of a wallet, backed by a CouchDB database.
+// The database is created if it doesn't exist. Each identity is stored in a separate
+// document whose ID is the identity's label. The format of the documents is the same as the
+// one used by the Node SDK's CouchDB wallet, so the wallets can be shared between SDKs.
+//  Parameters:
+//  couchDBURL specifies the URL of the CouchDB server (e.g. http://localhost:5984).
+//  dbName specifies the name of the database in which to store the wallet.
+//
+//  Returns:
+//  A Wallet object.
+func NewCouchDBWallet(couchDBURL, dbName string, options ...CouchDBWalletOption) (*Wallet, error) {
+	if dbName == "" {
+		return nil, errors.New("database name is empty")
+	}
+
+	opts := &keyvaluestore.CouchDBKeyValueStoreOptions{
+		URL:        couchDBURL,
+		Database:   dbName,
+		StringData: true,
+	}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	store, err := keyvaluestore.NewCouchDB(opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to open wallet database")
+	}
+
+	return NewWallet(&couchDBWalletStore{store: store}), nil
+}
+
+// Put an identity into the wallet.
+func (s *couchDBWalletStore) Put(label string, content []byte) error {
+	if err := s.store.Store(label, content); err != nil {
+		return errors.WithMessage(err, "failed to store identity ["+label+"]")
+	}
+	return nil
+}
+
+// Get an identity from the wallet.
+func (s *couchDBWalletStore) Get(label string) ([]byte, error) {
+	content, err := s.store.Load(label)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, ErrIdentityNotFound
+		}
+		return nil, errors.WithMessage(err, "failed to get identity ["+label+"]")
+	}
+	return content.([]byte), nil
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+func (s *couchDBWalletStore) Remove(label string) error {
+	if err := s.store.Delete(label); err != nil {
+		return errors.WithMessage(err, "failed to remove identity ["+label+"]")
+	}
+	return nil
+}
+
+// Exists tests the existence of an identity in the wallet.
+func (s *couchDBWalletStore) Exists(label string) bool {
+	_, err := s.store.Load(label)
+	return err == nil
+}
+
+// List all of the labels in the wallet.
+func (s *couchDBWalletStore) List() ([]string, error) {
+	labels, err := s.store.Keys()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list identities")
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
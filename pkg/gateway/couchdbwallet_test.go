@@ -0,0 +1,170 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCouchDBWallet(t *testing.T) {
+	couchDB := newMockCouchDB()
+	server := httptest.NewServer(couchDB)
+	defer server.Close()
+
+	wallet, err := NewCouchDBWallet(server.URL, "wallet", WithCouchDBCredentials("admin", "adminpw"))
+	require.NoError(t, err)
+
+	// Creating the wallet again must not fail if the database exists
+	_, err = NewCouchDBWallet(server.URL, "wallet", WithCouchDBCredentials("admin", "adminpw"))
+	require.NoError(t, err)
+
+	assert.False(t, wallet.Exists("user1"))
+	_, err = wallet.Get("user1")
+	assert.Equal(t, ErrIdentityNotFound, err)
+
+	require.NoError(t, wallet.PutX509("user1", "Org1MSP", testCert, testKey))
+	require.NoError(t, wallet.PutX509("user1", "Org1MSP", testCert, testKey), "expecting update to succeed")
+	require.NoError(t, wallet.PutX509("user2", "Org2MSP", testCert, testKey))
+	assert.True(t, wallet.Exists("user1"))
+
+	id, err := wallet.Get("user1")
+	require.NoError(t, err)
+	assert.Equal(t, "Org1MSP", id.mspID())
+	assert.Equal(t, testKey, id.(*X509Identity).Key())
+
+	labels, err := wallet.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2"}, labels)
+
+	require.NoError(t, wallet.Remove("user1"))
+	require.NoError(t, wallet.Remove("user1"))
+	assert.False(t, wallet.Exists("user1"))
+
+	// Document format must be compatible with the Node SDK
+	doc := couchDB.docs["user2"]
+	assert.Equal(t, "user2", doc.ID)
+	assert.Contains(t, doc.Data, `"mspId":"Org2MSP"`)
+}
+
+func TestCouchDBWalletUnauthorized(t *testing.T) {
+	server := httptest.NewServer(newMockCouchDB())
+	defer server.Close()
+
+	_, err := NewCouchDBWallet(server.URL, "wallet")
+	assert.Error(t, err)
+}
+
+func TestCouchDBWalletTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	// Release the hung handlers before the server is closed, otherwise Close blocks
+	defer close(done)
+
+	_, err := NewCouchDBWallet(server.URL, "wallet", WithCouchDBTimeout(50*time.Millisecond))
+	assert.Error(t, err, "expecting timeout error for hung CouchDB")
+}
+
+// mockCouchDBDocument is a wallet document in the format of the Node SDK's CouchDB wallet
+type mockCouchDBDocument struct {
+	ID   string `json:"_id"`
+	Rev  string `json:"_rev,omitempty"`
+	Data string `json:"data"`
+}
+
+// mockCouchDB implements the subset of the CouchDB API used by the wallet
+type mockCouchDB struct {
+	mutex    sync.Mutex
+	dbExists bool
+	docs     map[string]mockCouchDBDocument
+	revision int
+}
+
+func newMockCouchDB() *mockCouchDB {
+	return &mockCouchDB{docs: make(map[string]mockCouchDBDocument)}
+}
+
+func (m *mockCouchDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "adminpw" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if len(parts) == 1 {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if m.dbExists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		m.dbExists = true
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	id := parts[1]
+	if id == "_all_docs" {
+		var rows []map[string]string
+		for id := range m.docs {
+			rows = append(rows, map[string]string{"id": id})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"rows": rows}) //nolint
+		return
+	}
+
+	existing, exists := m.docs[id]
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(existing) //nolint
+	case http.MethodPut:
+		doc := mockCouchDBDocument{}
+		if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if exists && doc.Rev != existing.Rev {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		m.revision++
+		doc.Rev = fmt.Sprintf("%d-abc", m.revision)
+		m.docs[id] = doc
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		if !exists || r.URL.Query().Get("rev") != existing.Rev {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		delete(m.docs, id)
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+	"github.com/pkg/errors"
+)
+
+// ErrorDetail describes the failure of an individual peer
+type ErrorDetail struct {
+	// Address is the address of the peer
+	Address string
+	// Code is the status code returned by the peer (or inferred by the SDK)
+	Code int32
+	// Message is the error message returned by the peer
+	Message string
+}
+
+// EndorseError is returned when a transaction couldn't be endorsed. Details holds the
+// failure of each peer that failed to endorse the transaction. The error isn't wrapped,
+// so it may be checked with a type assertion. Its cause is the underlying SDK error,
+// so status.FromError can still be used to retrieve the status of the failure.
+type EndorseError struct {
+	Details []*ErrorDetail
+	msg     string
+	cause   error
+}
+
+func (e *EndorseError) Error() string {
+	return e.msg + ": " + e.cause.Error()
+}
+
+// Cause returns the underlying SDK error
+func (e *EndorseError) Cause() error {
+	return e.cause
+}
+
+// newEndorseError returns an EndorseError holding the per-peer failures contained in the
+// given error. If the error doesn't contain any peer failures then it's wrapped with the message.
+func newEndorseError(msg string, err error) error {
+	details := endorsementFailures(err)
+	if len(details) == 0 {
+		return errors.WithMessage(err, msg)
+	}
+	return &EndorseError{Details: details, msg: msg, cause: err}
+}
+
+type causer interface {
+	Cause() error
+}
+
+// endorsementFailures extracts the per-peer failures from the errors returned by the endorsers
+// (which identify the peer that failed) and from the endorsement validation errors (which hold
+// the endorser in their status details)
+func endorsementFailures(err error) []*ErrorDetail {
+	var details []*ErrorDetail
+
+	for err != nil {
+		switch e := err.(type) {
+		case multi.Errors:
+			for _, err := range e {
+				details = append(details, endorsementFailures(err)...)
+			}
+			return details
+		case *peer.EndorserError:
+			return append(details, newErrorDetail(e.Endorser, e.Cause()))
+		case *status.Status:
+			if e.Group == status.EndorserServerStatus && len(e.Details) > 0 {
+				if endorser, ok := e.Details[0].(string); ok {
+					details = append(details, &ErrorDetail{Address: endorser, Code: e.Code, Message: e.Message})
+				}
+			}
+			return details
+		}
+
+		c, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+
+	return details
+}
+
+func newErrorDetail(address string, err error) *ErrorDetail {
+	if s, ok := status.FromError(err); ok {
+		return &ErrorDetail{Address: address, Code: s.Code, Message: s.Message}
+	}
+	return &ErrorDetail{Address: address, Code: status.Unknown.ToInt32(), Message: err.Error()}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndorseError(t *testing.T) {
+	connErr := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+	ccErr := status.NewFromExtractedChaincodeError(500, "invalid function")
+
+	cause := errors.WithMessage(multi.New(
+		errors.WithStack(peer.NewEndorserError("peer1.org1.example.com:7051", connErr)),
+		errors.WithStack(peer.NewEndorserError("peer0.org2.example.com:7051", ccErr)),
+	), "SendTransactionProposal failed")
+
+	err := newEndorseError("failed to submit transaction [move]", cause)
+	endorseErr, ok := err.(*EndorseError)
+	require.True(t, ok, "expecting EndorseError")
+	require.Equal(t, 2, len(endorseErr.Details))
+	assert.Equal(t, &ErrorDetail{Address: "peer1.org1.example.com:7051", Code: status.ConnectionFailed.ToInt32(), Message: "connection failed"}, endorseErr.Details[0])
+	assert.Equal(t, &ErrorDetail{Address: "peer0.org2.example.com:7051", Code: 500, Message: "invalid function"}, endorseErr.Details[1])
+
+	s, ok := status.FromError(err)
+	require.True(t, ok, "expecting status to be available from the cause")
+	assert.Equal(t, status.MultipleErrors.ToInt32(), s.Code)
+}
+
+func TestEndorseErrorFromValidation(t *testing.T) {
+	validationErr := status.New(status.EndorserServerStatus, 500, "chaincode error", []interface{}{"peer1.org1.example.com:7051", []byte("payload")})
+
+	err := newEndorseError("failed to evaluate transaction [query]", errors.WithMessage(validationErr, "endorsement validation failed"))
+	endorseErr, ok := err.(*EndorseError)
+	require.True(t, ok, "expecting EndorseError")
+	assert.Equal(t, []*ErrorDetail{{Address: "peer1.org1.example.com:7051", Code: 500, Message: "chaincode error"}}, endorseErr.Details)
+	assert.Equal(t, validationErr, errors.Cause(err))
+}
+
+func TestEndorseErrorWithoutDetails(t *testing.T) {
+	err := newEndorseError("failed to submit transaction [move]", errors.New("commit failed"))
+	_, ok := err.(*EndorseError)
+	assert.False(t, ok, "expecting error without peer failures to be wrapped")
+	assert.Equal(t, "failed to submit transaction [move]: commit failed", err.Error())
+}
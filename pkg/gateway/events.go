@@ -0,0 +1,242 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
+)
+
+// EventOption describes a functional parameter for Contract.RegisterEvent
+type EventOption func(*eventOptions) error
+
+type eventOptions struct {
+	startBlock   *uint64
+	checkpointer Checkpoint
+}
+
+// WithStartBlock replays the chaincode events starting at the given block. By default
+// only the events of blocks committed after registration are received.
+func WithStartBlock(blockNumber uint64) EventOption {
+	return func(opts *eventOptions) error {
+		opts.startBlock = &blockNumber
+		return nil
+	}
+}
+
+// WithCheckpoint resumes listening after the last event recorded by the given checkpoint.
+// The checkpoint takes precedence over WithStartBlock unless it's empty (i.e. nothing has
+// been checkpointed yet). The application is responsible for checkpointing each event
+// once it has been processed (see Checkpointer.CheckpointChaincodeEvent).
+func WithCheckpoint(checkpoint Checkpoint) EventOption {
+	return func(opts *eventOptions) error {
+		if checkpoint == nil {
+			return errors.New("checkpoint is nil")
+		}
+		opts.checkpointer = checkpoint
+		return nil
+	}
+}
+
+// eventRegistration is the registration returned by Contract.RegisterEvent. It holds the
+// event service on which the registration was made so that it can be unregistered.
+type eventRegistration struct {
+	service   fab.EventService
+	reg       fab.Registration
+	blockReg  fab.Registration // block registration used to read the checkpoint block, if any
+	done      chan struct{}    // closed on Unregister to stop forwarding resumed events
+	closeOnce sync.Once
+	blockOnce sync.Once
+}
+
+// RegisterEvent registers for the chaincode events emitted by the contract. Events are
+// received from the deliver service of the channel's peers (as block events) rather than
+// from the ChaincodeEvents service of the Fabric Gateway, which Fabric 1.x peers don't
+// provide. Events include the event payload, so the caller must have sufficient privileges
+// to receive block events. Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  eventFilter is a regular expression that filters the event names.
+//  options specifies where to start listening (see WithStartBlock and WithCheckpoint).
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Contract) RegisterEvent(eventFilter string, options ...EventOption) (fab.Registration, <-chan *fab.CCEvent, error) {
+	opts := eventOptions{}
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return nil, nil, errors.WithMessage(err, "invalid event option")
+		}
+	}
+
+	ctx, err := c.network.channelProvider()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to create channel context")
+	}
+	if ctx.ChannelService() == nil {
+		return nil, nil, errors.New("channel service not initialized")
+	}
+
+	service, err := ctx.ChannelService().EventService(opts.serviceOptions()...)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "event service creation failed")
+	}
+
+	reg, eventch, err := service.RegisterChaincodeEvent(c.chaincodeID, eventFilter)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "chaincode event registration failed")
+	}
+
+	registration := &eventRegistration{service: service, reg: reg, done: make(chan struct{})}
+	if !opts.resume() {
+		return registration, eventch, nil
+	}
+
+	blockNumber := opts.checkpointer.BlockNumber()
+	var processed func() map[string]bool
+	if txID := opts.checkpointer.TransactionID(); txID != "" {
+		// The chaincode events don't carry their position in the block, so the checkpoint
+		// block is read to find out which of its transactions have been processed
+		blockReg, blockch, err := service.RegisterBlockEvent()
+		if err != nil {
+			service.Unregister(reg)
+			return nil, nil, errors.WithMessage(err, "block event registration failed")
+		}
+		registration.blockReg = blockReg
+		processed = func() map[string]bool {
+			defer registration.unregisterBlocks()
+			return processedTxIDs(blockch, registration.done, blockNumber, txID)
+		}
+	}
+
+	return registration, skipProcessedEvents(eventch, registration.done, blockNumber, processed), nil
+}
+
+// Unregister removes the given registration and closes the event channel
+func (c *Contract) Unregister(registration fab.Registration) {
+	if r, ok := registration.(*eventRegistration); ok {
+		r.closeOnce.Do(func() { close(r.done) })
+		r.unregisterBlocks()
+		r.service.Unregister(r.reg)
+		return
+	}
+	logger.Warnf("Invalid chaincode event registration: %v", registration)
+}
+
+func (r *eventRegistration) unregisterBlocks() {
+	if r.blockReg != nil {
+		r.blockOnce.Do(func() { r.service.Unregister(r.blockReg) })
+	}
+}
+
+// resume returns true if listening resumes from a (non-empty) checkpoint
+func (opts *eventOptions) resume() bool {
+	return opts.checkpointer != nil && (opts.checkpointer.BlockNumber() > 0 || opts.checkpointer.TransactionID() != "")
+}
+
+func (opts *eventOptions) serviceOptions() []options.Opt {
+	serviceOpts := []options.Opt{client.WithBlockEvents()}
+
+	switch {
+	case opts.resume():
+		serviceOpts = append(serviceOpts, deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(opts.checkpointer.BlockNumber()))
+	case opts.startBlock != nil:
+		serviceOpts = append(serviceOpts, deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(*opts.startBlock))
+	}
+
+	return serviceOpts
+}
+
+// skipProcessedEvents filters out the events that precede the checkpoint, i.e. the events of
+// earlier blocks and the events of the checkpoint block whose transactions have been processed.
+// processed returns the IDs of those transactions; it's nil if none of them has been processed.
+// Once done is closed, the remaining events are discarded until the input channel is closed.
+func skipProcessedEvents(in <-chan *fab.CCEvent, done <-chan struct{}, blockNumber uint64, processed func() map[string]bool) <-chan *fab.CCEvent {
+	out := make(chan *fab.CCEvent)
+
+	go func() {
+		defer close(out)
+
+		var txIDs map[string]bool
+		if processed != nil {
+			txIDs = processed()
+		}
+
+		for event := range in {
+			if event.BlockNumber < blockNumber {
+				continue
+			}
+			if event.BlockNumber == blockNumber && txIDs[event.TxID] {
+				continue
+			}
+
+			select {
+			case out <- event:
+			case <-done:
+				drain(in)
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// processedTxIDs waits for the checkpoint block and returns the IDs of its transactions up to and
+// including the given transaction. If the transaction isn't in the block (or the block can't be
+// read) then nil is returned so that no event is lost; events may be received again instead.
+func processedTxIDs(blockch <-chan *fab.BlockEvent, done <-chan struct{}, blockNumber uint64, txID string) map[string]bool {
+	for {
+		select {
+		case <-done:
+			return nil
+		case event, ok := <-blockch:
+			if !ok {
+				return nil
+			}
+			number := event.Block.GetHeader().GetNumber()
+			if number < blockNumber {
+				continue
+			}
+			if number > blockNumber {
+				logger.Warnf("Checkpoint block %d was not received; events of transactions up to [%s] may be received again", blockNumber, txID)
+				return nil
+			}
+			return blockTxIDs(event, txID)
+		}
+	}
+}
+
+func blockTxIDs(event *fab.BlockEvent, txID string) map[string]bool {
+	block, err := ledger.DecodeBlock(event.Block)
+	if err != nil {
+		logger.Warnf("Decoding checkpoint block failed; events of transactions up to [%s] may be received again: %s", txID, err)
+		return nil
+	}
+
+	txIDs := make(map[string]bool)
+	for _, tx := range block.Transactions {
+		txIDs[tx.TxID] = true
+		if tx.TxID == txID {
+			return txIDs
+		}
+	}
+
+	logger.Warnf("Transaction [%s] not found in checkpoint block %d; events of the block may be received again", txID, block.Number)
+	return nil
+}
+
+func drain(eventch <-chan *fab.CCEvent) {
+	for range eventch {
+	}
+}
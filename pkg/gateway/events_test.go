@@ -0,0 +1,135 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterEvent(t *testing.T) {
+	network := newOfflineTestNetwork(nil)
+	contract := network.GetContract("mycc")
+
+	_, _, err := contract.RegisterEvent(".*")
+	assert.Error(t, err, "expecting error for missing channel service")
+
+	ctx, err := network.channelProvider()
+	require.NoError(t, err)
+	ctx.(*mocks.MockChannelContext).Channel = &mocks.MockChannelService{}
+
+	reg, eventch, err := contract.RegisterEvent(".*", WithStartBlock(10))
+	require.NoError(t, err)
+	assert.NotNil(t, eventch)
+	contract.Unregister(reg)
+
+	_, _, err = contract.RegisterEvent(".*", WithCheckpoint(nil))
+	assert.Error(t, err, "expecting error for nil checkpoint")
+}
+
+func TestEventServiceOptions(t *testing.T) {
+	opts := eventOptions{}
+	assert.False(t, opts.resume())
+	assert.Equal(t, 1, len(opts.serviceOptions()), "expecting block events only")
+
+	require.NoError(t, WithStartBlock(5)(&opts))
+	assert.Equal(t, 3, len(opts.serviceOptions()), "expecting seek from start block")
+
+	checkpointer := NewInMemoryCheckpointer()
+	require.NoError(t, WithCheckpoint(checkpointer)(&opts))
+	assert.False(t, opts.resume(), "expecting start block to be used for empty checkpoint")
+
+	require.NoError(t, checkpointer.CheckpointTransaction(7, "tx1"))
+	assert.True(t, opts.resume())
+	assert.Equal(t, 3, len(opts.serviceOptions()), "expecting seek from checkpoint block")
+}
+
+func TestSkipProcessedEvents(t *testing.T) {
+	in := make(chan *fab.CCEvent, 6)
+	in <- &fab.CCEvent{BlockNumber: 6, TxID: "tx0"}
+	in <- &fab.CCEvent{BlockNumber: 7, TxID: "tx1"}
+	in <- &fab.CCEvent{BlockNumber: 7, TxID: "tx3"}
+	in <- &fab.CCEvent{BlockNumber: 8, TxID: "tx4"}
+	close(in)
+
+	// tx2 is the checkpointed transaction but it didn't emit a (matching) event
+	processed := func() map[string]bool { return map[string]bool{"tx1": true, "tx2": true} }
+
+	var txIDs []string
+	for event := range skipProcessedEvents(in, nil, 7, processed) {
+		txIDs = append(txIDs, event.TxID)
+	}
+	assert.Equal(t, []string{"tx3", "tx4"}, txIDs)
+
+	in = make(chan *fab.CCEvent, 2)
+	in <- &fab.CCEvent{BlockNumber: 7, TxID: "tx1"}
+	in <- &fab.CCEvent{BlockNumber: 8, TxID: "tx2"}
+	close(in)
+
+	txIDs = nil
+	for event := range skipProcessedEvents(in, nil, 8, nil) {
+		txIDs = append(txIDs, event.TxID)
+	}
+	assert.Equal(t, []string{"tx2"}, txIDs)
+}
+
+func TestProcessedTxIDs(t *testing.T) {
+	block := newReplicationTestBlock(7,
+		replicationTestTx{txID: "tx1", ns: "mycc", key: "k1"},
+		replicationTestTx{txID: "tx2", ns: "mycc", key: "k2"},
+		replicationTestTx{txID: "tx3", ns: "mycc", key: "k3"},
+	)
+
+	blockch := make(chan *fab.BlockEvent, 2)
+	blockch <- &fab.BlockEvent{Block: newReplicationTestBlock(6)}
+	blockch <- &fab.BlockEvent{Block: block}
+	assert.Equal(t, map[string]bool{"tx1": true, "tx2": true}, processedTxIDs(blockch, nil, 7, "tx2"))
+
+	blockch <- &fab.BlockEvent{Block: block}
+	assert.Nil(t, processedTxIDs(blockch, nil, 7, "txX"), "expecting no processed transactions for unknown transaction")
+
+	blockch <- &fab.BlockEvent{Block: newReplicationTestBlock(8)}
+	assert.Nil(t, processedTxIDs(blockch, nil, 7, "tx2"), "expecting no processed transactions for missed block")
+
+	done := make(chan struct{})
+	close(done)
+	assert.Nil(t, processedTxIDs(make(chan *fab.BlockEvent), done, 7, "tx2"))
+}
+
+func TestSkipProcessedEventsDone(t *testing.T) {
+	in := make(chan *fab.CCEvent, 3)
+	in <- &fab.CCEvent{BlockNumber: 8, TxID: "tx1"}
+	in <- &fab.CCEvent{BlockNumber: 8, TxID: "tx2"}
+	in <- &fab.CCEvent{BlockNumber: 9, TxID: "tx3"}
+
+	done := make(chan struct{})
+	out := skipProcessedEvents(in, done, 8, nil)
+
+	// The pending events are discarded once done is closed, even though they aren't received
+	close(done)
+	timeout := time.After(5 * time.Second)
+	for len(in) > 0 {
+		select {
+		case <-timeout:
+			t.Fatal("timed out waiting for the pending events to be discarded")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	close(in)
+	select {
+	case _, ok := <-out:
+		assert.False(t, ok, "expecting the event channel to be closed")
+	case <-timeout:
+		t.Fatal("timed out waiting for the event channel to be closed")
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	"github.com/pkg/errors"
+)
+
+const dataFileExtension = ".id"
+
+type fileSystemWalletStore struct {
+	path  string
+	store *keyvaluestore.FileKeyValueStore
+}
+
+// NewFileSystemWallet creates an instance of a wallet, backed by files on the filesystem.
+// Each identity is stored in a separate JSON file named <label>.id in the
+// given directory, which is the same format as the Node and Java SDK wallets.
+//  Parameters:
+//  path specifies where on the filesystem to store the wallet.
+//
+//  Returns:
+//  A Wallet object.
+func NewFileSystemWallet(path string) (*Wallet, error) {
+	cleanPath := filepath.Clean(path)
+	if err := os.MkdirAll(cleanPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "failed to create wallet directory [%s]", cleanPath)
+	}
+
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{
+		Path: cleanPath,
+		KeySerializer: func(key interface{}) (string, error) {
+			label, ok := key.(string)
+			if !ok {
+				return "", errors.New("converting key to string failed")
+			}
+			if label == "" || strings.ContainsAny(label, `/\`) {
+				return "", errors.Errorf("invalid label [%s]", label)
+			}
+			return filepath.Join(cleanPath, label+dataFileExtension), nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWallet(&fileSystemWalletStore{path: cleanPath, store: store}), nil
+}
+
+// Put an identity into the wallet.
+func (f *fileSystemWalletStore) Put(label string, content []byte) error {
+	return f.store.Store(label, content)
+}
+
+// Get an identity from the wallet.
+func (f *fileSystemWalletStore) Get(label string) ([]byte, error) {
+	content, err := f.store.Load(label)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return content.([]byte), nil
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+func (f *fileSystemWalletStore) Remove(label string) error {
+	return f.store.Delete(label)
+}
+
+// Exists tests the existence of an identity in the wallet.
+func (f *fileSystemWalletStore) Exists(label string) bool {
+	_, err := f.store.Load(label)
+	return err == nil
+}
+
+// List all of the labels in the wallet.
+func (f *fileSystemWalletStore) List() ([]string, error) {
+	files, err := ioutil.ReadDir(f.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read wallet directory [%s]", f.path)
+	}
+
+	var labels []string
+	for _, file := range files {
+		name := file.Name()
+		if !file.IsDir() && filepath.Ext(name) == dataFileExtension {
+			labels = append(labels, strings.TrimSuffix(name, dataFileExtension))
+		}
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
@@ -0,0 +1,233 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gateway enables Go developers to build client applications using the programming
+// model of the other Fabric SDKs. Identities are stored in wallets that are interchangeable
+// with the wallets of the Node and Java SDKs.
+//
+//  Basic Flow:
+//  1) Connect to a gateway using a connection profile and an identity
+//  2) Get the network (channel) from the gateway
+//  3) Get the contract (chaincode) from the network
+//  4) Submit or evaluate transactions
+//
+// Chaincode events (see Contract.RegisterEvent) are received from the deliver service of the
+// channel's peers rather than from the ChaincodeEvents service of the Fabric Gateway, which
+// Fabric 1.x peers don't provide.
+package gateway
+
+import (
+	"encoding/pem"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/gateway")
+
+const defaultTimeout = 5 * time.Minute
+
+// Gateway is the entry point to a Fabric network for client applications. It holds the
+// SDK instance and the identity used to interact with the networks (channels) that are
+// accessible through the gateway.
+type Gateway struct {
+	sdk      *fabsdk.FabricSDK
+	ownsSDK  bool
+	config   core.ConfigProvider
+	wallet   *Wallet
+	label    string
+	user     string
+	org      string
+	identity msp.SigningIdentity
+	timeout  time.Duration
+	sdkOpts  []fabsdk.Option
+}
+
+// Option describes a functional parameter for the Connect function
+type Option func(*Gateway) error
+
+// ConfigOption specifies the configuration (connection profile or SDK) used by the gateway
+type ConfigOption func(*Gateway) error
+
+// IdentityOption specifies the identity used by the gateway
+type IdentityOption func(*Gateway) error
+
+// Connect creates a gateway using the given configuration and identity.
+//  Parameters:
+//  config specifies the connection profile or SDK instance (see WithConfig and WithSDK).
+//  identity specifies the identity used for all transactions (see WithIdentity and WithUser).
+//  options specifies optional settings such as the transaction timeout.
+//
+//  Returns:
+//  A gateway.
+func Connect(config ConfigOption, identity IdentityOption, options ...Option) (*Gateway, error) {
+	gw := &Gateway{
+		timeout: defaultTimeout,
+	}
+
+	if err := config(gw); err != nil {
+		return nil, errors.WithMessage(err, "invalid gateway configuration")
+	}
+	if err := identity(gw); err != nil {
+		return nil, errors.WithMessage(err, "invalid gateway identity")
+	}
+	for _, option := range options {
+		if err := option(gw); err != nil {
+			return nil, errors.WithMessage(err, "invalid gateway option")
+		}
+	}
+
+	if gw.sdk == nil {
+		sdk, err := fabsdk.New(gw.config, gw.sdkOpts...)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create SDK")
+		}
+		gw.sdk = sdk
+		gw.ownsSDK = true
+	}
+
+	if gw.wallet != nil {
+		if err := gw.loadIdentity(); err != nil {
+			gw.Close()
+			return nil, err
+		}
+	}
+
+	return gw, nil
+}
+
+// WithConfig configures the gateway from a connection profile. The SDK created from
+// the connection profile is closed when the gateway is closed.
+func WithConfig(config core.ConfigProvider) ConfigOption {
+	return func(gw *Gateway) error {
+		if config == nil {
+			return errors.New("config provider is nil")
+		}
+		gw.config = config
+		return nil
+	}
+}
+
+// WithSDK configures the gateway with an existing SDK instance. The SDK is not closed
+// when the gateway is closed since it may be shared with other clients.
+func WithSDK(sdk *fabsdk.FabricSDK) ConfigOption {
+	return func(gw *Gateway) error {
+		if sdk == nil {
+			return errors.New("SDK is nil")
+		}
+		gw.sdk = sdk
+		return nil
+	}
+}
+
+// WithIdentity uses the identity with the given label in the wallet for all transactions
+func WithIdentity(wallet *Wallet, label string) IdentityOption {
+	return func(gw *Gateway) error {
+		if wallet == nil {
+			return errors.New("wallet is nil")
+		}
+		if !wallet.Exists(label) {
+			return errors.Errorf("identity [%s] not found in wallet", label)
+		}
+		gw.wallet = wallet
+		gw.label = label
+		return nil
+	}
+}
+
+// WithUser uses the given user from the credential store of the connection profile for all transactions
+func WithUser(user string) IdentityOption {
+	return func(gw *Gateway) error {
+		gw.user = user
+		return nil
+	}
+}
+
+// WithOfflineIdentity uses an identity whose private key is not available to the SDK.
+// Transactions must be created with Contract.NewProposal and signed by the holder of
+// the private key (e.g. an external signing service); SubmitTransaction and
+// EvaluateTransaction can't be used with an offline identity.
+//  Parameters:
+//  mspID is the MSP ID of the identity.
+//  cert is the PEM encoded certificate of the identity.
+func WithOfflineIdentity(mspID string, cert string) IdentityOption {
+	return func(gw *Gateway) error {
+		if block, _ := pem.Decode([]byte(cert)); block == nil {
+			return errors.New("invalid PEM certificate for offline identity")
+		}
+		gw.identity = &signingIdentity{
+			id:                    offlineIdentityID,
+			mspID:                 mspID,
+			enrollmentCertificate: []byte(cert),
+		}
+		return nil
+	}
+}
+
+// WithOrg sets the organization of the user specified by WithUser. The client organization
+// of the connection profile is used by default.
+func WithOrg(org string) Option {
+	return func(gw *Gateway) error {
+		gw.org = org
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of transactions submitted or evaluated through the gateway
+// (5 minutes by default)
+func WithTimeout(timeout time.Duration) Option {
+	return func(gw *Gateway) error {
+		if timeout <= 0 {
+			return errors.New("timeout must be greater than zero")
+		}
+		gw.timeout = timeout
+		return nil
+	}
+}
+
+// GetNetwork returns the network (channel) with the given name
+func (gw *Gateway) GetNetwork(name string) (*Network, error) {
+	return newNetwork(gw, name)
+}
+
+// Close releases the resources held by the gateway
+func (gw *Gateway) Close() {
+	if gw.ownsSDK {
+		gw.sdk.Close()
+	}
+}
+
+func (gw *Gateway) loadIdentity() error {
+	ctx, err := gw.sdk.Context()()
+	if err != nil {
+		return errors.WithMessage(err, "failed to create client context")
+	}
+
+	identity, err := gw.wallet.SigningIdentity(gw.label, ctx.CryptoSuite())
+	if err != nil {
+		return errors.WithMessage(err, "failed to load identity ["+gw.label+"] from wallet")
+	}
+
+	logger.Debugf("using identity [%s] of MSP [%s] from wallet", gw.label, identity.Identifier().MSPID)
+	gw.identity = identity
+	return nil
+}
+
+func (gw *Gateway) contextOptions() []fabsdk.ContextOption {
+	if gw.identity != nil {
+		return []fabsdk.ContextOption{fabsdk.WithIdentity(gw.identity)}
+	}
+
+	options := []fabsdk.ContextOption{fabsdk.WithUser(gw.user)}
+	if gw.org != "" {
+		options = append(options, fabsdk.WithOrg(gw.org))
+	}
+	return options
+}
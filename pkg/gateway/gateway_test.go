@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sdkConfigFile = "../../test/fixtures/config/config_test.yaml"
+
+func TestConnectWithConfig(t *testing.T) {
+	gw, err := Connect(WithConfig(config.FromFile(sdkConfigFile)), WithUser("User1"), WithOrg("org1"), WithTimeout(time.Minute))
+	require.NoError(t, err)
+	defer gw.Close()
+
+	assert.True(t, gw.ownsSDK)
+	assert.Equal(t, time.Minute, gw.timeout)
+	assert.Equal(t, 2, len(gw.contextOptions()))
+}
+
+func TestConnectWithSDK(t *testing.T) {
+	sdk, err := fabsdk.New(config.FromFile(sdkConfigFile))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	gw, err := Connect(WithSDK(sdk), WithUser("User1"))
+	require.NoError(t, err)
+	gw.Close()
+
+	assert.False(t, gw.ownsSDK)
+	assert.Equal(t, defaultTimeout, gw.timeout)
+}
+
+func TestConnectWithIdentity(t *testing.T) {
+	wallet := NewInMemoryWallet()
+
+	_, err := Connect(WithConfig(config.FromFile(sdkConfigFile)), WithIdentity(wallet, "user1"))
+	assert.Error(t, err, "expecting error for identity that isn't in the wallet")
+
+	require.NoError(t, wallet.Put("user1", NewX509Identity("Org1MSP", testCert, testKey)))
+	_, err = Connect(WithConfig(config.FromFile(sdkConfigFile)), WithIdentity(wallet, "user1"))
+	assert.Error(t, err, "expecting error for invalid private key")
+}
+
+func TestConnectInvalidOptions(t *testing.T) {
+	_, err := Connect(WithConfig(nil), WithUser("User1"))
+	assert.Error(t, err)
+
+	_, err = Connect(WithSDK(nil), WithUser("User1"))
+	assert.Error(t, err)
+
+	_, err = Connect(WithConfig(config.FromFile(sdkConfigFile)), WithIdentity(nil, "user1"))
+	assert.Error(t, err)
+
+	_, err = Connect(WithConfig(config.FromFile(sdkConfigFile)), WithUser("User1"), WithTimeout(0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	x509Type    = "X.509"
+	hsmX509Type = "HSM-X.509"
+
+	// identityVersion is the latest version of the wallet's JSON format
+	identityVersion = 1
+)
+
+// Identity represents an identity stored in a wallet. Each implementation
+// corresponds to an identity type in the wallet's JSON format.
+type Identity interface {
+	idType() string
+	mspID() string
+	toJSON() ([]byte, error)
+	fromJSON(data []byte) (Identity, error)
+}
+
+// identityTypes maps the identity type in the wallet's JSON format to its implementation
+var identityTypes = map[string]Identity{
+	x509Type:    &X509Identity{},
+	hsmX509Type: &HSMX509Identity{},
+}
+
+// MarshalIdentity serializes an identity to the JSON format used by the wallets of the Node
+// and Java SDKs, so that identities can be exported to applications written in other languages.
+func MarshalIdentity(id Identity) ([]byte, error) {
+	if id == nil {
+		return nil, errors.New("identity is nil")
+	}
+	data, err := id.toJSON()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal identity")
+	}
+	return data, nil
+}
+
+// UnmarshalIdentity deserializes an identity from the JSON format used by the wallets of the
+// Node and Java SDKs. The concrete type of the returned identity depends on the "type" field.
+func UnmarshalIdentity(data []byte) (Identity, error) {
+	var header struct {
+		Version int    `json:"version"`
+		IDType  string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, errors.Wrap(err, "invalid identity format")
+	}
+	if header.Version > identityVersion {
+		return nil, errors.Errorf("unsupported identity version [%d]", header.Version)
+	}
+
+	idType, ok := identityTypes[header.IDType]
+	if !ok {
+		return nil, errors.Errorf("unsupported identity type [%s]", header.IDType)
+	}
+
+	return idType.fromJSON(data)
+}
+
+// X509Identity represents an X509 identity. The JSON encoding of an X509Identity is the
+// same as the one used by the Node and Java SDK wallets.
+type X509Identity struct {
+	Version     int         `json:"version"`
+	MspID       string      `json:"mspId"`
+	IDType      string      `json:"type"`
+	Credentials credentials `json:"credentials"`
+}
+
+type credentials struct {
+	Certificate string `json:"certificate"`
+	Key         string `json:"privateKey"`
+}
+
+// NewX509Identity creates an X509 identity from a PEM encoded certificate and private key
+func NewX509Identity(mspid string, cert string, key string) *X509Identity {
+	return &X509Identity{
+		Version: identityVersion,
+		MspID:   mspid,
+		IDType:  x509Type,
+		Credentials: credentials{
+			Certificate: cert,
+			Key:         key,
+		},
+	}
+}
+
+// Certificate returns the PEM encoded certificate of the identity
+func (x *X509Identity) Certificate() string {
+	return x.Credentials.Certificate
+}
+
+// Key returns the PEM encoded private key of the identity
+func (x *X509Identity) Key() string {
+	return x.Credentials.Key
+}
+
+func (x *X509Identity) idType() string {
+	return x509Type
+}
+
+func (x *X509Identity) mspID() string {
+	return x.MspID
+}
+
+func (x *X509Identity) toJSON() ([]byte, error) {
+	return json.Marshal(x)
+}
+
+func (x *X509Identity) fromJSON(data []byte) (Identity, error) {
+	identity := &X509Identity{}
+	if err := json.Unmarshal(data, identity); err != nil {
+		return nil, errors.Wrap(err, "invalid X509 identity")
+	}
+	if identity.Credentials.Certificate == "" {
+		return nil, errors.New("certificate is missing from X509 identity")
+	}
+	return identity, nil
+}
+
+// HSMX509Identity represents an X509 identity whose private key is held in a hardware
+// security module. Only the certificate is stored in the wallet; the private key is looked
+// up in the PKCS#11 crypto suite using the subject key identifier of the certificate.
+// The JSON encoding is the same as the one used by the Node SDK wallets.
+type HSMX509Identity struct {
+	Version     int            `json:"version"`
+	MspID       string         `json:"mspId"`
+	IDType      string         `json:"type"`
+	Credentials hsmCredentials `json:"credentials"`
+}
+
+type hsmCredentials struct {
+	Certificate string `json:"certificate"`
+}
+
+// NewHSMX509Identity creates an HSM backed X509 identity from a PEM encoded certificate
+func NewHSMX509Identity(mspid string, cert string) *HSMX509Identity {
+	return &HSMX509Identity{
+		Version: identityVersion,
+		MspID:   mspid,
+		IDType:  hsmX509Type,
+		Credentials: hsmCredentials{
+			Certificate: cert,
+		},
+	}
+}
+
+// Certificate returns the PEM encoded certificate of the identity
+func (x *HSMX509Identity) Certificate() string {
+	return x.Credentials.Certificate
+}
+
+func (x *HSMX509Identity) idType() string {
+	return hsmX509Type
+}
+
+func (x *HSMX509Identity) mspID() string {
+	return x.MspID
+}
+
+func (x *HSMX509Identity) toJSON() ([]byte, error) {
+	return json.Marshal(x)
+}
+
+func (x *HSMX509Identity) fromJSON(data []byte) (Identity, error) {
+	identity := &HSMX509Identity{}
+	if err := json.Unmarshal(data, identity); err != nil {
+		return nil, errors.Wrap(err, "invalid HSM X509 identity")
+	}
+	if identity.Credentials.Certificate == "" {
+		return nil, errors.New("certificate is missing from HSM X509 identity")
+	}
+	return identity, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sort"
+	"sync"
+)
+
+type inMemoryWalletStore struct {
+	mutex   sync.RWMutex
+	storage map[string][]byte
+}
+
+// NewInMemoryWallet creates an instance of a wallet, held in memory.
+// This implementation is not backed by a persistent store.
+//
+//  Returns:
+//  A Wallet object.
+func NewInMemoryWallet() *Wallet {
+	return NewWallet(&inMemoryWalletStore{storage: make(map[string][]byte)})
+}
+
+// Put an identity into the wallet.
+func (s *inMemoryWalletStore) Put(label string, content []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.storage[label] = content
+	return nil
+}
+
+// Get an identity from the wallet.
+func (s *inMemoryWalletStore) Get(label string) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	content, ok := s.storage[label]
+	if !ok {
+		return nil, ErrIdentityNotFound
+	}
+	return content, nil
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+func (s *inMemoryWalletStore) Remove(label string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.storage, label)
+	return nil
+}
+
+// Exists tests the existence of an identity in the wallet.
+func (s *inMemoryWalletStore) Exists(label string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, ok := s.storage[label]
+	return ok
+}
+
+// List all of the labels in the wallet.
+func (s *inMemoryWalletStore) List() ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	labels := make([]string, 0, len(s.storage))
+	for label := range s.storage {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
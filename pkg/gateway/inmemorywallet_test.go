@@ -0,0 +1,50 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryWallet(t *testing.T) {
+	wallet := NewInMemoryWallet()
+
+	assert.False(t, wallet.Exists("user1"))
+	_, err := wallet.Get("user1")
+	assert.Equal(t, ErrIdentityNotFound, err)
+
+	require.NoError(t, wallet.PutX509("user1", "Org1MSP", testCert, testKey))
+	require.NoError(t, wallet.Put("user2", NewX509Identity("Org2MSP", testCert, testKey)))
+	assert.True(t, wallet.Exists("user1"))
+
+	id, err := wallet.Get("user1")
+	require.NoError(t, err)
+	assert.Equal(t, "Org1MSP", id.mspID())
+	assert.Equal(t, testCert, id.(*X509Identity).Certificate())
+
+	labels, err := wallet.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2"}, labels)
+
+	require.NoError(t, wallet.Remove("user1"))
+	require.NoError(t, wallet.Remove("user1"))
+	assert.False(t, wallet.Exists("user1"))
+
+	assert.Error(t, wallet.Put("", NewX509Identity("Org1MSP", testCert, testKey)))
+	assert.Error(t, wallet.Put("user3", nil))
+}
+
+func TestPutX509InvalidPEM(t *testing.T) {
+	wallet := NewInMemoryWallet()
+
+	assert.Error(t, wallet.PutX509("user1", "Org1MSP", "invalid", testKey))
+	assert.Error(t, wallet.PutX509("user1", "Org1MSP", testCert, "invalid"))
+	assert.False(t, wallet.Exists("user1"))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/pkg/errors"
+)
+
+// channelClient is the subset of the channel client used by networks and contracts
+type channelClient interface {
+	Query(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	Execute(request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+	InvokeHandler(handler invoke.Handler, request channel.Request, options ...channel.RequestOption) (channel.Response, error)
+}
+
+// Network represents a channel that is accessible through a gateway
+type Network struct {
+	name            string
+	gateway         *Gateway
+	channelProvider context.ChannelProvider
+	client          channelClient
+}
+
+func newNetwork(gw *Gateway, name string) (*Network, error) {
+	channelProvider := gw.sdk.ChannelContext(name, gw.contextOptions()...)
+
+	client, err := channel.New(channelProvider)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel client for network ["+name+"]")
+	}
+
+	return &Network{
+		name:            name,
+		gateway:         gw,
+		channelProvider: channelProvider,
+		client:          client,
+	}, nil
+}
+
+// Name returns the name of the network (channel)
+func (n *Network) Name() string {
+	return n.name
+}
+
+// GetContract returns the contract implemented by the chaincode with the given ID
+func (n *Network) GetContract(chaincodeID string) *Contract {
+	return n.GetContractWithName(chaincodeID, "")
+}
+
+// GetContractWithName returns a named contract implemented by the chaincode with the given
+// ID. Chaincode that implements several contracts exposes the transactions of each contract
+// as "<contract name>:<transaction name>".
+func (n *Network) GetContractWithName(chaincodeID, name string) *Contract {
+	return newContract(n, chaincodeID, name)
+}
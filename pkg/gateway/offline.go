@@ -0,0 +1,309 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"bytes"
+	reqContext "context"
+	"crypto/sha256"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const offlineIdentityID = "offline"
+
+// Proposal is a transaction proposal that is signed outside of the SDK. The digest of
+// the proposal must be signed with the private key of the gateway identity and the
+// signature passed to Evaluate or Endorse.
+type Proposal struct {
+	contract *Contract
+	ctx      context.Channel
+	proposal *fab.TransactionProposal
+	bytes    []byte
+	orgs     []string
+}
+
+// NewProposal creates a proposal to invoke the given transaction function. Proposals are
+// typically used with an offline identity (see WithOfflineIdentity), where the private key
+// of the identity isn't available to the SDK.
+//  Parameters:
+//  name is the name of the transaction function.
+//  args are the arguments passed to the transaction function.
+//
+//  Returns:
+//  The unsigned proposal.
+func (c *Contract) NewProposal(name string, args ...string) (*Proposal, error) {
+	return c.newProposal(name, args, proposalOptions{})
+}
+
+func (c *Contract) newProposal(name string, args []string, opts proposalOptions) (*Proposal, error) {
+	ctx, err := c.network.channelProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create channel context")
+	}
+
+	txh, err := txn.NewHeader(ctx, ctx.ChannelID())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create transaction header")
+	}
+
+	request := fab.ChaincodeInvokeRequest{
+		ChaincodeID:  c.chaincodeID,
+		Fcn:          c.qualifiedName(name),
+		Args:         bytesArgs(args),
+		TransientMap: opts.transient,
+	}
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, request)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create proposal")
+	}
+
+	proposalBytes, err := proto.Marshal(proposal.Proposal)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of proposal failed")
+	}
+
+	return &Proposal{
+		contract: c,
+		ctx:      ctx,
+		proposal: proposal,
+		bytes:    proposalBytes,
+		orgs:     opts.endorsingOrgs,
+	}, nil
+}
+
+// TransactionID returns the ID of the transaction
+func (p *Proposal) TransactionID() string {
+	return string(p.proposal.TxnID)
+}
+
+// Bytes returns the serialized proposal
+func (p *Proposal) Bytes() []byte {
+	return p.bytes
+}
+
+// Digest returns the SHA256 digest of the serialized proposal, which is what must be signed
+func (p *Proposal) Digest() []byte {
+	return digest(p.bytes)
+}
+
+// Evaluate sends the signed proposal to a single endorsing peer and returns the result of the
+// transaction function. The transaction is not sent to the orderer, so the ledger is not updated.
+func (p *Proposal) Evaluate(signature []byte) ([]byte, error) {
+	responses, err := p.endorse(signature, 1)
+	if err != nil {
+		return nil, newEndorseError("failed to evaluate transaction", err)
+	}
+	return responses[0].ProposalResponse.GetResponse().Payload, nil
+}
+
+// Endorse sends the signed proposal to the endorsing peers. The endorsed transaction that is
+// returned must be signed in turn before it's submitted to the orderer.
+func (p *Proposal) Endorse(signature []byte) (*Transaction, error) {
+	responses, err := p.endorse(signature, 0)
+	if err != nil {
+		return nil, newEndorseError("failed to endorse transaction", err)
+	}
+
+	tx, err := txn.New(fab.TransactionRequest{Proposal: p.proposal, ProposalResponses: responses})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create transaction")
+	}
+
+	hdr, err := utils.GetHeader(p.proposal.Proposal.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal header failed")
+	}
+	txBytes, err := utils.GetBytesTransaction(tx.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	payloadBytes, err := proto.Marshal(&common.Payload{Header: hdr, Data: txBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of transaction payload failed")
+	}
+
+	return &Transaction{
+		proposal: p,
+		tx:       tx,
+		result:   responses[0].ProposalResponse.GetResponse().Payload,
+		bytes:    payloadBytes,
+	}, nil
+}
+
+func (p *Proposal) endorse(signature []byte, maxTargets int) ([]*fab.TransactionProposalResponse, error) {
+	endorsers, err := p.ctx.SelectionService().GetEndorsersForChaincode([]string{p.contract.chaincodeID})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get endorsing peers")
+	}
+	if len(p.orgs) > 0 {
+		endorsers = filter.Filter(endorsers, filter.NewMSPFilter(p.orgs...))
+		if len(endorsers) == 0 {
+			return nil, errors.Errorf("no endorsing peers found for organizations %v", p.orgs)
+		}
+	}
+	if maxTargets > 0 && len(endorsers) > maxTargets {
+		endorsers = endorsers[:maxTargets]
+	}
+
+	targets := make([]fab.ProposalProcessor, len(endorsers))
+	for i, endorser := range endorsers {
+		targets[i] = endorser
+	}
+
+	reqCtx, cancel := p.contract.newSignedRequest(p.ctx, p.bytes, signature)
+	defer cancel()
+
+	responses, err := txn.SendProposal(reqCtx, p.proposal, targets)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateResponses(responses); err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// Transaction is an endorsed transaction that is signed outside of the SDK. The digest of the
+// transaction must be signed with the private key of the gateway identity and the signature
+// passed to Submit.
+type Transaction struct {
+	proposal *Proposal
+	tx       *fab.Transaction
+	result   []byte
+	bytes    []byte
+}
+
+// TransactionID returns the ID of the transaction
+func (t *Transaction) TransactionID() string {
+	return t.proposal.TransactionID()
+}
+
+// Result returns the result of the transaction function returned by the endorsing peers
+func (t *Transaction) Result() []byte {
+	return t.result
+}
+
+// Bytes returns the serialized transaction payload
+func (t *Transaction) Bytes() []byte {
+	return t.bytes
+}
+
+// Digest returns the SHA256 digest of the serialized transaction payload, which is what must be signed
+func (t *Transaction) Digest() []byte {
+	return digest(t.bytes)
+}
+
+// Submit sends the signed transaction to the orderer and waits for it to be committed
+func (t *Transaction) Submit(signature []byte) error {
+	ctx := t.proposal.ctx
+
+	reqCtx, cancel := t.proposal.contract.newSignedRequest(ctx, t.bytes, signature)
+	defer cancel()
+
+	chConfig, err := ctx.ChannelService().ChannelConfig()
+	if err != nil {
+		return errors.WithMessage(err, "failed to retrieve channel config")
+	}
+	transactor, err := ctx.InfraProvider().CreateChannelTransactor(reqCtx, chConfig)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create transactor")
+	}
+	eventService, err := ctx.ChannelService().EventService()
+	if err != nil {
+		return errors.WithMessage(err, "event service creation failed")
+	}
+
+	reg, statusNotifier, err := eventService.RegisterTxStatusEvent(t.TransactionID())
+	if err != nil {
+		return errors.Wrap(err, "error registering for TxStatus event")
+	}
+	defer eventService.Unregister(reg)
+
+	if _, err := transactor.SendTransaction(t.tx); err != nil {
+		return errors.WithMessage(err, "failed to submit transaction")
+	}
+
+	select {
+	case txStatus := <-statusNotifier:
+		if txStatus.TxValidationCode != pb.TxValidationCode_VALID {
+			return status.New(status.EventServerStatus, int32(txStatus.TxValidationCode), "received invalid transaction", nil)
+		}
+		return nil
+	case <-reqCtx.Done():
+		return errors.New("Submit didn't receive block event")
+	}
+}
+
+// newSignedRequest creates a request context whose signing manager returns the given
+// signature for the given message, which has been signed outside of the SDK
+func (c *Contract) newSignedRequest(ctx context.Channel, msg []byte, signature []byte) (reqContext.Context, reqContext.CancelFunc) {
+	signedCtx := &presignedContext{
+		Channel:        ctx,
+		signingManager: &presignedSigningManager{msg: msg, signature: signature},
+	}
+
+	return contextImpl.NewRequest(signedCtx, contextImpl.WithTimeout(c.timeout()))
+}
+
+// presignedContext overrides the signing manager of a channel context
+type presignedContext struct {
+	context.Channel
+	signingManager core.SigningManager
+}
+
+// SigningManager returns the signing manager that holds the externally created signature
+func (c *presignedContext) SigningManager() core.SigningManager {
+	return c.signingManager
+}
+
+// presignedSigningManager returns a signature that was created outside of the SDK. Signing
+// any message other than the one that was signed externally fails.
+type presignedSigningManager struct {
+	msg       []byte
+	signature []byte
+}
+
+// Sign returns the externally created signature of the given message
+func (m *presignedSigningManager) Sign(msg []byte, key core.Key) ([]byte, error) {
+	if len(m.signature) == 0 {
+		return nil, errors.New("signature is required")
+	}
+	if !bytes.Equal(msg, m.msg) {
+		return nil, errors.New("message doesn't match the message that was signed")
+	}
+	return m.signature, nil
+}
+
+func validateResponses(responses []*fab.TransactionProposalResponse) error {
+	for i, r := range responses {
+		if r.ProposalResponse.GetResponse().Status != int32(common.Status_SUCCESS) {
+			return status.NewFromProposalResponse(r.ProposalResponse, r.Endorser)
+		}
+		if i > 0 && !bytes.Equal(responses[0].ProposalResponse.Payload, r.ProposalResponse.Payload) {
+			return status.New(status.EndorserClientStatus, status.EndorsementMismatch.ToInt32(),
+				"ProposalResponsePayloads do not match", nil)
+		}
+	}
+	return nil
+}
+
+func digest(msg []byte) []byte {
+	hash := sha256.Sum256(msg)
+	return hash[:]
+}
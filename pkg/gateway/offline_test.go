@@ -0,0 +1,92 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineProposal(t *testing.T) {
+	peer1 := &mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Payload: []byte("result"), Status: 200}
+	peer2 := &mocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", Payload: []byte("result"), Status: 200}
+
+	contract := newOfflineTestNetwork([]fab.Peer{peer1, peer2}).GetContract("mycc")
+
+	proposal, err := contract.NewProposal("move", "a", "b", "10")
+	require.NoError(t, err)
+	assert.NotEmpty(t, proposal.TransactionID())
+	assert.NotEmpty(t, proposal.Bytes())
+	assert.Equal(t, 32, len(proposal.Digest()))
+
+	_, err = proposal.Evaluate(nil)
+	assert.Error(t, err, "expecting error for missing signature")
+
+	result, err := proposal.Evaluate([]byte("signature"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("result"), result)
+	assert.Equal(t, 1, peer1.ProcessProposalCalls+peer2.ProcessProposalCalls)
+
+	transaction, err := proposal.Endorse([]byte("signature"))
+	require.NoError(t, err)
+	assert.Equal(t, proposal.TransactionID(), transaction.TransactionID())
+	assert.Equal(t, []byte("result"), transaction.Result())
+	assert.NotEmpty(t, transaction.Bytes())
+	assert.Equal(t, digest(transaction.Bytes()), transaction.Digest())
+	assert.Equal(t, 3, peer1.ProcessProposalCalls+peer2.ProcessProposalCalls)
+}
+
+func TestOfflineProposalEndorsementFailure(t *testing.T) {
+	peer1 := &mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Payload: []byte("result"), Status: 500}
+
+	contract := newOfflineTestNetwork([]fab.Peer{peer1}).GetContract("mycc")
+
+	proposal, err := contract.NewProposal("move")
+	require.NoError(t, err)
+
+	_, err = proposal.Endorse([]byte("signature"))
+	assert.Error(t, err)
+}
+
+func TestPresignedSigningManager(t *testing.T) {
+	m := &presignedSigningManager{msg: []byte("msg"), signature: []byte("signature")}
+
+	signature, err := m.Sign([]byte("msg"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("signature"), signature)
+
+	_, err = m.Sign([]byte("other"), nil)
+	assert.Error(t, err, "expecting error for message that wasn't signed")
+}
+
+func TestWithOfflineIdentity(t *testing.T) {
+	gw := &Gateway{}
+	require.NoError(t, WithOfflineIdentity("Org1MSP", testCert)(gw))
+	assert.Equal(t, "Org1MSP", gw.identity.Identifier().MSPID)
+	assert.Nil(t, gw.identity.PrivateKey())
+
+	assert.Error(t, WithOfflineIdentity("Org1MSP", "invalid")(&Gateway{}))
+}
+
+func newOfflineTestNetwork(peers []fab.Peer) *Network {
+	ctx := mocks.NewMockChannelContext(mocks.NewMockContext(mspmocks.NewMockSigningIdentity("test", "Org1MSP")), "mychannel")
+	ctx.Selection = &mocks.MockSelectionService{Peers: peers}
+
+	return &Network{
+		name:    "mychannel",
+		gateway: &Gateway{timeout: defaultTimeout},
+		channelProvider: func() (context.Channel, error) {
+			return ctx, nil
+		},
+	}
+}
@@ -0,0 +1,268 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	reqContext "context"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/ledger"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// StateWrite is a write to the world state of the channel. The writes to private data
+// collections only carry the hashes of the key and value since the private data itself
+// isn't included in the blocks.
+type StateWrite struct {
+	Namespace string
+	// Collection is the private data collection of the write, or empty for public state
+	Collection string
+	// Key and Value are set for writes to public state
+	Key   string
+	Value []byte
+	// KeyHash and ValueHash are set for writes to private data collections
+	KeyHash   []byte
+	ValueHash []byte
+	IsDelete  bool
+}
+
+// StateUpdate holds the writes of a valid transaction to the replicated namespaces and collections
+type StateUpdate struct {
+	BlockNumber uint64
+	TxID        string
+	Timestamp   time.Time
+	Writes      []*StateWrite
+}
+
+// StateSink receives the state updates of a StateReplicator, e.g. to write them to a
+// database or publish them to a message broker
+type StateSink interface {
+	// Apply applies the writes of a transaction. The replicator stops if an error is returned,
+	// and the transaction is replayed when the replicator is run again.
+	Apply(update *StateUpdate) error
+}
+
+// StateSinkFunc is an adapter that allows a function to be used as a StateSink
+type StateSinkFunc func(update *StateUpdate) error
+
+// Apply calls f(update)
+func (f StateSinkFunc) Apply(update *StateUpdate) error {
+	return f(update)
+}
+
+// ReplicatorOption describes a functional parameter for Network.NewStateReplicator
+type ReplicatorOption func(*StateReplicator) error
+
+// WithNamespaces replicates the public state of the given namespaces (chaincodes) only.
+// By default the public state of all namespaces is replicated.
+func WithNamespaces(namespaces ...string) ReplicatorOption {
+	return func(r *StateReplicator) error {
+		if len(namespaces) == 0 {
+			return errors.New("no namespace specified")
+		}
+		r.namespaces = make(map[string]bool)
+		for _, ns := range namespaces {
+			r.namespaces[ns] = true
+		}
+		return nil
+	}
+}
+
+// WithCollections replicates the hashed writes to the given private data collections of a
+// namespace. Private data collections aren't replicated by default.
+func WithCollections(namespace string, collections ...string) ReplicatorOption {
+	return func(r *StateReplicator) error {
+		if namespace == "" || len(collections) == 0 {
+			return errors.New("namespace and collections are required")
+		}
+		if r.collections[namespace] == nil {
+			r.collections[namespace] = make(map[string]bool)
+		}
+		for _, coll := range collections {
+			r.collections[namespace][coll] = true
+		}
+		return nil
+	}
+}
+
+// StateReplicator tails the blocks of a channel and hands the writes of the valid transactions
+// to a sink so that the world state, or part of it, can be replicated off-chain. Progress is
+// recorded with a checkpointer: the checkpoint is advanced after each update has been applied,
+// so replication resumes after the last applied transaction when the replicator is run again.
+//
+// A transaction whose update was applied is replayed if the replicator stops before its
+// checkpoint is recorded. Each update carries its block number and transaction ID so that the
+// sink can discard duplicates. Alternatively, a sink that also implements Checkpointer may be
+// used as the checkpointer: if it stages the writes in Apply and commits them together with the
+// checkpoint in CheckpointTransaction (e.g. in a single database transaction), each update is
+// applied exactly once.
+type StateReplicator struct {
+	network      *Network
+	sink         StateSink
+	checkpointer Checkpointer
+	namespaces   map[string]bool
+	collections  map[string]map[string]bool
+}
+
+// NewStateReplicator returns a replicator of the channel's world state. Replication starts
+// after the given checkpoint, or at the genesis block if nothing has been checkpointed yet.
+func (n *Network) NewStateReplicator(sink StateSink, checkpointer Checkpointer, options ...ReplicatorOption) (*StateReplicator, error) {
+	if sink == nil || checkpointer == nil {
+		return nil, errors.New("sink and checkpointer are required")
+	}
+
+	r := &StateReplicator{
+		network:      n,
+		sink:         sink,
+		checkpointer: checkpointer,
+		collections:  make(map[string]map[string]bool),
+	}
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, errors.WithMessage(err, "invalid replicator option")
+		}
+	}
+	return r, nil
+}
+
+// Run replicates the state updates until the context is done or an error occurs. It returns
+// the context's error when the context is done. Run must not be called concurrently.
+func (r *StateReplicator) Run(ctx reqContext.Context) error {
+	chCtx, err := r.network.channelProvider()
+	if err != nil {
+		return errors.WithMessage(err, "failed to create channel context")
+	}
+	if chCtx.ChannelService() == nil {
+		return errors.New("channel service not initialized")
+	}
+
+	service, err := chCtx.ChannelService().EventService(
+		client.WithBlockEvents(),
+		deliverclient.WithSeekType(seek.FromBlock),
+		deliverclient.WithBlockNum(r.checkpointer.BlockNumber()),
+	)
+	if err != nil {
+		return errors.WithMessage(err, "event service creation failed")
+	}
+
+	reg, eventch, err := service.RegisterBlockEvent()
+	if err != nil {
+		return errors.WithMessage(err, "block event registration failed")
+	}
+	defer service.Unregister(reg)
+
+	return r.run(ctx, eventch)
+}
+
+func (r *StateReplicator) run(ctx reqContext.Context, eventch <-chan *fab.BlockEvent) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-eventch:
+			if !ok {
+				return errors.New("block event channel closed")
+			}
+			if err := r.replicate(event.Block); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replicate applies the state updates of the transactions of a block that follow the checkpoint
+func (r *StateReplicator) replicate(block *common.Block) error {
+	decoded, err := ledger.DecodeBlock(block)
+	if err != nil {
+		return errors.WithMessage(err, "decoding block failed")
+	}
+
+	if decoded.Number < r.checkpointer.BlockNumber() {
+		return nil
+	}
+
+	lastTxID := r.checkpointer.TransactionID()
+	skipping := decoded.Number == r.checkpointer.BlockNumber() && lastTxID != ""
+	for _, tx := range decoded.Transactions {
+		if skipping {
+			if tx.TxID == lastTxID {
+				skipping = false
+			}
+			continue
+		}
+
+		writes := r.writes(tx)
+		if len(writes) == 0 {
+			continue
+		}
+		update := &StateUpdate{
+			BlockNumber: decoded.Number,
+			TxID:        tx.TxID,
+			Timestamp:   tx.Timestamp,
+			Writes:      writes,
+		}
+		if err := r.sink.Apply(update); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("applying state update of transaction [%s] failed", tx.TxID))
+		}
+		if err := r.checkpointer.CheckpointTransaction(decoded.Number, tx.TxID); err != nil {
+			return errors.WithMessage(err, "checkpointing transaction failed")
+		}
+	}
+
+	if err := r.checkpointer.CheckpointBlock(decoded.Number); err != nil {
+		return errors.WithMessage(err, "checkpointing block failed")
+	}
+	return nil
+}
+
+// writes returns the writes of a valid endorser transaction to the replicated namespaces and collections
+func (r *StateReplicator) writes(tx *ledger.Transaction) []*StateWrite {
+	if tx.Type != common.HeaderType_ENDORSER_TRANSACTION || tx.ValidationCode != pb.TxValidationCode_VALID {
+		return nil
+	}
+
+	var writes []*StateWrite
+	for _, action := range tx.Actions {
+		if action.RWSet == nil {
+			continue
+		}
+		for _, nsRWSet := range action.RWSet.NsRwSets {
+			if (r.namespaces == nil || r.namespaces[nsRWSet.NameSpace]) && nsRWSet.KvRwSet != nil {
+				for _, w := range nsRWSet.KvRwSet.Writes {
+					writes = append(writes, &StateWrite{
+						Namespace: nsRWSet.NameSpace,
+						Key:       w.Key,
+						Value:     w.Value,
+						IsDelete:  w.IsDelete,
+					})
+				}
+			}
+			for _, collRWSet := range nsRWSet.CollHashedRwSets {
+				if !r.collections[nsRWSet.NameSpace][collRWSet.CollectionName] || collRWSet.HashedRwSet == nil {
+					continue
+				}
+				for _, w := range collRWSet.HashedRwSet.HashedWrites {
+					writes = append(writes, &StateWrite{
+						Namespace:  nsRWSet.NameSpace,
+						Collection: collRWSet.CollectionName,
+						KeyHash:    w.KeyHash,
+						ValueHash:  w.ValueHash,
+						IsDelete:   w.IsDelete,
+					})
+				}
+			}
+		}
+	}
+	return writes
+}
@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	reqContext "context"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	ledgerutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateReplicator(t *testing.T) {
+	var updates []*StateUpdate
+	sink := StateSinkFunc(func(update *StateUpdate) error {
+		updates = append(updates, update)
+		return nil
+	})
+	checkpointer := NewInMemoryCheckpointer()
+
+	network := newOfflineTestNetwork(nil)
+	r, err := network.NewStateReplicator(sink, checkpointer, WithNamespaces("cc1"), WithCollections("cc1", "coll1"))
+	require.NoError(t, err)
+
+	require.NoError(t, r.replicate(newReplicationTestBlock(0,
+		replicationTestTx{txID: "tx1", ns: "cc1", key: "k1", coll: "coll1"},
+		replicationTestTx{txID: "tx2", ns: "cc2", key: "k2"},
+		replicationTestTx{txID: "tx3", ns: "cc1", key: "k3", validationCode: pb.TxValidationCode_MVCC_READ_CONFLICT},
+	)))
+	require.Len(t, updates, 1, "expecting only the valid transaction of cc1 to be replicated")
+	assert.Equal(t, "tx1", updates[0].TxID)
+	require.Len(t, updates[0].Writes, 2)
+	assert.Equal(t, &StateWrite{Namespace: "cc1", Key: "k1", Value: []byte("value")}, updates[0].Writes[0])
+	assert.Equal(t, "coll1", updates[0].Writes[1].Collection)
+	assert.Equal(t, []byte("keyhash"), updates[0].Writes[1].KeyHash)
+	assert.Equal(t, uint64(1), checkpointer.BlockNumber())
+	assert.Equal(t, "", checkpointer.TransactionID())
+
+	// Blocks before the checkpoint are skipped
+	updates = nil
+	require.NoError(t, r.replicate(newReplicationTestBlock(0, replicationTestTx{txID: "tx1", ns: "cc1", key: "k1"})))
+	assert.Empty(t, updates)
+}
+
+func TestStateReplicatorResume(t *testing.T) {
+	block := newReplicationTestBlock(5,
+		replicationTestTx{txID: "tx1", ns: "cc1", key: "k1"},
+		replicationTestTx{txID: "tx2", ns: "cc1", key: "k2"},
+		replicationTestTx{txID: "tx3", ns: "cc1", key: "k3"},
+	)
+
+	failTxID := "tx3"
+	var applied []string
+	sink := StateSinkFunc(func(update *StateUpdate) error {
+		if update.TxID == failTxID {
+			return errors.New("database unavailable")
+		}
+		applied = append(applied, update.TxID)
+		return nil
+	})
+	checkpointer := NewInMemoryCheckpointer()
+	require.NoError(t, checkpointer.CheckpointTransaction(5, "tx1"))
+
+	network := newOfflineTestNetwork(nil)
+	r, err := network.NewStateReplicator(sink, checkpointer)
+	require.NoError(t, err)
+
+	assert.Error(t, r.replicate(block), "expecting sink error")
+	assert.Equal(t, []string{"tx2"}, applied)
+	assert.Equal(t, uint64(5), checkpointer.BlockNumber())
+	assert.Equal(t, "tx2", checkpointer.TransactionID())
+
+	// The failed transaction is replayed
+	failTxID = ""
+	require.NoError(t, r.replicate(block))
+	assert.Equal(t, []string{"tx2", "tx3"}, applied)
+	assert.Equal(t, uint64(6), checkpointer.BlockNumber())
+}
+
+func TestStateReplicatorRun(t *testing.T) {
+	var updates []*StateUpdate
+	sink := StateSinkFunc(func(update *StateUpdate) error {
+		updates = append(updates, update)
+		return nil
+	})
+
+	network := newOfflineTestNetwork(nil)
+	_, err := network.NewStateReplicator(nil, NewInMemoryCheckpointer())
+	assert.Error(t, err, "expecting error for missing sink")
+	_, err = network.NewStateReplicator(sink, NewInMemoryCheckpointer(), WithNamespaces())
+	assert.Error(t, err, "expecting error for empty namespaces")
+
+	r, err := network.NewStateReplicator(sink, NewInMemoryCheckpointer())
+	require.NoError(t, err)
+	assert.Error(t, r.Run(reqContext.Background()), "expecting error for missing channel service")
+
+	ctx, err := network.channelProvider()
+	require.NoError(t, err)
+	ctx.(*mocks.MockChannelContext).Channel = &mocks.MockChannelService{}
+	cancelled, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+	assert.Equal(t, reqContext.Canceled, r.Run(cancelled))
+
+	eventch := make(chan *fab.BlockEvent, 1)
+	eventch <- &fab.BlockEvent{Block: newReplicationTestBlock(0, replicationTestTx{txID: "tx1", ns: "cc1", key: "k1"})}
+	close(eventch)
+	assert.Error(t, r.run(reqContext.Background(), eventch), "expecting error for closed event channel")
+	assert.Len(t, updates, 1)
+}
+
+type replicationTestTx struct {
+	txID           string
+	ns             string
+	key            string
+	coll           string
+	validationCode pb.TxValidationCode
+}
+
+func newReplicationTestBlock(number uint64, txs ...replicationTestTx) *common.Block {
+	block := &common.Block{
+		Header:   &common.BlockHeader{Number: number},
+		Data:     &common.BlockData{},
+		Metadata: &common.BlockMetadata{Metadata: make([][]byte, len(common.BlockMetadataIndex_name))},
+	}
+	flags := ledgerutil.NewTxValidationFlags(len(txs))
+	for i, tx := range txs {
+		block.Data.Data = append(block.Data.Data, utils.MarshalOrPanic(newReplicationTestEnvelope(tx)))
+		flags[i] = uint8(tx.validationCode)
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func newReplicationTestEnvelope(tx replicationTestTx) *common.Envelope {
+	nsRWSet := &rwsetutil.NsRwSet{
+		NameSpace: tx.ns,
+		KvRwSet:   &kvrwset.KVRWSet{Writes: []*kvrwset.KVWrite{{Key: tx.key, Value: []byte("value")}}},
+	}
+	if tx.coll != "" {
+		nsRWSet.CollHashedRwSets = []*rwsetutil.CollHashedRwSet{{
+			CollectionName: tx.coll,
+			HashedRwSet: &kvrwset.HashedRWSet{
+				HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("keyhash"), ValueHash: []byte("valuehash")}},
+			},
+		}}
+	}
+	results, err := (&rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{nsRWSet}}).ToProtoBytes()
+	if err != nil {
+		panic(err)
+	}
+
+	prpBytes, err := utils.GetBytesProposalResponsePayload([]byte("hash"), &pb.Response{Status: 200}, results, nil, &pb.ChaincodeID{Name: tx.ns})
+	if err != nil {
+		panic(err)
+	}
+	capBytes, err := utils.GetBytesChaincodeActionPayload(&pb.ChaincodeActionPayload{
+		Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prpBytes},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	payload := &common.Payload{
+		Header: &common.Header{
+			ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+				Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+				ChannelId: "mychannel",
+				TxId:      tx.txID,
+			}),
+			SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{}),
+		},
+		Data: utils.MarshalOrPanic(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: capBytes}}}),
+	}
+	return &common.Envelope{Payload: utils.MarshalOrPanic(payload)}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	pb_msp "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// signingIdentity is a signing identity created from a wallet identity
+type signingIdentity struct {
+	id                    string
+	mspID                 string
+	enrollmentCertificate []byte
+	privateKey            core.Key
+	serializeOnce         sync.Once
+	serialized            []byte
+	serializeErr          error
+}
+
+func newSigningIdentity(label string, id Identity, cryptoSuite core.CryptoSuite) (*signingIdentity, error) {
+	var cert []byte
+	var key core.Key
+	var err error
+
+	switch identity := id.(type) {
+	case *X509Identity:
+		cert = []byte(identity.Certificate())
+		key, err = fabricCaUtil.ImportBCCSPKeyFromPEMBytes([]byte(identity.Key()), cryptoSuite, true)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to import private key of identity ["+label+"]")
+		}
+	case *HSMX509Identity:
+		cert = []byte(identity.Certificate())
+		key, err = cryptoutil.GetPrivateKeyFromCert(cert, cryptoSuite)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to find private key of identity ["+label+"] in HSM")
+		}
+	default:
+		return nil, errors.Errorf("unsupported identity type [%s] for [%s]", id.idType(), label)
+	}
+
+	return &signingIdentity{
+		id:                    label,
+		mspID:                 id.mspID(),
+		enrollmentCertificate: cert,
+		privateKey:            key,
+	}, nil
+}
+
+// Identifier returns the identifier of the identity
+func (s *signingIdentity) Identifier() *msp.IdentityIdentifier {
+	return &msp.IdentityIdentifier{MSPID: s.mspID, ID: s.id}
+}
+
+// Verify a signature over some message using this identity as reference
+func (s *signingIdentity) Verify(msg []byte, sig []byte) error {
+	return errors.New("not implemented")
+}
+
+// Serialize converts an identity to bytes. The identity is marshalled once and the same
+// bytes are returned by subsequent calls, so the returned bytes must not be modified.
+func (s *signingIdentity) Serialize() ([]byte, error) {
+	s.serializeOnce.Do(func() {
+		serializedIdentity := &pb_msp.SerializedIdentity{
+			Mspid:   s.mspID,
+			IdBytes: s.enrollmentCertificate,
+		}
+		identity, err := proto.Marshal(serializedIdentity)
+		if err != nil {
+			s.serializeErr = errors.Wrap(err, "marshal serializedIdentity failed")
+			return
+		}
+		// The capacity is limited so that appending to the bytes never modifies the cached identity
+		s.serialized = identity[:len(identity):len(identity)]
+	})
+	return s.serialized, s.serializeErr
+}
+
+// EnrollmentCertificate returns the underlying ECert representing this identity
+func (s *signingIdentity) EnrollmentCertificate() []byte {
+	return s.enrollmentCertificate
+}
+
+// PrivateKey returns the crypto suite representation of the private key
+func (s *signingIdentity) PrivateKey() core.Key {
+	return s.privateKey
+}
+
+// PublicVersion returns the public parts of this identity
+func (s *signingIdentity) PublicVersion() msp.Identity {
+	return s
+}
+
+// Sign the message
+func (s *signingIdentity) Sign(msg []byte) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	pb_msp "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const userMSPDir = "../../test/fixtures/fabric/v1/crypto-config/peerOrganizations/org1.example.com/users/User1@org1.example.com/msp"
+
+func TestSigningIdentity(t *testing.T) {
+	cert, err := ioutil.ReadFile(filepath.Join(userMSPDir, "signcerts", "User1@org1.example.com-cert.pem"))
+	require.NoError(t, err)
+	key, err := ioutil.ReadFile(filepath.Join(userMSPDir, "keystore", "abbe8ee0f86c227b1917d208921497603d2ff28f4ba8e902d703744c4a6fa7b7_sk"))
+	require.NoError(t, err)
+
+	cs, err := sw.GetSuiteWithDefaultEphemeral()
+	require.NoError(t, err)
+
+	wallet := NewInMemoryWallet()
+	require.NoError(t, wallet.PutX509("user1", "Org1MSP", string(cert), string(key)))
+
+	sigID, err := wallet.SigningIdentity("user1", cs)
+	require.NoError(t, err)
+	assert.Equal(t, "Org1MSP", sigID.Identifier().MSPID)
+	assert.Equal(t, "user1", sigID.Identifier().ID)
+	assert.Equal(t, cert, sigID.EnrollmentCertificate())
+	assert.True(t, sigID.PrivateKey().Private())
+
+	serialized, err := sigID.Serialize()
+	require.NoError(t, err)
+	identity := &pb_msp.SerializedIdentity{}
+	require.NoError(t, proto.Unmarshal(serialized, identity))
+	assert.Equal(t, "Org1MSP", identity.Mspid)
+	assert.Equal(t, cert, identity.IdBytes)
+
+	// The key was imported as a temporary key so it can't be found by the SKI of the certificate
+	require.NoError(t, wallet.Put("hsmuser", NewHSMX509Identity("Org1MSP", string(cert))))
+	_, err = wallet.SigningIdentity("hsmuser", cs)
+	assert.Error(t, err, "expecting error for key that isn't in the crypto suite")
+
+	_, err = wallet.SigningIdentity("unknown", cs)
+	assert.Equal(t, ErrIdentityNotFound, err)
+}
+
+func TestHSMX509IdentityJSON(t *testing.T) {
+	wallet := NewInMemoryWallet()
+	require.NoError(t, wallet.Put("user1", NewHSMX509Identity("Org1MSP", testCert)))
+
+	id, err := wallet.Get("user1")
+	require.NoError(t, err)
+	hsmID, ok := id.(*HSMX509Identity)
+	require.True(t, ok, "expecting HSM X509 identity")
+	assert.Equal(t, "Org1MSP", hsmID.MspID)
+	assert.Equal(t, hsmX509Type, hsmID.IDType)
+	assert.Equal(t, testCert, hsmID.Certificate())
+
+	_, err = (&HSMX509Identity{}).fromJSON([]byte(`{"version":1,"mspId":"Org1MSP","type":"HSM-X.509","credentials":{}}`))
+	assert.Error(t, err, "expecting error for missing certificate")
+}
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ProposalOption describes a functional parameter for Contract.CreateTransaction
+type ProposalOption func(*proposalOptions) error
+
+type proposalOptions struct {
+	transient     map[string][]byte
+	endorsingOrgs []string
+}
+
+// WithTransient sets the transient data of the proposal. Transient data is passed to the
+// chaincode but isn't recorded on the ledger, so it's used to pass private data to the chaincode.
+func WithTransient(data map[string][]byte) ProposalOption {
+	return func(opts *proposalOptions) error {
+		opts.transient = data
+		return nil
+	}
+}
+
+// WithEndorsingOrganizations restricts the endorsement of the proposal to the peers of the given
+// organizations (MSP IDs). This is typically required when the transaction function writes to a
+// private data collection that isn't shared with all of the organizations in the channel.
+func WithEndorsingOrganizations(mspIDs ...string) ProposalOption {
+	return func(opts *proposalOptions) error {
+		if len(mspIDs) == 0 {
+			return errors.New("at least one endorsing organization is required")
+		}
+		opts.endorsingOrgs = mspIDs
+		return nil
+	}
+}
+
+// TransactionRequest is an invocation of a transaction function with proposal options such as
+// transient data and endorsing organizations
+type TransactionRequest struct {
+	contract *Contract
+	name     string
+	opts     proposalOptions
+}
+
+// CreateTransaction creates a request to invoke the given transaction function with the given
+// proposal options. The request may be evaluated or submitted more than once.
+//  Parameters:
+//  name is the name of the transaction function.
+//  options specifies the proposal options (see WithTransient and WithEndorsingOrganizations).
+//
+//  Returns:
+//  The transaction request.
+func (c *Contract) CreateTransaction(name string, options ...ProposalOption) (*TransactionRequest, error) {
+	opts := proposalOptions{}
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return nil, errors.WithMessage(err, "invalid proposal option")
+		}
+	}
+
+	return &TransactionRequest{
+		contract: c,
+		name:     name,
+		opts:     opts,
+	}, nil
+}
+
+// Name returns the name of the transaction function
+func (r *TransactionRequest) Name() string {
+	return r.name
+}
+
+// Evaluate evaluates the transaction function with the given arguments (see Contract.EvaluateTransaction)
+func (r *TransactionRequest) Evaluate(args ...string) ([]byte, error) {
+	return r.contract.evaluate(r.name, args, r.opts)
+}
+
+// Submit submits the transaction function with the given arguments (see Contract.SubmitTransaction)
+func (r *TransactionRequest) Submit(args ...string) ([]byte, error) {
+	return r.contract.submit(r.name, args, r.opts)
+}
+
+// SubmitAsync submits the transaction function with the given arguments without waiting for
+// the transaction to be committed (see Contract.SubmitAsync)
+func (r *TransactionRequest) SubmitAsync(args ...string) ([]byte, *Commit, error) {
+	return r.contract.submitAsync(r.name, args, r.opts)
+}
+
+// NewProposal creates a proposal to invoke the transaction function with the given arguments,
+// which is signed outside of the SDK (see Contract.NewProposal)
+func (r *TransactionRequest) NewProposal(args ...string) (*Proposal, error) {
+	return r.contract.newProposal(r.name, args, r.opts)
+}
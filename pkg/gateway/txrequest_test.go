@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionRequestTransient(t *testing.T) {
+	client := &mockChannelClient{payload: []byte("result")}
+	contract := newTestNetwork(client).GetContract("mycc")
+
+	transient := map[string][]byte{"asset": []byte("secret")}
+	request, err := contract.CreateTransaction("createAsset", WithTransient(transient))
+	require.NoError(t, err)
+	assert.Equal(t, "createAsset", request.Name())
+
+	result, err := request.Submit("asset1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("result"), result)
+	assert.Equal(t, transient, client.execute.TransientMap)
+
+	_, err = request.Evaluate("asset1")
+	require.NoError(t, err)
+	assert.Equal(t, transient, client.query.TransientMap)
+
+	_, err = contract.SubmitTransaction("createAsset")
+	require.NoError(t, err)
+	assert.Nil(t, client.execute.TransientMap)
+}
+
+func TestTransactionRequestEndorsingOrganizations(t *testing.T) {
+	_, err := newTestNetwork(&mockChannelClient{}).GetContract("mycc").CreateTransaction("createAsset", WithEndorsingOrganizations())
+	assert.Error(t, err, "expecting error for missing organizations")
+
+	client := &mockChannelClient{payload: []byte("result")}
+	network := newOfflineTestNetwork(nil)
+	network.client = client
+	contract := network.GetContract("mycc")
+
+	request, err := contract.CreateTransaction("createAsset", WithEndorsingOrganizations("Org1MSP"))
+	require.NoError(t, err)
+
+	_, err = request.Submit("asset1")
+	require.NoError(t, err)
+	assert.Equal(t, "createAsset", client.execute.Fcn)
+}
+
+func TestOfflineProposalEndorsingOrganizations(t *testing.T) {
+	peer1 := &mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockMSP: "Org1MSP", Payload: []byte("result"), Status: 200}
+	peer2 := &mocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockMSP: "Org2MSP", Payload: []byte("result"), Status: 200}
+
+	contract := newOfflineTestNetwork([]fab.Peer{peer1, peer2}).GetContract("mycc")
+
+	request, err := contract.CreateTransaction("createAsset", WithEndorsingOrganizations("Org2MSP"), WithTransient(map[string][]byte{"k": []byte("v")}))
+	require.NoError(t, err)
+
+	proposal, err := request.NewProposal("asset1")
+	require.NoError(t, err)
+
+	_, err = proposal.Endorse([]byte("signature"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, peer1.ProcessProposalCalls)
+	assert.Equal(t, 1, peer2.ProcessProposalCalls)
+
+	request, err = contract.CreateTransaction("createAsset", WithEndorsingOrganizations("Org3MSP"))
+	require.NoError(t, err)
+	proposal, err = request.NewProposal("asset1")
+	require.NoError(t, err)
+	_, err = proposal.Endorse([]byte("signature"))
+	assert.Error(t, err, "expecting error for organization without peers")
+}
@@ -0,0 +1,418 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultVaultMount = "secret"
+	vaultTokenHeader  = "X-Vault-Token"
+	vaultIdentityKey  = "identity"
+
+	// the token is renewed (or a new token is obtained) when it's due to expire within this period
+	vaultTokenRenewalMargin = 30 * time.Second
+)
+
+// VaultWalletOption describes a functional parameter for the NewVaultWallet function
+type VaultWalletOption func(*vaultWalletStore)
+
+// WithVaultHTTPClient sets the HTTP client used to access Vault (e.g. to configure TLS)
+func WithVaultHTTPClient(client *http.Client) VaultWalletOption {
+	return func(s *vaultWalletStore) {
+		s.client = client
+	}
+}
+
+// WithVaultMount sets the mount path of the KV version 2 secrets engine (secret by default)
+func WithVaultMount(mount string) VaultWalletOption {
+	return func(s *vaultWalletStore) {
+		s.mount = strings.Trim(mount, "/")
+	}
+}
+
+// WithVaultToken authenticates with the given Vault token. The token's TTL is looked up when the
+// wallet is created and a renewable token is renewed whenever it's due to expire. A token that
+// isn't renewable can't be used once it has expired.
+func WithVaultToken(token string) VaultWalletOption {
+	return func(s *vaultWalletStore) {
+		s.token = token
+	}
+}
+
+// WithVaultAppRole authenticates using the AppRole auth method. A new token is
+// obtained whenever the current token is due to expire and can't be renewed.
+func WithVaultAppRole(roleID, secretID string) VaultWalletOption {
+	return func(s *vaultWalletStore) {
+		s.roleID = roleID
+		s.secretID = secretID
+	}
+}
+
+type vaultWalletStore struct {
+	address  string
+	mount    string
+	path     string
+	client   *http.Client
+	roleID   string
+	secretID string
+
+	mutex     sync.Mutex
+	token     string
+	expiry    time.Time // zero if the token doesn't expire
+	renewable bool
+}
+
+type vaultAuthResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int64  `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+// NewVaultWallet creates an instance of a wallet, backed by the KV version 2 secrets engine of
+// HashiCorp Vault. Each identity is stored in a separate secret at <path>/<label>, so access to
+// individual identities may be controlled with Vault policies. Private keys are never written to disk.
+//  Parameters:
+//  address specifies the address of the Vault server (e.g. https://vault:8200).
+//  path specifies the path, relative to the secrets engine mount, under which identities are stored.
+//
+//  Returns:
+//  A Wallet object.
+func NewVaultWallet(address, path string, options ...VaultWalletOption) (*Wallet, error) {
+	s := &vaultWalletStore{
+		address: strings.TrimSuffix(address, "/"),
+		mount:   defaultVaultMount,
+		path:    strings.Trim(path, "/"),
+		client:  http.DefaultClient,
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+
+	if s.path == "" {
+		return nil, errors.New("vault path is empty")
+	}
+	if s.token == "" && s.roleID == "" {
+		return nil, errors.New("either a Vault token or AppRole credentials must be provided")
+	}
+
+	if s.roleID != "" {
+		if err := s.login(); err != nil {
+			return nil, err
+		}
+	} else if err := s.lookupToken(); err != nil {
+		return nil, err
+	}
+
+	return NewWallet(s), nil
+}
+
+// Put an identity into the wallet.
+func (s *vaultWalletStore) Put(label string, content []byte) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{vaultIdentityKey: string(content)},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal Vault secret")
+	}
+
+	resp, err := s.do(http.MethodPost, s.secretURL("data", label), body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return vaultError(resp, "failed to store identity ["+label+"]")
+	}
+	return nil
+}
+
+// Get an identity from the wallet.
+func (s *vaultWalletStore) Get(label string) ([]byte, error) {
+	resp, err := s.do(http.MethodGet, s.secretURL("data", label), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrIdentityNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, vaultError(resp, "failed to get identity ["+label+"]")
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Vault response")
+	}
+
+	content, ok := secret.Data.Data[vaultIdentityKey]
+	if !ok {
+		// the latest version of the secret was deleted
+		return nil, ErrIdentityNotFound
+	}
+	return []byte(content), nil
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+func (s *vaultWalletStore) Remove(label string) error {
+	resp, err := s.do(http.MethodDelete, s.secretURL("metadata", label), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return vaultError(resp, "failed to remove identity ["+label+"]")
+	}
+	return nil
+}
+
+// Exists tests the existence of an identity in the wallet.
+func (s *vaultWalletStore) Exists(label string) bool {
+	_, err := s.Get(label)
+	return err == nil
+}
+
+// List all of the labels in the wallet.
+func (s *vaultWalletStore) List() ([]string, error) {
+	resp, err := s.do("LIST", s.address+"/v1/"+s.mount+"/metadata/"+s.path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, vaultError(resp, "failed to list identities")
+	}
+
+	var list struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Vault response")
+	}
+
+	var labels []string
+	for _, key := range list.Data.Keys {
+		// keys ending with a slash are sub-paths
+		if !strings.HasSuffix(key, "/") {
+			labels = append(labels, key)
+		}
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
+
+func (s *vaultWalletStore) secretURL(kind, label string) string {
+	return s.address + "/v1/" + s.mount + "/" + kind + "/" + s.path + "/" + url.PathEscape(label)
+}
+
+// do sends a request to Vault. If the request is rejected because the token has
+// expired or was revoked, a new token is obtained and the request is retried once.
+func (s *vaultWalletStore) do(method, reqURL string, body []byte) (*http.Response, error) {
+	token, err := s.currentToken()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.send(method, reqURL, token, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusForbidden && s.roleID != "" {
+		resp.Body.Close()
+
+		s.mutex.Lock()
+		err = s.loginLocked()
+		token = s.token
+		s.mutex.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		return s.send(method, reqURL, token, body)
+	}
+
+	return resp, nil
+}
+
+func (s *vaultWalletStore) send(method, reqURL, token string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, reqURL, reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Vault request")
+	}
+	if token != "" {
+		req.Header.Set(vaultTokenHeader, token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Vault request %s failed", method)
+	}
+	return resp, nil
+}
+
+// currentToken returns a valid token, renewing the token or obtaining a new one if
+// the current token's lease is about to expire
+func (s *vaultWalletStore) currentToken() (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.expiry.IsZero() || time.Now().Add(vaultTokenRenewalMargin).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	if s.renewable {
+		err := s.renewLocked()
+		if err == nil {
+			return s.token, nil
+		}
+		if s.roleID == "" {
+			return "", err
+		}
+	}
+
+	if s.roleID == "" {
+		return "", errors.New("Vault token has expired")
+	}
+
+	if err := s.loginLocked(); err != nil {
+		return "", err
+	}
+	return s.token, nil
+}
+
+func (s *vaultWalletStore) login() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.loginLocked()
+}
+
+func (s *vaultWalletStore) loginLocked() error {
+	body, err := json.Marshal(map[string]string{"role_id": s.roleID, "secret_id": s.secretID})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal AppRole login request")
+	}
+
+	resp, err := s.send(http.MethodPost, s.address+"/v1/auth/approle/login", "", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return vaultError(resp, "Vault AppRole login failed")
+	}
+	return s.updateToken(resp)
+}
+
+// lookupToken looks up the TTL of the given token so that it's renewed before it expires
+func (s *vaultWalletStore) lookupToken() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	resp, err := s.send(http.MethodGet, s.address+"/v1/auth/token/lookup-self", s.token, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return vaultError(resp, "Vault token lookup failed")
+	}
+
+	var lookupResp struct {
+		Data struct {
+			TTL       int64 `json:"ttl"`
+			Renewable bool  `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&lookupResp); err != nil {
+		return errors.Wrap(err, "failed to decode Vault token lookup response")
+	}
+
+	s.renewable = lookupResp.Data.Renewable
+	if lookupResp.Data.TTL > 0 {
+		s.expiry = time.Now().Add(time.Duration(lookupResp.Data.TTL) * time.Second)
+	} else {
+		s.expiry = time.Time{}
+	}
+	return nil
+}
+
+func (s *vaultWalletStore) renewLocked() error {
+	resp, err := s.send(http.MethodPost, s.address+"/v1/auth/token/renew-self", s.token, []byte("{}"))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return vaultError(resp, "Vault token renewal failed")
+	}
+	return s.updateToken(resp)
+}
+
+func (s *vaultWalletStore) updateToken(resp *http.Response) error {
+	authResp := vaultAuthResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return errors.Wrap(err, "failed to decode Vault auth response")
+	}
+	if authResp.Auth.ClientToken == "" {
+		return errors.New("Vault auth response does not contain a token")
+	}
+
+	s.token = authResp.Auth.ClientToken
+	s.renewable = authResp.Auth.Renewable
+	if authResp.Auth.LeaseDuration > 0 {
+		s.expiry = time.Now().Add(time.Duration(authResp.Auth.LeaseDuration) * time.Second)
+	} else {
+		s.expiry = time.Time{}
+	}
+	return nil
+}
+
+func vaultError(resp *http.Response, msg string) error {
+	body, _ := ioutil.ReadAll(resp.Body) //nolint
+	return errors.Errorf("%s: Vault returned status %d: %s", msg, resp.StatusCode, strings.TrimSpace(string(body)))
+}
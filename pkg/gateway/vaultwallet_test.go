@@ -0,0 +1,227 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultWallet(t *testing.T) {
+	vault := newMockVault()
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	wallet, err := NewVaultWallet(server.URL, "fabric/wallet", WithVaultToken("root"))
+	require.NoError(t, err)
+
+	labels, err := wallet.List()
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+
+	assert.False(t, wallet.Exists("user1"))
+	_, err = wallet.Get("user1")
+	assert.Equal(t, ErrIdentityNotFound, err)
+
+	require.NoError(t, wallet.PutX509("user1", "Org1MSP", testCert, testKey))
+	require.NoError(t, wallet.PutX509("user2", "Org2MSP", testCert, testKey))
+	assert.True(t, wallet.Exists("user1"))
+
+	id, err := wallet.Get("user1")
+	require.NoError(t, err)
+	assert.Equal(t, "Org1MSP", id.mspID())
+	assert.Equal(t, testKey, id.(*X509Identity).Key())
+
+	labels, err = wallet.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2"}, labels)
+
+	require.NoError(t, wallet.Remove("user1"))
+	require.NoError(t, wallet.Remove("user1"))
+	assert.False(t, wallet.Exists("user1"))
+
+	_, ok := vault.secrets["fabric/wallet/user2"]
+	assert.True(t, ok, "expecting identity to be stored at its own path")
+
+	_, err = NewVaultWallet(server.URL, "fabric/wallet", WithVaultToken("invalid"))
+	assert.Error(t, err, "expecting error for invalid token")
+}
+
+func TestVaultWalletTokenRenewal(t *testing.T) {
+	vault := newMockVault()
+	vault.tokens["periodic"] = true
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	// The root token doesn't expire so it's never renewed
+	wallet, err := NewVaultWallet(server.URL, "wallet", WithVaultToken("root"))
+	require.NoError(t, err)
+	require.NoError(t, wallet.PutX509("user1", "Org1MSP", testCert, testKey))
+	assert.Equal(t, 0, vault.renewals)
+
+	// The token expires within the renewal margin so it's renewed before each request
+	wallet, err = NewVaultWallet(server.URL, "wallet", WithVaultToken("periodic"))
+	require.NoError(t, err)
+	assert.True(t, wallet.Exists("user1"))
+	assert.Equal(t, 1, vault.renewals)
+}
+
+func TestVaultWalletAppRole(t *testing.T) {
+	vault := newMockVault()
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	_, err := NewVaultWallet(server.URL, "wallet", WithVaultAppRole("role", "invalid"))
+	assert.Error(t, err)
+
+	wallet, err := NewVaultWallet(server.URL, "wallet", WithVaultAppRole("role", "secret"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, vault.logins)
+
+	// The token expires within the renewal margin so it's renewed before each request
+	require.NoError(t, wallet.PutX509("user1", "Org1MSP", testCert, testKey))
+	assert.Equal(t, 1, vault.renewals)
+	assert.True(t, wallet.Exists("user1"))
+	assert.Equal(t, 2, vault.renewals)
+
+	// A revoked token results in a new login
+	vault.revokeTokens()
+	assert.True(t, wallet.Exists("user1"))
+	assert.Equal(t, 2, vault.logins)
+}
+
+func TestVaultWalletInvalidOptions(t *testing.T) {
+	_, err := NewVaultWallet("http://localhost:8200", "wallet")
+	assert.Error(t, err)
+
+	_, err = NewVaultWallet("http://localhost:8200", "", WithVaultToken("root"))
+	assert.Error(t, err)
+}
+
+// mockVault implements the subset of the Vault API used by the wallet
+type mockVault struct {
+	mutex    sync.Mutex
+	tokens   map[string]bool
+	secrets  map[string]string
+	logins   int
+	renewals int
+}
+
+func newMockVault() *mockVault {
+	return &mockVault{
+		tokens:  map[string]bool{"root": true},
+		secrets: make(map[string]string),
+	}
+}
+
+func (m *mockVault) revokeTokens() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.tokens = make(map[string]bool)
+}
+
+func (m *mockVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/auth/approle/login":
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req) //nolint
+		if req["role_id"] != "role" || req["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.logins++
+		m.writeAuth(w, fmt.Sprintf("login-%d", m.logins))
+		return
+	case "/v1/auth/token/lookup-self":
+		token := r.Header.Get(vaultTokenHeader)
+		if !m.tokens[token] {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var ttl int
+		if token != "root" {
+			ttl = 10
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{ //nolint
+			"data": map[string]interface{}{"ttl": ttl, "renewable": ttl > 0},
+		})
+		return
+	case "/v1/auth/token/renew-self":
+		if !m.tokens[r.Header.Get(vaultTokenHeader)] {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		m.renewals++
+		m.writeAuth(w, r.Header.Get(vaultTokenHeader))
+		return
+	}
+
+	if !m.tokens[r.Header.Get(vaultTokenHeader)] {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		path := strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")
+		if r.Method == http.MethodPost {
+			var req struct {
+				Data map[string]string `json:"data"`
+			}
+			json.NewDecoder(r.Body).Decode(&req) //nolint
+			m.secrets[path] = req.Data[vaultIdentityKey]
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		secret, ok := m.secrets[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{ //nolint
+			"data": map[string]interface{}{"data": map[string]string{vaultIdentityKey: secret}},
+		})
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/"):
+		path := strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/")
+		if r.Method == http.MethodDelete {
+			delete(m.secrets, path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		var keys []string
+		for p := range m.secrets {
+			if strings.HasPrefix(p, path+"/") {
+				keys = append(keys, strings.TrimPrefix(p, path+"/"))
+			}
+		}
+		if len(keys) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"keys": keys}}) //nolint
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (m *mockVault) writeAuth(w http.ResponseWriter, token string) {
+	m.tokens[token] = true
+	json.NewEncoder(w).Encode(map[string]interface{}{ //nolint
+		"auth": map[string]interface{}{"client_token": token, "lease_duration": 10, "renewable": true},
+	})
+}
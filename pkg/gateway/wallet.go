@@ -0,0 +1,134 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"encoding/pem"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+// WalletStore is the interface for implementations that provide backing storage for identities in a wallet
+type WalletStore interface {
+	Put(label string, content []byte) error
+	Get(label string) ([]byte, error)
+	List() ([]string, error)
+	Exists(label string) bool
+	Remove(label string) error
+}
+
+// A Wallet stores identity information used to connect to a Hyperledger Fabric network.
+// Instances are created using factory methods on the implementing objects.
+type Wallet struct {
+	store WalletStore
+}
+
+// ErrIdentityNotFound is returned when an identity doesn't exist in a wallet
+var ErrIdentityNotFound = errors.New("identity not found")
+
+// NewWallet creates a wallet backed by the given store
+func NewWallet(store WalletStore) *Wallet {
+	return &Wallet{store: store}
+}
+
+// Put an identity into the wallet
+//  Parameters:
+//  label specifies the name to be associated with the identity.
+//  id specifies the identity to store in the wallet.
+func (w *Wallet) Put(label string, id Identity) error {
+	if label == "" {
+		return errors.New("label is empty")
+	}
+	if id == nil {
+		return errors.New("identity is nil")
+	}
+
+	content, err := MarshalIdentity(id)
+	if err != nil {
+		return err
+	}
+
+	return w.store.Put(label, content)
+}
+
+// PutX509 puts an X509 identity, created from the given PEM encoded certificate and
+// private key, into the wallet. This is a convenient way of seeding a wallet.
+//  Parameters:
+//  label specifies the name to be associated with the identity.
+//  mspID specifies the MSP ID of the identity.
+//  cert specifies the PEM encoded certificate of the identity.
+//  key specifies the PEM encoded private key of the identity.
+func (w *Wallet) PutX509(label, mspID, cert, key string) error {
+	if block, _ := pem.Decode([]byte(cert)); block == nil {
+		return errors.Errorf("invalid PEM certificate for [%s]", label)
+	}
+	if block, _ := pem.Decode([]byte(key)); block == nil {
+		return errors.Errorf("invalid PEM private key for [%s]", label)
+	}
+	return w.Put(label, NewX509Identity(mspID, cert, key))
+}
+
+// Get an identity from the wallet. ErrIdentityNotFound is returned if the identity doesn't exist.
+//  Parameters:
+//  label specifies the name associated with the identity.
+//
+//  Returns:
+//  The identity object.
+func (w *Wallet) Get(label string) (Identity, error) {
+	content, err := w.store.Get(label)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := UnmarshalIdentity(content)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load identity ["+label+"]")
+	}
+	return id, nil
+}
+
+// SigningIdentity returns a signing identity for the given identity in the wallet. The private key
+// of an X509 identity is imported into the crypto suite as a temporary key. The private key of an
+// HSM X509 identity is never exported from the HSM; instead it's looked up in the crypto suite,
+// which must be configured for PKCS#11, using the subject key identifier of the certificate.
+//  Parameters:
+//  label specifies the name associated with the identity.
+//  cryptoSuite specifies the crypto suite used to import or look up the private key.
+//
+//  Returns:
+//  The signing identity.
+func (w *Wallet) SigningIdentity(label string, cryptoSuite core.CryptoSuite) (msp.SigningIdentity, error) {
+	id, err := w.Get(label)
+	if err != nil {
+		return nil, err
+	}
+	return newSigningIdentity(label, id, cryptoSuite)
+}
+
+// Remove an identity from the wallet. If the identity does not exist, this method does nothing.
+//  Parameters:
+//  label specifies the name associated with the identity.
+func (w *Wallet) Remove(label string) error {
+	return w.store.Remove(label)
+}
+
+// Exists tests whether the wallet contains an identity for the given label.
+//  Parameters:
+//  label specifies the name associated with the identity.
+//
+//  Returns:
+//  True if the named identity is in the wallet.
+func (w *Wallet) Exists(label string) bool {
+	return w.store.Exists(label)
+}
+
+// List returns the labels of all identities in the wallet.
+func (w *Wallet) List() ([]string, error) {
+	return w.store.List()
+}
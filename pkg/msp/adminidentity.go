@@ -0,0 +1,163 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	fabricmsp "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// DefaultAdminUser is the name under which the admin of an organization is looked up if
+	// no name is given. It is the name of the admin users generated by cryptogen.
+	DefaultAdminUser = "Admin"
+
+	adminCertsDir     = "admincerts"
+	mspConfigFileName = "config.yaml"
+)
+
+// GetAdminSigningIdentity returns the signing identity of the admin of the manager's organization.
+//
+// The admin is looked up under each of the given names in turn (DefaultAdminUser if none is given),
+// the same way as GetSigningIdentity does: in the user store, the embedded users of the
+// organization config and the organization's MSP directories (cryptoPath). msp.ErrUserNotFound is
+// returned if none of the names is found.
+//
+// The user that is found is validated against the MSP directory of the organization, if it exists. It is
+// located from the cryptoPath of the organization like the MSP directories of cryptogen, i.e. the
+// directory "msp" of the organization's directory that contains the "users" directory. If the MSP has
+// admin certificates (admincerts), the user's certificate must be one of them. If NodeOUs are enabled
+// in the MSP config (config.yaml), the user's certificate must carry the client OU and must not carry
+// the peer OU, since Fabric rejects identities that are neither clients nor peers and an admin signs
+// as a client. Only the OU names are compared; the certificates of the OU identifiers aren't checked.
+func (mgr *IdentityManager) GetAdminSigningIdentity(adminIDs ...string) (msp.SigningIdentity, error) {
+	if len(adminIDs) == 0 {
+		adminIDs = []string{DefaultAdminUser}
+	}
+
+	for _, id := range adminIDs {
+		user, err := mgr.GetUser(id)
+		if err != nil {
+			if err == msp.ErrUserNotFound {
+				continue
+			}
+			return nil, errors.WithMessage(err, "loading admin user failed")
+		}
+		if err := mgr.validateAdmin(user); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("user [%s] is not an admin of org [%s]", id, mgr.orgName))
+		}
+		return user, nil
+	}
+	return nil, msp.ErrUserNotFound
+}
+
+func (mgr *IdentityManager) validateAdmin(user *User) error {
+	if mgr.cryptoPathTemplate == "" {
+		return nil
+	}
+	// The cryptoPath is the MSP directory of a user: <org dir>/users/<user dir>/msp
+	orgMSPDir := filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(mgr.cryptoPathTemplate))), "msp")
+	if _, err := os.Stat(orgMSPDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "reading MSP directory failed")
+	}
+	return validateAdminMSPDir(orgMSPDir, user.enrollmentCertificate)
+}
+
+// validateAdminMSPDir validates the certificate of an admin against the given MSP directory
+func validateAdminMSPDir(mspDir string, certPEM []byte) error {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return errors.New("enrollment certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrap(err, "parsing enrollment certificate failed")
+	}
+
+	if err := validateAdminCerts(filepath.Join(mspDir, adminCertsDir), cert); err != nil {
+		return err
+	}
+	return validateNodeOUs(filepath.Join(mspDir, mspConfigFileName), cert)
+}
+
+func validateAdminCerts(dir string, cert *x509.Certificate) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "reading admin certificates failed")
+	}
+
+	var adminCerts int
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return errors.Wrap(err, "reading admin certificate failed")
+		}
+		for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+			adminCerts++
+			if bytes.Equal(block.Bytes, cert.Raw) {
+				return nil
+			}
+		}
+	}
+	if adminCerts == 0 {
+		return nil
+	}
+	return errors.New("certificate is not one of the admin certificates of the MSP")
+}
+
+func validateNodeOUs(configFile string, cert *x509.Certificate) error {
+	data, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "reading MSP config failed")
+	}
+	config := fabricmsp.Configuration{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return errors.Wrap(err, "parsing MSP config failed")
+	}
+	if config.NodeOUs == nil || !config.NodeOUs.Enable {
+		return nil
+	}
+
+	if ou := config.NodeOUs.PeerOUIdentifier; ou != nil && hasOU(cert, ou.OrganizationalUnitIdentifier) {
+		return errors.Errorf("certificate carries the peer OU [%s]", ou.OrganizationalUnitIdentifier)
+	}
+	if ou := config.NodeOUs.ClientOUIdentifier; ou != nil && !hasOU(cert, ou.OrganizationalUnitIdentifier) {
+		return errors.Errorf("certificate doesn't carry the client OU [%s]", ou.OrganizationalUnitIdentifier)
+	}
+	return nil
+}
+
+func hasOU(cert *x509.Certificate, ou string) bool {
+	for _, certOU := range cert.Subject.OrganizationalUnit {
+		if certOU == ou {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+)
+
+const adminCertPath = "../../test/fixtures/fabric/v1/crypto-config/peerOrganizations/org1.example.com/msp/admincerts/Admin@org1.example.com-cert.pem"
+
+func TestGetAdminSigningIdentity(t *testing.T) {
+	cryptoConfig, endpointConfig, identityConfig, _ := getConfigs(t)
+
+	cleanupTestPath(t, cryptoConfig.KeyStorePath())
+	defer cleanupTestPath(t, cryptoConfig.KeyStorePath())
+
+	cryptoSuite, err := sw.GetSuiteByConfig(cryptoConfig)
+	if err != nil {
+		t.Fatalf("Failed to setup cryptoSuite: %s", err)
+	}
+	mgr, err := NewIdentityManager(orgName, userStoreFromConfig(t, identityConfig), cryptoSuite, endpointConfig)
+	if err != nil {
+		t.Fatalf("Failed to setup credential manager: %s", err)
+	}
+
+	admin, err := mgr.GetAdminSigningIdentity()
+	if err != nil {
+		t.Fatalf("GetAdminSigningIdentity failed: %s", err)
+	}
+	if admin.Identifier().ID != DefaultAdminUser {
+		t.Fatalf("Expecting default admin, got [%s]", admin.Identifier().ID)
+	}
+
+	admin, err = mgr.GetAdminSigningIdentity("Non-Existent", "Admin")
+	if err != nil || admin.Identifier().ID != "Admin" {
+		t.Fatalf("Expecting admin to be resolved from the second name, got [%v]", err)
+	}
+
+	if _, err := mgr.GetAdminSigningIdentity("Non-Existent"); err != msp.ErrUserNotFound {
+		t.Fatalf("Expecting ErrUserNotFound, got [%v]", err)
+	}
+
+	if _, err := mgr.GetAdminSigningIdentity("User1"); err == nil {
+		t.Fatal("Expecting error for user that isn't an admin of the MSP")
+	}
+}
+
+func TestValidateAdminMSPDir(t *testing.T) {
+	mspDir, err := ioutil.TempDir("", "adminmsp")
+	if err != nil {
+		t.Fatalf("TempDir failed: %s", err)
+	}
+	defer os.RemoveAll(mspDir)
+
+	if err := validateAdminMSPDir(mspDir, []byte(testCert)); err != nil {
+		t.Fatalf("Expecting empty MSP directory to be valid: %s", err)
+	}
+	if err := validateAdminMSPDir(mspDir, []byte("not a cert")); err == nil {
+		t.Fatal("Expecting error for invalid certificate")
+	}
+
+	// Admin certificates
+	adminCert, err := ioutil.ReadFile(adminCertPath)
+	if err != nil {
+		t.Fatalf("Reading admin cert failed: %s", err)
+	}
+	writeMSPFile(t, filepath.Join(mspDir, adminCertsDir, "admin-cert.pem"), adminCert)
+	if err := validateAdminMSPDir(mspDir, []byte(testCert)); err == nil {
+		t.Fatal("Expecting error for certificate that isn't an admin certificate")
+	}
+	writeMSPFile(t, filepath.Join(mspDir, adminCertsDir, "user1-cert.pem"), []byte(testCert))
+	if err := validateAdminMSPDir(mspDir, []byte(testCert)); err != nil {
+		t.Fatalf("Expecting admin certificate to be valid: %s", err)
+	}
+
+	// NodeOUs (the test certificate has no OU)
+	writeMSPFile(t, filepath.Join(mspDir, mspConfigFileName), []byte(`
+NodeOUs:
+  Enable: false
+  ClientOUIdentifier:
+    OrganizationalUnitIdentifier: client
+`))
+	if err := validateAdminMSPDir(mspDir, []byte(testCert)); err != nil {
+		t.Fatalf("Expecting NodeOUs not to be checked when disabled: %s", err)
+	}
+
+	writeMSPFile(t, filepath.Join(mspDir, mspConfigFileName), []byte(`
+NodeOUs:
+  Enable: true
+  PeerOUIdentifier:
+    OrganizationalUnitIdentifier: peer
+`))
+	if err := validateAdminMSPDir(mspDir, []byte(testCert)); err != nil {
+		t.Fatalf("Expecting certificate without peer OU to be valid: %s", err)
+	}
+
+	writeMSPFile(t, filepath.Join(mspDir, mspConfigFileName), []byte(`
+NodeOUs:
+  Enable: true
+  ClientOUIdentifier:
+    OrganizationalUnitIdentifier: client
+`))
+	if err := validateAdminMSPDir(mspDir, []byte(testCert)); err == nil {
+		t.Fatal("Expecting error for certificate without client OU")
+	}
+}
+
+func writeMSPFile(t *testing.T, file string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatalf("MkdirAll failed: %s", err)
+	}
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatalf("WriteFile failed: %s", err)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+)
+
+// DefaultUserCacheTTL is the time that a user is cached by CachedUserStore if no TTL is configured
+const DefaultUserCacheTTL = 5 * time.Minute
+
+// CachedUserStore is an in-memory write-through cache in front of another user store, which avoids
+// a round trip to a remote user store (e.g. Redis or CouchDB) on each identity lookup. Users are
+// stored in the underlying store before they're cached. A cached user is loaded again from the
+// underlying store once its TTL has elapsed, so that users that were re-enrolled by another
+// instance of the application are eventually picked up.
+//
+// This component has been designed to be safe for concurrency.
+type CachedUserStore struct {
+	store msp.UserStore
+	ttl   time.Duration
+	clock clock.Clock
+	lock  sync.RWMutex
+	users map[msp.IdentityIdentifier]*cachedUser
+}
+
+type cachedUser struct {
+	userData *msp.UserData
+	expiry   time.Time
+}
+
+type userDeleter interface {
+	Delete(key msp.IdentityIdentifier) error
+}
+
+// NewCachedUserStore returns a cache of the given user store. Users are cached for the given TTL
+// (DefaultUserCacheTTL if zero).
+func NewCachedUserStore(store msp.UserStore, ttl time.Duration) *CachedUserStore {
+	if ttl <= 0 {
+		ttl = DefaultUserCacheTTL
+	}
+	return &CachedUserStore{
+		store: store,
+		ttl:   ttl,
+		clock: clock.Real,
+		users: make(map[msp.IdentityIdentifier]*cachedUser),
+	}
+}
+
+// Load returns the cached user or loads it from the underlying store
+func (s *CachedUserStore) Load(key msp.IdentityIdentifier) (*msp.UserData, error) {
+	s.lock.RLock()
+	cached, ok := s.users[key]
+	s.lock.RUnlock()
+
+	if ok && s.clock.Now().Before(cached.expiry) {
+		return cached.userData, nil
+	}
+
+	userData, err := s.store.Load(key)
+	if err != nil {
+		if err == msp.ErrUserNotFound {
+			s.evict(key)
+		}
+		return nil, err
+	}
+	s.cache(key, userData)
+	return userData, nil
+}
+
+// Store stores the user in the underlying store and caches it
+func (s *CachedUserStore) Store(userData *msp.UserData) error {
+	key := msp.IdentityIdentifier{ID: userData.ID, MSPID: userData.MSPID}
+	if err := s.store.Store(userData); err != nil {
+		// The stored user is in an unknown state
+		s.evict(key)
+		return err
+	}
+	s.cache(key, userData)
+	return nil
+}
+
+// Delete deletes the user from the underlying store (if it supports deletion) and from the cache
+func (s *CachedUserStore) Delete(key msp.IdentityIdentifier) error {
+	s.evict(key)
+	if d, ok := s.store.(userDeleter); ok {
+		return d.Delete(key)
+	}
+	return nil
+}
+
+func (s *CachedUserStore) cache(key msp.IdentityIdentifier, userData *msp.UserData) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.users[key] = &cachedUser{userData: userData, expiry: s.clock.Now().Add(s.ttl)}
+}
+
+func (s *CachedUserStore) evict(key msp.IdentityIdentifier) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.users, key)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+// countingUserStore counts the loads of the underlying store
+type countingUserStore struct {
+	*MemoryUserStore
+	loads    int
+	storeErr error
+}
+
+func (s *countingUserStore) Load(key msp.IdentityIdentifier) (*msp.UserData, error) {
+	s.loads++
+	return s.MemoryUserStore.Load(key)
+}
+
+func (s *countingUserStore) Store(user *msp.UserData) error {
+	if s.storeErr != nil {
+		return s.storeErr
+	}
+	return s.MemoryUserStore.Store(user)
+}
+
+func TestCachedUserStore(t *testing.T) {
+	fakeClock := clock.NewFake(time.Now())
+	backend := &countingUserStore{MemoryUserStore: NewMemoryUserStore()}
+	store := NewCachedUserStore(backend, time.Minute)
+	store.clock = fakeClock
+	key := msp.IdentityIdentifier{ID: "user1", MSPID: "Org1"}
+
+	if _, err := store.Load(key); err != msp.ErrUserNotFound {
+		t.Fatalf("Expecting ErrUserNotFound, got [%v]", err)
+	}
+
+	if err := store.Store(&msp.UserData{ID: "user1", MSPID: "Org1", EnrollmentCertificate: []byte(testCert1)}); err != nil {
+		t.Fatalf("Store failed [%s]", err)
+	}
+	if _, err := backend.MemoryUserStore.Load(key); err != nil {
+		t.Fatalf("Expecting user to be written through to the underlying store [%s]", err)
+	}
+
+	backend.loads = 0
+	for i := 0; i < 3; i++ {
+		user, err := store.Load(key)
+		if err != nil {
+			t.Fatalf("Load failed [%s]", err)
+		}
+		if string(user.EnrollmentCertificate) != testCert1 {
+			t.Fatal("Unexpected enrollment certificate")
+		}
+	}
+	if backend.loads != 0 {
+		t.Fatalf("Expecting user to be loaded from the cache, got %d loads", backend.loads)
+	}
+
+	// The user is re-enrolled by another instance
+	if err := backend.MemoryUserStore.Store(&msp.UserData{ID: "user1", MSPID: "Org1", EnrollmentCertificate: []byte(testCert2)}); err != nil {
+		t.Fatalf("Store failed [%s]", err)
+	}
+	fakeClock.Advance(2 * time.Minute)
+	user, err := store.Load(key)
+	if err != nil {
+		t.Fatalf("Load failed [%s]", err)
+	}
+	if string(user.EnrollmentCertificate) != testCert2 || backend.loads != 1 {
+		t.Fatal("Expecting user to be reloaded once the TTL has elapsed")
+	}
+
+	backend.storeErr = errors.New("injected error")
+	if err := store.Store(&msp.UserData{ID: "user1", MSPID: "Org1", EnrollmentCertificate: []byte(testCert1)}); err == nil {
+		t.Fatal("Expecting error of the underlying store")
+	}
+	if _, err := store.Load(key); err != nil || backend.loads != 2 {
+		t.Fatal("Expecting user to be evicted when the underlying store failed")
+	}
+}
+
+func TestNewUserStoreWithCache(t *testing.T) {
+	RegisterUserStore("counting", func(c msp.CredentialStoreType, cs core.CryptoSuite) (msp.UserStore, error) {
+		return &countingUserStore{MemoryUserStore: NewMemoryUserStore()}, nil
+	})
+
+	userStore, err := NewUserStore(msp.CredentialStoreType{Type: "counting", Cache: msp.UserCacheConfig{Enabled: true}}, nil)
+	if err != nil {
+		t.Fatalf("NewUserStore failed [%s]", err)
+	}
+	cached, ok := userStore.(*CachedUserStore)
+	if !ok {
+		t.Fatalf("Expecting cached user store, got %T", userStore)
+	}
+	if cached.ttl != DefaultUserCacheTTL {
+		t.Fatalf("Expecting default TTL, got %s", cached.ttl)
+	}
+
+	if _, err := NewUserStore(msp.CredentialStoreType{Type: RedisUserStoreType}, nil); err == nil {
+		t.Fatal("Expecting error for Redis user store without address")
+	}
+}
@@ -7,19 +7,25 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	reqContext "context"
 	"fmt"
-
+	"strconv"
 	"strings"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/opevents"
 	contextApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/readonly"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/tracing"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 	"github.com/pkg/errors"
 )
 
 var logger = logging.NewLogger("fabsdk/msp")
+var tracer = tracing.NewTracer("fabsdk/msp")
 
 // CAClientImpl implements api/msp/CAClient
 type CAClientImpl struct {
@@ -30,6 +36,8 @@ type CAClientImpl struct {
 	userStore       msp.UserStore
 	adapter         *fabricCAAdapter
 	registrar       msp.EnrollCredentials
+	reqCtx          reqContext.Context
+	providers       readonly.Provider // carries the read-only mode of the SDK
 }
 
 // NewCAClient creates a new CA CAClient instance
@@ -70,7 +78,7 @@ func NewCAClient(orgName string, ctx contextApi.Client) (*CAClientImpl, error) {
 	caName := orgConfig.CertificateAuthorities[0]
 	caConfig, err = ctx.IdentityConfig().CAConfig(orgName)
 	if err == nil {
-		adapter, err = newFabricCAAdapter(orgName, ctx.CryptoSuite(), ctx.IdentityConfig())
+		adapter, err = newFabricCAAdapter(orgName, ctx.CryptoSuite(), ctx.IdentityConfig(), ctx.CATransport())
 		if err == nil {
 			registrar = caConfig.Registrar
 		} else {
@@ -93,36 +101,67 @@ func NewCAClient(orgName string, ctx contextApi.Client) (*CAClientImpl, error) {
 		userStore:       ctx.UserStore(),
 		adapter:         adapter,
 		registrar:       registrar,
+		providers:       ctx,
 	}
 	return mgr, nil
 }
 
+// WithContext returns a copy of the client whose operations are bound to the given context. The
+// requests to the CA are cancelled when the context is done (e.g. when its deadline expires), in
+// which case the operations return an error. The request ID carried by the context, if any (see
+// audit.WithRequestID), is sent to the CA.
+func (c *CAClientImpl) WithContext(ctx reqContext.Context) api.CAClient {
+	client := *c
+	client.reqCtx = ctx
+	return &client
+}
+
+// requestContext returns the context of an operation along with its request ID
+func (c *CAClientImpl) requestContext() (reqContext.Context, string) {
+	ctx := c.reqCtx
+	if ctx == nil {
+		ctx = reqContext.Background()
+	}
+	return audit.EnsureRequestID(ctx)
+}
+
 // Enroll a registered user in order to receive a signed X509 certificate.
 // A new key pair is generated for the user. The private key and the
 // enrollment certificate issued by the CA are stored in SDK stores.
 // They can be retrieved by calling IdentityManager.GetSigningIdentity().
 //
-// enrollmentID The registered ID to use for enrollment
-// enrollmentSecret The secret associated with the enrollment ID
-func (c *CAClientImpl) Enroll(enrollmentID string, enrollmentSecret string) error {
+// request holds the enrollment ID and secret of the registered user, and
+// optionally the signing profile and the CSR information (common name, hosts and key request)
+func (c *CAClientImpl) Enroll(request *api.EnrollmentRequest) error {
+	if err := readonly.Check(c.providers, "ca.Enroll"); err != nil {
+		return err
+	}
 
 	if c.adapter == nil {
 		return fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
-	if enrollmentID == "" {
+	if request == nil {
+		return errors.New("enrollment request is required")
+	}
+	if request.Name == "" {
 		return errors.New("enrollmentID is required")
 	}
-	if enrollmentSecret == "" {
+	if request.Secret == "" {
 		return errors.New("enrollmentSecret is required")
 	}
-	// TODO add attributes
-	cert, err := c.adapter.Enroll(enrollmentID, enrollmentSecret)
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Enrolling [%s] with CA of org [%s]", request.Name, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.Enroll", tracing.String("org", c.orgName), tracing.String("enrollmentID", request.Name))
+	cert, err := c.adapter.withRequest(ctx, requestID).Enroll(request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.Enroll", request.Name, map[string]string{"enrollmentID": request.Name, "profile": request.Profile}, err)
 	if err != nil {
 		return errors.Wrap(err, "enroll failed")
 	}
 	userData := &msp.UserData{
 		MSPID: c.orgMSPID,
-		ID:    enrollmentID,
+		ID:    request.Name,
 		EnrollmentCertificate: cert,
 	}
 	err = c.userStore.Store(userData)
@@ -132,12 +171,22 @@ func (c *CAClientImpl) Enroll(enrollmentID string, enrollmentSecret string) erro
 	return nil
 }
 
-// Reenroll an enrolled user in order to obtain a new signed X509 certificate
-func (c *CAClientImpl) Reenroll(enrollmentID string) error {
+// Reenroll an enrolled user in order to obtain a new signed X509 certificate.
+// The certificate is issued for the current key of the user unless request.NewKey is set,
+// in which case a new key pair is generated and stored in the crypto suite's key store.
+// The user record is replaced in the user store once the CA has issued the certificate.
+func (c *CAClientImpl) Reenroll(request *api.ReenrollmentRequest) error {
+	if err := readonly.Check(c.providers, "ca.Reenroll"); err != nil {
+		return err
+	}
 
 	if c.adapter == nil {
 		return fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
+	if request == nil {
+		return errors.New("reenrollment request is required")
+	}
+	enrollmentID := request.Name
 	if enrollmentID == "" {
 		logger.Infof("invalid re-enroll request, missing enrollmentID")
 		return errors.New("user name missing")
@@ -148,16 +197,27 @@ func (c *CAClientImpl) Reenroll(enrollmentID string) error {
 		return errors.Wrapf(err, "failed to retrieve user: %s", enrollmentID)
 	}
 
-	cert, err := c.adapter.Reenroll(user.PrivateKey(), user.EnrollmentCertificate())
-	if err != nil {
-		return errors.Wrap(err, "reenroll failed")
-	}
-	userData := &msp.UserData{
-		MSPID: c.orgMSPID,
-		ID:    user.Identifier().ID,
-		EnrollmentCertificate: cert,
-	}
-	err = c.userStore.Store(userData)
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Re-enrolling [%s] with CA of org [%s]", enrollmentID, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.Reenroll", tracing.String("org", c.orgName), tracing.String("enrollmentID", enrollmentID))
+	cert, err := c.adapter.withRequest(ctx, requestID).Reenroll(user.PrivateKey(), user.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.Reenroll", enrollmentID, map[string]string{"enrollmentID": enrollmentID, "newKey": strconv.FormatBool(request.NewKey)}, err)
+	if err == nil {
+		userData := &msp.UserData{
+			MSPID: c.orgMSPID,
+			ID:    user.Identifier().ID,
+			EnrollmentCertificate: cert,
+		}
+		err = c.userStore.Store(userData)
+	}
+	opevents.Publish(&opevents.Event{
+		Type:       opevents.Reenrolled,
+		Source:     enrollmentID,
+		Attributes: map[string]string{"org": c.orgName},
+		Err:        err,
+	})
 	if err != nil {
 		return errors.Wrap(err, "reenroll failed")
 	}
@@ -169,6 +229,9 @@ func (c *CAClientImpl) Reenroll(enrollmentID string) error {
 // request: Registration Request
 // Returns Enrolment Secret
 func (c *CAClientImpl) Register(request *api.RegistrationRequest) (string, error) {
+	if err := readonly.Check(c.providers, "ca.Register"); err != nil {
+		return "", err
+	}
 	if c.adapter == nil {
 		return "", fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -188,7 +251,13 @@ func (c *CAClientImpl) Register(request *api.RegistrationRequest) (string, error
 		return "", err
 	}
 
-	secret, err := c.adapter.Register(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Registering [%s] with CA of org [%s]", request.Name, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.Register", tracing.String("org", c.orgName), tracing.String("name", request.Name))
+	secret, err := c.adapter.withRequest(ctx, requestID).Register(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.Register", c.registrar.EnrollID, map[string]string{"name": request.Name, "type": request.Type, "affiliation": request.Affiliation}, err)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to register user")
 	}
@@ -200,6 +269,9 @@ func (c *CAClientImpl) Register(request *api.RegistrationRequest) (string, error
 // registrar: The User that is initiating the revocation
 // request: Revocation Request
 func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationResponse, error) {
+	if err := readonly.Check(c.providers, "ca.Revoke"); err != nil {
+		return nil, err
+	}
 	if c.adapter == nil {
 		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
 	}
@@ -216,13 +288,429 @@ func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationRe
 		return nil, err
 	}
 
-	resp, err := c.adapter.Revoke(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Revoking [%s] with CA of org [%s]", request.Name, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.Revoke", tracing.String("org", c.orgName), tracing.String("name", request.Name))
+	resp, err := c.adapter.withRequest(ctx, requestID).Revoke(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.Revoke", c.registrar.EnrollID, map[string]string{"name": request.Name, "serial": request.Serial, "reason": request.Reason}, err)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to revoke")
 	}
 	return resp, nil
 }
 
+// GetIdentity returns information about the requested identity
+// id: The identity to retrieve
+// caname: The name of the CA to connect to (optional)
+func (c *CAClientImpl) GetIdentity(id, caname string) (*api.IdentityResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	if id == "" {
+		return nil, errors.New("id is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Retrieving identity [%s] from CA of org [%s]", id, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.GetIdentity", tracing.String("org", c.orgName), tracing.String("id", id))
+	resp, err := c.adapter.withRequest(ctx, requestID).GetIdentity(registrar.PrivateKey(), registrar.EnrollmentCertificate(), id, caname)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.GetIdentity", c.registrar.EnrollID, map[string]string{"id": id}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get identity")
+	}
+	return resp, nil
+}
+
+// GetAllIdentities returns all identities that the registrar is authorized to see
+// caname: The name of the CA to connect to (optional)
+func (c *CAClientImpl) GetAllIdentities(caname string) ([]*api.IdentityResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Retrieving identities from CA of org [%s]", c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.GetAllIdentities", tracing.String("org", c.orgName))
+	resp, err := c.adapter.withRequest(ctx, requestID).GetAllIdentities(registrar.PrivateKey(), registrar.EnrollmentCertificate(), caname)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.GetAllIdentities", c.registrar.EnrollID, nil, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get identities")
+	}
+	return resp, nil
+}
+
+// ModifyIdentity modifies an identity registered with the Fabric CA
+// request: Identity Request
+func (c *CAClientImpl) ModifyIdentity(request *api.IdentityRequest) (*api.IdentityResponse, error) {
+	if err := readonly.Check(c.providers, "ca.ModifyIdentity"); err != nil {
+		return nil, err
+	}
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	// Validate request
+	if request == nil {
+		return nil, errors.New("identity request is required")
+	}
+	if request.ID == "" {
+		return nil, errors.New("request.ID is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Modifying identity [%s] with CA of org [%s]", request.ID, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.ModifyIdentity", tracing.String("org", c.orgName), tracing.String("id", request.ID))
+	resp, err := c.adapter.withRequest(ctx, requestID).ModifyIdentity(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.ModifyIdentity", c.registrar.EnrollID, map[string]string{"id": request.ID, "type": request.Type, "affiliation": request.Affiliation}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to modify identity")
+	}
+	return resp, nil
+}
+
+// RemoveIdentity removes an identity registered with the Fabric CA
+// request: Remove Identity Request
+func (c *CAClientImpl) RemoveIdentity(request *api.RemoveIdentityRequest) (*api.IdentityResponse, error) {
+	if err := readonly.Check(c.providers, "ca.RemoveIdentity"); err != nil {
+		return nil, err
+	}
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	// Validate request
+	if request == nil {
+		return nil, errors.New("remove identity request is required")
+	}
+	if request.ID == "" {
+		return nil, errors.New("request.ID is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Removing identity [%s] from CA of org [%s]", request.ID, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.RemoveIdentity", tracing.String("org", c.orgName), tracing.String("id", request.ID))
+	resp, err := c.adapter.withRequest(ctx, requestID).RemoveIdentity(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.RemoveIdentity", c.registrar.EnrollID, map[string]string{"id": request.ID, "force": strconv.FormatBool(request.Force)}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to remove identity")
+	}
+	return resp, nil
+}
+
+// GetAffiliation returns information about the requested affiliation
+// affiliation: The affiliation to retrieve (e.g. org1.department1)
+// caname: The name of the CA to connect to (optional)
+func (c *CAClientImpl) GetAffiliation(affiliation, caname string) (*api.AffiliationResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	if affiliation == "" {
+		return nil, errors.New("affiliation is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Retrieving affiliation [%s] from CA of org [%s]", affiliation, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.GetAffiliation", tracing.String("org", c.orgName), tracing.String("affiliation", affiliation))
+	resp, err := c.adapter.withRequest(ctx, requestID).GetAffiliation(registrar.PrivateKey(), registrar.EnrollmentCertificate(), affiliation, caname)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.GetAffiliation", c.registrar.EnrollID, map[string]string{"affiliation": affiliation}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get affiliation")
+	}
+	return resp, nil
+}
+
+// GetAllAffiliations returns all affiliations that the registrar is authorized to see
+// caname: The name of the CA to connect to (optional)
+func (c *CAClientImpl) GetAllAffiliations(caname string) (*api.AffiliationResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Retrieving affiliations from CA of org [%s]", c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.GetAllAffiliations", tracing.String("org", c.orgName))
+	resp, err := c.adapter.withRequest(ctx, requestID).GetAllAffiliations(registrar.PrivateKey(), registrar.EnrollmentCertificate(), caname)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.GetAllAffiliations", c.registrar.EnrollID, nil, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get affiliations")
+	}
+	return resp, nil
+}
+
+// GetCAInfo returns the information of the CA, such as its certificate chain, e.g. to bootstrap the MSP
+// configuration of a channel. The request isn't authenticated, so no registrar is required.
+// caname: The name of the CA to connect to (optional)
+func (c *CAClientImpl) GetCAInfo(caname string) (*api.GetCAInfoResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Retrieving information of CA of org [%s]", c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.GetCAInfo", tracing.String("org", c.orgName))
+	resp, err := c.adapter.withRequest(ctx, requestID).GetCAInfo(caname)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get CA info")
+	}
+	return resp, nil
+}
+
+// GetCertificates returns the certificates issued by the CA that match the request and that the registrar is authorized to see
+// request: Certificates Request
+func (c *CAClientImpl) GetCertificates(request *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	if request == nil {
+		return nil, errors.New("certificates request is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Retrieving certificates from CA of org [%s]", c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.GetCertificates", tracing.String("org", c.orgName), tracing.String("id", request.ID))
+	resp, err := c.adapter.withRequest(ctx, requestID).GetCertificates(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.GetCertificates", c.registrar.EnrollID, map[string]string{"id": request.ID, "serial": request.Serial}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get certificates")
+	}
+	return resp, nil
+}
+
+// GenCRL generates a CRL containing the unexpired certificates revoked by the CA that match the request.
+// The registrar must have the hf.GenCRL attribute.
+// request: GenCRL Request
+func (c *CAClientImpl) GenCRL(request *api.GenCRLRequest) (*api.GenCRLResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	if request == nil {
+		return nil, errors.New("gencrl request is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Generating CRL from CA of org [%s]", c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.GenCRL", tracing.String("org", c.orgName))
+	resp, err := c.adapter.withRequest(ctx, requestID).GenCRL(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.GenCRL", c.registrar.EnrollID, map[string]string{"caname": request.CAName}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate CRL")
+	}
+	return resp, nil
+}
+
+// AddAffiliation adds an affiliation to the Fabric CA
+// request: Affiliation Request
+func (c *CAClientImpl) AddAffiliation(request *api.AffiliationRequest) (*api.AffiliationResponse, error) {
+	if err := readonly.Check(c.providers, "ca.AddAffiliation"); err != nil {
+		return nil, err
+	}
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	// Validate request
+	if request == nil {
+		return nil, errors.New("affiliation request is required")
+	}
+	if request.Name == "" {
+		return nil, errors.New("request.Name is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Adding affiliation [%s] to CA of org [%s]", request.Name, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.AddAffiliation", tracing.String("org", c.orgName), tracing.String("affiliation", request.Name))
+	resp, err := c.adapter.withRequest(ctx, requestID).AddAffiliation(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.AddAffiliation", c.registrar.EnrollID, map[string]string{"affiliation": request.Name, "force": strconv.FormatBool(request.Force)}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to add affiliation")
+	}
+	return resp, nil
+}
+
+// ModifyAffiliation renames an affiliation of the Fabric CA
+// request: Modify Affiliation Request
+func (c *CAClientImpl) ModifyAffiliation(request *api.ModifyAffiliationRequest) (*api.AffiliationResponse, error) {
+	if err := readonly.Check(c.providers, "ca.ModifyAffiliation"); err != nil {
+		return nil, err
+	}
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	// Validate request
+	if request == nil {
+		return nil, errors.New("modify affiliation request is required")
+	}
+	if request.Name == "" || request.NewName == "" {
+		return nil, errors.New("request.Name and request.NewName are required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Renaming affiliation [%s] to [%s] with CA of org [%s]", request.Name, request.NewName, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.ModifyAffiliation", tracing.String("org", c.orgName), tracing.String("affiliation", request.Name))
+	resp, err := c.adapter.withRequest(ctx, requestID).ModifyAffiliation(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.ModifyAffiliation", c.registrar.EnrollID, map[string]string{"affiliation": request.Name, "newName": request.NewName, "force": strconv.FormatBool(request.Force)}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to modify affiliation")
+	}
+	return resp, nil
+}
+
+// RemoveAffiliation removes an affiliation from the Fabric CA
+// request: Affiliation Request
+func (c *CAClientImpl) RemoveAffiliation(request *api.AffiliationRequest) (*api.AffiliationResponse, error) {
+	if err := readonly.Check(c.providers, "ca.RemoveAffiliation"); err != nil {
+		return nil, err
+	}
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+	// Validate request
+	if request == nil {
+		return nil, errors.New("affiliation request is required")
+	}
+	if request.Name == "" {
+		return nil, errors.New("request.Name is required")
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, requestID := c.requestContext()
+	logger.With(logging.RequestID(requestID)).Debugf("Removing affiliation [%s] from CA of org [%s]", request.Name, c.orgName)
+
+	_, span := tracer.Start(ctx, "ca.RemoveAffiliation", tracing.String("org", c.orgName), tracing.String("affiliation", request.Name))
+	resp, err := c.adapter.withRequest(ctx, requestID).RemoveAffiliation(registrar.PrivateKey(), registrar.EnrollmentCertificate(), request)
+	tracing.End(span, err)
+	c.recordAudit(requestID, "ca.RemoveAffiliation", c.registrar.EnrollID, map[string]string{"affiliation": request.Name, "force": strconv.FormatBool(request.Force)}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to remove affiliation")
+	}
+	return resp, nil
+}
+
+// recordAudit records an audit event for a CA operation performed by the given identity (if auditing is enabled)
+func (c *CAClientImpl) recordAudit(requestID, operation, identity string, attrs map[string]string, err error) {
+	if !audit.Enabled() {
+		return
+	}
+
+	audit.Record(&audit.Event{
+		RequestID:  requestID,
+		MSPID:      c.orgMSPID,
+		Identity:   identity,
+		Operation:  operation,
+		Endpoints:  []string{c.adapter.caClient.Config.URL},
+		Attributes: attrs,
+		Err:        err,
+	})
+}
+
 func (c *CAClientImpl) getRegistrar(enrollID string, enrollSecret string) (msp.SigningIdentity, error) {
 
 	if enrollID == "" {
@@ -239,7 +727,7 @@ func (c *CAClientImpl) getRegistrar(enrollID string, enrollSecret string) (msp.S
 		}
 
 		// Attempt to enroll the registrar
-		err = c.Enroll(enrollID, enrollSecret)
+		err = c.Enroll(&api.EnrollmentRequest{Name: enrollID, Secret: enrollSecret})
 		if err != nil {
 			return nil, err
 		}
@@ -7,6 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"bytes"
+	reqContext "context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
 	"testing"
 	"time"
 
@@ -14,9 +19,11 @@ import (
 	"strings"
 
 	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
 	fabApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockcontext"
+	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	mockmspApi "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockmsp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -39,13 +46,13 @@ func TestEnrollAndReenroll(t *testing.T) {
 	orgMSPID := mspIDByOrgName(t, f.endpointConfig, org1)
 
 	// Empty enrollment ID
-	err := f.caClient.Enroll("", "user1")
+	err := f.caClient.Enroll(&api.EnrollmentRequest{Name: "", Secret: "user1"})
 	if err == nil {
 		t.Fatalf("Enroll didn't return error")
 	}
 
 	// Empty enrollment secret
-	err = f.caClient.Enroll("enrolledUsername", "")
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: "enrolledUsername", Secret: ""})
 	if err == nil {
 		t.Fatalf("Enroll didn't return error")
 	}
@@ -56,7 +63,7 @@ func TestEnrollAndReenroll(t *testing.T) {
 	if err != msp.ErrUserNotFound {
 		t.Fatalf("Expected to not find user in user store")
 	}
-	err = f.caClient.Enroll(enrollUsername, "enrollmentSecret")
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: enrollUsername, Secret: "enrollmentSecret"})
 	if err != nil {
 		t.Fatalf("identityManager Enroll return error %v", err)
 	}
@@ -66,7 +73,7 @@ func TestEnrollAndReenroll(t *testing.T) {
 	}
 
 	// Reenroll with empty user
-	err = f.caClient.Reenroll("")
+	err = f.caClient.Reenroll(&api.ReenrollmentRequest{Name: ""})
 	if err == nil {
 		t.Fatalf("Expected error with enpty user")
 	}
@@ -87,10 +94,112 @@ func reenrollWithAppropriateUser(f textFixture, t *testing.T, enrolledUserData *
 	if err != nil {
 		t.Fatalf("newUser return error %v", err)
 	}
-	err = f.caClient.Reenroll(enrolledUser.Identifier().ID)
+	err = f.caClient.Reenroll(&api.ReenrollmentRequest{Name: enrolledUser.Identifier().ID})
 	if err != nil {
 		t.Fatalf("Reenroll return error %v", err)
 	}
+
+	// The certificate is issued for the current key
+	reenrolledUserData, err := f.userStore.Load(msp.IdentityIdentifier{MSPID: enrolledUserData.MSPID, ID: enrolledUserData.ID})
+	if err != nil {
+		t.Fatalf("Expected to load user from user store")
+	}
+	if bytes.Equal(reenrolledUserData.EnrollmentCertificate, enrolledUserData.EnrollmentCertificate) {
+		t.Fatalf("Expected a new certificate to be stored")
+	}
+	if !bytes.Equal(certPublicKey(t, reenrolledUserData.EnrollmentCertificate), certPublicKey(t, enrolledUserData.EnrollmentCertificate)) {
+		t.Fatalf("Expected the certificate to be issued for the current key")
+	}
+
+	// Rotate the key
+	err = f.caClient.Reenroll(&api.ReenrollmentRequest{Name: enrolledUser.Identifier().ID, NewKey: true})
+	if err != nil {
+		t.Fatalf("Reenroll with new key return error %v", err)
+	}
+	rotatedUserData, err := f.userStore.Load(msp.IdentityIdentifier{MSPID: enrolledUserData.MSPID, ID: enrolledUserData.ID})
+	if err != nil {
+		t.Fatalf("Expected to load user from user store")
+	}
+	if bytes.Equal(certPublicKey(t, rotatedUserData.EnrollmentCertificate), certPublicKey(t, enrolledUserData.EnrollmentCertificate)) {
+		t.Fatalf("Expected the certificate to be issued for a new key")
+	}
+	// The new key is in the key store
+	if _, err := iManager.(*IdentityManager).GetSigningIdentity(enrolledUserData.ID); err != nil {
+		t.Fatalf("Expected to get signing identity with the new key: %v", err)
+	}
+}
+
+func certPublicKey(t *testing.T, certPEM []byte) []byte {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatalf("certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("parsing certificate failed: %v", err)
+	}
+	pubKey, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		t.Fatalf("marshalling public key failed: %v", err)
+	}
+	return pubKey
+}
+
+// TestEnrollWithCSR tests enrollment with CSR information
+func TestEnrollWithCSR(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	orgMSPID := mspIDByOrgName(t, f.endpointConfig, org1)
+
+	enrollUsername := createRandomName()
+	err := f.caClient.Enroll(&api.EnrollmentRequest{
+		Name:    enrollUsername,
+		Secret:  "enrollmentSecret",
+		Profile: "tls",
+		CSR: &api.CSRInfo{
+			Hosts:      []string{"peer0.org1.example.com", "127.0.0.1"},
+			KeyRequest: &api.KeyRequest{Algo: "ecdsa", Size: 256},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+	enrolledUserData, err := f.userStore.Load(msp.IdentityIdentifier{MSPID: orgMSPID, ID: enrollUsername})
+	if err != nil {
+		t.Fatalf("Expected to load user from user store")
+	}
+	block, _ := pem.Decode(enrolledUserData.EnrollmentCertificate)
+	if block == nil {
+		t.Fatalf("Expected PEM-encoded enrollment certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse enrollment certificate: %s", err)
+	}
+	if len(cert.DNSNames) != 1 || cert.DNSNames[0] != "peer0.org1.example.com" {
+		t.Fatalf("Expected requested host name in certificate, got %v", cert.DNSNames)
+	}
+	if len(cert.IPAddresses) != 1 || cert.IPAddresses[0].String() != "127.0.0.1" {
+		t.Fatalf("Expected requested IP address in certificate, got %v", cert.IPAddresses)
+	}
+
+	// Unsupported key request
+	err = f.caClient.Enroll(&api.EnrollmentRequest{
+		Name:   createRandomName(),
+		Secret: "enrollmentSecret",
+		CSR:    &api.CSRInfo{KeyRequest: &api.KeyRequest{Algo: "ecdsa", Size: 111}},
+	})
+	if err == nil {
+		t.Fatalf("Expected error for unsupported key size")
+	}
+
+	// Missing request
+	if err = f.caClient.Enroll(nil); err == nil {
+		t.Fatalf("Expected error for missing enrollment request")
+	}
 }
 
 // TestWrongURL tests creation of CAClient with wrong URL
@@ -128,13 +237,15 @@ func TestWrongURL(t *testing.T) {
 	mockContext.EXPECT().CryptoSuite().Return(f.cryptoSuite).AnyTimes()
 	mockContext.EXPECT().UserStore().Return(f.userStore).AnyTimes()
 	mockContext.EXPECT().IdentityManager("Org1").Return(iManager, true).AnyTimes()
+	mockContext.EXPECT().ReadOnly().Return(false).AnyTimes()
+	mockContext.EXPECT().CATransport().Return(nil).AnyTimes()
 
 	//f.caClient, err = NewCAClient(org1, f.identityManager, f.userStore, f.cryptoSuite, wrongURLConfigConfig)
 	f.caClient, err = NewCAClient(org1, mockContext)
 	if err != nil {
 		t.Fatalf("NewidentityManagerClient return error: %v", err)
 	}
-	err = f.caClient.Enroll("enrollmentID", "enrollmentSecret")
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: "enrollmentID", Secret: "enrollmentSecret"})
 	if err == nil {
 		t.Fatalf("Enroll didn't return error")
 	}
@@ -198,13 +309,122 @@ func TestRegister(t *testing.T) {
 	var attributes []api.Attribute
 	attributes = append(attributes, api.Attribute{Name: "test1", Value: "test2"})
 	attributes = append(attributes, api.Attribute{Name: "test2", Value: "test3"})
-	secret, err := f.caClient.Register(&api.RegistrationRequest{Name: "test", Affiliation: "test", Attributes: attributes})
+	name := createRandomName()
+	secret, err := f.caClient.Register(&api.RegistrationRequest{Name: name, Affiliation: "test", Attributes: attributes})
 	if err != nil {
 		t.Fatalf("identityManager Register return error %v", err)
 	}
-	if secret != "mockSecretValue" {
+	if expected, _ := caServer.Secret(name); secret != expected {
 		t.Fatalf("identityManager Register return wrong value %s", secret)
 	}
+
+	// Register the same identity again
+	_, err = f.caClient.Register(&api.RegistrationRequest{Name: name, Affiliation: "test"})
+	if err == nil {
+		t.Fatalf("Expected error when registering an identity twice")
+	}
+}
+
+// TestRegisterAudit tests that the request ID is sent to the CA and that an audit event is recorded
+func TestRegisterAudit(t *testing.T) {
+	var events []*audit.Event
+	audit.Initialize(audit.SinkFunc(func(event *audit.Event) {
+		events = append(events, event)
+	}))
+	defer audit.Initialize(nil)
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	name := createRandomName()
+	_, err := f.caClient.Register(&api.RegistrationRequest{Name: name, Affiliation: "test"})
+	if err != nil {
+		t.Fatalf("identityManager Register return error %v", err)
+	}
+
+	// The registrar is enrolled before the registration
+	if len(events) != 2 {
+		t.Fatalf("Expecting 2 audit events but got %d", len(events))
+	}
+	event := events[1]
+	if event.Operation != "ca.Register" || event.Attributes["name"] != name || event.Err != nil {
+		t.Fatalf("Unexpected audit event: %+v", event)
+	}
+	if event.RequestID == "" || event.RequestID != caServer.LastRequestID() {
+		t.Fatalf("Expecting request ID [%s] to be sent to the CA but got [%s]", event.RequestID, caServer.LastRequestID())
+	}
+	if len(event.Endpoints) != 1 || !strings.Contains(caServerURL, event.Endpoints[0]) {
+		t.Fatalf("Expecting CA endpoint [%s] but got %v", caServerURL, event.Endpoints)
+	}
+}
+
+// TestRegisterWithContext tests that the requests to the CA are bound to the client's context
+func TestRegisterWithContext(t *testing.T) {
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	ctx := audit.WithRequestID(reqContext.Background(), "request-1")
+	_, err := f.caClient.WithContext(ctx).Register(&api.RegistrationRequest{Name: "withcontext", Affiliation: "test"})
+	if err != nil {
+		t.Fatalf("identityManager Register return error %v", err)
+	}
+	if caServer.LastRequestID() != "request-1" {
+		t.Fatalf("Expecting request ID of context to be sent to the CA but got [%s]", caServer.LastRequestID())
+	}
+
+	ctx, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+	_, err = f.caClient.WithContext(ctx).Register(&api.RegistrationRequest{Name: "cancelled", Affiliation: "test"})
+	if err == nil || !strings.Contains(err.Error(), reqContext.Canceled.Error()) {
+		t.Fatalf("Expecting error for cancelled context but got %v", err)
+	}
+}
+
+// TestCATransport tests that the CA transport of the client's context only wraps the transport of its CA clients
+func TestCATransport(t *testing.T) {
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	var requests int
+	transport := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests++
+			return next.RoundTrip(req)
+		})
+	}
+
+	ctxProvider := context.NewProvider(context.WithIdentityManagerProvider(f.identityManagerProvider),
+		context.WithUserStore(f.userStore), context.WithCryptoSuite(f.cryptoSuite),
+		context.WithCryptoSuiteConfig(f.cryptSuiteConfig), context.WithEndpointConfig(f.endpointConfig),
+		context.WithIdentityConfig(f.identityConfig), context.WithCATransport(transport))
+	caClient, err := NewCAClient(org1, &context.Client{Providers: ctxProvider})
+	if err != nil {
+		t.Fatalf("NewCAClient returned error: %v", err)
+	}
+
+	if _, err := caClient.GetCAInfo(""); err != nil {
+		t.Fatalf("GetCAInfo returned error: %v", err)
+	}
+	if requests == 0 {
+		t.Fatalf("Expecting the requests to the CA to be sent through the transport of the context")
+	}
+
+	sent := requests
+	if _, err := f.caClient.GetCAInfo(""); err != nil {
+		t.Fatalf("GetCAInfo returned error: %v", err)
+	}
+	if requests != sent {
+		t.Fatalf("Expecting the transport to only be used by the CA clients of its context")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
 }
 
 // TestEmbeddedRegistar tests registration with embedded registrar identity
@@ -223,11 +443,11 @@ func TestEmbeddedRegistar(t *testing.T) {
 	var attributes []api.Attribute
 	attributes = append(attributes, api.Attribute{Name: "test1", Value: "test2"})
 	attributes = append(attributes, api.Attribute{Name: "test2", Value: "test3"})
-	secret, err := f.caClient.Register(&api.RegistrationRequest{Name: "withEmbeddedRegistrar", Affiliation: "test", Attributes: attributes})
+	secret, err := f.caClient.Register(&api.RegistrationRequest{Name: createRandomName(), Affiliation: "test", Attributes: attributes, Secret: "embeddedSecret"})
 	if err != nil {
 		t.Fatalf("identityManager Register return error %v", err)
 	}
-	if secret != "mockSecretValue" {
+	if secret != "embeddedSecret" {
 		t.Fatalf("identityManager Register return wrong value %s", secret)
 	}
 }
@@ -291,6 +511,391 @@ func TestRevoke(t *testing.T) {
 	}
 }
 
+// TestIdentities tests retrieving, modifying and removing identities registered with the CA
+func TestIdentities(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	name := createRandomName()
+	_, err := f.caClient.Register(&api.RegistrationRequest{Name: name, Affiliation: "org1", Attributes: []api.Attribute{{Name: "test1", Value: "test2"}}})
+	if err != nil {
+		t.Fatalf("identityManager Register return error %v", err)
+	}
+
+	// Get identity without ID
+	_, err = f.caClient.GetIdentity("", "")
+	if err == nil {
+		t.Fatalf("Expected error without ID")
+	}
+
+	identity, err := f.caClient.GetIdentity(name, "")
+	if err != nil {
+		t.Fatalf("GetIdentity return error %v", err)
+	}
+	if identity.ID != name || identity.Affiliation != "org1" || len(identity.Attributes) != 1 {
+		t.Fatalf("GetIdentity return unexpected identity %+v", identity)
+	}
+
+	identities, err := f.caClient.GetAllIdentities("")
+	if err != nil {
+		t.Fatalf("GetAllIdentities return error %v", err)
+	}
+	if !containsIdentity(identities, name) {
+		t.Fatalf("GetAllIdentities didn't return identity %s: %+v", name, identities)
+	}
+
+	// Modify with nil request
+	_, err = f.caClient.ModifyIdentity(nil)
+	if err == nil {
+		t.Fatalf("Expected error with nil request")
+	}
+
+	// Modify without ID
+	_, err = f.caClient.ModifyIdentity(&api.IdentityRequest{})
+	if err == nil {
+		t.Fatalf("Expected error without ID")
+	}
+
+	identity, err = f.caClient.ModifyIdentity(&api.IdentityRequest{ID: name, Affiliation: "org2", Secret: "newSecret", Attributes: []api.Attribute{{Name: "test3", Value: "test4", ECert: true}}})
+	if err != nil {
+		t.Fatalf("ModifyIdentity return error %v", err)
+	}
+	if identity.Affiliation != "org2" || identity.Secret != "newSecret" || len(identity.Attributes) != 2 {
+		t.Fatalf("ModifyIdentity return unexpected identity %+v", identity)
+	}
+
+	// Remove with nil request
+	_, err = f.caClient.RemoveIdentity(nil)
+	if err == nil {
+		t.Fatalf("Expected error with nil request")
+	}
+
+	identity, err = f.caClient.RemoveIdentity(&api.RemoveIdentityRequest{ID: name})
+	if err != nil {
+		t.Fatalf("RemoveIdentity return error %v", err)
+	}
+	if identity.ID != name {
+		t.Fatalf("RemoveIdentity return unexpected identity %+v", identity)
+	}
+
+	// Get removed identity
+	_, err = f.caClient.GetIdentity(name, "")
+	if err == nil {
+		t.Fatalf("Expected error retrieving removed identity")
+	}
+}
+
+func containsIdentity(identities []*api.IdentityResponse, id string) bool {
+	for _, identity := range identities {
+		if identity.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// TestIdentitiesNoRegistrar tests identity management with no configured registrar identity
+func TestIdentitiesNoRegistrar(t *testing.T) {
+
+	noRegistrarBackend, err := getNoRegistrarBackend()
+	if err != nil {
+		t.Fatalf("Failed to get config backend, cause: %v", err)
+	}
+
+	f := textFixture{}
+	f.setup(noRegistrarBackend)
+	defer f.close()
+
+	_, err = f.caClient.GetIdentity("test", "")
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	_, err = f.caClient.GetAllIdentities("")
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	_, err = f.caClient.ModifyIdentity(&api.IdentityRequest{ID: "test"})
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	_, err = f.caClient.RemoveIdentity(&api.RemoveIdentityRequest{ID: "test"})
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+}
+
+func TestAffiliations(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	name := "org1." + createRandomName()
+
+	// Add with nil request
+	_, err := f.caClient.AddAffiliation(nil)
+	if err == nil {
+		t.Fatalf("Expected error with nil request")
+	}
+
+	// Add without name
+	_, err = f.caClient.AddAffiliation(&api.AffiliationRequest{})
+	if err == nil {
+		t.Fatalf("Expected error without name")
+	}
+
+	// Add without parent affiliation
+	_, err = f.caClient.AddAffiliation(&api.AffiliationRequest{Name: name + ".unit1.team1"})
+	if err == nil {
+		t.Fatalf("Expected error adding affiliation without parent")
+	}
+
+	affiliation, err := f.caClient.AddAffiliation(&api.AffiliationRequest{Name: name + ".unit1.team1", Force: true})
+	if err != nil {
+		t.Fatalf("AddAffiliation return error %v", err)
+	}
+	if affiliation.Name != name+".unit1.team1" {
+		t.Fatalf("AddAffiliation return unexpected affiliation %+v", affiliation)
+	}
+
+	// Get affiliation without name
+	_, err = f.caClient.GetAffiliation("", "")
+	if err == nil {
+		t.Fatalf("Expected error without name")
+	}
+
+	affiliation, err = f.caClient.GetAffiliation(name, "")
+	if err != nil {
+		t.Fatalf("GetAffiliation return error %v", err)
+	}
+	if affiliation.Name != name || len(affiliation.Affiliations) != 1 || affiliation.Affiliations[0].Name != name+".unit1" {
+		t.Fatalf("GetAffiliation return unexpected affiliation %+v", affiliation)
+	}
+
+	affiliations, err := f.caClient.GetAllAffiliations("")
+	if err != nil {
+		t.Fatalf("GetAllAffiliations return error %v", err)
+	}
+	if !containsAffiliation(affiliations.AffiliationInfo, name+".unit1.team1") {
+		t.Fatalf("GetAllAffiliations didn't return affiliation %s: %+v", name, affiliations)
+	}
+
+	// Modify without new name
+	_, err = f.caClient.ModifyAffiliation(&api.ModifyAffiliationRequest{AffiliationRequest: api.AffiliationRequest{Name: name}})
+	if err == nil {
+		t.Fatalf("Expected error without new name")
+	}
+
+	affiliation, err = f.caClient.ModifyAffiliation(&api.ModifyAffiliationRequest{AffiliationRequest: api.AffiliationRequest{Name: name + ".unit1"}, NewName: name + ".unit2"})
+	if err != nil {
+		t.Fatalf("ModifyAffiliation return error %v", err)
+	}
+	if affiliation.Name != name+".unit2" || len(affiliation.Affiliations) != 1 || affiliation.Affiliations[0].Name != name+".unit2.team1" {
+		t.Fatalf("ModifyAffiliation return unexpected affiliation %+v", affiliation)
+	}
+
+	// Remove affiliation with children
+	_, err = f.caClient.RemoveAffiliation(&api.AffiliationRequest{Name: name})
+	if err == nil {
+		t.Fatalf("Expected error removing affiliation with children without force")
+	}
+
+	affiliation, err = f.caClient.RemoveAffiliation(&api.AffiliationRequest{Name: name, Force: true})
+	if err != nil {
+		t.Fatalf("RemoveAffiliation return error %v", err)
+	}
+	if affiliation.Name != name {
+		t.Fatalf("RemoveAffiliation return unexpected affiliation %+v", affiliation)
+	}
+
+	// Get removed affiliation
+	_, err = f.caClient.GetAffiliation(name+".unit2", "")
+	if err == nil {
+		t.Fatalf("Expected error retrieving removed affiliation")
+	}
+}
+
+func containsAffiliation(info api.AffiliationInfo, name string) bool {
+	if info.Name == name {
+		return true
+	}
+	for _, child := range info.Affiliations {
+		if containsAffiliation(child, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// TestAffiliationsNoRegistrar tests affiliation management with no configured registrar identity
+func TestAffiliationsNoRegistrar(t *testing.T) {
+
+	noRegistrarBackend, err := getNoRegistrarBackend()
+	if err != nil {
+		t.Fatalf("Failed to get config backend, cause: %v", err)
+	}
+
+	f := textFixture{}
+	f.setup(noRegistrarBackend)
+	defer f.close()
+
+	_, err = f.caClient.GetAffiliation("org1", "")
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	_, err = f.caClient.GetAllAffiliations("")
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	_, err = f.caClient.AddAffiliation(&api.AffiliationRequest{Name: "org3"})
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	_, err = f.caClient.ModifyAffiliation(&api.ModifyAffiliationRequest{AffiliationRequest: api.AffiliationRequest{Name: "org3"}, NewName: "org4"})
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	_, err = f.caClient.RemoveAffiliation(&api.AffiliationRequest{Name: "org3"})
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+}
+
+// TestCAInfoAndCertificates tests the retrieval of the CA information and of the issued certificates
+func TestCAInfoAndCertificates(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	info, err := f.caClient.GetCAInfo("")
+	if err != nil {
+		t.Fatalf("GetCAInfo return error %v", err)
+	}
+	block, _ := pem.Decode(info.CAChain)
+	if block == nil {
+		t.Fatalf("Expected PEM-encoded CA chain")
+	}
+	if _, err = x509.ParseCertificate(block.Bytes); err != nil {
+		t.Fatalf("Failed to parse CA certificate: %s", err)
+	}
+
+	enrollUsername := createRandomName()
+	err = f.caClient.Enroll(&api.EnrollmentRequest{Name: enrollUsername, Secret: "enrollmentSecret"})
+	if err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+
+	certs, err := f.caClient.GetCertificates(&api.GetCertificatesRequest{ID: enrollUsername, NotRevoked: true, NotExpired: true})
+	if err != nil {
+		t.Fatalf("GetCertificates return error %v", err)
+	}
+	if len(certs.Certs) != 1 {
+		t.Fatalf("Expected one certificate for %s, got %d", enrollUsername, len(certs.Certs))
+	}
+
+	// Revoked in the last hour
+	certs, err = f.caClient.GetCertificates(&api.GetCertificatesRequest{ID: enrollUsername, RevokedStart: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("GetCertificates return error %v", err)
+	}
+	if len(certs.Certs) != 0 {
+		t.Fatalf("Expected no revoked certificate for %s, got %d", enrollUsername, len(certs.Certs))
+	}
+
+	if _, err = f.caClient.GetCertificates(nil); err == nil {
+		t.Fatalf("Expected error with nil request")
+	}
+}
+
+// TestCertificatesNoRegistrar tests the retrieval of certificates without a registrar
+func TestCertificatesNoRegistrar(t *testing.T) {
+
+	noRegistrarBackend, err := getNoRegistrarBackend()
+	if err != nil {
+		t.Fatalf("Failed to get config backend, cause: %v", err)
+	}
+
+	f := textFixture{}
+	f.setup(noRegistrarBackend)
+	defer f.close()
+
+	_, err = f.caClient.GetCertificates(&api.GetCertificatesRequest{})
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	_, err = f.caClient.GenCRL(&api.GenCRLRequest{})
+	if err != api.ErrCARegistrarNotFound {
+		t.Fatalf("Expected ErrCARegistrarNotFound, got: %v", err)
+	}
+
+	// No registrar is required to retrieve the CA information
+	if _, err = f.caClient.GetCAInfo(""); err != nil {
+		t.Fatalf("GetCAInfo return error %v", err)
+	}
+}
+
+// TestGenCRL tests that the CRL generated after a revocation lists the revoked certificates
+func TestGenCRL(t *testing.T) {
+
+	f := textFixture{}
+	f.setup(nil)
+	defer f.close()
+
+	enrollUsername := createRandomName()
+	err := f.caClient.Enroll(&api.EnrollmentRequest{Name: enrollUsername, Secret: "enrollmentSecret"})
+	if err != nil {
+		t.Fatalf("Enroll return error %v", err)
+	}
+	revoked, err := f.caClient.Revoke(&api.RevocationRequest{Name: enrollUsername})
+	if err != nil {
+		t.Fatalf("Revoke return error %v", err)
+	}
+
+	resp, err := f.caClient.GenCRL(&api.GenCRLRequest{RevokedAfter: time.Now().Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("GenCRL return error %v", err)
+	}
+	block, _ := pem.Decode(resp.CRL)
+	if block == nil {
+		t.Fatalf("Expected PEM-encoded CRL")
+	}
+	crl, err := x509.ParseCRL(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse CRL: %s", err)
+	}
+	if len(crl.TBSCertList.RevokedCertificates) < len(revoked.RevokedCerts) {
+		t.Fatalf("Expected at least %d revoked certificates in CRL, got %d", len(revoked.RevokedCerts), len(crl.TBSCertList.RevokedCertificates))
+	}
+
+	// Revoked in the future
+	resp, err = f.caClient.GenCRL(&api.GenCRLRequest{RevokedAfter: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("GenCRL return error %v", err)
+	}
+	block, _ = pem.Decode(resp.CRL)
+	if crl, err = x509.ParseCRL(block.Bytes); err != nil {
+		t.Fatalf("Failed to parse CRL: %s", err)
+	}
+	if len(crl.TBSCertList.RevokedCertificates) != 0 {
+		t.Fatalf("Expected empty CRL, got %d revoked certificates", len(crl.TBSCertList.RevokedCertificates))
+	}
+
+	if _, err = f.caClient.GenCRL(nil); err == nil {
+		t.Fatalf("Expected error with nil request")
+	}
+}
+
 // TestCAConfigError will test CAClient creation with bad CAConfig
 func TestCAConfigError(t *testing.T) {
 
@@ -334,6 +939,7 @@ func TestCAServerCertPathsError(t *testing.T) {
 	mockContext.EXPECT().IdentityConfig().Return(mockIdentityConfig).AnyTimes()
 	mockContext.EXPECT().UserStore().Return(&mockmsp.MockUserStore{}).AnyTimes()
 	mockContext.EXPECT().CryptoSuite().Return(f.cryptoSuite).AnyTimes()
+	mockContext.EXPECT().CATransport().Return(nil).AnyTimes()
 
 	_, err := NewCAClient(org1, mockContext)
 	if err == nil || !strings.Contains(err.Error(), "CAServerCerts error") {
@@ -361,6 +967,7 @@ func TestCAClientCertPathError(t *testing.T) {
 	mockContext.EXPECT().IdentityConfig().Return(mockIdentityConfig).AnyTimes()
 	mockContext.EXPECT().UserStore().Return(&mockmsp.MockUserStore{}).AnyTimes()
 	mockContext.EXPECT().CryptoSuite().Return(f.cryptoSuite).AnyTimes()
+	mockContext.EXPECT().CATransport().Return(nil).AnyTimes()
 
 	_, err := NewCAClient(org1, mockContext)
 	if err == nil || !strings.Contains(err.Error(), "CAClientCertPath error") {
@@ -390,6 +997,7 @@ func TestCAClientKeyPathError(t *testing.T) {
 	mockContext.EXPECT().IdentityConfig().Return(mockIdentityConfig).AnyTimes()
 	mockContext.EXPECT().UserStore().Return(&mockmsp.MockUserStore{}).AnyTimes()
 	mockContext.EXPECT().CryptoSuite().Return(f.cryptoSuite).AnyTimes()
+	mockContext.EXPECT().CATransport().Return(nil).AnyTimes()
 
 	_, err := NewCAClient(org1, mockContext)
 	if err == nil || !strings.Contains(err.Error(), "CAClientKeyPath error") {
@@ -0,0 +1,55 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	reqContext "context"
+	"net/http"
+)
+
+// headerTransport sets the given headers on the requests sent with the wrapped transport
+type headerTransport struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func withHeaders(headers map[string]string) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &headerTransport{headers: headers, next: next}
+	}
+}
+
+// RoundTrip sends a copy of the request with the headers set, since a RoundTripper must not modify the request
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.WithContext(req.Context())
+	r.Header = make(http.Header, len(req.Header)+len(t.headers))
+	for name, values := range req.Header {
+		r.Header[name] = values
+	}
+	for name, value := range t.headers {
+		r.Header.Set(name, value)
+	}
+	return t.next.RoundTrip(r)
+}
+
+// contextTransport binds the requests sent with the wrapped transport to the given context, so that
+// they are cancelled when the context is done
+type contextTransport struct {
+	ctx  reqContext.Context
+	next http.RoundTripper
+}
+
+func withContext(ctx reqContext.Context) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &contextTransport{ctx: ctx, next: next}
+	}
+}
+
+// RoundTrip sends a copy of the request that is bound to the context
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
@@ -15,10 +15,21 @@ import (
 )
 
 // CertFileUserStore stores each user in a separate file.
-// Only user's enrollment cert is stored, in pem format.
+// By default only user's enrollment cert is stored, in pem format (see WithCodec).
 // File naming is <user>@<org>-cert.pem
 type CertFileUserStore struct {
 	store core.KVStore
+	codec UserDataCodec
+}
+
+// CertFileUserStoreOpt is a CertFileUserStore option
+type CertFileUserStoreOpt func(s *CertFileUserStore)
+
+// WithCodec sets the codec that encodes the stored user data (PEMCodec by default)
+func WithCodec(codec UserDataCodec) CertFileUserStoreOpt {
+	return func(s *CertFileUserStore) {
+		s.codec = codec
+	}
 }
 
 func storeKeyFromUserIdentifier(key msp.IdentityIdentifier) string {
@@ -26,14 +37,19 @@ func storeKeyFromUserIdentifier(key msp.IdentityIdentifier) string {
 }
 
 // NewCertFileUserStore1 creates a new instance of CertFileUserStore
-func NewCertFileUserStore1(store core.KVStore) (*CertFileUserStore, error) {
-	return &CertFileUserStore{
+func NewCertFileUserStore1(store core.KVStore, opts ...CertFileUserStoreOpt) (*CertFileUserStore, error) {
+	s := &CertFileUserStore{
 		store: store,
-	}, nil
+		codec: PEMCodec{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 // NewCertFileUserStore creates a new instance of CertFileUserStore
-func NewCertFileUserStore(path string) (*CertFileUserStore, error) {
+func NewCertFileUserStore(path string, opts ...CertFileUserStoreOpt) (*CertFileUserStore, error) {
 	if path == "" {
 		return nil, errors.New("path is empty")
 	}
@@ -43,7 +59,7 @@ func NewCertFileUserStore(path string) (*CertFileUserStore, error) {
 	if err != nil {
 		return nil, errors.WithMessage(err, "user store creation failed")
 	}
-	return NewCertFileUserStore1(store)
+	return NewCertFileUserStore1(store, opts...)
 }
 
 // Load returns the User stored in the store for a key.
@@ -59,18 +75,17 @@ func (s *CertFileUserStore) Load(key msp.IdentityIdentifier) (*msp.UserData, err
 	if !ok {
 		return nil, errors.New("user is not of proper type")
 	}
-	userData := &msp.UserData{
-		MSPID: key.MSPID,
-		ID:    key.ID,
-		EnrollmentCertificate: certBytes,
-	}
-	return userData, nil
+	return s.codec.Decode(key, certBytes)
 }
 
 // Store stores a User into store
 func (s *CertFileUserStore) Store(user *msp.UserData) error {
 	key := storeKeyFromUserIdentifier(msp.IdentityIdentifier{MSPID: user.MSPID, ID: user.ID})
-	return s.store.Store(key, user.EnrollmentCertificate)
+	data, err := s.codec.Encode(user)
+	if err != nil {
+		return err
+	}
+	return s.store.Store(key, data)
 }
 
 // Delete deletes a User from store
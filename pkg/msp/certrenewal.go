@@ -0,0 +1,340 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/opevents"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultCertCheckInterval is the interval at which the certificate renewal service checks the
+	// certificates if no interval is configured
+	DefaultCertCheckInterval = time.Hour
+	// DefaultCertExpiryWarning is the time before the expiry of a certificate at which the certificate
+	// renewal service warns about it if no time is configured
+	DefaultCertExpiryWarning = 30 * 24 * time.Hour
+	// DefaultCertEventBufferSize is the size of the certificate renewal service's event buffer if none
+	// is configured
+	DefaultCertEventBufferSize = 100
+)
+
+// Reenroller re-enrolls an identity with the CA of its organization, rotating its key if newKey is set.
+// The new enrollment certificate must be stored in the user store.
+type Reenroller func(id msp.IdentityIdentifier, newKey bool) error
+
+// CertRenewalOpts configures the certificate renewal service
+type CertRenewalOpts struct {
+	// Interval is the interval at which the certificates are checked (DefaultCertCheckInterval if zero)
+	Interval time.Duration
+	// WarnBefore is the time before the expiry of a certificate at which an event is emitted
+	// (DefaultCertExpiryWarning if zero)
+	WarnBefore time.Duration
+	// RenewBefore is the time before the expiry of a certificate at which the identity is re-enrolled.
+	// Identities aren't re-enrolled if it is zero.
+	RenewBefore time.Duration
+	// NewKey makes the re-enrollments rotate the keys of the identities
+	NewKey bool
+	// Identities are watched in addition to the identities that are stored in or loaded from the
+	// user store returned by WatchUserStore
+	Identities []msp.IdentityIdentifier
+	// EventBufferSize is the size of the event buffer (DefaultCertEventBufferSize if zero)
+	EventBufferSize int
+	// Clock is the clock on which the certificates are checked (clock.Real if nil)
+	Clock clock.Clock
+}
+
+// CertExpiryEvent reports that the enrollment certificate of an identity is about to expire (or has
+// expired), that the identity was re-enrolled or that the check or the re-enrollment failed
+type CertExpiryEvent struct {
+	// Identity is the identity whose certificate was checked
+	Identity msp.IdentityIdentifier
+	// NotAfter is the expiry of the identity's certificate; it is the expiry of the new certificate
+	// if the identity was re-enrolled
+	NotAfter time.Time
+	// Renewed is set if the identity was re-enrolled
+	Renewed bool
+	// Err is the error if the certificate couldn't be checked or the identity couldn't be re-enrolled
+	Err error
+}
+
+// CertRenewalService watches the enrollment certificates of identities in the user store. It emits an
+// event when a certificate is about to expire and optionally re-enrolls the identity some time before
+// the certificate expires. The check is repeated at the configured interval once the service is started.
+//
+// A warning is emitted once per certificate. A failed re-enrollment is retried at the next check.
+// Events are dropped if the event buffer is full. The events are also published as operational events
+// (see opevents.CertExpiring).
+//
+// This component has been designed to be safe for concurrency.
+type CertRenewalService struct {
+	userStore  msp.UserStore
+	reenroller Reenroller
+	opts       CertRenewalOpts
+	events     chan *CertExpiryEvent
+
+	lock    sync.Mutex
+	watched map[msp.IdentityIdentifier]bool
+	done    chan struct{}
+	stopped bool
+	wg      sync.WaitGroup
+
+	checkLock sync.Mutex
+	warned    map[msp.IdentityIdentifier]time.Time
+}
+
+// NewCertRenewalService creates a certificate renewal service for the identities of the given user
+// store. The reenroller may be nil if identities are not re-enrolled (RenewBefore is zero).
+func NewCertRenewalService(userStore msp.UserStore, reenroller Reenroller, opts CertRenewalOpts) (*CertRenewalService, error) {
+	if userStore == nil {
+		return nil, errors.New("user store is nil")
+	}
+	if opts.Interval < 0 || opts.WarnBefore < 0 || opts.RenewBefore < 0 {
+		return nil, errors.New("certificate renewal durations must not be negative")
+	}
+	if opts.RenewBefore > 0 && reenroller == nil {
+		return nil, errors.New("a reenroller is required to renew certificates")
+	}
+	if opts.Interval == 0 {
+		opts.Interval = DefaultCertCheckInterval
+	}
+	if opts.WarnBefore == 0 {
+		opts.WarnBefore = DefaultCertExpiryWarning
+	}
+	if opts.EventBufferSize <= 0 {
+		opts.EventBufferSize = DefaultCertEventBufferSize
+	}
+	if opts.Clock == nil {
+		opts.Clock = clock.Real
+	}
+
+	s := &CertRenewalService{
+		userStore:  userStore,
+		reenroller: reenroller,
+		opts:       opts,
+		events:     make(chan *CertExpiryEvent, opts.EventBufferSize),
+		watched:    make(map[msp.IdentityIdentifier]bool),
+		warned:     make(map[msp.IdentityIdentifier]time.Time),
+	}
+	s.Watch(opts.Identities...)
+	return s, nil
+}
+
+// Events returns the channel of the service's events. The channel is closed when the service is stopped.
+func (s *CertRenewalService) Events() <-chan *CertExpiryEvent {
+	return s.events
+}
+
+// Watch adds identities to the identities whose certificates are checked
+func (s *CertRenewalService) Watch(ids ...msp.IdentityIdentifier) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, id := range ids {
+		s.watched[id] = true
+	}
+}
+
+// Unwatch removes identities from the identities whose certificates are checked
+func (s *CertRenewalService) Unwatch(ids ...msp.IdentityIdentifier) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, id := range ids {
+		delete(s.watched, id)
+	}
+}
+
+// WatchUserStore returns a user store that stores and loads the users of the given store and watches
+// each identity that is stored (e.g. when it is enrolled) or loaded
+func (s *CertRenewalService) WatchUserStore(store msp.UserStore) msp.UserStore {
+	return &watchedUserStore{UserStore: store, service: s}
+}
+
+type watchedUserStore struct {
+	msp.UserStore
+	service *CertRenewalService
+}
+
+func (w *watchedUserStore) Store(user *msp.UserData) error {
+	if err := w.UserStore.Store(user); err != nil {
+		return err
+	}
+	w.service.Watch(msp.IdentityIdentifier{MSPID: user.MSPID, ID: user.ID})
+	return nil
+}
+
+func (w *watchedUserStore) Load(id msp.IdentityIdentifier) (*msp.UserData, error) {
+	user, err := w.UserStore.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	w.service.Watch(id)
+	return user, nil
+}
+
+// Start checks the certificates and then checks them periodically until the service is stopped
+func (s *CertRenewalService) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped {
+		return errors.New("certificate renewal service is stopped")
+	}
+	if s.done != nil {
+		return errors.New("certificate renewal service is already started")
+	}
+	s.done = make(chan struct{})
+
+	s.wg.Add(1)
+	go s.run(s.done)
+	return nil
+}
+
+// Stop stops the service and closes the events channel. The service can't be restarted.
+func (s *CertRenewalService) Stop() {
+	s.lock.Lock()
+	if s.stopped {
+		s.lock.Unlock()
+		return
+	}
+	s.stopped = true
+	done := s.done
+	s.done = nil
+	s.lock.Unlock()
+
+	if done != nil {
+		close(done)
+		s.wg.Wait()
+	}
+
+	// Wait for a concurrent Check to complete before closing the channel
+	s.checkLock.Lock()
+	close(s.events)
+	s.checkLock.Unlock()
+}
+
+func (s *CertRenewalService) run(done chan struct{}) {
+	defer s.wg.Done()
+
+	for {
+		s.Check()
+		select {
+		case <-done:
+			return
+		case <-s.opts.Clock.After(s.opts.Interval):
+		}
+	}
+}
+
+// Check checks the certificates of the watched identities once, emitting the events and
+// re-enrolling the identities as configured
+func (s *CertRenewalService) Check() {
+	s.checkLock.Lock()
+	defer s.checkLock.Unlock()
+
+	s.lock.Lock()
+	stopped := s.stopped
+	ids := make([]msp.IdentityIdentifier, 0, len(s.watched))
+	for id := range s.watched {
+		ids = append(ids, id)
+	}
+	s.lock.Unlock()
+
+	if stopped {
+		return
+	}
+	for _, id := range ids {
+		s.check(id)
+	}
+}
+
+func (s *CertRenewalService) check(id msp.IdentityIdentifier) {
+	notAfter, err := s.notAfter(id)
+	if err != nil {
+		if err == msp.ErrUserNotFound {
+			logger.Debugf("Certificate of [%s@%s] isn't checked since the user isn't stored", id.ID, id.MSPID)
+			return
+		}
+		s.emit(&CertExpiryEvent{Identity: id, Err: errors.WithMessage(err, "checking certificate failed")})
+		return
+	}
+
+	remaining := notAfter.Sub(s.opts.Clock.Now())
+	if s.opts.RenewBefore > 0 && remaining <= s.opts.RenewBefore {
+		s.renew(id, notAfter)
+		return
+	}
+	if remaining <= s.opts.WarnBefore && !s.warned[id].Equal(notAfter) {
+		logger.Warnf("Certificate of [%s@%s] expires at %s", id.ID, id.MSPID, notAfter)
+		s.warned[id] = notAfter
+		s.emit(&CertExpiryEvent{Identity: id, NotAfter: notAfter})
+	}
+}
+
+func (s *CertRenewalService) renew(id msp.IdentityIdentifier, notAfter time.Time) {
+	logger.Infof("Re-enrolling [%s@%s] since its certificate expires at %s", id.ID, id.MSPID, notAfter)
+
+	if err := s.reenroller(id, s.opts.NewKey); err != nil {
+		logger.Warnf("Re-enrolling [%s@%s] failed: %s", id.ID, id.MSPID, err)
+		s.emit(&CertExpiryEvent{Identity: id, NotAfter: notAfter, Err: errors.WithMessage(err, "re-enrollment failed")})
+		return
+	}
+
+	renewed, err := s.notAfter(id)
+	if err != nil {
+		s.emit(&CertExpiryEvent{Identity: id, NotAfter: notAfter, Renewed: true, Err: errors.WithMessage(err, "checking renewed certificate failed")})
+		return
+	}
+	delete(s.warned, id)
+	s.emit(&CertExpiryEvent{Identity: id, NotAfter: renewed, Renewed: true})
+}
+
+func (s *CertRenewalService) notAfter(id msp.IdentityIdentifier) (time.Time, error) {
+	user, err := s.userStore.Load(id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	block, _ := pem.Decode(user.EnrollmentCertificate)
+	if block == nil {
+		return time.Time{}, errors.New("enrollment certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "parsing enrollment certificate failed")
+	}
+	return cert.NotAfter, nil
+}
+
+func (s *CertRenewalService) emit(event *CertExpiryEvent) {
+	select {
+	case s.events <- event:
+	default:
+		logger.Warnf("Certificate expiry event of [%s@%s] dropped since the event buffer is full", event.Identity.ID, event.Identity.MSPID)
+	}
+
+	attributes := map[string]string{"mspID": event.Identity.MSPID}
+	if !event.NotAfter.IsZero() {
+		attributes["notAfter"] = event.NotAfter.Format(time.RFC3339)
+	}
+	if event.Renewed {
+		attributes["renewed"] = "true"
+	}
+	opevents.Publish(&opevents.Event{
+		Type:       opevents.CertExpiring,
+		Source:     event.Identity.ID,
+		Attributes: attributes,
+		Err:        event.Err,
+	})
+}
@@ -0,0 +1,205 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/clock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+func TestCertRenewalWarning(t *testing.T) {
+	now := time.Now()
+	fakeClock := clock.NewFake(now)
+
+	store := NewMemoryUserStore()
+	service, err := NewCertRenewalService(store, nil, CertRenewalOpts{Interval: time.Hour, WarnBefore: 24 * time.Hour, Clock: fakeClock})
+	if err != nil {
+		t.Fatalf("NewCertRenewalService failed [%s]", err)
+	}
+	userStore := service.WatchUserStore(store)
+
+	user1 := msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "user1"}
+	user2 := msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "user2"}
+	storeTestUser(t, userStore, user1, now.Add(12*time.Hour))
+	storeTestUser(t, userStore, user2, now.Add(48*time.Hour))
+
+	service.Check()
+	event := nextCertExpiryEvent(t, service)
+	if event.Identity != user1 || event.Renewed || event.Err != nil || !event.NotAfter.Equal(now.Add(12*time.Hour).Truncate(time.Second)) {
+		t.Fatalf("Unexpected event %+v", event)
+	}
+
+	// The warning is emitted once per certificate
+	service.Check()
+	expectNoCertExpiryEvent(t, service)
+
+	fakeClock.Advance(25 * time.Hour)
+	service.Check()
+	event = nextCertExpiryEvent(t, service)
+	if event.Identity != user2 {
+		t.Fatalf("Unexpected event %+v", event)
+	}
+
+	service.Unwatch(user1, user2)
+	storeTestUser(t, store, user1, now.Add(26*time.Hour))
+	service.Check()
+	expectNoCertExpiryEvent(t, service)
+}
+
+func TestCertRenewalReenroll(t *testing.T) {
+	now := time.Now()
+	fakeClock := clock.NewFake(now)
+
+	store := NewMemoryUserStore()
+	user1 := msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "user1"}
+	storeTestUser(t, store, user1, now.Add(5*24*time.Hour))
+
+	var reenrollErr error
+	var newKeys []bool
+	reenroller := func(id msp.IdentityIdentifier, newKey bool) error {
+		if reenrollErr != nil {
+			return reenrollErr
+		}
+		newKeys = append(newKeys, newKey)
+		storeTestUser(t, store, id, fakeClock.Now().Add(90*24*time.Hour))
+		return nil
+	}
+
+	opts := CertRenewalOpts{
+		Interval:    time.Hour,
+		RenewBefore: 7 * 24 * time.Hour,
+		NewKey:      true,
+		Identities:  []msp.IdentityIdentifier{user1},
+		Clock:       fakeClock,
+	}
+	if _, err := NewCertRenewalService(store, nil, opts); err == nil {
+		t.Fatal("Expecting error for renewal without reenroller")
+	}
+	service, err := NewCertRenewalService(store, reenroller, opts)
+	if err != nil {
+		t.Fatalf("NewCertRenewalService failed [%s]", err)
+	}
+
+	reenrollErr = errors.New("CA unavailable")
+	service.Check()
+	event := nextCertExpiryEvent(t, service)
+	if event.Identity != user1 || event.Renewed || event.Err == nil {
+		t.Fatalf("Expecting failed re-enrollment, got %+v", event)
+	}
+
+	// The re-enrollment is retried at the next check
+	reenrollErr = nil
+	service.Check()
+	event = nextCertExpiryEvent(t, service)
+	if event.Identity != user1 || !event.Renewed || event.Err != nil || event.NotAfter.Before(now.Add(80*24*time.Hour)) {
+		t.Fatalf("Expecting renewed certificate, got %+v", event)
+	}
+	if len(newKeys) != 1 || !newKeys[0] {
+		t.Fatalf("Expecting one re-enrollment with a new key, got %v", newKeys)
+	}
+
+	service.Check()
+	expectNoCertExpiryEvent(t, service)
+}
+
+func TestCertRenewalStartStop(t *testing.T) {
+	now := time.Now()
+	fakeClock := clock.NewFake(now)
+
+	store := NewMemoryUserStore()
+	user1 := msp.IdentityIdentifier{MSPID: "Org1MSP", ID: "user1"}
+	storeTestUser(t, store, user1, now.Add(48*time.Hour))
+
+	service, err := NewCertRenewalService(store, nil, CertRenewalOpts{Interval: time.Hour, WarnBefore: 24 * time.Hour, Identities: []msp.IdentityIdentifier{user1}, Clock: fakeClock})
+	if err != nil {
+		t.Fatalf("NewCertRenewalService failed [%s]", err)
+	}
+	if err := service.Start(); err != nil {
+		t.Fatalf("Start failed [%s]", err)
+	}
+	if err := service.Start(); err == nil {
+		t.Fatal("Expecting error when starting the service twice")
+	}
+
+	if !fakeClock.BlockUntil(1, time.Second) {
+		t.Fatal("Expecting the service to wait for the next check")
+	}
+	expectNoCertExpiryEvent(t, service)
+
+	fakeClock.Advance(25 * time.Hour)
+	select {
+	case event := <-service.Events():
+		if event.Identity != user1 {
+			t.Fatalf("Unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for expiry warning")
+	}
+
+	service.Stop()
+	if _, ok := <-service.Events(); ok {
+		t.Fatal("Expecting events channel to be closed")
+	}
+	if err := service.Start(); err == nil {
+		t.Fatal("Expecting error when starting a stopped service")
+	}
+	service.Stop()
+}
+
+func storeTestUser(t *testing.T, store msp.UserStore, id msp.IdentityIdentifier, notAfter time.Time) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed [%s]", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: id.ID},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed [%s]", err)
+	}
+	user := &msp.UserData{
+		MSPID: id.MSPID,
+		ID:    id.ID,
+		EnrollmentCertificate: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+	if err := store.Store(user); err != nil {
+		t.Fatalf("Store failed [%s]", err)
+	}
+}
+
+func nextCertExpiryEvent(t *testing.T, service *CertRenewalService) *CertExpiryEvent {
+	select {
+	case event := <-service.Events():
+		return event
+	default:
+		t.Fatal("Expecting certificate expiry event")
+		return nil
+	}
+}
+
+func expectNoCertExpiryEvent(t *testing.T, service *CertRenewalService) {
+	select {
+	case event := <-service.Events():
+		t.Fatalf("Unexpected certificate expiry event %+v", event)
+	default:
+	}
+}
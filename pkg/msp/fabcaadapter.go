@@ -7,10 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	reqContext "context"
+	"time"
+
 	"github.com/pkg/errors"
 
 	caapi "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
 	calib "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
@@ -24,12 +28,16 @@ type fabricCAAdapter struct {
 	caClient    *calib.Client
 }
 
-func newFabricCAAdapter(orgName string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig) (*fabricCAAdapter, error) {
+func newFabricCAAdapter(orgName string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig, transport msp.CATransport) (*fabricCAAdapter, error) {
 
 	caClient, err := createFabricCAClient(orgName, cryptoSuite, config)
 	if err != nil {
 		return nil, err
 	}
+	if transport != nil {
+		// e.g. the recorder of the SDK
+		caClient = caClient.WithTransport(transport)
+	}
 
 	a := &fabricCAAdapter{
 		config:      config,
@@ -39,16 +47,45 @@ func newFabricCAAdapter(orgName string, cryptoSuite core.CryptoSuite, config msp
 	return a, nil
 }
 
+// withRequest returns a copy of the adapter whose requests to the CA are bound to the given
+// context and carry the given request ID
+func (c *fabricCAAdapter) withRequest(ctx reqContext.Context, requestID string) *fabricCAAdapter {
+	return &fabricCAAdapter{
+		config:      c.config,
+		cryptoSuite: c.cryptoSuite,
+		caClient:    c.caClient.WithTransport(withHeaders(map[string]string{audit.RequestIDKey: requestID})).WithTransport(withContext(ctx)),
+	}
+}
+
 // Enroll handles enrollment.
-func (c *fabricCAAdapter) Enroll(enrollmentID string, enrollmentSecret string) ([]byte, error) {
+func (c *fabricCAAdapter) Enroll(request *api.EnrollmentRequest) ([]byte, error) {
 
-	logger.Debugf("Enrolling user [%s]", enrollmentID)
+	logger.Debugf("Enrolling user [%s]", request.Name)
 
-	// TODO add attributes
 	careq := &caapi.EnrollmentRequest{
-		CAName: c.caClient.Config.CAName,
-		Name:   enrollmentID,
-		Secret: enrollmentSecret,
+		CAName:  c.caClient.Config.CAName,
+		Name:    request.Name,
+		Secret:  request.Secret,
+		Profile: request.Profile,
+		Label:   request.Label,
+	}
+	if request.CAName != "" {
+		careq.CAName = request.CAName
+	}
+	for _, attrReq := range request.AttrReqs {
+		careq.AttrReqs = append(careq.AttrReqs, &caapi.AttributeRequest{Name: attrReq.Name, Optional: attrReq.Optional})
+	}
+	if request.CSR != nil {
+		careq.CSR = &caapi.CSRInfo{
+			CN:    request.CSR.CN,
+			Hosts: request.CSR.Hosts,
+		}
+		if request.CSR.KeyRequest != nil {
+			careq.CSR.KeyRequest = &caapi.BasicKeyRequest{
+				Algo: request.CSR.KeyRequest.Algo,
+				Size: request.CSR.KeyRequest.Size,
+			}
+		}
 	}
 	caresp, err := c.caClient.Enroll(careq)
 	if err != nil {
@@ -58,19 +95,35 @@ func (c *fabricCAAdapter) Enroll(enrollmentID string, enrollmentSecret string) (
 }
 
 // Reenroll handles re-enrollment
-func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte) ([]byte, error) {
+// key: private key of the enrolled identity
+// cert: enrollment certificate of the enrolled identity
+// request: Reenrollment Request
+// Returns the new enrollment certificate. A new key is stored in the crypto suite's key store.
+func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte, request *api.ReenrollmentRequest) ([]byte, error) {
 
-	logger.Debugf("Enrolling user [%s]")
+	logger.Debugf("Re-enrolling user [%s]", request.Name)
 
 	careq := &caapi.ReenrollmentRequest{
-		CAName: c.caClient.Config.CAName,
+		CAName:  c.caClient.Config.CAName,
+		Profile: request.Profile,
+		Label:   request.Label,
+	}
+	if request.CAName != "" {
+		careq.CAName = request.CAName
+	}
+	for _, attrReq := range request.AttrReqs {
+		careq.AttrReqs = append(careq.AttrReqs, &caapi.AttributeRequest{Name: attrReq.Name, Optional: attrReq.Optional})
 	}
 	caidentity, err := c.caClient.NewIdentity(key, cert)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to create CA signing identity")
 	}
 
-	caresp, err := caidentity.Reenroll(careq)
+	reenroll := caidentity.ReenrollUsingKey
+	if request.NewKey {
+		reenroll = caidentity.Reenroll
+	}
+	caresp, err := reenroll(careq)
 	if err != nil {
 		return nil, errors.WithMessage(err, "reenroll failed")
 	}
@@ -150,6 +203,351 @@ func (c *fabricCAAdapter) Revoke(key core.Key, cert []byte, request *api.Revocat
 	}, nil
 }
 
+// GetIdentity returns information about the requested identity
+// key: registrar private key
+// cert: registrar enrollment certificate
+func (c *fabricCAAdapter) GetIdentity(key core.Key, cert []byte, id, caname string) (*api.IdentityResponse, error) {
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.GetIdentity(id, caname)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get identity")
+	}
+
+	return &api.IdentityResponse{
+		ID:             resp.ID,
+		Type:           resp.Type,
+		MaxEnrollments: resp.MaxEnrollments,
+		Affiliation:    resp.Affiliation,
+		Attributes:     getAttributes(resp.Attributes),
+		CAName:         resp.CAName,
+	}, nil
+}
+
+// GetAllIdentities returns all identities that the registrar is authorized to see
+// key: registrar private key
+// cert: registrar enrollment certificate
+func (c *fabricCAAdapter) GetAllIdentities(key core.Key, cert []byte, caname string) ([]*api.IdentityResponse, error) {
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.GetAllIdentities(caname)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get identities")
+	}
+
+	var identities []*api.IdentityResponse
+	for _, identity := range resp.Identities {
+		identities = append(identities, &api.IdentityResponse{
+			ID:             identity.ID,
+			Type:           identity.Type,
+			MaxEnrollments: identity.MaxEnrollments,
+			Affiliation:    identity.Affiliation,
+			Attributes:     getAttributes(identity.Attributes),
+			CAName:         resp.CAName,
+		})
+	}
+
+	return identities, nil
+}
+
+// ModifyIdentity modifies an identity registered with the CA
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: Identity Request
+func (c *fabricCAAdapter) ModifyIdentity(key core.Key, cert []byte, request *api.IdentityRequest) (*api.IdentityResponse, error) {
+	var req = caapi.ModifyIdentityRequest{
+		CAName:         request.CAName,
+		ID:             request.ID,
+		Type:           request.Type,
+		MaxEnrollments: request.MaxEnrollments,
+		Affiliation:    request.Affiliation,
+		Secret:         request.Secret,
+		Attributes:     getCAAttributes(request.Attributes),
+	}
+
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.ModifyIdentity(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to modify identity")
+	}
+
+	return getIdentityResponse(resp), nil
+}
+
+// RemoveIdentity removes an identity registered with the CA
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: Remove Identity Request
+func (c *fabricCAAdapter) RemoveIdentity(key core.Key, cert []byte, request *api.RemoveIdentityRequest) (*api.IdentityResponse, error) {
+	var req = caapi.RemoveIdentityRequest{
+		CAName: request.CAName,
+		ID:     request.ID,
+		Force:  request.Force,
+	}
+
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.RemoveIdentity(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to remove identity")
+	}
+
+	return getIdentityResponse(resp), nil
+}
+
+// GetAffiliation returns information about the requested affiliation
+// key: registrar private key
+// cert: registrar enrollment certificate
+func (c *fabricCAAdapter) GetAffiliation(key core.Key, cert []byte, affiliation, caname string) (*api.AffiliationResponse, error) {
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.GetAffiliation(affiliation, caname)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get affiliation")
+	}
+
+	return getAffiliationResponse(resp), nil
+}
+
+// GetAllAffiliations returns all affiliations that the registrar is authorized to see
+// key: registrar private key
+// cert: registrar enrollment certificate
+func (c *fabricCAAdapter) GetAllAffiliations(key core.Key, cert []byte, caname string) (*api.AffiliationResponse, error) {
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.GetAllAffiliations(caname)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get affiliations")
+	}
+
+	return getAffiliationResponse(resp), nil
+}
+
+// GetCAInfo returns the information of the CA
+// caname: The name of the CA to connect to (optional)
+func (c *fabricCAAdapter) GetCAInfo(caname string) (*api.GetCAInfoResponse, error) {
+	req := &caapi.GetCAInfoRequest{CAName: c.caClient.Config.CAName}
+	if caname != "" {
+		req.CAName = caname
+	}
+
+	resp, err := c.caClient.GetCAInfo(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get CA info")
+	}
+
+	return &api.GetCAInfoResponse{
+		CAName:                    resp.CAName,
+		CAChain:                   resp.CAChain,
+		IssuerPublicKey:           resp.IssuerPublicKey,
+		IssuerRevocationPublicKey: resp.IssuerRevocationPublicKey,
+		Version:                   resp.Version,
+	}, nil
+}
+
+// GetCertificates returns the certificates that match the request
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: Certificates Request
+func (c *fabricCAAdapter) GetCertificates(key core.Key, cert []byte, request *api.GetCertificatesRequest) (*api.GetCertificatesResponse, error) {
+	var req = caapi.GetCertificatesRequest{
+		ID:         request.ID,
+		AKI:        request.AKI,
+		Serial:     request.Serial,
+		Revoked:    timeRange(request.RevokedStart, request.RevokedEnd),
+		Expired:    timeRange(request.ExpiredStart, request.ExpiredEnd),
+		NotExpired: request.NotExpired,
+		NotRevoked: request.NotRevoked,
+		CAName:     request.CAName,
+	}
+
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.GetCertificates(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get certificates")
+	}
+
+	certs := make([][]byte, len(resp.Certs))
+	for i, certInfo := range resp.Certs {
+		certs[i] = []byte(certInfo.PEM)
+	}
+	return &api.GetCertificatesResponse{CAName: resp.CAName, Certs: certs}, nil
+}
+
+// GenCRL generates a CRL of the revoked certificates that match the request
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: GenCRL Request
+func (c *fabricCAAdapter) GenCRL(key core.Key, cert []byte, request *api.GenCRLRequest) (*api.GenCRLResponse, error) {
+	var req = caapi.GenCRLRequest{
+		CAName:        request.CAName,
+		RevokedAfter:  request.RevokedAfter,
+		RevokedBefore: request.RevokedBefore,
+		ExpireAfter:   request.ExpireAfter,
+		ExpireBefore:  request.ExpireBefore,
+	}
+
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.GenCRL(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate CRL")
+	}
+
+	return &api.GenCRLResponse{CRL: resp.CRL}, nil
+}
+
+// AddAffiliation adds an affiliation to the CA
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: Affiliation Request
+func (c *fabricCAAdapter) AddAffiliation(key core.Key, cert []byte, request *api.AffiliationRequest) (*api.AffiliationResponse, error) {
+	var req = caapi.AddAffiliationRequest{
+		Name:   request.Name,
+		Force:  request.Force,
+		CAName: request.CAName,
+	}
+
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.AddAffiliation(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to add affiliation")
+	}
+
+	return getAffiliationResponse(resp), nil
+}
+
+// ModifyAffiliation renames an affiliation of the CA
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: Modify Affiliation Request
+func (c *fabricCAAdapter) ModifyAffiliation(key core.Key, cert []byte, request *api.ModifyAffiliationRequest) (*api.AffiliationResponse, error) {
+	var req = caapi.ModifyAffiliationRequest{
+		Name:    request.Name,
+		NewName: request.NewName,
+		Force:   request.Force,
+		CAName:  request.CAName,
+	}
+
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.ModifyAffiliation(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to modify affiliation")
+	}
+
+	return getAffiliationResponse(resp), nil
+}
+
+// RemoveAffiliation removes an affiliation from the CA
+// key: registrar private key
+// cert: registrar enrollment certificate
+// request: Affiliation Request
+func (c *fabricCAAdapter) RemoveAffiliation(key core.Key, cert []byte, request *api.AffiliationRequest) (*api.AffiliationResponse, error) {
+	var req = caapi.RemoveAffiliationRequest{
+		Name:   request.Name,
+		Force:  request.Force,
+		CAName: request.CAName,
+	}
+
+	registrar, err := c.caClient.NewIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	resp, err := registrar.RemoveAffiliation(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to remove affiliation")
+	}
+
+	return getAffiliationResponse(resp), nil
+}
+
+func getAffiliationResponse(resp *caapi.AffiliationResponse) *api.AffiliationResponse {
+	return &api.AffiliationResponse{
+		AffiliationInfo: getAffiliationInfo(resp.AffiliationInfo),
+		CAName:          resp.CAName,
+	}
+}
+
+func getAffiliationInfo(caInfo caapi.AffiliationInfo) api.AffiliationInfo {
+	info := api.AffiliationInfo{Name: caInfo.Name}
+	for _, child := range caInfo.Affiliations {
+		info.Affiliations = append(info.Affiliations, getAffiliationInfo(child))
+	}
+	for _, identity := range caInfo.Identities {
+		info.Identities = append(info.Identities, api.IdentityInfo{
+			ID:             identity.ID,
+			Type:           identity.Type,
+			Affiliation:    identity.Affiliation,
+			Attributes:     getAttributes(identity.Attributes),
+			MaxEnrollments: identity.MaxEnrollments,
+		})
+	}
+	return info
+}
+
+func getIdentityResponse(resp *caapi.IdentityResponse) *api.IdentityResponse {
+	return &api.IdentityResponse{
+		ID:             resp.ID,
+		Type:           resp.Type,
+		MaxEnrollments: resp.MaxEnrollments,
+		Affiliation:    resp.Affiliation,
+		Attributes:     getAttributes(resp.Attributes),
+		Secret:         resp.Secret,
+		CAName:         resp.CAName,
+	}
+}
+
+func getAttributes(caAttributes []caapi.Attribute) []api.Attribute {
+	var attributes []api.Attribute
+	for i := range caAttributes {
+		attributes = append(attributes, api.Attribute{Name: caAttributes[i].Name, Value: caAttributes[i].Value, ECert: caAttributes[i].ECert})
+	}
+	return attributes
+}
+
+func getCAAttributes(attributes []api.Attribute) []caapi.Attribute {
+	var caAttributes []caapi.Attribute
+	for i := range attributes {
+		caAttributes = append(caAttributes, caapi.Attribute{Name: attributes[i].Name, Value: attributes[i].Value, ECert: attributes[i].ECert})
+	}
+	return caAttributes
+}
+
 func createFabricCAClient(org string, cryptoSuite core.CryptoSuite, config msp.IdentityConfig) (*calib.Client, error) {
 
 	// Create new Fabric-ca client without configs
@@ -207,3 +605,15 @@ func createFabricCAClient(org string, cryptoSuite core.CryptoSuite, config msp.I
 
 	return c, nil
 }
+
+// timeRange returns the time range of a certificates request in the format expected by the CA
+func timeRange(start, end time.Time) caapi.TimeRange {
+	var r caapi.TimeRange
+	if !start.IsZero() {
+		r.StartTime = start.UTC().Format(time.RFC3339)
+	}
+	if !end.IsZero() {
+		r.EndTime = end.UTC().Format(time.RFC3339)
+	}
+	return r
+}
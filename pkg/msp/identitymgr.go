@@ -19,15 +19,22 @@ import (
 )
 
 // IdentityManager implements fab/IdentityManager
+//
+// Only X.509 identities are managed. Enrolling Idemix credentials and signing with Idemix identities
+// are not supported since the Idemix MSP and its crypto library are removed from the pinned Fabric
+// code (see the third_party pinning patches), so the SDK can neither build an Idemix credential
+// request nor produce Idemix signatures. The Idemix issuer public keys of a CA are still returned
+// by CAClient.GetCAInfo.
 type IdentityManager struct {
-	orgName         string
-	orgMSPID        string
-	config          fab.EndpointConfig
-	cryptoSuite     core.CryptoSuite
-	embeddedUsers   map[string]endpoint.TLSKeyPair
-	mspPrivKeyStore core.KVStore
-	mspCertStore    core.KVStore
-	userStore       msp.UserStore
+	orgName            string
+	orgMSPID           string
+	config             fab.EndpointConfig
+	cryptoSuite        core.CryptoSuite
+	embeddedUsers      map[string]endpoint.TLSKeyPair
+	cryptoPathTemplate string
+	mspPrivKeyStore    core.KVStore
+	mspCertStore       core.KVStore
+	userStore          msp.UserStore
 }
 
 // NewIdentityManager creates a new instance of IdentityManager
@@ -69,14 +76,15 @@ func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite cor
 	}
 
 	mgr := &IdentityManager{
-		orgName:         orgName,
-		orgMSPID:        orgConfig.MSPID,
-		config:          endpointConfig,
-		cryptoSuite:     cryptoSuite,
-		mspPrivKeyStore: mspPrivKeyStore,
-		mspCertStore:    mspCertStore,
-		embeddedUsers:   orgConfig.Users,
-		userStore:       userStore,
+		orgName:            orgName,
+		orgMSPID:           orgConfig.MSPID,
+		config:             endpointConfig,
+		cryptoSuite:        cryptoSuite,
+		cryptoPathTemplate: orgCryptoPathTemplate,
+		mspPrivKeyStore:    mspPrivKeyStore,
+		mspCertStore:       mspCertStore,
+		embeddedUsers:      orgConfig.Users,
+		userStore:          userStore,
 		// CA Client state is created lazily, when (if) needed
 	}
 	return mgr, nil
@@ -9,7 +9,6 @@ package msp
 import (
 	"fmt"
 	"io/ioutil"
-	"net"
 	"os"
 	"strings"
 	"testing"
@@ -25,12 +24,11 @@ import (
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
 	kvs "github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
 	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
-	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockca"
 )
 
 const (
 	org1               = "Org1"
-	caServerURLListen  = "http://127.0.0.1:0"
 	dummyUserStorePath = "/tmp/userstore"
 	configPath         = "../core/config/testdata/config_test.yaml"
 )
@@ -47,7 +45,7 @@ type textFixture struct {
 	identityManagerProvider msp.IdentityManagerProvider
 }
 
-var caServer = &mockmsp.MockFabricCAServer{}
+var caServer = mockca.New(mockca.WithOpenEnrollment())
 
 func (f *textFixture) setup(configBackend *mocks.MockConfigBackend) { //nolint
 
@@ -59,21 +57,19 @@ func (f *textFixture) setup(configBackend *mocks.MockConfigBackend) { //nolint
 		configBackend = backend
 	}
 
-	var lis net.Listener
-	var err error
+	// Start Http Server if it's not running
 	if !caServer.Running() {
-		lis, err = net.Listen("tcp", strings.TrimPrefix(caServerURLListen, "http://"))
-		if err != nil {
+		if err := caServer.Start(); err != nil {
 			panic(fmt.Sprintf("Error starting CA Server %s", err))
 		}
-
-		caServerURL = "http://" + lis.Addr().String()
+		caServerURL = caServer.URL()
 	}
 
 	updateCAServerURL(caServerURL, configBackend)
 
 	f.cryptSuiteConfig = cryptosuite.ConfigFromBackend(configBackend)
 
+	var err error
 	f.endpointConfig, err = fabImpl.ConfigFromBackend(configBackend)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to read config : %v", err))
@@ -132,11 +128,6 @@ func (f *textFixture) setup(configBackend *mocks.MockConfigBackend) { //nolint
 	if err != nil {
 		panic(fmt.Sprintf("NewCAClient returned error: %v", err))
 	}
-
-	// Start Http Server if it's not running
-	if !caServer.Running() {
-		caServer.Start(lis, f.cryptoSuite)
-	}
 }
 
 func (f *textFixture) close() {
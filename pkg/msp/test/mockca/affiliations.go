@@ -0,0 +1,251 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockca
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+)
+
+func (s *Server) handleAffiliations(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+
+	if _, err := s.authenticate(req); err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		s.mutex.RLock()
+		defer s.mutex.RUnlock()
+		sendResponse(w, &api.AffiliationResponse{AffiliationInfo: s.affiliationInfo(""), CAName: s.opts.caName})
+	case http.MethodPost:
+		s.addAffiliationRequest(w, req)
+	default:
+		sendError(w, http.StatusMethodNotAllowed, "method %s is not allowed", req.Method)
+	}
+}
+
+func (s *Server) handleAffiliation(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+
+	if _, err := s.authenticate(req); err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+	name := strings.TrimPrefix(req.URL.Path, "/affiliations/")
+
+	switch req.Method {
+	case http.MethodGet:
+		s.getAffiliation(w, name)
+	case http.MethodPut:
+		s.modifyAffiliation(w, req, name)
+	case http.MethodDelete:
+		s.removeAffiliation(w, req, name)
+	default:
+		sendError(w, http.StatusMethodNotAllowed, "method %s is not allowed", req.Method)
+	}
+}
+
+// addAffiliationRequest adds an affiliation. The parent affiliation must exist unless the request is forced.
+func (s *Server) addAffiliationRequest(w http.ResponseWriter, req *http.Request) {
+	addReq := &api.AddAffiliationRequestNet{}
+	if err := decodeRequest(req, addReq); err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	if addReq.Name == "" {
+		sendError(w, http.StatusBadRequest, "affiliation name is required")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.affiliations[addReq.Name] {
+		sendError(w, http.StatusBadRequest, "affiliation '%s' already exists", addReq.Name)
+		return
+	}
+	parent := parentAffiliation(addReq.Name)
+	if parent != "" && !s.affiliations[parent] && !isForced(req, addReq.Force) {
+		sendError(w, http.StatusBadRequest, "parent affiliation '%s' does not exist, the request must be forced to create it", parent)
+		return
+	}
+	s.addAffiliation(addReq.Name)
+
+	sendResponse(w, &api.AffiliationResponse{AffiliationInfo: api.AffiliationInfo{Name: addReq.Name}, CAName: s.opts.caName})
+}
+
+func (s *Server) getAffiliation(w http.ResponseWriter, name string) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if !s.affiliations[name] {
+		sendError(w, http.StatusNotFound, "affiliation '%s' does not exist", name)
+		return
+	}
+
+	sendResponse(w, &api.AffiliationResponse{AffiliationInfo: s.affiliationInfo(name), CAName: s.opts.caName})
+}
+
+// modifyAffiliation renames an affiliation and its child affiliations. The request must be forced
+// if identities are affiliated with them, in which case the affiliation of the identities is updated.
+func (s *Server) modifyAffiliation(w http.ResponseWriter, req *http.Request, name string) {
+	modifyReq := &api.ModifyAffiliationRequestNet{}
+	if err := decodeRequest(req, modifyReq); err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	newName := modifyReq.NewName
+	if newName == "" {
+		sendError(w, http.StatusBadRequest, "new affiliation name is required")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.affiliations[name] {
+		sendError(w, http.StatusNotFound, "affiliation '%s' does not exist", name)
+		return
+	}
+	if s.affiliations[newName] {
+		sendError(w, http.StatusBadRequest, "affiliation '%s' already exists", newName)
+		return
+	}
+	affected := s.identitiesOf(name)
+	if len(affected) > 0 && !isForced(req, modifyReq.Force) {
+		sendError(w, http.StatusBadRequest, "identities are affiliated with '%s', the request must be forced to modify it", name)
+		return
+	}
+
+	renamed := s.affiliationsOf(name)
+	for _, aff := range renamed {
+		delete(s.affiliations, aff)
+	}
+	for _, aff := range renamed {
+		s.addAffiliation(newName + strings.TrimPrefix(aff, name))
+	}
+	for _, registered := range affected {
+		registered.info.Affiliation = newName + strings.TrimPrefix(registered.info.Affiliation, name)
+	}
+
+	sendResponse(w, &api.AffiliationResponse{AffiliationInfo: s.affiliationInfo(newName), CAName: s.opts.caName})
+}
+
+// removeAffiliation removes an affiliation. The request must be forced if the affiliation has child affiliations
+// or identities, in which case they are removed as well and the certificates of the identities are revoked.
+func (s *Server) removeAffiliation(w http.ResponseWriter, req *http.Request, name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.affiliations[name] {
+		sendError(w, http.StatusNotFound, "affiliation '%s' does not exist", name)
+		return
+	}
+	info := s.affiliationInfo(name)
+	if (len(info.Affiliations) > 0 || len(info.Identities) > 0) && !isForced(req, false) {
+		sendError(w, http.StatusBadRequest, "affiliation '%s' has child affiliations or identities, the request must be forced to remove it", name)
+		return
+	}
+
+	for _, aff := range s.affiliationsOf(name) {
+		delete(s.affiliations, aff)
+	}
+	for _, registered := range s.identitiesOf(name) {
+		delete(s.identities, registered.info.ID)
+		s.revoke(s.certsOf(registered.info.ID))
+	}
+
+	sendResponse(w, &api.AffiliationResponse{AffiliationInfo: info, CAName: s.opts.caName})
+}
+
+// addAffiliation adds an affiliation and its parent affiliations. The caller must hold the lock, unless the server is being created.
+func (s *Server) addAffiliation(name string) {
+	for aff := name; aff != ""; aff = parentAffiliation(aff) {
+		s.affiliations[aff] = true
+	}
+}
+
+// affiliationInfo returns the given affiliation with its child affiliations and their identities. The root
+// affiliation is the empty string. The caller must hold the lock.
+func (s *Server) affiliationInfo(name string) api.AffiliationInfo {
+	info := api.AffiliationInfo{Name: name}
+
+	var children []string
+	for aff := range s.affiliations {
+		if aff != name && parentAffiliation(aff) == name {
+			children = append(children, aff)
+		}
+	}
+	sort.Strings(children)
+	for _, child := range children {
+		info.Affiliations = append(info.Affiliations, s.affiliationInfo(child))
+	}
+
+	for _, registered := range s.sortedIdentities() {
+		if registered.info.Affiliation == name {
+			info.Identities = append(info.Identities, registered.info)
+		}
+	}
+	return info
+}
+
+// identitiesOf returns the identities affiliated with the given affiliation or its child affiliations. The caller must hold the lock.
+func (s *Server) identitiesOf(name string) []*identity {
+	var affiliated []*identity
+	for _, registered := range s.sortedIdentities() {
+		if isAffiliatedWith(registered.info.Affiliation, name) {
+			affiliated = append(affiliated, registered)
+		}
+	}
+	return affiliated
+}
+
+// affiliationsOf returns the given affiliation and its child affiliations. The caller must hold the lock.
+func (s *Server) affiliationsOf(name string) []string {
+	var affs []string
+	for aff := range s.affiliations {
+		if isAffiliatedWith(aff, name) {
+			affs = append(affs, aff)
+		}
+	}
+	return affs
+}
+
+// sortedIdentities returns the registered identities sorted by enrollment ID. The caller must hold the lock.
+func (s *Server) sortedIdentities() []*identity {
+	sorted := make([]*identity, 0, len(s.identities))
+	for _, registered := range s.identities {
+		sorted = append(sorted, registered)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].info.ID < sorted[j].info.ID
+	})
+	return sorted
+}
+
+// parentAffiliation returns the parent of an affiliation, or the empty string for a top-level affiliation
+func parentAffiliation(name string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		return name[:i]
+	}
+	return ""
+}
+
+// isAffiliatedWith returns true if the affiliation is the given affiliation or one of its child affiliations
+func isAffiliatedWith(affiliation, name string) bool {
+	return affiliation == name || strings.HasPrefix(affiliation, name+".")
+}
+
+func isForced(req *http.Request, force bool) bool {
+	return force || req.URL.Query().Get("force") == "true"
+}
@@ -0,0 +1,180 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockca
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+)
+
+// version is the server version returned by the mock CA
+const version = "1.1.0"
+
+func (s *Server) handleCAInfo(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+	if !checkMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	sendResponse(w, &serverInfoResponseNet{
+		CAName:  s.opts.caName,
+		CAChain: util.B64Encode(s.ca.certPEM),
+		Version: version,
+	})
+}
+
+func (s *Server) handleCertificates(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+	if !checkMethod(w, req, http.MethodGet) {
+		return
+	}
+
+	if _, err := s.authenticate(req); err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+	filter, err := newCertFilter(req.URL.Query(), s.ca.aki)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	certs := []api.CertificateInfo{}
+	for _, ic := range s.certs {
+		if filter.matches(ic) {
+			certs = append(certs, api.CertificateInfo{PEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ic.cert.Raw}))})
+		}
+	}
+	sendResponse(w, &api.CertificatesResponse{CAName: s.opts.caName, Certs: certs})
+}
+
+func (s *Server) handleGenCRL(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+	if !checkMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	if _, err := s.authenticate(req); err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+	genCRLReq := &api.GenCRLRequest{}
+	if err := decodeRequest(req, genCRLReq); err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	now := time.Now()
+	byExpiry := !genCRLReq.ExpireAfter.IsZero() || !genCRLReq.ExpireBefore.IsZero()
+	var revoked []*issuedCert
+	for _, ic := range s.revokedCerts() {
+		if !within(ic.revokedAt, genCRLReq.RevokedAfter, genCRLReq.RevokedBefore) {
+			continue
+		}
+		// Like fabric-ca, only the unexpired certificates are listed unless an expiry period is requested
+		if (byExpiry && !within(ic.cert.NotAfter, genCRLReq.ExpireAfter, genCRLReq.ExpireBefore)) || (!byExpiry && now.After(ic.cert.NotAfter)) {
+			continue
+		}
+		revoked = append(revoked, ic)
+	}
+
+	crl, err := s.ca.crl(revoked, now)
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+	sendResponse(w, &genCRLResponseNet{CRL: util.B64Encode(crl)})
+}
+
+// genCRLResponseNet is the response of the gencrl endpoint
+type genCRLResponseNet struct {
+	// Base64 encoding of PEM-encoded CRL
+	CRL string
+}
+
+// certFilter selects the issued certificates that match the query parameters of a certificates request
+type certFilter struct {
+	id, serial               string
+	otherCA                  bool
+	revokedStart, revokedEnd time.Time
+	expiredStart, expiredEnd time.Time
+	byRevocation, byExpiry   bool
+	notExpired, notRevoked   bool
+}
+
+func newCertFilter(query url.Values, aki string) (*certFilter, error) {
+	// Every certificate is issued by the mock CA, so no certificate matches the key identifier of another CA
+	requestedAKI := strings.ToLower(query.Get("aki"))
+	f := &certFilter{
+		id:         query.Get("id"),
+		serial:     strings.ToLower(strings.TrimLeft(query.Get("serial"), "0")),
+		otherCA:    requestedAKI != "" && requestedAKI != aki,
+		notExpired: query.Get("notexpired") == "true",
+		notRevoked: query.Get("notrevoked") == "true",
+	}
+
+	var err error
+	for _, t := range []struct {
+		param string
+		value *time.Time
+		set   *bool
+	}{
+		{"revoked_start", &f.revokedStart, &f.byRevocation},
+		{"revoked_end", &f.revokedEnd, &f.byRevocation},
+		{"expired_start", &f.expiredStart, &f.byExpiry},
+		{"expired_end", &f.expiredEnd, &f.byExpiry},
+	} {
+		value := query.Get(t.param)
+		if value == "" {
+			continue
+		}
+		if *t.value, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", t.param)
+		}
+		*t.set = true
+	}
+	return f, nil
+}
+
+func (f *certFilter) matches(ic *issuedCert) bool {
+	if f.otherCA || (f.id != "" && ic.id != f.id) {
+		return false
+	}
+	if f.serial != "" && strings.TrimLeft(ic.serial, "0") != f.serial {
+		return false
+	}
+	expired := time.Now().After(ic.cert.NotAfter)
+	if (f.notRevoked && ic.revoked) || (f.notExpired && expired) {
+		return false
+	}
+	if f.byRevocation && (!ic.revoked || !within(ic.revokedAt, f.revokedStart, f.revokedEnd)) {
+		return false
+	}
+	if f.byExpiry && !within(ic.cert.NotAfter, f.expiredStart, f.expiredEnd) {
+		return false
+	}
+	return true
+}
+
+// within returns true if t is within the given period. A zero start or end leaves the period open.
+func within(t, start, end time.Time) bool {
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || !t.After(end))
+}
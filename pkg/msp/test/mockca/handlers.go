@@ -0,0 +1,436 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockca
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	cfsslapi "github.com/cloudflare/cfssl/api"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+)
+
+// enrollmentResponseNet is the response to the enroll and reenroll requests
+type enrollmentResponseNet struct {
+	// Base64 encoded PEM-encoded ECert
+	Cert string
+	// The server information
+	ServerInfo serverInfoResponseNet
+}
+
+// serverInfoResponseNet is the information about the CA returned on enrollment
+type serverInfoResponseNet struct {
+	// CAName is a unique name associated with fabric-ca-server's CA
+	CAName string
+	// Base64 encoding of PEM-encoded certificate chain
+	CAChain string
+	// Version of the server
+	Version string
+}
+
+// revocationResponseNet is the response to the revoke request
+type revocationResponseNet struct {
+	RevokedCerts []api.RevokedCert
+	// Base64 encoding of PEM-encoded CRL
+	CRL string
+}
+
+func (s *Server) handleEnroll(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+	if !checkMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	id, secret, ok := req.BasicAuth()
+	if !ok {
+		sendError(w, http.StatusUnauthorized, "basic authorization header is missing")
+		return
+	}
+	enrollReq := &api.EnrollmentRequestNet{}
+	if err := decodeRequest(req, enrollReq); err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	enrollee, ok := s.identities[id]
+	if !ok && s.opts.openEnrollment {
+		var err error
+		enrollee, err = s.register(&api.RegistrationRequest{Name: id, Secret: secret})
+		if err != nil {
+			sendError(w, http.StatusInternalServerError, "%s", err)
+			return
+		}
+	}
+	if enrollee == nil || enrollee.secret != secret {
+		sendError(w, http.StatusUnauthorized, "authentication failure for identity '%s'", id)
+		return
+	}
+	if enrollee.revoked {
+		sendError(w, http.StatusUnauthorized, "identity '%s' is revoked", id)
+		return
+	}
+	if enrollee.info.MaxEnrollments > 0 && enrollee.enrollments >= enrollee.info.MaxEnrollments {
+		sendError(w, http.StatusUnauthorized, "identity '%s' has reached its maximum number of enrollments", id)
+		return
+	}
+
+	cert, err := s.issue(enrollee, enrollReq)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	enrollee.enrollments++
+
+	s.sendEnrollmentResponse(w, cert)
+}
+
+func (s *Server) handleReenroll(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+	if !checkMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	caller, err := s.authenticate(req)
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+	reenrollReq := &api.EnrollmentRequestNet{}
+	if err = decodeRequest(req, reenrollReq); err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cert, err := s.issue(caller, reenrollReq)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.sendEnrollmentResponse(w, cert)
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+	if !checkMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	if _, err := s.authenticate(req); err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+	registerReq := &api.RegistrationRequest{}
+	if err := decodeRequest(req, registerReq); err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	registered, err := s.register(registerReq)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	sendResponse(w, &api.RegistrationResponseNet{RegistrationResponse: api.RegistrationResponse{Secret: registered.secret}})
+}
+
+func (s *Server) handleRevoke(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+	if !checkMethod(w, req, http.MethodPost) {
+		return
+	}
+
+	if _, err := s.authenticate(req); err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+	revokeReq := &api.RevocationRequest{}
+	if err := decodeRequest(req, revokeReq); err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var revoked []api.RevokedCert
+	switch {
+	case revokeReq.Name != "":
+		revokee, ok := s.identities[revokeReq.Name]
+		if !ok {
+			sendError(w, http.StatusNotFound, "identity '%s' is not registered", revokeReq.Name)
+			return
+		}
+		revokee.revoked = true
+		revoked = s.revoke(s.certsOf(revokeReq.Name))
+	case revokeReq.Serial != "" && revokeReq.AKI != "":
+		if normalizeHex(revokeReq.AKI) != normalizeHex(s.ca.aki) {
+			sendError(w, http.StatusNotFound, "certificate with serial %s and AKI %s was not issued by this CA", revokeReq.Serial, revokeReq.AKI)
+			return
+		}
+		cert := s.certBySerial(revokeReq.Serial)
+		if cert == nil {
+			sendError(w, http.StatusNotFound, "certificate with serial %s was not issued by this CA", revokeReq.Serial)
+			return
+		}
+		revoked = s.revoke([]*issuedCert{cert})
+	default:
+		sendError(w, http.StatusBadRequest, "either the name or the serial and AKI of the certificate to revoke are required")
+		return
+	}
+
+	crl, err := s.ca.crl(s.revokedCerts(), time.Now())
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, "%s", err)
+		return
+	}
+
+	sendResponse(w, &revocationResponseNet{RevokedCerts: revoked, CRL: util.B64Encode(crl)})
+}
+
+func (s *Server) handleIdentities(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+	if !checkMethod(w, req, http.MethodGet) {
+		return
+	}
+
+	if _, err := s.authenticate(req); err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	resp := &api.GetAllIDsResponse{CAName: s.opts.caName}
+	for _, registered := range s.sortedIdentities() {
+		resp.Identities = append(resp.Identities, registered.info)
+	}
+
+	sendResponse(w, resp)
+}
+
+func (s *Server) handleIdentity(w http.ResponseWriter, req *http.Request) {
+	s.storeRequestID(req)
+
+	caller, err := s.authenticate(req)
+	if err != nil {
+		sendError(w, http.StatusUnauthorized, "%s", err)
+		return
+	}
+	id := strings.TrimPrefix(req.URL.Path, "/identities/")
+
+	switch req.Method {
+	case http.MethodGet:
+		s.getIdentity(w, id)
+	case http.MethodPut:
+		s.modifyIdentity(w, req, id)
+	case http.MethodDelete:
+		s.removeIdentity(w, req, id, caller)
+	default:
+		sendError(w, http.StatusMethodNotAllowed, "method %s is not allowed", req.Method)
+	}
+}
+
+func (s *Server) getIdentity(w http.ResponseWriter, id string) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	registered, ok := s.identities[id]
+	if !ok {
+		sendError(w, http.StatusNotFound, "identity '%s' is not registered", id)
+		return
+	}
+
+	info := registered.info
+	sendResponse(w, &api.GetIDResponse{
+		ID:             info.ID,
+		Type:           info.Type,
+		Affiliation:    info.Affiliation,
+		Attributes:     info.Attributes,
+		MaxEnrollments: info.MaxEnrollments,
+		CAName:         s.opts.caName,
+	})
+}
+
+// modifyIdentity updates the fields of the identity that are set in the request. Attributes are
+// merged by name and an attribute with an empty value is removed.
+func (s *Server) modifyIdentity(w http.ResponseWriter, req *http.Request, id string) {
+	modifyReq := &api.ModifyIdentityRequest{}
+	if err := decodeRequest(req, modifyReq); err != nil {
+		sendError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	registered, ok := s.identities[id]
+	if !ok {
+		sendError(w, http.StatusNotFound, "identity '%s' is not registered", id)
+		return
+	}
+
+	info := &registered.info
+	if modifyReq.Type != "" {
+		info.Type = modifyReq.Type
+	}
+	if modifyReq.Affiliation != "" {
+		info.Affiliation = modifyReq.Affiliation
+	}
+	if modifyReq.MaxEnrollments != 0 {
+		info.MaxEnrollments = modifyReq.MaxEnrollments
+	}
+	if modifyReq.Secret != "" {
+		registered.secret = modifyReq.Secret
+	}
+	info.Attributes = mergeAttributes(info.Attributes, modifyReq.Attributes)
+
+	sendResponse(w, &api.IdentityResponse{
+		ID:             info.ID,
+		Type:           info.Type,
+		Affiliation:    info.Affiliation,
+		Attributes:     info.Attributes,
+		MaxEnrollments: info.MaxEnrollments,
+		Secret:         modifyReq.Secret,
+		CAName:         s.opts.caName,
+	})
+}
+
+// removeIdentity removes the identity and revokes its certificates. Callers must force the removal of their own identity.
+func (s *Server) removeIdentity(w http.ResponseWriter, req *http.Request, id string, caller *identity) {
+	if caller.info.ID == id && req.URL.Query().Get("force") != "true" {
+		sendError(w, http.StatusBadRequest, "removal of the caller's own identity '%s' must be forced", id)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	registered, ok := s.identities[id]
+	if !ok {
+		sendError(w, http.StatusNotFound, "identity '%s' is not registered", id)
+		return
+	}
+	delete(s.identities, id)
+	s.revoke(s.certsOf(id))
+
+	info := registered.info
+	sendResponse(w, &api.IdentityResponse{
+		ID:             info.ID,
+		Type:           info.Type,
+		Affiliation:    info.Affiliation,
+		Attributes:     info.Attributes,
+		MaxEnrollments: info.MaxEnrollments,
+		CAName:         s.opts.caName,
+	})
+}
+
+// certBySerial returns the issued certificate with the given hex-encoded serial number. The caller must hold the lock.
+func (s *Server) certBySerial(serial string) *issuedCert {
+	serial = normalizeHex(serial)
+	for _, ic := range s.certs {
+		if ic.serial == serial {
+			return ic
+		}
+	}
+	return nil
+}
+
+func (s *Server) sendEnrollmentResponse(w http.ResponseWriter, cert string) {
+	sendResponse(w, &enrollmentResponseNet{
+		Cert: util.B64Encode([]byte(cert)),
+		ServerInfo: serverInfoResponseNet{
+			CAName:  s.opts.caName,
+			CAChain: util.B64Encode(s.ca.certPEM),
+		},
+	})
+}
+
+func mergeAttributes(attrs []api.Attribute, updates []api.Attribute) []api.Attribute {
+	merged := make([]api.Attribute, 0, len(attrs)+len(updates))
+	updated := make(map[string]api.Attribute)
+	for _, attr := range updates {
+		updated[attr.Name] = attr
+	}
+	for _, attr := range attrs {
+		if update, ok := updated[attr.Name]; ok {
+			delete(updated, attr.Name)
+			if update.Value == "" {
+				continue
+			}
+			attr = update
+		}
+		merged = append(merged, attr)
+	}
+	for _, attr := range updates {
+		if _, ok := updated[attr.Name]; ok && attr.Value != "" {
+			merged = append(merged, attr)
+		}
+	}
+	return merged
+}
+
+// certFromToken returns the certificate of an authorization token, which is made of
+// the base64-encoded certificate and signature separated by a dot
+func certFromToken(token string) (*x509.Certificate, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, errors.New("invalid authorization token")
+	}
+	certPEM, err := util.B64Decode(parts[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid certificate in authorization token")
+	}
+	cert, err := util.GetX509CertificateFromPEM(certPEM)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid certificate in authorization token")
+	}
+	return cert, nil
+}
+
+func checkMethod(w http.ResponseWriter, req *http.Request, method string) bool {
+	if req.Method != method {
+		sendError(w, http.StatusMethodNotAllowed, "method %s is not allowed", req.Method)
+		return false
+	}
+	return true
+}
+
+func sendResponse(w http.ResponseWriter, result interface{}) {
+	if err := cfsslapi.SendResponse(w, result); err != nil {
+		logger.Errorf("failed to send response: %s", err)
+	}
+}
+
+func sendError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	logger.Debugf("mock CA request failed: %s", msg)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(cfsslapi.NewErrorResponse(msg, status)); err != nil {
+		logger.Errorf("failed to send error response: %s", err)
+	}
+}
@@ -0,0 +1,389 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mockca provides an in-process mock fabric-ca server for unit tests. The server implements
+// the enroll, reenroll, register, revoke, gencrl, cainfo and certificates endpoints as well as the identity
+// and affiliation management endpoints of fabric-ca over plain HTTP. It keeps the registered identities, the
+// affiliations and the issued certificates in memory and signs the certificate requests of the
+// clients with a fixed CA key, so that the issued certificates match the keys generated by the
+// clients. The certificates are deterministic: their serial numbers are sequential, their subject
+// is made of the enrollment ID and type of the identity and their validity period is fixed.
+//
+// The mock does not verify the signatures of authorization tokens: the caller of an authenticated
+// request is the enrollment ID of the certificate in the token, which is rejected only if it was
+// revoked by the mock. Any caller may register, modify, remove and revoke identities and
+// manage affiliations.
+//
+//  Basic Flow:
+//  1) Create and start the server
+//  2) Point the URL of the certificate authority in the SDK configuration at the URL of the server
+//  3) Stop the server at the end of the test
+//
+//  server := mockca.New(mockca.WithCAName("ca.org1.example.com"))
+//  if err := server.Start(); err != nil {
+//      t.Fatal(err)
+//  }
+//  defer server.Stop()
+//
+//  caConfig.URL = server.URL()
+package mockca
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/audit"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+)
+
+var logger = logging.NewLogger("fabsdk/mockca")
+
+// Server is an in-process mock fabric-ca server
+type Server struct {
+	opts          options
+	ca            *ca
+	mutex         sync.RWMutex
+	identities    map[string]*identity
+	affiliations  map[string]bool
+	certs         []*issuedCert
+	nextSerial    int64
+	listener      net.Listener
+	server        *http.Server
+	lastRequestID atomic.Value
+}
+
+// identity is an identity registered with the mock CA
+type identity struct {
+	info        api.IdentityInfo
+	secret      string
+	enrollments int
+	revoked     bool
+}
+
+// Opt is a server option
+type Opt func(o *options)
+
+// WithAddress sets the host on which the server listens (127.0.0.1 by default). The server listens on a random port.
+func WithAddress(host string) Opt {
+	return func(o *options) {
+		o.host = host
+	}
+}
+
+// WithCAName sets the name of the CA returned to the clients
+func WithCAName(name string) Opt {
+	return func(o *options) {
+		o.caName = name
+	}
+}
+
+// WithIdentity registers an identity with the given enrollment ID and secret when the server is created.
+// The bootstrap identity "admin" with secret "adminpw" is always registered.
+func WithIdentity(id, secret string) Opt {
+	return func(o *options) {
+		o.identities = append(o.identities, api.RegistrationRequest{Name: id, Secret: secret})
+	}
+}
+
+// WithAffiliation adds an affiliation and its parent affiliations when the server is created. The server is
+// created with the default affiliations of fabric-ca: org1, org1.department1, org1.department2, org2 and org2.department1.
+func WithAffiliation(name string) Opt {
+	return func(o *options) {
+		o.affiliations = append(o.affiliations, name)
+	}
+}
+
+// WithOpenEnrollment allows identities that are not registered to enroll with any secret.
+// These identities are registered on their first enrollment.
+func WithOpenEnrollment() Opt {
+	return func(o *options) {
+		o.openEnrollment = true
+	}
+}
+
+// WithValidity sets the validity period of the issued certificates (by default, the validity period of the CA certificate)
+func WithValidity(notBefore, notAfter time.Time) Opt {
+	return func(o *options) {
+		o.notBefore = notBefore
+		o.notAfter = notAfter
+	}
+}
+
+type options struct {
+	host           string
+	caName         string
+	identities     []api.RegistrationRequest
+	affiliations   []string
+	openEnrollment bool
+	notBefore      time.Time
+	notAfter       time.Time
+}
+
+// New returns a new mock CA server. The server must be started before it's used.
+func New(opts ...Opt) *Server {
+	c := newCA()
+
+	o := options{
+		host:         "127.0.0.1",
+		identities:   []api.RegistrationRequest{{Name: "admin", Secret: "adminpw"}},
+		affiliations: []string{"org1.department1", "org1.department2", "org2.department1"},
+		notBefore:    c.cert.NotBefore,
+		notAfter:     c.cert.NotAfter,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := &Server{
+		opts:         o,
+		ca:           c,
+		identities:   make(map[string]*identity),
+		affiliations: make(map[string]bool),
+		nextSerial:   firstSerial,
+	}
+	for _, name := range o.affiliations {
+		s.addAffiliation(name)
+	}
+	for i := range o.identities {
+		if _, err := s.register(&o.identities[i]); err != nil {
+			panic(err)
+		}
+	}
+	return s
+}
+
+// Start starts serving requests
+func (s *Server) Start() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.listener != nil {
+		return errors.New("server is already started")
+	}
+
+	lis, err := net.Listen("tcp", s.opts.host+":0")
+	if err != nil {
+		return errors.Wrap(err, "failed to listen")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/enroll", s.handleEnroll)
+	mux.HandleFunc("/reenroll", s.handleReenroll)
+	mux.HandleFunc("/register", s.handleRegister)
+	mux.HandleFunc("/revoke", s.handleRevoke)
+	mux.HandleFunc("/identities", s.handleIdentities)
+	mux.HandleFunc("/identities/", s.handleIdentity)
+	mux.HandleFunc("/affiliations", s.handleAffiliations)
+	mux.HandleFunc("/affiliations/", s.handleAffiliation)
+	mux.HandleFunc("/cainfo", s.handleCAInfo)
+	mux.HandleFunc("/certificates", s.handleCertificates)
+	mux.HandleFunc("/gencrl", s.handleGenCRL)
+
+	s.listener = lis
+	s.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("mock CA server failed: %s", err)
+		}
+	}()
+	logger.Infof("Mock CA server started on %s", lis.Addr())
+	return nil
+}
+
+// Stop stops serving requests
+func (s *Server) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.server == nil {
+		return
+	}
+	if err := s.server.Close(); err != nil {
+		logger.Warnf("failed to stop mock CA server: %s", err)
+	}
+	s.server = nil
+	s.listener = nil
+}
+
+// Running returns true if the server is started
+func (s *Server) Running() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.listener != nil
+}
+
+// URL returns the URL of the server. The server must be started.
+func (s *Server) URL() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.listener == nil {
+		return ""
+	}
+	return "http://" + s.listener.Addr().String()
+}
+
+// CACert returns the PEM-encoded certificate of the CA which signs the issued certificates
+func (s *Server) CACert() []byte {
+	return s.ca.certPEM
+}
+
+// LastRequestID returns the request ID header of the last request
+func (s *Server) LastRequestID() string {
+	requestID, _ := s.lastRequestID.Load().(string)
+	return requestID
+}
+
+// Secret returns the enrollment secret of a registered identity
+func (s *Server) Secret(id string) (string, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	registered, ok := s.identities[id]
+	if !ok {
+		return "", false
+	}
+	return registered.secret, true
+}
+
+// register registers an identity. The caller must hold the lock, unless the server is being created.
+// If the request has no secret, the secret is the enrollment ID followed by "pw".
+func (s *Server) register(req *api.RegistrationRequest) (*identity, error) {
+	if req.Name == "" {
+		return nil, errors.New("enrollment ID is required")
+	}
+	if _, ok := s.identities[req.Name]; ok {
+		return nil, errors.Errorf("identity '%s' is already registered", req.Name)
+	}
+
+	identityType := req.Type
+	if identityType == "" {
+		identityType = "client"
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret = req.Name + "pw"
+	}
+
+	registered := &identity{
+		info: api.IdentityInfo{
+			ID:             req.Name,
+			Type:           identityType,
+			Affiliation:    req.Affiliation,
+			Attributes:     req.Attributes,
+			MaxEnrollments: req.MaxEnrollments,
+		},
+		secret: secret,
+	}
+	s.identities[req.Name] = registered
+	return registered, nil
+}
+
+// issue issues a certificate to the given identity. The caller must hold the lock.
+func (s *Server) issue(enrollee *identity, req *api.EnrollmentRequestNet) (string, error) {
+	issued, certPEM, err := s.ca.issue(&certRequest{
+		csr:       req.Request,
+		hosts:     req.Hosts,
+		serial:    s.nextSerial,
+		identity:  enrollee,
+		notBefore: s.opts.notBefore,
+		notAfter:  s.opts.notAfter,
+		attrReqs:  req.AttrReqs,
+	})
+	if err != nil {
+		return "", err
+	}
+	s.nextSerial++
+	s.certs = append(s.certs, issued)
+	return string(certPEM), nil
+}
+
+// authenticate returns the registered identity of the caller of a token-authenticated request.
+// Callers which are not registered with the mock CA, such as identities configured in the SDK,
+// are returned as identities of type client.
+func (s *Server) authenticate(req *http.Request) (*identity, error) {
+	token := req.Header.Get("authorization")
+	if token == "" {
+		return nil, errors.New("authorization header is missing")
+	}
+	cert, err := certFromToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	serial := serialString(cert.SerialNumber)
+	for _, ic := range s.certs {
+		if ic.serial == serial && ic.revoked {
+			return nil, errors.Errorf("certificate with serial %s is revoked", serial)
+		}
+	}
+
+	id := cert.Subject.CommonName
+	if caller, ok := s.identities[id]; ok {
+		if caller.revoked {
+			return nil, errors.Errorf("identity '%s' is revoked", id)
+		}
+		return caller, nil
+	}
+	return &identity{info: api.IdentityInfo{ID: id, Type: "client"}}, nil
+}
+
+// revoke revokes the given certificates. The caller must hold the lock.
+func (s *Server) revoke(certs []*issuedCert) []api.RevokedCert {
+	var revoked []api.RevokedCert
+	for _, ic := range certs {
+		if ic.revoked {
+			continue
+		}
+		ic.revoked = true
+		ic.revokedAt = time.Now()
+		revoked = append(revoked, api.RevokedCert{Serial: ic.serial, AKI: s.ca.aki})
+	}
+	return revoked
+}
+
+// revokedCerts returns all the revoked certificates. The caller must hold the lock.
+func (s *Server) revokedCerts() []*issuedCert {
+	var revoked []*issuedCert
+	for _, ic := range s.certs {
+		if ic.revoked {
+			revoked = append(revoked, ic)
+		}
+	}
+	return revoked
+}
+
+// certsOf returns the certificates issued to the given identity. The caller must hold the lock.
+func (s *Server) certsOf(id string) []*issuedCert {
+	var certs []*issuedCert
+	for _, ic := range s.certs {
+		if ic.id == id {
+			certs = append(certs, ic)
+		}
+	}
+	return certs
+}
+
+func (s *Server) storeRequestID(req *http.Request) {
+	s.lastRequestID.Store(req.Header.Get(audit.RequestIDKey))
+}
+
+func decodeRequest(req *http.Request, v interface{}) error {
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil {
+		return errors.Wrap(err, "failed to decode request body")
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mockca
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
+	calib "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	bccspSw "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+)
+
+func TestEnrollRegisterRevoke(t *testing.T) {
+	server := New(WithCAName("ca.example.com"))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client := newClient(t, server)
+
+	_, err := client.Enroll(&api.EnrollmentRequest{Name: "admin", Secret: "wrong"})
+	assert.Error(t, err, "expecting enrollment with a wrong secret to fail")
+
+	resp, err := client.Enroll(&api.EnrollmentRequest{Name: "admin", Secret: "adminpw"})
+	require.NoError(t, err)
+	assert.Equal(t, "ca.example.com", resp.ServerInfo.CAName)
+	assert.Equal(t, server.CACert(), resp.ServerInfo.CAChain)
+
+	cert := parseCert(t, resp.Identity.GetECert().Cert())
+	assert.Equal(t, "admin", cert.Subject.CommonName)
+	assert.Equal(t, int64(firstSerial), cert.SerialNumber.Int64())
+	registrar := resp.Identity
+
+	regResp, err := registrar.Register(&api.RegistrationRequest{Name: "user1", Type: "client", Attributes: []api.Attribute{{Name: "role", Value: "tester", ECert: true}}})
+	require.NoError(t, err)
+	assert.Equal(t, "user1pw", regResp.Secret)
+
+	_, err = registrar.Register(&api.RegistrationRequest{Name: "user1"})
+	assert.Error(t, err, "expecting the registration of a registered identity to fail")
+
+	resp, err = client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: regResp.Secret})
+	require.NoError(t, err)
+	cert = parseCert(t, resp.Identity.GetECert().Cert())
+	assert.Equal(t, "user1", cert.Subject.CommonName)
+	assert.Equal(t, int64(firstSerial+1), cert.SerialNumber.Int64())
+	assert.True(t, server.opts.notAfter.Equal(cert.NotAfter), "unexpected certificate expiry")
+	assert.NoError(t, cert.CheckSignatureFrom(server.ca.cert))
+	assert.True(t, hasAttrs(cert), "expecting attributes in the certificate")
+	user := resp.Identity
+
+	resp, err = user.Reenroll(&api.ReenrollmentRequest{})
+	require.NoError(t, err)
+	cert = parseCert(t, resp.Identity.GetECert().Cert())
+	assert.Equal(t, "user1", cert.Subject.CommonName)
+	assert.Equal(t, int64(firstSerial+2), cert.SerialNumber.Int64())
+
+	revResp, err := registrar.Revoke(&api.RevocationRequest{Name: "user1"})
+	require.NoError(t, err)
+	assert.Len(t, revResp.RevokedCerts, 2)
+	crlBlock, _ := pem.Decode(revResp.CRL)
+	require.NotNil(t, crlBlock)
+	crl, err := x509.ParseCRL(crlBlock.Bytes)
+	require.NoError(t, err)
+	assert.Len(t, crl.TBSCertList.RevokedCertificates, 2)
+
+	_, err = user.Reenroll(&api.ReenrollmentRequest{})
+	assert.Error(t, err, "expecting reenrollment with a revoked certificate to fail")
+
+	_, err = client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: regResp.Secret})
+	assert.Error(t, err, "expecting enrollment of a revoked identity to fail")
+
+	adminCert := parseCert(t, registrar.GetECert().Cert())
+	revResp, err = registrar.Revoke(&api.RevocationRequest{Serial: adminCert.SerialNumber.Text(16), AKI: server.ca.aki})
+	require.NoError(t, err)
+	assert.Len(t, revResp.RevokedCerts, 1)
+}
+
+func TestEnrollAttributeRequests(t *testing.T) {
+	server := New()
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client := newClient(t, server)
+
+	resp, err := client.Enroll(&api.EnrollmentRequest{Name: "admin", Secret: "adminpw"})
+	require.NoError(t, err)
+	_, err = resp.Identity.Register(&api.RegistrationRequest{Name: "user1", Type: "client", Attributes: []api.Attribute{{Name: "role", Value: "tester"}}})
+	require.NoError(t, err)
+
+	resp, err = client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: "user1pw"})
+	require.NoError(t, err)
+	attrs := certAttrs(t, parseCert(t, resp.Identity.GetECert().Cert()))
+	assert.Equal(t, "client", attrs["hf.Type"])
+	assert.NotContains(t, attrs, "role", "expecting only the ecert attributes without attribute requests")
+
+	attrReqs := []*api.AttributeRequest{{Name: "role"}, {Name: "unknown", Optional: true}}
+	resp, err = client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: "user1pw", AttrReqs: attrReqs})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"role": "tester"}, certAttrs(t, parseCert(t, resp.Identity.GetECert().Cert())))
+
+	_, err = client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: "user1pw", AttrReqs: []*api.AttributeRequest{{Name: "unknown"}}})
+	assert.Error(t, err, "expecting enrollment to fail for a required attribute that the identity doesn't have")
+}
+
+func TestOpenEnrollment(t *testing.T) {
+	server := New(WithOpenEnrollment(), WithValidity(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client := newClient(t, server)
+
+	resp, err := client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: "anysecret"})
+	require.NoError(t, err)
+	cert := parseCert(t, resp.Identity.GetECert().Cert())
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), cert.NotAfter.UTC())
+
+	secret, ok := server.Secret("user1")
+	assert.True(t, ok, "expecting identity to be registered on enrollment")
+	assert.Equal(t, "anysecret", secret)
+
+	_, err = client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: "othersecret"})
+	assert.Error(t, err, "expecting registered identities to enroll with their secret")
+}
+
+func TestIdentities(t *testing.T) {
+	server := New(WithIdentity("user1", "user1pw"))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client := newClient(t, server)
+	resp, err := client.Enroll(&api.EnrollmentRequest{Name: "admin", Secret: "adminpw"})
+	require.NoError(t, err)
+	registrar := resp.Identity
+
+	ids, err := registrar.GetAllIdentities("")
+	require.NoError(t, err)
+	require.Len(t, ids.Identities, 2)
+	assert.Equal(t, "admin", ids.Identities[0].ID)
+	assert.Equal(t, "user1", ids.Identities[1].ID)
+
+	modResp, err := registrar.ModifyIdentity(&api.ModifyIdentityRequest{ID: "user1", Affiliation: "org1", Attributes: []api.Attribute{{Name: "role", Value: "tester"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "org1", modResp.Affiliation)
+	assert.Len(t, modResp.Attributes, 1)
+
+	modResp, err = registrar.ModifyIdentity(&api.ModifyIdentityRequest{ID: "user1", Attributes: []api.Attribute{{Name: "role"}}})
+	require.NoError(t, err)
+	assert.Empty(t, modResp.Attributes)
+
+	id, err := registrar.GetIdentity("user1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "org1", id.Affiliation)
+
+	_, err = registrar.RemoveIdentity(&api.RemoveIdentityRequest{ID: "admin"})
+	assert.Error(t, err, "expecting removal of the caller's identity without force to fail")
+
+	_, err = registrar.RemoveIdentity(&api.RemoveIdentityRequest{ID: "user1"})
+	require.NoError(t, err)
+
+	_, err = registrar.GetIdentity("user1", "")
+	assert.Error(t, err, "expecting removed identity to be unknown")
+}
+
+func TestAffiliations(t *testing.T) {
+	server := New(WithAffiliation("org3.department1"))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client := newClient(t, server)
+	resp, err := client.Enroll(&api.EnrollmentRequest{Name: "admin", Secret: "adminpw"})
+	require.NoError(t, err)
+	registrar := resp.Identity
+
+	all, err := registrar.GetAllAffiliations("")
+	require.NoError(t, err)
+	require.Len(t, all.Affiliations, 3)
+	assert.Equal(t, "org1", all.Affiliations[0].Name)
+	assert.Equal(t, "org3", all.Affiliations[2].Name)
+	assert.Equal(t, "org3.department1", all.Affiliations[2].Affiliations[0].Name)
+
+	_, err = registrar.AddAffiliation(&api.AddAffiliationRequest{Name: "org4.department1"})
+	assert.Error(t, err, "expecting the addition of an affiliation without parent to fail")
+	_, err = registrar.AddAffiliation(&api.AddAffiliationRequest{Name: "org4.department1", Force: true})
+	require.NoError(t, err)
+
+	_, err = registrar.Register(&api.RegistrationRequest{Name: "user1", Affiliation: "org4.department1"})
+	require.NoError(t, err)
+
+	_, err = registrar.ModifyAffiliation(&api.ModifyAffiliationRequest{Name: "org4", NewName: "org5"})
+	assert.Error(t, err, "expecting the modification of an affiliation with identities to require force")
+	aff, err := registrar.ModifyAffiliation(&api.ModifyAffiliationRequest{Name: "org4", NewName: "org5", Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, "org5", aff.Name)
+	require.Len(t, aff.Affiliations, 1)
+	assert.Equal(t, "org5.department1", aff.Affiliations[0].Name)
+	require.Len(t, aff.Affiliations[0].Identities, 1)
+	assert.Equal(t, "org5.department1", aff.Affiliations[0].Identities[0].Affiliation)
+
+	_, err = registrar.RemoveAffiliation(&api.RemoveAffiliationRequest{Name: "org5"})
+	assert.Error(t, err, "expecting the removal of an affiliation with children to require force")
+	_, err = registrar.RemoveAffiliation(&api.RemoveAffiliationRequest{Name: "org5", Force: true})
+	require.NoError(t, err)
+
+	_, err = registrar.GetAffiliation("org5.department1", "")
+	assert.Error(t, err, "expecting removed affiliation to be unknown")
+	_, err = registrar.GetIdentity("user1", "")
+	assert.Error(t, err, "expecting identities of a removed affiliation to be removed")
+}
+
+func TestCAInfoAndCertificates(t *testing.T) {
+	server := New(WithCAName("ca.org1.example.com"))
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client := newClient(t, server)
+	info, err := client.GetCAInfo(&api.GetCAInfoRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "ca.org1.example.com", info.CAName)
+	assert.Equal(t, caCert, string(info.CAChain))
+	assert.Equal(t, version, info.Version)
+
+	resp, err := client.Enroll(&api.EnrollmentRequest{Name: "admin", Secret: "adminpw"})
+	require.NoError(t, err)
+	registrar := resp.Identity
+
+	_, err = registrar.Register(&api.RegistrationRequest{Name: "user1", Secret: "user1pw"})
+	require.NoError(t, err)
+	_, err = client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: "user1pw"})
+	require.NoError(t, err)
+
+	certs, err := registrar.GetCertificates(&api.GetCertificatesRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "ca.org1.example.com", certs.CAName)
+	assert.Len(t, certs.Certs, 2)
+
+	certs, err = registrar.GetCertificates(&api.GetCertificatesRequest{ID: "user1"})
+	require.NoError(t, err)
+	require.Len(t, certs.Certs, 1)
+	block, _ := pem.Decode([]byte(certs.Certs[0].PEM))
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", cert.Subject.CommonName)
+
+	_, err = registrar.Revoke(&api.RevocationRequest{Name: "user1"})
+	require.NoError(t, err)
+
+	certs, err = registrar.GetCertificates(&api.GetCertificatesRequest{NotRevoked: true})
+	require.NoError(t, err)
+	assert.Len(t, certs.Certs, 1)
+	certs, err = registrar.GetCertificates(&api.GetCertificatesRequest{Revoked: api.TimeRange{StartTime: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)}})
+	require.NoError(t, err)
+	assert.Len(t, certs.Certs, 1)
+	certs, err = registrar.GetCertificates(&api.GetCertificatesRequest{AKI: "0102"})
+	require.NoError(t, err)
+	assert.Len(t, certs.Certs, 0)
+
+	_, err = registrar.GetCertificates(&api.GetCertificatesRequest{Expired: api.TimeRange{StartTime: "yesterday"}})
+	assert.Error(t, err, "expecting invalid time to be rejected")
+}
+
+func TestGenCRL(t *testing.T) {
+	server := New()
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client := newClient(t, server)
+	resp, err := client.Enroll(&api.EnrollmentRequest{Name: "admin", Secret: "adminpw"})
+	require.NoError(t, err)
+	registrar := resp.Identity
+
+	_, err = registrar.Register(&api.RegistrationRequest{Name: "user1", Secret: "user1pw"})
+	require.NoError(t, err)
+	_, err = client.Enroll(&api.EnrollmentRequest{Name: "user1", Secret: "user1pw"})
+	require.NoError(t, err)
+	_, err = registrar.Revoke(&api.RevocationRequest{Name: "user1"})
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		req     api.GenCRLRequest
+		revoked int
+	}{
+		{api.GenCRLRequest{}, 1},
+		{api.GenCRLRequest{RevokedAfter: time.Now().Add(-time.Hour), RevokedBefore: time.Now().Add(time.Hour)}, 1},
+		{api.GenCRLRequest{RevokedAfter: time.Now().Add(time.Hour)}, 0},
+		{api.GenCRLRequest{ExpireBefore: time.Now()}, 0},
+	} {
+		crlResp, err := registrar.GenCRL(&test.req)
+		require.NoError(t, err)
+		block, _ := pem.Decode(crlResp.CRL)
+		require.NotNil(t, block)
+		crl, err := x509.ParseCRL(block.Bytes)
+		require.NoError(t, err)
+		assert.Len(t, crl.TBSCertList.RevokedCertificates, test.revoked, "unexpected CRL for request %+v", test.req)
+	}
+}
+
+func newClient(t *testing.T, server *Server) *calib.Client {
+	client := &calib.Client{
+		Config: &calib.ClientConfig{URL: server.URL()},
+	}
+	client.Config.MSPDir = t.Name()
+	client.HomeDir = "/tmp/mockca"
+
+	// The enrollment keys are generated by the client so the key store must be writable
+	keyStore, err := bccspSw.NewFileBasedKeyStore(nil, filepath.Join(client.HomeDir, t.Name(), "keystore"), false)
+	require.NoError(t, err)
+	cs, err := sw.GetSuite(256, "SHA2", keyStore)
+	require.NoError(t, err)
+	client.Config.CSP = cs
+	require.NoError(t, client.Init())
+	return client
+}
+
+func parseCert(t *testing.T, certPEM []byte) *x509.Certificate {
+	cert, err := util.GetX509CertificateFromPEM(certPEM)
+	require.NoError(t, err)
+	return cert
+}
+
+func hasAttrs(cert *x509.Certificate) bool {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(attrOID) {
+			return true
+		}
+	}
+	return false
+}
+
+func certAttrs(t *testing.T, cert *x509.Certificate) map[string]string {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(attrOID) {
+			attrs := struct {
+				Attrs map[string]string `json:"attrs"`
+			}{}
+			require.NoError(t, json.Unmarshal(ext.Value, &attrs))
+			return attrs.Attrs
+		}
+	}
+	t.Fatal("attribute extension not found")
+	return nil
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"sync"
+
 	"github.com/golang/protobuf/proto"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -21,6 +23,9 @@ type User struct {
 	mspID                 string
 	enrollmentCertificate []byte
 	privateKey            core.Key
+	serializeOnce         sync.Once
+	serialized            []byte
+	serializeErr          error
 }
 
 // Identifier returns user identifier
@@ -33,17 +38,23 @@ func (u *User) Verify(msg []byte, sig []byte) error {
 	return errors.New("not implemented")
 }
 
-// Serialize converts an identity to bytes
+// Serialize converts an identity to bytes. The identity is marshalled once and the same
+// bytes are returned by subsequent calls, so the returned bytes must not be modified.
 func (u *User) Serialize() ([]byte, error) {
-	serializedIdentity := &pb_msp.SerializedIdentity{
-		Mspid:   u.mspID,
-		IdBytes: u.enrollmentCertificate,
-	}
-	identity, err := proto.Marshal(serializedIdentity)
-	if err != nil {
-		return nil, errors.Wrap(err, "marshal serializedIdentity failed")
-	}
-	return identity, nil
+	u.serializeOnce.Do(func() {
+		serializedIdentity := &pb_msp.SerializedIdentity{
+			Mspid:   u.mspID,
+			IdBytes: u.enrollmentCertificate,
+		}
+		identity, err := proto.Marshal(serializedIdentity)
+		if err != nil {
+			u.serializeErr = errors.Wrap(err, "marshal serializedIdentity failed")
+			return
+		}
+		// The capacity is limited so that appending to the bytes never modifies the cached identity
+		u.serialized = identity[:len(identity):len(identity)]
+	})
+	return u.serialized, u.serializeErr
 }
 
 // EnrollmentCertificate Returns the underlying ECert representing this user’s identity.
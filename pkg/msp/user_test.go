@@ -10,12 +10,14 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	cryptosuiteimpl "github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	pb_msp "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
 )
 
 func TestUserMethods(t *testing.T) {
@@ -109,3 +111,32 @@ func verifyBytes(t *testing.T, v interface{}, expected []byte) error {
 	}
 	return nil
 }
+
+func TestUserSerializeMemoized(t *testing.T) {
+	user := &User{id: "user1", mspID: "Org1MSP", enrollmentCertificate: []byte("cert")}
+
+	serialized1, err := user.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	serialized2, err := user.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if &serialized1[0] != &serialized2[0] {
+		t.Fatal("Expected the serialized identity to be reused")
+	}
+
+	// Appending to the serialized identity must not modify the cached identity
+	if cap(serialized1) != len(serialized1) {
+		t.Fatal("Expected the capacity of the serialized identity to be limited")
+	}
+
+	identity := &pb_msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serialized2, identity); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if identity.Mspid != "Org1MSP" || !bytes.Equal(identity.IdBytes, []byte("cert")) {
+		t.Fatalf("Unexpected serialized identity: %v", identity)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PEMFormat is the format of the user data that only consists of the PEM encoded enrollment
+	// certificate. It is the default format of the user stores.
+	PEMFormat = "pem"
+	// JSONFormat is the format of the user data that is encoded as a JSON object (see JSONCodec)
+	JSONFormat = "json"
+
+	encryptionAlgorithm = "AES-GCM"
+)
+
+// UserDataCodec encodes the user data that is persisted by the user stores (see WithCodec).
+// The codec determines the format of the stored credentials, e.g. in order to inspect them
+// or to integrate them with existing secret formats.
+type UserDataCodec interface {
+	// Encode encodes the given user data
+	Encode(userData *msp.UserData) ([]byte, error)
+	// Decode decodes the data that was stored for the given identity
+	Decode(key msp.IdentityIdentifier, data []byte) (*msp.UserData, error)
+}
+
+// UserDataCodecForFormat returns the codec of the given format (PEMFormat if empty)
+func UserDataCodecForFormat(format string) (UserDataCodec, error) {
+	switch format {
+	case "", PEMFormat:
+		return PEMCodec{}, nil
+	case JSONFormat:
+		return JSONCodec{}, nil
+	default:
+		return nil, errors.Errorf("unsupported user data format [%s]", format)
+	}
+}
+
+// PEMCodec stores the PEM encoded enrollment certificate as is. The ID and MSP ID of the user
+// are only part of the key under which the certificate is stored.
+type PEMCodec struct{}
+
+// Encode returns the enrollment certificate
+func (c PEMCodec) Encode(userData *msp.UserData) ([]byte, error) {
+	return userData.EnrollmentCertificate, nil
+}
+
+// Decode returns the user data of the given identity with the given enrollment certificate
+func (c PEMCodec) Decode(key msp.IdentityIdentifier, data []byte) (*msp.UserData, error) {
+	return &msp.UserData{
+		ID:                    key.ID,
+		MSPID:                 key.MSPID,
+		EnrollmentCertificate: data,
+	}, nil
+}
+
+// JSONCodec stores the user data as a JSON object, e.g.
+//  {"id": "user1", "mspid": "Org1MSP", "enrollmentCertificate": "-----BEGIN CERTIFICATE-----\n..."}
+// Data that was stored in the PEM format is decoded as well, so that an existing store can be
+// switched to the JSON format.
+type JSONCodec struct{}
+
+type jsonUserData struct {
+	ID                    string `json:"id"`
+	MSPID                 string `json:"mspid"`
+	EnrollmentCertificate string `json:"enrollmentCertificate"`
+}
+
+// Encode encodes the user data as JSON
+func (c JSONCodec) Encode(userData *msp.UserData) ([]byte, error) {
+	data, err := json.Marshal(&jsonUserData{
+		ID:                    userData.ID,
+		MSPID:                 userData.MSPID,
+		EnrollmentCertificate: string(userData.EnrollmentCertificate),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding user data failed")
+	}
+	return data, nil
+}
+
+// Decode decodes user data that was encoded as JSON or stored in the PEM format
+func (c JSONCodec) Decode(key msp.IdentityIdentifier, data []byte) (*msp.UserData, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return PEMCodec{}.Decode(key, data)
+	}
+
+	userData := &jsonUserData{}
+	if err := json.Unmarshal(data, userData); err != nil {
+		return nil, errors.Wrap(err, "decoding user data failed")
+	}
+	if userData.ID != key.ID || userData.MSPID != key.MSPID {
+		return nil, errors.Errorf("stored user data is for [%s@%s]", userData.ID, userData.MSPID)
+	}
+	return &msp.UserData{
+		ID:                    userData.ID,
+		MSPID:                 userData.MSPID,
+		EnrollmentCertificate: []byte(userData.EnrollmentCertificate),
+	}, nil
+}
+
+// EncryptedCodec encrypts the data encoded by another codec with AES-GCM. The encrypted data is
+// stored as a JSON object, e.g.
+//  {"alg": "AES-GCM", "nonce": "...", "ciphertext": "..."}
+// The ID and MSP ID of the user are authenticated along with the data, so that encrypted data
+// can't be moved to the key of another user.
+type EncryptedCodec struct {
+	codec UserDataCodec
+	aead  cipher.AEAD
+}
+
+type encryptedUserData struct {
+	Algorithm  string `json:"alg"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NewEncryptedCodec returns a codec that encrypts the data encoded by the given codec with the given
+// AES key, which must be 16, 24 or 32 bytes long. NewEncryptedCodec(JSONCodec{}, key) stores the user
+// data as encrypted JSON.
+func NewEncryptedCodec(codec UserDataCodec, key []byte) (*EncryptedCodec, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid encryption key")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AES-GCM cipher failed")
+	}
+	return &EncryptedCodec{codec: codec, aead: aead}, nil
+}
+
+// Encode encodes the user data with the underlying codec and encrypts it
+func (c *EncryptedCodec) Encode(userData *msp.UserData) ([]byte, error) {
+	plaintext, err := c.codec.Encode(userData)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "generating nonce failed")
+	}
+
+	data, err := json.Marshal(&encryptedUserData{
+		Algorithm:  encryptionAlgorithm,
+		Nonce:      nonce,
+		Ciphertext: c.aead.Seal(nil, nonce, plaintext, additionalData(msp.IdentityIdentifier{ID: userData.ID, MSPID: userData.MSPID})),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding encrypted user data failed")
+	}
+	return data, nil
+}
+
+// Decode decrypts the data and decodes it with the underlying codec
+func (c *EncryptedCodec) Decode(key msp.IdentityIdentifier, data []byte) (*msp.UserData, error) {
+	encrypted := &encryptedUserData{}
+	if err := json.Unmarshal(data, encrypted); err != nil {
+		return nil, errors.Wrap(err, "user data is not encrypted")
+	}
+	if encrypted.Algorithm != encryptionAlgorithm {
+		return nil, errors.Errorf("unsupported encryption algorithm [%s]", encrypted.Algorithm)
+	}
+	if len(encrypted.Nonce) != c.aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+
+	plaintext, err := c.aead.Open(nil, encrypted.Nonce, encrypted.Ciphertext, additionalData(key))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting user data failed")
+	}
+	return c.codec.Decode(key, plaintext)
+}
+
+func additionalData(key msp.IdentityIdentifier) []byte {
+	return []byte(storeKeyFromUserIdentifier(key))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+)
+
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestStoreWithCodecs(t *testing.T) {
+	encrypted, err := NewEncryptedCodec(JSONCodec{}, testEncryptionKey)
+	if err != nil {
+		t.Fatalf("NewEncryptedCodec failed [%s]", err)
+	}
+
+	for name, codec := range map[string]UserDataCodec{"pem": PEMCodec{}, "json": JSONCodec{}, "encrypted": encrypted} {
+		cleanupTestPath(t, storePathRoot)
+
+		store, err := NewCertFileUserStore(storePath, WithCodec(codec))
+		if err != nil {
+			t.Fatalf("NewCertFileUserStore failed [%s]", err)
+		}
+		user1 := &msp.UserData{MSPID: "Org1", ID: "user1", EnrollmentCertificate: []byte(testCert1)}
+		if err := store.Store(user1); err != nil {
+			t.Fatalf("Store with %s codec failed [%s]", name, err)
+		}
+		user, err := store.Load(msp.IdentityIdentifier{MSPID: "Org1", ID: "user1"})
+		if err != nil {
+			t.Fatalf("Load with %s codec failed [%s]", name, err)
+		}
+		if !reflect.DeepEqual(user1, user) {
+			t.Fatalf("Unexpected user loaded with %s codec: %v", name, user)
+		}
+
+		data, err := ioutil.ReadFile(path.Join(storePath, "user1@Org1-cert.pem"))
+		if err != nil {
+			t.Fatalf("Reading stored user failed [%s]", err)
+		}
+		if name == "encrypted" && bytes.Contains(data, []byte("BEGIN CERTIFICATE")) {
+			t.Fatalf("Expecting certificate to be encrypted: %s", data)
+		}
+	}
+	cleanupTestPath(t, storePathRoot)
+}
+
+func TestJSONCodec(t *testing.T) {
+	key := msp.IdentityIdentifier{MSPID: "Org1", ID: "user1"}
+
+	data, err := JSONCodec{}.Encode(&msp.UserData{MSPID: "Org1", ID: "user1", EnrollmentCertificate: []byte(testCert1)})
+	if err != nil {
+		t.Fatalf("Encode failed [%s]", err)
+	}
+	stored := make(map[string]string)
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("Expecting JSON object [%s]", err)
+	}
+	if stored["id"] != "user1" || stored["mspid"] != "Org1" || stored["enrollmentCertificate"] != testCert1 {
+		t.Fatalf("Unexpected JSON: %s", data)
+	}
+
+	user, err := JSONCodec{}.Decode(key, []byte(testCert1))
+	if err != nil || string(user.EnrollmentCertificate) != testCert1 {
+		t.Fatalf("Expecting data stored in PEM format to be decoded [%v]", err)
+	}
+
+	if _, err := (JSONCodec{}).Decode(msp.IdentityIdentifier{MSPID: "Org1", ID: "user2"}, data); err == nil {
+		t.Fatal("Expecting error for data of another user")
+	}
+}
+
+func TestEncryptedCodec(t *testing.T) {
+	if _, err := NewEncryptedCodec(JSONCodec{}, []byte("short")); err == nil {
+		t.Fatal("Expecting error for invalid key size")
+	}
+
+	codec, err := NewEncryptedCodec(PEMCodec{}, testEncryptionKey)
+	if err != nil {
+		t.Fatalf("NewEncryptedCodec failed [%s]", err)
+	}
+	data, err := codec.Encode(&msp.UserData{MSPID: "Org1", ID: "user1", EnrollmentCertificate: []byte(testCert1)})
+	if err != nil {
+		t.Fatalf("Encode failed [%s]", err)
+	}
+
+	if _, err := codec.Decode(msp.IdentityIdentifier{MSPID: "Org1", ID: "user2"}, data); err == nil {
+		t.Fatal("Expecting error when decrypting the data of another user")
+	}
+	if _, err := codec.Decode(msp.IdentityIdentifier{MSPID: "Org1", ID: "user1"}, []byte(testCert1)); err == nil {
+		t.Fatal("Expecting error when decoding data that isn't encrypted")
+	}
+
+	otherCodec, err := NewEncryptedCodec(PEMCodec{}, []byte("fedcba9876543210fedcba9876543210"))
+	if err != nil {
+		t.Fatalf("NewEncryptedCodec failed [%s]", err)
+	}
+	if _, err := otherCodec.Decode(msp.IdentityIdentifier{MSPID: "Org1", ID: "user1"}, data); err == nil {
+		t.Fatal("Expecting error when decrypting with another key")
+	}
+}
+
+func TestUserDataCodecForFormat(t *testing.T) {
+	for _, format := range []string{"", PEMFormat, JSONFormat} {
+		if _, err := UserDataCodecForFormat(format); err != nil {
+			t.Fatalf("UserDataCodecForFormat failed for [%s]: %s", format, err)
+		}
+	}
+	if _, err := UserDataCodecForFormat("xml"); err == nil {
+		t.Fatal("Expecting error for unsupported format")
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
+	"github.com/pkg/errors"
+)
+
+const (
+	// FileUserStoreType is the type of the built-in user store that stores each user in a separate
+	// file under client.credentialStore.path. It is used when no type is configured.
+	FileUserStoreType = "file"
+	// CouchDBUserStoreType is the type of the built-in user store that stores each user in a separate
+	// document of the CouchDB database configured under client.credentialStore.couchdb, so that the
+	// enrolled users can be shared by several instances of an application
+	CouchDBUserStoreType = "couchdb"
+	// RedisUserStoreType is the type of the built-in user store that stores each user under a separate
+	// key of the Redis database configured under client.credentialStore.redis
+	RedisUserStoreType = "redis"
+)
+
+// UserStoreFactory creates a user store from the credential store configuration. The crypto suite is
+// the SDK's crypto suite; it's nil if the user store is created without one (see NewUserStore).
+//
+// The user store (see msp.UserStore) is the extension point for persisting enrolled users. A custom
+// implementation is plugged in by registering its factory with RegisterUserStore and setting
+// client.credentialStore.type to the name it was registered under. Only the enrollment certificates
+// are kept in the user store; the private keys are kept in the crypto suite's key store.
+type UserStoreFactory func(config msp.CredentialStoreType, cryptoSuite core.CryptoSuite) (msp.UserStore, error)
+
+var userStores = struct {
+	sync.RWMutex
+	registry map[string]UserStoreFactory
+}{
+	registry: map[string]UserStoreFactory{
+		FileUserStoreType:    newFileUserStore,
+		CouchDBUserStoreType: newCouchDBUserStore,
+		RedisUserStoreType:   newRedisUserStore,
+	},
+}
+
+// RegisterUserStore registers a user store factory under the given type so that it can be selected
+// in the configuration (client.credentialStore.type). A factory that was registered under the same
+// type is replaced.
+func RegisterUserStore(storeType string, factory UserStoreFactory) {
+	userStores.Lock()
+	defer userStores.Unlock()
+	userStores.registry[storeType] = factory
+}
+
+// NewUserStore creates the user store of the configured type. The user store is wrapped with
+// an in-memory cache (see CachedUserStore) if client.credentialStore.cache.enabled is set.
+// The crypto suite holds the key that encrypts the user data if client.credentialStore.encryption.keySKI
+// is set; it may be nil otherwise.
+func NewUserStore(config msp.CredentialStoreType, cryptoSuite core.CryptoSuite) (msp.UserStore, error) {
+	storeType := config.Type
+	if storeType == "" {
+		storeType = FileUserStoreType
+	}
+
+	userStores.RLock()
+	factory, ok := userStores.registry[storeType]
+	userStores.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("user store type [%s] is not registered", storeType)
+	}
+	userStore, err := factory(config, cryptoSuite)
+	if err != nil {
+		return nil, err
+	}
+	if config.Cache.Enabled {
+		return NewCachedUserStore(userStore, config.Cache.TTL), nil
+	}
+	return userStore, nil
+}
+
+func newFileUserStore(config msp.CredentialStoreType, cryptoSuite core.CryptoSuite) (msp.UserStore, error) {
+	store, err := keyvaluestore.New(&keyvaluestore.FileKeyValueStoreOptions{Path: config.Path})
+	if err != nil {
+		return nil, errors.WithMessage(err, "CreateNewFileKeyValueStore failed")
+	}
+	return newCertUserStore(config, store, cryptoSuite)
+}
+
+func newCouchDBUserStore(config msp.CredentialStoreType, cryptoSuite core.CryptoSuite) (msp.UserStore, error) {
+	store, err := keyvaluestore.NewCouchDB(&keyvaluestore.CouchDBKeyValueStoreOptions{
+		URL:      config.CouchDB.URL,
+		Database: config.CouchDB.Database,
+		Username: config.CouchDB.Username,
+		Password: config.CouchDB.Password,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "CouchDB user store creation failed")
+	}
+	// The documents are keyed like the files of the file based store
+	return newCertUserStore(config, store, cryptoSuite)
+}
+
+func newRedisUserStore(config msp.CredentialStoreType, cryptoSuite core.CryptoSuite) (msp.UserStore, error) {
+	store, err := keyvaluestore.NewRedis(&keyvaluestore.RedisKeyValueStoreOptions{
+		Address:   config.Redis.Address,
+		Password:  config.Redis.Password,
+		Database:  config.Redis.Database,
+		KeyPrefix: config.Redis.KeyPrefix,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "Redis user store creation failed")
+	}
+	return newCertUserStore(config, store, cryptoSuite)
+}
+
+// newCertUserStore returns a user store that stores the users in the given store in the configured
+// format. The user data (including the ID and MSP ID of the user) is encrypted if configured.
+func newCertUserStore(config msp.CredentialStoreType, store core.KVStore, cryptoSuite core.CryptoSuite) (msp.UserStore, error) {
+	format := config.Format
+	if format == "" && config.Encryption != (msp.UserStoreEncryptionConfig{}) {
+		format = JSONFormat
+	}
+	codec, err := UserDataCodecForFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	codec, err = encryptionCodec(config, store, codec, cryptoSuite)
+	if err != nil {
+		return nil, err
+	}
+	return NewCertFileUserStore1(store, WithCodec(codec))
+}
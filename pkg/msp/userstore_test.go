@@ -0,0 +1,58 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"path"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+)
+
+func TestNewUserStoreDefaultType(t *testing.T) {
+	cleanupTestPath(t, storePathRoot)
+	defer cleanupTestPath(t, storePathRoot)
+
+	userStore, err := NewUserStore(msp.CredentialStoreType{Path: path.Join(storePathRoot, "users")}, nil)
+	if err != nil {
+		t.Fatalf("NewUserStore failed [%s]", err)
+	}
+	if _, ok := userStore.(*CertFileUserStore); !ok {
+		t.Fatalf("Expecting file user store by default, got %T", userStore)
+	}
+
+	if _, err := NewUserStore(msp.CredentialStoreType{Type: FileUserStoreType}, nil); err == nil {
+		t.Fatal("Expecting error for file user store without path")
+	}
+	if _, err := NewUserStore(msp.CredentialStoreType{Type: CouchDBUserStoreType}, nil); err == nil {
+		t.Fatal("Expecting error for CouchDB user store without URL")
+	}
+}
+
+func TestRegisterUserStore(t *testing.T) {
+	if _, err := NewUserStore(msp.CredentialStoreType{Type: "memory"}, nil); err == nil {
+		t.Fatal("Expecting error for unregistered user store type")
+	}
+
+	var config msp.CredentialStoreType
+	RegisterUserStore("memory", func(c msp.CredentialStoreType, cs core.CryptoSuite) (msp.UserStore, error) {
+		config = c
+		return NewMemoryUserStore(), nil
+	})
+
+	userStore, err := NewUserStore(msp.CredentialStoreType{Type: "memory", Path: "unused"}, nil)
+	if err != nil {
+		t.Fatalf("NewUserStore failed [%s]", err)
+	}
+	if _, ok := userStore.(*MemoryUserStore); !ok {
+		t.Fatalf("Expecting registered user store, got %T", userStore)
+	}
+	if config.Path != "unused" {
+		t.Fatal("Expecting credential store config to be passed to the factory")
+	}
+}
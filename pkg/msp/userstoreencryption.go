@@ -0,0 +1,149 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// saltKey is the key under which the salt of the passphrase is kept in the store. It can't
+	// clash with the keys of the users, which end with "-cert.pem".
+	saltKey = "userstore.salt"
+
+	saltSize         = 16
+	encryptionKeyLen = 32
+	pbkdf2Iterations = 100000
+)
+
+// PassphraseKey derives a 256 bit AES key from the given passphrase and salt with PBKDF2 (SHA-256)
+func PassphraseKey(passphrase string, salt []byte) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, pbkdf2Iterations, encryptionKeyLen, sha256.New)
+}
+
+// CryptoSuiteKey returns the raw bytes of the symmetric key with the given SKI from the crypto
+// suite's key store. The key must be exportable, which isn't the case of hardware (PKCS11) keys.
+func CryptoSuiteKey(cs core.CryptoSuite, ski []byte) ([]byte, error) {
+	key, err := cs.GetKey(ski)
+	if err != nil {
+		return nil, errors.WithMessage(err, "encryption key not found in crypto suite")
+	}
+	if !key.Symmetric() {
+		return nil, errors.New("encryption key must be a symmetric key")
+	}
+	raw, err := key.Bytes()
+	if err != nil {
+		return nil, errors.WithMessage(err, "encryption key is not exportable")
+	}
+	return raw, nil
+}
+
+// kvStoreCreator is implemented by the stores that are able to store a value only if no value is
+// stored for the key (see keyvaluestore.FileKeyValueStore.StoreIfAbsent)
+type kvStoreCreator interface {
+	StoreIfAbsent(key interface{}, value interface{}) (bool, error)
+}
+
+// encryptionCodec returns the codec that encrypts the user data (and encodes it with the given codec)
+// if encryption is configured. The salt of a passphrase is generated on first use and kept in the
+// given store, so that all of the instances that share the store derive the same key. The key with
+// the configured SKI is taken from the given crypto suite.
+func encryptionCodec(config msp.CredentialStoreType, store core.KVStore, codec UserDataCodec, cryptoSuite core.CryptoSuite) (UserDataCodec, error) {
+	encryption := config.Encryption
+	if encryption.Passphrase == "" && encryption.KeySKI == "" {
+		return codec, nil
+	}
+	if encryption.Passphrase != "" && encryption.KeySKI != "" {
+		return nil, errors.New("either a passphrase or the SKI of a key may be configured for the user store encryption")
+	}
+
+	var key []byte
+	if encryption.KeySKI != "" {
+		if cryptoSuite == nil {
+			return nil, errors.New("a crypto suite is required to retrieve the encryption key")
+		}
+		ski, err := hex.DecodeString(encryption.KeySKI)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid encryption key SKI")
+		}
+		key, err = CryptoSuiteKey(cryptoSuite, ski)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		salt, err := loadSalt(store)
+		if err != nil {
+			return nil, err
+		}
+		key = PassphraseKey(encryption.Passphrase, salt)
+	}
+	return NewEncryptedCodec(codec, key)
+}
+
+// loadSalt returns the stored salt, or generates and stores a new salt. The salt is created atomically
+// if the store supports it; otherwise it's read back after it's stored and loading fails if another
+// instance stored a different salt concurrently.
+func loadSalt(store core.KVStore) ([]byte, error) {
+	salt, err := storedSalt(store)
+	if err != core.ErrKeyValueNotFound {
+		return salt, err
+	}
+
+	newSalt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, newSalt); err != nil {
+		return nil, errors.Wrap(err, "generating salt failed")
+	}
+
+	if creator, ok := store.(kvStoreCreator); ok {
+		created, err := creator.StoreIfAbsent(saltKey, newSalt)
+		if err != nil {
+			return nil, errors.WithMessage(err, "storing salt failed")
+		}
+		if created {
+			return newSalt, nil
+		}
+		// Another instance stored its salt first
+		return storedSalt(store)
+	}
+
+	if err := store.Store(saltKey, newSalt); err != nil {
+		return nil, errors.WithMessage(err, "storing salt failed")
+	}
+	salt, err = storedSalt(store)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(salt, newSalt) {
+		return nil, errors.New("salt was stored concurrently by another instance of the user store")
+	}
+	return salt, nil
+}
+
+// storedSalt returns the stored salt or core.ErrKeyValueNotFound
+func storedSalt(store core.KVStore) ([]byte, error) {
+	salt, err := store.Load(saltKey)
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, err
+		}
+		return nil, errors.WithMessage(err, "loading salt failed")
+	}
+	saltBytes, ok := salt.([]byte)
+	if !ok || len(saltBytes) == 0 {
+		return nil, errors.New("stored salt is not of proper type")
+	}
+	return saltBytes, nil
+}
@@ -0,0 +1,232 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/pkg/errors"
+)
+
+func TestEncryptedFileUserStore(t *testing.T) {
+	cleanupTestPath(t, storePathRoot)
+	defer cleanupTestPath(t, storePathRoot)
+
+	config := msp.CredentialStoreType{
+		Path:       storePath,
+		Encryption: msp.UserStoreEncryptionConfig{Passphrase: "passphrase"},
+	}
+	userStore, err := NewUserStore(config, nil)
+	if err != nil {
+		t.Fatalf("NewUserStore failed [%s]", err)
+	}
+	user1 := &msp.UserData{MSPID: "Org1", ID: "user1", EnrollmentCertificate: []byte(testCert1)}
+	if err := userStore.Store(user1); err != nil {
+		t.Fatalf("Store failed [%s]", err)
+	}
+
+	data, err := ioutil.ReadFile(path.Join(storePath, "user1@Org1-cert.pem"))
+	if err != nil {
+		t.Fatalf("Reading stored user failed [%s]", err)
+	}
+	if bytes.Contains(data, []byte("CERTIFICATE")) || bytes.Contains(data, []byte("Org1")) {
+		t.Fatalf("Expecting user data to be encrypted: %s", data)
+	}
+	if _, err := os.Stat(path.Join(storePath, saltKey)); err != nil {
+		t.Fatalf("Expecting salt to be stored [%s]", err)
+	}
+
+	// The salt is reused by another instance of the store
+	userStore, err = NewUserStore(config, nil)
+	if err != nil {
+		t.Fatalf("NewUserStore failed [%s]", err)
+	}
+	user, err := userStore.Load(msp.IdentityIdentifier{MSPID: "Org1", ID: "user1"})
+	if err != nil {
+		t.Fatalf("Load failed [%s]", err)
+	}
+	if string(user.EnrollmentCertificate) != testCert1 {
+		t.Fatal("Unexpected enrollment certificate")
+	}
+
+	config.Encryption.Passphrase = "wrong"
+	userStore, err = NewUserStore(config, nil)
+	if err != nil {
+		t.Fatalf("NewUserStore failed [%s]", err)
+	}
+	if _, err := userStore.Load(msp.IdentityIdentifier{MSPID: "Org1", ID: "user1"}); err == nil {
+		t.Fatal("Expecting error when loading with the wrong passphrase")
+	}
+
+	config.Encryption.KeySKI = "0102"
+	if _, err := NewUserStore(config, nil); err == nil {
+		t.Fatal("Expecting error when both a passphrase and a key are configured")
+	}
+}
+
+func TestEncryptionCodecKeySKI(t *testing.T) {
+	config := msp.CredentialStoreType{Encryption: msp.UserStoreEncryptionConfig{KeySKI: "0102"}}
+	if _, err := encryptionCodec(config, nil, JSONCodec{}, nil); err == nil {
+		t.Fatal("Expecting error when the key is configured without a crypto suite")
+	}
+
+	cryptoSuite := &keyCryptoSuite{key: &symmetricKey{raw: bytes.Repeat([]byte{1}, 32)}}
+	codec, err := encryptionCodec(config, nil, JSONCodec{}, cryptoSuite)
+	if err != nil {
+		t.Fatalf("encryptionCodec failed [%s]", err)
+	}
+	if _, ok := codec.(*EncryptedCodec); !ok {
+		t.Fatalf("Expecting encrypted codec, got %T", codec)
+	}
+}
+
+func TestLoadSalt(t *testing.T) {
+	store := newMapKVStore()
+	salt, err := loadSalt(store)
+	if err != nil {
+		t.Fatalf("loadSalt failed [%s]", err)
+	}
+	if len(salt) != saltSize {
+		t.Fatalf("Unexpected salt size %d", len(salt))
+	}
+	stored, err := loadSalt(store)
+	if err != nil || !bytes.Equal(salt, stored) {
+		t.Fatalf("Expecting the stored salt to be reused [%v]", err)
+	}
+
+	// Another instance stores its salt between the store and the load of this instance
+	store = newMapKVStore()
+	store.concurrentValue = []byte("concurrent-salt")
+	if _, err := loadSalt(store); err == nil {
+		t.Fatal("Expecting error when the salt is stored concurrently")
+	}
+
+	// Another instance creates its salt first
+	creator := &creatorKVStore{mapKVStore: newMapKVStore()}
+	creator.concurrentValue = []byte("concurrent-salt")
+	salt, err = loadSalt(creator)
+	if err != nil {
+		t.Fatalf("loadSalt failed [%s]", err)
+	}
+	if string(salt) != "concurrent-salt" {
+		t.Fatalf("Expecting the salt of the other instance, got %x", salt)
+	}
+}
+
+// mapKVStore is an in-memory KV store. If a concurrent value is set then it replaces the stored values,
+// as if another instance stored its value concurrently.
+type mapKVStore struct {
+	values          map[interface{}]interface{}
+	concurrentValue []byte
+}
+
+func newMapKVStore() *mapKVStore {
+	return &mapKVStore{values: make(map[interface{}]interface{})}
+}
+
+func (s *mapKVStore) Store(key interface{}, value interface{}) error {
+	if s.concurrentValue != nil {
+		value = s.concurrentValue
+	}
+	s.values[key] = value
+	return nil
+}
+
+func (s *mapKVStore) Load(key interface{}) (interface{}, error) {
+	value, ok := s.values[key]
+	if !ok {
+		return nil, core.ErrKeyValueNotFound
+	}
+	return value, nil
+}
+
+func (s *mapKVStore) Delete(key interface{}) error {
+	delete(s.values, key)
+	return nil
+}
+
+// creatorKVStore is an in-memory KV store that is able to create values atomically
+type creatorKVStore struct {
+	*mapKVStore
+}
+
+func (s *creatorKVStore) StoreIfAbsent(key interface{}, value interface{}) (bool, error) {
+	if s.concurrentValue != nil {
+		s.values[key] = s.concurrentValue
+		return false, nil
+	}
+	if _, ok := s.values[key]; ok {
+		return false, nil
+	}
+	s.values[key] = value
+	return true, nil
+}
+
+func TestCryptoSuiteKey(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	raw, err := CryptoSuiteKey(&keyCryptoSuite{key: &symmetricKey{raw: key}}, []byte("ski"))
+	if err != nil {
+		t.Fatalf("CryptoSuiteKey failed [%s]", err)
+	}
+	if !bytes.Equal(key, raw) {
+		t.Fatal("Unexpected key")
+	}
+
+	if _, err := CryptoSuiteKey(&keyCryptoSuite{key: &symmetricKey{}}, []byte("ski")); err == nil {
+		t.Fatal("Expecting error for key that isn't exportable")
+	}
+	if _, err := CryptoSuiteKey(&keyCryptoSuite{}, []byte("ski")); err == nil {
+		t.Fatal("Expecting error for unknown key")
+	}
+}
+
+type keyCryptoSuite struct {
+	fcmocks.MockCryptoSuite
+	key core.Key
+}
+
+func (cs *keyCryptoSuite) GetKey(ski []byte) (core.Key, error) {
+	if cs.key == nil {
+		return nil, errors.New("key not found")
+	}
+	return cs.key, nil
+}
+
+type symmetricKey struct {
+	raw []byte
+}
+
+func (k *symmetricKey) Bytes() ([]byte, error) {
+	if k.raw == nil {
+		return nil, errors.New("not exportable")
+	}
+	return k.raw, nil
+}
+
+func (k *symmetricKey) SKI() []byte {
+	return []byte("ski")
+}
+
+func (k *symmetricKey) Symmetric() bool {
+	return true
+}
+
+func (k *symmetricKey) Private() bool {
+	return true
+}
+
+func (k *symmetricKey) PublicKey() (core.Key, error) {
+	return nil, errors.New("not supported")
+}
@@ -12,12 +12,20 @@ import (
 	"sync/atomic"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/metrics"
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/futurevalue"
 	"github.com/pkg/errors"
 )
 
 var logger = logging.NewLogger("fabsdk/util")
 
+var cacheRequests = metrics.NewCounter(metrics.CounterOpts{
+	Subsystem:  "cache",
+	Name:       "requests_total",
+	Help:       "The number of cache lookups, by cache and result (hit or miss).",
+	LabelNames: []string{"cache", "result"},
+})
+
 // Key holds the string key for the cache entry
 type Key interface {
 	String() string
@@ -72,6 +80,7 @@ func (c *Cache) Get(key Key) (interface{}, error) {
 
 	f, ok := c.m.Load(keyStr)
 	if ok {
+		cacheRequests.With(c.name, "hit").Add(1)
 		return f.(future).Get()
 	}
 
@@ -88,8 +97,10 @@ func (c *Cache) Get(key Key) (interface{}, error) {
 	f, loaded := c.m.LoadOrStore(keyStr, newFuture)
 	if loaded {
 		// Another thread has added the key before us. Return the value.
+		cacheRequests.With(c.name, "hit").Add(1)
 		return f.(future).Get()
 	}
+	cacheRequests.With(c.name, "miss").Add(1)
 
 	// We added the key. It must be initailized.
 	value, err := newFuture.Initialize()
@@ -113,6 +124,61 @@ func (c *Cache) MustGet(key Key) interface{} {
 	return value
 }
 
+// Delete removes the entry for the given key from the cache and
+// calls Close on the value if it implements a Close() function.
+// A subsequent Get for the key creates a new value.
+func (c *Cache) Delete(key Key) {
+	keyStr := key.String()
+
+	f, ok := c.m.Load(keyStr)
+	if !ok {
+		return
+	}
+
+	c.m.Delete(keyStr)
+	c.close(keyStr, f.(future))
+}
+
+// Clear removes all entries from the cache and calls Close on the values
+// that implement a Close() function. Unlike Close, the cache remains usable.
+func (c *Cache) Clear() {
+	logger.Debugf("%s - Clearing cache", c.name)
+
+	c.m.Range(func(key interface{}, value interface{}) bool {
+		c.m.Delete(key)
+		c.close(key.(string), value.(future))
+		return true
+	})
+}
+
+// Len returns the number of entries in the cache, including the
+// entries that are still being initialized
+func (c *Cache) Len() int {
+	n := 0
+	c.m.Range(func(key interface{}, value interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Range calls f for each initialized entry in the cache. Entries that are
+// still being initialized are skipped. If f returns false then the
+// iteration stops.
+func (c *Cache) Range(f func(key string, value interface{}) bool) {
+	c.m.Range(func(key interface{}, value interface{}) bool {
+		fv := value.(future)
+		if !fv.IsSet() {
+			return true
+		}
+		v, err := fv.Get()
+		if err != nil {
+			return true
+		}
+		return f(key.(string), v)
+	})
+}
+
 // Close does the following:
 // - calls Close on all values that implement a Close() function
 // - deletes all entries from the cache
@@ -189,6 +189,113 @@ func TestClose(t *testing.T) {
 	}
 }
 
+func TestDelete(t *testing.T) {
+	var numTimesInitialized int32
+	cache := New("Example_Cache", func(key Key) (interface{}, error) {
+		atomic.AddInt32(&numTimesInitialized, 1)
+		return &closableValue{
+			str: fmt.Sprintf("Value_for_key_%s", key),
+		}, nil
+	})
+	defer cache.Close()
+
+	cval, err := cache.Get(NewStringKey("Key1"))
+	if err != nil {
+		t.Fatalf("Error returned: %s", err)
+	}
+
+	cache.Delete(NewStringKey("Key1"))
+
+	// Deleting a key that doesn't exist should be fine
+	cache.Delete(NewStringKey("Key2"))
+
+	if !cval.(*closableValue).CloseCalled() {
+		t.Fatalf("Expecting close to be called but is wasn't")
+	}
+
+	_, err = cache.Get(NewStringKey("Key1"))
+	if err != nil {
+		t.Fatalf("Error returned: %s", err)
+	}
+	if n := atomic.LoadInt32(&numTimesInitialized); n != 2 {
+		t.Fatalf("Expecting value to be initialized twice but was initialized %d time(s)", n)
+	}
+}
+
+func TestClear(t *testing.T) {
+	cache := New("Example_Cache", func(key Key) (interface{}, error) {
+		return &closableValue{
+			str: fmt.Sprintf("Value_for_key_%s", key),
+		}, nil
+	})
+	defer cache.Close()
+
+	cval1, err := cache.Get(NewStringKey("Key1"))
+	if err != nil {
+		t.Fatalf("Error returned: %s", err)
+	}
+	cval2, err := cache.Get(NewStringKey("Key2"))
+	if err != nil {
+		t.Fatalf("Error returned: %s", err)
+	}
+
+	cache.Clear()
+
+	if !cval1.(*closableValue).CloseCalled() || !cval2.(*closableValue).CloseCalled() {
+		t.Fatalf("Expecting close to be called on all values")
+	}
+
+	cval, err := cache.Get(NewStringKey("Key1"))
+	if err != nil {
+		t.Fatalf("Expecting cache to be usable after clear but got error: %s", err)
+	}
+	if cval == cval1 {
+		t.Fatalf("Expecting a new value after clear")
+	}
+}
+
+func TestLenAndRange(t *testing.T) {
+	cache := New("Example_Cache", func(key Key) (interface{}, error) {
+		if key.String() == "error" {
+			return nil, fmt.Errorf("some error")
+		}
+		return fmt.Sprintf("Value_for_key_%s", key), nil
+	})
+	defer cache.Close()
+
+	if l := cache.Len(); l != 0 {
+		t.Fatalf("Expecting empty cache but got %d entries", l)
+	}
+
+	cache.MustGet(NewStringKey("Key1"))
+	cache.MustGet(NewStringKey("Key2"))
+	if _, err := cache.Get(NewStringKey("error")); err == nil {
+		t.Fatalf("Expecting error")
+	}
+
+	if l := cache.Len(); l != 2 {
+		t.Fatalf("Expecting 2 entries but got %d", l)
+	}
+
+	values := make(map[string]interface{})
+	cache.Range(func(key string, value interface{}) bool {
+		values[key] = value
+		return true
+	})
+	if len(values) != 2 || values["Key1"] != "Value_for_key_Key1" || values["Key2"] != "Value_for_key_Key2" {
+		t.Fatalf("Unexpected values from Range: %v", values)
+	}
+
+	n := 0
+	cache.Range(func(key string, value interface{}) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Fatalf("Expecting Range to stop after the first entry but got %d calls", n)
+	}
+}
+
 // fail - as t.Fatalf() is not goroutine safe, this function behaves like t.Fatalf().
 func fail(t *testing.T, template string, args ...interface{}) {
 	fmt.Printf(template, args...)
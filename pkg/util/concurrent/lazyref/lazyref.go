@@ -150,6 +150,26 @@ func (r *Reference) Get() (interface{}, error) {
 	return value, nil
 }
 
+// Refresh invokes the initializer immediately (regardless of any refresh interval) and, if
+// the initializer is successful, replaces the value. Callers of Get continue to receive the
+// old value while the initializer is running.
+func (r *Reference) Refresh() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.closed {
+		return errors.New("reference is already closed")
+	}
+
+	value, err := r.initializer()
+	if err != nil {
+		return err
+	}
+	r.set(value)
+
+	return nil
+}
+
 // MustGet returns the value. If an error is returned
 // during initialization of the value then this function
 // will panic.
@@ -482,3 +482,40 @@ func TestProactiveRefresh(t *testing.T) {
 		t.Fatalf("expecting finalizer to be called %d time(s) but was called %d time(s)", expectedTimesFinalized, num)
 	}
 }
+
+func TestRefresh(t *testing.T) {
+	var numTimesInitialized int32
+	fail := false
+
+	ref := New(func() (interface{}, error) {
+		if fail {
+			return nil, fmt.Errorf("initializer error")
+		}
+		return atomic.AddInt32(&numTimesInitialized, 1), nil
+	}, WithRefreshInterval(InitOnFirstAccess, time.Hour))
+	defer ref.Close()
+
+	if value := ref.MustGet(); value != int32(1) {
+		t.Fatalf("expecting value 1 but got %v", value)
+	}
+
+	if err := ref.Refresh(); err != nil {
+		t.Fatalf("unexpected error refreshing reference: %s", err)
+	}
+	if value := ref.MustGet(); value != int32(2) {
+		t.Fatalf("expecting value 2 after refresh but got %v", value)
+	}
+
+	fail = true
+	if err := ref.Refresh(); err == nil {
+		t.Fatal("expecting error refreshing reference")
+	}
+	if value := ref.MustGet(); value != int32(2) {
+		t.Fatalf("expecting the old value to be kept after a failed refresh but got %v", value)
+	}
+
+	ref.Close()
+	if err := ref.Refresh(); err == nil {
+		t.Fatal("expecting error refreshing closed reference")
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package limiter
+
+import (
+	reqContext "context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultInitialLimit is the default number of concurrent requests allowed before any request completes
+	DefaultInitialLimit = 20
+	// DefaultMinLimit is the default lowest concurrency limit
+	DefaultMinLimit = 1
+	// DefaultMaxLimit is the default highest concurrency limit
+	DefaultMaxLimit = 500
+	// DefaultBackoffRatio is the default factor by which the limit is decreased
+	DefaultBackoffRatio = 0.9
+	// DefaultLatencyTolerance is the default ratio of the latency to the baseline latency above which
+	// a request is considered slow
+	DefaultLatencyTolerance = 2.0
+
+	// baselineDrift is the fraction of the difference between a latency and the baseline by which
+	// the baseline moves up, so that the baseline follows a lasting increase of the latency
+	baselineDrift = 0.01
+)
+
+// Opts defines the parameters of an adaptive limiter
+type Opts struct {
+	// InitialLimit is the number of concurrent requests allowed before any request completes
+	InitialLimit int
+	// MinLimit is the lowest concurrency limit
+	MinLimit int
+	// MaxLimit is the highest concurrency limit
+	MaxLimit int
+	// BackoffRatio is the factor (between 0 and 1) by which the limit is multiplied when
+	// the endpoint is overloaded or slow
+	BackoffRatio float64
+	// LatencyTolerance is the ratio of the latency of a request to the baseline latency
+	// above which the request is considered slow
+	LatencyTolerance float64
+}
+
+// DefaultOpts are the default adaptive limiter parameters
+var DefaultOpts = Opts{
+	InitialLimit:     DefaultInitialLimit,
+	MinLimit:         DefaultMinLimit,
+	MaxLimit:         DefaultMaxLimit,
+	BackoffRatio:     DefaultBackoffRatio,
+	LatencyTolerance: DefaultLatencyTolerance,
+}
+
+// Adaptive limits the number of concurrent requests to an endpoint with an AIMD (additive increase,
+// multiplicative decrease) algorithm. The limit grows by one for each limit's worth of requests that
+// complete in time and shrinks by "BackoffRatio" when a request fails because the endpoint is overloaded
+// or when a request is slower than "LatencyTolerance" times the baseline latency. The baseline is the
+// lowest latency observed, drifting up slowly so that it follows lasting changes. The limit is decreased
+// at most once per baseline latency so that the requests that were in flight at the time of an overload
+// don't collapse it.
+//
+// This component has been designed to be safe for concurrency.
+type Adaptive struct {
+	lock         sync.Mutex
+	opts         Opts
+	limit        float64
+	inFlight     int
+	baseline     time.Duration
+	lastDecrease time.Time
+	released     chan struct{}
+	now          func() time.Time
+}
+
+// New returns an adaptive limiter with the given parameters. Parameters that aren't set take their default values.
+func New(opts Opts) *Adaptive {
+	if opts.MinLimit < 1 {
+		opts.MinLimit = DefaultMinLimit
+	}
+	if opts.MaxLimit < opts.MinLimit {
+		opts.MaxLimit = DefaultMaxLimit
+		if opts.MaxLimit < opts.MinLimit {
+			opts.MaxLimit = opts.MinLimit
+		}
+	}
+	if opts.InitialLimit < opts.MinLimit || opts.InitialLimit > opts.MaxLimit {
+		opts.InitialLimit = int(math.Max(float64(opts.MinLimit), math.Min(DefaultInitialLimit, float64(opts.MaxLimit))))
+	}
+	if opts.BackoffRatio <= 0 || opts.BackoffRatio >= 1 {
+		opts.BackoffRatio = DefaultBackoffRatio
+	}
+	if opts.LatencyTolerance <= 1 {
+		opts.LatencyTolerance = DefaultLatencyTolerance
+	}
+
+	return &Adaptive{
+		opts:     opts,
+		limit:    float64(opts.InitialLimit),
+		released: make(chan struct{}),
+		now:      time.Now,
+	}
+}
+
+// Acquire waits until a request may be sent. An error is returned if the context is done first.
+// Release must be called once the request completes.
+func (l *Adaptive) Acquire(ctx reqContext.Context) error {
+	for {
+		l.lock.Lock()
+		if l.inFlight < int(l.limit) {
+			l.inFlight++
+			l.lock.Unlock()
+			return nil
+		}
+		released := l.released
+		l.lock.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "timed out waiting for the concurrency limit")
+		}
+	}
+}
+
+// Release records the completion of a request with the given latency. Overloaded is true if the
+// request failed because the endpoint is overloaded or unavailable.
+func (l *Adaptive) Release(latency time.Duration, overloaded bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	saturated := l.inFlight >= int(l.limit)
+	l.inFlight--
+
+	switch {
+	case overloaded:
+		l.decrease()
+	case l.baseline == 0 || latency < l.baseline:
+		l.baseline = latency
+		l.increase(saturated)
+	default:
+		l.baseline += time.Duration(float64(latency-l.baseline) * baselineDrift)
+		if float64(latency) > float64(l.baseline)*l.opts.LatencyTolerance {
+			l.decrease()
+		} else {
+			l.increase(saturated)
+		}
+	}
+
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// increase grows the limit by one per limit's worth of requests. The limit only grows if it was
+// reached, otherwise a lightly loaded endpoint would see its limit grow without bound.
+func (l *Adaptive) increase(saturated bool) {
+	if saturated {
+		l.limit = math.Min(float64(l.opts.MaxLimit), l.limit+1/l.limit)
+	}
+}
+
+func (l *Adaptive) decrease() {
+	now := l.now()
+	if now.Sub(l.lastDecrease) < l.baseline {
+		return
+	}
+	l.lastDecrease = now
+	l.limit = math.Max(float64(l.opts.MinLimit), l.limit*l.opts.BackoffRatio)
+}
+
+// Limit returns the current concurrency limit
+func (l *Adaptive) Limit() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return int(l.limit)
+}
+
+// InFlight returns the number of requests in flight
+func (l *Adaptive) InFlight() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.inFlight
+}
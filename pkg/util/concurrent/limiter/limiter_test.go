@@ -0,0 +1,120 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package limiter
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquire(t *testing.T) {
+	l := New(Opts{InitialLimit: 2, MinLimit: 1, MaxLimit: 10})
+
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	assert.Equal(t, 2, l.InFlight())
+
+	// The limit is reached
+	ctx, cancel := reqContext.WithTimeout(reqContext.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, l.Acquire(ctx))
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- l.Acquire(reqContext.Background())
+	}()
+
+	l.Release(10*time.Millisecond, false)
+	select {
+	case err := <-acquired:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the request to be allowed")
+	}
+}
+
+func TestAdditiveIncrease(t *testing.T) {
+	l := New(Opts{InitialLimit: 2, MinLimit: 1, MaxLimit: 3})
+
+	for i := 0; i < 20; i++ {
+		require.NoError(t, l.Acquire(reqContext.Background()))
+		require.NoError(t, l.Acquire(reqContext.Background()))
+		l.Release(10*time.Millisecond, false)
+		l.Release(10*time.Millisecond, false)
+	}
+	assert.Equal(t, 3, l.Limit(), "expecting the limit to grow up to the maximum")
+
+	// The limit doesn't grow if it isn't reached
+	l = New(Opts{InitialLimit: 2, MinLimit: 1, MaxLimit: 3})
+	for i := 0; i < 20; i++ {
+		require.NoError(t, l.Acquire(reqContext.Background()))
+		l.Release(10*time.Millisecond, false)
+	}
+	assert.Equal(t, 2, l.Limit())
+}
+
+func TestMultiplicativeDecrease(t *testing.T) {
+	now := time.Now()
+	l := New(Opts{InitialLimit: 10, MinLimit: 2, MaxLimit: 20, BackoffRatio: 0.5})
+	l.now = func() time.Time { return now }
+
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	l.Release(10*time.Millisecond, true)
+	assert.Equal(t, 5, l.Limit())
+
+	// The limit is decreased at most once per baseline latency
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	l.Release(10*time.Millisecond, false)
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	l.Release(10*time.Millisecond, true)
+	assert.Equal(t, 5, l.Limit())
+
+	now = now.Add(time.Second)
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	l.Release(10*time.Millisecond, true)
+	assert.Equal(t, 2, l.Limit())
+
+	// The limit doesn't go below the minimum
+	now = now.Add(time.Second)
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	l.Release(10*time.Millisecond, true)
+	assert.Equal(t, 2, l.Limit())
+}
+
+func TestSlowRequests(t *testing.T) {
+	now := time.Now()
+	l := New(Opts{InitialLimit: 10, MinLimit: 1, MaxLimit: 20, BackoffRatio: 0.5, LatencyTolerance: 2})
+	l.now = func() time.Time { return now }
+
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	l.Release(10*time.Millisecond, false)
+	assert.Equal(t, 10, l.Limit())
+
+	// Within the tolerance
+	now = now.Add(time.Second)
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	l.Release(15*time.Millisecond, false)
+	assert.Equal(t, 10, l.Limit())
+
+	// Slower than the tolerance
+	require.NoError(t, l.Acquire(reqContext.Background()))
+	l.Release(50*time.Millisecond, false)
+	assert.Equal(t, 5, l.Limit())
+}
+
+func TestDefaults(t *testing.T) {
+	l := New(Opts{})
+	assert.Equal(t, DefaultOpts, l.opts)
+	assert.Equal(t, DefaultInitialLimit, l.Limit())
+
+	l = New(Opts{MinLimit: 1, MaxLimit: 5})
+	assert.Equal(t, 5, l.Limit(), "expecting the initial limit to be capped by the maximum")
+}
@@ -0,0 +1,166 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package protopool reuses the protobuf messages that are unmarshalled while parsing blocks and
+// transaction envelopes so that high volume block processing doesn't put pressure on the garbage
+// collector. A message obtained from this package must be released (see Release) once it's no
+// longer referenced. Fields of a released message that were copied beforehand (including byte
+// slices and strings) remain valid, but pointers to nested messages must not be retained.
+package protopool
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+type pool struct {
+	sync.Pool
+}
+
+func newPool(newMsg func() proto.Message) *pool {
+	return &pool{Pool: sync.Pool{New: func() interface{} { return newMsg() }}}
+}
+
+func (p *pool) unmarshal(data []byte, name string) (proto.Message, error) {
+	msg := p.Get().(proto.Message)
+	if err := proto.Unmarshal(data, msg); err != nil {
+		p.put(msg)
+		return nil, errors.Wrapf(err, "error unmarshaling %s", name)
+	}
+	return msg, nil
+}
+
+func (p *pool) put(msg proto.Message) {
+	msg.Reset()
+	p.Put(msg)
+}
+
+var (
+	envelopes                = newPool(func() proto.Message { return &common.Envelope{} })
+	payloads                 = newPool(func() proto.Message { return &common.Payload{} })
+	channelHeaders           = newPool(func() proto.Message { return &common.ChannelHeader{} })
+	signatureHeaders         = newPool(func() proto.Message { return &common.SignatureHeader{} })
+	transactions             = newPool(func() proto.Message { return &pb.Transaction{} })
+	chaincodeActionPayloads  = newPool(func() proto.Message { return &pb.ChaincodeActionPayload{} })
+	proposalResponsePayloads = newPool(func() proto.Message { return &pb.ProposalResponsePayload{} })
+	chaincodeActions         = newPool(func() proto.Message { return &pb.ChaincodeAction{} })
+)
+
+// Envelope unmarshals a transaction envelope (e.g. an entry of the data of a block)
+func Envelope(data []byte) (*common.Envelope, error) {
+	msg, err := envelopes.unmarshal(data, "Envelope")
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*common.Envelope), nil
+}
+
+// Payload unmarshals the payload of an envelope
+func Payload(env *common.Envelope) (*common.Payload, error) {
+	if env == nil {
+		return nil, errors.New("nil envelope")
+	}
+	msg, err := payloads.unmarshal(env.Payload, "Payload")
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*common.Payload), nil
+}
+
+// ChannelHeader unmarshals a channel header
+func ChannelHeader(data []byte) (*common.ChannelHeader, error) {
+	msg, err := channelHeaders.unmarshal(data, "ChannelHeader")
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*common.ChannelHeader), nil
+}
+
+// SignatureHeader unmarshals a signature header
+func SignatureHeader(data []byte) (*common.SignatureHeader, error) {
+	msg, err := signatureHeaders.unmarshal(data, "SignatureHeader")
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*common.SignatureHeader), nil
+}
+
+// Transaction unmarshals the transaction contained in the data of a payload
+func Transaction(data []byte) (*pb.Transaction, error) {
+	msg, err := transactions.unmarshal(data, "Transaction")
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*pb.Transaction), nil
+}
+
+// ChaincodeActionPayload unmarshals the payload of a transaction action
+func ChaincodeActionPayload(data []byte) (*pb.ChaincodeActionPayload, error) {
+	msg, err := chaincodeActionPayloads.unmarshal(data, "ChaincodeActionPayload")
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*pb.ChaincodeActionPayload), nil
+}
+
+// ProposalResponsePayload unmarshals a proposal response payload
+func ProposalResponsePayload(data []byte) (*pb.ProposalResponsePayload, error) {
+	msg, err := proposalResponsePayloads.unmarshal(data, "ProposalResponsePayload")
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*pb.ProposalResponsePayload), nil
+}
+
+// ChaincodeAction unmarshals the extension of a proposal response payload
+func ChaincodeAction(data []byte) (*pb.ChaincodeAction, error) {
+	msg, err := chaincodeActions.unmarshal(data, "ChaincodeAction")
+	if err != nil {
+		return nil, err
+	}
+	return msg.(*pb.ChaincodeAction), nil
+}
+
+// Release returns the given messages to their pools. Nil messages and messages of types
+// that aren't pooled are ignored.
+func Release(msgs ...proto.Message) {
+	for _, msg := range msgs {
+		if msg == nil || reflect.ValueOf(msg).IsNil() {
+			continue
+		}
+		if p := poolOf(msg); p != nil {
+			p.put(msg)
+		}
+	}
+}
+
+func poolOf(msg proto.Message) *pool {
+	switch msg.(type) {
+	case *common.Envelope:
+		return envelopes
+	case *common.Payload:
+		return payloads
+	case *common.ChannelHeader:
+		return channelHeaders
+	case *common.SignatureHeader:
+		return signatureHeaders
+	case *pb.Transaction:
+		return transactions
+	case *pb.ChaincodeActionPayload:
+		return chaincodeActionPayloads
+	case *pb.ProposalResponsePayload:
+		return proposalResponsePayloads
+	case *pb.ChaincodeAction:
+		return chaincodeActions
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protopool
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	chdr, err := proto.Marshal(&common.ChannelHeader{TxId: "txid", ChannelId: "mychannel"})
+	require.NoError(t, err)
+	payloadBytes, err := proto.Marshal(&common.Payload{Header: &common.Header{ChannelHeader: chdr}, Data: []byte("data")})
+	require.NoError(t, err)
+	envBytes, err := proto.Marshal(&common.Envelope{Payload: payloadBytes, Signature: []byte("signature")})
+	require.NoError(t, err)
+
+	env, err := Envelope(envBytes)
+	require.NoError(t, err)
+	payload, err := Payload(env)
+	require.NoError(t, err)
+	channelHeader, err := ChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+
+	data := payload.Data
+	txID := channelHeader.TxId
+	Release(env, payload, channelHeader)
+
+	assert.Equal(t, []byte("data"), data, "copied fields must remain valid after the release")
+	assert.Equal(t, "txid", txID)
+
+	// A reused message doesn't contain the fields of its previous use
+	env, err = Envelope(nil)
+	require.NoError(t, err)
+	assert.Empty(t, env.Payload)
+	assert.Empty(t, env.Signature)
+	Release(env)
+}
+
+func TestUnmarshalError(t *testing.T) {
+	_, err := Transaction([]byte("invalid"))
+	assert.Error(t, err)
+
+	_, err = Payload(nil)
+	assert.Error(t, err)
+}
+
+func TestRelease(t *testing.T) {
+	var ccAction *pb.ChaincodeAction
+	assert.NotPanics(t, func() { Release(nil, ccAction, &pb.ChaincodeEvent{}) })
+}
+
+func BenchmarkEnvelope(b *testing.B) {
+	envBytes, err := proto.Marshal(&common.Envelope{Payload: make([]byte, 4096), Signature: make([]byte, 72)})
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		env, err := Envelope(envBytes)
+		if err != nil {
+			b.Fatal(err)
+		}
+		Release(env)
+	}
+}
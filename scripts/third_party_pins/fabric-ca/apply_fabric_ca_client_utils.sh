@@ -28,6 +28,7 @@ declare -a PKGS=(
 declare -a FILES=(
     "api/client.go"
     "api/net.go"
+    "api/sdkpatch_certificates.go"
 
     "lib/client.go"
     "lib/identity.go"
@@ -36,6 +37,13 @@ declare -a FILES=(
     "lib/util.go"
     "lib/serverrevoke.go"
     "lib/sdkpatch_serverstruct.go"
+    "lib/sdkpatch_transport.go"
+    "lib/sdkpatch_identities.go"
+    "lib/sdkpatch_affiliation.go"
+    "lib/sdkpatch_cainfo.go"
+    "lib/sdkpatch_certificates.go"
+    "lib/sdkpatch_gencrl.go"
+    "lib/sdkpatch_reenroll.go"
 
     "lib/tls/tls.go"
 
@@ -81,6 +89,12 @@ sed -i'' -e '/log "github.com\// a\
 sed -i'' -e 's/bccsp.BCCSP/core.CryptoSuite/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 sed -i'' -e 's/bccsp.Key/core.Key/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 sed -i'' -e 's/\/\/ Initialize BCCSP (the crypto layer)/c.csp = cfg.CSP/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e '/cr.CN = id/ a\
+\/\/ Use the requested common name, if any\
+if req != nil \&\& req.CN != "" {\
+cr.CN = req.CN\
+}\
+' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 START_LINE=`grep -n "c.csp, err = util.InitBCCSP(&cfg.CSP, mspDir, c.HomeDir)" "${TMP_PROJECT_PATH}/${FILTER_FILENAME}" | head -n 1 | awk -F':' '{print $1}'`
 for i in {1..4}
 do
@@ -68,6 +68,7 @@ declare -a FILES=(
     "bccsp/pkcs11/ecdsakey.go"
     "bccsp/pkcs11/impl.go"
     "bccsp/pkcs11/pkcs11.go"
+    "bccsp/pkcs11/sdkpatch_session.go"
 
     "bccsp/signer/signer.go"
 
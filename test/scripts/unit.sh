@@ -27,6 +27,12 @@ PKGS=`$GO_CMD list $REPO... 2> /dev/null | \
       grep -v ^$REPO/internal/github.com/ | grep -v ^$REPO/third_party/ | \
       grep -v ^$REPO/pkg/core/cryptosuite/bccsp/pkcs11 | grep -v ^$REPO/pkg/core/cryptosuite/bccsp/multisuite | \
       grep -v ^$REPO/vendor/ | grep -v ^$REPO/test/`
+
+# The slog adapter requires Go 1.21 or later (log/slog)
+if ! $GO_CMD version | grep -qE 'go1\.(2[1-9]|[3-9][0-9])'; then
+    PKGS=`echo "$PKGS" | grep -v ^$REPO/pkg/core/logging/slogadapter`
+fi
+
 echo "Running unit tests..."
 
 RACEFLAG=""